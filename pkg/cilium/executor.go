@@ -36,5 +36,6 @@ func (e *CiliumExecutor) Execute(params map[string]interface{}, cfg *config.Conf
 
 	// Execute the command
 	process := command.NewShellProcess("cilium", cfg.Timeout)
+	process.Path = cfg.CiliumPath
 	return process.Run(ciliumCmd)
 }
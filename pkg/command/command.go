@@ -3,6 +3,8 @@ package command
 import (
 	"bytes"
 	"context"
+	"fmt"
+	"os"
 	"os/exec"
 	"strings"
 	"time"
@@ -13,6 +15,7 @@ import (
 // ShellProcess wraps a shell command execution
 type ShellProcess struct {
 	Command         string
+	Path            string // binary run for Command instead of the one on PATH, if set
 	StripNewlines   bool
 	ReturnErrOutput bool
 	Timeout         int // in seconds
@@ -54,6 +57,10 @@ func (s *ShellProcess) Exec(commands string) (string, error) {
 		return "", err
 	}
 
+	if len(parts) > 0 && s.Path != "" && parts[0] == s.Command {
+		parts[0] = s.Path
+	}
+
 	if len(parts) > 1 {
 		// Command with arguments
 		// #nosec G204: Subprocess launched with a potential tainted input or cmd arguments
@@ -79,10 +86,10 @@ func (s *ShellProcess) Exec(commands string) (string, error) {
 		return "", ctx.Err()
 	}
 
-	// Handle errors
+	// Handle errors, returning what the command printed when it exits nonzero to report a result
 	if err != nil {
-		if s.ReturnErrOutput && stderr.Len() > 0 {
-			return stderr.String(), nil
+		if s.ReturnErrOutput && stdout.Len()+stderr.Len() > 0 {
+			return stdout.String() + stderr.String(), nil
 		}
 		return "", err
 	}
@@ -95,3 +102,18 @@ func (s *ShellProcess) Exec(commands string) (string, error) {
 
 	return output, nil
 }
+
+// CheckExecutable reports an error unless path names an executable regular file
+func CheckExecutable(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if !info.Mode().IsRegular() {
+		return fmt.Errorf("%s is not a regular file", path)
+	}
+	if info.Mode().Perm()&0o111 == 0 {
+		return fmt.Errorf("%s is not executable", path)
+	}
+	return nil
+}
@@ -1,6 +1,8 @@
 package command
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 )
@@ -172,3 +174,47 @@ func TestReturnErrOutput(t *testing.T) {
 		t.Errorf("Expected error when ReturnErrOutput=false, got none")
 	}
 }
+
+func TestReturnErrOutputIncludesStdout(t *testing.T) {
+	// Commands like kubectl diff print their result on stdout and exit nonzero
+	sp := NewShellProcess("sh", 5)
+	sp.ReturnErrOutput = true
+	output, err := sp.Exec(`sh -c "echo differences; exit 1"`)
+	if err != nil {
+		t.Fatalf("Expected no error when ReturnErrOutput=true, got: %v", err)
+	}
+	if output != "differences\n" {
+		t.Errorf("Expected stdout to be returned, got %q", output)
+	}
+}
+
+func TestRunWithCustomPath(t *testing.T) {
+	// A stand-in binary that prints the name it was run as and its arguments
+	path := filepath.Join(t.TempDir(), "kubectl.1.28")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\necho \"$0 $*\"\n"), 0o755); err != nil {
+		t.Fatalf("failed to write stand-in binary: %v", err)
+	}
+
+	sp := NewShellProcess("kubectl", 5)
+	sp.Path = path
+	output, err := sp.Run("kubectl get pods -n shop")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if want := path + " get pods -n shop"; strings.TrimSpace(output) != want {
+		t.Errorf("Expected %q, got: %q", want, output)
+	}
+
+	if err := CheckExecutable(path); err != nil {
+		t.Errorf("Expected %s to be executable, got: %v", path, err)
+	}
+	if err := os.Chmod(path, 0o644); err != nil {
+		t.Fatalf("failed to chmod: %v", err)
+	}
+	if err := CheckExecutable(path); err == nil {
+		t.Error("Expected a non-executable file to be rejected")
+	}
+	if err := CheckExecutable(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("Expected a missing file to be rejected")
+	}
+}
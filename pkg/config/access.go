@@ -0,0 +1,55 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/Azure/mcp-kubernetes/pkg/security"
+)
+
+// DowngradeToReadOnly lowers the access level to readonly after startup validation, recording
+// the level that was asked for and why it wasn't granted so access denials can explain it
+func (cfg *ConfigData) DowngradeToReadOnly(reason string) {
+	if cfg.RequestedAccessLevel == "" {
+		cfg.RequestedAccessLevel = cfg.AccessLevel
+	}
+	cfg.DowngradeReason = reason
+	cfg.AccessLevel = "readonly"
+	cfg.SecurityConfig.AccessLevel = security.AccessLevelReadOnly
+}
+
+// accessLevelRank orders the access levels from least to most access
+var accessLevelRank = map[string]int{"readonly": 0, "readwrite": 1, "admin": 2}
+
+// ForTransport returns the configuration a tool call arriving over the server's transport runs
+// with: cfg itself, or, over sse and streamable-http when --http-max-access-level is below the
+// access level, a copy capped at that level whose access denials name the flag. The copy shares
+// everything else with cfg.
+func (cfg *ConfigData) ForTransport() *ConfigData {
+	ceiling := ""
+	if cfg.Transport == "sse" || cfg.Transport == "streamable-http" {
+		ceiling = cfg.HTTPMaxAccessLevel
+	}
+	if ceiling == "" || accessLevelRank[ceiling] >= accessLevelRank[cfg.AccessLevel] {
+		return cfg
+	}
+
+	capped := *cfg
+	if capped.RequestedAccessLevel == "" {
+		capped.RequestedAccessLevel = cfg.AccessLevel
+	}
+	capped.DowngradeReason = fmt.Sprintf("calls over the %s transport are capped by --http-max-access-level=%s", cfg.Transport, ceiling)
+	capped.AccessLevel = ceiling
+	capped.SecurityConfig = cfg.SecurityConfig.WithAccessLevel(security.AccessLevel(ceiling))
+	return &capped
+}
+
+// AccessLevelGuidance explains where the current access level comes from, for errors about
+// commands that need more access
+func (cfg *ConfigData) AccessLevelGuidance(required string) string {
+	if cfg.DowngradeReason != "" {
+		return fmt.Sprintf("this requires %s; the server was started with %s but is running as %s because %s",
+			required, cfg.RequestedAccessLevel, cfg.AccessLevel, cfg.DowngradeReason)
+	}
+	return fmt.Sprintf("this requires %s; the server is running as %s as configured, so it must be restarted with --access-level=%s",
+		required, cfg.AccessLevel, required)
+}
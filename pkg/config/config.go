@@ -2,9 +2,14 @@ package config
 
 import (
 	"fmt"
+	"log"
+	"net/url"
+	"os"
 	"strings"
 
+	"github.com/Azure/mcp-kubernetes/pkg/command"
 	"github.com/Azure/mcp-kubernetes/pkg/security"
+	"github.com/Azure/mcp-kubernetes/pkg/version"
 	flag "github.com/spf13/pflag"
 )
 
@@ -14,6 +19,12 @@ type ConfigData struct {
 	AdditionalTools map[string]bool
 	// Command execution timeout in seconds
 	Timeout int
+	// MaxTimeout is the ceiling in seconds for every timeout, however it is set (0 means none)
+	MaxTimeout int
+	// OperationTimeouts overrides Timeout for individual kubectl verbs
+	OperationTimeouts map[string]int
+	// InjectRequestTimeout adds kubectl's --request-timeout, set to the call's timeout, to commands that don't set one
+	InjectRequestTimeout bool
 	// Security configuration
 	SecurityConfig *security.SecurityConfig
 
@@ -23,21 +34,142 @@ type ConfigData struct {
 	Port            int
 	AccessLevel     string
 	AllowNamespaces string
+	// RequestedAccessLevel is the access level asked for when startup validation lowered it
+	RequestedAccessLevel string
+	// DowngradeReason says why startup validation lowered the access level, if it did
+	DowngradeReason string
+	// AllowImages is a comma-separated allow-list of image registries and prefixes
+	AllowImages string
+	// AllowServers is a comma-separated allow-list of API server URLs for the server parameter
+	AllowServers string
+	// AllowTenants is a comma-separated allow-list of tenants for the tenant parameter
+	AllowTenants string
+	// AllowAllNamespaces opts into access to every namespace when AllowNamespaces is empty under StrictConfig
+	AllowAllNamespaces bool
+	// AllowBulkMutations lets mutating commands use --all without confirm=true
+	AllowBulkMutations bool
+	// DeleteRequiresName refuses deletes with --all or a selector, so every delete names its resources
+	DeleteRequiresName bool
+	// RecordChangeCause annotates the resources of create, apply, scale, set and patch with a change-cause
+	RecordChangeCause bool
+	// RequireReason refuses commands that change the cluster unless the call gives a reason
+	RequireReason bool
+	// RequireResourceLimits refuses inline manifests with containers that don't set resource requests and limits
+	RequireResourceLimits bool
+	// HTTPMaxAccessLevel caps the access level of tool calls over the sse and streamable-http transports ("" for no cap)
+	HTTPMaxAccessLevel string
+	// ApprovalWebhook is a URL that must approve admin operations and deletes before they run ("" for none)
+	ApprovalWebhook string
+	// ApprovalTimeout is the number of seconds the approval webhook has to answer
+	ApprovalTimeout int
+	// NoExecNamespaces is a comma-separated list of namespaces whose pods exec and cp may not target
+	NoExecNamespaces string
+	// ProtectNamespace is the namespace delete may not remove, normally the one the server runs in
+	ProtectNamespace string
+	// DenyResources is a comma-separated list of resource types commands may not create or change
+	DenyResources string
+	// DenyAPIGroups is a comma-separated list of API groups commands may not touch ("core" for the core group)
+	DenyAPIGroups string
 	// ValidateClusterRole controls whether to validate mw-opsai-cluster-role
 	ValidateClusterRole bool
+	// ValidationRetries is how many times a failed cluster role check is retried before acting on it
+	ValidationRetries int
+	// ReadSource selects where get operations are served from (shell or informer)
+	ReadSource string
+	// InformerResync is the informer relist interval in seconds
+	InformerResync int
+	// MaxListItems caps the number of items returned by get operations (0 means unlimited)
+	MaxListItems int
+	// MaxOutputBytes caps the bytes of output returned by kubectl commands (0 means unlimited)
+	MaxOutputBytes int
+	// OperationOutputBytes overrides MaxOutputBytes for individual kubectl verbs
+	OperationOutputBytes map[string]int
+	// RateLimit caps the tool calls each user may make per minute (0 means unlimited)
+	RateLimit int
+	// SessionConcurrency caps the tool calls each MCP session may have running at once (0 means unlimited)
+	SessionConcurrency int
+	// RecentCommands is how many recent tool calls kubectl_recent_commands reports (0 turns it off)
+	RecentCommands int
+	// DescribeYAMLKinds is a comma-separated list of resource types whose describe also returns the YAML
+	DescribeYAMLKinds string
+	// EditStripPaths is a comma-separated list of dotted paths removed from describe-yaml's YAML
+	EditStripPaths string
+	// IdempotencyKeys is how many idempotency keys of changes are remembered (0 turns them off)
+	IdempotencyKeys int
+	// IdempotencyTTL is how long in seconds the result of a change made with an idempotency key is remembered
+	IdempotencyTTL int
+	// Snapshots is how many resources are kept as they were before a change by name, for restore-last (0 turns it off)
+	Snapshots int
+	// TimeoutPageSize is the page size a get list is fetched again in when the API server times out (0 turns it off)
+	TimeoutPageSize int
+	// TimeoutMaxPages bounds the pages fetched for a get list after a timeout
+	TimeoutMaxPages int
+	// ReadCache is how many single-object get results are cached and revalidated by resourceVersion (0 turns it off)
+	ReadCache int
+	// FilterNamespaceList hides namespaces outside AllowNamespaces from get namespaces output
+	FilterNamespaceList bool
+	// ExpandAllNamespaces runs reads with --all-namespaces in each allowed namespace when namespaces are restricted
+	ExpandAllNamespaces bool
+	// ResultTransform names the built-in transform JSON output is run through ("" for none)
+	ResultTransform string
+	// JQExpression is the expression the jq transform applies when a call doesn't give one
+	JQExpression string
+	// ImpersonationNamespaces maps impersonated users to the namespace used when none is given
+	ImpersonationNamespaces map[string]string
+	// KubectlPath, HelmPath, CiliumPath and HubblePath are the binaries run for each tool
+	// instead of the ones found on PATH ("" uses PATH)
+	KubectlPath string
+	HelmPath    string
+	CiliumPath  string
+	HubblePath  string
+	// KubectlUserAgent is sent to the remote agent as user_agent, for it to set on kubectl's API
+	// requests so audit logs attribute them to this server. It only takes effect with agents that
+	// honor the field ("" leaves kubectl's own).
+	KubectlUserAgent string
+	// ConfigFile is an optional YAML file with defaults for the options above
+	ConfigFile string
+	// StrictConfig makes an unreadable config file, unknown config key or invalid namespace pattern fatal
+	StrictConfig bool
 }
 
+// Read sources for get operations
+const (
+	ReadSourceShell    = "shell"
+	ReadSourceInformer = "informer"
+)
+
+// DefaultEditStripPaths are the server-set fields removed from describe-yaml's YAML so it can
+// be edited and applied
+const DefaultEditStripPaths = "status,metadata.managedFields,metadata.resourceVersion,metadata.uid,metadata.creationTimestamp"
+
+// ResultTransformJQ runs JSON output through a jq expression
+const ResultTransformJQ = "jq"
+
 // NewConfig creates and returns a new configuration instance
 func NewConfig() *ConfigData {
 	return &ConfigData{
-		AdditionalTools:     make(map[string]bool),
-		Timeout:             60,
-		SecurityConfig:      security.NewSecurityConfig(),
-		Transport:           "stdio",
-		Port:                8000,
-		AccessLevel:         "readonly",
-		AllowNamespaces:     "",
-		ValidateClusterRole: true, // Enable by default
+		AdditionalTools:         make(map[string]bool),
+		Timeout:                 60,
+		OperationTimeouts:       make(map[string]int),
+		OperationOutputBytes:    make(map[string]int),
+		SecurityConfig:          security.NewSecurityConfig(),
+		Transport:               "stdio",
+		Port:                    8000,
+		AccessLevel:             "readonly",
+		AllowNamespaces:         "",
+		ValidateClusterRole:     true, // Enable by default
+		InjectRequestTimeout:    true,
+		ValidationRetries:       2,
+		NoExecNamespaces:        security.DefaultNoExecNamespaces,
+		ReadSource:              ReadSourceShell,
+		InformerResync:          30,
+		RecentCommands:          100,
+		TimeoutMaxPages:         20,
+		IdempotencyKeys:         1000,
+		IdempotencyTTL:          600,
+		ApprovalTimeout:         10,
+		EditStripPaths:          DefaultEditStripPaths,
+		ImpersonationNamespaces: make(map[string]string),
 	}
 }
 
@@ -48,6 +180,51 @@ func (cfg *ConfigData) ParseFlags() error {
 	flag.StringVar(&cfg.Host, "host", "127.0.0.1", "Host to listen for the server (only used with transport sse or streamable-http)")
 	flag.IntVar(&cfg.Port, "port", 8000, "Port to listen for the server (only used with transport sse or streamable-http)")
 	flag.IntVar(&cfg.Timeout, "timeout", 60, "Timeout for command execution in seconds, default is 60s")
+	flag.IntVar(&cfg.MaxTimeout, "max-timeout", 0,
+		"Upper limit in seconds for every timeout, including per-operation and caller-requested ones (0 means no limit)")
+	operationTimeouts := flag.String("operation-timeouts", "",
+		"Comma-separated verb=seconds pairs overriding --timeout for individual kubectl verbs (e.g. describe=120)")
+	flag.BoolVar(&cfg.InjectRequestTimeout, "inject-request-timeout", true,
+		"Add --request-timeout matching the call's timeout to kubectl commands that don't set one, so kubectl stops waiting on the API server when the call times out")
+	flag.StringVar(&cfg.ReadSource, "read-source", ReadSourceShell,
+		"Where get operations on pods, deployments and services are served from (shell or informer)")
+	flag.IntVar(&cfg.InformerResync, "informer-resync", 30, "Informer relist interval in seconds (only used with read-source informer)")
+	flag.IntVar(&cfg.MaxListItems, "max-list-items", 0, "Maximum number of items returned by get operations (0 means unlimited)")
+	flag.IntVar(&cfg.MaxOutputBytes, "max-output-bytes", 0,
+		"Maximum bytes of output returned by a kubectl command; longer output is cut at a line boundary (0 means unlimited)")
+	operationOutputBytes := flag.String("operation-output-bytes", "",
+		"Comma-separated verb=bytes pairs overriding --max-output-bytes for individual kubectl verbs (e.g. logs=500000,get=100000)")
+	flag.IntVar(&cfg.RateLimit, "rate-limit", 0, "Maximum tool calls per minute for each user (0 means unlimited)")
+	flag.IntVar(&cfg.SessionConcurrency, "session-concurrency", 0,
+		"Maximum tool calls each MCP session may have running at once; further calls are rejected (0 means unlimited)")
+	flag.StringVar(&cfg.DescribeYAMLKinds, "describe-yaml-kinds", "",
+		"Comma-separated resource types whose describe of one object also returns its YAML, as describe-yaml does (e.g. configmaps)")
+	flag.StringVar(&cfg.EditStripPaths, "edit-strip-paths", DefaultEditStripPaths,
+		"Comma-separated dotted paths removed from the YAML describe-yaml returns (empty keeps every field)")
+	flag.IntVar(&cfg.ReadCache, "read-cache", 0,
+		"Number of get results for single objects (-o json or yaml) kept in memory and served while their resourceVersion is unchanged (0 turns it off)")
+	flag.IntVar(&cfg.TimeoutPageSize, "timeout-page-size", 0,
+		"Items per page when a get list with -o json or name that the API server timed out on is fetched again in pages (0 turns it off)")
+	flag.IntVar(&cfg.TimeoutMaxPages, "timeout-max-pages", 20, "Maximum pages fetched for a get list after an API server timeout")
+	flag.IntVar(&cfg.IdempotencyKeys, "idempotency-keys", 1000,
+		"Number of idempotency keys whose change results are kept so retried changes aren't applied twice (0 turns them off)")
+	flag.IntVar(&cfg.IdempotencyTTL, "idempotency-ttl", 600, "Seconds the result of a change made with an idempotency key is kept")
+	flag.IntVar(&cfg.Snapshots, "snapshots", 0,
+		"Number of resources kept in memory as they were before being changed by name, for restore-last (0 turns snapshots off)")
+	flag.IntVar(&cfg.RecentCommands, "recent-commands", 100, "Number of recent tool calls kept in memory for kubectl_recent_commands (0 turns it off)")
+	flag.StringVar(&cfg.ResultTransform, "result-transform", "",
+		"Built-in transform applied to JSON output before it is returned (jq, or empty for none)")
+	flag.StringVar(&cfg.JQExpression, "jq-expression", "",
+		"Expression the jq result transform applies when a call doesn't pass one (only used with result-transform jq)")
+	flag.StringVar(&cfg.KubectlPath, "kubectl-path", "", "Path of the kubectl binary to run (empty uses kubectl from PATH)")
+	flag.StringVar(&cfg.KubectlUserAgent, "kubectl-user-agent", "mcp-kubernetes/"+version.GetVersion(),
+		"User agent sent to the remote agent for kubectl's API requests, shown in API server audit logs; needs an agent that honors user_agent (empty leaves kubectl's own)")
+	flag.StringVar(&cfg.HelmPath, "helm-path", "", "Path of the helm binary to run (empty uses helm from PATH)")
+	flag.StringVar(&cfg.CiliumPath, "cilium-path", "", "Path of the cilium binary to run (empty uses cilium from PATH)")
+	flag.StringVar(&cfg.HubblePath, "hubble-path", "", "Path of the hubble binary to run (empty uses hubble from PATH)")
+	flag.StringVar(&cfg.ConfigFile, "config", "", "Path to a YAML config file; flags given on the command line take precedence")
+	flag.BoolVar(&cfg.StrictConfig, "strict-config", false,
+		"Fail startup on an unreadable config file, unknown config key or invalid namespace pattern instead of ignoring it")
 
 	// Tools configuration
 	additionalTools := flag.String("additional-tools", "",
@@ -55,13 +232,70 @@ func (cfg *ConfigData) ParseFlags() error {
 
 	// Security settings
 	flag.StringVar(&cfg.AccessLevel, "access-level", "readonly", "Access level (readonly, readwrite, or admin)")
+	flag.StringVar(&cfg.HTTPMaxAccessLevel, "http-max-access-level", "",
+		"Highest access level for tool calls over the sse and streamable-http transports (readonly, readwrite or admin; empty means --access-level)")
 	flag.StringVar(&cfg.AllowNamespaces, "allow-namespaces", "",
 		"Comma-separated list of namespaces to allow (empty means all allowed)")
+	flag.BoolVar(&cfg.AllowAllNamespaces, "allow-all-namespaces", false,
+		"Allow every namespace when --allow-namespaces is empty; required for that under --strict-config, where an empty list denies all")
+	flag.BoolVar(&cfg.AllowBulkMutations, "allow-bulk-mutations", false,
+		"Allow commands that change resources to use --all without passing confirm=true")
+	flag.BoolVar(&cfg.DeleteRequiresName, "delete-requires-name", false,
+		"Refuse delete with --all or a label or field selector; deletes must name their resources")
+	flag.StringVar(&cfg.AllowImages, "allow-images", "",
+		"Comma-separated registries or image prefixes that run, create and set image may use (empty means all allowed)")
+	flag.StringVar(&cfg.AllowTenants, "allow-tenants", "",
+		"Comma-separated tenants whose remote agents tool calls may target with the tenant parameter (tokens come from TENANT_TOKENS)")
+	flag.StringVar(&cfg.NoExecNamespaces, "no-exec-namespaces", security.DefaultNoExecNamespaces,
+		"Comma-separated namespaces whose pods exec and cp may not target at any access level (empty allows all)")
+	flag.StringVar(&cfg.ProtectNamespace, "protect-namespace", os.Getenv("POD_NAMESPACE"),
+		"Namespace that delete may not remove, normally the one the server runs in (defaults to $POD_NAMESPACE)")
+	flag.StringVar(&cfg.DenyResources, "deny-resources", "",
+		"Comma-separated resource types that commands may not create or change (e.g. secrets)")
+	flag.StringVar(&cfg.DenyAPIGroups, "deny-api-groups", "",
+		"Comma-separated API groups whose resources commands may not read or change (e.g. rbac.authorization.k8s.io,policy; core for the core group)")
+	flag.BoolVar(&cfg.FilterNamespaceList, "filter-namespace-list", false,
+		"Remove namespaces outside --allow-namespaces from get namespaces output")
+	flag.BoolVar(&cfg.ExpandAllNamespaces, "expand-all-namespaces", false,
+		"Run reads with --all-namespaces in each namespace --allow-namespaces permits and combine the results, instead of refusing them")
+	flag.StringVar(&cfg.AllowServers, "allow-servers", "",
+		"Comma-separated API server URLs that commands may target with the server parameter or --server (empty means none)")
 	flag.BoolVar(&cfg.ValidateClusterRole, "validate-cluster-role", true,
 		"Validate mw-opsai-cluster-role exists before allowing admin/readwrite access")
+	flag.IntVar(&cfg.ValidationRetries, "validation-retries", 2,
+		"Times to retry a failed cluster role validation, with exponential backoff, before downgrading")
+	flag.BoolVar(&cfg.RecordChangeCause, "record-change-cause", false,
+		"Annotate resources changed by create, apply, scale, set and patch with kubernetes.io/change-cause (the call's reason, or the change)")
+	flag.BoolVar(&cfg.RequireReason, "require-reason", false,
+		"Refuse commands that change the cluster unless the call passes a reason")
+	flag.BoolVar(&cfg.RequireResourceLimits, "require-resource-limits", false,
+		"Refuse inline manifests for create and apply whose containers don't set resource requests and limits")
+	flag.StringVar(&cfg.ApprovalWebhook, "approval-webhook", "",
+		"URL that admin operations and deletes are POSTed to before they run; they run only if it approves (empty turns it off)")
+	flag.IntVar(&cfg.ApprovalTimeout, "approval-webhook-timeout", 10,
+		"Seconds the approval webhook has to answer before the command is denied")
+	flag.BoolVar(&cfg.SecurityConfig.RequireAdminConfirm, "require-admin-confirm", false,
+		"Require admin operations to pass the confirm_token issued by kubectl_check_permissions")
+	flag.BoolVar(&cfg.SecurityConfig.RequireExplicitNamespace, "require-explicit-namespace", false,
+		"Refuse commands that change namespaced resources without a namespace (the namespace parameter or -n)")
+	flag.BoolVar(&cfg.SecurityConfig.DisallowInsecureTLS, "disallow-insecure-tls", true,
+		"Refuse commands that turn off verification of the API server's certificate with --insecure-skip-tls-verify")
+	impersonationNamespaces := flag.String("impersonation-namespaces", "",
+		"Comma-separated user=namespace pairs; commands impersonating a listed user without a namespace run in the mapped namespace")
+	redactPatterns := flag.StringArray("redact-patterns", nil,
+		"Additional regex to redact from command output, applied after the built-in patterns (repeatable)")
 
 	flag.Parse()
 
+	// Parse additional tools
+	cfg.parseAdditionalTools(*additionalTools)
+
+	if cfg.ConfigFile != "" {
+		if err := cfg.loadConfigFile(cfg.ConfigFile, flag.CommandLine.Changed); err != nil {
+			return err
+		}
+	}
+
 	// Update security config with access level
 	switch cfg.AccessLevel {
 	case "readonly":
@@ -74,21 +308,186 @@ func (cfg *ConfigData) ParseFlags() error {
 		return fmt.Errorf("invalid access level '%s'. Valid values are: readonly, readwrite, admin", cfg.AccessLevel)
 	}
 
+	switch cfg.HTTPMaxAccessLevel {
+	case "", "readonly", "readwrite", "admin":
+	default:
+		return fmt.Errorf("invalid http max access level '%s'. Valid values are: readonly, readwrite, admin", cfg.HTTPMaxAccessLevel)
+	}
+
+	if cfg.ReadSource != ReadSourceShell && cfg.ReadSource != ReadSourceInformer {
+		return fmt.Errorf("invalid read source '%s'. Valid values are: shell, informer", cfg.ReadSource)
+	}
+	if cfg.ResultTransform != "" && cfg.ResultTransform != ResultTransformJQ {
+		return fmt.Errorf("invalid result transform '%s'. Valid values are: jq, or empty for none", cfg.ResultTransform)
+	}
+	if cfg.JQExpression != "" && cfg.ResultTransform != ResultTransformJQ {
+		return fmt.Errorf("jq-expression requires result-transform jq")
+	}
+	if cfg.InformerResync <= 0 {
+		return fmt.Errorf("informer resync must be positive, got %d", cfg.InformerResync)
+	}
+	if cfg.MaxListItems < 0 {
+		return fmt.Errorf("max list items must not be negative, got %d", cfg.MaxListItems)
+	}
+	if cfg.MaxOutputBytes < 0 {
+		return fmt.Errorf("max output bytes must not be negative, got %d", cfg.MaxOutputBytes)
+	}
+	if cfg.ValidationRetries < 0 {
+		return fmt.Errorf("validation retries must not be negative, got %d", cfg.ValidationRetries)
+	}
+	if cfg.RateLimit < 0 {
+		return fmt.Errorf("rate limit must not be negative, got %d", cfg.RateLimit)
+	}
+	if cfg.SessionConcurrency < 0 {
+		return fmt.Errorf("session concurrency must not be negative, got %d", cfg.SessionConcurrency)
+	}
+	if cfg.ReadCache < 0 {
+		return fmt.Errorf("read cache must not be negative, got %d", cfg.ReadCache)
+	}
+	if cfg.TimeoutPageSize < 0 {
+		return fmt.Errorf("timeout page size must not be negative, got %d", cfg.TimeoutPageSize)
+	}
+	if cfg.TimeoutMaxPages <= 0 {
+		return fmt.Errorf("timeout max pages must be positive, got %d", cfg.TimeoutMaxPages)
+	}
+	if cfg.IdempotencyKeys < 0 {
+		return fmt.Errorf("idempotency keys must not be negative, got %d", cfg.IdempotencyKeys)
+	}
+	if cfg.IdempotencyTTL <= 0 {
+		return fmt.Errorf("idempotency ttl must be positive, got %d", cfg.IdempotencyTTL)
+	}
+	if cfg.Snapshots < 0 {
+		return fmt.Errorf("snapshots must not be negative, got %d", cfg.Snapshots)
+	}
+	if cfg.RecentCommands < 0 {
+		return fmt.Errorf("recent commands must not be negative, got %d", cfg.RecentCommands)
+	}
+	if cfg.MaxTimeout < 0 {
+		return fmt.Errorf("max timeout must not be negative, got %d", cfg.MaxTimeout)
+	}
+	if cfg.ApprovalTimeout <= 0 {
+		return fmt.Errorf("approval webhook timeout must be positive, got %d", cfg.ApprovalTimeout)
+	}
+	if cfg.ApprovalWebhook != "" {
+		if u, err := url.Parse(cfg.ApprovalWebhook); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("approval webhook must be an http or https URL, got '%s'", cfg.ApprovalWebhook)
+		}
+	}
+
+	if err := cfg.checkBinaryPaths(); err != nil {
+		return err
+	}
+
+	if err := cfg.parseOperationTimeouts(*operationTimeouts); err != nil {
+		return err
+	}
+	cfg.clampConfiguredTimeouts()
+
+	if err := cfg.parseOperationOutputBytes(*operationOutputBytes); err != nil {
+		return err
+	}
+
+	if err := cfg.applyNamespacePolicy(); err != nil {
+		return err
+	}
+
+	if cfg.AllowImages != "" {
+		cfg.SecurityConfig.SetAllowedImages(cfg.AllowImages)
+	}
+
+	if cfg.AllowServers != "" {
+		cfg.SecurityConfig.SetAllowedServers(cfg.AllowServers)
+	}
+
+	if cfg.AllowTenants != "" {
+		cfg.SecurityConfig.SetAllowedTenants(cfg.AllowTenants)
+	}
+
+	if cfg.DenyResources != "" {
+		cfg.SecurityConfig.SetDeniedResources(cfg.DenyResources)
+	}
+
+	if cfg.DenyAPIGroups != "" {
+		cfg.SecurityConfig.SetDeniedAPIGroups(cfg.DenyAPIGroups)
+	}
+
+	cfg.SecurityConfig.SetNoExecNamespaces(cfg.NoExecNamespaces)
+	cfg.SecurityConfig.SetProtectedNamespace(cfg.ProtectNamespace, os.Getenv("POD_NAMESPACE"), os.Getenv("POD_NAME"))
+	cfg.SecurityConfig.SetRateLimit(cfg.RateLimit)
+	cfg.SecurityConfig.SetSessionConcurrency(cfg.SessionConcurrency)
+
+	if err := cfg.SecurityConfig.AddRedactPatterns(*redactPatterns); err != nil {
+		return err
+	}
+
+	if err := cfg.parseImpersonationNamespaces(*impersonationNamespaces); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// parseAdditionalTools parses a comma-separated tool list into AdditionalTools
+func (cfg *ConfigData) parseAdditionalTools(value string) {
+	for _, tool := range strings.Split(value, ",") {
+		tool = strings.TrimSpace(tool)
+		if tool == "" {
+			continue
+		}
+		cfg.AdditionalTools[tool] = true
+	}
+}
+
+// applyNamespacePolicy sets the namespaces commands may access. An empty --allow-namespaces
+// allows every namespace, except under --strict-config, where it denies them all unless
+// --allow-all-namespaces opts in explicitly.
+func (cfg *ConfigData) applyNamespacePolicy() error {
 	if cfg.AllowNamespaces != "" {
+		if cfg.AllowAllNamespaces {
+			return fmt.Errorf("allow-all-namespaces can't be combined with allow-namespaces")
+		}
+		if err := cfg.checkNamespacePatterns(); err != nil {
+			return err
+		}
 		cfg.SecurityConfig.SetAllowedNamespaces(cfg.AllowNamespaces)
+		return nil
 	}
 
-	// Parse additional tools
-	if *additionalTools != "" {
-		for _, tool := range strings.Split(*additionalTools, ",") {
-			tool = strings.TrimSpace(tool)
-			if tool == "" {
-				continue
-			}
-			cfg.AdditionalTools[tool] = true
-		}
+	if cfg.StrictConfig && !cfg.AllowAllNamespaces {
+		log.Printf("Warning: no --allow-namespaces under --strict-config, denying every namespace; pass --allow-all-namespaces to allow them all")
+		cfg.SecurityConfig.SetDenyUnlistedNamespaces(true)
+	}
+	return nil
+}
+
+// checkNamespacePatterns rejects allow-namespaces patterns that don't compile under
+// --strict-config; otherwise they are logged and matched as literal names
+func (cfg *ConfigData) checkNamespacePatterns() error {
+	err := security.ValidateNamespacePatterns(cfg.AllowNamespaces)
+	if err == nil {
+		return nil
 	}
+	if cfg.StrictConfig {
+		return fmt.Errorf("allow-namespaces: %w", err)
+	}
+	log.Printf("Warning: allow-namespaces: %v; treating as literal namespace names", err)
+	return nil
+}
 
+// parseImpersonationNamespaces parses "user=namespace" pairs into ImpersonationNamespaces
+func (cfg *ConfigData) parseImpersonationNamespaces(value string) error {
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		user, namespace, ok := strings.Cut(pair, "=")
+		user, namespace = strings.TrimSpace(user), strings.TrimSpace(namespace)
+		if !ok || user == "" || namespace == "" {
+			return fmt.Errorf("invalid impersonation namespace '%s', expected user=namespace", pair)
+		}
+		cfg.ImpersonationNamespaces[user] = namespace
+	}
 	return nil
 }
 
@@ -103,3 +502,21 @@ func IsToolSupported(tool string) bool {
 	}
 	return false
 }
+
+// checkBinaryPaths verifies that every configured tool binary exists and is executable
+func (cfg *ConfigData) checkBinaryPaths() error {
+	for _, binary := range []struct{ flag, path string }{
+		{"kubectl-path", cfg.KubectlPath},
+		{"helm-path", cfg.HelmPath},
+		{"cilium-path", cfg.CiliumPath},
+		{"hubble-path", cfg.HubblePath},
+	} {
+		if binary.path == "" {
+			continue
+		}
+		if err := command.CheckExecutable(binary.path); err != nil {
+			return fmt.Errorf("%s: %w", binary.flag, err)
+		}
+	}
+	return nil
+}
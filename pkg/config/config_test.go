@@ -1,6 +1,9 @@
 package config
 
 import (
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -50,3 +53,245 @@ type ValidationError struct {
 func (e *ValidationError) Error() string {
 	return e.Message
 }
+
+func TestParseImpersonationNamespaces(t *testing.T) {
+	cfg := NewConfig()
+	if err := cfg.parseImpersonationNamespaces("alice=team-a, system:serviceaccount:ci:deployer = ci"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.ImpersonationNamespaces["alice"] != "team-a" || cfg.ImpersonationNamespaces["system:serviceaccount:ci:deployer"] != "ci" {
+		t.Errorf("unexpected mapping: %v", cfg.ImpersonationNamespaces)
+	}
+
+	if err := NewConfig().parseImpersonationNamespaces("alice"); err == nil {
+		t.Error("expected error for pair without namespace")
+	}
+}
+
+func writeConfigFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func noFlagsSet(string) bool { return false }
+
+func TestLoadConfigFile(t *testing.T) {
+	path := writeConfigFile(t, "access_level: readwrite\nallow_namespaces: team-.*\nmax_list_items: 50\nadditional_tools: helm\n")
+
+	cfg := NewConfig()
+	if err := cfg.loadConfigFile(path, noFlagsSet); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.AccessLevel != "readwrite" || cfg.AllowNamespaces != "team-.*" || cfg.MaxListItems != 50 || !cfg.AdditionalTools["helm"] {
+		t.Errorf("config file not applied: %+v", cfg)
+	}
+
+	// Flags set on the command line win over the file
+	cfg = NewConfig()
+	flagSet := func(name string) bool { return name == "access-level" }
+	if err := cfg.loadConfigFile(path, flagSet); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.AccessLevel != "readonly" {
+		t.Errorf("expected flag value to take precedence, got %s", cfg.AccessLevel)
+	}
+}
+
+func TestLoadConfigFile_ResourceVerbs(t *testing.T) {
+	path := writeConfigFile(t, "resource_verbs:\n  \"*\": [get, describe]\n  pods: [get, describe, delete]\n")
+
+	cfg := NewConfig()
+	if err := cfg.loadConfigFile(path, noFlagsSet); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cfg.SecurityConfig.IsVerbAllowed("delete", "pod") || cfg.SecurityConfig.IsVerbAllowed("delete", "deployments") {
+		t.Error("resource verbs policy from the config file not applied")
+	}
+}
+
+func TestLoadConfigFile_UnknownKey(t *testing.T) {
+	path := writeConfigFile(t, "access_level: admin\nallow_namespace: default\n")
+
+	cfg := NewConfig()
+	cfg.StrictConfig = true
+	if err := cfg.loadConfigFile(path, noFlagsSet); err == nil {
+		t.Error("expected error for unknown config key in strict mode")
+	}
+
+	cfg = NewConfig()
+	if err := cfg.loadConfigFile(path, noFlagsSet); err != nil {
+		t.Fatalf("unexpected error outside strict mode: %v", err)
+	}
+	if cfg.AccessLevel != "admin" {
+		t.Errorf("expected known keys to be applied, got access level %s", cfg.AccessLevel)
+	}
+}
+
+func TestLoadConfigFile_Unparseable(t *testing.T) {
+	path := writeConfigFile(t, "access_level: [readonly\n")
+
+	cfg := NewConfig()
+	cfg.StrictConfig = true
+	if err := cfg.loadConfigFile(path, noFlagsSet); err == nil {
+		t.Error("expected error for malformed config file in strict mode")
+	}
+	if err := cfg.loadConfigFile(filepath.Join(t.TempDir(), "missing.yaml"), noFlagsSet); err == nil {
+		t.Error("expected error for missing config file in strict mode")
+	}
+
+	cfg = NewConfig()
+	if err := cfg.loadConfigFile(path, noFlagsSet); err != nil {
+		t.Errorf("unexpected error outside strict mode: %v", err)
+	}
+}
+
+func TestCheckNamespacePatterns_InvalidRegex(t *testing.T) {
+	cfg := NewConfig()
+	cfg.AllowNamespaces = "default,team-[a-z"
+	cfg.StrictConfig = true
+	if err := cfg.checkNamespacePatterns(); err == nil {
+		t.Error("expected error for invalid namespace regex in strict mode")
+	}
+
+	cfg.StrictConfig = false
+	if err := cfg.checkNamespacePatterns(); err != nil {
+		t.Errorf("unexpected error outside strict mode: %v", err)
+	}
+
+	cfg.AllowNamespaces = "default,team-.*"
+	cfg.StrictConfig = true
+	if err := cfg.checkNamespacePatterns(); err != nil {
+		t.Errorf("unexpected error for valid patterns: %v", err)
+	}
+}
+
+func TestEffectiveTimeout(t *testing.T) {
+	cfg := NewConfig()
+	if err := cfg.parseOperationTimeouts("describe=120, logs=30"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cfg.MaxTimeout = 90
+
+	tests := []struct {
+		name      string
+		verb      string
+		requested int
+		expected  int
+	}{
+		{name: "requested over ceiling is clamped", verb: "get", requested: 600, expected: 90},
+		{name: "requested under ceiling", verb: "describe", requested: 45, expected: 45},
+		{name: "operation timeout over ceiling is clamped", verb: "describe", expected: 90},
+		{name: "operation timeout under ceiling", verb: "logs", expected: 30},
+		{name: "no override", verb: "get", expected: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cfg.EffectiveTimeout(tt.verb, tt.requested); got != tt.expected {
+				t.Errorf("EffectiveTimeout(%q, %d) = %d, want %d", tt.verb, tt.requested, got, tt.expected)
+			}
+		})
+	}
+
+	cfg.Timeout = 300
+	cfg.clampConfiguredTimeouts()
+	if cfg.Timeout != 90 || cfg.OperationTimeouts["describe"] != 90 {
+		t.Errorf("configured timeouts not clamped: timeout=%d, operations=%v", cfg.Timeout, cfg.OperationTimeouts)
+	}
+
+	// describe gets a longer default unless --timeout is already longer
+	cfg = NewConfig()
+	if got := cfg.EffectiveTimeout("describe", 0); got != 120 {
+		t.Errorf("default describe timeout = %d, want 120", got)
+	}
+	cfg.Timeout = 300
+	if got := cfg.EffectiveTimeout("describe", 0); got != 0 {
+		t.Errorf("describe timeout with a longer --timeout = %d, want 0", got)
+	}
+
+	if err := NewConfig().parseOperationTimeouts("describe=slow"); err == nil {
+		t.Error("expected error for non-numeric operation timeout")
+	}
+}
+
+func TestOutputByteLimit(t *testing.T) {
+	cfg := NewConfig()
+	cfg.MaxOutputBytes = 100000
+	if err := cfg.parseOperationOutputBytes("logs=500000, get=50000"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if limit, source := cfg.OutputByteLimit("logs"); limit != 500000 || source != "--operation-output-bytes" {
+		t.Errorf("logs limit = %d from %s, want 500000 from --operation-output-bytes", limit, source)
+	}
+	if limit, source := cfg.OutputByteLimit("describe"); limit != 100000 || source != "--max-output-bytes" {
+		t.Errorf("describe limit = %d from %s, want 100000 from --max-output-bytes", limit, source)
+	}
+	for _, value := range []string{"logs", "logs=lots", "logs=0", "=100"} {
+		if err := NewConfig().parseOperationOutputBytes(value); err == nil {
+			t.Errorf("expected error for operation output limit %q", value)
+		}
+	}
+}
+
+func TestApplyNamespacePolicy(t *testing.T) {
+	// Without strict mode an empty allow-list keeps allowing every namespace
+	cfg := NewConfig()
+	if err := cfg.applyNamespacePolicy(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cfg.SecurityConfig.IsNamespaceAllowed("team-a") {
+		t.Error("expected every namespace to be allowed without --strict-config")
+	}
+
+	// Under strict mode it denies every namespace
+	cfg = NewConfig()
+	cfg.StrictConfig = true
+	if err := cfg.applyNamespacePolicy(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.SecurityConfig.IsNamespaceAllowed("team-a") || !cfg.SecurityConfig.HasNamespaceRestrictions() {
+		t.Error("expected an empty allow-list to deny every namespace under --strict-config")
+	}
+
+	// unless all namespaces are allowed explicitly
+	cfg = NewConfig()
+	cfg.StrictConfig = true
+	cfg.AllowAllNamespaces = true
+	if err := cfg.applyNamespacePolicy(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cfg.SecurityConfig.IsNamespaceAllowed("team-a") {
+		t.Error("expected --allow-all-namespaces to allow every namespace")
+	}
+
+	cfg = NewConfig()
+	cfg.AllowNamespaces = "team-a"
+	cfg.AllowAllNamespaces = true
+	if err := cfg.applyNamespacePolicy(); err == nil {
+		t.Error("expected --allow-all-namespaces with --allow-namespaces to be rejected")
+	}
+}
+
+func TestCheckBinaryPaths(t *testing.T) {
+	cfg := NewConfig()
+	if err := cfg.checkBinaryPaths(); err != nil {
+		t.Errorf("unexpected error without paths: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "helm")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatalf("failed to write binary: %v", err)
+	}
+	cfg.HelmPath = path
+	if err := cfg.checkBinaryPaths(); err != nil {
+		t.Errorf("unexpected error for an executable helm path: %v", err)
+	}
+
+	cfg.KubectlPath = filepath.Join(t.TempDir(), "kubectl")
+	if err := cfg.checkBinaryPaths(); err == nil || !strings.Contains(err.Error(), "kubectl-path") {
+		t.Errorf("expected a missing kubectl path to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,224 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// fileConfig mirrors the command-line flags for --config files. Keys use the flag names
+// with underscores; fields left out of the file keep their flag values.
+type fileConfig struct {
+	Transport                *string             `yaml:"transport"`
+	Host                     *string             `yaml:"host"`
+	Port                     *int                `yaml:"port"`
+	Timeout                  *int                `yaml:"timeout"`
+	MaxTimeout               *int                `yaml:"max_timeout"`
+	OperationTimeouts        map[string]int      `yaml:"operation_timeouts"`
+	InjectRequestTimeout     *bool               `yaml:"inject_request_timeout"`
+	AccessLevel              *string             `yaml:"access_level"`
+	HTTPMaxAccessLevel       *string             `yaml:"http_max_access_level"`
+	AllowNamespaces          *string             `yaml:"allow_namespaces"`
+	AllowAllNamespaces       *bool               `yaml:"allow_all_namespaces"`
+	AllowBulkMutations       *bool               `yaml:"allow_bulk_mutations"`
+	DeleteRequiresName       *bool               `yaml:"delete_requires_name"`
+	AllowImages              *string             `yaml:"allow_images"`
+	AllowServers             *string             `yaml:"allow_servers"`
+	AllowTenants             *string             `yaml:"allow_tenants"`
+	DenyResources            *string             `yaml:"deny_resources"`
+	DenyAPIGroups            *string             `yaml:"deny_api_groups"`
+	NoExecNamespaces         *string             `yaml:"no_exec_namespaces"`
+	ProtectNamespace         *string             `yaml:"protect_namespace"`
+	ResourceVerbs            map[string][]string `yaml:"resource_verbs"`
+	FilterNamespaceList      *bool               `yaml:"filter_namespace_list"`
+	ExpandAllNamespaces      *bool               `yaml:"expand_all_namespaces"`
+	AdditionalTools          *string             `yaml:"additional_tools"`
+	ValidateClusterRole      *bool               `yaml:"validate_cluster_role"`
+	ValidationRetries        *int                `yaml:"validation_retries"`
+	RequireAdminConfirm      *bool               `yaml:"require_admin_confirm"`
+	RequireExplicitNamespace *bool               `yaml:"require_explicit_namespace"`
+	DisallowInsecureTLS      *bool               `yaml:"disallow_insecure_tls"`
+	RecordChangeCause        *bool               `yaml:"record_change_cause"`
+	RequireReason            *bool               `yaml:"require_reason"`
+	RequireResourceLimits    *bool               `yaml:"require_resource_limits"`
+	ApprovalWebhook          *string             `yaml:"approval_webhook"`
+	ApprovalTimeout          *int                `yaml:"approval_webhook_timeout"`
+	ReadSource               *string             `yaml:"read_source"`
+	KubectlPath              *string             `yaml:"kubectl_path"`
+	KubectlUserAgent         *string             `yaml:"kubectl_user_agent"`
+	HelmPath                 *string             `yaml:"helm_path"`
+	CiliumPath               *string             `yaml:"cilium_path"`
+	HubblePath               *string             `yaml:"hubble_path"`
+	ResultTransform          *string             `yaml:"result_transform"`
+	JQExpression             *string             `yaml:"jq_expression"`
+	InformerResync           *int                `yaml:"informer_resync"`
+	MaxListItems             *int                `yaml:"max_list_items"`
+	MaxOutputBytes           *int                `yaml:"max_output_bytes"`
+	OperationOutputBytes     map[string]int      `yaml:"operation_output_bytes"`
+	RateLimit                *int                `yaml:"rate_limit"`
+	SessionConcurrency       *int                `yaml:"session_concurrency"`
+	RecentCommands           *int                `yaml:"recent_commands"`
+	ReadCache                *int                `yaml:"read_cache"`
+	TimeoutPageSize          *int                `yaml:"timeout_page_size"`
+	TimeoutMaxPages          *int                `yaml:"timeout_max_pages"`
+	IdempotencyKeys          *int                `yaml:"idempotency_keys"`
+	IdempotencyTTL           *int                `yaml:"idempotency_ttl"`
+	Snapshots                *int                `yaml:"snapshots"`
+	DescribeYAMLKinds        *string             `yaml:"describe_yaml_kinds"`
+	EditStripPaths           *string             `yaml:"edit_strip_paths"`
+	RedactPatterns           []string            `yaml:"redact_patterns"`
+	ImpersonationNamespaces  map[string]string   `yaml:"impersonation_namespaces"`
+}
+
+// readConfigFile loads a --config file. Unknown keys and unreadable files are errors in
+// strict mode; otherwise they are logged and the file (or the unknown keys) ignored.
+func readConfigFile(path string, strict bool) (*fileConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if strict {
+			return nil, fmt.Errorf("failed to read config file: %w", err)
+		}
+		log.Printf("Warning: ignoring config file %s: %v", path, err)
+		return &fileConfig{}, nil
+	}
+
+	var fc fileConfig
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&fc); err == nil || isEmptyDocument(err) {
+		return &fc, nil
+	} else if strict {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	} else {
+		log.Printf("Warning: config file %s: %v", path, err)
+	}
+
+	// Retry without rejecting unknown keys, so only a file that can't be parsed at all is ignored
+	fc = fileConfig{}
+	if err := yaml.Unmarshal(data, &fc); err != nil {
+		log.Printf("Warning: ignoring config file %s: %v", path, err)
+		return &fileConfig{}, nil
+	}
+	return &fc, nil
+}
+
+// isEmptyDocument reports the error yaml returns for a file with no content
+func isEmptyDocument(err error) bool {
+	return err != nil && err.Error() == "EOF"
+}
+
+// loadConfigFile reads a --config file and applies it to cfg. Options whose flag was set on
+// the command line keep the flag value; redact patterns and impersonation namespaces from
+// the file are merged with those given as flags.
+func (cfg *ConfigData) loadConfigFile(path string, flagSet func(name string) bool) error {
+	fc, err := readConfigFile(path, cfg.StrictConfig)
+	if err != nil {
+		return err
+	}
+	if err := cfg.applyConfigFile(fc, flagSet); err != nil {
+		return fmt.Errorf("config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// applyConfigFile copies values from a config file into cfg, skipping options whose flag
+// was set on the command line so flags always take precedence
+func (cfg *ConfigData) applyConfigFile(fc *fileConfig, flagSet func(name string) bool) error {
+	setString := func(flag string, dst *string, value *string) {
+		if value != nil && !flagSet(flag) {
+			*dst = *value
+		}
+	}
+	setInt := func(flag string, dst *int, value *int) {
+		if value != nil && !flagSet(flag) {
+			*dst = *value
+		}
+	}
+	setBool := func(flag string, dst *bool, value *bool) {
+		if value != nil && !flagSet(flag) {
+			*dst = *value
+		}
+	}
+
+	setString("transport", &cfg.Transport, fc.Transport)
+	setString("host", &cfg.Host, fc.Host)
+	setInt("port", &cfg.Port, fc.Port)
+	setInt("timeout", &cfg.Timeout, fc.Timeout)
+	setInt("max-timeout", &cfg.MaxTimeout, fc.MaxTimeout)
+	setString("access-level", &cfg.AccessLevel, fc.AccessLevel)
+	setString("http-max-access-level", &cfg.HTTPMaxAccessLevel, fc.HTTPMaxAccessLevel)
+	setString("allow-namespaces", &cfg.AllowNamespaces, fc.AllowNamespaces)
+	setBool("allow-all-namespaces", &cfg.AllowAllNamespaces, fc.AllowAllNamespaces)
+	setBool("inject-request-timeout", &cfg.InjectRequestTimeout, fc.InjectRequestTimeout)
+	setBool("allow-bulk-mutations", &cfg.AllowBulkMutations, fc.AllowBulkMutations)
+	setBool("delete-requires-name", &cfg.DeleteRequiresName, fc.DeleteRequiresName)
+	setString("allow-images", &cfg.AllowImages, fc.AllowImages)
+	setString("allow-servers", &cfg.AllowServers, fc.AllowServers)
+	setString("allow-tenants", &cfg.AllowTenants, fc.AllowTenants)
+	setString("deny-resources", &cfg.DenyResources, fc.DenyResources)
+	setString("deny-api-groups", &cfg.DenyAPIGroups, fc.DenyAPIGroups)
+	setString("no-exec-namespaces", &cfg.NoExecNamespaces, fc.NoExecNamespaces)
+	setString("protect-namespace", &cfg.ProtectNamespace, fc.ProtectNamespace)
+	setBool("validate-cluster-role", &cfg.ValidateClusterRole, fc.ValidateClusterRole)
+	setInt("validation-retries", &cfg.ValidationRetries, fc.ValidationRetries)
+	setBool("require-admin-confirm", &cfg.SecurityConfig.RequireAdminConfirm, fc.RequireAdminConfirm)
+	setBool("require-explicit-namespace", &cfg.SecurityConfig.RequireExplicitNamespace, fc.RequireExplicitNamespace)
+	setBool("disallow-insecure-tls", &cfg.SecurityConfig.DisallowInsecureTLS, fc.DisallowInsecureTLS)
+	setBool("record-change-cause", &cfg.RecordChangeCause, fc.RecordChangeCause)
+	setBool("require-reason", &cfg.RequireReason, fc.RequireReason)
+	setBool("require-resource-limits", &cfg.RequireResourceLimits, fc.RequireResourceLimits)
+	setString("approval-webhook", &cfg.ApprovalWebhook, fc.ApprovalWebhook)
+	setInt("approval-webhook-timeout", &cfg.ApprovalTimeout, fc.ApprovalTimeout)
+	setBool("filter-namespace-list", &cfg.FilterNamespaceList, fc.FilterNamespaceList)
+	setBool("expand-all-namespaces", &cfg.ExpandAllNamespaces, fc.ExpandAllNamespaces)
+	setString("read-source", &cfg.ReadSource, fc.ReadSource)
+	setString("kubectl-path", &cfg.KubectlPath, fc.KubectlPath)
+	setString("kubectl-user-agent", &cfg.KubectlUserAgent, fc.KubectlUserAgent)
+	setString("helm-path", &cfg.HelmPath, fc.HelmPath)
+	setString("cilium-path", &cfg.CiliumPath, fc.CiliumPath)
+	setString("hubble-path", &cfg.HubblePath, fc.HubblePath)
+	setString("result-transform", &cfg.ResultTransform, fc.ResultTransform)
+	setString("jq-expression", &cfg.JQExpression, fc.JQExpression)
+	setInt("informer-resync", &cfg.InformerResync, fc.InformerResync)
+	setInt("max-list-items", &cfg.MaxListItems, fc.MaxListItems)
+	setInt("max-output-bytes", &cfg.MaxOutputBytes, fc.MaxOutputBytes)
+	setInt("rate-limit", &cfg.RateLimit, fc.RateLimit)
+	setInt("session-concurrency", &cfg.SessionConcurrency, fc.SessionConcurrency)
+	setInt("recent-commands", &cfg.RecentCommands, fc.RecentCommands)
+	setInt("read-cache", &cfg.ReadCache, fc.ReadCache)
+	setInt("timeout-page-size", &cfg.TimeoutPageSize, fc.TimeoutPageSize)
+	setInt("timeout-max-pages", &cfg.TimeoutMaxPages, fc.TimeoutMaxPages)
+	setInt("idempotency-keys", &cfg.IdempotencyKeys, fc.IdempotencyKeys)
+	setInt("idempotency-ttl", &cfg.IdempotencyTTL, fc.IdempotencyTTL)
+	setInt("snapshots", &cfg.Snapshots, fc.Snapshots)
+	setString("describe-yaml-kinds", &cfg.DescribeYAMLKinds, fc.DescribeYAMLKinds)
+	setString("edit-strip-paths", &cfg.EditStripPaths, fc.EditStripPaths)
+
+	if fc.AdditionalTools != nil && !flagSet("additional-tools") {
+		cfg.parseAdditionalTools(*fc.AdditionalTools)
+	}
+	for verb, timeout := range fc.OperationTimeouts {
+		if timeout <= 0 {
+			return fmt.Errorf("operation timeout for %s must be positive, got %d", verb, timeout)
+		}
+		cfg.OperationTimeouts[verb] = timeout
+	}
+	for verb, limit := range fc.OperationOutputBytes {
+		if limit <= 0 {
+			return fmt.Errorf("operation output limit for %s must be positive, got %d", verb, limit)
+		}
+		cfg.OperationOutputBytes[verb] = limit
+	}
+	if len(fc.ResourceVerbs) > 0 {
+		if err := cfg.SecurityConfig.SetResourceVerbs(fc.ResourceVerbs); err != nil {
+			return err
+		}
+	}
+	for user, namespace := range fc.ImpersonationNamespaces {
+		cfg.ImpersonationNamespaces[user] = namespace
+	}
+	return cfg.SecurityConfig.AddRedactPatterns(fc.RedactPatterns)
+}
@@ -0,0 +1,35 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parseOperationOutputBytes parses "verb=bytes" pairs into OperationOutputBytes
+func (cfg *ConfigData) parseOperationOutputBytes(value string) error {
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		verb, size, ok := strings.Cut(pair, "=")
+		verb = strings.TrimSpace(verb)
+		limit, err := strconv.Atoi(strings.TrimSpace(size))
+		if !ok || verb == "" || err != nil || limit <= 0 {
+			return fmt.Errorf("invalid operation output limit '%s', expected verb=bytes", pair)
+		}
+		cfg.OperationOutputBytes[verb] = limit
+	}
+	return nil
+}
+
+// OutputByteLimit returns the most bytes of output returned for a command with the given
+// kubectl verb, and the flag that set it: the verb's --operation-output-bytes entry, else
+// --max-output-bytes. Zero means the output is returned whole.
+func (cfg *ConfigData) OutputByteLimit(verb string) (int, string) {
+	if limit, ok := cfg.OperationOutputBytes[verb]; ok {
+		return limit, "--operation-output-bytes"
+	}
+	return cfg.MaxOutputBytes, "--max-output-bytes"
+}
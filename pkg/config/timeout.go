@@ -0,0 +1,71 @@
+package config
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+)
+
+// defaultOperationTimeouts are the timeouts in seconds of kubectl verbs that are slow by
+// nature, used when --operation-timeouts doesn't set one and --timeout is shorter. describe
+// on a large custom resource or a service with many endpoints easily takes over a minute.
+var defaultOperationTimeouts = map[string]int{
+	"describe": 120,
+}
+
+// parseOperationTimeouts parses "verb=seconds" pairs into OperationTimeouts
+func (cfg *ConfigData) parseOperationTimeouts(value string) error {
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		verb, seconds, ok := strings.Cut(pair, "=")
+		verb = strings.TrimSpace(verb)
+		timeout, err := strconv.Atoi(strings.TrimSpace(seconds))
+		if !ok || verb == "" || err != nil || timeout <= 0 {
+			return fmt.Errorf("invalid operation timeout '%s', expected verb=seconds", pair)
+		}
+		cfg.OperationTimeouts[verb] = timeout
+	}
+	return nil
+}
+
+// clampConfiguredTimeouts holds the global and per-operation timeouts to --max-timeout
+func (cfg *ConfigData) clampConfiguredTimeouts() {
+	cfg.Timeout = cfg.clampTimeout(cfg.Timeout, "timeout")
+	for verb, timeout := range cfg.OperationTimeouts {
+		cfg.OperationTimeouts[verb] = cfg.clampTimeout(timeout, "operation timeout for "+verb)
+	}
+}
+
+// EffectiveTimeout returns the timeout in seconds for a command with the given kubectl verb:
+// the caller's requested timeout if positive, else the verb's operation timeout, else its
+// default from defaultOperationTimeouts when longer than Timeout. Zero means none applies and
+// the transport's default is used. The result never exceeds MaxTimeout.
+func (cfg *ConfigData) EffectiveTimeout(verb string, requested int) int {
+	if requested > 0 {
+		return cfg.clampTimeout(requested, "requested timeout")
+	}
+	if timeout, ok := cfg.OperationTimeouts[verb]; ok {
+		return cfg.clampTimeout(timeout, "operation timeout for "+verb)
+	}
+	if timeout := defaultOperationTimeouts[verb]; timeout > cfg.Timeout {
+		return cfg.clampTimeout(timeout, "default operation timeout for "+verb)
+	}
+	return 0
+}
+
+// ClampTimeout holds a timeout in seconds to MaxTimeout, logging when it is lowered
+func (cfg *ConfigData) ClampTimeout(timeout int) int {
+	return cfg.clampTimeout(timeout, "timeout")
+}
+
+func (cfg *ConfigData) clampTimeout(timeout int, source string) int {
+	if cfg == nil || cfg.MaxTimeout <= 0 || timeout <= cfg.MaxTimeout {
+		return timeout
+	}
+	log.Printf("Warning: %s of %ds exceeds max-timeout, using %ds", source, timeout, cfg.MaxTimeout)
+	return cfg.MaxTimeout
+}
@@ -2,6 +2,7 @@ package helm
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/Azure/mcp-kubernetes/pkg/command"
 	"github.com/Azure/mcp-kubernetes/pkg/config"
@@ -22,7 +23,23 @@ func NewExecutor() *HelmExecutor {
 // Execute handles helm command execution
 func (e *HelmExecutor) Execute(params map[string]interface{}, cfg *config.ConfigData) (string, error) {
 	helmCmd, ok := params["command"].(string)
-	if !ok {
+	chart, _ := params["chart"].(string)
+	if release, _ := params["values_release"].(string); strings.TrimSpace(release) != "" {
+		if ok && strings.TrimSpace(helmCmd) != "" || strings.TrimSpace(chart) != "" {
+			return "", fmt.Errorf("values_release runs helm get values and can't be combined with command or chart")
+		}
+		return e.releaseValues(strings.TrimSpace(release), params, cfg)
+	}
+	if strings.TrimSpace(chart) != "" {
+		if ok && strings.TrimSpace(helmCmd) != "" {
+			return "", fmt.Errorf("chart renders with helm template and can't be combined with command")
+		}
+		var err error
+		helmCmd, err = templateCommand(strings.TrimSpace(chart), params)
+		if err != nil {
+			return "", err
+		}
+	} else if !ok {
 		return "", fmt.Errorf("invalid command parameter")
 	}
 
@@ -35,5 +52,27 @@ func (e *HelmExecutor) Execute(params map[string]interface{}, cfg *config.Config
 
 	// Execute the command
 	process := command.NewShellProcess("helm", cfg.Timeout)
+	process.Path = cfg.HelmPath
 	return process.Run(helmCmd)
 }
+
+// releaseValues returns the values of an installed release as parsed JSON. helm get is a
+// read, so the command passes the access level and namespace checks of every mode.
+func (e *HelmExecutor) releaseValues(release string, params map[string]interface{}, cfg *config.ConfigData) (string, error) {
+	helmCmd, err := valuesCommand(release, params)
+	if err != nil {
+		return "", err
+	}
+	validator := security.NewValidator(cfg.SecurityConfig)
+	if err := validator.ValidateCommand(helmCmd, security.CommandTypeHelm); err != nil {
+		return "", err
+	}
+
+	process := command.NewShellProcess("helm", cfg.Timeout)
+	process.Path = cfg.HelmPath
+	output, err := process.Run(helmCmd)
+	if err != nil {
+		return "", err
+	}
+	return parseValues(release, params, output)
+}
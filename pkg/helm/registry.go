@@ -9,8 +9,25 @@ func RegisterHelm() mcp.Tool {
 	return mcp.NewTool("helm",
 		mcp.WithDescription("Run Helm package manager commands for Kubernetes"),
 		mcp.WithString("command",
-			mcp.Required(),
-			mcp.Description("The helm command to execute (e.g., 'helm list', 'helm install myapp ./chart')"),
+			mcp.Description("The helm command to execute (e.g., 'helm list', 'helm install myapp ./chart'); omit it to render chart or read values_release"),
+		),
+		mcp.WithString("chart",
+			mcp.Description("Chart to render with helm template, without installing it (e.g. './chart' or 'bitnami/nginx')"),
+		),
+		mcp.WithString("release",
+			mcp.Description("Release name to render chart as, default 'release'"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("Namespace to render chart for, or of values_release (adds --namespace)"),
+		),
+		mcp.WithObject("values",
+			mcp.Description("Values to render chart with, as an object (each top-level key is added with --set-json)"),
+		),
+		mcp.WithString("values_release",
+			mcp.Description("Installed release whose values to return as JSON {release, namespace, computed, values}, from helm get values"),
+		),
+		mcp.WithBoolean("all_values",
+			mcp.Description("With values_release, include the chart defaults as well as the supplied values (adds --all)"),
 		),
 	)
 }
@@ -0,0 +1,68 @@
+package helm
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// templateName matches release names and namespaces (DNS labels)
+var templateName = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+// templateValueKey matches the top-level value keys that --set-json takes literally
+var templateValueKey = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// templateCommand builds the helm template command that renders chart from the release,
+// namespace and values parameters
+func templateCommand(chart string, params map[string]interface{}) (string, error) {
+	if strings.HasPrefix(chart, "-") || strings.ContainsAny(chart, " '\"\\") {
+		return "", fmt.Errorf("invalid chart '%s'", chart)
+	}
+	release, _ := params["release"].(string)
+	release = strings.TrimSpace(release)
+	if release == "" {
+		release = "release"
+	}
+	if !templateName.MatchString(release) {
+		return "", fmt.Errorf("invalid release name '%s'", release)
+	}
+
+	parts := []string{"helm", "template", release, chart}
+	if namespace, _ := params["namespace"].(string); strings.TrimSpace(namespace) != "" {
+		namespace = strings.TrimSpace(namespace)
+		if !templateName.MatchString(namespace) {
+			return "", fmt.Errorf("invalid namespace '%s'", namespace)
+		}
+		parts = append(parts, "--namespace", namespace)
+	}
+
+	if raw, ok := params["values"]; ok && raw != nil {
+		values, ok := raw.(map[string]interface{})
+		if !ok {
+			return "", fmt.Errorf("values must be an object")
+		}
+		keys := make([]string, 0, len(values))
+		for key := range values {
+			if !templateValueKey.MatchString(key) {
+				return "", fmt.Errorf("invalid values key '%s'", key)
+			}
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			data, err := json.Marshal(values[key])
+			if err != nil {
+				return "", fmt.Errorf("failed to encode value '%s': %w", key, err)
+			}
+			parts = append(parts, "--set-json="+singleQuote(key+"="+string(data)))
+		}
+	}
+	return strings.Join(parts, " "), nil
+}
+
+// singleQuote wraps s in single quotes for the command parser, escaping quotes inside it
+func singleQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'"'"'`) + "'"
+}
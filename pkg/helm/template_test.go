@@ -0,0 +1,50 @@
+package helm
+
+import (
+	"testing"
+
+	"github.com/google/shlex"
+)
+
+func TestTemplateCommand(t *testing.T) {
+	command, err := templateCommand("./charts/web", map[string]interface{}{
+		"release":   "web",
+		"namespace": "shop",
+		"values": map[string]interface{}{
+			"replicaCount": 3,
+			"image":        map[string]interface{}{"tag": "it's-1.2"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	parts, err := shlex.Split(command)
+	if err != nil {
+		t.Fatalf("command %q doesn't parse: %v", command, err)
+	}
+	want := []string{"helm", "template", "web", "./charts/web", "--namespace", "shop",
+		`--set-json=image={"tag":"it's-1.2"}`, "--set-json=replicaCount=3"}
+	if len(parts) != len(want) {
+		t.Fatalf("command = %q, want %q", parts, want)
+	}
+	for i := range want {
+		if parts[i] != want[i] {
+			t.Errorf("part %d = %q, want %q", i, parts[i], want[i])
+		}
+	}
+
+	for _, params := range []map[string]interface{}{
+		{"release": "Web App"},
+		{"namespace": "shop; rm"},
+		{"values": map[string]interface{}{"image.tag": "1.2"}},
+		{"values": "replicaCount=3"},
+	} {
+		if _, err := templateCommand("./charts/web", params); err == nil {
+			t.Errorf("templateCommand() with %v should fail", params)
+		}
+	}
+	if _, err := templateCommand("--post-renderer=x", nil); err == nil {
+		t.Error("templateCommand() should refuse a flag as the chart")
+	}
+}
@@ -0,0 +1,61 @@
+package helm
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// releaseValues is the parsed output of helm get values for one release
+type releaseValues struct {
+	Release   string `json:"release"`
+	Namespace string `json:"namespace,omitempty"`
+	// Computed is true when Values include the chart defaults (--all), not only the values the
+	// release was installed or upgraded with
+	Computed bool                   `json:"computed"`
+	Values   map[string]interface{} `json:"values"`
+}
+
+// valuesCommand builds the helm get values command for the values_release, all_values and
+// namespace parameters
+func valuesCommand(release string, params map[string]interface{}) (string, error) {
+	if !templateName.MatchString(release) {
+		return "", fmt.Errorf("invalid release name '%s'", release)
+	}
+
+	parts := []string{"helm", "get", "values", release, "-o", "json"}
+	if all, _ := params["all_values"].(bool); all {
+		parts = append(parts, "--all")
+	}
+	if namespace, _ := params["namespace"].(string); strings.TrimSpace(namespace) != "" {
+		namespace = strings.TrimSpace(namespace)
+		if !templateName.MatchString(namespace) {
+			return "", fmt.Errorf("invalid namespace '%s'", namespace)
+		}
+		parts = append(parts, "--namespace", namespace)
+	}
+	return strings.Join(parts, " "), nil
+}
+
+// parseValues turns the JSON printed by helm get values into a releaseValues result. A
+// release installed without values prints null, which becomes an empty object.
+func parseValues(release string, params map[string]interface{}, output string) (string, error) {
+	result := releaseValues{Release: release, Values: map[string]interface{}{}}
+	if namespace, _ := params["namespace"].(string); strings.TrimSpace(namespace) != "" {
+		result.Namespace = strings.TrimSpace(namespace)
+	}
+	result.Computed, _ = params["all_values"].(bool)
+
+	if err := json.Unmarshal([]byte(strings.TrimSpace(output)), &result.Values); err != nil {
+		return "", fmt.Errorf("failed to parse values of release '%s': %w", release, err)
+	}
+	if result.Values == nil {
+		result.Values = map[string]interface{}{}
+	}
+
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode values: %w", err)
+	}
+	return string(data), nil
+}
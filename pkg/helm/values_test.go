@@ -0,0 +1,66 @@
+package helm
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestValuesCommand(t *testing.T) {
+	command, err := valuesCommand("web", map[string]interface{}{"namespace": "shop", "all_values": true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "helm get values web -o json --all --namespace shop"; command != want {
+		t.Errorf("command = %q, want %q", command, want)
+	}
+
+	for _, release := range []string{"Web App", "--all", "web;rm"} {
+		if _, err := valuesCommand(release, nil); err == nil {
+			t.Errorf("valuesCommand(%q) should fail", release)
+		}
+	}
+	if _, err := valuesCommand("web", map[string]interface{}{"namespace": "shop; rm"}); err == nil {
+		t.Error("valuesCommand() should refuse an invalid namespace")
+	}
+}
+
+func TestParseValues(t *testing.T) {
+	output := `{"image":{"repository":"nginx","tag":"1.25"},"replicaCount":3,"ingress":{"enabled":false,"hosts":["web.example.com"]}}`
+	result, err := parseValues("web", map[string]interface{}{"namespace": "shop", "all_values": true}, output)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var parsed releaseValues
+	if err := json.Unmarshal([]byte(result), &parsed); err != nil {
+		t.Fatalf("result is not JSON: %v\n%s", err, result)
+	}
+	if parsed.Release != "web" || parsed.Namespace != "shop" || !parsed.Computed {
+		t.Errorf("unexpected release fields: %+v", parsed)
+	}
+	image, _ := parsed.Values["image"].(map[string]interface{})
+	if image["repository"] != "nginx" || image["tag"] != "1.25" {
+		t.Errorf("image = %v, want nginx 1.25", parsed.Values["image"])
+	}
+	if parsed.Values["replicaCount"] != float64(3) {
+		t.Errorf("replicaCount = %v, want 3", parsed.Values["replicaCount"])
+	}
+	ingress, _ := parsed.Values["ingress"].(map[string]interface{})
+	hosts, _ := ingress["hosts"].([]interface{})
+	if ingress["enabled"] != false || len(hosts) != 1 || hosts[0] != "web.example.com" {
+		t.Errorf("ingress = %v", parsed.Values["ingress"])
+	}
+
+	// A release installed without values prints null
+	result, err = parseValues("web", nil, "null\n")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "{\n  \"release\": \"web\",\n  \"computed\": false,\n  \"values\": {}\n}"; result != want {
+		t.Errorf("result = %q, want %q", result, want)
+	}
+
+	if _, err := parseValues("web", nil, "USER-SUPPLIED VALUES:\nreplicaCount: 3\n"); err == nil {
+		t.Error("parseValues() should fail on non-JSON output")
+	}
+}
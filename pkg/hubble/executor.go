@@ -36,5 +36,6 @@ func (e *HubbleExecutor) Execute(params map[string]interface{}, cfg *config.Conf
 
 	// Execute the command
 	process := command.NewShellProcess("hubble", cfg.Timeout)
+	process.Path = cfg.HubblePath
 	return process.Run(hubbleCmd)
 }
@@ -0,0 +1,66 @@
+package kubectl
+
+import (
+	"fmt"
+	"strings"
+)
+
+// apiVersion is one line of `kubectl api-versions` output; the core group is ""
+type apiVersion struct {
+	Group   string `json:"group"`
+	Version string `json:"version"`
+}
+
+// parseAPIVersions splits api-versions output into group and version pairs. Lines of the
+// form "v1" belong to the core group.
+func parseAPIVersions(output string) (interface{}, error) {
+	versions := []apiVersion{}
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if strings.ContainsAny(line, " \t") || strings.Count(line, "/") > 1 {
+			return nil, fmt.Errorf("unexpected api-versions line %q", line)
+		}
+		group, version, ok := strings.Cut(line, "/")
+		if !ok {
+			group, version = "", line
+		}
+		if version == "" {
+			return nil, fmt.Errorf("unexpected api-versions line %q", line)
+		}
+		versions = append(versions, apiVersion{Group: group, Version: version})
+	}
+	return versions, nil
+}
+
+// filterAPIVersions keeps the lines of api-versions output in the given API group, where
+// "core" names the core group. Output that can't be parsed is returned unchanged.
+func filterAPIVersions(output, group string) string {
+	if _, err := parseAPIVersions(output); err != nil {
+		return output
+	}
+	if group == "core" {
+		group = ""
+	}
+
+	var kept []string
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		lineGroup, _, ok := strings.Cut(line, "/")
+		if !ok {
+			lineGroup = ""
+		}
+		if lineGroup == group {
+			kept = append(kept, line)
+		}
+	}
+	if len(kept) == 0 {
+		return ""
+	}
+	return strings.Join(kept, "\n") + "\n"
+}
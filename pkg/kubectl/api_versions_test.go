@@ -0,0 +1,101 @@
+package kubectl
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const apiVersionsOutput = `admissionregistration.k8s.io/v1
+apps/v1
+autoscaling/v1
+autoscaling/v2
+batch/v1
+v1
+`
+
+func TestParseAPIVersions(t *testing.T) {
+	value, err := parseAPIVersions(apiVersionsOutput)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	versions := value.([]apiVersion)
+	if len(versions) != 6 {
+		t.Fatalf("expected 6 versions, got %+v", versions)
+	}
+	if versions[1] != (apiVersion{Group: "apps", Version: "v1"}) || versions[5] != (apiVersion{Group: "", Version: "v1"}) {
+		t.Errorf("unexpected versions: %+v", versions)
+	}
+
+	if _, err := parseAPIVersions("error: the server doesn't have a resource type"); err == nil {
+		t.Error("expected non-version output to fail parsing")
+	}
+}
+
+func TestKubectlToolExecutor_APIVersionsGroup(t *testing.T) {
+	worker := newTestWorker(t, func(command string) map[string]interface{} {
+		if command != "kubectl api-versions" {
+			return map[string]interface{}{"error": "unexpected command: " + command}
+		}
+		return map[string]interface{}{"stdout": apiVersionsOutput}
+	})
+	executor := NewKubectlToolExecutor(worker)
+
+	tests := []struct {
+		group    string
+		expected []apiVersion
+	}{
+		{"", []apiVersion{{"admissionregistration.k8s.io", "v1"}, {"apps", "v1"}, {"autoscaling", "v1"}, {"autoscaling", "v2"}, {"batch", "v1"}, {"", "v1"}}},
+		{"autoscaling", []apiVersion{{"autoscaling", "v1"}, {"autoscaling", "v2"}}},
+		{"core", []apiVersion{{"", "v1"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.group, func(t *testing.T) {
+			result, err := executor.Execute(map[string]interface{}{
+				"_tool_name": "kubectl_cluster",
+				"operation":  "api-versions",
+				"resource":   "",
+				"args":       "",
+				"group":      tt.group,
+				"structured": true,
+			}, newTestConfig("readonly"))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var versions []apiVersion
+			if err := json.Unmarshal([]byte(result), &versions); err != nil {
+				t.Fatalf("result is not a version list: %v\n%s", err, result)
+			}
+			if len(versions) != len(tt.expected) {
+				t.Fatalf("versions = %+v, want %+v", versions, tt.expected)
+			}
+			for i := range versions {
+				if versions[i] != tt.expected[i] {
+					t.Errorf("versions[%d] = %+v, want %+v", i, versions[i], tt.expected[i])
+				}
+			}
+		})
+	}
+
+	// Without structured the filtered raw lines are returned
+	result, err := executor.Execute(map[string]interface{}{
+		"_tool_name": "kubectl_cluster",
+		"operation":  "api-versions",
+		"resource":   "",
+		"args":       "",
+		"group":      "batch",
+	}, newTestConfig("readonly"))
+	if err != nil || result != "batch/v1\n" {
+		t.Errorf("result = %q, %v; want only batch/v1", result, err)
+	}
+
+	if _, err := executor.Execute(map[string]interface{}{
+		"_tool_name": "kubectl_cluster",
+		"operation":  "api-resources",
+		"resource":   "",
+		"args":       "",
+		"group":      "apps",
+	}, newTestConfig("readonly")); err == nil || !strings.Contains(err.Error(), "group is only supported for api-versions") {
+		t.Errorf("expected group to be refused for api-resources, got %v", err)
+	}
+}
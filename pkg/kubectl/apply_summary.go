@@ -0,0 +1,66 @@
+package kubectl
+
+import (
+	"fmt"
+	"strings"
+)
+
+// applySummary groups the objects of an apply by what happened to each
+type applySummary struct {
+	Created    []string `json:"created"`
+	Configured []string `json:"configured"`
+	Unchanged  []string `json:"unchanged"`
+	// ServerSideApplied lists objects applied with --server-side, which kubectl doesn't
+	// report as created or configured
+	ServerSideApplied []string `json:"serverside_applied,omitempty"`
+	Pruned            []string `json:"pruned,omitempty"`
+	DryRun            bool     `json:"dry_run,omitempty"`
+	Warnings          []string `json:"warnings,omitempty"`
+}
+
+// parseApply parses the "<type>/<name> <result>" lines kubectl apply prints, e.g.
+// "deployment.apps/web configured" or "service/web created (dry run)". Any other line, such
+// as the output of -o json, is an error so the raw output is returned instead.
+func parseApply(output string) (interface{}, error) {
+	summary := &applySummary{Created: []string{}, Configured: []string{}, Unchanged: []string{}}
+	objects := 0
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "Warning:") {
+			summary.Warnings = append(summary.Warnings, strings.TrimSpace(strings.TrimPrefix(line, "Warning:")))
+			continue
+		}
+
+		for _, suffix := range []string{" (dry run)", " (server dry run)"} {
+			if trimmed, ok := strings.CutSuffix(line, suffix); ok {
+				line, summary.DryRun = trimmed, true
+			}
+		}
+		object, result, ok := strings.Cut(line, " ")
+		if !ok || !strings.Contains(object, "/") {
+			return nil, fmt.Errorf("unexpected apply output line: %q", line)
+		}
+		switch result {
+		case "created":
+			summary.Created = append(summary.Created, object)
+		case "configured":
+			summary.Configured = append(summary.Configured, object)
+		case "unchanged":
+			summary.Unchanged = append(summary.Unchanged, object)
+		case "serverside-applied":
+			summary.ServerSideApplied = append(summary.ServerSideApplied, object)
+		case "pruned":
+			summary.Pruned = append(summary.Pruned, object)
+		default:
+			return nil, fmt.Errorf("unexpected apply result %q for %s", result, object)
+		}
+		objects++
+	}
+	if objects == 0 {
+		return nil, fmt.Errorf("no applied objects in output")
+	}
+	return summary, nil
+}
@@ -0,0 +1,75 @@
+package kubectl
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+const multiObjectApply = `namespace/shop unchanged
+configmap/settings configured
+service/web unchanged
+deployment.apps/web configured
+horizontalpodautoscaler.autoscaling/web created
+`
+
+func TestParseApply(t *testing.T) {
+	value, err := parseApply(multiObjectApply)
+	if err != nil {
+		t.Fatalf("parseApply() error = %v", err)
+	}
+	expected := &applySummary{
+		Created:    []string{"horizontalpodautoscaler.autoscaling/web"},
+		Configured: []string{"configmap/settings", "deployment.apps/web"},
+		Unchanged:  []string{"namespace/shop", "service/web"},
+	}
+	if !reflect.DeepEqual(value, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, value)
+	}
+
+	value, err = parseApply("Warning: resource configmaps/settings is missing the kubectl.kubernetes.io/last-applied-configuration annotation\nconfigmap/settings configured (server dry run)\n")
+	if err != nil {
+		t.Fatalf("parseApply() error = %v", err)
+	}
+	summary := value.(*applySummary)
+	if !summary.DryRun || len(summary.Configured) != 1 || len(summary.Warnings) != 1 {
+		t.Errorf("Unexpected dry run summary: %+v", summary)
+	}
+
+	for _, raw := range []string{"", `{"kind": "List", "items": []}`, "deployment.apps/web restarted\n"} {
+		if _, err := parseApply(raw); err == nil {
+			t.Errorf("Expected %q to be rejected", raw)
+		}
+		if got := formatStructured("apply -f app.yaml", raw); got != raw {
+			t.Errorf("Expected the raw output back for %q, got %q", raw, got)
+		}
+	}
+}
+
+func TestKubectlToolExecutor_StructuredApply(t *testing.T) {
+	worker := newTestWorker(t, func(command string) map[string]interface{} {
+		if command == "kubectl apply -f app.yaml -n shop" {
+			return map[string]interface{}{"stdout": multiObjectApply}
+		}
+		return map[string]interface{}{"error": "unexpected command: " + command}
+	})
+	executor := NewKubectlToolExecutor(worker)
+
+	output, err := executor.Execute(map[string]interface{}{
+		"_tool_name": "kubectl_resources",
+		"operation":  "apply",
+		"resource":   "",
+		"args":       "-f app.yaml -n shop",
+		"structured": true,
+	}, newTestConfig("readwrite"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var summary map[string][]string
+	if err := json.Unmarshal([]byte(output), &summary); err != nil {
+		t.Fatalf("Output is not an apply summary: %v\n%s", err, output)
+	}
+	if len(summary["created"]) != 1 || len(summary["configured"]) != 2 || len(summary["unchanged"]) != 2 {
+		t.Errorf("Unexpected summary: %v", summary)
+	}
+}
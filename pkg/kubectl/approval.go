@@ -0,0 +1,97 @@
+package kubectl
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/Azure/mcp-kubernetes/pkg/config"
+)
+
+// approvalRequest is the body POSTed to --approval-webhook for a command awaiting approval
+type approvalRequest struct {
+	// Command is the full kubectl command, redacted like command output
+	Command  string `json:"command"`
+	Category string `json:"category"`
+	Reason   string `json:"reason,omitempty"`
+	// Tool and Operation are the tool call the command was built from
+	Tool      string `json:"tool,omitempty"`
+	Operation string `json:"operation,omitempty"`
+	// AccessLevel is the server's access level when the command was checked
+	AccessLevel string `json:"access_level"`
+}
+
+// approvalResponse is what the webhook answers; anything but approved: true denies the command
+type approvalResponse struct {
+	Approved bool   `json:"approved"`
+	Reason   string `json:"reason"`
+}
+
+// needsApproval reports whether a command must be approved by the webhook: admin operations
+// and deletes
+func (e *KubectlToolExecutor) needsApproval(command string) bool {
+	if e.determineCommandCategory(command) == "admin" {
+		return true
+	}
+	cmdline, err := parseCommandLine(command)
+	return err != nil || cmdline.verb() == "delete"
+}
+
+// checkApproval asks --approval-webhook whether an admin operation or delete may run. The
+// command runs only if the webhook answers 200 with {"approved": true} within
+// --approval-webhook-timeout; a denial, any other answer, an error or a timeout refuses it.
+func (e *KubectlToolExecutor) checkApproval(command string, params map[string]interface{}, cfg *config.ConfigData) error {
+	if cfg.ApprovalWebhook == "" || !e.needsApproval(command) {
+		return nil
+	}
+
+	reason, _ := params["reason"].(string)
+	tool, _ := params["_tool_name"].(string)
+	operation, _ := params["operation"].(string)
+	body, err := json.Marshal(approvalRequest{
+		Command:     cfg.SecurityConfig.Redact("kubectl " + command),
+		Category:    e.determineCommandCategory(command),
+		Reason:      reason,
+		Tool:        tool,
+		Operation:   operation,
+		AccessLevel: cfg.AccessLevel,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode approval request: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.ApprovalTimeout)*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, cfg.ApprovalWebhook, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("command not approved: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("command not approved: the approval webhook didn't answer within %d seconds", cfg.ApprovalTimeout)
+		}
+		return fmt.Errorf("command not approved: approval webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("command not approved: the approval webhook answered %s", resp.Status)
+	}
+
+	var answer approvalResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 64*1024)).Decode(&answer); err != nil {
+		return fmt.Errorf("command not approved: invalid approval webhook response: %w", err)
+	}
+	if !answer.Approved {
+		if answer.Reason != "" {
+			return fmt.Errorf("command not approved: %s", answer.Reason)
+		}
+		return fmt.Errorf("command not approved by the approval webhook")
+	}
+	return nil
+}
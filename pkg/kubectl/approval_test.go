@@ -0,0 +1,103 @@
+package kubectl
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestKubectlToolExecutor_ApprovalWebhook(t *testing.T) {
+	var approvals []approvalRequest
+	webhook := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		var req approvalRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+		approvals = append(approvals, req)
+		// The fake approver allows deleting web and nothing else
+		if strings.Contains(req.Command, "delete deployment web ") {
+			_, _ = rw.Write([]byte(`{"approved": true}`))
+			return
+		}
+		_, _ = rw.Write([]byte(`{"approved": false, "reason": "change freeze until Monday"}`))
+	}))
+	defer webhook.Close()
+
+	var commands []string
+	worker := newTestWorker(t, func(command string) map[string]interface{} {
+		commands = append(commands, command)
+		return map[string]interface{}{"stdout": "ok\n"}
+	})
+	executor := NewKubectlToolExecutor(worker)
+	cfg := newTestConfig("admin")
+	cfg.ApprovalWebhook = webhook.URL
+
+	run := func(operation, resource, args string) (string, error) {
+		return executor.Execute(map[string]interface{}{
+			"_tool_name": "kubectl_resources",
+			"operation":  operation,
+			"resource":   resource,
+			"args":       args,
+			"reason":     "cleanup",
+		}, cfg)
+	}
+
+	if _, err := run("delete", "deployment", "web -n shop"); err != nil {
+		t.Fatalf("expected the approved delete to run, got %v", err)
+	}
+	if len(commands) != 1 || !strings.HasPrefix(commands[0], "kubectl delete deployment web") {
+		t.Errorf("expected the delete to run, got %v", commands)
+	}
+	if len(approvals) != 1 || approvals[0].Category != "read-write" || approvals[0].Reason != "cleanup" ||
+		approvals[0].Tool != "kubectl_resources" || approvals[0].Operation != "delete" || approvals[0].AccessLevel != "admin" {
+		t.Errorf("unexpected approval request: %+v", approvals)
+	}
+
+	commands = nil
+	_, err := run("delete", "deployment", "api -n shop")
+	if err == nil || !strings.Contains(err.Error(), "change freeze until Monday") {
+		t.Errorf("expected the denied delete to be refused with the webhook's reason, got %v", err)
+	}
+	if _, err := run("drain", "node", "node-1 --ignore-daemonsets"); err == nil {
+		t.Error("expected the denied admin operation to be refused")
+	}
+	if len(commands) != 0 {
+		t.Errorf("expected nothing denied to run, got %v", commands)
+	}
+	if len(approvals) != 3 || approvals[2].Category != "admin" {
+		t.Errorf("expected the drain to be sent for approval as admin, got %+v", approvals)
+	}
+
+	// Other changes and reads don't ask the webhook
+	if _, err := run("get", "pods", "-n shop"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := run("patch", "deployment", `web -n shop -p '{"spec":{"replicas":2}}'`); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if len(approvals) != 3 {
+		t.Errorf("expected only admin operations and deletes to be sent for approval, got %+v", approvals)
+	}
+
+	// A webhook that doesn't answer in time denies the command
+	slow := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(3 * time.Second):
+		}
+	}))
+	defer slow.Close()
+	cfg.ApprovalWebhook = slow.URL
+	cfg.ApprovalTimeout = 1
+	commands = nil
+	if _, err := run("delete", "deployment", "web -n shop"); err == nil || !strings.Contains(err.Error(), "didn't answer") {
+		t.Errorf("expected a timeout to deny the command, got %v", err)
+	}
+	if len(commands) != 0 {
+		t.Errorf("expected nothing to run, got %v", commands)
+	}
+}
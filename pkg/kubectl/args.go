@@ -0,0 +1,136 @@
+package kubectl
+
+import (
+	"strings"
+
+	"github.com/google/shlex"
+)
+
+// shortFlagNames maps kubectl short flags to their long form
+var shortFlagNames = map[string]string{
+	"-n": "--namespace",
+	"-l": "--selector",
+	"-o": "--output",
+	"-c": "--container",
+	"-f": "--filename",
+	"-k": "--kustomize",
+	"-p": "--patch",
+	"-A": "--all-namespaces",
+	"-w": "--watch",
+	"-s": "--server",
+}
+
+// valueFlags lists long flags that consume the following argument when not given as --flag=value
+var valueFlags = map[string]bool{
+	"--namespace":       true,
+	"--selector":        true,
+	"--output":          true,
+	"--container":       true,
+	"--filename":        true,
+	"--kustomize":       true,
+	"--patch":           true,
+	"--field-selector":  true,
+	"--context":         true,
+	"--type":            true,
+	"--image":           true,
+	"--replicas":        true,
+	"--as":              true,
+	"--as-group":        true,
+	"--to-revision":     true,
+	"--types":           true,
+	"--server":          true,
+	"--tail":            true,
+	"--since":           true,
+	"--request-timeout": true,
+}
+
+// commandLine is a parsed view of a kubectl command line
+type commandLine struct {
+	// positionals are the non-flag arguments, starting with the kubectl verb
+	positionals []string
+	// flags maps the long flag name to its values ("" for boolean flags)
+	flags map[string][]string
+	// trailing holds everything after a "--" separator (e.g. the exec command)
+	trailing []string
+}
+
+// parseCommandLine splits a kubectl command (without the leading "kubectl") into
+// positionals and flags, normalizing short flags to their long form
+func parseCommandLine(command string) (*commandLine, error) {
+	parts, err := shlex.Split(command)
+	if err != nil {
+		return nil, err
+	}
+	if len(parts) > 0 && parts[0] == "kubectl" {
+		parts = parts[1:]
+	}
+
+	cl := &commandLine{flags: make(map[string][]string)}
+	for i := 0; i < len(parts); i++ {
+		part := parts[i]
+
+		if part == "--" {
+			cl.trailing = parts[i+1:]
+			break
+		}
+		if !strings.HasPrefix(part, "-") || part == "-" {
+			cl.positionals = append(cl.positionals, part)
+			continue
+		}
+
+		name, value, hasValue := strings.Cut(part, "=")
+		if long, ok := shortFlagNames[name]; ok {
+			name = long
+		} else if !strings.HasPrefix(name, "--") && len(name) > 2 {
+			// Short flag with attached value, e.g. -nkube-system
+			if long, ok := shortFlagNames[name[:2]]; ok {
+				name, value, hasValue = long, name[2:], true
+			}
+		}
+
+		if !hasValue && valueFlags[name] && i+1 < len(parts) {
+			i++
+			value = parts[i]
+		}
+		cl.flags[name] = append(cl.flags[name], value)
+	}
+
+	return cl, nil
+}
+
+// verb returns the kubectl verb, e.g. "get"
+func (c *commandLine) verb() string {
+	if len(c.positionals) == 0 {
+		return ""
+	}
+	return c.positionals[0]
+}
+
+// args returns the positional arguments after the verb
+func (c *commandLine) args() []string {
+	if len(c.positionals) < 2 {
+		return nil
+	}
+	return c.positionals[1:]
+}
+
+// flag returns the last value given for a long flag
+func (c *commandLine) flag(name string) (string, bool) {
+	values, ok := c.flags[name]
+	if !ok || len(values) == 0 {
+		return "", false
+	}
+	return values[len(values)-1], true
+}
+
+// hasFlag reports whether a long flag is present
+func (c *commandLine) hasFlag(name string) bool {
+	_, ok := c.flags[name]
+	return ok
+}
+
+// boolFlag reports whether a boolean long flag is set, treating "--flag" and "--flag=true" as set
+func (c *commandLine) boolFlag(name string) bool {
+	value, ok := c.flag(name)
+	return ok && (value == "" || value == "true")
+}
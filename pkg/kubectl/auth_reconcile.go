@@ -0,0 +1,80 @@
+package kubectl
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Azure/mcp-kubernetes/pkg/config"
+	"github.com/Azure/mcp-kubernetes/pkg/security"
+)
+
+// reconcileKinds are the kinds auth reconcile acts on; it skips anything else in a manifest
+var reconcileKinds = map[string]bool{
+	"Role":               true,
+	"RoleBinding":        true,
+	"ClusterRole":        true,
+	"ClusterRoleBinding": true,
+}
+
+// authReconcile runs `auth reconcile -f -` with an inline manifest of RBAC roles and bindings
+// on stdin. The manifest may only hold the four RBAC kinds, each in an allowed namespace; while
+// namespaces are restricted, ClusterRoles and ClusterRoleBindings are refused as for any other
+// change to cluster-scoped types. The command is an admin operation and goes through the same
+// access, reason, confirmation and approval checks as one given with -f.
+func (e *KubectlToolExecutor) authReconcile(args string, params map[string]interface{}, cfg *config.ConfigData) (string, error) {
+	manifest, _ := params["manifest"].(string)
+	objects, err := manifestObjects(manifest)
+	if err != nil {
+		return "", err
+	}
+	if len(objects) == 0 {
+		return "", fmt.Errorf("auth reconcile requires a manifest with at least one object")
+	}
+	for _, object := range objects {
+		if !reconcileKinds[object.kind] {
+			return "", fmt.Errorf("auth reconcile only reconciles Roles, RoleBindings, ClusterRoles and ClusterRoleBindings, not %s", object.kind)
+		}
+	}
+	if err := validateManifestScope(objects, cfg); err != nil {
+		return "", err
+	}
+
+	cmdline, err := parseCommandLine(args)
+	if err != nil {
+		return "", err
+	}
+	if cmdline.hasFlag("--filename") || cmdline.hasFlag("--kustomize") {
+		return "", fmt.Errorf("-f and -k conflict with manifest, which is passed to auth reconcile on stdin")
+	}
+	imp, err := impersonationFromParams(params)
+	if err != nil {
+		return "", err
+	}
+	command := "auth reconcile -f -"
+	if args = strings.TrimSpace(args); args != "" {
+		command += " " + args
+	}
+	command = injectImpersonationNamespace(imp.apply(command), cfg)
+
+	if err := e.checkAccessLevel(command, cfg); err != nil {
+		return "", denied(err)
+	}
+	if err := e.checkReason(command, params, cfg); err != nil {
+		return "", denied(err)
+	}
+	if err := e.checkAdminConfirmation(command, params, cfg); err != nil {
+		return "", denied(err)
+	}
+	if err := security.NewValidator(cfg.SecurityConfig).ValidateCommand(command, security.CommandTypeKubectl); err != nil {
+		return "", err
+	}
+	if err := e.checkApproval(command, params, cfg); err != nil {
+		return "", denied(err)
+	}
+
+	output, err := e.executor.executeKubectlCommandWithInput(command, manifest, cfg)
+	if err != nil {
+		return "", summarizeFailure(err)
+	}
+	return output, nil
+}
@@ -0,0 +1,85 @@
+package kubectl
+
+import (
+	"strings"
+	"testing"
+)
+
+const reconcileManifest = `apiVersion: rbac.authorization.k8s.io/v1
+kind: Role
+metadata:
+  name: reader
+  namespace: shop
+rules:
+- apiGroups: [""]
+  resources: [pods]
+  verbs: [get, list]
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: RoleBinding
+metadata:
+  name: reader
+  namespace: shop
+roleRef: {apiGroup: rbac.authorization.k8s.io, kind: Role, name: reader}
+subjects:
+- {kind: ServiceAccount, name: ci, namespace: shop}
+`
+
+func TestKubectlToolExecutor_AuthReconcile(t *testing.T) {
+	var commands []string
+	worker := newTestWorker(t, func(command string) map[string]interface{} {
+		commands = append(commands, command)
+		return map[string]interface{}{"stdout": "role.rbac.authorization.k8s.io/reader reconciled\nrolebinding.rbac.authorization.k8s.io/reader reconciled\n"}
+	})
+	executor := NewKubectlToolExecutor(worker)
+
+	reconcile := func(level, args, manifest string) (string, error) {
+		return executor.Execute(map[string]interface{}{
+			"_tool_name": "kubectl_config",
+			"operation":  "auth",
+			"resource":   "reconcile",
+			"args":       args,
+			"manifest":   manifest,
+		}, newTestConfig(level))
+	}
+
+	for _, level := range []string{"readonly", "readwrite"} {
+		if _, err := reconcile(level, "", reconcileManifest); err == nil || !strings.Contains(err.Error(), "admin") {
+			t.Errorf("expected auth reconcile to require admin access at %s, got %v", level, err)
+		}
+		if _, err := reconcile(level, "-f rbac.yaml", ""); err == nil || !strings.Contains(err.Error(), "admin") {
+			t.Errorf("expected auth reconcile -f to require admin access at %s, got %v", level, err)
+		}
+	}
+	if len(commands) != 0 {
+		t.Fatalf("expected nothing to run below admin, got %v", commands)
+	}
+
+	output, err := reconcile("admin", "--remove-extra-subjects", reconcileManifest)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(output, "rolebinding.rbac.authorization.k8s.io/reader reconciled") {
+		t.Errorf("unexpected output: %s", output)
+	}
+	if len(commands) != 1 || commands[0] != "kubectl auth reconcile -f - --remove-extra-subjects" {
+		t.Errorf("commands = %v, want [kubectl auth reconcile -f - --remove-extra-subjects]", commands)
+	}
+
+	if _, err := reconcile("admin", "", "apiVersion: v1\nkind: ConfigMap\nmetadata: {name: settings}\n"); err == nil {
+		t.Error("expected a manifest without RBAC objects to be refused")
+	}
+	if _, err := reconcile("admin", "-f rbac.yaml", reconcileManifest); err == nil {
+		t.Error("expected -f in args to conflict with manifest")
+	}
+
+	// auth can-i stays read-only
+	if _, err := executor.Execute(map[string]interface{}{
+		"_tool_name": "kubectl_config",
+		"operation":  "auth",
+		"resource":   "can-i",
+		"args":       "list pods",
+	}, newTestConfig("readonly")); err != nil {
+		t.Errorf("expected auth can-i to run at readonly, got %v", err)
+	}
+}
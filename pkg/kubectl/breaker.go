@@ -0,0 +1,111 @@
+package kubectl
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// agentPingTimeout bounds the health pings used by the breaker and readiness
+	agentPingTimeout = 5 * time.Second
+	// breakerThreshold is how many requests in a row the agent must fail before the breaker opens
+	breakerThreshold = 3
+	// breakerCooldown is how long an open breaker rejects requests before pinging the agent again
+	breakerCooldown = 30 * time.Second
+)
+
+// agentBreaker stops sending requests to a remote agent that has stopped answering. A request
+// that times out is followed by a ping: if the agent answers, it is alive and the cluster is
+// just slow, so only timeouts the agent doesn't answer count toward opening the breaker.
+type agentBreaker struct {
+	mu       sync.Mutex
+	failures int
+	openedAt time.Time // zero while closed
+}
+
+// allow reports whether a request may be sent. Once the cooldown has passed, an open breaker
+// pings the agent and closes if it answers.
+func (b *agentBreaker) allow(ping func(time.Duration) error) error {
+	b.mu.Lock()
+	openedAt := b.openedAt
+	b.mu.Unlock()
+
+	if openedAt.IsZero() {
+		return nil
+	}
+	if wait := breakerCooldown - time.Since(openedAt); wait > 0 {
+		return fmt.Errorf("remote agent is unavailable after %d unanswered requests; retry in %ds",
+			breakerThreshold, int(wait.Seconds())+1)
+	}
+
+	if err := ping(agentPingTimeout); err != nil {
+		b.mu.Lock()
+		b.openedAt = time.Now()
+		b.mu.Unlock()
+		return fmt.Errorf("remote agent is unavailable: %w", err)
+	}
+	b.succeeded()
+	return nil
+}
+
+// succeeded closes the breaker after the agent answered
+func (b *agentBreaker) succeeded() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures = 0
+	b.openedAt = time.Time{}
+}
+
+// failed records a request the agent didn't answer, opening the breaker at breakerThreshold
+func (b *agentBreaker) failed() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures++
+	if b.failures >= breakerThreshold && b.openedAt.IsZero() {
+		b.openedAt = time.Now()
+	}
+}
+
+// isOpen reports whether requests are currently being rejected
+func (b *agentBreaker) isOpen() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return !b.openedAt.IsZero()
+}
+
+// errRequestTimeout is wrapped by the error for a request that got no response in time
+var errRequestTimeout = errors.New("timeout waiting for response")
+
+// timedOut classifies a request that got no response in time by pinging the agent, and
+// returns the error to report for it
+func (w *Worker) timedOut(timeout int) error {
+	pingTimeout := min(agentPingTimeout, time.Duration(timeout)*time.Second)
+	if err := w.Ping(pingTimeout); err != nil {
+		w.breaker.failed()
+		return fmt.Errorf("%w after %ds: the remote agent is not responding (%v)", errRequestTimeout, timeout, err)
+	}
+	w.breaker.succeeded()
+	return fmt.Errorf("%w after %ds: the remote agent is alive, so the cluster or command is slow", errRequestTimeout, timeout)
+}
+
+// ReadinessHandler serves /readyz, answering 200 when the remote agent answers a ping and
+// 503 when it doesn't. A successful ping also closes the circuit breaker.
+func ReadinessHandler(w *Worker) http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		if w == nil {
+			http.Error(rw, "remote worker is not configured", http.StatusServiceUnavailable)
+			return
+		}
+		if err := w.Ping(agentPingTimeout); err != nil {
+			w.breaker.failed()
+			http.Error(rw, "remote agent is not ready: "+err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		w.breaker.succeeded()
+		rw.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		_, _ = rw.Write([]byte("ok\n"))
+	}
+}
@@ -0,0 +1,134 @@
+package kubectl
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Azure/mcp-kubernetes/pkg/kubectl/ws"
+)
+
+func TestAgentBreaker(t *testing.T) {
+	var b agentBreaker
+	pingOK := func(time.Duration) error { return nil }
+	pingFails := func(time.Duration) error { return errors.New("timeout waiting for ping response") }
+
+	for i := 0; i < breakerThreshold; i++ {
+		if err := b.allow(pingFails); err != nil {
+			t.Fatalf("breaker should stay closed before %d failures, got %v", breakerThreshold, err)
+		}
+		b.failed()
+	}
+	if !b.isOpen() {
+		t.Fatalf("breaker should open after %d failures", breakerThreshold)
+	}
+
+	// Requests are rejected without pinging during the cooldown
+	if err := b.allow(func(time.Duration) error {
+		t.Errorf("no ping expected during the cooldown")
+		return nil
+	}); err == nil || !strings.Contains(err.Error(), "retry in") {
+		t.Errorf("expected rejection during the cooldown, got %v", err)
+	}
+
+	// After the cooldown a failed ping keeps it open, and an answered one closes it
+	b.openedAt = time.Now().Add(-breakerCooldown)
+	if err := b.allow(pingFails); err == nil || !b.isOpen() {
+		t.Errorf("expected the breaker to stay open when the ping fails, got %v", err)
+	}
+	b.openedAt = time.Now().Add(-breakerCooldown)
+	if err := b.allow(pingOK); err != nil || b.isOpen() {
+		t.Errorf("expected the breaker to close when the ping is answered, got %v", err)
+	}
+}
+
+// newPingOnlyWorker returns a worker whose agent answers pings but never answers commands
+func newPingOnlyWorker(t *testing.T) *Worker {
+	t.Helper()
+
+	var w *Worker
+	consumer := &fakeConsumer{}
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		var msg struct {
+			Payload struct {
+				Id     int                    `json:"Id"`
+				Result map[string]interface{} `json:"result"`
+			}
+		}
+		if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if ping, _ := msg.Payload.Result["ping"].(bool); ping {
+			payload, _ := json.Marshal(map[string]interface{}{
+				"Id":     msg.Payload.Id,
+				"result": map[string]interface{}{"stdout": "Client Version: v1.30.0"},
+			})
+			w.handleMessage(r.Context(), consumer, &ws.Msg{Payload: payload})
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	w, err := New(&Config{Mode: ModeAgent, Token: "test-token", Location: "test-host", UnsubscribeEndpoint: srv.URL, Timeout: 1})
+	if err != nil {
+		t.Fatalf("failed to create worker: %v", err)
+	}
+	return w
+}
+
+func TestKubectlExecutor_TimeoutWithLiveAgent(t *testing.T) {
+	worker := newPingOnlyWorker(t)
+	executor := NewExecutor(worker)
+
+	_, err := executor.executeKubectlCommandOnHostWithin("get pods -A", "", 1, newTestConfig("readonly"))
+	if err == nil || !strings.Contains(err.Error(), "the remote agent is alive") {
+		t.Fatalf("expected a slow-cluster timeout, got %v", err)
+	}
+	if worker.breaker.failures != 0 {
+		t.Errorf("a timeout the agent answers pings for should not count against it, got %d failures", worker.breaker.failures)
+	}
+}
+
+func TestKubectlToolExecutor_DescribeTimeout(t *testing.T) {
+	executor := NewKubectlToolExecutor(newPingOnlyWorker(t))
+
+	_, err := executor.Execute(map[string]interface{}{
+		"_tool_name": "kubectl_resources",
+		"operation":  "describe",
+		"resource":   "services",
+		"args":       "big -n shop",
+		"timeout":    float64(1),
+	}, newTestConfig("readonly"))
+	if err == nil || !strings.HasPrefix(err.Error(), "describe timed out; try get -o yaml or increase timeout") {
+		t.Fatalf("expected a describe-specific timeout error, got %v", err)
+	}
+}
+
+func TestReadinessHandler(t *testing.T) {
+	ready := httptest.NewRecorder()
+	ReadinessHandler(newPingOnlyWorker(t))(ready, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	if ready.Code != http.StatusOK {
+		t.Errorf("expected 200 when the agent answers, got %d: %s", ready.Code, ready.Body)
+	}
+
+	// An agent that can't be reached is not ready
+	unreachable, err := New(&Config{Mode: ModeAgent, Token: "test-token", Location: "test-host", UnsubscribeEndpoint: "http://127.0.0.1:1"})
+	if err != nil {
+		t.Fatalf("failed to create worker: %v", err)
+	}
+	notReady := httptest.NewRecorder()
+	ReadinessHandler(unreachable)(notReady, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	if notReady.Code != http.StatusServiceUnavailable || !strings.Contains(notReady.Body.String(), "not ready") {
+		t.Errorf("expected 503 when the agent is unreachable, got %d: %s", notReady.Code, notReady.Body)
+	}
+
+	unconfigured := httptest.NewRecorder()
+	ReadinessHandler(nil)(unconfigured, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	if unconfigured.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503 without a worker, got %d", unconfigured.Code)
+	}
+}
@@ -0,0 +1,174 @@
+package kubectl
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Azure/mcp-kubernetes/pkg/config"
+	"github.com/Azure/mcp-kubernetes/pkg/security"
+)
+
+// changeCauseAnnotation is the annotation rollout history shows as a revision's change-cause
+const changeCauseAnnotation = "kubernetes.io/change-cause"
+
+// changeCauseVerbs are the commands whose resources are annotated with --record-change-cause
+var changeCauseVerbs = map[string]bool{
+	"create": true,
+	"apply":  true,
+	"scale":  true,
+	"set":    true,
+	"patch":  true,
+}
+
+// reasonExemptVerbs are the commands that need more than read access but don't change cluster
+// objects, so --require-reason doesn't apply to them
+var reasonExemptVerbs = map[string]bool{
+	"exec":         true,
+	"cp":           true,
+	"attach":       true,
+	"port-forward": true,
+	"proxy":        true,
+	"config":       true,
+}
+
+// createSubtypes are the create subcommands that take a type before the name, e.g. create secret generic NAME
+var createSubtypes = map[string]bool{
+	"secret":  true,
+	"service": true,
+}
+
+// changeCauseTargetFlags are the flags that also select the changed resources for the annotate
+// that records the change-cause
+var changeCauseTargetFlags = []string{
+	"--namespace", "--selector", "--filename", "--kustomize", "--recursive", "--all",
+	"--context", "--server", "--as", "--as-group",
+}
+
+// reasonParam returns the caller's reason for a change, or "" when none was given
+func reasonParam(params map[string]interface{}) string {
+	reason, _ := params["reason"].(string)
+	return strings.TrimSpace(reason)
+}
+
+// isDryRun reports whether a command only previews its change
+func isDryRun(cmdline *commandLine) bool {
+	value, ok := cmdline.flag("--dry-run")
+	return ok && value != "none"
+}
+
+// checkReason refuses commands that change the cluster without a reason when --require-reason
+// is set. Dry runs and commands that don't change objects, such as exec, are not affected.
+func (e *KubectlToolExecutor) checkReason(command string, params map[string]interface{}, cfg *config.ConfigData) error {
+	if !cfg.RequireReason || e.determineCommandCategory(command) == "read-only" {
+		return nil
+	}
+	cmdline, err := parseCommandLine(command)
+	if err != nil {
+		return err
+	}
+	if reasonExemptVerbs[cmdline.verb()] || isDryRun(cmdline) || reasonParam(params) != "" {
+		return nil
+	}
+
+	return fmt.Errorf("%s changes the cluster and this server requires a reason for every change; pass reason to say why", cmdline.verb())
+}
+
+// changeCauseTargets returns the resource types and names a command changed, without the
+// subcommands and key=value arguments of create and set
+func changeCauseTargets(cmdline *commandLine) []string {
+	args := cmdline.args()
+	switch cmdline.verb() {
+	case "create":
+		// create -f has no positional targets, and create token doesn't store an object
+		if len(args) < 2 || args[0] == "token" {
+			return nil
+		}
+		name := args[1]
+		if createSubtypes[args[0]] && len(args) > 2 {
+			name = args[2]
+		}
+		return []string{args[0], name}
+	case "set":
+		if len(args) == 0 {
+			return nil
+		}
+		var targets []string
+		for _, arg := range args[1:] {
+			if !strings.Contains(arg, "=") {
+				targets = append(targets, arg)
+			}
+		}
+		// set serviceaccount ends with the service account's name
+		if (args[0] == "serviceaccount" || args[0] == "sa") && len(targets) > 0 {
+			targets = targets[:len(targets)-1]
+		}
+		return targets
+	}
+	return args
+}
+
+// changeCauseCommand builds the annotate command that records a change-cause on the resources
+// a command changed: the caller's reason, or the change itself when no reason was given
+func changeCauseCommand(command, reason string) (string, error) {
+	cmdline, err := parseCommandLine(command)
+	if err != nil {
+		return "", err
+	}
+	targets := changeCauseTargets(cmdline)
+	if len(targets) == 0 && !cmdline.hasFlag("--filename") && !cmdline.hasFlag("--kustomize") {
+		return "", fmt.Errorf("no resources to annotate for %s", cmdline.verb())
+	}
+	if reason == "" {
+		reason = strings.Join(append([]string{cmdline.verb()}, targets...), " ") + " via mcp-kubernetes"
+	}
+
+	parts := append([]string{"annotate"}, targets...)
+	for _, name := range changeCauseTargetFlags {
+		for _, value := range cmdline.flags[name] {
+			switch {
+			case value == "":
+				parts = append(parts, name)
+			case name == "--namespace":
+				parts = append(parts, name+"="+value)
+			default:
+				parts = append(parts, name+"="+shellQuote(value))
+			}
+		}
+	}
+	parts = append(parts, changeCauseAnnotation+"="+shellQuote(reason), "--overwrite")
+	return strings.Join(parts, " "), nil
+}
+
+// recordChangeCause annotates the resources of a successful create, apply, scale, set or patch
+// with its change-cause when --record-change-cause is set. kubectl has no flag to add an
+// annotation on these verbs, so this is a separate annotate, subject to the usual checks. The
+// change has already been made, so a failure to record it is noted in the output rather than
+// failing the call.
+func (e *KubectlToolExecutor) recordChangeCause(command, output string, params map[string]interface{}, cfg *config.ConfigData) string {
+	if !cfg.RecordChangeCause {
+		return output
+	}
+	cmdline, err := parseCommandLine(command)
+	if err != nil || !changeCauseVerbs[cmdline.verb()] || isDryRun(cmdline) {
+		return output
+	}
+
+	annotate, err := changeCauseCommand(command, reasonParam(params))
+	if err == nil {
+		err = e.checkAccessLevel(annotate, cfg)
+	}
+	if err == nil {
+		err = security.NewValidator(cfg.SecurityConfig).ValidateCommand(annotate, security.CommandTypeKubectl)
+	}
+	if err == nil && hasManifest(params) {
+		// The objects were read from stdin, and so is the annotate
+		manifest, _ := params["manifest"].(string)
+		_, err = e.executor.executeKubectlCommandWithInput(annotate, manifest, cfg)
+	} else if err == nil {
+		_, err = e.runCommand(annotate, cfg)
+	}
+	if err != nil {
+		return fmt.Sprintf("%s\nChange-cause not recorded: %v\n", strings.TrimRight(output, "\n"), err)
+	}
+	return output
+}
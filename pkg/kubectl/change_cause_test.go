@@ -0,0 +1,194 @@
+package kubectl
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Azure/mcp-kubernetes/pkg/security"
+)
+
+func TestKubectlToolExecutor_RecordChangeCause(t *testing.T) {
+	var commands []string
+	worker := newTestWorker(t, func(command string) map[string]interface{} {
+		commands = append(commands, command)
+		switch command {
+		case "kubectl scale deployment web -n shop --replicas=3":
+			return map[string]interface{}{"stdout": "deployment.apps/web scaled\n"}
+		case "kubectl annotate deployment web --namespace=shop kubernetes.io/change-cause='traffic spike' --overwrite",
+			"kubectl annotate deployment web --namespace=shop kubernetes.io/change-cause='scale deployment web via mcp-kubernetes' --overwrite":
+			return map[string]interface{}{"stdout": "deployment.apps/web annotated\n"}
+		}
+		return map[string]interface{}{"error": "unexpected command: " + command}
+	})
+	executor := NewKubectlToolExecutor(worker)
+	cfg := newTestConfig("readwrite")
+	cfg.RecordChangeCause = true
+
+	tests := []struct {
+		name     string
+		reason   string
+		annotate string
+	}{
+		{
+			name:     "caller reason",
+			reason:   "traffic spike",
+			annotate: "kubectl annotate deployment web --namespace=shop kubernetes.io/change-cause='traffic spike' --overwrite",
+		},
+		{
+			name:     "no reason",
+			annotate: "kubectl annotate deployment web --namespace=shop kubernetes.io/change-cause='scale deployment web via mcp-kubernetes' --overwrite",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			commands = nil
+			params := map[string]interface{}{
+				"_tool_name": "kubectl_workloads",
+				"operation":  "scale",
+				"resource":   "deployment",
+				"args":       "web -n shop --replicas=3",
+			}
+			if tt.reason != "" {
+				params["reason"] = tt.reason
+			}
+			output, err := executor.Execute(params, cfg)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if output != "deployment.apps/web scaled\n" {
+				t.Errorf("Unexpected output: %q", output)
+			}
+			if len(commands) != 2 || commands[1] != tt.annotate {
+				t.Errorf("Expected the scale then %q, got %q", tt.annotate, commands)
+			}
+		})
+	}
+
+	// A failed annotate leaves the change in place and says so
+	commands = nil
+	output, err := executor.Execute(map[string]interface{}{
+		"_tool_name": "kubectl_workloads",
+		"operation":  "scale",
+		"resource":   "deployment",
+		"args":       "web -n shop --replicas=3",
+		"reason":     "rejected by the fake worker",
+	}, cfg)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(output, "deployment.apps/web scaled") || !strings.Contains(output, "Change-cause not recorded") {
+		t.Errorf("Expected the scale output with a note, got %q", output)
+	}
+}
+
+func TestKubectlToolExecutor_RequireReason(t *testing.T) {
+	worker := newTestWorker(t, func(command string) map[string]interface{} {
+		return map[string]interface{}{"stdout": "ok\n"}
+	})
+	executor := NewKubectlToolExecutor(worker)
+	cfg := newTestConfig("readwrite")
+	cfg.RequireReason = true
+
+	tests := []struct {
+		name    string
+		params  map[string]interface{}
+		refused bool
+	}{
+		{
+			name:    "change without reason",
+			params:  map[string]interface{}{"_tool_name": "kubectl_resources", "operation": "delete", "resource": "pod", "args": "web -n shop"},
+			refused: true,
+		},
+		{
+			name:    "blank reason",
+			params:  map[string]interface{}{"_tool_name": "kubectl_metadata", "operation": "label", "resource": "pod", "args": "web tier=gold -n shop", "reason": "  "},
+			refused: true,
+		},
+		{
+			name:   "change with reason",
+			params: map[string]interface{}{"_tool_name": "kubectl_resources", "operation": "delete", "resource": "pod", "args": "web -n shop", "reason": "stuck pod"},
+		},
+		{
+			name:   "read",
+			params: map[string]interface{}{"_tool_name": "kubectl_resources", "operation": "get", "resource": "pods", "args": "-n shop"},
+		},
+		{
+			name:   "dry run",
+			params: map[string]interface{}{"_tool_name": "kubectl_resources", "operation": "apply", "resource": "", "args": "-f app.yaml --dry-run=server"},
+		},
+		{
+			name:   "exec",
+			params: map[string]interface{}{"_tool_name": "kubectl_diagnostics", "operation": "exec", "resource": "", "args": "web -n shop -- date"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := executor.Execute(tt.params, cfg)
+			if !tt.refused {
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+				return
+			}
+			var validationErr *security.ValidationError
+			if !errors.As(err, &validationErr) || !strings.Contains(err.Error(), "requires a reason") {
+				t.Errorf("Expected a refusal asking for a reason, got %v", err)
+			}
+		})
+	}
+}
+
+func TestChangeCauseCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		command  string
+		expected string
+		err      string
+	}{
+		{
+			name:     "apply file",
+			command:  "apply -f app.yaml -n shop",
+			expected: "annotate --namespace=shop --filename='app.yaml' kubernetes.io/change-cause='apply via mcp-kubernetes' --overwrite",
+		},
+		{
+			name:     "create secret",
+			command:  "create secret generic db --from-literal=password=hunter2",
+			expected: "annotate secret db kubernetes.io/change-cause='create secret db via mcp-kubernetes' --overwrite",
+		},
+		{
+			name:     "set image",
+			command:  "set image deployment/web web=nginx:1.27 -n shop",
+			expected: "annotate deployment/web --namespace=shop kubernetes.io/change-cause='set deployment/web via mcp-kubernetes' --overwrite",
+		},
+		{
+			name:     "set serviceaccount",
+			command:  "set serviceaccount deployment web builder",
+			expected: "annotate deployment web kubernetes.io/change-cause='set deployment web via mcp-kubernetes' --overwrite",
+		},
+		{
+			name:     "patch by selector",
+			command:  "patch deployment -l app=web -p '{\"spec\":{}}'",
+			expected: "annotate deployment --selector='app=web' kubernetes.io/change-cause='patch deployment via mcp-kubernetes' --overwrite",
+		},
+		{
+			name:    "create token",
+			command: "create token builder",
+			err:     "no resources to annotate",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := changeCauseCommand(tt.command, "")
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Errorf("Expected error containing %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil || got != tt.expected {
+				t.Errorf("Expected %q, got %q (%v)", tt.expected, got, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package kubectl
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"fmt"
+	"io"
+)
+
+const (
+	// payloadEncodingGzip marks a result whose stdout and stderr are base64-encoded gzip data
+	payloadEncodingGzip = "gzip"
+	// maxDecompressedPayload bounds what a compressed stdout or stderr may expand to
+	maxDecompressedPayload = 32 << 20
+)
+
+// decodeResult returns a remote result with its compressed output expanded. Requests tell the
+// agent it may gzip large output with accept_encoding; a result it compressed carries
+// "encoding": "gzip". A result in an unknown encoding, or one that can't be decoded, is
+// turned into an error result so the waiting request fails instead of returning garbage.
+func decodeResult(result map[string]interface{}) map[string]interface{} {
+	encoding, _ := result["encoding"].(string)
+	if encoding == "" {
+		return result
+	}
+
+	decoded := make(map[string]interface{}, len(result))
+	for key, value := range result {
+		if key != "encoding" {
+			decoded[key] = value
+		}
+	}
+	if encoding != payloadEncodingGzip {
+		decoded["error"] = fmt.Sprintf("unsupported result encoding '%s'", encoding)
+		return decoded
+	}
+
+	for _, key := range []string{"stdout", "stderr"} {
+		value, ok := result[key].(string)
+		if !ok || value == "" {
+			continue
+		}
+		data, err := gunzipBase64(value)
+		if err != nil {
+			decoded["error"] = fmt.Sprintf("failed to decode gzipped %s: %v", key, err)
+			return decoded
+		}
+		decoded[key] = data
+	}
+	return decoded
+}
+
+// gunzipBase64 decodes base64 gzip data, refusing output over maxDecompressedPayload
+func gunzipBase64(value string) (string, error) {
+	compressed, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return "", err
+	}
+	reader, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return "", err
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(io.LimitReader(reader, maxDecompressedPayload+1))
+	if err != nil {
+		return "", err
+	}
+	if len(data) > maxDecompressedPayload {
+		return "", fmt.Errorf("output expands to more than %d bytes", maxDecompressedPayload)
+	}
+	return string(data), nil
+}
@@ -0,0 +1,93 @@
+package kubectl
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+func gzipBase64(t *testing.T, data string) string {
+	t.Helper()
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write([]byte(data)); err != nil {
+		t.Fatalf("failed to compress: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("failed to compress: %v", err)
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes())
+}
+
+func TestKubectlToolExecutor_GzippedResult(t *testing.T) {
+	logs := strings.Repeat("GET /healthz 200\n", 1000)
+	worker := newTestWorker(t, func(command string) map[string]interface{} {
+		if command == "kubectl logs web -n default" {
+			return map[string]interface{}{"stdout": gzipBase64(t, logs), "encoding": "gzip"}
+		}
+		return map[string]interface{}{"error": "unexpected command: " + command}
+	})
+	executor := NewKubectlToolExecutor(worker)
+
+	result, err := executor.Execute(map[string]interface{}{
+		"_tool_name": "kubectl_diagnostics",
+		"operation":  "logs",
+		"resource":   "",
+		"args":       "web -n default",
+	}, newTestConfig("readonly"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result != logs {
+		t.Errorf("Expected the decompressed logs, got %d bytes starting %q", len(result), result[:min(len(result), 40)])
+	}
+}
+
+func TestDecodeResult(t *testing.T) {
+	plain := map[string]interface{}{"stdout": "pod/web\n"}
+	if got := decodeResult(plain); got["stdout"] != "pod/web\n" {
+		t.Errorf("Expected an unencoded result unchanged, got %v", got)
+	}
+
+	got := decodeResult(map[string]interface{}{
+		"stdout":    gzipBase64(t, "out"),
+		"stderr":    gzipBase64(t, "warning"),
+		"exit_code": float64(0),
+		"encoding":  "gzip",
+	})
+	if got["stdout"] != "out" || got["stderr"] != "warning" || got["exit_code"] != float64(0) || got["encoding"] != nil {
+		t.Errorf("Unexpected decoded result: %v", got)
+	}
+
+	tests := []struct {
+		name     string
+		result   map[string]interface{}
+		expected string
+	}{
+		{
+			name:     "unknown encoding",
+			result:   map[string]interface{}{"stdout": "x", "encoding": "br"},
+			expected: "unsupported result encoding 'br'",
+		},
+		{
+			name:     "not base64",
+			result:   map[string]interface{}{"stdout": "%%%", "encoding": "gzip"},
+			expected: "failed to decode gzipped stdout",
+		},
+		{
+			name:     "zip bomb",
+			result:   map[string]interface{}{"stdout": gzipBase64(t, strings.Repeat("0", maxDecompressedPayload+1)), "encoding": "gzip"},
+			expected: "expands to more than",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := parseCommandResult(decodeResult(tt.result))
+			if res.Err == nil || !strings.Contains(res.Err.Error(), tt.expected) {
+				t.Errorf("Expected error containing %q, got %v", tt.expected, res.Err)
+			}
+		})
+	}
+}
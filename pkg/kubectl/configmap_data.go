@@ -0,0 +1,91 @@
+package kubectl
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Azure/mcp-kubernetes/pkg/config"
+)
+
+// configMapData is the decoded content of one ConfigMap
+type configMapData struct {
+	Name      string            `json:"name"`
+	Namespace string            `json:"namespace"`
+	Data      map[string]string `json:"data"`
+	// BinaryData maps each binaryData key to the length in bytes of its decoded value; the
+	// bytes themselves are not returned
+	BinaryData map[string]int `json:"binary_data"`
+}
+
+// configMapData returns the data and binaryData keys of one ConfigMap as JSON, read as the
+// identity given by the as/as_group parameters. It is refused when --deny-resources covers
+// configmaps.
+func (e *KubectlToolExecutor) configMapData(resource, args string, params map[string]interface{}, cfg *config.ConfigData) (string, error) {
+	cmdline, err := parseCommandLine(args)
+	if err != nil {
+		return "", err
+	}
+
+	name := ""
+	if kind, objName, ok := strings.Cut(resource, "/"); ok {
+		resource, name = kind, objName
+	}
+	names := len(cmdline.positionals)
+	if name != "" {
+		names++
+	} else if names == 1 {
+		name = cmdline.positionals[0]
+	}
+	if described, ok := cfg.SecurityConfig.Resources().Lookup(resource); !ok || described.Name != "configmaps" || described.Group != "" || names != 1 {
+		return "", fmt.Errorf("configmap-data requires resource configmap and exactly one name")
+	}
+	if cmdline.hasFlag("--output") || cmdline.hasFlag("--selector") || cmdline.hasFlag("--all-namespaces") {
+		return "", fmt.Errorf("configmap-data does not accept --output, --selector or --all-namespaces")
+	}
+	if cfg.SecurityConfig.IsResourceDenied("configmaps") {
+		return "", fmt.Errorf("configmap-data is not available: configmaps are denied by security configuration")
+	}
+
+	if !objectName.MatchString(name) {
+		return "", fmt.Errorf("invalid configmap name '%s'", name)
+	}
+	namespace, err := namespaceFlag(cmdline, "")
+	if err != nil {
+		return "", err
+	}
+
+	imp, err := impersonationFromParams(params)
+	if err != nil {
+		return "", err
+	}
+	command := "get configmap " + name + " -o json"
+	if namespace != "" {
+		command += " -n " + namespace
+	}
+	command = injectImpersonationNamespace(imp.apply(command), cfg)
+	obj, err := e.getObject(command, cfg)
+	if err != nil {
+		return "", err
+	}
+
+	namespace, _ = objectNamespacedName(obj)
+	result := configMapData{Name: name, Namespace: namespace, Data: nestedStringMap(obj, "data"), BinaryData: map[string]int{}}
+	if result.Data == nil {
+		result.Data = map[string]string{}
+	}
+	for key, value := range nestedStringMap(obj, "binaryData") {
+		decoded, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return "", fmt.Errorf("failed to decode binaryData key '%s': %w", key, err)
+		}
+		result.BinaryData[key] = len(decoded)
+	}
+
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode configmap data: %w", err)
+	}
+	return string(data), nil
+}
@@ -0,0 +1,95 @@
+package kubectl
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestKubectlToolExecutor_ConfigMapData(t *testing.T) {
+	var commands []string
+	worker := newTestWorker(t, func(command string) map[string]interface{} {
+		commands = append(commands, command)
+		if command != "kubectl get configmap app-config -o json -n shop" {
+			return map[string]interface{}{"error": "unexpected command: " + command}
+		}
+		return map[string]interface{}{"stdout": `{
+  "apiVersion": "v1",
+  "kind": "ConfigMap",
+  "metadata": {"name": "app-config", "namespace": "shop"},
+  "data": {"mode": "fast", "app.properties": "debug=true\nport=8080\n"},
+  "binaryData": {"logo.png": "iVBORw0KGgo="}
+}`}
+	})
+	executor := NewKubectlToolExecutor(worker)
+
+	result, err := executor.Execute(map[string]interface{}{
+		"_tool_name": "kubectl_resources",
+		"operation":  "configmap-data",
+		"resource":   "configmap",
+		"args":       "app-config -n shop",
+	}, newTestConfig("readonly"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v (commands: %v)", err, commands)
+	}
+
+	var got configMapData
+	if err := json.Unmarshal([]byte(result), &got); err != nil {
+		t.Fatalf("expected JSON, got %q: %v", result, err)
+	}
+	want := configMapData{
+		Name:       "app-config",
+		Namespace:  "shop",
+		Data:       map[string]string{"mode": "fast", "app.properties": "debug=true\nport=8080\n"},
+		BinaryData: map[string]int{"logo.png": 8},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+	if strings.Contains(result, "iVBORw0KGgo=") {
+		t.Errorf("binaryData bytes should not be returned:\n%s", result)
+	}
+}
+
+func TestKubectlToolExecutor_ConfigMapDataRejected(t *testing.T) {
+	tests := []struct {
+		name     string
+		resource string
+		args     string
+		denied   string
+		wantErr  string
+	}{
+		{name: "not a configmap", resource: "secret", args: "db-creds -n shop", wantErr: "requires resource configmap"},
+		{name: "no name", resource: "configmap", args: "-n shop", wantErr: "exactly one name"},
+		{name: "several names", resource: "configmap", args: "a b -n shop", wantErr: "exactly one name"},
+		{name: "selector", resource: "configmap", args: "app-config -l app=web", wantErr: "does not accept"},
+		{name: "namespace carrying flags", resource: "configmap", args: "app-config -n 'shop --as=admin'", wantErr: "invalid namespace"},
+		{name: "name carrying flags", resource: "configmap", args: "'app-config --as=admin' -n shop", wantErr: "invalid configmap name"},
+		{name: "denied", resource: "cm", args: "app-config -n shop", denied: "configmaps", wantErr: "denied by security configuration"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			worker := newTestWorker(t, func(command string) map[string]interface{} {
+				t.Errorf("unexpected command: %s", command)
+				return map[string]interface{}{"error": "unexpected command"}
+			})
+			executor := NewKubectlToolExecutor(worker)
+			cfg := newTestConfig("readonly")
+			if tt.denied != "" {
+				cfg.SecurityConfig.SetDeniedResources(tt.denied)
+			}
+
+			_, err := executor.Execute(map[string]interface{}{
+				"_tool_name": "kubectl_resources",
+				"operation":  "configmap-data",
+				"resource":   tt.resource,
+				"args":       tt.args,
+			}, cfg)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
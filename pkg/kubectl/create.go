@@ -0,0 +1,132 @@
+package kubectl
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// createDataKey matches the keys kubectl accepts in configmap and secret data
+var createDataKey = regexp.MustCompile(`^[-._a-zA-Z0-9]+$`)
+
+// createKinds maps the resource types create accepts structured parameters for to their kind
+var createKinds = map[string]string{
+	"configmap":  "configmap",
+	"configmaps": "configmap",
+	"cm":         "configmap",
+	"secret":     "secret",
+	"secrets":    "secret",
+	"job":        "job",
+	"jobs":       "job",
+}
+
+// applyCreateParams adds the structured create parameters to a create configmap, secret or
+// job command. literals and files become --from-literal entries, with the file contents sent
+// inline since the remote agent can't read local paths; image becomes --image for a job. A
+// secret given data is created as a generic secret.
+func applyCreateParams(command string, params map[string]interface{}) (string, error) {
+	literals, hasLiterals := params["literals"]
+	files, hasFiles := params["files"]
+	image, _ := params["image"].(string)
+	image = strings.TrimSpace(image)
+	if (!hasLiterals || literals == nil) && (!hasFiles || files == nil) && image == "" {
+		return command, nil
+	}
+
+	cmdline, err := parseCommandLine(command)
+	if err != nil {
+		return "", err
+	}
+	if cmdline.verb() != "create" || len(cmdline.args()) == 0 {
+		return "", fmt.Errorf("literals, files and image are only supported for create configmap, secret and job")
+	}
+	resourceType := cmdline.args()[0]
+	kind, ok := createKinds[strings.ToLower(resourceType)]
+	if !ok {
+		return "", fmt.Errorf("literals, files and image are only supported for create configmap, secret and job, not %s", resourceType)
+	}
+	if len(cmdline.args()) < 2 {
+		return "", fmt.Errorf("create %s requires a name in args", kind)
+	}
+
+	if kind == "job" {
+		if literals != nil || files != nil {
+			return "", fmt.Errorf("literals and files are only supported for configmaps and secrets")
+		}
+		if image == "" {
+			return "", fmt.Errorf("create job requires image")
+		}
+		if cmdline.hasFlag("--image") || cmdline.hasFlag("--from") {
+			return "", fmt.Errorf("image conflicts with --image or --from in args")
+		}
+		return insertFlags(command, []string{"--image=" + shellQuote(image)}), nil
+	}
+
+	if image != "" {
+		return "", fmt.Errorf("image is only supported for jobs")
+	}
+	data, err := createData(literals, files)
+	if err != nil {
+		return "", err
+	}
+	if len(data) == 0 {
+		return "", fmt.Errorf("literals or files must hold at least one entry")
+	}
+
+	if kind == "secret" {
+		if cmdline.hasFlag("--type") {
+			return "", fmt.Errorf("literals and files create generic secrets; --type is not supported with them")
+		}
+		switch subcommand := cmdline.args()[1:]; {
+		case len(subcommand) > 0 && subcommand[0] == "generic":
+		case len(subcommand) > 0 && (subcommand[0] == "tls" || subcommand[0] == "docker-registry"):
+			return "", fmt.Errorf("literals and files are only supported for generic secrets, not %s", subcommand[0])
+		default:
+			command = strings.Replace(command, "create "+resourceType+" ", "create "+resourceType+" generic ", 1)
+		}
+	}
+
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	flags := make([]string, 0, len(keys))
+	for _, key := range keys {
+		flags = append(flags, "--from-literal="+shellQuote(key+"="+data[key]))
+	}
+	return insertFlags(command, flags), nil
+}
+
+// createData merges the literals and files maps into configmap or secret data, rejecting
+// invalid and duplicate keys
+func createData(literals, files interface{}) (map[string]string, error) {
+	data := make(map[string]string)
+	for _, source := range []struct {
+		name  string
+		value interface{}
+	}{{"literals", literals}, {"files", files}} {
+		if source.value == nil {
+			continue
+		}
+		entries, ok := source.value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s must be an object mapping keys to string values", source.name)
+		}
+		for key, value := range entries {
+			text, ok := value.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s entry '%s' must be a string", source.name, key)
+			}
+			if !createDataKey.MatchString(key) {
+				return nil, fmt.Errorf("invalid data key '%s' in %s", key, source.name)
+			}
+			if _, dup := data[key]; dup {
+				return nil, fmt.Errorf("data key '%s' is given more than once", key)
+			}
+			data[key] = text
+		}
+	}
+	return data, nil
+}
@@ -0,0 +1,141 @@
+package kubectl
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestKubectlToolExecutor_CreateParams(t *testing.T) {
+	tests := []struct {
+		name     string
+		resource string
+		args     string
+		params   map[string]interface{}
+		expected string
+		errMsg   string
+	}{
+		{
+			name:     "configmap from literal",
+			resource: "configmap",
+			args:     "app-config -n shop",
+			params: map[string]interface{}{
+				"literals": map[string]interface{}{"mode": "production", "greeting": "it's fine"},
+				"files":    map[string]interface{}{"app.properties": "debug=false\nport=8080\n"},
+			},
+			expected: "kubectl create configmap app-config -n shop --from-literal='app.properties=debug=false\nport=8080\n' " +
+				`--from-literal='greeting=it'"'"'s fine' --from-literal='mode=production'`,
+		},
+		{
+			name:     "job from image",
+			resource: "job",
+			args:     "migrate -n shop -- ./migrate.sh --all",
+			params:   map[string]interface{}{"image": "registry.example.com/shop/app:1.2"},
+			expected: "kubectl create job migrate -n shop --image='registry.example.com/shop/app:1.2' -- ./migrate.sh --all",
+		},
+		{
+			name:     "secret is created as generic",
+			resource: "secret",
+			args:     "db-creds -n shop",
+			params:   map[string]interface{}{"literals": map[string]interface{}{"password": "s3cret"}},
+			expected: "kubectl create secret generic db-creds -n shop --from-literal='password=s3cret'",
+		},
+		{
+			name:     "tls secret rejects literals",
+			resource: "secret",
+			args:     "tls web-tls -n shop",
+			params:   map[string]interface{}{"literals": map[string]interface{}{"tls.crt": "x"}},
+			errMsg:   "only supported for generic secrets",
+		},
+		{
+			name:     "job requires image",
+			resource: "job",
+			args:     "migrate -n shop",
+			params:   map[string]interface{}{"literals": map[string]interface{}{"a": "b"}},
+			errMsg:   "only supported for configmaps and secrets",
+		},
+		{
+			name:     "invalid key",
+			resource: "configmap",
+			args:     "app-config -n shop",
+			params:   map[string]interface{}{"literals": map[string]interface{}{"bad key": "x"}},
+			errMsg:   "invalid data key 'bad key'",
+		},
+		{
+			name:     "duplicate key",
+			resource: "configmap",
+			args:     "app-config -n shop",
+			params: map[string]interface{}{
+				"literals": map[string]interface{}{"app.properties": "x"},
+				"files":    map[string]interface{}{"app.properties": "y"},
+			},
+			errMsg: "given more than once",
+		},
+		{
+			name:     "other resources",
+			resource: "deployment",
+			args:     "web -n shop",
+			params:   map[string]interface{}{"image": "nginx"},
+			errMsg:   "not deployment",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var commands []string
+			worker := newTestWorker(t, func(command string) map[string]interface{} {
+				commands = append(commands, command)
+				return map[string]interface{}{"stdout": "created"}
+			})
+			executor := NewKubectlToolExecutor(worker)
+
+			params := map[string]interface{}{
+				"_tool_name": "kubectl_resources",
+				"operation":  "create",
+				"resource":   tt.resource,
+				"args":       tt.args,
+			}
+			for k, v := range tt.params {
+				params[k] = v
+			}
+
+			_, err := executor.Execute(params, newTestConfig("readwrite"))
+			if tt.errMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+					t.Fatalf("expected error containing %q, got %v", tt.errMsg, err)
+				}
+				if len(commands) != 0 {
+					t.Errorf("rejected create should not run, got %v", commands)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(commands) != 1 || commands[0] != tt.expected {
+				t.Errorf("commands = %q, want [%q]", commands, tt.expected)
+			}
+		})
+	}
+}
+
+func TestKubectlToolExecutor_CreateDeniedSecret(t *testing.T) {
+	worker := newTestWorker(t, func(command string) map[string]interface{} {
+		t.Errorf("no command should reach the agent, got %q", command)
+		return map[string]interface{}{"stdout": ""}
+	})
+	executor := NewKubectlToolExecutor(worker)
+
+	cfg := newTestConfig("readwrite")
+	cfg.SecurityConfig.SetDeniedResources("secrets")
+
+	_, err := executor.Execute(map[string]interface{}{
+		"_tool_name": "kubectl_resources",
+		"operation":  "create",
+		"resource":   "secret",
+		"args":       "db-creds -n shop",
+		"literals":   map[string]interface{}{"password": "s3cret"},
+	}, cfg)
+	if err == nil || !strings.Contains(err.Error(), "denied by security configuration") {
+		t.Fatalf("expected the denied-resources policy to reject the secret, got %v", err)
+	}
+}
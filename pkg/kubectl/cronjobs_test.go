@@ -0,0 +1,68 @@
+package kubectl
+
+import (
+	"reflect"
+	"testing"
+)
+
+const cronJobsJSON = `{
+  "items": [
+    {
+      "metadata": {"name": "report", "namespace": "shop"},
+      "spec": {"schedule": "0 6 * * *", "timeZone": "Europe/Berlin", "suspend": false},
+      "status": {
+        "lastScheduleTime": "2024-05-01T04:00:00Z",
+        "active": [{"kind": "Job", "name": "report-28573440", "namespace": "shop"}]
+      }
+    },
+    {
+      "metadata": {"name": "cleanup", "namespace": "shop"},
+      "spec": {"schedule": "*/15 * * * *", "suspend": true},
+      "status": {"lastScheduleTime": "2024-04-30T23:45:00Z"}
+    },
+    {
+      "metadata": {"name": "backfill", "namespace": "shop"},
+      "spec": {"schedule": "@hourly"},
+      "status": {}
+    }
+  ]
+}`
+
+func TestKubectlToolExecutor_CronJobs(t *testing.T) {
+	restricted := newTestConfig("readonly")
+	restricted.SecurityConfig.SetAllowedNamespaces("shop")
+
+	runDiagnosticCases(t, "cronjobs", newTestConfig("readonly"), map[string]string{
+		"kubectl get cronjobs -n shop -o json -l 'team=data'": cronJobsJSON,
+		"kubectl get cronjobs -n default -o json":             `{"items": []}`,
+	}, []diagnosticCase{
+		{
+			name: "sorted by name",
+			args: "-n shop -l team=data",
+			check: func(t *testing.T, output string, _ []string) {
+				got := decodeReport[cronJobList](t, output)
+				want := cronJobList{Namespace: "shop", CronJobs: []cronJobInfo{
+					{Name: "backfill", Schedule: "@hourly", ActiveJobs: []string{}},
+					{Name: "cleanup", Schedule: "*/15 * * * *", Suspend: true, LastScheduleTime: "2024-04-30T23:45:00Z", ActiveJobs: []string{}},
+					{Name: "report", Schedule: "0 6 * * *", TimeZone: "Europe/Berlin", LastScheduleTime: "2024-05-01T04:00:00Z", ActiveJobs: []string{"report-28573440"}},
+				}}
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("expected %+v, got %+v", want, got)
+				}
+			},
+		},
+		{
+			name: "default namespace, none set",
+			args: "",
+			check: func(t *testing.T, output string, _ []string) {
+				if got := decodeReport[cronJobList](t, output); got.Namespace != "default" || got.CronJobs == nil || len(got.CronJobs) != 0 {
+					t.Errorf("expected an empty list for default, got %+v", got)
+				}
+			},
+		},
+		{name: "namespace carrying flags refused", args: "-n 'shop --as=admin'", refused: "invalid namespace"},
+		{name: "names refused", args: "report -n shop", refused: "takes a namespace"},
+		{name: "all namespaces refused", args: "-A", refused: "takes a namespace"},
+		{name: "namespace outside the allow-list", args: "-n billing", cfg: restricted, refused: "billing"},
+	})
+}
@@ -0,0 +1,89 @@
+package kubectl
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Azure/mcp-kubernetes/pkg/config"
+)
+
+// deletionPollInterval is how often a delete with wait_for_deletion checks for its resources
+const deletionPollInterval = time.Second
+
+// deletionTargetFlags are the delete flags that also select the resources for the get that
+// checks whether they are gone; delete-only flags such as --cascade and --grace-period are dropped
+var deletionTargetFlags = []string{
+	"--namespace", "--selector", "--field-selector", "--filename", "--kustomize", "--recursive",
+	"--all-namespaces", "--context", "--server", "--as", "--as-group",
+}
+
+// applyWaitForDeletion makes kubectl wait for the deleted resources to be removed, which
+// --wait=false in args contradicts
+func applyWaitForDeletion(command string) (string, error) {
+	cmdline, err := parseCommandLine(command)
+	if err != nil {
+		return "", err
+	}
+	if wait, ok := cmdline.flag("--wait"); ok {
+		if wait == "false" {
+			return "", fmt.Errorf("wait_for_deletion can't be combined with --wait=false")
+		}
+		return command, nil
+	}
+	return insertFlags(command, []string{"--wait=true"}), nil
+}
+
+// deletionCheckCommand turns a delete command into a get of the same resources that prints the
+// names of those still present, and nothing once they are all gone
+func deletionCheckCommand(command string) (string, error) {
+	cmdline, err := parseCommandLine(command)
+	if err != nil {
+		return "", err
+	}
+	if cmdline.verb() != "delete" {
+		return "", fmt.Errorf("wait_for_deletion is only supported for delete, not %s", cmdline.verb())
+	}
+
+	parts := append([]string{"get"}, cmdline.args()...)
+	for _, name := range deletionTargetFlags {
+		for _, value := range cmdline.flags[name] {
+			switch {
+			case value == "":
+				parts = append(parts, name)
+			case name == "--namespace":
+				parts = append(parts, name+"="+value)
+			default:
+				parts = append(parts, name+"="+shellQuote(value))
+			}
+		}
+	}
+	return strings.Join(append(parts, "--ignore-not-found", "-o", "name"), " "), nil
+}
+
+// confirmDeletion polls until the resources of a finished delete are gone, and adds that
+// confirmation to the delete's output. Resources still present at the deadline, which is
+// the end of the call's timeout, are an error that names them.
+func (e *KubectlToolExecutor) confirmDeletion(command, output string, deadline time.Time, cfg *config.ConfigData) (string, error) {
+	check, err := deletionCheckCommand(command)
+	if err != nil {
+		return "", err
+	}
+
+	started := time.Now()
+	for {
+		remaining, err := e.runReadCommand(check, cfg)
+		if err != nil {
+			return "", fmt.Errorf("delete succeeded but checking for removal failed: %w", err)
+		}
+		if strings.TrimSpace(remaining) == "" {
+			return fmt.Sprintf("%s\nConfirmed removed after %s\n", strings.TrimRight(output, "\n"),
+				time.Since(started).Round(time.Second)), nil
+		}
+		if time.Now().Add(deletionPollInterval).After(deadline) {
+			return "", fmt.Errorf("delete succeeded but resources were still present when the timeout ran out: %s",
+				strings.Join(strings.Fields(remaining), ", "))
+		}
+		time.Sleep(deletionPollInterval)
+	}
+}
@@ -0,0 +1,121 @@
+package kubectl
+
+import (
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestDeletionCheckCommand(t *testing.T) {
+	tests := []struct {
+		command  string
+		expected string
+	}{
+		{
+			command:  "delete pod web -n default --wait=true --grace-period=0",
+			expected: "get pod web --namespace=default --ignore-not-found -o name",
+		},
+		{
+			command:  "delete pods -l app=web -n shop --cascade=foreground --wait=true --as alice",
+			expected: "get pods --namespace=shop --selector='app=web' --as='alice' --ignore-not-found -o name",
+		},
+	}
+	for _, tt := range tests {
+		got, err := deletionCheckCommand(tt.command)
+		if err != nil {
+			t.Fatalf("Unexpected error for %q: %v", tt.command, err)
+		}
+		if got != tt.expected {
+			t.Errorf("For %q expected %q, got %q", tt.command, tt.expected, got)
+		}
+	}
+}
+
+func TestKubectlToolExecutor_WaitForDeletion(t *testing.T) {
+	var checks atomic.Int32
+	worker := newTestWorker(t, func(command string) map[string]interface{} {
+		switch command {
+		case "kubectl delete pod web -n default --wait=true":
+			return map[string]interface{}{"stdout": `pod "web" deleted` + "\n"}
+		case "kubectl get pod web --namespace=default --ignore-not-found -o name":
+			// Still terminating on the first check, gone on the next
+			if checks.Add(1) == 1 {
+				return map[string]interface{}{"stdout": "pod/web\n"}
+			}
+			return map[string]interface{}{"stdout": ""}
+		}
+		return map[string]interface{}{"error": "unexpected command: " + command}
+	})
+	executor := NewKubectlToolExecutor(worker)
+
+	result, err := executor.Execute(map[string]interface{}{
+		"_tool_name":        "kubectl_resources",
+		"operation":         "delete",
+		"resource":          "pod",
+		"args":              "web -n default",
+		"wait_for_deletion": true,
+	}, newTestConfig("readwrite"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(result, `pod "web" deleted`) || !strings.Contains(result, "Confirmed removed") {
+		t.Errorf("Expected the delete output and a removal confirmation, got %q", result)
+	}
+	if got := checks.Load(); got != 2 {
+		t.Errorf("Expected 2 removal checks, got %d", got)
+	}
+}
+
+func TestKubectlToolExecutor_WaitForDeletionTimeout(t *testing.T) {
+	worker := newTestWorker(t, func(command string) map[string]interface{} {
+		switch command {
+		case "kubectl delete pod web -n default --wait=true":
+			return map[string]interface{}{"stdout": `pod "web" deleted` + "\n"}
+		case "kubectl get pod web --namespace=default --ignore-not-found -o name":
+			return map[string]interface{}{"stdout": "pod/web\n"}
+		}
+		return map[string]interface{}{"error": "unexpected command: " + command}
+	})
+	executor := NewKubectlToolExecutor(worker)
+
+	_, err := executor.Execute(map[string]interface{}{
+		"_tool_name":        "kubectl_resources",
+		"operation":         "delete",
+		"resource":          "pod",
+		"args":              "web -n default",
+		"wait_for_deletion": true,
+		"timeout":           1,
+	}, newTestConfig("readwrite"))
+	if err == nil || !strings.Contains(err.Error(), "pod/web") {
+		t.Errorf("Expected an error naming the remaining pod, got %v", err)
+	}
+}
+
+func TestKubectlToolExecutor_WaitForDeletionParams(t *testing.T) {
+	executor := NewKubectlToolExecutor(nil)
+	cfg := newTestConfig("readwrite")
+
+	tests := []struct {
+		name      string
+		operation string
+		args      string
+		expected  string
+	}{
+		{name: "not delete", operation: "get", args: "web", expected: "only supported for delete"},
+		{name: "wait disabled", operation: "delete", args: "web --wait=false", expected: "--wait=false"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := executor.Execute(map[string]interface{}{
+				"_tool_name":        "kubectl_resources",
+				"operation":         tt.operation,
+				"resource":          "pod",
+				"args":              tt.args,
+				"wait_for_deletion": true,
+			}, cfg)
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("Expected error containing %q, got %v", tt.expected, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package kubectl
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Azure/mcp-kubernetes/pkg/config"
+)
+
+const (
+	// deploymentLogsMaxPods bounds the pods whose logs are fetched for a deployment
+	deploymentLogsMaxPods = 20
+	// deploymentLogsDefaultTail is the number of lines fetched per pod without --tail
+	deploymentLogsDefaultTail = 100
+	// deploymentLogsMaxTail bounds --tail per pod
+	deploymentLogsMaxTail = 1000
+	// deploymentLogsMaxBytes bounds the log output kept per pod, keeping the most recent lines
+	deploymentLogsMaxBytes = 64 * 1024
+)
+
+// logsSince matches the durations accepted by --since
+var logsSince = regexp.MustCompile(`^[0-9]+[smh]$`)
+
+// deploymentLogs fetches the recent logs of every pod selected by a deployment and returns
+// them one pod after another, each under a "==> pod/<name> <==" heading. Pods are taken in
+// name order up to deploymentLogsMaxPods, each limited to --tail lines (default 100) and
+// deploymentLogsMaxBytes. A pod whose logs can't be read gets the error under its heading.
+// Every lookup goes through the same access and namespace checks as a direct call.
+func (e *KubectlToolExecutor) deploymentLogs(args string, params map[string]interface{}, cfg *config.ConfigData) (string, error) {
+	cmdline, err := parseCommandLine(args)
+	if err != nil {
+		return "", err
+	}
+	if len(cmdline.positionals) != 1 {
+		return "", fmt.Errorf("deployment-logs requires exactly one deployment name")
+	}
+	name := cmdline.positionals[0]
+	if kind, deployment, ok := strings.Cut(name, "/"); ok {
+		if describeTreeKinds[strings.ToLower(kind)] != "deployments" {
+			return "", fmt.Errorf("deployment-logs requires a deployment, got '%s'", name)
+		}
+		name = deployment
+	}
+	if !objectName.MatchString(name) {
+		return "", fmt.Errorf("invalid deployment name '%s'", name)
+	}
+	namespace, err := namespaceFlag(cmdline, "default")
+	if err != nil {
+		return "", err
+	}
+
+	logFlags := []string{}
+	tail := deploymentLogsDefaultTail
+	if value, ok := cmdline.flag("--tail"); ok {
+		tail, err = strconv.Atoi(value)
+		if err != nil || tail <= 0 || tail > deploymentLogsMaxTail {
+			return "", fmt.Errorf("--tail must be between 1 and %d, got '%s'", deploymentLogsMaxTail, value)
+		}
+	}
+	logFlags = append(logFlags, fmt.Sprintf("--tail=%d", tail))
+	if since, ok := cmdline.flag("--since"); ok {
+		if !logsSince.MatchString(since) {
+			return "", fmt.Errorf("invalid --since duration '%s'", since)
+		}
+		logFlags = append(logFlags, "--since="+since)
+	}
+	container, _ := cmdline.flag("--container")
+	if param, _ := params["container"].(string); strings.TrimSpace(param) != "" {
+		container = strings.TrimSpace(param)
+	}
+	if container != "" {
+		if !containerName.MatchString(container) {
+			return "", fmt.Errorf("invalid container name '%s'", container)
+		}
+		logFlags = append(logFlags, "-c "+container)
+	} else {
+		logFlags = append(logFlags, "--all-containers=true", "--prefix=true")
+	}
+
+	deployment, err := e.getObject(fmt.Sprintf("get deployments %s -n %s -o json", name, namespace), cfg)
+	if err != nil {
+		return "", err
+	}
+	selector := nestedStringMap(deployment, "spec", "selector", "matchLabels")
+	if len(selector) == 0 {
+		return "", fmt.Errorf("deployment %s has no matchLabels selector to find its pods by", name)
+	}
+
+	pods, err := e.listObjects(fmt.Sprintf("get pods -n %s -l %s -o json", namespace, formatSelector(selector)), cfg)
+	if err != nil {
+		return "", err
+	}
+	var names []string
+	for _, pod := range pods {
+		_, podName := objectNamespacedName(pod)
+		names = append(names, podName)
+	}
+	sort.Strings(names)
+	if len(names) == 0 {
+		return fmt.Sprintf("No pods found for deployment %s in namespace %s\n", name, namespace), nil
+	}
+
+	var output strings.Builder
+	for i, podName := range names {
+		if i == deploymentLogsMaxPods {
+			fmt.Fprintf(&output, "... %d more pods not shown\n", len(names)-i)
+			break
+		}
+		fmt.Fprintf(&output, "==> pod/%s <==\n", podName)
+		logs, err := e.runReadCommand(fmt.Sprintf("logs %s -n %s %s", podName, namespace, strings.Join(logFlags, " ")), cfg)
+		if err != nil {
+			fmt.Fprintf(&output, "[error: %v]\n", err)
+			continue
+		}
+		logs = recentLogBytes(logs, deploymentLogsMaxBytes)
+		output.WriteString(logs)
+		if logs != "" && !strings.HasSuffix(logs, "\n") {
+			output.WriteString("\n")
+		}
+	}
+	return output.String(), nil
+}
+
+// recentLogBytes keeps the last max bytes of logs, starting at a line boundary
+func recentLogBytes(logs string, max int) string {
+	if len(logs) <= max {
+		return logs
+	}
+	logs = logs[len(logs)-max:]
+	if i := strings.IndexByte(logs, '\n'); i >= 0 {
+		logs = logs[i+1:]
+	}
+	return "[earlier output omitted]\n" + logs
+}
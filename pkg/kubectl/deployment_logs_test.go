@@ -0,0 +1,83 @@
+package kubectl
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestKubectlToolExecutor_DeploymentLogs(t *testing.T) {
+	outputs := map[string]map[string]interface{}{
+		"kubectl get deployments web -n shop -o json": {"stdout": `{
+			"metadata": {"name": "web", "namespace": "shop"},
+			"spec": {"selector": {"matchLabels": {"app": "web", "tier": "frontend"}}}}`},
+		"kubectl get pods -n shop -l app=web,tier=frontend -o json": {"stdout": `{"items": [
+			{"metadata": {"name": "web-b", "namespace": "shop"}},
+			{"metadata": {"name": "web-a", "namespace": "shop"}}]}`},
+		"kubectl logs web-a -n shop --tail=50 -c app": {"stdout": "starting\nready\n"},
+		"kubectl logs web-b -n shop --tail=50 -c app": {"error": "container app is waiting to start"},
+	}
+	worker := newTestWorker(t, func(command string) map[string]interface{} {
+		if reply, ok := outputs[command]; ok {
+			return reply
+		}
+		return map[string]interface{}{"error": "unexpected command: " + command}
+	})
+	executor := NewKubectlToolExecutor(worker)
+
+	result, err := executor.Execute(map[string]interface{}{
+		"_tool_name": "kubectl_diagnostics",
+		"operation":  "deployment-logs",
+		"resource":   "",
+		"args":       "deployment/web -n shop --tail 50",
+		"container":  "app",
+	}, newTestConfig("readonly"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.HasPrefix(result, "==> pod/web-a <==\nstarting\nready\n==> pod/web-b <==\n[error: ") {
+		t.Errorf("unexpected output:\n%s", result)
+	}
+	if !strings.Contains(result, "container app is waiting to start") {
+		t.Errorf("expected the web-b error in the output:\n%s", result)
+	}
+}
+
+func TestKubectlToolExecutor_DeploymentLogsNamespacePolicy(t *testing.T) {
+	worker := newTestWorker(t, func(command string) map[string]interface{} {
+		return map[string]interface{}{"error": "unexpected command: " + command}
+	})
+	executor := NewKubectlToolExecutor(worker)
+	cfg := newTestConfig("readonly")
+	cfg.SecurityConfig.SetAllowedNamespaces("default")
+
+	if _, err := executor.Execute(map[string]interface{}{
+		"_tool_name": "kubectl_diagnostics",
+		"operation":  "deployment-logs",
+		"resource":   "",
+		"args":       "web -n shop",
+	}, cfg); err == nil || !strings.Contains(err.Error(), "shop") {
+		t.Errorf("expected the namespace policy to refuse shop, got %v", err)
+	}
+}
+
+func TestDeploymentLogsArgs(t *testing.T) {
+	executor := NewKubectlToolExecutor(newTestWorker(t, func(command string) map[string]interface{} {
+		return map[string]interface{}{"error": "unexpected command: " + command}
+	}))
+	for _, args := range []string{"", "web api", "statefulset/db", "web --tail=0", "web --tail=5000", "web --since=yesterday", "web -n 'shop --as=admin'", "'web --as=admin'"} {
+		if _, err := executor.deploymentLogs(args, nil, newTestConfig("readonly")); err == nil {
+			t.Errorf("expected args %q to be rejected", args)
+		}
+	}
+}
+
+func TestRecentLogBytes(t *testing.T) {
+	logs := "line one\nline two\nline three\n"
+	if got := recentLogBytes(logs, 100); got != logs {
+		t.Errorf("short logs changed: %q", got)
+	}
+	if got := recentLogBytes(logs, 15); got != "[earlier output omitted]\nline three\n" {
+		t.Errorf("recentLogBytes = %q", got)
+	}
+}
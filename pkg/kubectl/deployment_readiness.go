@@ -0,0 +1,95 @@
+package kubectl
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/Azure/mcp-kubernetes/pkg/config"
+)
+
+// deploymentReadinessReport is the replica counts of the deployments in a namespace
+type deploymentReadinessReport struct {
+	Namespace   string                `json:"namespace"`
+	Deployments []deploymentReadiness `json:"deployments"`
+	// NotReady names the deployments with fewer ready, available or updated replicas than desired
+	NotReady []string `json:"not_ready"`
+}
+
+// deploymentReadiness is one deployment's desired replicas against those ready, available and
+// running the current pod template
+type deploymentReadiness struct {
+	Name      string `json:"name"`
+	Desired   int64  `json:"desired"`
+	Ready     int64  `json:"ready"`
+	Available int64  `json:"available"`
+	Updated   int64  `json:"updated"`
+	// NotReady is set when any of ready, available or updated is below desired
+	NotReady bool `json:"not_ready"`
+	// Paused is set for a deployment whose rollout is paused, which explains a lagging updated count
+	Paused bool `json:"paused,omitempty"`
+}
+
+// deploymentReadiness lists the deployments of a namespace (-n, default "default"),
+// optionally narrowed with -l, with their desired, ready, available and updated replicas in
+// name order, and flags those not fully ready. A deployment scaled to zero counts as ready.
+// The list goes through the same access and namespace checks as a direct get.
+func (e *KubectlToolExecutor) deploymentReadiness(args string, cfg *config.ConfigData) (string, error) {
+	cmdline, err := parseCommandLine(args)
+	if err != nil {
+		return "", err
+	}
+	if len(cmdline.positionals) != 0 || cmdline.hasFlag("--all-namespaces") {
+		return "", fmt.Errorf("deployment-readiness takes a namespace (-n) and optionally -l, e.g. '-n default'")
+	}
+	namespace, err := namespaceFlag(cmdline, "default")
+	if err != nil {
+		return "", err
+	}
+	command := "get deployments -n " + namespace + " -o json"
+	if selector, ok := cmdline.flag("--selector"); ok && selector != "" {
+		command += " -l " + shellQuote(selector)
+	}
+
+	deployments, err := e.listObjects(command, cfg)
+	if err != nil {
+		return "", err
+	}
+	report := deploymentReadinessReport{Namespace: namespace, Deployments: []deploymentReadiness{}, NotReady: []string{}}
+	for _, deployment := range deployments {
+		report.Deployments = append(report.Deployments, readinessOf(deployment))
+	}
+	sort.Slice(report.Deployments, func(i, j int) bool {
+		return report.Deployments[i].Name < report.Deployments[j].Name
+	})
+	for _, row := range report.Deployments {
+		if row.NotReady {
+			report.NotReady = append(report.NotReady, row.Name)
+		}
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode deployment readiness: %w", err)
+	}
+	return string(data), nil
+}
+
+// readinessOf reads a deployment's replica counts; spec.replicas defaults to 1 when unset
+func readinessOf(deployment map[string]interface{}) deploymentReadiness {
+	_, name := objectNamespacedName(deployment)
+	desired := int64(1)
+	if _, set := nestedValue(deployment, "spec", "replicas").(float64); set {
+		desired = nestedInt(deployment, "spec", "replicas")
+	}
+	row := deploymentReadiness{
+		Name:      name,
+		Desired:   desired,
+		Ready:     nestedInt(deployment, "status", "readyReplicas"),
+		Available: nestedInt(deployment, "status", "availableReplicas"),
+		Updated:   nestedInt(deployment, "status", "updatedReplicas"),
+	}
+	row.Paused, _ = nestedValue(deployment, "spec", "paused").(bool)
+	row.NotReady = row.Ready < row.Desired || row.Available < row.Desired || row.Updated < row.Desired
+	return row
+}
@@ -0,0 +1,67 @@
+package kubectl
+
+import (
+	"testing"
+)
+
+const readinessDeploymentsJSON = `{
+  "items": [
+    {"metadata": {"name": "web", "namespace": "shop"}, "spec": {"replicas": 3},
+     "status": {"replicas": 3, "readyReplicas": 3, "availableReplicas": 3, "updatedReplicas": 3}},
+    {"metadata": {"name": "api", "namespace": "shop"}, "spec": {"replicas": 4},
+     "status": {"replicas": 5, "readyReplicas": 3, "availableReplicas": 2, "updatedReplicas": 2}},
+    {"metadata": {"name": "worker", "namespace": "shop"}, "spec": {"replicas": 2, "paused": true},
+     "status": {"replicas": 2, "readyReplicas": 2, "availableReplicas": 2, "updatedReplicas": 1}},
+    {"metadata": {"name": "batch", "namespace": "shop"}, "spec": {"replicas": 0}, "status": {}},
+    {"metadata": {"name": "cache", "namespace": "shop"}, "spec": {}, "status": {"replicas": 1, "updatedReplicas": 1}}
+  ]
+}`
+
+func TestKubectlToolExecutor_DeploymentReadiness(t *testing.T) {
+	cfg := newTestConfig("readonly")
+	cfg.SecurityConfig.SetAllowedNamespaces("shop")
+
+	runDiagnosticCases(t, "deployment-readiness", cfg, map[string]string{
+		"kubectl get deployments -n shop -o json":                 readinessDeploymentsJSON,
+		"kubectl get deployments -n shop -o json -l 'tier=front'": `{"items": []}`,
+	}, []diagnosticCase{
+		{
+			name: "replica counts",
+			args: "-n shop",
+			check: func(t *testing.T, output string, _ []string) {
+				report := decodeReport[deploymentReadinessReport](t, output)
+				expected := []deploymentReadiness{
+					{Name: "api", Desired: 4, Ready: 3, Available: 2, Updated: 2, NotReady: true},
+					{Name: "batch", Desired: 0},
+					{Name: "cache", Desired: 1, Updated: 1, NotReady: true},
+					{Name: "web", Desired: 3, Ready: 3, Available: 3, Updated: 3},
+					{Name: "worker", Desired: 2, Ready: 2, Available: 2, Updated: 1, NotReady: true, Paused: true},
+				}
+				if report.Namespace != "shop" || len(report.Deployments) != len(expected) {
+					t.Fatalf("expected %d deployments in shop, got %+v", len(expected), report)
+				}
+				for i, row := range report.Deployments {
+					if row != expected[i] {
+						t.Errorf("deployments[%d] = %+v, want %+v", i, row, expected[i])
+					}
+				}
+				if len(report.NotReady) != 3 || report.NotReady[0] != "api" || report.NotReady[1] != "cache" || report.NotReady[2] != "worker" {
+					t.Errorf("not_ready = %v, want [api cache worker]", report.NotReady)
+				}
+			},
+		},
+		{
+			name: "empty selection",
+			args: "-n shop -l tier=front",
+			check: func(t *testing.T, output string, _ []string) {
+				if report := decodeReport[deploymentReadinessReport](t, output); len(report.Deployments) != 0 || report.NotReady == nil {
+					t.Errorf("expected an empty report, got %+v", report)
+				}
+			},
+		},
+		{name: "namespace carrying flags refused", args: "-n 'shop --as=admin'", refused: "invalid namespace"},
+		{name: "names refused", args: "web -n shop", refused: "takes a namespace"},
+		{name: "all namespaces refused", args: "--all-namespaces", refused: "takes a namespace"},
+		{name: "namespace outside the allow-list", args: "-n kube-system", refused: "kube-system"},
+	})
+}
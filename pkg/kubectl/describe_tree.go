@@ -0,0 +1,429 @@
+package kubectl
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Azure/mcp-kubernetes/pkg/config"
+	"github.com/Azure/mcp-kubernetes/pkg/security"
+)
+
+const (
+	// describeTreeMaxItems bounds the replicasets, pods and services listed for a workload
+	describeTreeMaxItems = 50
+	// describeTreeMaxEvents bounds the number of recent events listed for a workload
+	describeTreeMaxEvents = 20
+)
+
+// describeTreeKinds maps accepted resource names to the kind that is fetched
+var describeTreeKinds = map[string]string{
+	"deployment":   "deployments",
+	"deployments":  "deployments",
+	"deploy":       "deployments",
+	"statefulset":  "statefulsets",
+	"statefulsets": "statefulsets",
+	"sts":          "statefulsets",
+}
+
+// workloadTree is the consolidated view of a workload and the resources related to it
+type workloadTree struct {
+	Kind          string            `json:"kind"`
+	Name          string            `json:"name"`
+	Namespace     string            `json:"namespace"`
+	Replicas      int64             `json:"replicas"`
+	ReadyReplicas int64             `json:"ready_replicas"`
+	Selector      map[string]string `json:"selector,omitempty"`
+	ReplicaSets   []treeReplicaSet  `json:"replicasets,omitempty"`
+	Pods          []treePod         `json:"pods"`
+	Services      []treeService     `json:"services"`
+	Events        []treeEvent       `json:"events"`
+	Truncated     map[string]int    `json:"truncated,omitempty"` // items omitted per list
+}
+
+type treeReplicaSet struct {
+	Name          string `json:"name"`
+	Revision      string `json:"revision,omitempty"`
+	Replicas      int64  `json:"replicas"`
+	ReadyReplicas int64  `json:"ready_replicas"`
+}
+
+type treePod struct {
+	Name     string `json:"name"`
+	Owner    string `json:"owner"`
+	Phase    string `json:"phase"`
+	Ready    string `json:"ready"`
+	Restarts int64  `json:"restarts"`
+	Node     string `json:"node,omitempty"`
+}
+
+type treeService struct {
+	Name      string   `json:"name"`
+	Type      string   `json:"type"`
+	ClusterIP string   `json:"cluster_ip,omitempty"`
+	Ports     []string `json:"ports,omitempty"`
+}
+
+type treeEvent struct {
+	Type     string `json:"type"`
+	Reason   string `json:"reason"`
+	Object   string `json:"object"`
+	Message  string `json:"message"`
+	Count    int64  `json:"count,omitempty"`
+	LastSeen string `json:"last_seen,omitempty"`
+}
+
+// describeTree gathers a deployment or statefulset together with the replicasets and pods
+// it owns, the services selecting its pods and their recent events. Every lookup is a
+// read-only get that goes through the same access and namespace checks as a direct call.
+func (e *KubectlToolExecutor) describeTree(resource, args string, cfg *config.ConfigData) (string, error) {
+	kind, ok := describeTreeKinds[strings.ToLower(resource)]
+	if !ok {
+		return "", fmt.Errorf("describe-tree supports deployment and statefulset resources, got '%s'", resource)
+	}
+
+	cmdline, err := parseCommandLine(args)
+	if err != nil {
+		return "", err
+	}
+	if len(cmdline.positionals) != 1 {
+		return "", fmt.Errorf("describe-tree requires exactly one %s name", strings.TrimSuffix(kind, "s"))
+	}
+	name := cmdline.positionals[0]
+	if !objectName.MatchString(name) {
+		return "", fmt.Errorf("invalid %s name '%s'", strings.TrimSuffix(kind, "s"), name)
+	}
+	namespace, err := namespaceFlag(cmdline, "default")
+	if err != nil {
+		return "", err
+	}
+
+	workload, err := e.getObject(fmt.Sprintf("get %s %s -n %s -o json", kind, name, namespace), cfg)
+	if err != nil {
+		return "", err
+	}
+
+	tree := &workloadTree{
+		Kind:          kind,
+		Name:          name,
+		Namespace:     namespace,
+		Replicas:      nestedInt(workload, "spec", "replicas"),
+		ReadyReplicas: nestedInt(workload, "status", "readyReplicas"),
+		Selector:      nestedStringMap(workload, "spec", "selector", "matchLabels"),
+		Truncated:     make(map[string]int),
+	}
+
+	// uids of everything in the tree, used to pick out related events
+	related := map[string]bool{objectUID(workload): true}
+
+	// Pods are owned directly by statefulsets but through replicasets for deployments
+	podOwners := map[string]string{objectUID(workload): name}
+	if kind == "deployments" {
+		replicaSets, err := e.listObjects(fmt.Sprintf("get replicasets -n %s -o json", namespace), cfg)
+		if err != nil {
+			return "", err
+		}
+		podOwners = make(map[string]string)
+		replicaSets = ownedBy(replicaSets, objectUID(workload))
+		sortNewestFirst(replicaSets)
+		for _, rs := range replicaSets {
+			_, rsName := objectNamespacedName(rs)
+			podOwners[objectUID(rs)] = rsName
+			related[objectUID(rs)] = true
+			if len(tree.ReplicaSets) == describeTreeMaxItems {
+				tree.Truncated["replicasets"]++
+				continue
+			}
+			tree.ReplicaSets = append(tree.ReplicaSets, treeReplicaSet{
+				Name:          rsName,
+				Revision:      nestedString(rs, "metadata", "annotations", "deployment.kubernetes.io/revision"),
+				Replicas:      nestedInt(rs, "status", "replicas"),
+				ReadyReplicas: nestedInt(rs, "status", "readyReplicas"),
+			})
+		}
+	}
+
+	podsCommand := fmt.Sprintf("get pods -n %s -o json", namespace)
+	if len(tree.Selector) > 0 {
+		podsCommand = fmt.Sprintf("get pods -n %s -l %s -o json", namespace, formatSelector(tree.Selector))
+	}
+	pods, err := e.listObjects(podsCommand, cfg)
+	if err != nil {
+		return "", err
+	}
+	tree.Pods = []treePod{}
+	for _, pod := range pods {
+		owner, ok := ownerName(pod, podOwners)
+		if !ok {
+			continue
+		}
+		related[objectUID(pod)] = true
+		if len(tree.Pods) == describeTreeMaxItems {
+			tree.Truncated["pods"]++
+			continue
+		}
+		tree.Pods = append(tree.Pods, summarizePod(pod, owner))
+	}
+
+	services, err := e.listObjects(fmt.Sprintf("get services -n %s -o json", namespace), cfg)
+	if err != nil {
+		return "", err
+	}
+	templateLabels := nestedStringMap(workload, "spec", "template", "metadata", "labels")
+	tree.Services = []treeService{}
+	for _, svc := range services {
+		selector := nestedStringMap(svc, "spec", "selector")
+		if len(selector) == 0 || !labelsContain(templateLabels, selector) {
+			continue
+		}
+		if len(tree.Services) == describeTreeMaxItems {
+			tree.Truncated["services"]++
+			continue
+		}
+		tree.Services = append(tree.Services, summarizeService(svc))
+	}
+
+	events, err := e.listObjects(fmt.Sprintf("get events -n %s -o json", namespace), cfg)
+	if err != nil {
+		return "", err
+	}
+	tree.Events = []treeEvent{}
+	var matched []map[string]interface{}
+	for _, event := range events {
+		if related[nestedString(event, "involvedObject", "uid")] {
+			matched = append(matched, event)
+		}
+	}
+	sort.SliceStable(matched, func(i, j int) bool {
+		return eventTime(matched[i]) > eventTime(matched[j])
+	})
+	if len(matched) > describeTreeMaxEvents {
+		tree.Truncated["events"] = len(matched) - describeTreeMaxEvents
+		matched = matched[:describeTreeMaxEvents]
+	}
+	for _, event := range matched {
+		tree.Events = append(tree.Events, treeEvent{
+			Type:     nestedString(event, "type"),
+			Reason:   nestedString(event, "reason"),
+			Object:   strings.ToLower(nestedString(event, "involvedObject", "kind")) + "/" + nestedString(event, "involvedObject", "name"),
+			Message:  nestedString(event, "message"),
+			Count:    nestedInt(event, "count"),
+			LastSeen: eventTime(event),
+		})
+	}
+
+	if len(tree.Truncated) == 0 {
+		tree.Truncated = nil
+	}
+
+	data, err := json.MarshalIndent(tree, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode describe-tree result: %w", err)
+	}
+	return string(data), nil
+}
+
+// runReadCommand runs a command issued internally by a composite operation, applying the
+// same access level and security validation as a command requested directly
+func (e *KubectlToolExecutor) runReadCommand(command string, cfg *config.ConfigData) (string, error) {
+	if err := e.checkAccessLevel(command, cfg); err != nil {
+		return "", err
+	}
+
+	validator := security.NewValidator(cfg.SecurityConfig)
+	if err := validator.ValidateCommand(command, security.CommandTypeKubectl); err != nil {
+		return "", err
+	}
+
+	return e.runCommand(command, cfg)
+}
+
+// getObject runs a `get ... -o json` command for a single object and decodes the result
+func (e *KubectlToolExecutor) getObject(command string, cfg *config.ConfigData) (map[string]interface{}, error) {
+	output, err := e.runReadCommand(command, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	var obj map[string]interface{}
+	if err := json.Unmarshal([]byte(output), &obj); err != nil {
+		return nil, fmt.Errorf("failed to parse output of '%s': %w", command, err)
+	}
+	return obj, nil
+}
+
+// listObjects runs a `get ... -o json` list command and returns its items
+func (e *KubectlToolExecutor) listObjects(command string, cfg *config.ConfigData) ([]map[string]interface{}, error) {
+	list, err := e.getObject(command, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return listItems(list), nil
+}
+
+// listItems returns the objects in a decoded list
+func listItems(list map[string]interface{}) []map[string]interface{} {
+	rawItems, _ := list["items"].([]interface{})
+	items := make([]map[string]interface{}, 0, len(rawItems))
+	for _, raw := range rawItems {
+		if item, ok := raw.(map[string]interface{}); ok {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// ownedBy returns the objects with an owner reference to the given uid
+func ownedBy(objects []map[string]interface{}, uid string) []map[string]interface{} {
+	var owned []map[string]interface{}
+	for _, obj := range objects {
+		if _, ok := ownerName(obj, map[string]string{uid: ""}); ok {
+			owned = append(owned, obj)
+		}
+	}
+	return owned
+}
+
+// podOwner returns the name recorded for the first owner reference found in owners
+func ownerName(obj map[string]interface{}, owners map[string]string) (string, bool) {
+	metadata, _ := obj["metadata"].(map[string]interface{})
+	refs, _ := metadata["ownerReferences"].([]interface{})
+	for _, raw := range refs {
+		ref, _ := raw.(map[string]interface{})
+		uid, _ := ref["uid"].(string)
+		if name, ok := owners[uid]; ok {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// summarizePod reduces a pod to its phase, readiness, restarts and node
+func summarizePod(pod map[string]interface{}, owner string) treePod {
+	_, name := objectNamespacedName(pod)
+	statuses, _ := nestedValue(pod, "status", "containerStatuses").([]interface{})
+
+	var ready, restarts int64
+	for _, raw := range statuses {
+		status, _ := raw.(map[string]interface{})
+		if isReady, _ := status["ready"].(bool); isReady {
+			ready++
+		}
+		restarts += nestedInt(status, "restartCount")
+	}
+
+	return treePod{
+		Name:     name,
+		Owner:    owner,
+		Phase:    nestedString(pod, "status", "phase"),
+		Ready:    fmt.Sprintf("%d/%d", ready, len(statuses)),
+		Restarts: restarts,
+		Node:     nestedString(pod, "spec", "nodeName"),
+	}
+}
+
+// summarizeService reduces a service to its type, cluster IP and ports
+func summarizeService(svc map[string]interface{}) treeService {
+	_, name := objectNamespacedName(svc)
+	rawPorts, _ := nestedValue(svc, "spec", "ports").([]interface{})
+
+	var ports []string
+	for _, raw := range rawPorts {
+		port, _ := raw.(map[string]interface{})
+		protocol, _ := port["protocol"].(string)
+		if protocol == "" {
+			protocol = "TCP"
+		}
+		ports = append(ports, fmt.Sprintf("%d/%s", nestedInt(port, "port"), protocol))
+	}
+
+	return treeService{
+		Name:      name,
+		Type:      nestedString(svc, "spec", "type"),
+		ClusterIP: nestedString(svc, "spec", "clusterIP"),
+		Ports:     ports,
+	}
+}
+
+// sortNewestFirst orders objects by creation timestamp, newest first
+func sortNewestFirst(objects []map[string]interface{}) {
+	sort.SliceStable(objects, func(i, j int) bool {
+		return nestedString(objects[i], "metadata", "creationTimestamp") > nestedString(objects[j], "metadata", "creationTimestamp")
+	})
+}
+
+// eventTime returns the most recent timestamp recorded on an event
+func eventTime(event map[string]interface{}) string {
+	for _, path := range [][]string{{"lastTimestamp"}, {"eventTime"}, {"metadata", "creationTimestamp"}} {
+		if ts := nestedString(event, path...); ts != "" {
+			return ts
+		}
+	}
+	return ""
+}
+
+// formatSelector renders a label map as a sorted equality selector
+func formatSelector(labels map[string]string) string {
+	terms := make([]string, 0, len(labels))
+	for key, value := range labels {
+		terms = append(terms, key+"="+value)
+	}
+	sort.Strings(terms)
+	return strings.Join(terms, ",")
+}
+
+// labelsContain reports whether labels contain every selector pair
+func labelsContain(labels, selector map[string]string) bool {
+	for key, want := range selector {
+		if got, ok := labels[key]; !ok || got != want {
+			return false
+		}
+	}
+	return true
+}
+
+// objectUID returns metadata.uid of an object
+func objectUID(obj map[string]interface{}) string {
+	return nestedString(obj, "metadata", "uid")
+}
+
+// nestedValue walks a decoded JSON object along path
+func nestedValue(obj map[string]interface{}, path ...string) interface{} {
+	var current interface{} = obj
+	for _, key := range path {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		current = m[key]
+	}
+	return current
+}
+
+// nestedString returns the string at path, or "" if it is missing
+func nestedString(obj map[string]interface{}, path ...string) string {
+	s, _ := nestedValue(obj, path...).(string)
+	return s
+}
+
+// nestedInt returns the number at path, or 0 if it is missing
+func nestedInt(obj map[string]interface{}, path ...string) int64 {
+	n, _ := nestedValue(obj, path...).(float64)
+	return int64(n)
+}
+
+// nestedStringMap returns the string map at path, or nil if it is missing
+func nestedStringMap(obj map[string]interface{}, path ...string) map[string]string {
+	raw, ok := nestedValue(obj, path...).(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	result := make(map[string]string, len(raw))
+	for key, value := range raw {
+		if s, ok := value.(string); ok {
+			result[key] = s
+		}
+	}
+	return result
+}
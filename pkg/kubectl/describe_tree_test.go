@@ -0,0 +1,113 @@
+package kubectl
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestKubectlToolExecutor_DescribeTree(t *testing.T) {
+	outputs := map[string]string{
+		"kubectl get deployments web -n shop -o json": `{
+			"metadata": {"name": "web", "namespace": "shop", "uid": "dep-1"},
+			"spec": {"replicas": 2, "selector": {"matchLabels": {"app": "web"}},
+				"template": {"metadata": {"labels": {"app": "web", "tier": "frontend"}}}},
+			"status": {"readyReplicas": 1}}`,
+		"kubectl get replicasets -n shop -o json": `{"items": [
+			{"metadata": {"name": "web-6d4", "uid": "rs-1", "creationTimestamp": "2025-01-02T00:00:00Z",
+				"annotations": {"deployment.kubernetes.io/revision": "2"},
+				"ownerReferences": [{"kind": "Deployment", "name": "web", "uid": "dep-1"}]},
+				"status": {"replicas": 2, "readyReplicas": 1}},
+			{"metadata": {"name": "api-7f9", "uid": "rs-2",
+				"ownerReferences": [{"kind": "Deployment", "name": "api", "uid": "dep-2"}]}}]}`,
+		"kubectl get pods -n shop -l app=web -o json": `{"items": [
+			{"metadata": {"name": "web-6d4-a", "uid": "pod-1", "ownerReferences": [{"uid": "rs-1"}]},
+				"spec": {"nodeName": "node-1"},
+				"status": {"phase": "Running", "containerStatuses": [{"ready": true, "restartCount": 0}]}},
+			{"metadata": {"name": "web-6d4-b", "uid": "pod-2", "ownerReferences": [{"uid": "rs-1"}]},
+				"status": {"phase": "Pending", "containerStatuses": [{"ready": false, "restartCount": 3}]}},
+			{"metadata": {"name": "web-debug", "uid": "pod-3"}, "status": {"phase": "Running"}}]}`,
+		"kubectl get services -n shop -o json": `{"items": [
+			{"metadata": {"name": "web"}, "spec": {"type": "ClusterIP", "clusterIP": "10.0.0.10",
+				"selector": {"app": "web"}, "ports": [{"port": 80, "protocol": "TCP"}]}},
+			{"metadata": {"name": "api"}, "spec": {"selector": {"app": "api"}}},
+			{"metadata": {"name": "external"}, "spec": {"type": "ExternalName"}}]}`,
+		"kubectl get events -n shop -o json": `{"items": [
+			{"type": "Warning", "reason": "BackOff", "message": "Back-off restarting failed container",
+				"involvedObject": {"kind": "Pod", "name": "web-6d4-b", "uid": "pod-2"},
+				"lastTimestamp": "2025-01-02T00:05:00Z", "count": 3},
+			{"type": "Normal", "reason": "ScalingReplicaSet", "message": "Scaled up replica set web-6d4 to 2",
+				"involvedObject": {"kind": "Deployment", "name": "web", "uid": "dep-1"},
+				"lastTimestamp": "2025-01-02T00:00:00Z"},
+			{"type": "Normal", "reason": "Pulled", "involvedObject": {"kind": "Pod", "name": "api-7f9-x", "uid": "pod-9"}}]}`,
+	}
+
+	var commands []string
+	worker := newTestWorker(t, func(command string) map[string]interface{} {
+		commands = append(commands, command)
+		output, ok := outputs[command]
+		if !ok {
+			return map[string]interface{}{"error": "unexpected command: " + command}
+		}
+		return map[string]interface{}{"stdout": output}
+	})
+	executor := NewKubectlToolExecutor(worker)
+
+	result, err := executor.Execute(map[string]interface{}{
+		"_tool_name": "kubectl_diagnostics",
+		"operation":  "describe-tree",
+		"resource":   "deployment",
+		"args":       "web -n shop",
+	}, newTestConfig("readonly"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v (commands: %v)", err, commands)
+	}
+
+	var tree workloadTree
+	if err := json.Unmarshal([]byte(result), &tree); err != nil {
+		t.Fatalf("result is not JSON: %v\n%s", err, result)
+	}
+
+	if tree.Replicas != 2 || tree.ReadyReplicas != 1 {
+		t.Errorf("replicas = %d/%d, want 1/2 ready", tree.ReadyReplicas, tree.Replicas)
+	}
+	if len(tree.ReplicaSets) != 1 || tree.ReplicaSets[0].Name != "web-6d4" || tree.ReplicaSets[0].Revision != "2" {
+		t.Errorf("unexpected replicasets: %+v", tree.ReplicaSets)
+	}
+	if len(tree.Pods) != 2 || tree.Pods[1].Restarts != 3 || tree.Pods[0].Owner != "web-6d4" {
+		t.Errorf("unexpected pods: %+v", tree.Pods)
+	}
+	if len(tree.Services) != 1 || tree.Services[0].Name != "web" || tree.Services[0].Ports[0] != "80/TCP" {
+		t.Errorf("unexpected services: %+v", tree.Services)
+	}
+	if len(tree.Events) != 2 || tree.Events[0].Reason != "BackOff" || tree.Events[1].Object != "deployment/web" {
+		t.Errorf("unexpected events: %+v", tree.Events)
+	}
+}
+
+func TestKubectlToolExecutor_DescribeTreeNamespacePolicy(t *testing.T) {
+	worker := newTestWorker(t, func(command string) map[string]interface{} {
+		t.Errorf("no command should reach the agent, got %q", command)
+		return map[string]interface{}{"stdout": "{}"}
+	})
+	executor := NewKubectlToolExecutor(worker)
+
+	cfg := newTestConfig("readonly")
+	cfg.SecurityConfig.SetAllowedNamespaces("default")
+
+	for args, refused := range map[string]string{
+		"db -n kube-system":          "kube-system",
+		"db -n 'default --as=admin'": "invalid namespace",
+		"'db --as=admin'":            "invalid statefulset name",
+	} {
+		_, err := executor.Execute(map[string]interface{}{
+			"_tool_name": "kubectl_diagnostics",
+			"operation":  "describe-tree",
+			"resource":   "statefulset",
+			"args":       args,
+		}, cfg)
+		if err == nil || !strings.Contains(err.Error(), refused) {
+			t.Errorf("expected %q to be refused with %q, got %v", args, refused, err)
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package kubectl
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/Azure/mcp-kubernetes/pkg/config"
+	"gopkg.in/yaml.v3"
+)
+
+// describeYAMLMaxBytes bounds each section of a describe-yaml result
+const describeYAMLMaxBytes = 64 * 1024
+
+// lastAppliedAnnotation duplicates the whole object and is dropped from describe-yaml output
+const lastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// describeYAML returns the describe output and the cleaned YAML manifest of a single object in
+// one result. Both commands are read-only gets that go through the same access and namespace
+// checks as a direct call, and as the identity given by the as/as_group parameters.
+func (e *KubectlToolExecutor) describeYAML(resource, args string, params map[string]interface{}, cfg *config.ConfigData) (string, error) {
+	cmdline, err := parseCommandLine(args)
+	if err != nil {
+		return "", err
+	}
+
+	names := len(cmdline.positionals)
+	if strings.Contains(resource, "/") {
+		names++
+	}
+	if resource == "" || names != 1 {
+		return "", fmt.Errorf("describe-yaml requires a resource type and exactly one name")
+	}
+	if cmdline.hasFlag("--output") || cmdline.hasFlag("--selector") || cmdline.hasFlag("--all-namespaces") {
+		return "", fmt.Errorf("describe-yaml does not accept --output, --selector or --all-namespaces")
+	}
+
+	imp, err := impersonationFromParams(params)
+	if err != nil {
+		return "", err
+	}
+	target := strings.TrimSpace(resource + " " + args)
+	command := func(verb string) string {
+		return injectImpersonationNamespace(imp.apply(verb+" "+target), cfg)
+	}
+
+	description, err := e.runReadCommand(command("describe"), cfg)
+	if err != nil {
+		return "", err
+	}
+	manifest, err := e.runReadCommand(insertFlags(command("get"), []string{"-o", "yaml"}), cfg)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	b.WriteString("=== describe ===\n")
+	b.WriteString(boundSection(description))
+	b.WriteString("\n=== yaml ===\n")
+	b.WriteString(boundSection(cleanManifest(manifest, stripPaths(cfg.EditStripPaths))))
+	return b.String(), nil
+}
+
+// describeWithYAML reports whether a describe should be answered like describe-yaml: when the
+// caller passes with_yaml, or when it names one object of a kind in --describe-yaml-kinds,
+// whose describe output says little beyond the spec (ConfigMaps, for example)
+func describeWithYAML(resource, args string, params map[string]interface{}, cfg *config.ConfigData) bool {
+	if withYAML, ok := params["with_yaml"].(bool); ok {
+		return withYAML
+	}
+	if strings.TrimSpace(cfg.DescribeYAMLKinds) == "" || resource == "" {
+		return false
+	}
+	cmdline, err := parseCommandLine(args)
+	if err != nil {
+		return false
+	}
+	names := len(cmdline.positionals)
+	if strings.Contains(resource, "/") {
+		names++
+	}
+	if names != 1 || cmdline.hasFlag("--output") || cmdline.hasFlag("--selector") || cmdline.hasFlag("--all-namespaces") {
+		return false
+	}
+
+	catalog := cfg.SecurityConfig.Resources()
+	described, ok := catalog.Lookup(resource)
+	if !ok {
+		return false
+	}
+	for _, kind := range strings.Split(cfg.DescribeYAMLKinds, ",") {
+		if listed, ok := catalog.Lookup(strings.TrimSpace(kind)); ok && listed.Name == described.Name && listed.Group == described.Group {
+			return true
+		}
+	}
+	return false
+}
+
+// stripPaths splits --edit-strip-paths into dotted paths, e.g. "metadata.uid"
+func stripPaths(list string) [][]string {
+	var paths [][]string
+	for _, path := range strings.Split(list, ",") {
+		if path = strings.TrimSpace(path); path != "" {
+			paths = append(paths, strings.Split(path, "."))
+		}
+	}
+	return paths
+}
+
+// cleanManifest removes server-managed noise from an object's YAML: the fields at paths and
+// the last-applied-configuration annotation. It returns the input unchanged if it can't be
+// parsed.
+func cleanManifest(manifest string, paths [][]string) string {
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(manifest), &doc); err != nil || len(doc.Content) == 0 {
+		return manifest
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return manifest
+	}
+
+	for _, path := range paths {
+		removePath(root, path)
+	}
+	if metadata := mappingValue(root, "metadata"); metadata != nil {
+		if annotations := mappingValue(metadata, "annotations"); annotations != nil {
+			removeKeys(annotations, func(key string) bool { return key == lastAppliedAnnotation })
+			if len(annotations.Content) == 0 {
+				removeKeys(metadata, func(key string) bool { return key == "annotations" })
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return manifest
+	}
+	return buf.String()
+}
+
+// removePath drops the field at a dotted path below a YAML mapping, if it is there
+func removePath(mapping *yaml.Node, path []string) {
+	for _, key := range path[:len(path)-1] {
+		if mapping = mappingValue(mapping, key); mapping == nil {
+			return
+		}
+	}
+	last := path[len(path)-1]
+	removeKeys(mapping, func(key string) bool { return key == last })
+}
+
+// mappingValue returns the value node for key in a YAML mapping, or nil
+func mappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key && mapping.Content[i+1].Kind == yaml.MappingNode {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// removeKeys drops the entries of a YAML mapping whose key matches
+func removeKeys(mapping *yaml.Node, match func(key string) bool) {
+	kept := mapping.Content[:0]
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if !match(mapping.Content[i].Value) {
+			kept = append(kept, mapping.Content[i], mapping.Content[i+1])
+		}
+	}
+	mapping.Content = kept
+}
+
+// boundSection truncates a section to describeYAMLMaxBytes at a line boundary
+func boundSection(section string) string {
+	if !strings.HasSuffix(section, "\n") {
+		section += "\n"
+	}
+	if len(section) <= describeYAMLMaxBytes {
+		return section
+	}
+
+	cut := strings.LastIndex(section[:describeYAMLMaxBytes], "\n") + 1
+	if cut == 0 {
+		cut = describeYAMLMaxBytes
+	}
+	return section[:cut] + fmt.Sprintf("... truncated: %d bytes omitted\n", len(section)-cut)
+}
@@ -0,0 +1,213 @@
+package kubectl
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Azure/mcp-kubernetes/pkg/config"
+)
+
+func TestKubectlToolExecutor_DescribeYAML(t *testing.T) {
+	outputs := map[string]string{
+		"kubectl describe pod web-0 -n shop": "Name:         web-0\nNamespace:    shop\nStatus:       Running\n",
+		"kubectl get pod web-0 -n shop -o yaml": `apiVersion: v1
+kind: Pod
+metadata:
+  annotations:
+    kubectl.kubernetes.io/last-applied-configuration: |
+      {"apiVersion":"v1","kind":"Pod"}
+  managedFields:
+  - manager: kubectl
+  name: web-0
+  namespace: shop
+  resourceVersion: "1234"
+spec:
+  containers:
+  - image: nginx:1.25
+    name: web
+status:
+  phase: Running
+`,
+	}
+
+	var commands []string
+	worker := newTestWorker(t, func(command string) map[string]interface{} {
+		commands = append(commands, command)
+		output, ok := outputs[command]
+		if !ok {
+			return map[string]interface{}{"error": "unexpected command: " + command}
+		}
+		return map[string]interface{}{"stdout": output}
+	})
+	executor := NewKubectlToolExecutor(worker)
+
+	result, err := executor.Execute(map[string]interface{}{
+		"_tool_name": "kubectl_resources",
+		"operation":  "describe-yaml",
+		"resource":   "pod",
+		"args":       "web-0 -n shop",
+	}, newTestConfig("readonly"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v (commands: %v)", err, commands)
+	}
+
+	describe := strings.Index(result, "=== describe ===\n")
+	manifest := strings.Index(result, "\n=== yaml ===\n")
+	if describe != 0 || manifest < 0 {
+		t.Fatalf("expected describe and yaml sections, got:\n%s", result)
+	}
+	if !strings.Contains(result[:manifest], "Status:       Running") {
+		t.Errorf("describe section missing describe output:\n%s", result)
+	}
+
+	yamlSection := result[manifest:]
+	for _, want := range []string{"name: web-0", "image: nginx:1.25"} {
+		if !strings.Contains(yamlSection, want) {
+			t.Errorf("yaml section missing %q:\n%s", want, yamlSection)
+		}
+	}
+	for _, unwanted := range []string{"managedFields", "resourceVersion", "last-applied-configuration", "annotations", "status:"} {
+		if strings.Contains(yamlSection, unwanted) {
+			t.Errorf("yaml section should not contain %q:\n%s", unwanted, yamlSection)
+		}
+	}
+}
+
+func TestKubectlToolExecutor_DescribeYAMLRejected(t *testing.T) {
+	tests := []struct {
+		name     string
+		resource string
+		args     string
+		errMsg   string
+	}{
+		{"no name", "pods", "-n shop", "exactly one name"},
+		{"several names", "pods", "web-0 web-1", "exactly one name"},
+		{"selector", "pods", "web-0 -l app=web", "does not accept"},
+		{"namespace not allowed", "pod", "web-0 -n kube-system", "namespace"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			worker := newTestWorker(t, func(command string) map[string]interface{} {
+				t.Errorf("unexpected command: %s", command)
+				return map[string]interface{}{"stdout": ""}
+			})
+			executor := NewKubectlToolExecutor(worker)
+			cfg := newTestConfig("readonly")
+			cfg.SecurityConfig.SetAllowedNamespaces("shop")
+
+			_, err := executor.Execute(map[string]interface{}{
+				"_tool_name": "kubectl_resources",
+				"operation":  "describe-yaml",
+				"resource":   tt.resource,
+				"args":       tt.args,
+			}, cfg)
+			if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("Execute() error = %v, want error containing %q", err, tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestKubectlToolExecutor_DescribeWithYAML(t *testing.T) {
+	outputs := map[string]string{
+		"kubectl describe configmap settings -n shop":       "Name:         settings\nNamespace:    shop\n\nData\n====\nmode:\n----\nfast\n",
+		"kubectl get configmap settings -n shop -o yaml":    "apiVersion: v1\ndata:\n  mode: fast\nkind: ConfigMap\nmetadata:\n  name: settings\n  namespace: shop\n",
+		"kubectl describe configmap settings other -n shop": "Name:         settings\n\nName:         other\n",
+		"kubectl describe deployment web -n shop":           "Name:         web\n",
+	}
+	worker := newTestWorker(t, func(command string) map[string]interface{} {
+		output, ok := outputs[command]
+		if !ok {
+			return map[string]interface{}{"error": "unexpected command: " + command}
+		}
+		return map[string]interface{}{"stdout": output}
+	})
+	executor := NewKubectlToolExecutor(worker)
+
+	describe := func(cfg *config.ConfigData, resource, args string, withYAML interface{}) string {
+		t.Helper()
+		params := map[string]interface{}{
+			"_tool_name": "kubectl_resources",
+			"operation":  "describe",
+			"resource":   resource,
+			"args":       args,
+		}
+		if withYAML != nil {
+			params["with_yaml"] = withYAML
+		}
+		result, err := executor.Execute(params, cfg)
+		if err != nil {
+			t.Fatalf("unexpected error describing %s %s: %v", resource, args, err)
+		}
+		return result
+	}
+
+	// with_yaml adds the manifest to a describe
+	cfg := newTestConfig("readonly")
+	result := describe(cfg, "configmap", "settings -n shop", true)
+	if !strings.HasPrefix(result, "=== describe ===\n") || !strings.Contains(result, "\n=== yaml ===\n") {
+		t.Fatalf("expected describe and yaml sections, got:\n%s", result)
+	}
+	if !strings.Contains(result, "data:\n  mode: fast") {
+		t.Errorf("yaml section missing the configmap data:\n%s", result)
+	}
+
+	// --describe-yaml-kinds does the same for the listed kinds, by any of their names
+	cfg.DescribeYAMLKinds = "cm"
+	if result := describe(cfg, "configmap", "settings -n shop", nil); !strings.Contains(result, "data:\n  mode: fast") {
+		t.Errorf("expected the yaml for a listed kind, got:\n%s", result)
+	}
+	if result := describe(cfg, "configmap", "settings -n shop", false); strings.Contains(result, "=== yaml ===") {
+		t.Errorf("with_yaml=false should keep the plain describe, got:\n%s", result)
+	}
+	if result := describe(cfg, "configmap", "settings other -n shop", nil); strings.Contains(result, "=== yaml ===") {
+		t.Errorf("a describe of several objects should stay plain, got:\n%s", result)
+	}
+	if result := describe(cfg, "deployment", "web -n shop", nil); strings.Contains(result, "=== yaml ===") {
+		t.Errorf("an unlisted kind should stay plain, got:\n%s", result)
+	}
+}
+
+func TestBoundSection(t *testing.T) {
+	section := strings.Repeat("0123456789abcde\n", describeYAMLMaxBytes/16+10)
+	bounded := boundSection(section)
+	if len(bounded) > describeYAMLMaxBytes+64 {
+		t.Errorf("section not bounded: %d bytes", len(bounded))
+	}
+	if !strings.HasSuffix(bounded, "bytes omitted\n") {
+		t.Errorf("expected truncation marker, got %q", bounded[len(bounded)-40:])
+	}
+}
+
+func TestCleanManifestStripPaths(t *testing.T) {
+	manifest := `apiVersion: v1
+kind: ConfigMap
+metadata:
+  creationTimestamp: "2024-01-01T00:00:00Z"
+  labels:
+    app: web
+    team: shop
+  name: settings
+  resourceVersion: "1234"
+  uid: 0b6e2b8c
+data:
+  mode: fast
+`
+	want := `apiVersion: v1
+kind: ConfigMap
+metadata:
+  creationTimestamp: "2024-01-01T00:00:00Z"
+  labels:
+    app: web
+  name: settings
+  resourceVersion: "1234"
+data: {}
+`
+	if got := cleanManifest(manifest, stripPaths("metadata.uid, metadata.labels.team,data.mode,spec.replicas")); got != want {
+		t.Errorf("expected:\n%s\ngot:\n%s", want, got)
+	}
+	if got := cleanManifest(manifest, stripPaths("")); got != manifest {
+		t.Errorf("expected an empty list to keep every field, got:\n%s", got)
+	}
+}
@@ -0,0 +1,85 @@
+package kubectl
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/Azure/mcp-kubernetes/pkg/config"
+)
+
+// diagnosticCase is one call of a kubectl_diagnostics operation
+type diagnosticCase struct {
+	name     string
+	resource string
+	args     string
+	// params are further parameters of the call, such as older_than
+	params map[string]interface{}
+	// cfg replaces the test's configuration for this call
+	cfg *config.ConfigData
+	// refused, when set, is text the call's error must contain; a refused call runs no command
+	refused string
+	// check inspects the output of a call that succeeded and the commands it ran
+	check func(t *testing.T, output string, commands []string)
+}
+
+// runDiagnosticCases runs each case as a call of operation against a worker that answers the
+// commands in replies with their stdout and fails any other command
+func runDiagnosticCases(t *testing.T, operation string, cfg *config.ConfigData, replies map[string]string, cases []diagnosticCase) {
+	t.Helper()
+	var commands []string
+	worker := newTestWorker(t, func(command string) map[string]interface{} {
+		commands = append(commands, command)
+		if stdout, ok := replies[command]; ok {
+			return map[string]interface{}{"stdout": stdout}
+		}
+		return map[string]interface{}{"error": "unexpected command: " + command}
+	})
+	executor := NewKubectlToolExecutor(worker)
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			commands = nil
+			params := map[string]interface{}{
+				"_tool_name": "kubectl_diagnostics",
+				"operation":  operation,
+				"resource":   tc.resource,
+				"args":       tc.args,
+			}
+			for name, value := range tc.params {
+				params[name] = value
+			}
+			callCfg := cfg
+			if tc.cfg != nil {
+				callCfg = tc.cfg
+			}
+
+			output, err := executor.Execute(params, callCfg)
+			if tc.refused != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.refused) {
+					t.Fatalf("expected an error containing %q, got %v", tc.refused, err)
+				}
+				if len(commands) != 0 {
+					t.Errorf("expected a refused call to run nothing, ran %q", commands)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v (commands: %q)", err, commands)
+			}
+			if tc.check != nil {
+				tc.check(t, output, commands)
+			}
+		})
+	}
+}
+
+// decodeReport decodes the JSON report of a diagnostic operation
+func decodeReport[T any](t *testing.T, output string) T {
+	t.Helper()
+	var report T
+	if err := json.Unmarshal([]byte(output), &report); err != nil {
+		t.Fatalf("expected JSON, got %q: %v", output, err)
+	}
+	return report
+}
@@ -0,0 +1,343 @@
+package kubectl
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+)
+
+var errMalformedDiff = errors.New("malformed diff output")
+
+// diffEntry is the planned change to a single resource in `kubectl diff` output
+type diffEntry struct {
+	Resource   string        `json:"resource"`
+	Namespace  string        `json:"namespace,omitempty"`
+	ChangeType string        `json:"change_type"` // create, update or delete
+	Changes    []fieldChange `json:"changes"`
+}
+
+// fieldChange is a changed leaf field; Old is null for added fields and New for removed ones
+type fieldChange struct {
+	Path string  `json:"path"`
+	Old  *string `json:"old"`
+	New  *string `json:"new"`
+}
+
+// diffFile accumulates the hunks of one resource while parsing
+type diffFile struct {
+	entry    diffEntry
+	oldLines int
+	newLines int
+	changes  map[string]*fieldChange
+	order    []string
+	oldPath  yamlPath
+	newPath  yamlPath
+	sawHunk  bool
+}
+
+// parseDiff turns unified `kubectl diff` output into a list of per-resource changes. Field
+// paths are rebuilt from the indentation of the lines in each hunk, so fields whose parents
+// fall outside the hunk context get a path relative to the first key that is visible.
+func parseDiff(output string) (interface{}, error) {
+	entries := []diffEntry{}
+	if strings.TrimSpace(output) == "" {
+		return entries, nil
+	}
+
+	var current *diffFile
+	finish := func() error {
+		if current == nil {
+			return nil
+		}
+		if !current.sawHunk {
+			return errMalformedDiff
+		}
+		entries = append(entries, current.result())
+		current = nil
+		return nil
+	}
+
+	for _, line := range strings.Split(strings.TrimRight(output, "\n"), "\n") {
+		switch {
+		case strings.HasPrefix(line, "diff "):
+			if err := finish(); err != nil {
+				return nil, err
+			}
+			current = newDiffFile(lastField(line))
+		case strings.HasPrefix(line, "--- "):
+			if current == nil || current.sawHunk {
+				if err := finish(); err != nil {
+					return nil, err
+				}
+				current = newDiffFile(headerPath(line))
+			}
+		case strings.HasPrefix(line, "+++ "):
+			if current == nil {
+				return nil, errMalformedDiff
+			}
+			if current.entry.Resource == "" {
+				current.setResource(headerPath(line))
+			}
+		case strings.HasPrefix(line, "@@"):
+			if current == nil {
+				return nil, errMalformedDiff
+			}
+			oldCount, newCount, err := parseHunkHeader(line)
+			if err != nil {
+				return nil, err
+			}
+			current.oldLines += oldCount
+			current.newLines += newCount
+			current.sawHunk = true
+			current.oldPath = yamlPath{}
+			current.newPath = yamlPath{}
+		case strings.HasPrefix(line, `\`):
+			// "\ No newline at end of file"
+		case current != nil && current.sawHunk && line != "":
+			current.addLine(line[0], line[1:])
+		case current != nil && current.sawHunk:
+			// blank context line
+		default:
+			return nil, errMalformedDiff
+		}
+	}
+
+	if err := finish(); err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, errMalformedDiff
+	}
+	return entries, nil
+}
+
+func newDiffFile(file string) *diffFile {
+	f := &diffFile{changes: make(map[string]*fieldChange)}
+	f.setResource(file)
+	return f
+}
+
+// setResource derives the resource from kubectl's temp file name, which has the form
+// <group>.<version>.<Kind>.<namespace>.<name> (group omitted for the core API)
+func (f *diffFile) setResource(file string) {
+	base := path.Base(file)
+	if base == "." || base == "/" || base == "null" {
+		return
+	}
+
+	parts := strings.Split(base, ".")
+	for i, part := range parts {
+		if part == "" || part[0] < 'A' || part[0] > 'Z' || i+2 >= len(parts) {
+			continue
+		}
+		// The kind is the first capitalized segment; the name may itself contain dots
+		f.entry.Resource = part + "/" + strings.Join(parts[i+2:], ".")
+		f.entry.Namespace = parts[i+1]
+		return
+	}
+	f.entry.Resource = base
+}
+
+// addLine records a context, removed or added line of a hunk
+func (f *diffFile) addLine(marker byte, text string) {
+	switch marker {
+	case ' ':
+		f.oldPath.push(text)
+		f.newPath.push(text)
+	case '-':
+		if key, value, ok := f.oldPath.push(text); ok {
+			f.record(key).Old = &value
+		}
+	case '+':
+		if key, value, ok := f.newPath.push(text); ok {
+			f.record(key).New = &value
+		}
+	}
+}
+
+// record returns the change for a path, creating it in order of first appearance
+func (f *diffFile) record(path string) *fieldChange {
+	change, ok := f.changes[path]
+	if !ok {
+		change = &fieldChange{Path: path}
+		f.changes[path] = change
+		f.order = append(f.order, path)
+	}
+	return change
+}
+
+// result classifies the file and returns its entry. Created and deleted resources list no
+// field changes since every field would be listed.
+func (f *diffFile) result() diffEntry {
+	entry := f.entry
+	entry.Changes = []fieldChange{}
+
+	switch {
+	case f.oldLines == 0 && f.newLines > 0:
+		entry.ChangeType = "create"
+	case f.newLines == 0 && f.oldLines > 0:
+		entry.ChangeType = "delete"
+	default:
+		entry.ChangeType = "update"
+		for _, p := range f.order {
+			change := f.changes[p]
+			if change.Old != nil && change.New != nil && *change.Old == *change.New {
+				continue
+			}
+			entry.Changes = append(entry.Changes, *change)
+		}
+	}
+	return entry
+}
+
+// yamlPath tracks the position of lines in a YAML document by indentation
+type yamlPath struct {
+	levels []yamlLevel
+}
+
+type yamlLevel struct {
+	indent int
+	key    string
+	items  int // list items seen at this level
+}
+
+// push adds a YAML line to the path. For a leaf "key: value" or "- value" line it returns
+// the full path of the field and its value.
+func (p *yamlPath) push(line string) (string, string, bool) {
+	trimmed := strings.TrimLeft(line, " ")
+	if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+		return "", "", false
+	}
+	indent := len(line) - len(trimmed)
+
+	// List items add an index level, then the item content is parsed as a key at its own indent.
+	// Sequences may be indented at the same level as their parent key, so only deeper levels
+	// and the previous item are dropped.
+	if strings.HasPrefix(trimmed, "- ") || trimmed == "-" {
+		p.pop(indent + 1)
+		index := 0
+		if n := len(p.levels); n > 0 && p.levels[n-1].indent == indent && strings.HasPrefix(p.levels[n-1].key, "[") {
+			index = p.levels[n-1].items + 1
+			p.levels = p.levels[:n-1]
+		}
+		p.levels = append(p.levels, yamlLevel{indent: indent, key: fmt.Sprintf("[%d]", index), items: index})
+
+		item := strings.TrimPrefix(strings.TrimPrefix(trimmed, "-"), " ")
+		if item == "" {
+			return "", "", false
+		}
+		if _, _, isKey := splitYAMLKey(item); !isKey {
+			return p.String(), unquoteYAML(item), true
+		}
+		return p.push(strings.Repeat(" ", indent+2) + item)
+	}
+
+	key, value, ok := splitYAMLKey(trimmed)
+	if !ok {
+		return "", "", false
+	}
+	p.pop(indent)
+	p.levels = append(p.levels, yamlLevel{indent: indent, key: key})
+
+	if value == "" || value == "|" || value == ">" || value == "|-" || value == ">-" {
+		return "", "", false
+	}
+	return p.String(), unquoteYAML(value), true
+}
+
+// pop removes levels at or deeper than indent
+func (p *yamlPath) pop(indent int) {
+	for len(p.levels) > 0 && p.levels[len(p.levels)-1].indent >= indent {
+		p.levels = p.levels[:len(p.levels)-1]
+	}
+}
+
+// String renders the path as dotted keys with list indexes, e.g. spec.containers[0].image
+func (p *yamlPath) String() string {
+	var b strings.Builder
+	for _, level := range p.levels {
+		if !strings.HasPrefix(level.key, "[") && b.Len() > 0 {
+			b.WriteByte('.')
+		}
+		b.WriteString(level.key)
+	}
+	return b.String()
+}
+
+// splitYAMLKey splits "key: value" (or "key:"), reporting false for lines that aren't a mapping key
+func splitYAMLKey(text string) (string, string, bool) {
+	if strings.HasPrefix(text, `"`) || strings.HasPrefix(text, `'`) {
+		quote := text[:1]
+		end := strings.Index(text[1:], quote+":")
+		if end < 0 {
+			return "", "", false
+		}
+		return text[1 : end+1], strings.TrimSpace(text[end+3:]), true
+	}
+
+	if strings.HasSuffix(text, ":") {
+		return strings.TrimSuffix(text, ":"), "", true
+	}
+	key, value, ok := strings.Cut(text, ": ")
+	if !ok || key == "" {
+		return "", "", false
+	}
+	return key, strings.TrimSpace(value), true
+}
+
+// unquoteYAML strips matching quotes from a scalar
+func unquoteYAML(value string) string {
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
+
+// parseHunkHeader returns the old and new line counts from "@@ -a,b +c,d @@"
+func parseHunkHeader(line string) (int, int, error) {
+	fields := strings.Fields(line)
+	if len(fields) < 3 || !strings.HasPrefix(fields[1], "-") || !strings.HasPrefix(fields[2], "+") {
+		return 0, 0, errMalformedDiff
+	}
+
+	count := func(r string) (int, error) {
+		_, n, ok := strings.Cut(r[1:], ",")
+		if !ok {
+			return 1, nil
+		}
+		var c int
+		if _, err := fmt.Sscanf(n, "%d", &c); err != nil {
+			return 0, errMalformedDiff
+		}
+		return c, nil
+	}
+
+	oldCount, err := count(fields[1])
+	if err != nil {
+		return 0, 0, err
+	}
+	newCount, err := count(fields[2])
+	if err != nil {
+		return 0, 0, err
+	}
+	return oldCount, newCount, nil
+}
+
+// headerPath returns the file path from a "--- path<TAB>timestamp" header
+func headerPath(line string) string {
+	file := strings.TrimSpace(line[4:])
+	if before, _, ok := strings.Cut(file, "\t"); ok {
+		file = before
+	}
+	return file
+}
+
+// lastField returns the last whitespace-separated field of a line
+func lastField(line string) string {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[len(fields)-1]
+}
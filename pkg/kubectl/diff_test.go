@@ -0,0 +1,214 @@
+package kubectl
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const fieldChangeDiff = `diff -u -N /tmp/LIVE-1234/apps.v1.Deployment.default.web /tmp/MERGED-5678/apps.v1.Deployment.default.web
+--- /tmp/LIVE-1234/apps.v1.Deployment.default.web	2025-01-02 10:00:00.000000000 +0000
++++ /tmp/MERGED-5678/apps.v1.Deployment.default.web	2025-01-02 10:00:00.000000000 +0000
+@@ -6,7 +6,7 @@
+   name: web
+   namespace: default
+ spec:
+-  replicas: 2
++  replicas: 3
+   selector:
+     matchLabels:
+       app: web
+@@ -20,9 +20,10 @@
+     spec:
+       containers:
+       - name: web
+-        image: nginx:1.25
++        image: nginx:1.27
+         ports:
+         - containerPort: 80
++      serviceAccountName: web
+       restartPolicy: Always
+`
+
+const newResourceDiff = `diff -u -N /tmp/LIVE-1234/v1.ConfigMap.shop.settings /tmp/MERGED-5678/v1.ConfigMap.shop.settings
+--- /tmp/LIVE-1234/v1.ConfigMap.shop.settings	1970-01-01 00:00:00.000000000 +0000
++++ /tmp/MERGED-5678/v1.ConfigMap.shop.settings	2025-01-02 10:00:00.000000000 +0000
+@@ -0,0 +1,7 @@
++apiVersion: v1
++data:
++  mode: production
++kind: ConfigMap
++metadata:
++  name: settings
++  namespace: shop
+`
+
+func TestParseDiff(t *testing.T) {
+	value, err := parseDiff(fieldChangeDiff + newResourceDiff)
+	if err != nil {
+		t.Fatalf("parseDiff() error = %v", err)
+	}
+	entries := value.([]diffEntry)
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+
+	update := entries[0]
+	if update.Resource != "Deployment/web" || update.Namespace != "default" || update.ChangeType != "update" {
+		t.Errorf("unexpected update entry: %+v", update)
+	}
+	expected := []struct{ path, old, new string }{
+		{"spec.replicas", "2", "3"},
+		{"spec.containers[0].image", "nginx:1.25", "nginx:1.27"},
+		{"spec.serviceAccountName", "", "web"},
+	}
+	if len(update.Changes) != len(expected) {
+		data, _ := json.Marshal(update.Changes)
+		t.Fatalf("expected %d changes, got %s", len(expected), data)
+	}
+	for i, want := range expected {
+		got := update.Changes[i]
+		if got.Path != want.path {
+			t.Errorf("change %d path = %q, want %q", i, got.Path, want.path)
+		}
+		if (want.old == "" && got.Old != nil) || (want.old != "" && (got.Old == nil || *got.Old != want.old)) {
+			t.Errorf("change %d old = %v, want %q", i, got.Old, want.old)
+		}
+		if got.New == nil || *got.New != want.new {
+			t.Errorf("change %d new = %v, want %q", i, got.New, want.new)
+		}
+	}
+
+	create := entries[1]
+	if create.Resource != "ConfigMap/settings" || create.Namespace != "shop" || create.ChangeType != "create" {
+		t.Errorf("unexpected create entry: %+v", create)
+	}
+}
+
+func TestParseDiffFallback(t *testing.T) {
+	// No differences parse to an empty list
+	value, err := parseDiff("")
+	if err != nil || len(value.([]diffEntry)) != 0 {
+		t.Errorf("expected empty list for no differences, got %v, %v", value, err)
+	}
+
+	// Anything that isn't a unified diff falls back to the raw output
+	raw := "error: the path \"manifest.yaml\" does not exist"
+	if got := formatStructured("diff -f manifest.yaml", raw); got != raw {
+		t.Errorf("expected raw output on parse failure, got %q", got)
+	}
+}
+
+func TestKubectlToolExecutor_StructuredDiff(t *testing.T) {
+	// kubectl diff exits with 1 when differences are found
+	worker := newTestWorker(t, func(command string) map[string]interface{} {
+		return map[string]interface{}{"stdout": newResourceDiff, "exit_code": float64(1)}
+	})
+	executor := NewKubectlToolExecutor(worker)
+
+	output, err := executor.Execute(map[string]interface{}{
+		"_tool_name": "kubectl_config",
+		"operation":  "diff",
+		"resource":   "",
+		"args":       "-f settings.yaml",
+		"structured": true,
+	}, newTestConfig("readonly"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var entries []diffEntry
+	if err := json.Unmarshal([]byte(output), &entries); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, output)
+	}
+	if len(entries) != 1 || entries[0].ChangeType != "create" {
+		t.Errorf("unexpected entries: %+v", entries)
+	}
+}
+
+func TestKubectlToolExecutor_ErrOutputOperations(t *testing.T) {
+	tests := []struct {
+		name      string
+		toolName  string
+		operation string
+		resource  string
+		args      string
+		access    string
+		reply     map[string]interface{}
+		want      string
+		errMsg    string
+	}{
+		{
+			name:      "diff with changes",
+			toolName:  "kubectl_config",
+			operation: "diff",
+			args:      "-f settings.yaml",
+			access:    "readonly",
+			reply:     map[string]interface{}{"stdout": newResourceDiff, "exit_code": float64(1)},
+			want:      newResourceDiff,
+		},
+		{
+			name:      "auth can-i answering no",
+			toolName:  "kubectl_config",
+			operation: "auth",
+			resource:  "can-i",
+			args:      "delete pods -n shop",
+			access:    "readonly",
+			reply:     map[string]interface{}{"stdout": "no\n", "exit_code": float64(1)},
+			want:      "no\n",
+		},
+		{
+			name:      "failed apply",
+			toolName:  "kubectl_resources",
+			operation: "apply",
+			args:      "-f deploy.yaml",
+			access:    "readwrite",
+			reply:     map[string]interface{}{"stdout": "", "stderr": "error: the path \"deploy.yaml\" does not exist", "exit_code": float64(1)},
+			errMsg:    "does not exist",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			worker := newTestWorker(t, func(command string) map[string]interface{} {
+				return tt.reply
+			})
+			executor := NewKubectlToolExecutor(worker)
+
+			output, err := executor.Execute(map[string]interface{}{
+				"_tool_name": tt.toolName,
+				"operation":  tt.operation,
+				"resource":   tt.resource,
+				"args":       tt.args,
+			}, newTestConfig(tt.access))
+			if tt.errMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+					t.Fatalf("expected error containing %q, got %v (output %q)", tt.errMsg, err, output)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if output != tt.want {
+				t.Errorf("output = %q, want %q", output, tt.want)
+			}
+		})
+	}
+}
+
+func TestReturnsErrOutput(t *testing.T) {
+	tests := map[string]bool{
+		"diff -f manifest.yaml":         true,
+		"kubectl diff -f -":             true,
+		"auth can-i delete pods -n dev": true,
+		"auth whoami":                   false,
+		"apply -f manifest.yaml":        false,
+		"get pods":                      false,
+	}
+	for command, expected := range tests {
+		if got := returnsErrOutput(command); got != expected {
+			t.Errorf("returnsErrOutput(%q) = %v, want %v", command, got, expected)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package kubectl
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/Azure/mcp-kubernetes/pkg/config"
+	"github.com/Azure/mcp-kubernetes/pkg/security"
+)
+
+// APIDiscoveryInterval is how often the resource catalog is refreshed from api-resources
+const APIDiscoveryInterval = 10 * time.Minute
+
+// EnableAPIDiscovery populates the security configuration's resource catalog from
+// `kubectl api-resources` now and every interval after, so custom resources are classified
+// as namespaced or cluster-scoped. Until discovery succeeds only the built-in types are
+// known. Closing the returned channel stops the refreshes.
+func (e *KubectlToolExecutor) EnableAPIDiscovery(cfg *config.ConfigData, interval time.Duration) chan<- struct{} {
+	stop := make(chan struct{})
+	go func() {
+		e.refreshAPIResources(cfg)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				e.refreshAPIResources(cfg)
+			case <-stop:
+				return
+			}
+		}
+	}()
+	return stop
+}
+
+// refreshAPIResources replaces the resource catalog with the cluster's api-resources,
+// keeping the previous contents if discovery fails
+func (e *KubectlToolExecutor) refreshAPIResources(cfg *config.ConfigData) {
+	if err := e.discoverAPIResources(cfg); err != nil {
+		slog.Error("api resource discovery failed", "err", err)
+	}
+}
+
+// discoverAPIResources lists the cluster's resource types into the resource catalog
+func (e *KubectlToolExecutor) discoverAPIResources(cfg *config.ConfigData) error {
+	output, err := e.executor.executeKubectlCommandOnHost("api-resources", "", cfg)
+	if err != nil {
+		return err
+	}
+
+	resources, err := security.ParseAPIResources(output)
+	if err != nil {
+		return fmt.Errorf("failed to parse api-resources: %w", err)
+	}
+	cfg.SecurityConfig.Resources().Replace(resources)
+	return nil
+}
@@ -0,0 +1,56 @@
+package kubectl
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestKubectlToolExecutor_APIDiscovery(t *testing.T) {
+	var commands []string
+	worker := newTestWorker(t, func(command string) map[string]interface{} {
+		commands = append(commands, command)
+		if command == "kubectl api-resources" {
+			return map[string]interface{}{"stdout": `NAME             SHORTNAMES   APIVERSION           NAMESPACED   KIND
+pods             po           v1                   true         Pod
+certificates     cert         cert-manager.io/v1   true         Certificate
+clusterissuers                cert-manager.io/v1   false        ClusterIssuer
+`}
+		}
+		return map[string]interface{}{"stdout": "deleted"}
+	})
+	executor := NewKubectlToolExecutor(worker)
+	cfg := newTestConfig("readwrite")
+	cfg.SecurityConfig.SetAllowedNamespaces("shop")
+
+	deleteResource := func(resource, args string) error {
+		_, err := executor.Execute(map[string]interface{}{
+			"_tool_name": "kubectl_resources",
+			"operation":  "delete",
+			"resource":   resource,
+			"args":       args,
+		}, cfg)
+		return err
+	}
+
+	// The custom resource is unknown until discovery runs
+	if err := deleteResource("certificates", "web -n shop"); err == nil || !strings.Contains(err.Error(), "unknown resource type") {
+		t.Fatalf("expected unknown resource type to be rejected, got %v", err)
+	}
+
+	if err := executor.discoverAPIResources(cfg); err != nil {
+		t.Fatalf("discovery failed: %v", err)
+	}
+	if !cfg.SecurityConfig.Resources().Discovered() {
+		t.Error("catalog should be marked as discovered")
+	}
+
+	if err := deleteResource("certificates", "web -n shop"); err != nil {
+		t.Errorf("namespaced custom resource should be allowed, got %v", err)
+	}
+	if err := deleteResource("clusterissuers", "letsencrypt"); err == nil || !strings.Contains(err.Error(), "cluster-scoped") {
+		t.Errorf("cluster-scoped custom resource should be rejected, got %v", err)
+	}
+	if got := commands[len(commands)-1]; got != "kubectl delete certificates web -n shop" {
+		t.Errorf("unexpected last command %q", got)
+	}
+}
@@ -0,0 +1,224 @@
+package kubectl
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Azure/mcp-kubernetes/pkg/config"
+)
+
+// nodeName matches a node name, which is put into a field selector
+var nodeName = regexp.MustCompile(`^[a-z0-9]([-a-z0-9.]*[a-z0-9])?$`)
+
+// drainCheck predicts whether draining a node would be held up by PodDisruptionBudgets
+type drainCheck struct {
+	Node string `json:"node"`
+	// Blocked is set when some pod on the node can't be evicted now
+	Blocked bool            `json:"blocked"`
+	Pods    []drainPod      `json:"pods"`
+	PDBs    []drainPDBState `json:"pdbs"`
+	// UncheckedPods counts the pods on the node in namespaces outside the allow-list, whose
+	// budgets aren't read
+	UncheckedPods int `json:"unchecked_pods,omitempty"`
+}
+
+// drainPod is a pod on the node and the budgets that govern its eviction
+type drainPod struct {
+	Namespace string   `json:"namespace"`
+	Name      string   `json:"name"`
+	PDBs      []string `json:"pdbs"`
+	// Skipped says why drain leaves the pod alone ("daemonset" or "mirror pod")
+	Skipped string `json:"skipped,omitempty"`
+	Blocked bool   `json:"blocked"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+// drainPDBState is a budget governing pods on the node
+type drainPDBState struct {
+	Namespace          string `json:"namespace"`
+	Name               string `json:"name"`
+	DisruptionsAllowed int64  `json:"disruptions_allowed"`
+	CurrentHealthy     int64  `json:"current_healthy"`
+	DesiredHealthy     int64  `json:"desired_healthy"`
+	ExpectedPods       int64  `json:"expected_pods"`
+	// PodsOnNode counts the pods on the node the budget governs; drain evicts them one at
+	// a time, waiting while the budget allows no more disruptions
+	PodsOnNode int `json:"pods_on_node"`
+}
+
+// drainCheck lists the pods on a node and the PodDisruptionBudgets governing them, with each
+// budget's allowed disruptions, so an agent can tell whether `kubectl drain` would block. A
+// pod is blocked when a budget allows no disruptions or more than one budget selects it, which
+// the eviction API refuses. DaemonSet and mirror pods, which drain doesn't evict, are marked
+// skipped. Pods are listed across namespaces as for node-capacity; budgets are only read in
+// allowed namespaces, and pods elsewhere are counted but not named.
+func (e *KubectlToolExecutor) drainCheck(args string, cfg *config.ConfigData) (string, error) {
+	cmdline, err := parseCommandLine(args)
+	if err != nil {
+		return "", err
+	}
+	if len(cmdline.positionals) != 1 || len(cmdline.flags) > 0 || len(cmdline.trailing) > 0 {
+		return "", fmt.Errorf("drain-check requires exactly one node name, e.g. args='aks-nodepool1-0'")
+	}
+	node := cmdline.positionals[0]
+	if kind, name, ok := strings.Cut(node, "/"); ok {
+		if kind != "node" && kind != "nodes" && kind != "no" {
+			return "", fmt.Errorf("drain-check requires a node, got '%s'", node)
+		}
+		node = name
+	}
+	if !nodeName.MatchString(node) {
+		return "", fmt.Errorf("invalid node name '%s'", node)
+	}
+
+	pods, err := e.listPodsAcrossNamespaces("get pods --all-namespaces -o json --field-selector=spec.nodeName="+node+","+nodeCapacityPodSelector, cfg)
+	if err != nil {
+		return "", err
+	}
+
+	report := drainCheck{Node: node, Pods: []drainPod{}, PDBs: []drainPDBState{}}
+	budgets := map[string][]map[string]interface{}{}
+	states := map[string]*drainPDBState{}
+	for _, pod := range pods {
+		namespace, name := objectNamespacedName(pod)
+		if !cfg.SecurityConfig.IsNamespaceAllowed(namespace) {
+			report.UncheckedPods++
+			continue
+		}
+		if _, ok := budgets[namespace]; !ok {
+			if budgets[namespace], err = e.listObjects("get poddisruptionbudgets -n "+namespace+" -o json", cfg); err != nil {
+				return "", err
+			}
+		}
+
+		entry := drainPod{Namespace: namespace, Name: name, PDBs: []string{}, Skipped: drainSkipReason(pod)}
+		labels, _ := nestedValue(pod, "metadata", "labels").(map[string]interface{})
+		var blocking []string
+		for _, budget := range budgets[namespace] {
+			selector, _ := nestedValue(budget, "spec", "selector").(map[string]interface{})
+			if !labelSelectorMatches(selector, labels) {
+				continue
+			}
+			_, budgetName := objectNamespacedName(budget)
+			entry.PDBs = append(entry.PDBs, budgetName)
+			if entry.Skipped != "" {
+				continue
+			}
+			key := namespace + "/" + budgetName
+			state, ok := states[key]
+			if !ok {
+				state = &drainPDBState{
+					Namespace:          namespace,
+					Name:               budgetName,
+					DisruptionsAllowed: nestedInt(budget, "status", "disruptionsAllowed"),
+					CurrentHealthy:     nestedInt(budget, "status", "currentHealthy"),
+					DesiredHealthy:     nestedInt(budget, "status", "desiredHealthy"),
+					ExpectedPods:       nestedInt(budget, "status", "expectedPods"),
+				}
+				states[key] = state
+			}
+			state.PodsOnNode++
+			if state.DisruptionsAllowed == 0 {
+				blocking = append(blocking, budgetName)
+			}
+		}
+
+		switch {
+		case entry.Skipped != "":
+		case len(entry.PDBs) > 1:
+			entry.Blocked = true
+			entry.Reason = "selected by more than one PodDisruptionBudget, which the eviction API refuses"
+		case len(blocking) > 0:
+			entry.Blocked = true
+			entry.Reason = "PodDisruptionBudget " + strings.Join(blocking, ", ") + " allows no disruptions"
+		}
+		report.Blocked = report.Blocked || entry.Blocked
+		report.Pods = append(report.Pods, entry)
+	}
+
+	for _, state := range states {
+		report.PDBs = append(report.PDBs, *state)
+	}
+	sort.Slice(report.Pods, func(i, j int) bool {
+		if report.Pods[i].Namespace != report.Pods[j].Namespace {
+			return report.Pods[i].Namespace < report.Pods[j].Namespace
+		}
+		return report.Pods[i].Name < report.Pods[j].Name
+	})
+	sort.Slice(report.PDBs, func(i, j int) bool {
+		if report.PDBs[i].Namespace != report.PDBs[j].Namespace {
+			return report.PDBs[i].Namespace < report.PDBs[j].Namespace
+		}
+		return report.PDBs[i].Name < report.PDBs[j].Name
+	})
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode drain check: %w", err)
+	}
+	return string(data), nil
+}
+
+// drainSkipReason says why kubectl drain leaves a pod in place, or "" if it evicts it
+func drainSkipReason(pod map[string]interface{}) string {
+	if _, ok := nestedStringMap(pod, "metadata", "annotations")["kubernetes.io/config.mirror"]; ok {
+		return "mirror pod"
+	}
+	refs, _ := nestedValue(pod, "metadata", "ownerReferences").([]interface{})
+	for _, raw := range refs {
+		ref, _ := raw.(map[string]interface{})
+		if nestedString(ref, "kind") == "DaemonSet" {
+			return "daemonset"
+		}
+	}
+	return ""
+}
+
+// labelSelectorMatches reports whether labels satisfy a metav1.LabelSelector. A nil selector
+// matches nothing and an empty one everything, as for PodDisruptionBudgets.
+func labelSelectorMatches(selector map[string]interface{}, labels map[string]interface{}) bool {
+	if selector == nil {
+		return false
+	}
+	for key, want := range nestedStringMap(selector, "matchLabels") {
+		if got, _ := labels[key].(string); got != want {
+			return false
+		}
+	}
+	expressions, _ := selector["matchExpressions"].([]interface{})
+	for _, raw := range expressions {
+		expression, _ := raw.(map[string]interface{})
+		value, present := labels[nestedString(expression, "key")].(string)
+		values, _ := expression["values"].([]interface{})
+		listed := false
+		for _, candidate := range values {
+			if candidate == value {
+				listed = true
+			}
+		}
+		switch nestedString(expression, "operator") {
+		case "In":
+			if !present || !listed {
+				return false
+			}
+		case "NotIn":
+			if present && listed {
+				return false
+			}
+		case "Exists":
+			if !present {
+				return false
+			}
+		case "DoesNotExist":
+			if present {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,133 @@
+package kubectl
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const drainPodsJSON = `{
+  "items": [
+    {"metadata": {"name": "web-0", "namespace": "shop", "labels": {"app": "web", "tier": "frontend"}}},
+    {"metadata": {"name": "db-0", "namespace": "shop", "labels": {"app": "db"}}},
+    {"metadata": {"name": "cache-0", "namespace": "shop", "labels": {"app": "cache", "tier": "backend"}}},
+    {"metadata": {"name": "log-agent-x", "namespace": "shop", "labels": {"app": "log-agent"},
+      "ownerReferences": [{"kind": "DaemonSet", "name": "log-agent", "controller": true}]}},
+    {"metadata": {"name": "coredns-1", "namespace": "kube-system", "labels": {"k8s-app": "kube-dns"}}}
+  ]
+}`
+
+const drainPDBsJSON = `{
+  "items": [
+    {
+      "metadata": {"name": "web", "namespace": "shop"},
+      "spec": {"selector": {"matchLabels": {"app": "web"}}},
+      "status": {"disruptionsAllowed": 1, "currentHealthy": 3, "desiredHealthy": 2, "expectedPods": 3}
+    },
+    {
+      "metadata": {"name": "db", "namespace": "shop"},
+      "spec": {"selector": {"matchLabels": {"app": "db"}}},
+      "status": {"disruptionsAllowed": 0, "currentHealthy": 1, "desiredHealthy": 1, "expectedPods": 1}
+    },
+    {
+      "metadata": {"name": "backend", "namespace": "shop"},
+      "spec": {"selector": {"matchExpressions": [{"key": "tier", "operator": "In", "values": ["backend"]}]}},
+      "status": {"disruptionsAllowed": 2, "currentHealthy": 4, "desiredHealthy": 2, "expectedPods": 4}
+    },
+    {
+      "metadata": {"name": "caches", "namespace": "shop"},
+      "spec": {"selector": {"matchExpressions": [{"key": "app", "operator": "In", "values": ["cache", "log-agent"]}]}},
+      "status": {"disruptionsAllowed": 1, "currentHealthy": 2, "desiredHealthy": 1, "expectedPods": 2}
+    }
+  ]
+}`
+
+func TestKubectlToolExecutor_DrainCheck(t *testing.T) {
+	cfg := newTestConfig("readonly")
+	cfg.SecurityConfig.SetAllowedNamespaces("shop")
+
+	runDiagnosticCases(t, "drain-check", cfg, map[string]string{
+		"kubectl get pods --all-namespaces -o json --field-selector=spec.nodeName=node-1,status.phase!=Succeeded,status.phase!=Failed": drainPodsJSON,
+		"kubectl get pods --all-namespaces -o json --field-selector=spec.nodeName=node-2,status.phase!=Succeeded,status.phase!=Failed": `{"items": []}`,
+		"kubectl get poddisruptionbudgets -n shop -o json": drainPDBsJSON,
+	}, []diagnosticCase{
+		{
+			name: "blocked by budgets",
+			args: "node/node-1",
+			check: func(t *testing.T, output string, commands []string) {
+				report := decodeReport[drainCheck](t, output)
+				if report.Node != "node-1" || !report.Blocked || report.UncheckedPods != 1 {
+					t.Errorf("expected node-1 to be blocked with one unchecked pod, got %+v", report)
+				}
+				wantPods := []drainPod{
+					{Namespace: "shop", Name: "cache-0", PDBs: []string{"backend", "caches"}, Blocked: true,
+						Reason: "selected by more than one PodDisruptionBudget, which the eviction API refuses"},
+					{Namespace: "shop", Name: "db-0", PDBs: []string{"db"}, Blocked: true,
+						Reason: "PodDisruptionBudget db allows no disruptions"},
+					{Namespace: "shop", Name: "log-agent-x", PDBs: []string{"caches"}, Skipped: "daemonset"},
+					{Namespace: "shop", Name: "web-0", PDBs: []string{"web"}},
+				}
+				if !reflect.DeepEqual(report.Pods, wantPods) {
+					t.Errorf("expected pods %+v, got %+v", wantPods, report.Pods)
+				}
+				wantPDBs := []drainPDBState{
+					{Namespace: "shop", Name: "backend", DisruptionsAllowed: 2, CurrentHealthy: 4, DesiredHealthy: 2, ExpectedPods: 4, PodsOnNode: 1},
+					{Namespace: "shop", Name: "caches", DisruptionsAllowed: 1, CurrentHealthy: 2, DesiredHealthy: 1, ExpectedPods: 2, PodsOnNode: 1},
+					{Namespace: "shop", Name: "db", DisruptionsAllowed: 0, CurrentHealthy: 1, DesiredHealthy: 1, ExpectedPods: 1, PodsOnNode: 1},
+					{Namespace: "shop", Name: "web", DisruptionsAllowed: 1, CurrentHealthy: 3, DesiredHealthy: 2, ExpectedPods: 3, PodsOnNode: 1},
+				}
+				if !reflect.DeepEqual(report.PDBs, wantPDBs) {
+					t.Errorf("expected budgets %+v, got %+v", wantPDBs, report.PDBs)
+				}
+				for _, command := range commands {
+					if strings.Contains(command, "kube-system") {
+						t.Errorf("expected budgets outside the allow-list not to be read, got %s", command)
+					}
+				}
+			},
+		},
+		{
+			// An empty node reads no budgets and is not blocked
+			name: "empty node",
+			args: "node-2",
+			check: func(t *testing.T, output string, commands []string) {
+				if report := decodeReport[drainCheck](t, output); report.Blocked || len(report.Pods) != 0 || len(commands) != 1 {
+					t.Errorf("expected node-2 to drain freely after one list, got %+v after %q", report, commands)
+				}
+			},
+		},
+		{name: "invalid node name refused", args: "node-1,spec.nodeName=node-2", refused: "invalid node name"},
+		{name: "other kinds refused", args: "pod/web-0", refused: "requires a node"},
+		{name: "node required", args: "", refused: "exactly one node name"},
+	})
+}
+
+func TestLabelSelectorMatches(t *testing.T) {
+	labels := map[string]interface{}{"app": "web", "tier": "frontend"}
+	tests := []struct {
+		selector string
+		want     bool
+	}{
+		{`{}`, true},
+		{`{"matchLabels": {"app": "web"}}`, true},
+		{`{"matchLabels": {"app": "db"}}`, false},
+		{`{"matchExpressions": [{"key": "tier", "operator": "NotIn", "values": ["backend"]}]}`, true},
+		{`{"matchExpressions": [{"key": "tier", "operator": "NotIn", "values": ["frontend"]}]}`, false},
+		{`{"matchExpressions": [{"key": "track", "operator": "Exists"}]}`, false},
+		{`{"matchExpressions": [{"key": "track", "operator": "DoesNotExist"}]}`, true},
+		{`{"matchLabels": {"app": "web"}, "matchExpressions": [{"key": "tier", "operator": "In", "values": ["backend"]}]}`, false},
+	}
+	for _, tt := range tests {
+		var selector map[string]interface{}
+		if err := json.Unmarshal([]byte(tt.selector), &selector); err != nil {
+			t.Fatal(err)
+		}
+		if got := labelSelectorMatches(selector, labels); got != tt.want {
+			t.Errorf("labelSelectorMatches(%s) = %v, want %v", tt.selector, got, tt.want)
+		}
+	}
+	if labelSelectorMatches(nil, labels) {
+		t.Error("expected a missing selector to match nothing")
+	}
+}
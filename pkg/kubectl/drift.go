@@ -0,0 +1,158 @@
+package kubectl
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Azure/mcp-kubernetes/pkg/config"
+	"github.com/Azure/mcp-kubernetes/pkg/security"
+	"gopkg.in/yaml.v3"
+)
+
+// driftReport is the result of comparing a manifest with the live cluster
+type driftReport struct {
+	InSync    bool            `json:"in_sync"`
+	Resources []resourceDrift `json:"resources"`
+}
+
+// resourceDrift classifies how one live resource differs from the manifest. Missing fields
+// are in the manifest but not live, extra fields are live but not in the manifest, and
+// changed fields have different values.
+type resourceDrift struct {
+	Resource  string        `json:"resource"`
+	Namespace string        `json:"namespace,omitempty"`
+	Status    string        `json:"status"` // "would create", "would delete" or "drifted"
+	Missing   []fieldChange `json:"missing,omitempty"`
+	Extra     []fieldChange `json:"extra,omitempty"`
+	Changed   []fieldChange `json:"changed,omitempty"`
+}
+
+// checkDrift diffs an inline manifest against the live cluster with `kubectl diff -f -` and
+// classifies the differences. Namespaces and kinds named in the manifest are checked against
+// the namespace policy since the command line doesn't show them.
+func (e *KubectlToolExecutor) checkDrift(args string, params map[string]interface{}, cfg *config.ConfigData) (string, error) {
+	manifest, _ := params["manifest"].(string)
+	if strings.TrimSpace(manifest) == "" {
+		return "", fmt.Errorf("drift requires a manifest")
+	}
+
+	objects, err := manifestObjects(manifest)
+	if err != nil {
+		return "", err
+	}
+	if err := validateManifestScope(objects, cfg); err != nil {
+		return "", err
+	}
+	validator := security.NewValidator(cfg.SecurityConfig)
+
+	imp, err := impersonationFromParams(params)
+	if err != nil {
+		return "", err
+	}
+	command := "diff -f -"
+	if args = strings.TrimSpace(args); args != "" {
+		command += " " + args
+	}
+	command = injectImpersonationNamespace(imp.apply(command), cfg)
+
+	if err := e.checkAccessLevel(command, cfg); err != nil {
+		return "", err
+	}
+	if err := validator.ValidateCommand(command, security.CommandTypeKubectl); err != nil {
+		return "", err
+	}
+
+	output, err := e.executor.executeKubectlCommandWithInput(command, manifest, cfg)
+	output, err = errOutput(command, output, err)
+	if err != nil {
+		return "", err
+	}
+
+	parsed, err := parseDiff(output)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse diff output: %w", err)
+	}
+
+	data, err := json.MarshalIndent(classifyDrift(parsed.([]diffEntry)), "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode drift report: %w", err)
+	}
+	return string(data), nil
+}
+
+// classifyDrift sorts the field changes of each diffed resource into missing, extra and changed
+func classifyDrift(entries []diffEntry) driftReport {
+	report := driftReport{InSync: len(entries) == 0, Resources: []resourceDrift{}}
+	for _, entry := range entries {
+		drift := resourceDrift{Resource: entry.Resource, Namespace: entry.Namespace}
+		switch entry.ChangeType {
+		case "create":
+			drift.Status = "would create"
+		case "delete":
+			drift.Status = "would delete"
+		default:
+			drift.Status = "drifted"
+			for _, change := range entry.Changes {
+				switch {
+				case change.Old == nil:
+					drift.Missing = append(drift.Missing, change)
+				case change.New == nil:
+					drift.Extra = append(drift.Extra, change)
+				default:
+					drift.Changed = append(drift.Changed, change)
+				}
+			}
+		}
+		report.Resources = append(report.Resources, drift)
+	}
+	return report
+}
+
+// manifestObject is the kind and metadata.namespace of one object in a manifest, with the
+// object itself
+type manifestObject struct {
+	kind      string
+	namespace string
+	object    map[string]interface{}
+}
+
+// manifestObjects returns the kind and namespace of each object in a YAML or JSON manifest
+func manifestObjects(manifest string) ([]manifestObject, error) {
+	var objects []manifestObject
+	dec := yaml.NewDecoder(strings.NewReader(manifest))
+	for {
+		var obj map[string]interface{}
+		if err := dec.Decode(&obj); errors.Is(err, io.EOF) {
+			return objects, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("invalid manifest: %w", err)
+		}
+		if obj == nil {
+			continue
+		}
+		kind, _ := obj["kind"].(string)
+		if kind == "" {
+			return nil, fmt.Errorf("invalid manifest: object without a kind")
+		}
+		objects = append(objects, manifestObject{kind: kind, namespace: nestedString(obj, "metadata", "namespace"), object: obj})
+	}
+}
+
+// validateManifestScope checks the namespaces set in a manifest against the allow-list and,
+// while namespaces are restricted, requires every kind in it to be namespaced, as for commands
+// that change resources
+func validateManifestScope(objects []manifestObject, cfg *config.ConfigData) error {
+	var kinds []string
+	for _, object := range objects {
+		if object.namespace != "" && !cfg.SecurityConfig.IsNamespaceAllowed(object.namespace) {
+			return &security.ValidationError{
+				Message: "Error: Access to namespace '" + object.namespace + "' is denied by security configuration",
+			}
+		}
+		kinds = append(kinds, object.kind)
+	}
+	return security.NewValidator(cfg.SecurityConfig).ValidateResourceKinds(kinds)
+}
@@ -0,0 +1,153 @@
+package kubectl
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const driftManifest = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: default
+spec:
+  replicas: 3
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+  namespace: shop
+data:
+  mode: production
+`
+
+func TestKubectlToolExecutor_Drift(t *testing.T) {
+	var commands []string
+	worker := newTestWorker(t, func(command string) map[string]interface{} {
+		commands = append(commands, command)
+		return map[string]interface{}{"stdout": fieldChangeDiff + newResourceDiff, "exit_code": float64(1)}
+	})
+	executor := NewKubectlToolExecutor(worker)
+
+	output, err := executor.Execute(map[string]interface{}{
+		"_tool_name": "kubectl_config",
+		"operation":  "drift",
+		"resource":   "",
+		"args":       "",
+		"manifest":   driftManifest,
+	}, newTestConfig("readonly"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(commands) != 1 || commands[0] != "kubectl diff -f -" {
+		t.Errorf("commands = %v, want [kubectl diff -f -]", commands)
+	}
+
+	var report driftReport
+	if err := json.Unmarshal([]byte(output), &report); err != nil {
+		t.Fatalf("output is not a drift report: %v\n%s", err, output)
+	}
+	if report.InSync || len(report.Resources) != 2 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+
+	web := report.Resources[0]
+	if web.Resource != "Deployment/web" || web.Status != "drifted" {
+		t.Errorf("unexpected deployment drift: %+v", web)
+	}
+	var changed []string
+	for _, change := range web.Changed {
+		changed = append(changed, change.Path)
+	}
+	if strings.Join(changed, ",") != "spec.replicas,spec.containers[0].image" {
+		t.Errorf("unexpected changed fields: %v", changed)
+	}
+	if len(web.Missing) != 1 || !strings.HasSuffix(web.Missing[0].Path, "serviceAccountName") {
+		t.Errorf("unexpected missing fields: %+v", web.Missing)
+	}
+	if len(web.Extra) != 0 {
+		t.Errorf("unexpected extra fields: %+v", web.Extra)
+	}
+
+	settings := report.Resources[1]
+	if settings.Resource != "ConfigMap/settings" || settings.Namespace != "shop" || settings.Status != "would create" {
+		t.Errorf("unexpected configmap drift: %+v", settings)
+	}
+}
+
+func TestKubectlToolExecutor_DriftInSync(t *testing.T) {
+	worker := newTestWorker(t, func(command string) map[string]interface{} {
+		return map[string]interface{}{"stdout": ""}
+	})
+	executor := NewKubectlToolExecutor(worker)
+
+	output, err := executor.Execute(map[string]interface{}{
+		"_tool_name": "kubectl_config",
+		"operation":  "drift",
+		"resource":   "",
+		"args":       "",
+		"manifest":   driftManifest,
+	}, newTestConfig("readonly"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var report driftReport
+	if err := json.Unmarshal([]byte(output), &report); err != nil {
+		t.Fatalf("output is not a drift report: %v\n%s", err, output)
+	}
+	if !report.InSync || len(report.Resources) != 0 {
+		t.Errorf("expected in sync, got %+v", report)
+	}
+}
+
+func TestKubectlToolExecutor_DriftNamespacePolicy(t *testing.T) {
+	worker := newTestWorker(t, func(command string) map[string]interface{} {
+		t.Errorf("no command should reach the agent, got %q", command)
+		return map[string]interface{}{"stdout": ""}
+	})
+	executor := NewKubectlToolExecutor(worker)
+
+	cfg := newTestConfig("readonly")
+	cfg.SecurityConfig.SetAllowedNamespaces("default")
+
+	_, err := executor.Execute(map[string]interface{}{
+		"_tool_name": "kubectl_config",
+		"operation":  "drift",
+		"resource":   "",
+		"args":       "",
+		"manifest":   driftManifest,
+	}, cfg)
+	if err == nil || !strings.Contains(err.Error(), "shop") {
+		t.Fatalf("expected namespace denial, got %v", err)
+	}
+}
+
+func TestKubectlToolExecutor_DriftClusterScopedKinds(t *testing.T) {
+	worker := newTestWorker(t, func(command string) map[string]interface{} {
+		t.Errorf("no command should reach the agent, got %q", command)
+		return map[string]interface{}{"stdout": ""}
+	})
+	executor := NewKubectlToolExecutor(worker)
+
+	cfg := newTestConfig("readonly")
+	cfg.SecurityConfig.SetAllowedNamespaces("shop")
+
+	_, err := executor.Execute(map[string]interface{}{
+		"_tool_name": "kubectl_config",
+		"operation":  "drift",
+		"resource":   "",
+		"args":       "",
+		"manifest": `apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: reader
+rules: []
+`,
+	}, cfg)
+	if err == nil || !strings.Contains(err.Error(), "cluster-scoped resource type 'clusterroles'") {
+		t.Fatalf("expected cluster-scope denial, got %v", err)
+	}
+}
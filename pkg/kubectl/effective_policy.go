@@ -0,0 +1,115 @@
+package kubectl
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Azure/mcp-kubernetes/pkg/config"
+)
+
+// EffectivePolicy is the policy a tool call with the given routing parameters would run under.
+// Allowed is false, with the reason, when the routing itself would be refused.
+type EffectivePolicy struct {
+	AccessLevel        string              `json:"access_level"`
+	Identity           *PolicyIdentity     `json:"identity,omitempty"`
+	Tenant             string              `json:"tenant,omitempty"`
+	Server             string              `json:"server,omitempty"`
+	Allowed            bool                `json:"allowed"`
+	Reason             string              `json:"reason,omitempty"`
+	NamespaceScope     NamespaceScope      `json:"namespace_scope"`
+	NamespaceOverrides []NamespaceOverride `json:"namespace_overrides"`
+}
+
+// PolicyIdentity is the impersonated identity commands would run as
+type PolicyIdentity struct {
+	User   string   `json:"user"`
+	Groups []string `json:"groups,omitempty"`
+}
+
+// NamespaceScope is the set of namespaces commands may reach. Allowed lists names and
+// patterns when Restricted; an empty list then allows none. DefaultNamespace is the
+// namespace used when a command names none.
+type NamespaceScope struct {
+	Restricted       bool     `json:"restricted"`
+	Allowed          []string `json:"allowed,omitempty"`
+	DefaultNamespace string   `json:"default_namespace"`
+}
+
+// NamespaceOverride is a rule that applies to one namespace only
+type NamespaceOverride struct {
+	Namespace string `json:"namespace"`
+	Rule      string `json:"rule"`
+}
+
+// EffectivePolicy resolves the access level, namespace scope and per-namespace rules for a
+// call with the given as, as_group, server and tenant parameters, without running anything
+func (e *KubectlToolExecutor) EffectivePolicy(params map[string]interface{}, cfg *config.ConfigData) (EffectivePolicy, error) {
+	imp, err := impersonationFromParams(params)
+	if err != nil {
+		return EffectivePolicy{}, err
+	}
+	server, _ := params["server"].(string)
+	server = strings.TrimSpace(server)
+	if strings.ContainsAny(server, " \t\n'\"") {
+		return EffectivePolicy{}, fmt.Errorf("invalid server '%s'", server)
+	}
+	tenant, _ := params["tenant"].(string)
+
+	secConfig := cfg.SecurityConfig
+	policy := EffectivePolicy{
+		AccessLevel: cfg.AccessLevel,
+		Tenant:      strings.TrimSpace(tenant),
+		Server:      server,
+		Allowed:     true,
+		NamespaceScope: NamespaceScope{
+			Restricted:       secConfig.HasNamespaceRestrictions(),
+			DefaultNamespace: "default",
+		},
+		NamespaceOverrides: []NamespaceOverride{},
+	}
+	if policy.NamespaceScope.Restricted {
+		policy.NamespaceScope.Allowed = secConfig.AllowedNamespaces()
+	}
+
+	refuse := func(reason string) {
+		if policy.Allowed {
+			policy.Allowed, policy.Reason = false, reason
+		}
+	}
+	if _, err := e.forTenant(params, cfg); err != nil {
+		refuse(err.Error())
+	}
+	if server != "" && !secConfig.IsServerAllowed(server) {
+		refuse("Error: API server '" + server + "' is not allowed by security configuration")
+	}
+
+	if imp.user != "" {
+		policy.Identity = &PolicyIdentity{User: imp.user, Groups: imp.groups}
+		if cfg.AccessLevel == AccessLevelReadOnly {
+			refuse("impersonation requires read-write access, but current access level is read-only: " +
+				cfg.AccessLevelGuidance("readwrite"))
+		}
+		if namespace, ok := cfg.ImpersonationNamespaces[imp.user]; ok {
+			rule := "default namespace for commands impersonating " + imp.user
+			if !secConfig.IsNamespaceAllowed(namespace) {
+				rule += ", but outside the namespace allow-list, so commands without a namespace are refused"
+			}
+			policy.NamespaceScope.DefaultNamespace = namespace
+			policy.NamespaceOverrides = append(policy.NamespaceOverrides, NamespaceOverride{Namespace: namespace, Rule: rule})
+		}
+	}
+
+	for _, namespace := range secConfig.NoExecNamespaces() {
+		policy.NamespaceOverrides = append(policy.NamespaceOverrides, NamespaceOverride{
+			Namespace: namespace,
+			Rule:      "exec and cp may not target pods here",
+		})
+	}
+	if namespace := secConfig.ProtectedNamespace(); namespace != "" {
+		policy.NamespaceOverrides = append(policy.NamespaceOverrides, NamespaceOverride{
+			Namespace: namespace,
+			Rule:      "may not be deleted, since this server runs in it",
+		})
+	}
+	return policy, nil
+}
@@ -0,0 +1,77 @@
+package kubectl
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEffectivePolicy_Impersonated(t *testing.T) {
+	executor := NewKubectlToolExecutor(nil)
+	cfg := newTestConfig("readwrite")
+	cfg.SecurityConfig.SetAllowedNamespaces("team-a,shared-.*")
+	cfg.SecurityConfig.SetNoExecNamespaces("kube-system")
+	cfg.ImpersonationNamespaces["alice"] = "team-a"
+
+	policy, err := executor.EffectivePolicy(map[string]interface{}{
+		"as":       "alice",
+		"as_group": "dev",
+	}, cfg)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !policy.Allowed || policy.AccessLevel != "readwrite" {
+		t.Errorf("Expected an allowed readwrite policy, got %+v", policy)
+	}
+	if policy.Identity == nil || policy.Identity.User != "alice" || len(policy.Identity.Groups) != 1 || policy.Identity.Groups[0] != "dev" {
+		t.Errorf("Expected identity alice in group dev, got %+v", policy.Identity)
+	}
+	scope := policy.NamespaceScope
+	if !scope.Restricted || scope.DefaultNamespace != "team-a" || strings.Join(scope.Allowed, ",") != "team-a,shared-.*" {
+		t.Errorf("Unexpected namespace scope: %+v", scope)
+	}
+	if len(policy.NamespaceOverrides) != 2 ||
+		policy.NamespaceOverrides[0].Namespace != "team-a" || !strings.Contains(policy.NamespaceOverrides[0].Rule, "alice") ||
+		policy.NamespaceOverrides[1].Namespace != "kube-system" {
+		t.Errorf("Unexpected namespace overrides: %+v", policy.NamespaceOverrides)
+	}
+
+	// Without impersonation the server's own default namespace applies
+	policy, err = executor.EffectivePolicy(map[string]interface{}{}, cfg)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if policy.Identity != nil || policy.NamespaceScope.DefaultNamespace != "default" {
+		t.Errorf("Expected no identity and the default namespace, got %+v", policy)
+	}
+}
+
+func TestEffectivePolicy_Refused(t *testing.T) {
+	executor := NewKubectlToolExecutor(nil)
+
+	tests := []struct {
+		name     string
+		level    string
+		params   map[string]interface{}
+		expected string
+	}{
+		{name: "impersonation in readonly", level: "readonly", params: map[string]interface{}{"as": "alice"}, expected: "impersonation requires read-write"},
+		{name: "server not allowed", level: "readwrite", params: map[string]interface{}{"server": "https://other:6443"}, expected: "API server 'https://other:6443'"},
+		{name: "tenant not allowed", level: "readwrite", params: map[string]interface{}{"tenant": "acme"}, expected: "Tenant 'acme'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, err := executor.EffectivePolicy(tt.params, newTestConfig(tt.level))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if policy.Allowed || !strings.Contains(policy.Reason, tt.expected) {
+				t.Errorf("Expected a refusal containing %q, got %+v", tt.expected, policy)
+			}
+		})
+	}
+
+	if _, err := executor.EffectivePolicy(map[string]interface{}{"as_group": "dev"}, newTestConfig("readwrite")); err == nil {
+		t.Error("Expected as_group without as to be an error")
+	}
+}
@@ -0,0 +1,194 @@
+package kubectl
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/Azure/mcp-kubernetes/pkg/config"
+	"github.com/Azure/mcp-kubernetes/pkg/security"
+)
+
+const (
+	// defaultWatchDuration is how long events are watched when no duration is given
+	defaultWatchDuration = 30 * time.Second
+	// maxWatchDuration bounds how long a single call may watch events
+	maxWatchDuration = 5 * time.Minute
+	// defaultWatchMaxEvents is the number of events collected when no max_events is given
+	defaultWatchMaxEvents = 100
+	// maxPendingEventBytes bounds the partial event kept between chunks
+	maxPendingEventBytes = 1 << 20
+)
+
+// eventWatchResult is the outcome of a bounded events watch
+type eventWatchResult struct {
+	Events []treeEvent `json:"events"`
+	// StoppedBy is "duration", "max_events" or "stream_end"
+	StoppedBy       string  `json:"stopped_by"`
+	DurationSeconds float64 `json:"duration_seconds"`
+}
+
+// eventAccumulator collects events from the JSON objects of a `kubectl events --watch -o json`
+// stream, which may be split across chunks at any point
+type eventAccumulator struct {
+	max          int
+	warningsOnly bool
+	pending      string
+	events       []treeEvent
+}
+
+// add consumes a chunk of output and reports whether max events have been collected. Output
+// that isn't a stream of JSON objects is an error rather than buffered indefinitely.
+func (a *eventAccumulator) add(chunk string) (bool, error) {
+	a.pending += chunk
+
+	dec := json.NewDecoder(strings.NewReader(a.pending))
+	for len(a.events) < a.max {
+		var event map[string]interface{}
+		if err := dec.Decode(&event); err != nil {
+			if !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+				return false, fmt.Errorf("failed to parse events stream: %w", err)
+			}
+			// Incomplete object: keep the rest for the next chunk
+			if len(a.pending) > maxPendingEventBytes {
+				return false, fmt.Errorf("failed to parse events stream: event exceeds %d bytes", maxPendingEventBytes)
+			}
+			break
+		}
+		a.pending = a.pending[dec.InputOffset():]
+		dec = json.NewDecoder(strings.NewReader(a.pending))
+
+		if a.warningsOnly && nestedString(event, "type") != "Warning" {
+			continue
+		}
+		a.events = append(a.events, treeEvent{
+			Type:     nestedString(event, "type"),
+			Reason:   nestedString(event, "reason"),
+			Object:   strings.ToLower(nestedString(event, "involvedObject", "kind")) + "/" + nestedString(event, "involvedObject", "name"),
+			Message:  nestedString(event, "message"),
+			Count:    nestedInt(event, "count"),
+			LastSeen: eventTime(event),
+		})
+	}
+	return len(a.events) >= a.max, nil
+}
+
+// isEventsWatch reports whether an events call asks to watch, through the watch parameter
+// or a --watch flag, which would otherwise block until the command times out
+func isEventsWatch(args string, params map[string]interface{}) bool {
+	if watch, _ := params["watch"].(bool); watch {
+		return true
+	}
+	cmdline, err := parseCommandLine(args)
+	return err == nil && cmdline.hasFlag("--watch")
+}
+
+// watchEvents streams events until the duration elapses or max_events have been collected.
+// Only Warning events are returned unless include_normal is set. The watch command is
+// assembled and checked like a direct call, so impersonation, server and namespace
+// policy all apply.
+func (e *KubectlToolExecutor) watchEvents(toolName, operation, resource, args string, params map[string]interface{}, cfg *config.ConfigData) (string, error) {
+	duration := defaultWatchDuration
+	seconds, ok, err := positiveIntParam("duration", params["duration"])
+	if err != nil {
+		return "", err
+	}
+	if ok {
+		duration = time.Duration(seconds) * time.Second
+	}
+	if duration > maxWatchDuration {
+		return "", fmt.Errorf("duration must be at most %d seconds", int(maxWatchDuration.Seconds()))
+	}
+	duration = time.Duration(cfg.ClampTimeout(int(duration.Seconds()))) * time.Second
+
+	maxEvents := int64(defaultWatchMaxEvents)
+	if n, ok, err := positiveIntParam("max_events", params["max_events"]); err != nil {
+		return "", err
+	} else if ok {
+		maxEvents = n
+	}
+
+	includeNormal, _ := params["include_normal"].(bool)
+
+	command, err := e.assembleCommand(toolName, operation, resource, args, params, cfg)
+	if err != nil {
+		return "", err
+	}
+	cmdline, err := parseCommandLine(command)
+	if err != nil {
+		return "", err
+	}
+	if cmdline.hasFlag("--output") {
+		return "", fmt.Errorf("events watch does not accept --output")
+	}
+
+	var flags []string
+	if !cmdline.hasFlag("--watch") {
+		flags = append(flags, "--watch")
+	}
+	flags = append(flags, "-o", "json")
+	if !includeNormal && !cmdline.hasFlag("--types") {
+		flags = append(flags, "--types=Warning")
+	}
+	command = insertFlags(command, flags)
+
+	if err := e.checkAccessLevel(command, cfg); err != nil {
+		return "", err
+	}
+	validator := security.NewValidator(cfg.SecurityConfig)
+	if err := validator.ValidateCommand(command, security.CommandTypeKubectl); err != nil {
+		return "", err
+	}
+
+	stream, err := e.executor.pulsarWorker.Stream("kubectl "+command, duration)
+	if err != nil {
+		return "", err
+	}
+	defer stream.Close()
+
+	acc := &eventAccumulator{max: int(maxEvents), warningsOnly: !includeNormal}
+	result := eventWatchResult{StoppedBy: "duration"}
+
+	start := time.Now()
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+collect:
+	for {
+		select {
+		case chunk := <-stream.Chunks():
+			if chunk.Err != nil {
+				return "", chunk.Err
+			}
+			full, err := acc.add(chunk.Stdout)
+			if err != nil {
+				return "", err
+			}
+			if full {
+				result.StoppedBy = "max_events"
+				break collect
+			}
+			if chunk.Done {
+				result.StoppedBy = "stream_end"
+				break collect
+			}
+		case <-timer.C:
+			break collect
+		}
+	}
+
+	result.Events = acc.events
+	if result.Events == nil {
+		result.Events = []treeEvent{}
+	}
+	result.DurationSeconds = time.Since(start).Round(time.Millisecond).Seconds()
+
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode events watch result: %w", err)
+	}
+	return string(data), nil
+}
@@ -0,0 +1,200 @@
+package kubectl
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newStreamTestWorker returns a worker whose fake agent answers a streaming request with the
+// given chunks, delivered in order after the request has been accepted
+func newStreamTestWorker(t *testing.T, reply func(command string) []map[string]interface{}) *Worker {
+	t.Helper()
+
+	var w *Worker
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		var msg struct {
+			Payload struct {
+				Id     int                    `json:"Id"`
+				Result map[string]interface{} `json:"result"`
+			}
+		}
+		if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		command, _ := msg.Payload.Result["command"].(string)
+		chunks := reply(command)
+		go func() {
+			for _, chunk := range chunks {
+				w.deliver(msg.Payload.Id, chunk)
+			}
+		}()
+	}))
+	t.Cleanup(srv.Close)
+
+	w, err := New(&Config{
+		Mode:                ModeAgent,
+		Location:            "test-host",
+		Token:               "test-token",
+		UnsubscribeEndpoint: srv.URL,
+		Timeout:             5,
+	})
+	if err != nil {
+		t.Fatalf("failed to create worker: %v", err)
+	}
+	return w
+}
+
+func watchedEvent(eventType, reason, pod string) string {
+	return `{"type": "` + eventType + `", "reason": "` + reason + `", "message": "` + reason + ` on ` + pod + `",
+  "involvedObject": {"kind": "Pod", "name": "` + pod + `"}, "lastTimestamp": "2025-01-02T00:00:00Z"}
+`
+}
+
+func TestEventAccumulator_MaxEvents(t *testing.T) {
+	acc := &eventAccumulator{max: 2, warningsOnly: true}
+
+	first := watchedEvent("Warning", "BackOff", "web-0")
+	// Objects split across chunks are completed by the next chunk
+	if full, err := acc.add(first[:20]); full || err != nil {
+		t.Fatalf("partial object should not complete the watch, got %v, %v", full, err)
+	}
+	if full, err := acc.add(first[20:] + watchedEvent("Normal", "Pulled", "web-1")); full || err != nil {
+		t.Fatalf("normal events should not count towards max_events, got %v, %v", full, err)
+	}
+	if len(acc.events) != 1 {
+		t.Fatalf("expected 1 event, got %+v", acc.events)
+	}
+
+	if full, err := acc.add(watchedEvent("Warning", "Unhealthy", "web-1") + watchedEvent("Warning", "Failed", "web-2")); !full || err != nil {
+		t.Fatalf("expected cutoff at max_events, got %v, %v", full, err)
+	}
+	if len(acc.events) != 2 || acc.events[1].Reason != "Unhealthy" || acc.events[1].Object != "pod/web-1" {
+		t.Errorf("unexpected events: %+v", acc.events)
+	}
+}
+
+func TestEventAccumulator_InvalidStream(t *testing.T) {
+	acc := &eventAccumulator{max: 10}
+	if _, err := acc.add("error: the server doesn't have a resource type \"events\"\n"); err == nil {
+		t.Errorf("expected non-JSON output to fail")
+	}
+
+	// A partial event is only buffered up to a limit
+	acc = &eventAccumulator{max: 10}
+	chunk := `{"message": "` + strings.Repeat("x", 64<<10)
+	var err error
+	for i := 0; i < 20 && err == nil; i++ {
+		_, err = acc.add(chunk)
+	}
+	if err == nil || len(acc.pending) > maxPendingEventBytes+len(chunk) {
+		t.Errorf("expected the partial event to be capped, got %v with %d bytes pending", err, len(acc.pending))
+	}
+}
+
+func TestKubectlToolExecutor_WatchEvents(t *testing.T) {
+	var commands []string
+	worker := newStreamTestWorker(t, func(command string) []map[string]interface{} {
+		commands = append(commands, command)
+		return []map[string]interface{}{
+			{"stdout": watchedEvent("Warning", "BackOff", "web-0"), "done": false},
+			{"stdout": watchedEvent("Warning", "Unhealthy", "web-1"), "done": false},
+			{"stdout": watchedEvent("Warning", "Failed", "web-2"), "done": false},
+			{"stdout": "", "done": true},
+		}
+	})
+	executor := NewKubectlToolExecutor(worker)
+
+	output, err := executor.Execute(map[string]interface{}{
+		"_tool_name": "kubectl_diagnostics",
+		"operation":  "events",
+		"resource":   "",
+		"args":       "-n shop",
+		"watch":      true,
+		"duration":   float64(10),
+		"max_events": float64(2),
+	}, newTestConfig("readonly"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(commands) != 1 || commands[0] != "kubectl events -n shop --watch -o json --types=Warning" {
+		t.Errorf("unexpected commands: %v", commands)
+	}
+
+	var result eventWatchResult
+	if err := json.Unmarshal([]byte(output), &result); err != nil {
+		t.Fatalf("result is not JSON: %v\n%s", err, output)
+	}
+	if result.StoppedBy != "max_events" || len(result.Events) != 2 {
+		t.Errorf("expected cutoff after 2 events, got %+v", result)
+	}
+}
+
+func TestKubectlToolExecutor_WatchEventsAssembledCommand(t *testing.T) {
+	var commands []string
+	worker := newStreamTestWorker(t, func(command string) []map[string]interface{} {
+		commands = append(commands, command)
+		return []map[string]interface{}{{"stdout": "", "done": true}}
+	})
+	executor := NewKubectlToolExecutor(worker)
+
+	cfg := newTestConfig("readwrite")
+	cfg.SecurityConfig.SetAllowedServers("https://staging.example.com:6443")
+	cfg.ImpersonationNamespaces = map[string]string{"system:serviceaccount:shop:agent": "shop"}
+
+	_, err := executor.Execute(map[string]interface{}{
+		"_tool_name": "kubectl_diagnostics",
+		"operation":  "events",
+		"resource":   "",
+		"args":       "",
+		"watch":      true,
+		"duration":   float64(1),
+		"as":         "system:serviceaccount:shop:agent",
+		"server":     "https://staging.example.com:6443",
+	}, cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := "kubectl events --server=https://staging.example.com:6443 --as=system:serviceaccount:shop:agent -n shop --watch -o json --types=Warning"
+	if len(commands) != 1 || commands[0] != expected {
+		t.Errorf("commands = %v, want [%s]", commands, expected)
+	}
+
+	// A server outside the allow-list is refused before the watch starts
+	_, err = executor.Execute(map[string]interface{}{
+		"_tool_name": "kubectl_diagnostics",
+		"operation":  "events",
+		"resource":   "",
+		"args":       "-n shop",
+		"watch":      true,
+		"server":     "https://prod.example.com:6443",
+	}, cfg)
+	if err == nil || len(commands) != 1 {
+		t.Errorf("expected the server to be denied, got %v after %v", err, commands)
+	}
+}
+
+func TestKubectlToolExecutor_WatchEventsNamespacePolicy(t *testing.T) {
+	worker := newStreamTestWorker(t, func(command string) []map[string]interface{} {
+		t.Errorf("unexpected command: %s", command)
+		return nil
+	})
+	executor := NewKubectlToolExecutor(worker)
+	cfg := newTestConfig("readonly")
+	cfg.SecurityConfig.SetAllowedNamespaces("shop")
+
+	_, err := executor.Execute(map[string]interface{}{
+		"_tool_name": "kubectl_diagnostics",
+		"operation":  "events",
+		"resource":   "",
+		"args":       "-n kube-system --watch",
+	}, cfg)
+	if err == nil || !strings.Contains(err.Error(), "namespace") {
+		t.Errorf("expected namespace policy error, got %v", err)
+	}
+}
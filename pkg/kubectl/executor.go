@@ -2,8 +2,10 @@ package kubectl
 
 import (
 	"crypto/sha1"
+	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
 	"strings"
 	"time"
 
@@ -28,9 +30,41 @@ func NewExecutor(pulsarWorker *Worker) *KubectlExecutor {
 	}
 }
 
+// errOutputOperations are the kubectl operations whose exit code 1 reports an answer rather
+// than a failure, so their output is returned instead of an error: diff exits with 1 when
+// there are differences and auth can-i when the answer is no. Other failures, such as a
+// failed apply, are errors.
+var errOutputOperations = [][]string{{"diff"}, {"auth", "can-i"}}
+
+// returnsErrOutput reports whether a command, with or without the leading "kubectl", runs
+// one of errOutputOperations
+func returnsErrOutput(cmd string) bool {
+	cmdline, err := parseCommandLine(cmd)
+	if err != nil {
+		return false
+	}
+	for _, operation := range errOutputOperations {
+		if len(cmdline.positionals) >= len(operation) && slices.Equal(cmdline.positionals[:len(operation)], operation) {
+			return true
+		}
+	}
+	return false
+}
+
+// errOutput replaces the error of a remote command from errOutputOperations that exited
+// with 1 by the output it printed
+func errOutput(cmd string, output string, err error) (string, error) {
+	var exitErr *exitError
+	if err != nil && errors.As(err, &exitErr) && exitErr.Code == 1 && returnsErrOutput(cmd) {
+		return exitErr.Stdout, nil
+	}
+	return output, err
+}
+
 // executeKubectlCommand executes a kubectl command with the given arguments
 func (e *KubectlExecutor) executeKubectlCommand(cmd string, args string, cfg *config.ConfigData) (string, error) {
 	process := command.NewShellProcess("kubectl", cfg.Timeout)
+	process.Path = cfg.KubectlPath
 
 	var fullCmd string
 	if strings.HasPrefix(cmd, "kubectl ") {
@@ -44,10 +78,17 @@ func (e *KubectlExecutor) executeKubectlCommand(cmd string, args string, cfg *co
 		}
 	}
 
-	return process.Run(fullCmd)
+	process.ReturnErrOutput = returnsErrOutput(fullCmd)
+	return process.Run(withRequestTimeout(fullCmd, cfg.Timeout, cfg))
 }
 
 func (e *KubectlExecutor) executeKubectlCommandOnHost(cmd string, args string, cfg *config.ConfigData) (string, error) {
+	return e.executeKubectlCommandOnHostWithin(cmd, args, 0, cfg)
+}
+
+// executeKubectlCommandOnHostWithin runs a command on the remote agent, waiting up to timeout
+// seconds for the response; zero uses the worker's timeout. Either is held to --max-timeout.
+func (e *KubectlExecutor) executeKubectlCommandOnHostWithin(cmd string, args string, timeout int, cfg *config.ConfigData) (string, error) {
 	var fullCmd string
 	if strings.HasPrefix(cmd, "kubectl ") {
 		// If command already includes "kubectl", use it as is (for backward compatibility)
@@ -59,30 +100,70 @@ func (e *KubectlExecutor) executeKubectlCommandOnHost(cmd string, args string, c
 			fullCmd += " " + args
 		}
 	}
-	id := int(time.Now().UnixMilli())
-	respCh := make(chan string, 1)
+	return e.requestOnHost(map[string]interface{}{"command": fullCmd}, timeout, cfg)
+}
+
+// executeKubectlCommandWithInput runs a command on the remote agent with input passed to its
+// stdin, for commands reading a manifest with -f -
+func (e *KubectlExecutor) executeKubectlCommandWithInput(cmd string, input string, cfg *config.ConfigData) (string, error) {
+	return e.executeKubectlCommandWithInputWithin(cmd, input, 0, cfg)
+}
+
+// executeKubectlCommandWithInputWithin runs a command with input on its stdin, waiting up to
+// timeout seconds for the response as executeKubectlCommandOnHostWithin does
+func (e *KubectlExecutor) executeKubectlCommandWithInputWithin(cmd string, input string, timeout int, cfg *config.ConfigData) (string, error) {
+	return e.requestOnHost(map[string]interface{}{
+		"command": "kubectl " + cmd,
+		"stdin":   input,
+	}, timeout, cfg)
+}
+
+// requestOnHost sends a command request to the remote agent and waits for its result. The
+// timeout goes with the request so the agent can stop the command when nobody is waiting.
+func (e *KubectlExecutor) requestOnHost(request map[string]interface{}, timeout int, cfg *config.ConfigData) (string, error) {
+	if e.pulsarWorker == nil || e.pulsarWorker.cfg == nil {
+		return "", fmt.Errorf("remote worker is not configured")
+	}
+	if timeout <= 0 {
+		timeout = e.pulsarWorker.cfg.Timeout
+	}
+	timeout = cfg.ClampTimeout(timeout)
+	request["timeout_seconds"] = timeout
+	if command, ok := request["command"].(string); ok {
+		request["command"] = withRequestTimeout(command, timeout, cfg)
+	}
+	if err := e.pulsarWorker.breaker.allow(e.pulsarWorker.Ping); err != nil {
+		return "", err
+	}
+
+	id := e.pulsarWorker.nextRequestID()
+	respCh := make(chan *commandResult, 1)
 	e.pulsarWorker.pending.Store(id, respCh)
 	topic := fmt.Sprintf("mcp-%s-%x", strings.ToLower(e.pulsarWorker.cfg.Token), sha1.Sum([]byte(strings.ToLower(e.pulsarWorker.cfg.Location))))
-	err := e.pulsarWorker.sendRequest(e.pulsarWorker.cfg.AccountUID, id, topic, map[string]interface{}{
-		"command": fullCmd,
-	})
+	err := e.pulsarWorker.sendRequest(e.pulsarWorker.cfg.AccountUID, id, topic, request)
 	if err != nil {
+		e.pulsarWorker.pending.Delete(id)
+		e.pulsarWorker.breaker.failed()
 		return "", fmt.Errorf("failed to send request: %s", err.Error())
 	}
 
 	slog.Info("waiting for response", "id", id, "topic", topic)
 
-	var res string
+	var res *commandResult
 	select {
 	case res = <-respCh:
 		slog.Info("got message", "id", id, "topic", topic)
-	case <-time.After(time.Second * time.Duration(e.pulsarWorker.cfg.Timeout)):
+	case <-time.After(time.Second * time.Duration(timeout)):
 		e.pulsarWorker.pending.Delete(id)
 		slog.Info("timeout", "id", id, "topic", topic)
-		return "", fmt.Errorf("timeout waiting for response")
+		return "", e.pulsarWorker.timedOut(timeout)
 	}
+	e.pulsarWorker.breaker.succeeded()
 	slog.Info("waiting completed", "id", id, "topic", topic)
-	return res, nil
+	if res.Err != nil {
+		return "", res.Err
+	}
+	return res.Stdout, nil
 }
 
 // Validate the command against security settings}
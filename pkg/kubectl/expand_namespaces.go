@@ -0,0 +1,214 @@
+package kubectl
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Azure/mcp-kubernetes/pkg/config"
+	"github.com/Azure/mcp-kubernetes/pkg/security"
+	"gopkg.in/yaml.v3"
+)
+
+// expandNamespacesMax bounds the namespaces an --all-namespaces read is expanded to
+const expandNamespacesMax = 50
+
+// tableCellSeparator splits the columns of kubectl's table output, which are padded with at
+// least three spaces while headers such as "NOMINATED NODE" contain one
+var tableCellSeparator = regexp.MustCompile(`\s{2,}`)
+
+// namespaceTableVerbs are the verbs whose table output gains a NAMESPACE column with
+// --all-namespaces
+var namespaceTableVerbs = map[string]bool{"get": true, "top": true, "events": true}
+
+// expandsAllNamespaces reports whether a command is a read across all namespaces that
+// --expand-all-namespaces runs as one read per allowed namespace instead of refusing
+func (e *KubectlToolExecutor) expandsAllNamespaces(command string, cfg *config.ConfigData) bool {
+	if !cfg.ExpandAllNamespaces || !cfg.SecurityConfig.HasNamespaceRestrictions() {
+		return false
+	}
+	cmdline, err := parseCommandLine(command)
+	if err != nil || !cmdline.boolFlag("--all-namespaces") {
+		return false
+	}
+	return e.determineCommandCategory(command) == "read-only"
+}
+
+// readAllowedNamespaces runs a read given with --all-namespaces once in each namespace the
+// allow-list permits and combines the results the way kubectl prints them for
+// --all-namespaces. Each per-namespace command goes through the same validation as any other.
+func (e *KubectlToolExecutor) readAllowedNamespaces(command string, timeout int, cfg *config.ConfigData) (string, error) {
+	listing, err := e.runCommand("get namespaces -o name", cfg)
+	if err != nil {
+		return "", fmt.Errorf("failed to list namespaces to expand --all-namespaces: %w", err)
+	}
+	var namespaces []string
+	for _, line := range strings.Split(listing, "\n") {
+		namespace := strings.TrimPrefix(strings.TrimSpace(line), "namespace/")
+		if namespace != "" && cfg.SecurityConfig.IsNamespaceAllowed(namespace) {
+			namespaces = append(namespaces, namespace)
+		}
+	}
+	sort.Strings(namespaces)
+	if len(namespaces) > expandNamespacesMax {
+		return "", fmt.Errorf("--all-namespaces would be expanded to %d allowed namespaces, more than %d; name a namespace with -n instead",
+			len(namespaces), expandNamespacesMax)
+	}
+
+	base := dropAllNamespaces(command)
+	validator := security.NewValidator(cfg.SecurityConfig)
+	outputs := make([]string, len(namespaces))
+	for i, namespace := range namespaces {
+		namespaced := insertFlags(base, []string{"-n", namespace})
+		if err := validator.ValidateCommand(namespaced, security.CommandTypeKubectl); err != nil {
+			return "", err
+		}
+		if outputs[i], err = e.runCommandWithin(namespaced, timeout, cfg); err != nil {
+			return "", fmt.Errorf("failed to read namespace %s: %w", namespace, err)
+		}
+	}
+	return mergeNamespaceOutputs(command, namespaces, outputs)
+}
+
+// dropAllNamespaces removes -A and --all-namespaces from a command, leaving anything after
+// a "--" separator alone
+func dropAllNamespaces(command string) string {
+	before, after, separated := strings.Cut(command, " -- ")
+	var kept []string
+	for _, part := range strings.Split(before, " ") {
+		switch part {
+		case "-A", "--all-namespaces", "--all-namespaces=true":
+		default:
+			kept = append(kept, part)
+		}
+	}
+	command = strings.Join(kept, " ")
+	if separated {
+		command += " -- " + after
+	}
+	return command
+}
+
+// mergeNamespaceOutputs combines the outputs of a command run in each of namespaces. JSON and
+// YAML lists are merged into one list, the tables of get, top and events gain the NAMESPACE
+// column --all-namespaces adds, and other output is concatenated.
+func mergeNamespaceOutputs(command string, namespaces, outputs []string) (string, error) {
+	var nonEmpty []int
+	for i, output := range outputs {
+		if strings.TrimSpace(output) != "" {
+			nonEmpty = append(nonEmpty, i)
+		}
+	}
+	if len(nonEmpty) == 0 {
+		return "No resources found\n", nil
+	}
+
+	cmdline, err := parseCommandLine(command)
+	if err != nil {
+		return "", err
+	}
+	format, _ := cmdline.flag("--output")
+	singleType := len(cmdline.args()) > 0 && !strings.Contains(cmdline.args()[0], ",")
+	switch {
+	case format == "json":
+		items := []interface{}{}
+		for _, i := range nonEmpty {
+			var list map[string]interface{}
+			if err := json.Unmarshal([]byte(outputs[i]), &list); err != nil {
+				return "", fmt.Errorf("failed to merge the output of namespace %s: %w", namespaces[i], err)
+			}
+			if listed, ok := list["items"].([]interface{}); ok {
+				items = append(items, listed...)
+			} else {
+				items = append(items, list)
+			}
+		}
+		merged, ok := marshalKubectlJSON(map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "List",
+			"items":      items,
+			"metadata":   map[string]interface{}{"resourceVersion": ""},
+		})
+		if !ok {
+			return "", fmt.Errorf("failed to encode the merged list")
+		}
+		return merged, nil
+	case format == "yaml":
+		return mergeYAMLLists(namespaces, outputs, nonEmpty)
+	case (format == "" || format == "wide") && singleType && namespaceTableVerbs[cmdline.verb()]:
+		return mergeNamespaceTables(namespaces, outputs, nonEmpty, !cmdline.boolFlag("--no-headers")), nil
+	default:
+		var b strings.Builder
+		for _, i := range nonEmpty {
+			output := outputs[i]
+			if strings.HasPrefix(format, "custom-columns") && b.Len() > 0 && !cmdline.boolFlag("--no-headers") {
+				// Keep the header of the first table only
+				_, output, _ = strings.Cut(output, "\n")
+			}
+			b.WriteString(output)
+			if !strings.HasSuffix(output, "\n") {
+				b.WriteString("\n")
+			}
+		}
+		return b.String(), nil
+	}
+}
+
+// mergeYAMLLists appends the items of each YAML list to the first, preserving key order
+func mergeYAMLLists(namespaces, outputs []string, nonEmpty []int) (string, error) {
+	var merged *yaml.Node
+	var mergedItems *yaml.Node
+	for _, i := range nonEmpty {
+		var doc yaml.Node
+		if err := yaml.Unmarshal([]byte(outputs[i]), &doc); err != nil || len(doc.Content) == 0 {
+			return "", fmt.Errorf("failed to merge the output of namespace %s: %v", namespaces[i], err)
+		}
+		items := yamlMapValue(doc.Content[0], "items")
+		if items == nil || items.Kind != yaml.SequenceNode {
+			return "", fmt.Errorf("failed to merge the output of namespace %s: not a list", namespaces[i])
+		}
+		if merged == nil {
+			merged, mergedItems = &doc, items
+			continue
+		}
+		mergedItems.Content = append(mergedItems.Content, items.Content...)
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(merged); err != nil {
+		return "", fmt.Errorf("failed to encode the merged list: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// mergeNamespaceTables joins the tables printed for each namespace into one with a leading
+// NAMESPACE column, realigned as kubectl aligns its tables
+func mergeNamespaceTables(namespaces, outputs []string, nonEmpty []int, header bool) string {
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 8, 3, ' ', 0)
+	wroteHeader := false
+	for _, i := range nonEmpty {
+		lines := strings.Split(strings.TrimRight(outputs[i], "\n"), "\n")
+		if header {
+			if !wroteHeader {
+				fmt.Fprintln(w, "NAMESPACE\t"+strings.Join(tableCellSeparator.Split(strings.TrimSpace(lines[0]), -1), "\t"))
+				wroteHeader = true
+			}
+			lines = lines[1:]
+		}
+		for _, line := range lines {
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+			fmt.Fprintln(w, namespaces[i]+"\t"+strings.Join(tableCellSeparator.Split(strings.TrimSpace(line), -1), "\t"))
+		}
+	}
+	w.Flush()
+	return b.String()
+}
@@ -0,0 +1,107 @@
+package kubectl
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestKubectlToolExecutor_ExpandAllNamespaces(t *testing.T) {
+	outputs := map[string]string{
+		"kubectl get namespaces -o name": "namespace/default\nnamespace/kube-system\nnamespace/shop\nnamespace/team-a\n",
+		"kubectl get pods -n shop":       "NAME    READY   STATUS    RESTARTS   AGE\nweb-1   1/1     Running   0          5d\n",
+		"kubectl get pods -n team-a":     "NAME              READY   STATUS             RESTARTS      AGE\nworker-abcdef-1   0/1     CrashLoopBackOff   7 (2m ago)    1h\n",
+		"kubectl get pods -o json -n shop": `{"apiVersion": "v1", "kind": "List", "items": [
+  {"metadata": {"name": "web-1", "namespace": "shop"}}]}`,
+		"kubectl get pods -o json -n team-a": `{"apiVersion": "v1", "kind": "List", "items": [
+  {"metadata": {"name": "worker-abcdef-1", "namespace": "team-a"}}]}`,
+	}
+	var commands []string
+	worker := newTestWorker(t, func(command string) map[string]interface{} {
+		commands = append(commands, command)
+		output, ok := outputs[strings.Replace(command, " --show-managed-fields=false", "", 1)]
+		if !ok {
+			return map[string]interface{}{"error": "unexpected command: " + command}
+		}
+		return map[string]interface{}{"stdout": output}
+	})
+	executor := NewKubectlToolExecutor(worker)
+	cfg := newTestConfig("readonly")
+	cfg.SecurityConfig.SetAllowedNamespaces("shop,team-.*")
+
+	get := func(args string) (string, error) {
+		return executor.Execute(map[string]interface{}{
+			"_tool_name": "kubectl_resources",
+			"operation":  "get",
+			"resource":   "pods",
+			"args":       args,
+		}, cfg)
+	}
+
+	// Refused without the option
+	if _, err := get("-A"); err == nil {
+		t.Fatal("expected get pods -A to be refused while namespaces are restricted")
+	}
+
+	cfg.ExpandAllNamespaces = true
+	result, err := get("-A")
+	if err != nil {
+		t.Fatalf("unexpected error: %v (commands: %v)", err, commands)
+	}
+	want := "NAMESPACE   NAME              READY   STATUS             RESTARTS     AGE\n" +
+		"shop        web-1             1/1     Running            0            5d\n" +
+		"team-a      worker-abcdef-1   0/1     CrashLoopBackOff   7 (2m ago)   1h\n"
+	if result != want {
+		t.Errorf("expected\n%s\ngot\n%s", want, result)
+	}
+	for _, command := range commands {
+		if strings.Contains(command, "-n default") || strings.Contains(command, "-n kube-system") {
+			t.Errorf("expected only allowed namespaces to be read, got %s", command)
+		}
+	}
+
+	result, err = get("--all-namespaces -o json")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var list struct {
+		Items []map[string]interface{} `json:"items"`
+	}
+	if err := json.Unmarshal([]byte(result), &list); err != nil {
+		t.Fatalf("expected a JSON list, got %q: %v", result, err)
+	}
+	var names []string
+	for _, item := range list.Items {
+		namespace, name := objectNamespacedName(item)
+		names = append(names, namespace+"/"+name)
+	}
+	if strings.Join(names, ",") != "shop/web-1,team-a/worker-abcdef-1" {
+		t.Errorf("expected the pods of the allowed namespaces, got %v", names)
+	}
+
+	// Changes across all namespaces are still refused
+	cfg.SecurityConfig.AccessLevel = "readwrite"
+	_, err = executor.Execute(map[string]interface{}{
+		"_tool_name": "kubectl_resources",
+		"operation":  "delete",
+		"resource":   "pods",
+		"args":       "-A -l app=web",
+	}, cfg)
+	if err == nil {
+		t.Error("expected a delete across all namespaces to be refused")
+	}
+}
+
+func TestDropAllNamespaces(t *testing.T) {
+	tests := map[string]string{
+		"get pods -A":                             "get pods",
+		"get pods --all-namespaces -o wide":       "get pods -o wide",
+		"get pods --all-namespaces=true -l app=a": "get pods -l app=a",
+		"exec web -A -- ls -A":                    "exec web -- ls -A",
+	}
+	for command, want := range tests {
+		if got := dropAllNamespaces(command); got != want {
+			t.Errorf("dropAllNamespaces(%q) = %q, want %q", command, got, want)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package kubectl
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+var errMalformedExplain = errors.New("malformed explain output")
+
+// explainFieldLine matches a field in the FIELDS section of explain output, e.g.
+// "  name	<string> -required-"
+var explainFieldLine = regexp.MustCompile(`^(\s+)([A-Za-z0-9_$.-]+)\s+<([^>]+)>(\s+-required-)?\s*$`)
+
+// explainHeader matches a header of explain output, such as "KIND:" or "FIELD:"
+var explainHeader = regexp.MustCompile(`^([A-Z]+):\s*(.*)$`)
+
+// explainResult is the parsed output of kubectl explain for a resource or one of its fields
+type explainResult struct {
+	Kind    string `json:"kind"`
+	Group   string `json:"group,omitempty"`
+	Version string `json:"version"`
+	// Field and Type are set when a nested field was explained, e.g. pods.spec.containers
+	Field       string         `json:"field,omitempty"`
+	Type        string         `json:"type,omitempty"`
+	Description string         `json:"description"`
+	Fields      []explainField `json:"fields"`
+}
+
+// explainField is one field under the explained resource or field. With --recursive, Field
+// is the dotted path below it, e.g. "spec.containers".
+type explainField struct {
+	Field       string `json:"field"`
+	Type        string `json:"type"`
+	Description string `json:"description"`
+	Required    bool   `json:"required"`
+}
+
+// parseExplain parses kubectl explain output, in both the layout of kubectl 1.27 and later
+// (FIELD:) and the earlier one (RESOURCE:), with or without --recursive
+func parseExplain(output string) (interface{}, error) {
+	result := explainResult{Fields: []explainField{}}
+	section := ""
+	var description []string
+	var field *explainField
+	var fieldDescription []string
+	type level struct {
+		indent int
+		name   string
+	}
+	var path []level
+
+	endField := func() {
+		if field != nil {
+			field.Description = joinExplainText(fieldDescription)
+			result.Fields = append(result.Fields, *field)
+			field, fieldDescription = nil, nil
+		}
+	}
+
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		if match := explainHeader.FindStringSubmatch(line); match != nil {
+			endField()
+			section = match[1]
+			value := strings.TrimSpace(match[2])
+			switch section {
+			case "KIND":
+				result.Kind = value
+			case "GROUP":
+				result.Group = value
+			case "VERSION":
+				result.Version = value
+			case "FIELD", "RESOURCE":
+				name, fieldType, ok := strings.Cut(value, " ")
+				if !ok {
+					return nil, errMalformedExplain
+				}
+				result.Field = name
+				result.Type = strings.Trim(strings.TrimSpace(fieldType), "<>")
+			}
+			continue
+		}
+
+		switch section {
+		case "DESCRIPTION":
+			description = append(description, line)
+		case "FIELDS":
+			match := explainFieldLine.FindStringSubmatch(line)
+			if match == nil {
+				if field == nil && strings.TrimSpace(line) != "" {
+					return nil, errMalformedExplain
+				}
+				fieldDescription = append(fieldDescription, line)
+				continue
+			}
+			endField()
+			indent := len(match[1])
+			for len(path) > 0 && path[len(path)-1].indent >= indent {
+				path = path[:len(path)-1]
+			}
+			path = append(path, level{indent: indent, name: match[2]})
+			names := make([]string, len(path))
+			for i, entry := range path {
+				names[i] = entry.name
+			}
+			field = &explainField{Field: strings.Join(names, "."), Type: match[3], Required: match[4] != ""}
+		}
+	}
+	endField()
+
+	if result.Kind == "" {
+		return nil, errMalformedExplain
+	}
+	result.Description = joinExplainText(description)
+	return result, nil
+}
+
+// joinExplainText joins the wrapped lines of an explain description, keeping paragraph breaks
+func joinExplainText(lines []string) string {
+	var paragraphs []string
+	var current []string
+	for _, line := range lines {
+		if line = strings.TrimSpace(line); line == "" {
+			if len(current) > 0 {
+				paragraphs = append(paragraphs, strings.Join(current, " "))
+				current = nil
+			}
+			continue
+		}
+		current = append(current, line)
+	}
+	if len(current) > 0 {
+		paragraphs = append(paragraphs, strings.Join(current, " "))
+	}
+	return strings.Join(paragraphs, "\n\n")
+}
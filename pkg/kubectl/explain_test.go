@@ -0,0 +1,93 @@
+package kubectl
+
+import (
+	"reflect"
+	"testing"
+)
+
+const explainContainersOutput = `KIND:       Pod
+VERSION:    v1
+
+FIELD: containers <[]Container>
+
+DESCRIPTION:
+    List of containers belonging to the pod. Containers cannot currently be
+    added or removed. There must be at least one container in a Pod.
+
+    A single application container that you want to run within a pod.
+
+FIELDS:
+  args	<[]string>
+    Arguments to the entrypoint. The container image's CMD is used if this is
+    not provided.
+
+  image	<string>
+    Container image name.
+
+  name	<string> -required-
+    Name of the container specified as a DNS_LABEL.
+
+`
+
+func TestParseExplain(t *testing.T) {
+	got, err := parseExplain(explainContainersOutput)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := explainResult{
+		Kind:        "Pod",
+		Version:     "v1",
+		Field:       "containers",
+		Type:        "[]Container",
+		Description: "List of containers belonging to the pod. Containers cannot currently be added or removed. There must be at least one container in a Pod.\n\nA single application container that you want to run within a pod.",
+		Fields: []explainField{
+			{Field: "args", Type: "[]string", Description: "Arguments to the entrypoint. The container image's CMD is used if this is not provided."},
+			{Field: "image", Type: "string", Description: "Container image name."},
+			{Field: "name", Type: "string", Description: "Name of the container specified as a DNS_LABEL.", Required: true},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestParseExplainRecursive(t *testing.T) {
+	output := `KIND:     Deployment
+VERSION:  apps/v1
+
+RESOURCE: spec <Object>
+
+DESCRIPTION:
+     Specification of the desired behavior of the Deployment.
+
+FIELDS:
+   replicas	<integer>
+   selector	<Object> -required-
+      matchLabels	<map[string]string>
+   template	<Object> -required-
+      spec	<Object>
+         containers	<[]Object> -required-
+`
+	got, err := parseExplain(output)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var fields []string
+	for _, field := range got.(explainResult).Fields {
+		fields = append(fields, field.Field)
+	}
+	want := []string{"replicas", "selector", "selector.matchLabels", "template", "template.spec", "template.spec.containers"}
+	if !reflect.DeepEqual(fields, want) {
+		t.Errorf("expected fields %v, got %v", want, fields)
+	}
+	if result := got.(explainResult); result.Field != "spec" || result.Type != "Object" || !result.Fields[1].Required {
+		t.Errorf("unexpected result %+v", result)
+	}
+}
+
+func TestFormatStructuredExplainFallback(t *testing.T) {
+	output := "error: field \"nope\" does not exist"
+	if got := formatStructured("explain pods.nope", output); got != output {
+		t.Errorf("expected raw output on parse failure, got %q", got)
+	}
+}
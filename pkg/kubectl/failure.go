@@ -0,0 +1,77 @@
+package kubectl
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// commandFailure is a failed mutating command with kubectl's error summary pulled out of the
+// full output, which is often buried under warnings and long validation detail
+type commandFailure struct {
+	Summary string `json:"summary"`
+	Output  string `json:"output"`
+}
+
+func (f *commandFailure) Error() string {
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return f.Output
+	}
+	return string(data)
+}
+
+// summarizeFailure wraps the error of a failed mutating command in a commandFailure. Errors
+// with no recognizable kubectl message are returned unchanged.
+func summarizeFailure(err error) error {
+	output := err.Error()
+	var exitErr *exitError
+	if errors.As(err, &exitErr) {
+		output = strings.TrimSpace(strings.Join([]string{exitErr.Stderr, exitErr.Stdout}, "\n"))
+		if output == "" {
+			return err
+		}
+	}
+
+	summary := failureSummary(output)
+	if summary == "" {
+		return err
+	}
+	return &commandFailure{Summary: summary, Output: output}
+}
+
+// failureSummary returns the first meaningful line of kubectl error output, preferring a line
+// that reports the error itself over warnings and client-side log noise
+func failureSummary(output string) string {
+	var fallback string
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || isFailureNoise(line) {
+			continue
+		}
+		if isErrorLine(line) {
+			return line
+		}
+		if fallback == "" {
+			fallback = line
+		}
+	}
+	return fallback
+}
+
+// isErrorLine reports lines in which kubectl states what went wrong
+func isErrorLine(line string) bool {
+	return strings.HasPrefix(line, "error:") ||
+		strings.HasPrefix(line, "Error from server") ||
+		strings.HasPrefix(line, "The ") && strings.Contains(line, " is invalid")
+}
+
+// isFailureNoise reports warnings and klog lines that precede the actual error
+func isFailureNoise(line string) bool {
+	if strings.HasPrefix(line, "Warning:") {
+		return true
+	}
+	// klog lines start with a severity letter and a date, e.g. W0102 15:04:05.000000
+	return len(line) > 5 && strings.ContainsRune("IWE", rune(line[0])) &&
+		strings.Trim(line[1:5], "0123456789") == ""
+}
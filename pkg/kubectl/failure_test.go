@@ -0,0 +1,73 @@
+package kubectl
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestFailureSummary(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   string
+		expected string
+	}{
+		{
+			name: "validation error after warnings",
+			output: `Warning: resource deployments/web is missing the kubectl.kubernetes.io/last-applied-configuration annotation
+W0102 15:04:05.000000   12345 helpers.go:123] some client-side log line
+error: error validating "deploy.yaml": error validating data: ValidationError(Deployment.spec): unknown field "replicaz" in io.k8s.api.apps.v1.DeploymentSpec; if you choose to ignore these errors, turn validation off with --validate=false`,
+			expected: `error: error validating "deploy.yaml": error validating data: ValidationError(Deployment.spec): unknown field "replicaz" in io.k8s.api.apps.v1.DeploymentSpec; if you choose to ignore these errors, turn validation off with --validate=false`,
+		},
+		{
+			name: "invalid object",
+			output: `The Deployment "web" is invalid:
+* spec.template.metadata.labels: Invalid value: map[string]string{"app":"api"}: ` + "`selector`" + ` does not match template ` + "`labels`",
+			expected: `The Deployment "web" is invalid:`,
+		},
+		{
+			name:     "no error prefix",
+			output:   "\nsomething went wrong\nmore detail",
+			expected: "something went wrong",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := failureSummary(tt.output); got != tt.expected {
+				t.Errorf("failureSummary() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestKubectlToolExecutor_CreateFailureSummary(t *testing.T) {
+	stderr := `Warning: would violate PodSecurity "restricted:latest": allowPrivilegeEscalation != false
+Error from server (AlreadyExists): deployments.apps "web" already exists`
+
+	worker := newTestWorker(t, func(command string) map[string]interface{} {
+		return map[string]interface{}{"stderr": stderr, "exit_code": float64(1)}
+	})
+	executor := NewKubectlToolExecutor(worker)
+
+	_, err := executor.Execute(map[string]interface{}{
+		"_tool_name": "kubectl_resources",
+		"operation":  "create",
+		"resource":   "deployment",
+		"args":       "web --image=nginx -n shop",
+	}, newTestConfig("readwrite"))
+	if err == nil {
+		t.Fatal("expected create to fail")
+	}
+
+	var failure commandFailure
+	if jsonErr := json.Unmarshal([]byte(err.Error()), &failure); jsonErr != nil {
+		t.Fatalf("error is not a JSON failure: %v\n%s", jsonErr, err.Error())
+	}
+	if failure.Summary != `Error from server (AlreadyExists): deployments.apps "web" already exists` {
+		t.Errorf("unexpected summary: %q", failure.Summary)
+	}
+	if !strings.Contains(failure.Output, "PodSecurity") {
+		t.Errorf("full output should be kept, got %q", failure.Output)
+	}
+}
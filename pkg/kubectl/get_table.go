@@ -0,0 +1,76 @@
+package kubectl
+
+import (
+	"regexp"
+	"strings"
+)
+
+// tableColumnGap separates the columns of a kubectl table header. Header names may contain
+// single spaces, like NOMINATED NODE, but columns are padded by at least two.
+var tableColumnGap = regexp.MustCompile(`\S+(?: \S+)*`)
+
+// getTable is the structured form of kubectl's default table output for get
+type getTable struct {
+	Columns []string   `json:"columns"`
+	Rows    [][]string `json:"rows"`
+	// Truncated holds the --max-list-items note when rows were left out
+	Truncated string `json:"truncated,omitempty"`
+}
+
+// parseGetTable splits the table printed by get without -o (or with -o wide) into columns
+// and rows. Cells are cut at the header's column offsets, since values such as ages or
+// label lists may contain spaces. Output holding several tables, such as a get of more than
+// one resource type, is rejected.
+func parseGetTable(output string) (interface{}, error) {
+	lines := strings.Split(strings.TrimRight(output, "\n"), "\n")
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) == "" {
+		return nil, errMalformedTable
+	}
+
+	header := lines[0]
+	spans := tableColumnGap.FindAllStringIndex(header, -1)
+	table := &getTable{Rows: [][]string{}}
+	starts := make([]int, len(spans))
+	for i, span := range spans {
+		table.Columns = append(table.Columns, header[span[0]:span[1]])
+		starts[i] = span[0]
+	}
+
+	for i, line := range lines[1:] {
+		if strings.HasPrefix(line, "... truncated:") && i == len(lines)-2 {
+			table.Truncated = line
+			break
+		}
+		if strings.TrimSpace(line) == "" {
+			return nil, errMalformedTable
+		}
+
+		row := make([]string, len(starts))
+		for col, start := range starts {
+			if start > len(line) {
+				return nil, errMalformedTable
+			}
+			// A cell must start at its column, right after padding
+			if start > 0 && line[start-1] != ' ' {
+				return nil, errMalformedTable
+			}
+			end := len(line)
+			if col+1 < len(starts) && starts[col+1] < end {
+				end = starts[col+1]
+			}
+			row[col] = strings.TrimSpace(line[start:end])
+		}
+		table.Rows = append(table.Rows, row)
+	}
+	return table, nil
+}
+
+// isTableGet reports whether a get command prints kubectl's default or wide table
+func isTableGet(command string) bool {
+	cmdline, err := parseCommandLine(command)
+	if err != nil || cmdline.verb() != "get" {
+		return false
+	}
+	format, ok := cmdline.flag("--output")
+	return !ok || format == "wide"
+}
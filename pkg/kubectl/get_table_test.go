@@ -0,0 +1,88 @@
+package kubectl
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+const podsWideTable = `NAME    READY   STATUS             RESTARTS      AGE   IP          NODE     NOMINATED NODE   READINESS GATES
+web-0   1/1     Running            0             3d    10.0.0.12   node-1   <none>           <none>
+web-1   0/1     CrashLoopBackOff   5 (2m ago)    3d    10.0.0.13   node-2   <none>           <none>
+`
+
+func TestParseGetTable(t *testing.T) {
+	value, err := parseGetTable(podsWideTable)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	table := value.(*getTable)
+
+	wantColumns := []string{"NAME", "READY", "STATUS", "RESTARTS", "AGE", "IP", "NODE", "NOMINATED NODE", "READINESS GATES"}
+	if !reflect.DeepEqual(table.Columns, wantColumns) {
+		t.Errorf("columns = %q, want %q", table.Columns, wantColumns)
+	}
+	wantRows := [][]string{
+		{"web-0", "1/1", "Running", "0", "3d", "10.0.0.12", "node-1", "<none>", "<none>"},
+		{"web-1", "0/1", "CrashLoopBackOff", "5 (2m ago)", "3d", "10.0.0.13", "node-2", "<none>", "<none>"},
+	}
+	if !reflect.DeepEqual(table.Rows, wantRows) {
+		t.Errorf("rows = %q, want %q", table.Rows, wantRows)
+	}
+
+	// The --max-list-items marker is reported separately from the rows
+	truncated := "NAME    READY\nweb-0   1/1\n... truncated: showing 1 of 2 items (--max-list-items)\n"
+	value, err = parseGetTable(truncated)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if table := value.(*getTable); len(table.Rows) != 1 || table.Truncated == "" {
+		t.Errorf("unexpected truncated table: %+v", table)
+	}
+
+	// Several tables can't be represented as one
+	if _, err := parseGetTable("NAME    READY\nweb-0   1/1\n\nNAME   TYPE\nweb    ClusterIP\n"); err == nil {
+		t.Error("expected output with several tables to be rejected")
+	}
+}
+
+func TestKubectlToolExecutor_StructuredGet(t *testing.T) {
+	worker := newTestWorker(t, func(command string) map[string]interface{} {
+		if command == "kubectl get pods -n shop -o json --show-managed-fields=false" {
+			return map[string]interface{}{"stdout": `{"items": []}`}
+		}
+		return map[string]interface{}{"stdout": podsWideTable}
+	})
+	executor := NewKubectlToolExecutor(worker)
+	cfg := newTestConfig("readonly")
+
+	output, err := executor.Execute(map[string]interface{}{
+		"_tool_name": "kubectl_resources",
+		"operation":  "get",
+		"resource":   "pods",
+		"args":       "-n shop -o wide",
+		"structured": true,
+	}, cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var table getTable
+	if err := json.Unmarshal([]byte(output), &table); err != nil {
+		t.Fatalf("output is not a JSON table: %v\n%s", err, output)
+	}
+	if len(table.Columns) != 9 || len(table.Rows) != 2 || table.Rows[1][3] != "5 (2m ago)" {
+		t.Errorf("unexpected table: %+v", table)
+	}
+
+	// Other output formats are returned as they are
+	output, err = executor.Execute(map[string]interface{}{
+		"_tool_name": "kubectl_resources",
+		"operation":  "get",
+		"resource":   "pods",
+		"args":       "-n shop -o json",
+		"structured": true,
+	}, cfg)
+	if err != nil || output != `{"items": []}` {
+		t.Errorf("json output = %q, %v; want it unchanged", output, err)
+	}
+}
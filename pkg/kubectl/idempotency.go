@@ -0,0 +1,120 @@
+package kubectl
+
+import (
+	"container/list"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// idempotencyKeyMaxLen bounds the idempotency keys callers may pass
+const idempotencyKeyMaxLen = 256
+
+// idempotentCall is a change made under an idempotency key. done is closed once it finishes;
+// until then, a retry with the same key waits for it.
+type idempotentCall struct {
+	key     string
+	command string
+	expires time.Time
+	done    chan struct{}
+	output  string
+	err     error
+}
+
+// idempotencyStore remembers the results of successful changes made under an idempotency key
+// for ttl, oldest first out once size keys are held. Failed changes are forgotten so a retry
+// runs them again.
+type idempotencyStore struct {
+	mu    sync.Mutex
+	size  int
+	ttl   time.Duration
+	order *list.List
+	calls map[string]*list.Element
+}
+
+func newIdempotencyStore(size int, ttl time.Duration) *idempotencyStore {
+	if size <= 0 || ttl <= 0 {
+		return nil
+	}
+	return &idempotencyStore{size: size, ttl: ttl, order: list.New(), calls: make(map[string]*list.Element)}
+}
+
+// TrackIdempotencyKeys remembers the results of the last size changes made with an
+// idempotency_key for ttl, for this executor and each tenant's, so a retried call returns the
+// first call's result instead of running again. 0 turns idempotency keys off.
+func (e *KubectlToolExecutor) TrackIdempotencyKeys(size int, ttl time.Duration) {
+	e.idempotency = newIdempotencyStore(size, ttl)
+	for _, tenant := range e.tenants {
+		tenant.idempotency = newIdempotencyStore(size, ttl)
+	}
+}
+
+// idempotencyKey returns the idempotency_key parameter, checking it may be used for a command
+func (e *KubectlToolExecutor) idempotencyKey(command string, params map[string]interface{}) (string, error) {
+	key, _ := params["idempotency_key"].(string)
+	if key = strings.TrimSpace(key); key == "" {
+		return "", nil
+	}
+	if e.idempotency == nil {
+		return "", fmt.Errorf("idempotency_key is not available: idempotency keys are turned off on this server")
+	}
+	if len(key) > idempotencyKeyMaxLen {
+		return "", fmt.Errorf("idempotency_key must be at most %d characters", idempotencyKeyMaxLen)
+	}
+	if e.determineCommandCategory(command) == "read-only" {
+		return "", fmt.Errorf("idempotency_key is only supported for commands that change resources")
+	}
+	return key, nil
+}
+
+// do runs a command under an idempotency key. A key seen before returns the earlier result, or
+// waits for it while that call is still running; a key reused for a different command is an
+// error.
+func (s *idempotencyStore) do(key, command string, run func() (string, error)) (string, error) {
+	s.mu.Lock()
+	now := time.Now()
+	s.expire(now)
+	if element, ok := s.calls[key]; ok {
+		call := element.Value.(*idempotentCall)
+		s.mu.Unlock()
+		if call.command != command {
+			return "", fmt.Errorf("idempotency_key '%s' was already used for a different command", key)
+		}
+		<-call.done
+		return call.output, call.err
+	}
+	call := &idempotentCall{key: key, command: command, expires: now.Add(s.ttl), done: make(chan struct{})}
+	s.calls[key] = s.order.PushBack(call)
+	for s.order.Len() > s.size {
+		s.remove(s.order.Front())
+	}
+	s.mu.Unlock()
+
+	call.output, call.err = run()
+	close(call.done)
+	if call.err != nil {
+		s.mu.Lock()
+		if element, ok := s.calls[key]; ok && element.Value == call {
+			s.remove(element)
+		}
+		s.mu.Unlock()
+	}
+	return call.output, call.err
+}
+
+// expire drops the keys whose ttl has passed; the caller holds mu
+func (s *idempotencyStore) expire(now time.Time) {
+	for element := s.order.Front(); element != nil; element = s.order.Front() {
+		if element.Value.(*idempotentCall).expires.After(now) {
+			return
+		}
+		s.remove(element)
+	}
+}
+
+// remove drops a key; the caller holds mu
+func (s *idempotencyStore) remove(element *list.Element) {
+	s.order.Remove(element)
+	delete(s.calls, element.Value.(*idempotentCall).key)
+}
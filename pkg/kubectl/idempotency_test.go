@@ -0,0 +1,100 @@
+package kubectl
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestKubectlToolExecutor_IdempotencyKey(t *testing.T) {
+	var commands []string
+	fail := false
+	worker := newTestWorker(t, func(command string) map[string]interface{} {
+		commands = append(commands, command)
+		if fail {
+			return map[string]interface{}{"stderr": "error: connection refused", "exit_code": 1}
+		}
+		return map[string]interface{}{"stdout": "deployment.apps/web created\n"}
+	})
+	executor := NewKubectlToolExecutor(worker)
+	executor.TrackIdempotencyKeys(10, time.Minute)
+	cfg := newTestConfig("readwrite")
+
+	call := func(key, args string) (string, error) {
+		return executor.Execute(map[string]interface{}{
+			"_tool_name":      "kubectl_resources",
+			"operation":       "create",
+			"resource":        "deployment",
+			"args":            args,
+			"idempotency_key": key,
+		}, cfg)
+	}
+
+	first, err := call("create-web-1", "web --image=nginx -n shop")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	retry, err := call("create-web-1", "web --image=nginx -n shop")
+	if err != nil {
+		t.Fatalf("unexpected error on retry: %v", err)
+	}
+	if retry != first || len(commands) != 1 {
+		t.Errorf("expected the retry to return %q without running again, got %q after commands %v", first, retry, commands)
+	}
+
+	if _, err := call("create-web-1", "api --image=nginx -n shop"); err == nil || !strings.Contains(err.Error(), "different command") {
+		t.Errorf("expected an error for a key reused with a different command, got %v", err)
+	}
+
+	// A failed change is forgotten, so its retry runs again
+	fail = true
+	if _, err := call("create-api-1", "api --image=nginx -n shop"); err == nil {
+		t.Fatal("expected the first attempt to fail")
+	}
+	fail = false
+	if _, err := call("create-api-1", "api --image=nginx -n shop"); err != nil {
+		t.Fatalf("unexpected error on retry: %v", err)
+	}
+	if len(commands) != 3 {
+		t.Errorf("expected the failed change to run again, got commands %v", commands)
+	}
+
+	// Keys are only for changes
+	_, err = executor.Execute(map[string]interface{}{
+		"_tool_name":      "kubectl_resources",
+		"operation":       "get",
+		"resource":        "pods",
+		"args":            "-n shop",
+		"idempotency_key": "list-1",
+	}, cfg)
+	if err == nil || !strings.Contains(err.Error(), "only supported for commands that change resources") {
+		t.Errorf("expected an error for a key on a read, got %v", err)
+	}
+}
+
+func TestIdempotencyStoreBounds(t *testing.T) {
+	store := newIdempotencyStore(2, time.Minute)
+	runs := 0
+	run := func() (string, error) {
+		runs++
+		return "done", nil
+	}
+
+	for _, key := range []string{"a", "b", "c"} {
+		if _, err := store.do(key, "create cm "+key, run); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if _, ok := store.calls["a"]; ok || len(store.calls) != 2 {
+		t.Errorf("expected the oldest key to be dropped, got %d keys", len(store.calls))
+	}
+
+	// Expired keys are dropped before a lookup
+	store.calls["b"].Value.(*idempotentCall).expires = time.Now().Add(-time.Second)
+	if _, err := store.do("b", "create cm b", run); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if runs != 4 {
+		t.Errorf("expected an expired key to run again, got %d runs", runs)
+	}
+}
@@ -0,0 +1,196 @@
+package kubectl
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Azure/mcp-kubernetes/pkg/config"
+)
+
+// impersonationValue restricts impersonated user and group names to characters that need no quoting
+var impersonationValue = regexp.MustCompile(`^[A-Za-z0-9@:._\-]+$`)
+
+// impersonation is the identity a command runs as, from the as/as_group parameters
+type impersonation struct {
+	user   string
+	groups []string
+}
+
+// impersonationFromParams reads the as and as_group parameters; as_group is comma-separated
+func impersonationFromParams(params map[string]interface{}) (impersonation, error) {
+	var imp impersonation
+
+	if user, ok := params["as"].(string); ok {
+		imp.user = strings.TrimSpace(user)
+	}
+	if groups, ok := params["as_group"].(string); ok {
+		for _, group := range strings.Split(groups, ",") {
+			if group = strings.TrimSpace(group); group != "" {
+				imp.groups = append(imp.groups, group)
+			}
+		}
+	}
+
+	if imp.user == "" && len(imp.groups) > 0 {
+		return imp, fmt.Errorf("as_group requires as to be set")
+	}
+	for _, value := range append([]string{imp.user}, imp.groups...) {
+		if value != "" && !impersonationValue.MatchString(value) {
+			return imp, fmt.Errorf("invalid impersonation identity '%s'", value)
+		}
+	}
+	return imp, nil
+}
+
+// impersonationFromCommand reads --as and --as-group flags already present in a command
+func impersonationFromCommand(cmdline *commandLine) impersonation {
+	user, _ := cmdline.flag("--as")
+	return impersonation{user: user, groups: cmdline.flags["--as-group"]}
+}
+
+// flags renders the impersonation as kubectl flags
+func (i impersonation) flags() []string {
+	var flags []string
+	if i.user != "" {
+		flags = append(flags, "--as="+i.user)
+	}
+	for _, group := range i.groups {
+		flags = append(flags, "--as-group="+group)
+	}
+	return flags
+}
+
+// apply adds the impersonation flags to a command, ahead of any "--" separator
+func (i impersonation) apply(command string) string {
+	flags := i.flags()
+	if len(flags) == 0 {
+		return command
+	}
+	return insertFlags(command, flags)
+}
+
+// insertFlags adds flags to a command before a "--" separator, or at the end if there is none
+func insertFlags(command string, flags []string) string {
+	joined := strings.Join(flags, " ")
+	if before, after, ok := strings.Cut(command, " -- "); ok {
+		return before + " " + joined + " -- " + after
+	}
+	return command + " " + joined
+}
+
+// injectImpersonationNamespace adds the namespace mapped to the impersonated user when the
+// command doesn't name one. The result is still subject to the namespace allow-list.
+func injectImpersonationNamespace(command string, cfg *config.ConfigData) string {
+	if len(cfg.ImpersonationNamespaces) == 0 {
+		return command
+	}
+
+	cmdline, err := parseCommandLine(command)
+	if err != nil || cmdline.hasFlag("--namespace") || cmdline.hasFlag("--all-namespaces") {
+		return command
+	}
+
+	user, _ := cmdline.flag("--as")
+	namespace, ok := cfg.ImpersonationNamespaces[user]
+	if !ok || user == "" {
+		return command
+	}
+	return insertFlags(command, []string{"-n", namespace})
+}
+
+// canICommand builds an `auth can-i` check for verb on resource (optionally TYPE/NAME) as the given identity
+func canICommand(verb, resource, namespace string, imp impersonation) string {
+	parts := []string{"auth", "can-i", verb, resource}
+	if namespace != "" {
+		parts = append(parts, "-n", namespace)
+	}
+	return strings.Join(append(parts, imp.flags()...), " ")
+}
+
+// preflightVerbs maps write commands to the RBAC verb they need on their target
+var preflightVerbs = map[string]string{
+	"create":   "create",
+	"delete":   "delete",
+	"apply":    "patch",
+	"patch":    "patch",
+	"replace":  "update",
+	"label":    "patch",
+	"annotate": "patch",
+	"scale":    "patch",
+	"set":      "patch",
+	"rollout":  "patch",
+	"cordon":   "patch",
+	"uncordon": "patch",
+	"taint":    "patch",
+	"drain":    "patch",
+}
+
+// preflightTarget derives the can-i arguments for a write command. It returns false when the
+// target can't be determined from the command line, e.g. for file-based operations.
+func preflightTarget(cmdline *commandLine) (verb, resource string, ok bool) {
+	verb, ok = preflightVerbs[cmdline.verb()]
+	if !ok {
+		return "", "", false
+	}
+
+	args := cmdline.args()
+	switch cmdline.verb() {
+	case "cordon", "uncordon", "drain":
+		if len(args) == 0 {
+			return "", "", false
+		}
+		return verb, "nodes/" + args[0], true
+	case "rollout", "set":
+		// Skip the subcommand, e.g. "rollout restart deployment/web"
+		if len(args) > 0 {
+			args = args[1:]
+		}
+	}
+
+	if len(args) == 0 {
+		return "", "", false
+	}
+	if strings.Contains(args[0], "/") {
+		return verb, args[0], true
+	}
+	if len(args) > 1 && !strings.Contains(args[1], "=") {
+		return verb, args[0] + "/" + args[1], true
+	}
+	return verb, args[0], true
+}
+
+// preflightAuthz checks with `auth can-i` that the identity the command will run as, including
+// any impersonation, is allowed to perform it. Commands whose target can't be determined are
+// left to the API server.
+func (e *KubectlToolExecutor) preflightAuthz(command string, cfg *config.ConfigData) error {
+	cmdline, err := parseCommandLine(command)
+	if err != nil {
+		return err
+	}
+
+	verb, resource, ok := preflightTarget(cmdline)
+	if !ok {
+		return nil
+	}
+	namespace, _ := cmdline.flag("--namespace")
+
+	check := canICommand(verb, resource, namespace, impersonationFromCommand(cmdline))
+	// can-i exits with 1 when the answer is no, which runReadCommand returns as output
+	output, err := e.runReadCommand(check, cfg)
+	if err != nil {
+		return fmt.Errorf("preflight authorization check failed: %w", err)
+	}
+	if strings.TrimSpace(output) != "yes" {
+		return fmt.Errorf("preflight denied: %s is not allowed to %s %s", identityName(cmdline), verb, resource)
+	}
+	return nil
+}
+
+// identityName describes who a command runs as, for error messages
+func identityName(cmdline *commandLine) string {
+	if user, ok := cmdline.flag("--as"); ok && user != "" {
+		return "'" + user + "'"
+	}
+	return "the current identity"
+}
@@ -0,0 +1,216 @@
+package kubectl
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestImpersonationApply(t *testing.T) {
+	tests := []struct {
+		name     string
+		params   map[string]interface{}
+		command  string
+		expected string
+		wantErr  bool
+	}{
+		{"No impersonation", map[string]interface{}{}, "get pods", "get pods", false},
+		{"User", map[string]interface{}{"as": "alice"}, "get pods -n dev", "get pods -n dev --as=alice", false},
+		{"User and groups", map[string]interface{}{"as": "system:serviceaccount:dev:ci", "as_group": "devs, ops"},
+			"delete pod web", "delete pod web --as=system:serviceaccount:dev:ci --as-group=devs --as-group=ops", false},
+		{"Before exec separator", map[string]interface{}{"as": "alice"}, "exec web -- ls -la", "exec web --as=alice -- ls -la", false},
+		{"Group without user", map[string]interface{}{"as_group": "devs"}, "get pods", "", true},
+		{"Unsafe value", map[string]interface{}{"as": "alice; rm -rf /"}, "get pods", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			imp, err := impersonationFromParams(tt.params)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := imp.apply(tt.command); got != tt.expected {
+				t.Errorf("apply() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCanICommand(t *testing.T) {
+	imp := impersonation{user: "alice", groups: []string{"devs"}}
+	got := canICommand("delete", "pods/web", "dev", imp)
+	expected := "auth can-i delete pods/web -n dev --as=alice --as-group=devs"
+	if got != expected {
+		t.Errorf("canICommand() = %q, want %q", got, expected)
+	}
+}
+
+func TestKubectlToolExecutor_PreflightCarriesImpersonation(t *testing.T) {
+	var commands []string
+	worker := newTestWorker(t, func(command string) map[string]interface{} {
+		commands = append(commands, command)
+		if strings.Contains(command, "auth can-i") {
+			return map[string]interface{}{"stdout": "no\n", "exit_code": float64(1)}
+		}
+		return map[string]interface{}{"stdout": "pod \"web\" deleted"}
+	})
+	executor := NewKubectlToolExecutor(worker)
+
+	_, err := executor.Execute(map[string]interface{}{
+		"_tool_name": "kubectl_resources",
+		"operation":  "delete",
+		"resource":   "pod",
+		"args":       "web -n dev",
+		"as":         "alice",
+		"as_group":   "devs",
+		"preflight":  true,
+	}, newTestConfig("readwrite"))
+	if err == nil || !strings.Contains(err.Error(), "preflight denied") {
+		t.Fatalf("expected preflight denial, got %v", err)
+	}
+
+	if len(commands) != 1 {
+		t.Fatalf("expected only the preflight command to run, got %v", commands)
+	}
+	expected := "kubectl auth can-i delete pod/web -n dev --as=alice --as-group=devs"
+	if commands[0] != expected {
+		t.Errorf("preflight command = %q, want %q", commands[0], expected)
+	}
+}
+
+func TestKubectlToolExecutor_PreflightCheckFailure(t *testing.T) {
+	worker := newTestWorker(t, func(command string) map[string]interface{} {
+		if strings.Contains(command, "auth can-i") {
+			return map[string]interface{}{"error": "dial tcp: lookup api.example.no", "exit_code": float64(1)}
+		}
+		t.Errorf("the command should not run when the preflight check fails, got %q", command)
+		return map[string]interface{}{"stdout": ""}
+	})
+	executor := NewKubectlToolExecutor(worker)
+
+	_, err := executor.Execute(map[string]interface{}{
+		"_tool_name": "kubectl_resources",
+		"operation":  "delete",
+		"resource":   "pod",
+		"args":       "web -n dev",
+		"preflight":  true,
+	}, newTestConfig("readwrite"))
+	if err == nil || !strings.Contains(err.Error(), "preflight authorization check failed") {
+		t.Fatalf("expected a failed check rather than a denial, got %v", err)
+	}
+}
+
+func TestKubectlToolExecutor_ReadOnlyRejectsImpersonation(t *testing.T) {
+	executor := NewKubectlToolExecutor(nil)
+
+	for _, params := range []map[string]interface{}{
+		{"as": "alice"},
+		{"args": "--as=alice"},
+		{"args": "--as-group=admins --as alice"},
+	} {
+		base := map[string]interface{}{
+			"_tool_name": "kubectl_config",
+			"operation":  "auth",
+			"resource":   "can-i",
+			"args":       "get secrets -n prod",
+		}
+		for k, v := range params {
+			if k == "args" {
+				v = base["args"].(string) + " " + v.(string)
+			}
+			base[k] = v
+		}
+		_, err := executor.Execute(base, newTestConfig("readonly"))
+		if err == nil || !strings.Contains(err.Error(), "impersonation requires read-write access") {
+			t.Errorf("params %v: expected impersonation to be denied, got %v", params, err)
+		}
+	}
+}
+
+func TestKubectlToolExecutor_PreflightAllowsCommand(t *testing.T) {
+	var commands []string
+	worker := newTestWorker(t, func(command string) map[string]interface{} {
+		commands = append(commands, command)
+		if strings.Contains(command, "auth can-i") {
+			return map[string]interface{}{"stdout": "yes\n"}
+		}
+		return map[string]interface{}{"stdout": "deployment.apps/web scaled"}
+	})
+	executor := NewKubectlToolExecutor(worker)
+
+	_, err := executor.Execute(map[string]interface{}{
+		"_tool_name": "kubectl_workloads",
+		"operation":  "scale",
+		"resource":   "deployment",
+		"args":       "web --replicas=3 -n dev --as=bob",
+		"preflight":  true,
+	}, newTestConfig("readwrite"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{
+		"kubectl auth can-i patch deployment/web -n dev --as=bob",
+		"kubectl scale deployment web --replicas=3 -n dev --as=bob",
+	}
+	if strings.Join(commands, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("commands = %v, want %v", commands, expected)
+	}
+}
+
+func TestKubectlToolExecutor_ImpersonationNamespace(t *testing.T) {
+	var commands []string
+	worker := newTestWorker(t, func(command string) map[string]interface{} {
+		commands = append(commands, command)
+		return map[string]interface{}{"stdout": "No resources found"}
+	})
+	executor := NewKubectlToolExecutor(worker)
+
+	cfg := newTestConfig("readwrite")
+	cfg.ImpersonationNamespaces = map[string]string{
+		"system:serviceaccount:team-a:agent": "team-a",
+		"system:serviceaccount:team-b:agent": "team-b",
+	}
+	cfg.SecurityConfig.SetAllowedNamespaces("team-a")
+
+	get := func(params map[string]interface{}) error {
+		base := map[string]interface{}{
+			"_tool_name": "kubectl_resources",
+			"operation":  "get",
+			"resource":   "pods",
+			"args":       "",
+		}
+		for k, v := range params {
+			base[k] = v
+		}
+		_, err := executor.Execute(base, cfg)
+		return err
+	}
+
+	// The mapped namespace is injected for the impersonated user
+	if err := get(map[string]interface{}{"as": "system:serviceaccount:team-a:agent"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// An explicit namespace wins over the mapping
+	if err := get(map[string]interface{}{"as": "system:serviceaccount:team-a:agent", "args": "--namespace=team-a -l app=web"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []string{
+		"kubectl get pods --as=system:serviceaccount:team-a:agent -n team-a",
+		"kubectl get pods --namespace=team-a -l app=web --as=system:serviceaccount:team-a:agent",
+	}
+	if strings.Join(commands, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("commands = %v, want %v", commands, expected)
+	}
+
+	// The injected namespace is still checked against the allow-list
+	err := get(map[string]interface{}{"as": "system:serviceaccount:team-b:agent"})
+	if err == nil || !strings.Contains(err.Error(), "team-b") {
+		t.Errorf("expected team-b to be denied, got %v", err)
+	}
+}
@@ -0,0 +1,427 @@
+package kubectl
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/mcp-kubernetes/pkg/config"
+)
+
+// Read sources for get operations
+const (
+	ReadSourceShell    = config.ReadSourceShell
+	ReadSourceInformer = config.ReadSourceInformer
+)
+
+// informerKindNames maps the kinds the informer can cache to the prefix kubectl prints with -o name
+var informerKindNames = map[string]string{
+	"pods":        "pod",
+	"deployments": "deployment.apps",
+	"services":    "service",
+}
+
+// informerIdleResyncs is how many resync intervals a cached scope survives without being read
+const informerIdleResyncs = 10
+
+// objectStore is a local cache of Kubernetes objects keyed by kind, namespace and name
+type objectStore interface {
+	Get(kind, namespace, name string) (map[string]interface{}, bool)
+	List(kind, namespace string, selector map[string]string) []map[string]interface{}
+	// HasSynced reports whether a kind is cached for a namespace ("" for all namespaces)
+	HasSynced(kind, namespace string) bool
+}
+
+// cacheScope is a kind listed in one namespace, or in all namespaces when namespace is ""
+type cacheScope struct {
+	kind      string
+	namespace string
+}
+
+// command returns the kubectl command that lists the scope
+func (c cacheScope) command() string {
+	if c.namespace == "" {
+		return fmt.Sprintf("get %s --all-namespaces -o json", c.kind)
+	}
+	return fmt.Sprintf("get %s -n %s -o json", c.kind, c.namespace)
+}
+
+// cacheStore is the default objectStore; each scope is replaced wholesale on relist
+type cacheStore struct {
+	mu      sync.RWMutex
+	objects map[cacheScope]map[string]map[string]interface{} // scope -> "namespace/name" -> object
+}
+
+var _ objectStore = (*cacheStore)(nil)
+
+// newCacheStore creates an empty cache store
+func newCacheStore() *cacheStore {
+	return &cacheStore{
+		objects: make(map[cacheScope]map[string]map[string]interface{}),
+	}
+}
+
+// Replace swaps the cached objects of a scope for a freshly listed set
+func (s *cacheStore) Replace(scope cacheScope, items []map[string]interface{}) {
+	objects := make(map[string]map[string]interface{}, len(items))
+	for _, item := range items {
+		namespace, name := objectNamespacedName(item)
+		objects[namespace+"/"+name] = item
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.objects[scope] = objects
+}
+
+// Remove drops a scope from the cache
+func (s *cacheStore) Remove(scope cacheScope) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.objects, scope)
+}
+
+// lookup returns the cached objects that cover a kind in a namespace, preferring the
+// namespace's own scope over the all-namespaces one
+func (s *cacheStore) lookup(kind, namespace string) (map[string]map[string]interface{}, bool) {
+	if objects, ok := s.objects[cacheScope{kind, namespace}]; ok {
+		return objects, true
+	}
+	objects, ok := s.objects[cacheScope{kind, ""}]
+	return objects, ok
+}
+
+// Get returns a cached object by namespace and name
+func (s *cacheStore) Get(kind, namespace, name string) (map[string]interface{}, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	objects, _ := s.lookup(kind, namespace)
+	obj, ok := objects[namespace+"/"+name]
+	return obj, ok
+}
+
+// List returns cached objects of a kind, optionally scoped to a namespace ("" for all)
+// and filtered by equality label selector, sorted by namespace and name
+func (s *cacheStore) List(kind, namespace string, selector map[string]string) []map[string]interface{} {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	objects, _ := s.lookup(kind, namespace)
+	keys := make([]string, 0, len(objects))
+	for key, obj := range objects {
+		ns, _ := objectNamespacedName(obj)
+		if namespace != "" && ns != namespace {
+			continue
+		}
+		if !matchesLabels(obj, selector) {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	items := make([]map[string]interface{}, 0, len(keys))
+	for _, key := range keys {
+		items = append(items, objects[key])
+	}
+	return items
+}
+
+// HasSynced reports whether a kind has been listed for the namespace or for all namespaces
+func (s *cacheStore) HasSynced(kind, namespace string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, ok := s.lookup(kind, namespace)
+	return ok
+}
+
+// informer caches only the kinds and namespaces that are actually read. A read of a scope
+// that isn't cached yet goes to the API server and schedules a list; cached scopes are
+// relisted every resync interval and dropped once they go unread for informerIdleResyncs.
+type informer struct {
+	store  *cacheStore
+	resync time.Duration
+	// list runs a kubectl command and returns its output
+	list func(command string) (string, error)
+
+	mu       sync.Mutex
+	lastRead map[cacheScope]time.Time
+	// wake asks run to list newly read scopes without waiting for the next resync
+	wake chan struct{}
+}
+
+var _ objectStore = (*informer)(nil)
+
+// newInformer creates an informer that lists scopes with list
+func newInformer(resync time.Duration, list func(command string) (string, error)) *informer {
+	return &informer{
+		store:    newCacheStore(),
+		resync:   resync,
+		list:     list,
+		lastRead: make(map[cacheScope]time.Time),
+		wake:     make(chan struct{}, 1),
+	}
+}
+
+// Get returns a cached object by namespace and name
+func (i *informer) Get(kind, namespace, name string) (map[string]interface{}, bool) {
+	return i.store.Get(kind, namespace, name)
+}
+
+// List returns cached objects of a kind in a namespace ("" for all)
+func (i *informer) List(kind, namespace string, selector map[string]string) []map[string]interface{} {
+	return i.store.List(kind, namespace, selector)
+}
+
+// HasSynced records the read of a scope, so it is kept cached, and reports whether it is cached
+func (i *informer) HasSynced(kind, namespace string) bool {
+	if i.store.HasSynced(kind, namespace) {
+		i.touch(cacheScope{kind, namespace})
+		return true
+	}
+	if i.touch(cacheScope{kind, namespace}) {
+		select {
+		case i.wake <- struct{}{}:
+		default:
+		}
+	}
+	return false
+}
+
+// touch marks a scope as read now, reporting whether it wasn't tracked before. A read served
+// by the all-namespaces scope keeps that scope alive instead of starting a new one.
+func (i *informer) touch(scope cacheScope) bool {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	now := time.Now()
+	if _, ok := i.lastRead[scope]; ok {
+		i.lastRead[scope] = now
+		return false
+	}
+	all := cacheScope{scope.kind, ""}
+	if _, ok := i.lastRead[all]; ok {
+		i.lastRead[all] = now
+		return false
+	}
+	i.lastRead[scope] = now
+	return true
+}
+
+// run lists newly read scopes as they appear and relists cached ones every resync interval
+// until stop is closed
+func (i *informer) run(stop <-chan struct{}) {
+	ticker := time.NewTicker(i.resync)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			i.relistAll()
+		case <-i.wake:
+			i.listNew()
+		case <-stop:
+			return
+		}
+	}
+}
+
+// scopes returns the tracked scopes, forgetting those idle for longer than informerIdleResyncs
+func (i *informer) scopes() []cacheScope {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	idle := time.Duration(informerIdleResyncs) * i.resync
+	scopes := make([]cacheScope, 0, len(i.lastRead))
+	for scope, last := range i.lastRead {
+		if time.Since(last) > idle {
+			delete(i.lastRead, scope)
+			i.store.Remove(scope)
+			continue
+		}
+		scopes = append(scopes, scope)
+	}
+	return scopes
+}
+
+// relistAll refreshes every tracked scope, keeping the previous contents of scopes that fail
+func (i *informer) relistAll() {
+	for _, scope := range i.scopes() {
+		if err := i.relist(scope); err != nil {
+			slog.Error("informer relist failed", "kind", scope.kind, "namespace", scope.namespace, "err", err)
+		}
+	}
+}
+
+// listNew lists tracked scopes that haven't been cached yet
+func (i *informer) listNew() {
+	for _, scope := range i.scopes() {
+		if i.store.HasSynced(scope.kind, scope.namespace) {
+			continue
+		}
+		if err := i.relist(scope); err != nil {
+			slog.Error("informer list failed", "kind", scope.kind, "namespace", scope.namespace, "err", err)
+		}
+	}
+}
+
+// relist fetches all objects of a scope and replaces them in the store
+func (i *informer) relist(scope cacheScope) error {
+	output, err := i.list(scope.command())
+	if err != nil {
+		return err
+	}
+
+	var list struct {
+		Items []map[string]interface{} `json:"items"`
+	}
+	if err := json.Unmarshal([]byte(output), &list); err != nil {
+		return fmt.Errorf("failed to parse %s list: %w", scope.kind, err)
+	}
+
+	i.store.Replace(scope, list.Items)
+	return nil
+}
+
+// serveFromStore answers a get command from the store. ok is false when the command
+// is outside what the cache can answer faithfully and should go to the API server.
+func serveFromStore(store objectStore, command string) (string, bool) {
+	cl, err := parseCommandLine(command)
+	if err != nil || cl.verb() != "get" || len(cl.trailing) > 0 {
+		return "", false
+	}
+
+	args := cl.args()
+	if len(args) == 0 || len(args) > 2 {
+		return "", false
+	}
+	kind := canonicalResource(args[0])
+	if _, ok := informerKindNames[kind]; !ok {
+		return "", false
+	}
+
+	// Only flags the cache can honor are accepted
+	for name := range cl.flags {
+		switch name {
+		case "--namespace", "--all-namespaces", "--selector", "--output":
+		case "--show-managed-fields":
+			// kubectl lists the cached objects without managedFields, its default
+			if cl.boolFlag(name) {
+				return "", false
+			}
+		default:
+			return "", false
+		}
+	}
+
+	output, _ := cl.flag("--output")
+	if output != "json" && output != "name" {
+		return "", false
+	}
+
+	// The kubeconfig's default namespace isn't known locally, so require it to be explicit
+	namespace, hasNamespace := cl.flag("--namespace")
+	allNamespaces := cl.boolFlag("--all-namespaces")
+	if hasNamespace == allNamespaces {
+		return "", false
+	}
+	if !store.HasSynced(kind, namespace) {
+		return "", false
+	}
+
+	if len(args) == 2 {
+		if allNamespaces || cl.hasFlag("--selector") {
+			return "", false
+		}
+		obj, ok := store.Get(kind, namespace, args[1])
+		if !ok {
+			// Possibly created since the last relist; let the API server answer
+			return "", false
+		}
+		if output == "name" {
+			return informerKindNames[kind] + "/" + args[1] + "\n", true
+		}
+		return marshalKubectlJSON(obj)
+	}
+
+	selector := map[string]string{}
+	if raw, ok := cl.flag("--selector"); ok {
+		if selector, ok = parseEqualitySelector(raw); !ok {
+			return "", false
+		}
+	}
+
+	items := store.List(kind, namespace, selector)
+	if output == "name" {
+		var b strings.Builder
+		for _, item := range items {
+			_, name := objectNamespacedName(item)
+			b.WriteString(informerKindNames[kind] + "/" + name + "\n")
+		}
+		return b.String(), true
+	}
+
+	itemsList := make([]interface{}, 0, len(items))
+	for _, item := range items {
+		itemsList = append(itemsList, item)
+	}
+	return marshalKubectlJSON(map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "List",
+		"items":      itemsList,
+		"metadata":   map[string]interface{}{"resourceVersion": ""},
+	})
+}
+
+// marshalKubectlJSON renders an object the way kubectl -o json does
+func marshalKubectlJSON(obj interface{}) (string, bool) {
+	data, err := json.MarshalIndent(obj, "", "    ")
+	if err != nil {
+		return "", false
+	}
+	return string(data) + "\n", true
+}
+
+// objectNamespacedName returns metadata.namespace and metadata.name of an object
+func objectNamespacedName(obj map[string]interface{}) (string, string) {
+	metadata, _ := obj["metadata"].(map[string]interface{})
+	namespace, _ := metadata["namespace"].(string)
+	name, _ := metadata["name"].(string)
+	return namespace, name
+}
+
+// matchesLabels reports whether an object's labels contain every selector pair
+func matchesLabels(obj map[string]interface{}, selector map[string]string) bool {
+	metadata, _ := obj["metadata"].(map[string]interface{})
+	labels, _ := metadata["labels"].(map[string]interface{})
+	for key, want := range selector {
+		if got, _ := labels[key].(string); got != want {
+			return false
+		}
+	}
+	return true
+}
+
+// parseEqualitySelector parses "a=b,c==d" selectors; set-based and inequality selectors are not supported
+func parseEqualitySelector(raw string) (map[string]string, bool) {
+	selector := make(map[string]string)
+	for _, term := range strings.Split(raw, ",") {
+		term = strings.TrimSpace(term)
+		if term == "" || strings.Contains(term, "!") {
+			return nil, false
+		}
+		key, value, ok := strings.Cut(term, "=")
+		if !ok {
+			return nil, false
+		}
+		value = strings.TrimPrefix(value, "=")
+		if key == "" || strings.ContainsAny(key+value, " ()") {
+			return nil, false
+		}
+		selector[key] = value
+	}
+	return selector, true
+}
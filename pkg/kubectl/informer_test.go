@@ -0,0 +1,199 @@
+package kubectl
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Azure/mcp-kubernetes/pkg/config"
+)
+
+// fakeStore is an objectStore backed by a fixed set of objects
+type fakeStore struct {
+	objects map[string][]map[string]interface{}
+}
+
+func (f *fakeStore) Get(kind, namespace, name string) (map[string]interface{}, bool) {
+	for _, obj := range f.objects[kind] {
+		ns, n := objectNamespacedName(obj)
+		if ns == namespace && n == name {
+			return obj, true
+		}
+	}
+	return nil, false
+}
+
+func (f *fakeStore) List(kind, namespace string, selector map[string]string) []map[string]interface{} {
+	var items []map[string]interface{}
+	for _, obj := range f.objects[kind] {
+		ns, _ := objectNamespacedName(obj)
+		if (namespace == "" || ns == namespace) && matchesLabels(obj, selector) {
+			items = append(items, obj)
+		}
+	}
+	return items
+}
+
+func (f *fakeStore) HasSynced(kind, namespace string) bool {
+	_, ok := f.objects[kind]
+	return ok
+}
+
+func testPod(namespace, name string, labels map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"metadata": map[string]interface{}{
+			"namespace": namespace,
+			"name":      name,
+			"labels":    labels,
+		},
+	}
+}
+
+func TestKubectlToolExecutor_InformerServesGet(t *testing.T) {
+	executor := NewKubectlToolExecutor(&Worker{})
+	executor.store = &fakeStore{objects: map[string][]map[string]interface{}{
+		"pods": {
+			testPod("default", "nginx", map[string]interface{}{"app": "web"}),
+			testPod("default", "redis", map[string]interface{}{"app": "cache"}),
+		},
+	}}
+	cfg := &config.ConfigData{}
+
+	// A named get is answered from the cache without reaching the (unconfigured) worker
+	output, err := executor.runCommand("get pod nginx -n default -o json", cfg)
+	if err != nil {
+		t.Fatalf("runCommand() unexpected error = %v", err)
+	}
+	var pod map[string]interface{}
+	if err := json.Unmarshal([]byte(output), &pod); err != nil {
+		t.Fatalf("expected pod JSON, got %q", output)
+	}
+	if _, name := objectNamespacedName(pod); name != "nginx" {
+		t.Errorf("expected nginx pod, got %v", pod)
+	}
+
+	// Lists honor label selectors
+	output, err = executor.runCommand("get pods -n default -l app=cache -o name", cfg)
+	if err != nil {
+		t.Fatalf("runCommand() unexpected error = %v", err)
+	}
+	if output != "pod/redis\n" {
+		t.Errorf("expected only pod/redis, got %q", output)
+	}
+
+	// Reads the cache can't answer fall through to the worker
+	_, err = executor.runCommand("get pods -n default", cfg)
+	if err == nil || !strings.Contains(err.Error(), "remote worker is not configured") {
+		t.Errorf("expected table output to fall through to the worker, got %v", err)
+	}
+	_, err = executor.runCommand("get deployments -n default -o json", cfg)
+	if err == nil {
+		t.Errorf("expected unsynced kind to fall through to the worker")
+	}
+}
+
+func TestServeFromStore(t *testing.T) {
+	store := newCacheStore()
+	store.Replace(cacheScope{"pods", ""}, []map[string]interface{}{
+		testPod("prod", "api-1", map[string]interface{}{"app": "api"}),
+		testPod("dev", "api-2", map[string]interface{}{"app": "api"}),
+	})
+
+	tests := []struct {
+		name    string
+		command string
+		wantOK  bool
+		want    string
+	}{
+		{"all namespaces list", "get pods -A -o name", true, "pod/api-2\npod/api-1\n"},
+		{"namespaced list", "get po --namespace=prod -o name", true, "pod/api-1\n"},
+		{"missing object falls through", "get pod api-3 -n prod -o json", false, ""},
+		{"implicit namespace falls through", "get pods -o json", false, ""},
+		{"unsupported flag falls through", "get pods -n prod -o json --show-labels", false, ""},
+		{"set-based selector falls through", "get pods -n prod -l 'app in (api)' -o json", false, ""},
+		{"uncached kind falls through", "get configmaps -n prod -o json", false, ""},
+		{"non-get falls through", "describe pod api-1 -n prod", false, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := serveFromStore(store, tt.command)
+			if ok != tt.wantOK {
+				t.Fatalf("serveFromStore() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if tt.want != "" && got != tt.want {
+				t.Errorf("serveFromStore() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInformerListsOnlyReadScopes(t *testing.T) {
+	var commands []string
+	inf := newInformer(time.Minute, func(command string) (string, error) {
+		commands = append(commands, command)
+		return `{"items":[{"metadata":{"namespace":"shop","name":"web"}}]}`, nil
+	})
+
+	// Nothing is listed until something is read
+	inf.relistAll()
+	if len(commands) != 0 {
+		t.Fatalf("expected no lists before any read, got %v", commands)
+	}
+
+	// The first read of a scope misses and schedules a list of just that namespace
+	if _, ok := serveFromStore(inf, "get services -n shop -o json"); ok {
+		t.Fatalf("expected the first read to miss")
+	}
+	select {
+	case <-inf.wake:
+	default:
+		t.Fatalf("expected the read to wake the informer")
+	}
+	inf.listNew()
+	if strings.Join(commands, ";") != "get services -n shop -o json" {
+		t.Fatalf("unexpected list commands %v", commands)
+	}
+	if output, ok := serveFromStore(inf, "get services -n shop -o name"); !ok || output != "service/web\n" {
+		t.Errorf("expected the listed scope to be served, got %q, %v", output, ok)
+	}
+
+	// Other namespaces and kinds are not cached by it
+	if _, ok := serveFromStore(inf, "get pods -n shop -o json"); ok {
+		t.Errorf("pods should not be served from the services scope")
+	}
+
+	// Scopes that go unread are dropped on the next resync
+	inf.mu.Lock()
+	for scope := range inf.lastRead {
+		inf.lastRead[scope] = time.Now().Add(-informerIdleResyncs * time.Minute * 2)
+	}
+	inf.mu.Unlock()
+	commands = nil
+	inf.relistAll()
+	if len(commands) != 0 || inf.store.HasSynced("services", "shop") {
+		t.Errorf("idle scope should be dropped, relisted %v", commands)
+	}
+}
+
+func TestInformerRelist(t *testing.T) {
+	inf := newInformer(time.Minute, func(command string) (string, error) {
+		if command != "get services --all-namespaces -o json" {
+			t.Errorf("unexpected relist command %q", command)
+		}
+		return `{"items":[{"metadata":{"namespace":"default","name":"web"}}]}`, nil
+	})
+
+	if err := inf.relist(cacheScope{"services", ""}); err != nil {
+		t.Fatalf("relist() unexpected error = %v", err)
+	}
+	if !inf.store.HasSynced("services", "default") {
+		t.Fatalf("expected services to be synced")
+	}
+	if _, ok := inf.store.Get("services", "default", "web"); !ok {
+		t.Errorf("expected relisted service in store")
+	}
+}
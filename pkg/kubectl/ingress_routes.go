@@ -0,0 +1,182 @@
+package kubectl
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Azure/mcp-kubernetes/pkg/config"
+)
+
+// ingressRouteList is the routes of the Ingresses in a namespace
+type ingressRouteList struct {
+	Namespace string         `json:"namespace"`
+	Routes    []ingressRoute `json:"routes"`
+	// MissingBackends names the services routes point to that don't exist, once each
+	MissingBackends []string `json:"missing_backends"`
+}
+
+// ingressRoute is one host and path of an Ingress and the backend it sends requests to. Host is
+// "*" for rules matching any host; the default backend has no path.
+type ingressRoute struct {
+	Ingress  string `json:"ingress"`
+	Class    string `json:"class,omitempty"`
+	Host     string `json:"host"`
+	Path     string `json:"path,omitempty"`
+	PathType string `json:"path_type,omitempty"`
+	Service  string `json:"service,omitempty"`
+	// Port is the service port's number or name
+	Port string `json:"port,omitempty"`
+	// Resource is a backend that isn't a service, as KIND/NAME
+	Resource       string `json:"resource,omitempty"`
+	TLS            bool   `json:"tls"`
+	DefaultBackend bool   `json:"default_backend,omitempty"`
+	MissingBackend bool   `json:"missing_backend"`
+}
+
+// ingressRoutes flattens the Ingresses of a namespace (-n, default "default"), or one Ingress
+// named in args, optionally narrowed with -l, into a row per host and path with the service and
+// port it routes to and whether the host is served over TLS. The services of the namespace are
+// listed to flag routes whose backend service doesn't exist. Both lists go through the same
+// access and namespace checks as a direct get.
+func (e *KubectlToolExecutor) ingressRoutes(args string, cfg *config.ConfigData) (string, error) {
+	cmdline, err := parseCommandLine(args)
+	if err != nil {
+		return "", err
+	}
+	if len(cmdline.positionals) > 1 || cmdline.hasFlag("--all-namespaces") {
+		return "", fmt.Errorf("ingress-routes takes an optional ingress name, a namespace (-n) and optionally -l, e.g. '-n default'")
+	}
+	namespace, err := namespaceFlag(cmdline, "default")
+	if err != nil {
+		return "", err
+	}
+
+	var ingresses []map[string]interface{}
+	if len(cmdline.positionals) == 1 {
+		name := strings.TrimPrefix(strings.TrimPrefix(cmdline.positionals[0], "ingress/"), "ingresses/")
+		if !objectName.MatchString(name) {
+			return "", fmt.Errorf("invalid ingress name '%s'", name)
+		}
+		ingress, err := e.getObject(fmt.Sprintf("get ingresses %s -n %s -o json", name, namespace), cfg)
+		if err != nil {
+			return "", err
+		}
+		ingresses = []map[string]interface{}{ingress}
+	} else {
+		command := "get ingresses -n " + namespace + " -o json"
+		if selector, ok := cmdline.flag("--selector"); ok && selector != "" {
+			command += " -l " + shellQuote(selector)
+		}
+		if ingresses, err = e.listObjects(command, cfg); err != nil {
+			return "", err
+		}
+	}
+
+	list := ingressRouteList{Namespace: namespace, Routes: []ingressRoute{}, MissingBackends: []string{}}
+	if len(ingresses) == 0 {
+		return marshalIngressRoutes(list)
+	}
+	services, err := e.listObjects("get services -n "+namespace+" -o json", cfg)
+	if err != nil {
+		return "", err
+	}
+	existing := map[string]bool{}
+	for _, service := range services {
+		_, name := objectNamespacedName(service)
+		existing[name] = true
+	}
+
+	missing := map[string]bool{}
+	for _, ingress := range ingresses {
+		_, name := objectNamespacedName(ingress)
+		class := nestedString(ingress, "spec", "ingressClassName")
+		tlsHosts, tlsAll := ingressTLSHosts(ingress)
+		addRoute := func(route ingressRoute, backend map[string]interface{}) {
+			route.Ingress, route.Class = name, class
+			route.TLS = tlsAll || tlsHosts[route.Host]
+			if service := nestedString(backend, "service", "name"); service != "" {
+				route.Service = service
+				route.Port = ingressBackendPort(backend)
+				route.MissingBackend = !existing[service]
+				if route.MissingBackend {
+					missing[service] = true
+				}
+			} else if resource, ok := backend["resource"].(map[string]interface{}); ok {
+				route.Resource = nestedString(resource, "kind") + "/" + nestedString(resource, "name")
+			}
+			list.Routes = append(list.Routes, route)
+		}
+
+		if backend, ok := nestedValue(ingress, "spec", "defaultBackend").(map[string]interface{}); ok {
+			addRoute(ingressRoute{Host: "*", DefaultBackend: true}, backend)
+		}
+		rules, _ := nestedValue(ingress, "spec", "rules").([]interface{})
+		for _, raw := range rules {
+			rule, _ := raw.(map[string]interface{})
+			host := nestedString(rule, "host")
+			if host == "" {
+				host = "*"
+			}
+			paths, _ := nestedValue(rule, "http", "paths").([]interface{})
+			for _, raw := range paths {
+				path, _ := raw.(map[string]interface{})
+				backend, _ := path["backend"].(map[string]interface{})
+				addRoute(ingressRoute{
+					Host:     host,
+					Path:     nestedString(path, "path"),
+					PathType: nestedString(path, "pathType"),
+				}, backend)
+			}
+		}
+	}
+	for service := range missing {
+		list.MissingBackends = append(list.MissingBackends, service)
+	}
+	sort.Strings(list.MissingBackends)
+
+	return marshalIngressRoutes(list)
+}
+
+// ingressTLSHosts returns the hosts an Ingress's tls section covers, and whether a tls entry
+// without hosts covers every host
+func ingressTLSHosts(ingress map[string]interface{}) (map[string]bool, bool) {
+	hosts := map[string]bool{}
+	all := false
+	entries, _ := nestedValue(ingress, "spec", "tls").([]interface{})
+	for _, raw := range entries {
+		entry, _ := raw.(map[string]interface{})
+		listed, _ := entry["hosts"].([]interface{})
+		if len(listed) == 0 {
+			all = true
+		}
+		for _, host := range listed {
+			if host, ok := host.(string); ok {
+				hosts[host] = true
+			}
+		}
+	}
+	return hosts, all
+}
+
+// ingressBackendPort returns a service backend's port number or name
+func ingressBackendPort(backend map[string]interface{}) string {
+	if name := nestedString(backend, "service", "port", "name"); name != "" {
+		return name
+	}
+	if number := nestedInt(backend, "service", "port", "number"); number != 0 {
+		return strconv.FormatInt(number, 10)
+	}
+	return ""
+}
+
+// marshalIngressRoutes encodes the routes of a namespace
+func marshalIngressRoutes(list ingressRouteList) (string, error) {
+	data, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode ingress routes: %w", err)
+	}
+	return string(data), nil
+}
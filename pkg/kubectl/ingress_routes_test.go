@@ -0,0 +1,100 @@
+package kubectl
+
+import (
+	"reflect"
+	"testing"
+)
+
+const ingressesJSON = `{
+  "items": [
+    {
+      "metadata": {"name": "shop", "namespace": "shop"},
+      "spec": {
+        "ingressClassName": "nginx",
+        "tls": [{"hosts": ["shop.example.com"], "secretName": "shop-tls"}],
+        "rules": [
+          {
+            "host": "shop.example.com",
+            "http": {"paths": [
+              {"path": "/", "pathType": "Prefix", "backend": {"service": {"name": "web", "port": {"number": 80}}}},
+              {"path": "/api", "pathType": "Prefix", "backend": {"service": {"name": "api", "port": {"name": "http"}}}}
+            ]}
+          },
+          {
+            "host": "admin.example.com",
+            "http": {"paths": [
+              {"path": "/", "pathType": "Exact", "backend": {"service": {"name": "admin", "port": {"number": 8080}}}}
+            ]}
+          }
+        ]
+      }
+    },
+    {
+      "metadata": {"name": "assets", "namespace": "shop"},
+      "spec": {
+        "defaultBackend": {"service": {"name": "web", "port": {"number": 80}}},
+        "rules": [
+          {"http": {"paths": [
+            {"path": "/static", "pathType": "Prefix", "backend": {"resource": {"apiGroup": "k8s.example.com", "kind": "StorageBucket", "name": "static"}}}
+          ]}}
+        ]
+      }
+    }
+  ]
+}`
+
+const ingressServicesJSON = `{
+  "items": [
+    {"metadata": {"name": "web", "namespace": "shop"}},
+    {"metadata": {"name": "api", "namespace": "shop"}}
+  ]
+}`
+
+func TestKubectlToolExecutor_IngressRoutes(t *testing.T) {
+	cfg := newTestConfig("readonly")
+	cfg.SecurityConfig.SetAllowedNamespaces("shop")
+
+	runDiagnosticCases(t, "ingress-routes", cfg, map[string]string{
+		"kubectl get ingresses -n shop -o json": ingressesJSON,
+		"kubectl get services -n shop -o json":  ingressServicesJSON,
+		"kubectl get ingresses assets -n shop -o json": `{"metadata": {"name": "assets", "namespace": "shop"},
+			"spec": {"defaultBackend": {"service": {"name": "web", "port": {"number": 80}}}}}`,
+	}, []diagnosticCase{
+		{
+			name: "every ingress",
+			args: "-n shop",
+			check: func(t *testing.T, output string, _ []string) {
+				list := decodeReport[ingressRouteList](t, output)
+				want := []ingressRoute{
+					{Ingress: "shop", Class: "nginx", Host: "shop.example.com", Path: "/", PathType: "Prefix", Service: "web", Port: "80", TLS: true},
+					{Ingress: "shop", Class: "nginx", Host: "shop.example.com", Path: "/api", PathType: "Prefix", Service: "api", Port: "http", TLS: true},
+					{Ingress: "shop", Class: "nginx", Host: "admin.example.com", Path: "/", PathType: "Exact", Service: "admin", Port: "8080", MissingBackend: true},
+					{Ingress: "assets", Host: "*", Service: "web", Port: "80", DefaultBackend: true},
+					{Ingress: "assets", Host: "*", Path: "/static", PathType: "Prefix", Resource: "StorageBucket/static"},
+				}
+				if !reflect.DeepEqual(list.Routes, want) {
+					t.Errorf("expected routes\n%+v\ngot\n%+v", want, list.Routes)
+				}
+				if !reflect.DeepEqual(list.MissingBackends, []string{"admin"}) {
+					t.Errorf("expected the admin service to be missing, got %v", list.MissingBackends)
+				}
+			},
+		},
+		{
+			// One ingress is fetched by name, with the TYPE/NAME form accepted
+			name: "one ingress",
+			args: "ingress/assets -n shop",
+			check: func(t *testing.T, output string, _ []string) {
+				list := decodeReport[ingressRouteList](t, output)
+				if len(list.Routes) != 1 || !list.Routes[0].DefaultBackend || len(list.MissingBackends) != 0 {
+					t.Errorf("expected only the assets default backend, got %+v", list)
+				}
+			},
+		},
+		{name: "namespace carrying flags refused", args: "-n 'shop --as=admin'", refused: "invalid namespace"},
+		{name: "name carrying flags refused", args: "'shop --as=admin' -n shop", refused: "invalid ingress name"},
+		{name: "several names refused", args: "shop assets -n shop", refused: "optional ingress name"},
+		{name: "all namespaces refused", args: "-A", refused: "optional ingress name"},
+		{name: "namespace outside the allow-list", args: "-n kube-system", refused: "kube-system"},
+	})
+}
@@ -0,0 +1,119 @@
+package kubectl
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Azure/mcp-kubernetes/pkg/config"
+)
+
+// podSpecPaths maps the kinds that carry a pod spec to where it is
+var podSpecPaths = map[string][]string{
+	"Pod":                   {"spec"},
+	"PodTemplate":           {"template", "spec"},
+	"Deployment":            {"spec", "template", "spec"},
+	"StatefulSet":           {"spec", "template", "spec"},
+	"DaemonSet":             {"spec", "template", "spec"},
+	"ReplicaSet":            {"spec", "template", "spec"},
+	"ReplicationController": {"spec", "template", "spec"},
+	"Job":                   {"spec", "template", "spec"},
+	"CronJob":               {"spec", "jobTemplate", "spec", "template", "spec"},
+}
+
+// hasManifest reports whether a call passes an inline manifest
+func hasManifest(params map[string]interface{}) bool {
+	manifest, _ := params["manifest"].(string)
+	return strings.TrimSpace(manifest) != ""
+}
+
+// applyManifestParam reads the objects of a create or apply from the manifest parameter, which
+// is sent on stdin, by adding -f - to the command
+func applyManifestParam(command string, params map[string]interface{}) (string, error) {
+	if !hasManifest(params) {
+		return command, nil
+	}
+	cmdline, err := parseCommandLine(command)
+	if err != nil {
+		return "", err
+	}
+	if verb := cmdline.verb(); verb != "create" && verb != "apply" {
+		return "", fmt.Errorf("manifest is only supported for create and apply, not %s", verb)
+	}
+	if len(cmdline.args()) > 0 {
+		return "", fmt.Errorf("manifest holds the objects to %s; pass resource='' and no names in args", cmdline.verb())
+	}
+	if cmdline.hasFlag("--filename") || cmdline.hasFlag("--kustomize") {
+		return "", fmt.Errorf("-f and -k conflict with manifest, which is passed to %s on stdin", cmdline.verb())
+	}
+	return insertFlags(command, []string{"-f", "-"}), nil
+}
+
+// runWithManifest runs a create or apply with its inline manifest on stdin, within the
+// requested timeout
+func (e *KubectlToolExecutor) runWithManifest(command string, params map[string]interface{}, requested int, cfg *config.ConfigData) (string, error) {
+	manifest, _ := params["manifest"].(string)
+	verb, _, _ := strings.Cut(strings.TrimSpace(command), " ")
+	return e.executor.executeKubectlCommandWithInputWithin(command, manifest, cfg.EffectiveTimeout(verb, requested), cfg)
+}
+
+// checkManifest applies the namespace policy and --require-resource-limits to the objects of
+// the inline manifest of a create or apply
+func checkManifest(params map[string]interface{}, cfg *config.ConfigData) error {
+	if !hasManifest(params) {
+		return nil
+	}
+	manifest, _ := params["manifest"].(string)
+	objects, err := manifestObjects(manifest)
+	if err != nil {
+		return err
+	}
+	return checkManifestObjects(objects, cfg)
+}
+
+// checkManifestObjects applies the namespace, resource kind and resource limit checks to the
+// objects of a manifest about to be sent to kubectl
+func checkManifestObjects(objects []manifestObject, cfg *config.ConfigData) error {
+	if err := validateManifestScope(objects, cfg); err != nil {
+		return err
+	}
+	if cfg.RequireResourceLimits {
+		if err := checkResourceLimits(objects); err != nil {
+			return denied(err)
+		}
+	}
+	return nil
+}
+
+// checkResourceLimits refuses a manifest with a pod spec whose containers, including init
+// containers, don't each set both resource requests and limits, naming every offender
+func checkResourceLimits(objects []manifestObject) error {
+	var problems []string
+	for _, object := range objects {
+		path, ok := podSpecPaths[object.kind]
+		if !ok {
+			continue
+		}
+		podSpec, _ := nestedValue(object.object, path...).(map[string]interface{})
+		_, name := objectNamespacedName(object.object)
+		for _, field := range []string{"initContainers", "containers"} {
+			containers, _ := podSpec[field].([]interface{})
+			for _, raw := range containers {
+				container, _ := raw.(map[string]interface{})
+				var missing []string
+				for _, kind := range []string{"requests", "limits"} {
+					if amounts, _ := nestedValue(container, "resources", kind).(map[string]interface{}); len(amounts) == 0 {
+						missing = append(missing, kind)
+					}
+				}
+				if len(missing) > 0 {
+					problems = append(problems, fmt.Sprintf("container '%s' of %s/%s has no resource %s",
+						nestedString(container, "name"), object.kind, name, strings.Join(missing, " or ")))
+				}
+			}
+		}
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	return fmt.Errorf("every container must set resource requests and limits (--require-resource-limits): %s", strings.Join(problems, "; "))
+}
@@ -0,0 +1,113 @@
+package kubectl
+
+import (
+	"strings"
+	"testing"
+)
+
+const limitsMissingManifest = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      initContainers:
+      - name: migrate
+        image: app
+        resources:
+          requests: {cpu: 100m, memory: 64Mi}
+          limits: {cpu: 500m, memory: 128Mi}
+      containers:
+      - name: web
+        image: nginx
+        resources:
+          requests: {cpu: 250m, memory: 128Mi}
+      - name: proxy
+        image: envoy
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+data:
+  mode: fast
+`
+
+const limitsSetManifest = `apiVersion: v1
+kind: Pod
+metadata:
+  name: web
+spec:
+  containers:
+  - name: web
+    image: nginx
+    resources:
+      requests: {cpu: 250m, memory: 128Mi}
+      limits: {cpu: "1", memory: 256Mi}
+`
+
+func TestKubectlToolExecutor_InlineManifest(t *testing.T) {
+	var commands []string
+	worker := newTestWorker(t, func(command string) map[string]interface{} {
+		commands = append(commands, command)
+		return map[string]interface{}{"stdout": "deployment.apps/web created\n"}
+	})
+	executor := NewKubectlToolExecutor(worker)
+	cfg := newTestConfig("readwrite")
+
+	apply := func(manifest string) (string, error) {
+		return executor.Execute(map[string]interface{}{
+			"_tool_name": "kubectl_resources",
+			"operation":  "apply",
+			"resource":   "",
+			"args":       "-n default",
+			"manifest":   manifest,
+		}, cfg)
+	}
+
+	// Without the policy any manifest is applied from stdin
+	if _, err := apply(limitsMissingManifest); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(commands) != 1 || commands[0] != "kubectl apply -n default -f -" {
+		t.Errorf("commands = %v, want [kubectl apply -n default -f -]", commands)
+	}
+
+	cfg.RequireResourceLimits = true
+	commands = nil
+	_, err := apply(limitsMissingManifest)
+	if err == nil {
+		t.Fatal("expected a manifest with containers missing limits to be refused")
+	}
+	for _, want := range []string{
+		"container 'web' of Deployment/web has no resource limits",
+		"container 'proxy' of Deployment/web has no resource requests or limits",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected the error to contain %q, got %v", want, err)
+		}
+	}
+	if strings.Contains(err.Error(), "migrate") {
+		t.Errorf("expected the init container with requests and limits to pass, got %v", err)
+	}
+	if len(commands) != 0 {
+		t.Errorf("expected nothing to run, got %v", commands)
+	}
+
+	if _, err := apply(limitsSetManifest); err != nil {
+		t.Errorf("expected a manifest with requests and limits to be applied, got %v", err)
+	}
+
+	// The manifest replaces -f and named objects
+	_, err = executor.Execute(map[string]interface{}{
+		"_tool_name": "kubectl_resources",
+		"operation":  "create",
+		"resource":   "deployment",
+		"args":       "web -n default",
+		"manifest":   limitsSetManifest,
+	}, cfg)
+	if err == nil {
+		t.Error("expected a manifest with a named resource to be refused")
+	}
+}
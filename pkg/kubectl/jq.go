@@ -0,0 +1,349 @@
+package kubectl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// jqMaxResults bounds the values a jq expression may produce, including intermediate ones
+const jqMaxResults = 100000
+
+// jqFilter maps one input value to the values it produces
+type jqFilter func(ctx context.Context, input interface{}) ([]interface{}, error)
+
+// compileJQ compiles the subset of jq the result transform supports: paths such as
+// .items[0].metadata.name and ."app.kubernetes.io/name", iteration with [], pipes,
+// collecting results with [ ... ], and the length and keys builtins
+func compileJQ(expression string) (jqFilter, error) {
+	p := &jqParser{src: expression}
+	filter, err := p.pipeline()
+	if err != nil {
+		return nil, err
+	}
+	if p.skipSpace(); p.pos < len(p.src) {
+		return nil, fmt.Errorf("invalid jq expression: unexpected '%s' at offset %d", p.src[p.pos:], p.pos)
+	}
+	return filter, nil
+}
+
+// jqParser is a recursive descent parser over a jq expression
+type jqParser struct {
+	src string
+	pos int
+}
+
+func (p *jqParser) skipSpace() {
+	for p.pos < len(p.src) && strings.ContainsRune(" \t\n\r", rune(p.src[p.pos])) {
+		p.pos++
+	}
+}
+
+// peek returns the next non-space byte, or 0 at the end of the expression
+func (p *jqParser) peek() byte {
+	p.skipSpace()
+	if p.pos >= len(p.src) {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+// pipeline parses terms separated by |, each applied to the outputs of the previous one
+func (p *jqParser) pipeline() (jqFilter, error) {
+	filter, err := p.term()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == '|' {
+		p.pos++
+		next, err := p.term()
+		if err != nil {
+			return nil, err
+		}
+		filter = jqPipe(filter, next)
+	}
+	return filter, nil
+}
+
+// term parses a path, a [ ... ] collection or a builtin
+func (p *jqParser) term() (jqFilter, error) {
+	switch c := p.peek(); {
+	case c == '.':
+		return p.path()
+	case c == '[':
+		p.pos++
+		inner, err := p.pipeline()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ']' {
+			return nil, fmt.Errorf("invalid jq expression: missing ']' at offset %d", p.pos)
+		}
+		p.pos++
+		return jqCollect(inner), nil
+	case isJQIdentStart(c):
+		name := p.ident()
+		switch name {
+		case "length":
+			return jqLength, nil
+		case "keys":
+			return jqKeys, nil
+		}
+		return nil, fmt.Errorf("unsupported jq function '%s'; only length and keys are available", name)
+	case c == 0:
+		return nil, fmt.Errorf("invalid jq expression: expected a filter at the end")
+	default:
+		return nil, fmt.Errorf("unsupported jq syntax at offset %d: '%s'", p.pos, p.src[p.pos:])
+	}
+}
+
+// path parses . followed by any number of .field, ."field", [n] and [] suffixes
+func (p *jqParser) path() (jqFilter, error) {
+	var steps []jqFilter
+	first := true
+	for p.pos < len(p.src) {
+		switch c := p.src[p.pos]; {
+		case c == '.':
+			p.pos++
+			switch {
+			case p.pos < len(p.src) && p.src[p.pos] == '"':
+				key, err := p.quoted()
+				if err != nil {
+					return nil, err
+				}
+				steps = append(steps, jqField(key))
+			case p.pos < len(p.src) && isJQIdentStart(p.src[p.pos]):
+				steps = append(steps, jqField(p.ident()))
+			case !first && (p.pos >= len(p.src) || p.src[p.pos] != '['):
+				return nil, fmt.Errorf("invalid jq expression: expected a field name at offset %d", p.pos)
+			}
+		case c == '[':
+			step, err := p.index()
+			if err != nil {
+				return nil, err
+			}
+			steps = append(steps, step)
+		default:
+			return jqChain(steps), nil
+		}
+		first = false
+	}
+	return jqChain(steps), nil
+}
+
+// index parses [n] or []
+func (p *jqParser) index() (jqFilter, error) {
+	p.pos++
+	end := strings.IndexByte(p.src[p.pos:], ']')
+	if end < 0 {
+		return nil, fmt.Errorf("invalid jq expression: missing ']' at offset %d", p.pos)
+	}
+	inner := strings.TrimSpace(p.src[p.pos : p.pos+end])
+	p.pos += end + 1
+	if inner == "" {
+		return jqIterate, nil
+	}
+	n, err := strconv.Atoi(inner)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported jq index '[%s]'; only [n] and [] are available", inner)
+	}
+	return jqIndex(n), nil
+}
+
+// quoted parses a double-quoted field name
+func (p *jqParser) quoted() (string, error) {
+	for end := p.pos + 1; end < len(p.src); end++ {
+		switch p.src[end] {
+		case '\\':
+			end++
+		case '"':
+			var key string
+			if err := json.Unmarshal([]byte(p.src[p.pos:end+1]), &key); err != nil {
+				return "", fmt.Errorf("invalid jq field name %s", p.src[p.pos:end+1])
+			}
+			p.pos = end + 1
+			return key, nil
+		}
+	}
+	return "", fmt.Errorf("invalid jq expression: unterminated string at offset %d", p.pos)
+}
+
+func (p *jqParser) ident() string {
+	start := p.pos
+	for p.pos < len(p.src) && (isJQIdentStart(p.src[p.pos]) || (p.src[p.pos] >= '0' && p.src[p.pos] <= '9')) {
+		p.pos++
+	}
+	return p.src[start:p.pos]
+}
+
+func isJQIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// jqPipe feeds every output of first to second
+func jqPipe(first, second jqFilter) jqFilter {
+	return func(ctx context.Context, input interface{}) ([]interface{}, error) {
+		values, err := first(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		var results []interface{}
+		for _, value := range values {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			out, err := second(ctx, value)
+			if err != nil {
+				return nil, err
+			}
+			results = append(results, out...)
+			if len(results) > jqMaxResults {
+				return nil, fmt.Errorf("jq expression produced more than %d values", jqMaxResults)
+			}
+		}
+		return results, nil
+	}
+}
+
+// jqChain applies path steps in turn; no steps is the identity
+func jqChain(steps []jqFilter) jqFilter {
+	filter := jqFilter(func(_ context.Context, input interface{}) ([]interface{}, error) {
+		return []interface{}{input}, nil
+	})
+	for _, step := range steps {
+		filter = jqPipe(filter, step)
+	}
+	return filter
+}
+
+// jqCollect gathers the outputs of inner into one array
+func jqCollect(inner jqFilter) jqFilter {
+	return func(ctx context.Context, input interface{}) ([]interface{}, error) {
+		values, err := inner(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		if values == nil {
+			values = []interface{}{}
+		}
+		return []interface{}{values}, nil
+	}
+}
+
+func jqField(key string) jqFilter {
+	return func(_ context.Context, input interface{}) ([]interface{}, error) {
+		switch v := input.(type) {
+		case nil:
+			return []interface{}{nil}, nil
+		case map[string]interface{}:
+			return []interface{}{v[key]}, nil
+		default:
+			return nil, fmt.Errorf("cannot index %s with \"%s\"", jqTypeName(input), key)
+		}
+	}
+}
+
+func jqIndex(n int) jqFilter {
+	return func(_ context.Context, input interface{}) ([]interface{}, error) {
+		switch v := input.(type) {
+		case nil:
+			return []interface{}{nil}, nil
+		case []interface{}:
+			i := n
+			if i < 0 {
+				i += len(v)
+			}
+			if i < 0 || i >= len(v) {
+				return []interface{}{nil}, nil
+			}
+			return []interface{}{v[i]}, nil
+		default:
+			return nil, fmt.Errorf("cannot index %s with a number", jqTypeName(input))
+		}
+	}
+}
+
+func jqIterate(_ context.Context, input interface{}) ([]interface{}, error) {
+	switch v := input.(type) {
+	case []interface{}:
+		return v, nil
+	case map[string]interface{}:
+		values := make([]interface{}, 0, len(v))
+		for _, key := range sortedKeys(v) {
+			values = append(values, v[key])
+		}
+		return values, nil
+	default:
+		return nil, fmt.Errorf("cannot iterate over %s", jqTypeName(input))
+	}
+}
+
+func jqLength(_ context.Context, input interface{}) ([]interface{}, error) {
+	switch v := input.(type) {
+	case nil:
+		return []interface{}{0}, nil
+	case string:
+		return []interface{}{utf8.RuneCountInString(v)}, nil
+	case []interface{}:
+		return []interface{}{len(v)}, nil
+	case map[string]interface{}:
+		return []interface{}{len(v)}, nil
+	case float64:
+		if v < 0 {
+			v = -v
+		}
+		return []interface{}{v}, nil
+	default:
+		return nil, fmt.Errorf("%s has no length", jqTypeName(input))
+	}
+}
+
+func jqKeys(_ context.Context, input interface{}) ([]interface{}, error) {
+	switch v := input.(type) {
+	case map[string]interface{}:
+		keys := []interface{}{}
+		for _, key := range sortedKeys(v) {
+			keys = append(keys, key)
+		}
+		return []interface{}{keys}, nil
+	case []interface{}:
+		keys := make([]interface{}, len(v))
+		for i := range v {
+			keys[i] = i
+		}
+		return []interface{}{keys}, nil
+	default:
+		return nil, fmt.Errorf("%s has no keys", jqTypeName(input))
+	}
+}
+
+func sortedKeys(obj map[string]interface{}) []string {
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// jqTypeName names a JSON value's type the way jq's errors do
+func jqTypeName(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	default:
+		return "object"
+	}
+}
@@ -1,27 +1,78 @@
 package kubectl
 
 import (
+	"errors"
 	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/Azure/mcp-kubernetes/pkg/config"
 	"github.com/Azure/mcp-kubernetes/pkg/security"
+	"github.com/Azure/mcp-kubernetes/pkg/tools"
 )
 
 // KubectlToolExecutor handles structured kubectl command execution for grouped tools
 type KubectlToolExecutor struct {
 	executor *KubectlExecutor
+	// store serves get operations from a local cache when the informer read source is enabled
+	store objectStore
+	// tenants holds an executor per tenant whose remote agent calls can be routed to
+	tenants map[string]*KubectlToolExecutor
+	// recent keeps the latest tool calls for kubectl_recent_commands, or is nil when turned off
+	recent *recentCommands
+	// reads caches single-object gets validated by resourceVersion, or is nil when turned off
+	reads *readCache
+	// idempotency remembers the results of changes made with an idempotency key, or is nil
+	// when turned off
+	idempotency *idempotencyStore
+	// snapshots keeps resources as they were before they were changed by name, or is nil when
+	// turned off
+	snapshots *snapshotStore
+	// schemas caches the cluster's OpenAPI schemas for lint
+	schemas *schemaCache
 }
 
+// KubectlToolExecutor streams large output to clients that can receive it
+var _ tools.StreamingExecutor = (*KubectlToolExecutor)(nil)
+
 // NewKubectlToolExecutor creates a new kubectl tool executor
 func NewKubectlToolExecutor(pulsarWorker *Worker) *KubectlToolExecutor {
 	return &KubectlToolExecutor{
 		executor: NewExecutor(pulsarWorker),
+		schemas:  newSchemaCache(),
 	}
 }
 
 // Execute processes structured kubectl commands with operation/resource/args parameters
 func (e *KubectlToolExecutor) Execute(params map[string]interface{}, cfg *config.ConfigData) (string, error) {
+	target, err := e.forTenant(params, cfg)
+	if err != nil {
+		e.recordCommand(params, cfg, err)
+		return "", err
+	}
+	output, err := target.execute(params, cfg, nil)
+	e.recordCommand(params, cfg, err)
+	return output, err
+}
+
+// ExecuteStreaming is Execute for callers that can show partial output. Commands known to
+// produce large output are streamed from the remote agent, with each chunk passed to emit as
+// it arrives; the complete output is still returned at the end.
+func (e *KubectlToolExecutor) ExecuteStreaming(params map[string]interface{}, cfg *config.ConfigData, emit func(chunk string)) (string, error) {
+	target, err := e.forTenant(params, cfg)
+	if err != nil {
+		e.recordCommand(params, cfg, err)
+		return "", err
+	}
+	output, err := target.execute(params, cfg, emit)
+	e.recordCommand(params, cfg, err)
+	return output, err
+}
+
+// execute runs a tool call, streaming large output to emit when it is not nil
+func (e *KubectlToolExecutor) execute(params map[string]interface{}, cfg *config.ConfigData, emit func(chunk string)) (string, error) {
 	// Extract structured parameters
 	operation, ok := params["operation"].(string)
 	if !ok {
@@ -46,28 +97,665 @@ func (e *KubectlToolExecutor) Execute(params map[string]interface{}, cfg *config
 		return "", err
 	}
 
-	// Map operation to kubectl command
-	kubectlCommand, err := MapOperationToCommand(toolName, operation, resource)
+	// Refuse structured parameters repeated as flags in args, then turn the scope ones into flags
+	if err := checkParamConflicts(args, params); err != nil {
+		return "", err
+	}
+	args, err := applyScopeParams(args, params)
+	if err != nil {
+		return "", err
+	}
+
+	echo, _ := params["echo"].(bool)
+
+	// Composite operations issue their own read-only commands
+	if toolName == "kubectl_diagnostics" && operation == "describe-tree" {
+		if echo {
+			return "", fmt.Errorf("echo is not supported for describe-tree, which runs several commands")
+		}
+		if container, _ := params["container"].(string); container != "" {
+			return "", fmt.Errorf("container is not supported for describe-tree")
+		}
+		return e.describeTree(resource, args, cfg)
+	}
+	if toolName == "kubectl_resources" && operation == "restore-last" {
+		if echo {
+			return "", fmt.Errorf("echo is not supported for restore-last")
+		}
+		return e.restoreLast(resource, args, params, cfg)
+	}
+	if toolName == "kubectl_diagnostics" && operation == "pod-diagnosis" {
+		if echo {
+			return "", fmt.Errorf("echo is not supported for pod-diagnosis")
+		}
+		return e.podDiagnosis(args, cfg)
+	}
+	if toolName == "kubectl_diagnostics" && operation == "orphans" {
+		if echo {
+			return "", fmt.Errorf("echo is not supported for orphans, which runs several commands")
+		}
+		return e.findOrphans(resource, args, cfg)
+	}
+	if toolName == "kubectl_diagnostics" && operation == "quota-status" {
+		if echo {
+			return "", fmt.Errorf("echo is not supported for quota-status")
+		}
+		return e.quotaStatus(args, cfg)
+	}
+	if toolName == "kubectl_diagnostics" && operation == "pending-pods" {
+		if echo {
+			return "", fmt.Errorf("echo is not supported for pending-pods, which may run several commands")
+		}
+		return e.pendingPods(args, cfg)
+	}
+	if toolName == "kubectl_diagnostics" && operation == "node-capacity" {
+		if echo {
+			return "", fmt.Errorf("echo is not supported for node-capacity, which runs several commands")
+		}
+		return e.nodeCapacity(args, cfg)
+	}
+	if toolName == "kubectl_diagnostics" && operation == "pull-secrets" {
+		if echo {
+			return "", fmt.Errorf("echo is not supported for pull-secrets, which runs several commands")
+		}
+		return e.pullSecrets(args, cfg)
+	}
+	if toolName == "kubectl_diagnostics" && operation == "cronjobs" {
+		if echo {
+			return "", fmt.Errorf("echo is not supported for cronjobs")
+		}
+		return e.cronJobs(args, cfg)
+	}
+	if toolName == "kubectl_diagnostics" && operation == "drain-check" {
+		if echo {
+			return "", fmt.Errorf("echo is not supported for drain-check, which runs several commands")
+		}
+		return e.drainCheck(args, cfg)
+	}
+	if toolName == "kubectl_diagnostics" && operation == "ingress-routes" {
+		if echo {
+			return "", fmt.Errorf("echo is not supported for ingress-routes, which runs several commands")
+		}
+		return e.ingressRoutes(args, cfg)
+	}
+	if toolName == "kubectl_diagnostics" && operation == "restarts" {
+		if echo {
+			return "", fmt.Errorf("echo is not supported for restarts")
+		}
+		return e.restarts(args, cfg)
+	}
+	if toolName == "kubectl_diagnostics" && operation == "service-endpoints" {
+		if echo {
+			return "", fmt.Errorf("echo is not supported for service-endpoints")
+		}
+		return e.serviceEndpoints(args, cfg)
+	}
+	if toolName == "kubectl_diagnostics" && operation == "rollout-stall" {
+		if echo {
+			return "", fmt.Errorf("echo is not supported for rollout-stall, which runs several commands")
+		}
+		return e.rolloutStall(args, cfg)
+	}
+	if toolName == "kubectl_diagnostics" && operation == "stuck-terminating" {
+		if echo {
+			return "", fmt.Errorf("echo is not supported for stuck-terminating, which may run several commands")
+		}
+		return e.stuckTerminating(resource, args, params, cfg)
+	}
+	if toolName == "kubectl_diagnostics" && operation == "deployment-readiness" {
+		if echo {
+			return "", fmt.Errorf("echo is not supported for deployment-readiness")
+		}
+		return e.deploymentReadiness(args, cfg)
+	}
+	if toolName == "kubectl_diagnostics" && operation == "probes" {
+		if echo {
+			return "", fmt.Errorf("echo is not supported for probes")
+		}
+		return e.probes(args, cfg)
+	}
+	if toolName == "kubectl_diagnostics" && operation == "deployment-logs" {
+		if echo {
+			return "", fmt.Errorf("echo is not supported for deployment-logs, which runs several commands")
+		}
+		return e.deploymentLogs(args, params, cfg)
+	}
+	if toolName == "kubectl_resources" && (operation == "describe-yaml" ||
+		operation == "describe" && describeWithYAML(resource, args, params, cfg)) {
+		if echo {
+			return "", fmt.Errorf("echo is not supported for describe-yaml, which runs several commands")
+		}
+		return e.describeYAML(resource, args, params, cfg)
+	}
+	if toolName == "kubectl_resources" && operation == "configmap-data" {
+		if echo {
+			return "", fmt.Errorf("echo is not supported for configmap-data")
+		}
+		return e.configMapData(resource, args, params, cfg)
+	}
+	if toolName == "kubectl_config" && operation == "namespace-diff" {
+		if echo {
+			return "", fmt.Errorf("echo is not supported for namespace-diff, which runs several commands")
+		}
+		return e.namespaceDiff(resource, args, cfg)
+	}
+	if toolName == "kubectl_config" && operation == "drift" {
+		if echo {
+			return "", fmt.Errorf("echo is not supported for drift")
+		}
+		return e.checkDrift(args, params, cfg)
+	}
+	if toolName == "kubectl_config" && operation == "lint" {
+		if echo {
+			return "", fmt.Errorf("echo is not supported for lint")
+		}
+		return e.lintManifest(params, cfg)
+	}
+	if toolName == "kubectl_config" && operation == "auth" && resource == "reconcile" && hasManifest(params) {
+		if echo {
+			return "", fmt.Errorf("echo is not supported for auth reconcile with a manifest")
+		}
+		return e.authReconcile(args, params, cfg)
+	}
+	if toolName == "kubectl_diagnostics" && operation == "events" && isEventsWatch(args, params) {
+		if echo {
+			return "", fmt.Errorf("echo is not supported for events watch")
+		}
+		return e.watchEvents(toolName, operation, resource, args, params, cfg)
+	}
+	if toolName == "kubectl_diagnostics" && operation == "logs" && isLogsFollow(args, params) {
+		if echo {
+			return "", fmt.Errorf("echo is not supported for logs follow")
+		}
+		return e.followLogs(toolName, operation, resource, args, params, cfg, emit)
+	}
+
+	fullCommand, err := e.assembleCommand(toolName, operation, resource, args, params, cfg)
 	if err != nil {
 		return "", err
 	}
 
-	// Build the full command
-	fullCommand := e.buildCommand(kubectlCommand, resource, args)
+	// Return the command that would run without validating or executing it
+	if echo {
+		return "kubectl " + fullCommand, nil
+	}
 
 	// Check access level for the command
 	if err := e.checkAccessLevel(fullCommand, cfg); err != nil {
+		return "", denied(err)
+	}
+
+	// Refuse rollbacks to an implicit revision unless the caller accepts it
+	if err := e.checkRolloutUndo(fullCommand, params); err != nil {
+		return "", denied(err)
+	}
+
+	// Refuse deletes that orphan dependents unless the caller accepts it
+	if err := e.checkOrphanDelete(fullCommand, params); err != nil {
+		return "", denied(err)
+	}
+
+	// Refuse changes to every resource of a type unless bulk changes are allowed
+	if err := e.checkBulkMutation(fullCommand, params, cfg); err != nil {
+		return "", denied(err)
+	}
+
+	// Refuse deletes by selector or --all when deletes must name their resources
+	if err := e.checkDeleteByName(fullCommand, cfg); err != nil {
+		return "", denied(err)
+	}
+
+	// Refuse changes without a reason when every change must carry one
+	if err := e.checkReason(fullCommand, params, cfg); err != nil {
+		return "", denied(err)
+	}
+
+	// Require the current confirmation token for admin operations in safe mode
+	if err := e.checkAdminConfirmation(fullCommand, params, cfg); err != nil {
+		return "", denied(err)
+	}
+
+	// Validate the command against security settings; a read across all namespaces that is
+	// expanded to the allowed ones is validated per namespace when it runs
+	if !e.expandsAllNamespaces(fullCommand, cfg) {
+		validator := security.NewValidator(cfg.SecurityConfig)
+		if err := validator.ValidateCommand(fullCommand, security.CommandTypeKubectl); err != nil {
+			return "", err
+		}
+	}
+
+	// Check the objects of an inline manifest against the namespace and resource policies
+	if err := checkManifest(params, cfg); err != nil {
+		return "", err
+	}
+
+	// Let the approval webhook decide whether an admin operation or delete may run
+	if err := e.checkApproval(fullCommand, params, cfg); err != nil {
+		return "", denied(err)
+	}
+
+	// Check RBAC for the identity the command runs as before making changes
+	if preflight, _ := params["preflight"].(bool); preflight && e.determineCommandCategory(fullCommand) != "read-only" {
+		if err := e.preflightAuthz(fullCommand, cfg); err != nil {
+			return "", err
+		}
+	}
+
+	// Return the earlier result of a change retried with the same idempotency key
+	key, err := e.idempotencyKey(fullCommand, params)
+	if err != nil {
+		return "", err
+	}
+	if key != "" {
+		// The same command with another manifest is a different change
+		identity := fullCommand
+		if manifest, _ := params["manifest"].(string); manifest != "" {
+			identity += "\n" + manifest
+		}
+		return e.idempotency.do(key, identity, func() (string, error) {
+			return e.runChecked(fullCommand, params, cfg, emit)
+		})
+	}
+	return e.runChecked(fullCommand, params, cfg, emit)
+}
+
+// runChecked runs a command that passed every check and post-processes its output for the
+// structured parameters of the call
+func (e *KubectlToolExecutor) runChecked(fullCommand string, params map[string]interface{}, cfg *config.ConfigData, emit func(chunk string)) (string, error) {
+	timeout, _, err := positiveIntParam("timeout", params["timeout"])
+	if err != nil {
+		return "", err
+	}
+
+	// The whole call, including any wait for deletion, is bounded by the command's timeout
+	deadline := time.Now().Add(time.Duration(e.callTimeout(fullCommand, int(timeout), cfg)) * time.Second)
+
+	// Keep the resources changed by name as they are now so restore-last can put them back
+	snapshots, err := e.snapshotBeforeChange(fullCommand, snapshotOwnerOf(params), cfg)
+	if err != nil {
+		return "", err
+	}
+
+	// Execute the command, streaming output that is likely to be large unless it must be filtered first
+	var output string
+	namesOnly, _ := params["names_only"].(bool)
+	transformer := newResultTransformer(cfg)
+	if e.expandsAllNamespaces(fullCommand, cfg) {
+		output, err = e.readAllowedNamespaces(fullCommand, int(timeout), cfg)
+	} else if hasManifest(params) {
+		output, err = e.runWithManifest(fullCommand, params, int(timeout), cfg)
+	} else if emit != nil && !namesOnly && !wantsNDJSON(params) && transformer == nil && isLargeOutput(fullCommand, cfg.MaxListItems) && !listsNamespaces(fullCommand, cfg) {
+		output, err = e.streamCommand(fullCommand, int(timeout), cfg, emit)
+	} else {
+		output, err = e.runCommandWithin(fullCommand, int(timeout), cfg)
+	}
+	if err != nil {
+		// Pull kubectl's error summary out of failed changes so it isn't lost in the output,
+		// with the outcome for each resource when several were named
+		if e.determineCommandCategory(fullCommand) != "read-only" {
+			if failure, ok := summarizeTargets(fullCommand, err); ok {
+				return "", failure
+			}
+			return "", summarizeFailure(err)
+		}
 		return "", err
 	}
+	e.keepSnapshots(snapshots)
 
-	// Validate the command against security settings
-	validator := security.NewValidator(cfg.SecurityConfig)
-	if err := validator.ValidateCommand(fullCommand, security.CommandTypeKubectl); err != nil {
+	// Confirm the deleted resources are gone when the caller is about to recreate them
+	if waitForDeletion, _ := params["wait_for_deletion"].(bool); waitForDeletion {
+		return e.confirmDeletion(fullCommand, output, deadline, cfg)
+	}
+
+	// Record why the resources were changed where rollout history and audits can see it
+	output = e.recordChangeCause(fullCommand, output, params, cfg)
+
+	// Hide namespaces outside the allow-list, then cap the number of items returned by list operations
+	output, err = filterNamespaceList(fullCommand, output, cfg)
+	if err != nil {
 		return "", err
 	}
+	output = limitListItems(fullCommand, output, cfg.MaxListItems)
+
+	// Run the output through the configured result transform
+	if transformer != nil {
+		expression, _ := params["jq"].(string)
+		output, err = transformer.Transform(output, expression)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	// Keep only the versions of the requested API group
+	if group, _ := params["group"].(string); strings.TrimSpace(group) != "" {
+		output = filterAPIVersions(output, strings.TrimSpace(group))
+	}
+
+	// Reduce the listing to bare names when the caller asked for them
+	if namesOnly {
+		return extractNames(fullCommand, output)
+	}
+
+	// Write each item on its own line when the caller asked for newline-delimited JSON
+	if wantsNDJSON(params) {
+		return toNDJSON(output)
+	}
+
+	// Parse the output into JSON when the caller asked for structured output
+	if structured, _ := params["structured"].(bool); structured {
+		return formatStructured(fullCommand, output), nil
+	}
 
-	// Execute the command directly
-	return e.executor.executeKubectlCommandOnHost(fullCommand, "", cfg) // kubectl
+	// Cap the bytes returned; names, NDJSON and structured results are left whole so they stay valid
+	return limitOutputBytes(fullCommand, output, cfg), nil
+}
+
+// assembleCommand builds the kubectl command (without the leading "kubectl") for a tool call,
+// including every flag injected from structured parameters
+func (e *KubectlToolExecutor) assembleCommand(toolName, operation, resource, args string, params map[string]interface{}, cfg *config.ConfigData) (string, error) {
+	// Map operation to kubectl command
+	kubectlCommand, err := MapOperationToCommand(toolName, operation, resource)
+	if err != nil {
+		return "", err
+	}
+
+	// Run the command as the impersonated identity if one was given
+	imp, err := impersonationFromParams(params)
+	if err != nil {
+		return "", err
+	}
+
+	command := e.buildCommand(kubectlCommand, resource, args)
+
+	// Roll back to an explicit revision
+	if params["to_revision"] != nil {
+		if kubectlCommand != "rollout undo" {
+			return "", fmt.Errorf("to_revision is only supported for rollout undo, not %s", kubectlCommand)
+		}
+		revision, ok, err := positiveIntParam("to_revision", params["to_revision"])
+		if err != nil {
+			return "", err
+		}
+		if ok {
+			command = insertFlags(command, []string{fmt.Sprintf("--to-revision=%d", revision)})
+		}
+	}
+
+	// Check a caller's jq expression before running anything
+	if err := checkResultTransform(params, cfg); err != nil {
+		return "", err
+	}
+
+	// Narrow api-versions to one API group after it runs
+	if group, _ := params["group"].(string); strings.TrimSpace(group) != "" && kubectlCommand != "api-versions" {
+		return "", fmt.Errorf("group is only supported for api-versions, not %s", operation)
+	}
+
+	// Show the pod template of one revision in a rollout history
+	if params["revision"] != nil {
+		if kubectlCommand != "rollout history" {
+			return "", fmt.Errorf("revision is only supported for rollout history, not %s", kubectlCommand)
+		}
+		revision, ok, err := positiveIntParam("revision", params["revision"])
+		if err != nil {
+			return "", err
+		}
+		if ok {
+			command = insertFlags(command, []string{fmt.Sprintf("--revision=%d", revision)})
+		}
+	}
+
+	// Choose how dependents of deleted resources are handled
+	if cascade, _ := params["cascade"].(string); strings.TrimSpace(cascade) != "" {
+		if kubectlCommand != "delete" {
+			return "", fmt.Errorf("cascade is only supported for delete, not %s", operation)
+		}
+		command, err = applyCascade(command, strings.TrimSpace(cascade))
+		if err != nil {
+			return "", err
+		}
+	}
+
+	// Wait for deleted resources to be removed before returning
+	if waitForDeletion, _ := params["wait_for_deletion"].(bool); waitForDeletion {
+		if kubectlCommand != "delete" {
+			return "", fmt.Errorf("wait_for_deletion is only supported for delete, not %s", operation)
+		}
+		command, err = applyWaitForDeletion(command)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	// Serialize a structured patch body into -p with its --type
+	patchType, _ := params["patch_type"].(string)
+	if body, ok := params["patch_body"]; ok && body != nil {
+		if kubectlCommand != "patch" {
+			return "", fmt.Errorf("patch_body is only supported for patch, not %s", operation)
+		}
+		command, err = applyPatchBody(command, strings.TrimSpace(patchType), body)
+		if err != nil {
+			return "", err
+		}
+	} else if strings.TrimSpace(patchType) != "" {
+		return "", fmt.Errorf("patch_type requires patch_body")
+	}
+
+	// List only names
+	if namesOnly, _ := params["names_only"].(bool); namesOnly {
+		if structured, _ := params["structured"].(bool); structured {
+			return "", fmt.Errorf("names_only can't be combined with structured")
+		}
+		command, err = applyNamesOnly(command)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	// Split a JSON list into one object per line after it runs
+	if wantsNDJSON(params) {
+		command, err = applyNDJSON(command, params)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	// Leave managedFields out of JSON and YAML unless they were asked for
+	command, err = applyManagedFields(command, params)
+	if err != nil {
+		return "", err
+	}
+
+	// Fill in create configmap, secret and job from structured data
+	command, err = applyCreateParams(command, params)
+	if err != nil {
+		return "", err
+	}
+
+	// Read the objects of a create or apply from the inline manifest
+	command, err = applyManifestParam(command, params)
+	if err != nil {
+		return "", err
+	}
+
+	// Scope logs/exec/cp to a container
+	if container, _ := params["container"].(string); strings.TrimSpace(container) != "" {
+		if !containerCommands[kubectlCommand] {
+			return "", fmt.Errorf("container is only supported for logs, exec and cp, not %s", operation)
+		}
+		command, err = applyContainer(command, strings.TrimSpace(container))
+		if err != nil {
+			return "", err
+		}
+	}
+
+	// Send the command to another API server
+	if server, _ := params["server"].(string); strings.TrimSpace(server) != "" {
+		command, err = applyServer(command, strings.TrimSpace(server))
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return injectImpersonationNamespace(imp.apply(command), cfg), nil
+}
+
+// containerCommands lists the kubectl commands that accept -c <container>
+var containerCommands = map[string]bool{
+	"logs": true,
+	"exec": true,
+	"cp":   true,
+}
+
+// containerName matches valid container names (DNS labels)
+var containerName = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+// applyContainer adds -c for the container parameter unless args already select a container
+func applyContainer(command, container string) (string, error) {
+	if !containerName.MatchString(container) {
+		return "", fmt.Errorf("invalid container name '%s'", container)
+	}
+	cmdline, err := parseCommandLine(command)
+	if err != nil {
+		return "", err
+	}
+	if cmdline.hasFlag("--container") {
+		return command, nil
+	}
+	return insertFlags(command, []string{"-c", container}), nil
+}
+
+// cascadeStrategies are the values kubectl accepts for delete --cascade
+var cascadeStrategies = map[string]bool{
+	"background": true,
+	"foreground": true,
+	"orphan":     true,
+}
+
+// applyCascade adds --cascade for the cascade parameter unless args already set one
+func applyCascade(command, cascade string) (string, error) {
+	if !cascadeStrategies[cascade] {
+		return "", fmt.Errorf("invalid cascade '%s': must be background, foreground or orphan", cascade)
+	}
+	cmdline, err := parseCommandLine(command)
+	if err != nil {
+		return "", err
+	}
+	if cmdline.hasFlag("--cascade") {
+		return command, nil
+	}
+	return insertFlags(command, []string{"--cascade=" + cascade}), nil
+}
+
+// applyServer adds --server for the server parameter unless args already set one. The URL is
+// checked against the server allow-list when the command is validated.
+func applyServer(command, server string) (string, error) {
+	if strings.ContainsAny(server, " \t\n'\"") {
+		return "", fmt.Errorf("invalid server '%s'", server)
+	}
+	cmdline, err := parseCommandLine(command)
+	if err != nil {
+		return "", err
+	}
+	if cmdline.hasFlag("--server") {
+		return command, nil
+	}
+	return insertFlags(command, []string{"--server=" + server}), nil
+}
+
+// positiveIntParam reads a positive whole number from a JSON number or string parameter
+func positiveIntParam(name string, value interface{}) (int64, bool, error) {
+	var n int64
+	switch v := value.(type) {
+	case nil:
+		return 0, false, nil
+	case float64:
+		n = int64(v)
+		if float64(n) != v {
+			return 0, false, fmt.Errorf("%s must be a whole number, got %v", name, v)
+		}
+	case int:
+		n = int64(v)
+	case string:
+		parsed, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+		if err != nil {
+			return 0, false, fmt.Errorf("%s must be a number, got '%s'", name, v)
+		}
+		n = parsed
+	default:
+		return 0, false, fmt.Errorf("%s must be a number", name)
+	}
+
+	if n <= 0 {
+		return 0, false, fmt.Errorf("%s must be positive, got %d", name, n)
+	}
+	return n, true, nil
+}
+
+// runCommand executes a validated kubectl command, answering simple reads from the
+// informer cache when it is enabled and can serve them
+func (e *KubectlToolExecutor) runCommand(command string, cfg *config.ConfigData) (string, error) {
+	return e.runCommandWithin(command, 0, cfg)
+}
+
+// runCommandWithin is runCommand with a caller-requested timeout in seconds (0 for none),
+// which takes precedence over the verb's --operation-timeouts entry
+func (e *KubectlToolExecutor) runCommandWithin(command string, requested int, cfg *config.ConfigData) (string, error) {
+	if e.store != nil {
+		if output, ok := serveFromStore(e.store, command); ok {
+			return output, nil
+		}
+	}
+	if e.reads != nil {
+		if versionCheck, ok := resourceVersionCommand(command); ok {
+			return e.cachedRead(command, versionCheck, requested, cfg)
+		}
+	}
+	return e.runOnHost(command, requested, cfg)
+}
+
+// runOnHost runs a kubectl command on the remote agent within the requested timeout
+func (e *KubectlToolExecutor) runOnHost(command string, requested int, cfg *config.ConfigData) (string, error) {
+	verb, _, _ := strings.Cut(strings.TrimSpace(command), " ")
+	timeout := cfg.EffectiveTimeout(verb, requested)
+	output, err := e.executor.executeKubectlCommandOnHostWithin(command, "", timeout, cfg) // kubectl
+	if verb == "describe" && errors.Is(err, errRequestTimeout) {
+		// A describe that runs out of time usually has a large object or many related events
+		return "", fmt.Errorf("describe timed out; try get -o yaml or increase timeout: %w", err)
+	}
+	if verb == "get" && cfg.TimeoutPageSize > 0 && serverTimedOut(err) {
+		// A large list the API server can't return at once usually succeeds in pages
+		if list, ok := pagedListCommand(command, cfg); ok {
+			return e.getInPages(command, list, timeout, cfg)
+		}
+		return "", fmt.Errorf("%w (lists with -o json or -o name and -n or -A are fetched again in pages)", err)
+	}
+	return errOutput(command, output, err)
+}
+
+// callTimeout is the timeout in seconds runCommandWithin applies to a command
+func (e *KubectlToolExecutor) callTimeout(command string, requested int, cfg *config.ConfigData) int {
+	verb, _, _ := strings.Cut(strings.TrimSpace(command), " ")
+	if timeout := cfg.EffectiveTimeout(verb, requested); timeout > 0 {
+		return timeout
+	}
+	return cfg.ClampTimeout(cfg.Timeout)
+}
+
+// EnableInformerReads serves get operations on pods, deployments and services from a local
+// cache of the kinds and namespaces that are read, relisted every resync interval. Writes and
+// any read the cache can't answer still go to the API server. Closing the returned channel
+// stops the relists.
+func (e *KubectlToolExecutor) EnableInformerReads(cfg *config.ConfigData, resync time.Duration) chan<- struct{} {
+	inf := newInformer(resync, func(command string) (string, error) {
+		return e.executor.executeKubectlCommandOnHost(command, "", cfg)
+	})
+
+	stop := make(chan struct{})
+	go inf.run(stop)
+
+	e.store = inf
+	return stop
 }
 
 // validateCombination validates if the operation/resource combination is valid for the tool
@@ -93,7 +781,7 @@ func (e *KubectlToolExecutor) validateCombination(toolName, operation, resource
 // validateResourcesOperation validates operations for the resources tool
 func (e *KubectlToolExecutor) validateResourcesOperation(operation string) error {
 	// Always allow read-only operations
-	readOnlyOps := []string{"get", "describe"}
+	readOnlyOps := []string{"get", "describe", "describe-yaml", "configmap-data"}
 	for _, validOp := range readOnlyOps {
 		if operation == validOp {
 			return nil
@@ -101,7 +789,7 @@ func (e *KubectlToolExecutor) validateResourcesOperation(operation string) error
 	}
 
 	// For write operations, they will be validated by the access level check
-	writeOps := []string{"create", "delete", "apply", "patch", "replace"}
+	writeOps := []string{"create", "delete", "apply", "patch", "replace", "restore-last"}
 	for _, validOp := range writeOps {
 		if operation == validOp {
 			return nil
@@ -159,7 +847,7 @@ func (e *KubectlToolExecutor) validateMetadataOperation(operation string) error
 
 // validateDiagnosticsOperation validates operations for the diagnostics tool
 func (e *KubectlToolExecutor) validateDiagnosticsOperation(operation string) error {
-	validOps := []string{"logs", "events", "top", "exec", "cp"}
+	validOps := []string{"logs", "events", "top", "exec", "cp", "describe-tree", "pod-diagnosis", "orphans", "deployment-logs", "quota-status", "pending-pods", "probes", "node-capacity", "pull-secrets", "cronjobs", "drain-check", "ingress-routes", "restarts", "service-endpoints", "rollout-stall", "stuck-terminating", "deployment-readiness"}
 	for _, validOp := range validOps {
 		if operation == validOp {
 			return nil
@@ -185,11 +873,11 @@ func (e *KubectlToolExecutor) validateClusterOperation(operation string) error {
 func (e *KubectlToolExecutor) validateConfigOperation(operation, resource string) error {
 	// Always allow read-only operations
 	switch operation {
-	case "diff":
+	case "diff", "drift", "namespace-diff", "lint":
 		return nil
 	case "auth":
-		if resource != "can-i" {
-			return fmt.Errorf("auth operation requires 'can-i' as resource")
+		if resource != "can-i" && resource != "reconcile" {
+			return fmt.Errorf("auth operation requires 'can-i' or 'reconcile' as resource")
 		}
 		return nil
 	case "certificate":
@@ -203,7 +891,7 @@ func (e *KubectlToolExecutor) validateConfigOperation(operation, resource string
 		return fmt.Errorf("invalid certificate subcommand '%s'. Valid subcommands: %s",
 			resource, strings.Join(validSubcmds, ", "))
 	default:
-		return fmt.Errorf("invalid operation '%s' for config tool. Valid operations: diff, auth, certificate",
+		return fmt.Errorf("invalid operation '%s' for config tool. Valid operations: diff, drift, namespace-diff, lint, auth, certificate",
 			operation)
 	}
 }
@@ -253,11 +941,22 @@ func (e *KubectlToolExecutor) checkAccessLevel(command string, cfg *config.Confi
 	switch cfg.AccessLevel {
 	case "readonly":
 		if category != "read-only" {
-			return fmt.Errorf("command requires %s access, but current access level is read-only", category)
+			required := "readwrite"
+			if category == "admin" {
+				required = "admin"
+			}
+			return fmt.Errorf("command requires %s access, but current access level is read-only: %s",
+				category, cfg.AccessLevelGuidance(required))
+		}
+		// Read-only sessions run as the server's own identity
+		if cmdline, err := parseCommandLine(command); err == nil && (cmdline.hasFlag("--as") || cmdline.hasFlag("--as-group")) {
+			return fmt.Errorf("impersonation requires read-write access, but current access level is read-only: %s",
+				cfg.AccessLevelGuidance("readwrite"))
 		}
 	case "readwrite":
 		if category == "admin" {
-			return fmt.Errorf("command requires admin access, but current access level is read-write")
+			return fmt.Errorf("command requires admin access, but current access level is read-write: %s",
+				cfg.AccessLevelGuidance("admin"))
 		}
 	case "admin":
 		// Admin can execute all commands
@@ -268,6 +967,118 @@ func (e *KubectlToolExecutor) checkAccessLevel(command string, cfg *config.Confi
 	return nil
 }
 
+// checkRolloutUndo requires rollout undo to name its target revision, either with to_revision
+// (or --to-revision in args) or by passing confirm=true to accept the previous revision
+func (e *KubectlToolExecutor) checkRolloutUndo(command string, params map[string]interface{}) error {
+	cmdline, err := parseCommandLine(command)
+	if err != nil {
+		return err
+	}
+	if cmdline.verb() != "rollout" || len(cmdline.args()) == 0 || cmdline.args()[0] != "undo" {
+		return nil
+	}
+
+	if revision, ok := cmdline.flag("--to-revision"); ok && revision != "" && revision != "0" {
+		return nil
+	}
+	if confirm, _ := params["confirm"].(bool); confirm {
+		return nil
+	}
+
+	return fmt.Errorf("rollout undo requires to_revision, or confirm=true to roll back to the previous revision; use rollout history to list revisions")
+}
+
+// checkOrphanDelete requires confirm=true for deletes with --cascade=orphan (or the older
+// --cascade=false), which leave the deleted resources' dependents running unowned
+func (e *KubectlToolExecutor) checkOrphanDelete(command string, params map[string]interface{}) error {
+	cmdline, err := parseCommandLine(command)
+	if err != nil {
+		return err
+	}
+	if cmdline.verb() != "delete" {
+		return nil
+	}
+
+	if cascade, _ := cmdline.flag("--cascade"); cascade != "orphan" && cascade != "false" {
+		return nil
+	}
+	if confirm, _ := params["confirm"].(bool); confirm {
+		return nil
+	}
+
+	return fmt.Errorf("delete with cascade=orphan leaves dependent resources behind and requires confirm=true")
+}
+
+// checkBulkMutation requires confirm=true, or --allow-bulk-mutations, for commands that change
+// resources with --all, which selects every resource of the type in the namespace. Reads with
+// --all and --all-namespaces are not affected.
+func (e *KubectlToolExecutor) checkBulkMutation(command string, params map[string]interface{}, cfg *config.ConfigData) error {
+	if cfg.AllowBulkMutations || e.determineCommandCategory(command) == "read-only" {
+		return nil
+	}
+	cmdline, err := parseCommandLine(command)
+	if err != nil {
+		return err
+	}
+	if !cmdline.boolFlag("--all") {
+		return nil
+	}
+	if confirm, _ := params["confirm"].(bool); confirm {
+		return nil
+	}
+
+	return fmt.Errorf("%s --all changes every matching resource in the namespace and requires confirm=true (or the server's --allow-bulk-mutations)", cmdline.verb())
+}
+
+// checkDeleteByName refuses deletes that don't name the resources they remove when
+// --delete-requires-name is set: --all, label and field selectors are rejected whatever the
+// confirm parameter says, and a bare type needs at least one name. Deletes of the resources in
+// files (-f, -k) name them in the files and are allowed.
+func (e *KubectlToolExecutor) checkDeleteByName(command string, cfg *config.ConfigData) error {
+	if !cfg.DeleteRequiresName {
+		return nil
+	}
+	cmdline, err := parseCommandLine(command)
+	if err != nil {
+		return err
+	}
+	if cmdline.verb() != "delete" {
+		return nil
+	}
+
+	if cmdline.boolFlag("--all") || cmdline.hasFlag("--selector") || cmdline.hasFlag("--field-selector") {
+		return fmt.Errorf("delete must name the resources to remove; --all and selectors are refused by the server's --delete-requires-name")
+	}
+	if cmdline.hasFlag("--filename") || cmdline.hasFlag("--kustomize") {
+		return nil
+	}
+	args := cmdline.args()
+	if len(args) < 2 && (len(args) == 0 || !strings.Contains(args[0], "/")) {
+		return fmt.Errorf("delete must name the resources to remove (e.g. 'pod web-0' or 'pod/web-0') under the server's --delete-requires-name")
+	}
+	return nil
+}
+
+// checkAdminConfirmation enforces the confirmation token on admin commands when --require-admin-confirm is set
+func (e *KubectlToolExecutor) checkAdminConfirmation(command string, params map[string]interface{}, cfg *config.ConfigData) error {
+	if cfg.SecurityConfig == nil || !cfg.SecurityConfig.RequireAdminConfirm {
+		return nil
+	}
+	if e.determineCommandCategory(command) != "admin" {
+		return nil
+	}
+
+	token, _ := params["confirm_token"].(string)
+	if token == "" {
+		return fmt.Errorf("admin operations require a confirm_token; call kubectl_check_permissions to obtain the current token")
+	}
+	if !cfg.SecurityConfig.ConsumeAdminConfirmToken(token) {
+		return fmt.Errorf("confirm_token is invalid or expired; call kubectl_check_permissions to obtain a new token")
+	}
+
+	return nil
+}
+
 // determineCommandCategory determines if a command is read-only, read-write, or admin
 func (e *KubectlToolExecutor) determineCommandCategory(command string) string {
 	// Extract the base command
@@ -278,6 +1089,11 @@ func (e *KubectlToolExecutor) determineCommandCategory(command string) string {
 
 	baseCmd := parts[0]
 
+	// auth reconcile writes RBAC roles and bindings, unlike the auth verb's other subcommands
+	if baseCmd == "auth" && len(parts) > 1 && parts[1] == "reconcile" {
+		return "admin"
+	}
+
 	// Check if it's a read-only command
 	readOnlyCommands := GetReadOnlyKubectlCommands()
 	for _, cmd := range readOnlyCommands {
@@ -1,8 +1,13 @@
 package kubectl
 
 import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/Azure/mcp-kubernetes/pkg/config"
 	"github.com/Azure/mcp-kubernetes/pkg/security"
@@ -118,7 +123,7 @@ func TestKubectlToolExecutor_ValidateCombination(t *testing.T) {
 			operation: "auth",
 			resource:  "invalid",
 			wantErr:   true,
-			errMsg:    "auth operation requires 'can-i' as resource",
+			errMsg:    "auth operation requires 'can-i' or 'reconcile' as resource",
 		},
 		{
 			name:      "valid certificate approve",
@@ -445,6 +450,27 @@ func TestKubectlToolExecutor_CheckAccessLevel(t *testing.T) {
 	}
 }
 
+func TestKubectlToolExecutor_AccessDenialGuidance(t *testing.T) {
+	executor := NewKubectlToolExecutor(&Worker{})
+
+	cfg := newTestConfig("admin")
+	cfg.DowngradeToReadOnly("mw-opsai-cluster-role was not found")
+
+	err := executor.checkAccessLevel("drain node-1", cfg)
+	if err == nil {
+		t.Fatal("expected drain to be denied after the downgrade")
+	}
+	want := "this requires admin; the server was started with admin but is running as readonly because mw-opsai-cluster-role was not found"
+	if !strings.Contains(err.Error(), want) {
+		t.Errorf("error = %q, want it to contain %q", err, want)
+	}
+
+	err = executor.checkAccessLevel("drain node-1", newTestConfig("readwrite"))
+	if err == nil || !strings.Contains(err.Error(), "restarted with --access-level=admin") {
+		t.Errorf("expected restart guidance without a downgrade, got %v", err)
+	}
+}
+
 func TestKubectlToolExecutor_Execute(t *testing.T) {
 	// Note: This test validates parameter extraction and command construction
 	// but doesn't execute actual kubectl commands
@@ -581,3 +607,617 @@ func TestMapOperationToCommand(t *testing.T) {
 		})
 	}
 }
+
+func TestKubectlToolExecutor_CheckAdminConfirmation(t *testing.T) {
+	executor := NewKubectlToolExecutor(&Worker{})
+
+	secConfig := security.NewSecurityConfig()
+	secConfig.AccessLevel = security.AccessLevelAdmin
+	secConfig.RequireAdminConfirm = true
+	cfg := &config.ConfigData{
+		AccessLevel:    "admin",
+		SecurityConfig: secConfig,
+	}
+
+	// Missing token is rejected
+	err := executor.checkAdminConfirmation("drain node-1", map[string]interface{}{}, cfg)
+	if err == nil || !strings.Contains(err.Error(), "require a confirm_token") {
+		t.Fatalf("expected missing token error, got %v", err)
+	}
+
+	// Wrong token is rejected
+	secConfig.IssueAdminConfirmToken()
+	err = executor.checkAdminConfirmation("drain node-1", map[string]interface{}{"confirm_token": "not-the-token"}, cfg)
+	if err == nil || !strings.Contains(err.Error(), "invalid or expired") {
+		t.Fatalf("expected invalid token error, got %v", err)
+	}
+
+	// Current token is accepted once
+	token := secConfig.IssueAdminConfirmToken()
+	params := map[string]interface{}{"confirm_token": token}
+	if err := executor.checkAdminConfirmation("drain node-1", params, cfg); err != nil {
+		t.Fatalf("expected current token to be accepted, got %v", err)
+	}
+	if err := executor.checkAdminConfirmation("cordon node-1", params, cfg); err == nil {
+		t.Errorf("expected reused token to be rejected")
+	}
+
+	// Non-admin commands are not gated
+	if err := executor.checkAdminConfirmation("get pods", map[string]interface{}{}, cfg); err != nil {
+		t.Errorf("read-only command should not require a token, got %v", err)
+	}
+
+	// Nothing is gated when safe mode is off
+	secConfig.RequireAdminConfirm = false
+	if err := executor.checkAdminConfirmation("drain node-1", map[string]interface{}{}, cfg); err != nil {
+		t.Errorf("admin command should not require a token when safe mode is off, got %v", err)
+	}
+}
+
+func TestKubectlToolExecutor_Echo(t *testing.T) {
+	// A nil worker would fail if the command were executed
+	executor := NewKubectlToolExecutor(nil)
+	cfg := &config.ConfigData{
+		AccessLevel: "readonly",
+		SecurityConfig: &security.SecurityConfig{
+			AccessLevel: security.AccessLevelReadOnly,
+		},
+	}
+
+	output, err := executor.Execute(map[string]interface{}{
+		"_tool_name": "kubectl_resources",
+		"operation":  "patch",
+		"resource":   "deployment",
+		"args":       `web -n prod -p '{"spec":{"replicas":2}}'`,
+		"as":         "alice",
+		"as_group":   "devs",
+		"echo":       true,
+	}, cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := `kubectl patch deployment web -n prod -p '{"spec":{"replicas":2}}' --as=alice --as-group=devs`
+	if output != expected {
+		t.Errorf("echo output = %q, want %q", output, expected)
+	}
+}
+
+func TestKubectlToolExecutor_RolloutUndoGuard(t *testing.T) {
+	tests := []struct {
+		name     string
+		params   map[string]interface{}
+		expected string
+		errMsg   string
+	}{
+		{
+			name:   "bare undo is rejected",
+			params: map[string]interface{}{},
+			errMsg: "rollout undo requires to_revision",
+		},
+		{
+			name:     "undo with revision",
+			params:   map[string]interface{}{"to_revision": float64(3)},
+			expected: "kubectl rollout undo deployment/web -n prod --to-revision=3",
+		},
+		{
+			name:     "undo with confirm",
+			params:   map[string]interface{}{"confirm": true},
+			expected: "kubectl rollout undo deployment/web -n prod",
+		},
+		{
+			name:   "invalid revision",
+			params: map[string]interface{}{"to_revision": float64(-1)},
+			errMsg: "to_revision must be positive",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var commands []string
+			worker := newTestWorker(t, func(command string) map[string]interface{} {
+				commands = append(commands, command)
+				return map[string]interface{}{"stdout": "deployment.apps/web rolled back"}
+			})
+			executor := NewKubectlToolExecutor(worker)
+
+			params := map[string]interface{}{
+				"_tool_name": "kubectl_workloads",
+				"operation":  "rollout",
+				"resource":   "undo",
+				"args":       "deployment/web -n prod",
+			}
+			for k, v := range tt.params {
+				params[k] = v
+			}
+
+			_, err := executor.Execute(params, newTestConfig("readwrite"))
+			if tt.errMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+					t.Fatalf("Execute() error = %v, want error containing %q", err, tt.errMsg)
+				}
+				if len(commands) != 0 {
+					t.Errorf("rejected undo should not run, got %v", commands)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(commands) != 1 || commands[0] != tt.expected {
+				t.Errorf("commands = %v, want [%s]", commands, tt.expected)
+			}
+		})
+	}
+
+	t.Run("rejected for rollout status", func(t *testing.T) {
+		executor := NewKubectlToolExecutor(nil)
+		_, err := executor.Execute(map[string]interface{}{
+			"_tool_name":  "kubectl_workloads",
+			"operation":   "rollout",
+			"resource":    "status",
+			"args":        "deployment/web -n prod",
+			"to_revision": float64(3),
+			"echo":        true,
+		}, newTestConfig("readwrite"))
+		if err == nil || !strings.Contains(err.Error(), "to_revision is only supported for rollout undo, not rollout status") {
+			t.Fatalf("expected to_revision rejection, got %v", err)
+		}
+	})
+}
+
+func TestKubectlToolExecutor_Container(t *testing.T) {
+	tests := []struct {
+		name      string
+		operation string
+		resource  string
+		args      string
+		container string
+		expected  string
+		errMsg    string
+	}{
+		{
+			name:      "logs with container",
+			operation: "logs",
+			args:      "web-0 -n prod --tail=50",
+			container: "sidecar",
+			expected:  "kubectl logs web-0 -n prod --tail=50 -c sidecar",
+		},
+		{
+			name:      "exec inserts before command",
+			operation: "exec",
+			args:      "web-0 -n prod -- sh -c date",
+			container: "app",
+			expected:  "kubectl exec web-0 -n prod -c app -- sh -c date",
+		},
+		{
+			name:      "container already in args",
+			operation: "logs",
+			args:      "web-0 -c app",
+			container: "sidecar",
+			errMsg:    "the container parameter conflicts with -c/--container in args",
+		},
+		{
+			name:      "rejected for events",
+			operation: "events",
+			args:      "-n prod",
+			container: "app",
+			errMsg:    "container is only supported for logs, exec and cp",
+		},
+		{
+			name:      "rejected for top node",
+			operation: "top",
+			resource:  "node",
+			container: "app",
+			errMsg:    "container is only supported for logs, exec and cp",
+		},
+		{
+			name:      "invalid name",
+			operation: "logs",
+			args:      "web-0",
+			container: "app; rm",
+			errMsg:    "invalid container name",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			executor := NewKubectlToolExecutor(nil)
+			output, err := executor.Execute(map[string]interface{}{
+				"_tool_name": "kubectl_diagnostics",
+				"operation":  tt.operation,
+				"resource":   tt.resource,
+				"args":       tt.args,
+				"container":  tt.container,
+				"echo":       true,
+			}, newTestConfig("readwrite"))
+
+			if tt.errMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+					t.Fatalf("Execute() error = %v, want error containing %q", err, tt.errMsg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if output != tt.expected {
+				t.Errorf("Execute() = %q, want %q", output, tt.expected)
+			}
+		})
+	}
+}
+
+func TestKubectlToolExecutor_DeleteCascade(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     string
+		params   map[string]interface{}
+		expected string
+		errMsg   string
+	}{
+		{
+			name:     "default cascade",
+			args:     "deployment web -n prod",
+			expected: "kubectl delete deployment web -n prod",
+		},
+		{
+			name:     "background",
+			args:     "deployment web -n prod",
+			params:   map[string]interface{}{"cascade": "background"},
+			expected: "kubectl delete deployment web -n prod --cascade=background",
+		},
+		{
+			name:     "foreground",
+			args:     "deployment web -n prod",
+			params:   map[string]interface{}{"cascade": "foreground"},
+			expected: "kubectl delete deployment web -n prod --cascade=foreground",
+		},
+		{
+			name:   "orphan without confirm",
+			args:   "deployment web -n prod",
+			params: map[string]interface{}{"cascade": "orphan"},
+			errMsg: "requires confirm=true",
+		},
+		{
+			name:     "orphan with confirm",
+			args:     "deployment web -n prod",
+			params:   map[string]interface{}{"cascade": "orphan", "confirm": true},
+			expected: "kubectl delete deployment web -n prod --cascade=orphan",
+		},
+		{
+			name:   "orphan in args without confirm",
+			args:   "deployment web -n prod --cascade=orphan",
+			errMsg: "requires confirm=true",
+		},
+		{
+			name:   "legacy cascade=false without confirm",
+			args:   "deployment web -n prod --cascade=false",
+			errMsg: "requires confirm=true",
+		},
+		{
+			name:   "invalid strategy",
+			args:   "deployment web -n prod",
+			params: map[string]interface{}{"cascade": "all"},
+			errMsg: "invalid cascade 'all'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var commands []string
+			worker := newTestWorker(t, func(command string) map[string]interface{} {
+				commands = append(commands, command)
+				return map[string]interface{}{"stdout": `deployment.apps "web" deleted`}
+			})
+			executor := NewKubectlToolExecutor(worker)
+
+			params := map[string]interface{}{
+				"_tool_name": "kubectl_resources",
+				"operation":  "delete",
+				"resource":   "",
+				"args":       tt.args,
+			}
+			for k, v := range tt.params {
+				params[k] = v
+			}
+
+			_, err := executor.Execute(params, newTestConfig("readwrite"))
+			if tt.errMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+					t.Fatalf("Execute() error = %v, want error containing %q", err, tt.errMsg)
+				}
+				if len(commands) != 0 {
+					t.Errorf("rejected delete should not run, got %v", commands)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(commands) != 1 || commands[0] != tt.expected {
+				t.Errorf("commands = %v, want [%s]", commands, tt.expected)
+			}
+		})
+	}
+
+	t.Run("rejected for get", func(t *testing.T) {
+		executor := NewKubectlToolExecutor(nil)
+		_, err := executor.Execute(map[string]interface{}{
+			"_tool_name": "kubectl_resources",
+			"operation":  "get",
+			"resource":   "pods",
+			"args":       "",
+			"cascade":    "orphan",
+			"echo":       true,
+		}, newTestConfig("readwrite"))
+		if err == nil || !strings.Contains(err.Error(), "cascade is only supported for delete") {
+			t.Fatalf("expected cascade rejection, got %v", err)
+		}
+	})
+}
+
+func TestKubectlToolExecutor_MaxTimeout(t *testing.T) {
+	// The agent accepts requests but never answers, so calls end at their timeout
+	var mu sync.Mutex
+	var sent []float64
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		var msg struct {
+			Payload struct {
+				Result map[string]interface{} `json:"result"`
+			}
+		}
+		if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if ping, _ := msg.Payload.Result["ping"].(bool); !ping {
+			timeout, _ := msg.Payload.Result["timeout_seconds"].(float64)
+			mu.Lock()
+			sent = append(sent, timeout)
+			mu.Unlock()
+		}
+	}))
+	t.Cleanup(srv.Close)
+	worker, err := New(&Config{
+		Mode:                ModeAgent,
+		Location:            "test-host",
+		Token:               "test-token",
+		UnsubscribeEndpoint: srv.URL,
+		Timeout:             600,
+	})
+	if err != nil {
+		t.Fatalf("failed to create worker: %v", err)
+	}
+	executor := NewKubectlToolExecutor(worker)
+
+	cfg := newTestConfig("readonly")
+	cfg.MaxTimeout = 1
+
+	for _, tt := range []struct {
+		name    string
+		timeout interface{}
+	}{
+		{name: "worker timeout over ceiling"},
+		{name: "requested timeout over ceiling", timeout: float64(600)},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			params := map[string]interface{}{
+				"_tool_name": "kubectl_resources",
+				"operation":  "get",
+				"resource":   "pods",
+				"args":       "-n shop",
+			}
+			if tt.timeout != nil {
+				params["timeout"] = tt.timeout
+			}
+
+			start := time.Now()
+			_, err := executor.Execute(params, cfg)
+			if err == nil || !strings.Contains(err.Error(), "timeout waiting for response") {
+				t.Fatalf("expected timeout, got %v", err)
+			}
+			if elapsed := time.Since(start); elapsed > 3*time.Second {
+				t.Errorf("call waited %s, want it clamped to max-timeout", elapsed)
+			}
+
+			// The agent is told the clamped timeout too
+			mu.Lock()
+			defer mu.Unlock()
+			if len(sent) == 0 || sent[len(sent)-1] != 1 {
+				t.Errorf("timeout_seconds sent to the agent = %v, want 1", sent)
+			}
+		})
+	}
+}
+
+func TestKubectlToolExecutor_Server(t *testing.T) {
+	tests := []struct {
+		name     string
+		allowed  string
+		server   string
+		expected string
+		errMsg   string
+	}{
+		{
+			name:     "allowed server",
+			allowed:  "https://api.example.com:6443",
+			server:   "https://api.example.com:6443",
+			expected: "kubectl get pods -n shop --server=https://api.example.com:6443",
+		},
+		{
+			name:    "server outside allow-list",
+			allowed: "https://api.example.com:6443",
+			server:  "https://other.example.com",
+			errMsg:  "API server 'https://other.example.com' is not allowed",
+		},
+		{
+			name:   "no allow-list",
+			server: "https://api.example.com:6443",
+			errMsg: "is not allowed",
+		},
+		{
+			name:     "no override",
+			expected: "kubectl get pods -n shop",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var commands []string
+			worker := newTestWorker(t, func(command string) map[string]interface{} {
+				commands = append(commands, command)
+				return map[string]interface{}{"stdout": "No resources found"}
+			})
+			executor := NewKubectlToolExecutor(worker)
+
+			cfg := newTestConfig("readonly")
+			cfg.SecurityConfig.SetAllowedServers(tt.allowed)
+
+			_, err := executor.Execute(map[string]interface{}{
+				"_tool_name": "kubectl_resources",
+				"operation":  "get",
+				"resource":   "pods",
+				"args":       "-n shop",
+				"server":     tt.server,
+			}, cfg)
+			if tt.errMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+					t.Fatalf("Execute() error = %v, want error containing %q", err, tt.errMsg)
+				}
+				if len(commands) != 0 {
+					t.Errorf("rejected command should not run, got %v", commands)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(commands) != 1 || commands[0] != tt.expected {
+				t.Errorf("commands = %v, want [%s]", commands, tt.expected)
+			}
+		})
+	}
+}
+
+func TestKubectlToolExecutor_DeleteRequiresName(t *testing.T) {
+	tests := []struct {
+		name   string
+		args   string
+		params map[string]interface{}
+		errMsg string
+	}{
+		{name: "label selector refused", args: "pods -l app=web -n shop", errMsg: "--all and selectors are refused"},
+		{name: "long selector refused", args: "pods --selector=app=web -n shop", errMsg: "--all and selectors are refused"},
+		{name: "field selector refused", args: "pods --field-selector=status.phase=Failed -n shop", errMsg: "--all and selectors are refused"},
+		{name: "--all refused even with confirm", args: "pods --all -n shop", params: map[string]interface{}{"confirm": true}, errMsg: "--all and selectors are refused"},
+		{name: "bare type refused", args: "pods -n shop", errMsg: "must name the resources"},
+		{name: "named resource", args: "pod web-0 -n shop"},
+		{name: "several names", args: "pod web-0 web-1 -n shop"},
+		{name: "type/name", args: "pod/web-0 -n shop"},
+		{name: "file", args: "-f web.yaml"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var commands []string
+			worker := newTestWorker(t, func(command string) map[string]interface{} {
+				commands = append(commands, command)
+				return map[string]interface{}{"stdout": "deleted"}
+			})
+			executor := NewKubectlToolExecutor(worker)
+			cfg := newTestConfig("readwrite")
+			cfg.DeleteRequiresName = true
+			cfg.AllowBulkMutations = true
+
+			params := map[string]interface{}{
+				"_tool_name": "kubectl_resources",
+				"operation":  "delete",
+				"resource":   "",
+				"args":       tt.args,
+			}
+			for k, v := range tt.params {
+				params[k] = v
+			}
+
+			_, err := executor.Execute(params, cfg)
+			if tt.errMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+					t.Fatalf("Execute() error = %v, want error containing %q", err, tt.errMsg)
+				}
+				if len(commands) != 0 {
+					t.Errorf("rejected delete should not run, got %v", commands)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(commands) != 1 {
+				t.Errorf("commands = %v, want one command", commands)
+			}
+		})
+	}
+}
+
+func TestKubectlToolExecutor_BulkMutation(t *testing.T) {
+	tests := []struct {
+		name      string
+		toolName  string
+		operation string
+		args      string
+		params    map[string]interface{}
+		allowBulk bool
+		errMsg    string
+	}{
+		{name: "delete --all blocked", toolName: "kubectl_resources", operation: "delete", args: "pods --all -n shop", errMsg: "delete --all changes every matching resource"},
+		{name: "delete --all=true blocked", toolName: "kubectl_resources", operation: "delete", args: "pods --all=true -n shop", errMsg: "requires confirm=true"},
+		{name: "scale --all blocked", toolName: "kubectl_workloads", operation: "scale", args: "deployment --all --replicas=0 -n shop", errMsg: "requires confirm=true"},
+		{name: "label --all blocked", toolName: "kubectl_metadata", operation: "label", args: "pods --all tier=web -n shop", errMsg: "requires confirm=true"},
+		{name: "delete --all with confirm", toolName: "kubectl_resources", operation: "delete", args: "pods --all -n shop", params: map[string]interface{}{"confirm": true}},
+		{name: "delete --all with --allow-bulk-mutations", toolName: "kubectl_resources", operation: "delete", args: "pods --all -n shop", allowBulk: true},
+		{name: "delete --all=false", toolName: "kubectl_resources", operation: "delete", args: "pods web --all=false -n shop"},
+		{name: "get --all-namespaces allowed", toolName: "kubectl_resources", operation: "get", args: "pods --all-namespaces"},
+		{name: "get -A allowed", toolName: "kubectl_resources", operation: "get", args: "pods -A"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var commands []string
+			worker := newTestWorker(t, func(command string) map[string]interface{} {
+				commands = append(commands, command)
+				return map[string]interface{}{"stdout": "done"}
+			})
+			executor := NewKubectlToolExecutor(worker)
+			cfg := newTestConfig("admin")
+			cfg.AllowBulkMutations = tt.allowBulk
+
+			params := map[string]interface{}{
+				"_tool_name": tt.toolName,
+				"operation":  tt.operation,
+				"resource":   "",
+				"args":       tt.args,
+			}
+			for k, v := range tt.params {
+				params[k] = v
+			}
+
+			_, err := executor.Execute(params, cfg)
+			if tt.errMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+					t.Fatalf("Execute() error = %v, want error containing %q", err, tt.errMsg)
+				}
+				if len(commands) != 0 {
+					t.Errorf("rejected command should not run, got %v", commands)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(commands) != 1 {
+				t.Errorf("commands = %v, want one command", commands)
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package kubectl
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/Azure/mcp-kubernetes/pkg/config"
+)
+
+// listTruncation annotates a json/yaml list that was cut down to --max-list-items
+type listTruncation struct {
+	TotalItems    int `json:"totalItems" yaml:"totalItems"`
+	ReturnedItems int `json:"returnedItems" yaml:"returnedItems"`
+}
+
+// limitListItems caps the number of items returned by a get command. JSON and YAML lists keep
+// their first max items and gain a "truncation" field with the total count; table and name
+// output keep their first max rows followed by a marker line. Other output formats and
+// non-list results are returned unchanged.
+func limitListItems(command, output string, max int) string {
+	if max <= 0 {
+		return output
+	}
+
+	cmdline, err := parseCommandLine(command)
+	if err != nil || cmdline.verb() != "get" {
+		return output
+	}
+
+	format, _ := cmdline.flag("--output")
+	switch {
+	case format == "json":
+		return limitJSONItems(output, max)
+	case format == "yaml":
+		return limitYAMLItems(output, max)
+	case format == "" || format == "wide" || strings.HasPrefix(format, "custom-columns"):
+		header := !cmdline.boolFlag("--no-headers")
+		return limitLines(output, max, header)
+	case format == "name":
+		return limitLines(output, max, false)
+	default:
+		// jsonpath and templates have no item structure to preserve
+		return output
+	}
+}
+
+// limitJSONItems truncates the items array of a kubectl JSON list
+func limitJSONItems(output string, max int) string {
+	var list map[string]interface{}
+	if err := json.Unmarshal([]byte(output), &list); err != nil {
+		return output
+	}
+	items, ok := list["items"].([]interface{})
+	if !ok || len(items) <= max {
+		return output
+	}
+
+	list["items"] = items[:max]
+	list["truncation"] = listTruncation{TotalItems: len(items), ReturnedItems: max}
+
+	limited, ok := marshalKubectlJSON(list)
+	if !ok {
+		return output
+	}
+	return limited
+}
+
+// limitYAMLItems truncates the items sequence of a kubectl YAML list, preserving key order
+func limitYAMLItems(output string, max int) string {
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(output), &doc); err != nil || len(doc.Content) == 0 {
+		return output
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return output
+	}
+
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value != "items" {
+			continue
+		}
+		items := root.Content[i+1]
+		if items.Kind != yaml.SequenceNode || len(items.Content) <= max {
+			return output
+		}
+
+		total := len(items.Content)
+		items.Content = items.Content[:max]
+		root.Content = append(root.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Value: "truncation"},
+			&yaml.Node{Kind: yaml.MappingNode, Content: []*yaml.Node{
+				{Kind: yaml.ScalarNode, Value: "totalItems"},
+				{Kind: yaml.ScalarNode, Tag: "!!int", Value: strconv.Itoa(total)},
+				{Kind: yaml.ScalarNode, Value: "returnedItems"},
+				{Kind: yaml.ScalarNode, Tag: "!!int", Value: strconv.Itoa(max)},
+			}},
+		)
+
+		var buf bytes.Buffer
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		if err := enc.Encode(&doc); err != nil {
+			return output
+		}
+		return buf.String()
+	}
+
+	return output
+}
+
+// limitLines keeps the first max rows of line-oriented output, plus the header if there is one
+func limitLines(output string, max int, header bool) string {
+	lines := strings.Split(strings.TrimRight(output, "\n"), "\n")
+
+	skip := 0
+	if header {
+		skip = 1
+	}
+	if len(lines)-skip <= max {
+		return output
+	}
+
+	total := len(lines) - skip
+	kept := lines[:skip+max]
+	kept = append(kept, fmt.Sprintf("... truncated: showing %d of %d items (--max-list-items)", max, total))
+	return strings.Join(kept, "\n") + "\n"
+}
+
+// limitOutputBytes cuts a command's output to the byte limit for its verb, from
+// --operation-output-bytes or --max-output-bytes, at the last line boundary within the limit,
+// and ends it with a marker line giving the bytes kept and the flag that set the limit
+func limitOutputBytes(command, output string, cfg *config.ConfigData) string {
+	verb, _, _ := strings.Cut(strings.TrimSpace(command), " ")
+	max, source := cfg.OutputByteLimit(verb)
+	if max <= 0 || len(output) <= max {
+		return output
+	}
+
+	cut := strings.LastIndex(output[:max], "\n") + 1
+	if cut == 0 {
+		// A single line longer than the limit is cut where it is, on a character boundary
+		cut = max
+		for cut > 0 && !utf8.RuneStart(output[cut]) {
+			cut--
+		}
+	}
+	kept := output[:cut]
+	if !strings.HasSuffix(kept, "\n") {
+		kept += "\n"
+	}
+	return kept + fmt.Sprintf("... truncated: showing %d of %d bytes (%s)\n", cut, len(output), source)
+}
@@ -0,0 +1,163 @@
+package kubectl
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// podList returns a kubectl JSON list with n pods
+func podList(n int) string {
+	items := make([]interface{}, n)
+	for i := range items {
+		items[i] = map[string]interface{}{
+			"kind":     "Pod",
+			"metadata": map[string]interface{}{"name": fmt.Sprintf("pod-%d", i)},
+		}
+	}
+	output, _ := marshalKubectlJSON(map[string]interface{}{"apiVersion": "v1", "kind": "List", "items": items})
+	return output
+}
+
+func TestLimitListItemsJSON(t *testing.T) {
+	output := limitListItems("get pods -A -o json", podList(1200), 500)
+
+	var list struct {
+		Items      []map[string]interface{} `json:"items"`
+		Truncation listTruncation           `json:"truncation"`
+	}
+	if err := json.Unmarshal([]byte(output), &list); err != nil {
+		t.Fatalf("output is not JSON: %v", err)
+	}
+	if len(list.Items) != 500 {
+		t.Errorf("items = %d, want 500", len(list.Items))
+	}
+	if list.Truncation.TotalItems != 1200 || list.Truncation.ReturnedItems != 500 {
+		t.Errorf("truncation = %+v, want total 1200 returned 500", list.Truncation)
+	}
+
+	// Lists within the cap are returned untouched
+	small := podList(3)
+	if got := limitListItems("get pods -o json", small, 500); got != small {
+		t.Errorf("list under the cap was modified")
+	}
+}
+
+func TestLimitListItemsYAML(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("apiVersion: v1\nitems:\n")
+	for i := 0; i < 10; i++ {
+		fmt.Fprintf(&b, "- kind: Pod\n  metadata:\n    name: pod-%d\n", i)
+	}
+	b.WriteString("kind: List\n")
+
+	output := limitListItems("get pods -o yaml", b.String(), 4)
+
+	var list struct {
+		Kind       string         `yaml:"kind"`
+		Items      []interface{}  `yaml:"items"`
+		Truncation listTruncation `yaml:"truncation"`
+	}
+	if err := yaml.Unmarshal([]byte(output), &list); err != nil {
+		t.Fatalf("output is not YAML: %v", err)
+	}
+	if len(list.Items) != 4 || list.Kind != "List" {
+		t.Errorf("items = %d kind = %q, want 4 items of a List", len(list.Items), list.Kind)
+	}
+	if list.Truncation.TotalItems != 10 || list.Truncation.ReturnedItems != 4 {
+		t.Errorf("truncation = %+v, want total 10 returned 4", list.Truncation)
+	}
+}
+
+func TestLimitListItemsText(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("NAME    READY   STATUS\n")
+	for i := 0; i < 5; i++ {
+		fmt.Fprintf(&b, "pod-%d   1/1     Running\n", i)
+	}
+
+	output := limitListItems("get pods", b.String(), 2)
+	lines := strings.Split(strings.TrimRight(output, "\n"), "\n")
+	if len(lines) != 4 || lines[0] != "NAME    READY   STATUS" || lines[2] != "pod-1   1/1     Running" {
+		t.Errorf("unexpected output:\n%s", output)
+	}
+	if !strings.Contains(lines[3], "showing 2 of 5 items") {
+		t.Errorf("missing truncation marker: %q", lines[3])
+	}
+
+	// No header with --no-headers or -o name
+	output = limitListItems("get pods -o name", "pod/a\npod/b\npod/c\n", 2)
+	if output != "pod/a\npod/b\n... truncated: showing 2 of 3 items (--max-list-items)\n" {
+		t.Errorf("unexpected name output: %q", output)
+	}
+
+	// Non-get commands and disabled limits are untouched
+	if got := limitListItems("describe pods", b.String(), 2); got != b.String() {
+		t.Errorf("describe output was modified")
+	}
+	if got := limitListItems("get pods", b.String(), 0); got != b.String() {
+		t.Errorf("output was modified with the limit disabled")
+	}
+}
+
+func TestLimitOutputBytes(t *testing.T) {
+	// 100 lines of 30 bytes each
+	var b strings.Builder
+	for i := 0; i < 100; i++ {
+		fmt.Fprintf(&b, "line %03d %s\n", i, strings.Repeat("x", 20))
+	}
+	long := b.String()
+	worker := newTestWorker(t, func(command string) map[string]interface{} {
+		return map[string]interface{}{"stdout": long}
+	})
+	executor := NewKubectlToolExecutor(worker)
+	cfg := newTestConfig("readonly")
+	cfg.MaxOutputBytes = 1000
+	cfg.OperationOutputBytes = map[string]int{"logs": 5000}
+
+	run := func(tool, operation, resource, args string) string {
+		output, err := executor.Execute(map[string]interface{}{
+			"_tool_name": tool,
+			"operation":  operation,
+			"resource":   resource,
+			"args":       args,
+		}, cfg)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return output
+	}
+
+	// logs has its own, larger limit and comes back whole
+	if output := run("kubectl_diagnostics", "logs", "", "web-1 -n shop"); output != long {
+		t.Errorf("expected logs under their limit to be returned whole, got %d bytes", len(output))
+	}
+
+	// get falls back to --max-output-bytes and is cut at a line boundary
+	output := run("kubectl_resources", "get", "pods", "-n shop")
+	lines := strings.Split(strings.TrimRight(output, "\n"), "\n")
+	if len(lines) != 34 || lines[32] != "line 032 "+strings.Repeat("x", 20) {
+		t.Fatalf("expected the first 33 lines and a marker, got %d lines ending %q", len(lines), lines[len(lines)-1])
+	}
+	if lines[33] != "... truncated: showing 990 of 3000 bytes (--max-output-bytes)" {
+		t.Errorf("unexpected marker %q", lines[33])
+	}
+
+	// A smaller per-operation limit names its flag
+	cfg.OperationOutputBytes["logs"] = 100
+	if output := run("kubectl_diagnostics", "logs", "", "web-1 -n shop"); !strings.HasSuffix(output, "... truncated: showing 90 of 3000 bytes (--operation-output-bytes)\n") {
+		t.Errorf("expected logs to be cut to their own limit, got %q", output)
+	}
+
+	// A line longer than the limit is cut within it
+	if got := limitOutputBytes("get pods", strings.Repeat("é", 10), cfg); !strings.HasPrefix(got, strings.Repeat("é", 10)) {
+		t.Errorf("expected output within the limit untouched, got %q", got)
+	}
+	cfg.MaxOutputBytes = 5
+	if got := limitOutputBytes("get pods", strings.Repeat("é", 10), cfg); got != "éé\n... truncated: showing 4 of 20 bytes (--max-output-bytes)\n" {
+		t.Errorf("expected a cut on a character boundary, got %q", got)
+	}
+}
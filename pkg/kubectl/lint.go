@@ -0,0 +1,370 @@
+package kubectl
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/Azure/mcp-kubernetes/pkg/config"
+	"gopkg.in/yaml.v3"
+)
+
+// lintReport is the result of validating a manifest against the cluster's OpenAPI schema
+type lintReport struct {
+	Valid   bool         `json:"valid"`
+	Objects []lintObject `json:"objects"`
+}
+
+// lintObject is the validation result for one object in the manifest
+type lintObject struct {
+	APIVersion string      `json:"api_version"`
+	Kind       string      `json:"kind"`
+	Name       string      `json:"name,omitempty"`
+	Errors     []lintError `json:"errors"`
+}
+
+// lintError is a problem with one field, named by its path, e.g.
+// spec.template.spec.containers[0].image
+type lintError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// openAPIDocument is the OpenAPI v3 document the API server serves for one group version
+type openAPIDocument struct {
+	Components struct {
+		Schemas map[string]*openAPISchema `json:"schemas"`
+	} `json:"components"`
+}
+
+// openAPISchema is the part of an OpenAPI v3 schema that lint checks
+type openAPISchema struct {
+	Ref                  string                    `json:"$ref"`
+	Type                 string                    `json:"type"`
+	Properties           map[string]*openAPISchema `json:"properties"`
+	AdditionalProperties *additionalProperties     `json:"additionalProperties"`
+	Items                *openAPISchema            `json:"items"`
+	Required             []string                  `json:"required"`
+	Enum                 []interface{}             `json:"enum"`
+	AllOf                []*openAPISchema          `json:"allOf"`
+	OneOf                []*openAPISchema          `json:"oneOf"`
+	AnyOf                []*openAPISchema          `json:"anyOf"`
+	PreserveUnknown      bool                      `json:"x-kubernetes-preserve-unknown-fields"`
+	IntOrString          bool                      `json:"x-kubernetes-int-or-string"`
+	GroupVersionKinds    []struct {
+		Group   string `json:"group"`
+		Version string `json:"version"`
+		Kind    string `json:"kind"`
+	} `json:"x-kubernetes-group-version-kind"`
+}
+
+// additionalProperties is either a boolean or the schema of the values of a map
+type additionalProperties struct {
+	allowed bool
+	schema  *openAPISchema
+}
+
+func (a *additionalProperties) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &a.allowed); err == nil {
+		return nil
+	}
+	a.allowed = true
+	return json.Unmarshal(data, &a.schema)
+}
+
+// schemaCache holds the OpenAPI documents fetched from the cluster, by group version
+type schemaCache struct {
+	mu        sync.Mutex
+	documents map[string]*openAPIDocument
+}
+
+func newSchemaCache() *schemaCache {
+	return &schemaCache{documents: make(map[string]*openAPIDocument)}
+}
+
+// schemaPath returns the API path of a group version's OpenAPI v3 document
+func schemaPath(apiVersion string) string {
+	if !strings.Contains(apiVersion, "/") {
+		return "/openapi/v3/api/" + apiVersion
+	}
+	return "/openapi/v3/apis/" + apiVersion
+}
+
+// openAPISchemas returns the OpenAPI document of a group version, fetching it from the
+// cluster the first time it is needed or again when refresh is set. fetched reports whether
+// the document came from the cluster rather than the cache.
+func (e *KubectlToolExecutor) openAPISchemas(apiVersion string, refresh bool, cfg *config.ConfigData) (document *openAPIDocument, fetched bool, err error) {
+	e.schemas.mu.Lock()
+	document, ok := e.schemas.documents[apiVersion]
+	e.schemas.mu.Unlock()
+	if ok && !refresh {
+		return document, false, nil
+	}
+
+	output, err := e.runReadCommand("get --raw "+schemaPath(apiVersion), cfg)
+	if err != nil {
+		return nil, false, err
+	}
+	document = &openAPIDocument{}
+	if err := json.Unmarshal([]byte(output), document); err != nil {
+		return nil, false, fmt.Errorf("failed to parse the OpenAPI schema of %s: %w", apiVersion, err)
+	}
+
+	e.schemas.mu.Lock()
+	e.schemas.documents[apiVersion] = document
+	e.schemas.mu.Unlock()
+	return document, true, nil
+}
+
+// kindSchema finds the schema of a kind in a group version's document
+func (d *openAPIDocument) kindSchema(apiVersion, kind string) *openAPISchema {
+	group, version, ok := strings.Cut(apiVersion, "/")
+	if !ok {
+		group, version = "", apiVersion
+	}
+	for _, schema := range d.Components.Schemas {
+		for _, gvk := range schema.GroupVersionKinds {
+			if gvk.Group == group && gvk.Version == version && gvk.Kind == kind {
+				return schema
+			}
+		}
+	}
+	return nil
+}
+
+// lintManifest validates each object of an inline manifest against the OpenAPI schema the
+// cluster serves for its apiVersion and kind, reporting unknown fields, wrong types, values
+// outside an enum and missing required fields by field path. Schemas are fetched once per
+// group version and cached; a kind missing from a cached schema, such as a newly installed
+// custom resource, fetches it again.
+func (e *KubectlToolExecutor) lintManifest(params map[string]interface{}, cfg *config.ConfigData) (string, error) {
+	manifest, _ := params["manifest"].(string)
+	if strings.TrimSpace(manifest) == "" {
+		return "", fmt.Errorf("lint requires a manifest")
+	}
+
+	report := lintReport{Valid: true, Objects: []lintObject{}}
+	dec := yaml.NewDecoder(strings.NewReader(manifest))
+	for {
+		var obj map[string]interface{}
+		if err := dec.Decode(&obj); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return "", fmt.Errorf("invalid manifest: %w", err)
+		}
+		if obj == nil {
+			continue
+		}
+
+		apiVersion, _ := obj["apiVersion"].(string)
+		kind, _ := obj["kind"].(string)
+		result := lintObject{APIVersion: apiVersion, Kind: kind, Name: nestedString(obj, "metadata", "name"), Errors: []lintError{}}
+		switch {
+		case apiVersion == "":
+			result.Errors = append(result.Errors, lintError{Field: "apiVersion", Message: "required field is missing"})
+		case kind == "":
+			result.Errors = append(result.Errors, lintError{Field: "kind", Message: "required field is missing"})
+		default:
+			document, schema, err := e.lookupKindSchema(apiVersion, kind, cfg)
+			if err != nil {
+				return "", err
+			}
+			if schema == nil {
+				result.Errors = append(result.Errors, lintError{Field: "kind", Message: fmt.Sprintf("the cluster has no schema for kind %s in %s", kind, apiVersion)})
+			} else {
+				result.Errors = validateSchema(document, schema, obj, "", result.Errors)
+			}
+		}
+		if len(result.Errors) > 0 {
+			report.Valid = false
+		}
+		report.Objects = append(report.Objects, result)
+	}
+	if len(report.Objects) == 0 {
+		return "", fmt.Errorf("lint requires a manifest with at least one object")
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode lint report: %w", err)
+	}
+	return string(data), nil
+}
+
+// lookupKindSchema returns the schema of a kind and the document it is in, or a nil schema
+// when the cluster doesn't serve its group version or the kind isn't in it
+func (e *KubectlToolExecutor) lookupKindSchema(apiVersion, kind string, cfg *config.ConfigData) (*openAPIDocument, *openAPISchema, error) {
+	document, fetched, err := e.openAPISchemas(apiVersion, false, cfg)
+	if err == nil && !fetched && document.kindSchema(apiVersion, kind) == nil {
+		document, _, err = e.openAPISchemas(apiVersion, true, cfg)
+	}
+	if err != nil {
+		if strings.Contains(err.Error(), "NotFound") || strings.Contains(err.Error(), "could not find the requested resource") {
+			return nil, nil, nil
+		}
+		return nil, nil, err
+	}
+	return document, document.kindSchema(apiVersion, kind), nil
+}
+
+// resolve follows a schema's $ref, including the single-entry allOf OpenAPI v3 wraps
+// references in
+func (d *openAPIDocument) resolve(schema *openAPISchema) *openAPISchema {
+	for schema != nil {
+		switch {
+		case schema.Ref != "":
+			schema = d.Components.Schemas[strings.TrimPrefix(schema.Ref, "#/components/schemas/")]
+		case len(schema.AllOf) == 1 && schema.Type == "" && schema.Properties == nil:
+			schema = schema.AllOf[0]
+		default:
+			return schema
+		}
+	}
+	return nil
+}
+
+// validateSchema appends the problems of a value against its schema to errs
+func validateSchema(d *openAPIDocument, schema *openAPISchema, value interface{}, path string, errs []lintError) []lintError {
+	schema = d.resolve(schema)
+	if schema == nil || value == nil {
+		// A field set to null is left unset
+		return errs
+	}
+	fail := func(message string) []lintError {
+		field := path
+		if field == "" {
+			field = "."
+		}
+		return append(errs, lintError{Field: field, Message: message})
+	}
+
+	if schema.IntOrString {
+		if !matchesType("integer", value) && !matchesType("string", value) {
+			return fail("expected an integer or string, got " + valueType(value))
+		}
+		return errs
+	}
+	if schema.Type == "" {
+		// Kinds such as resource quantities list the types they accept
+		alternatives := append(append([]*openAPISchema{}, schema.OneOf...), schema.AnyOf...)
+		var types []string
+		for _, alternative := range alternatives {
+			if alternative = d.resolve(alternative); alternative != nil && alternative.Type != "" {
+				if matchesType(alternative.Type, value) {
+					return errs
+				}
+				types = append(types, alternative.Type)
+			}
+		}
+		if len(types) > 0 {
+			return fail("expected " + strings.Join(types, " or ") + ", got " + valueType(value))
+		}
+		if schema.Properties == nil && schema.AdditionalProperties == nil {
+			return errs
+		}
+	} else if !matchesType(schema.Type, value) {
+		return fail("expected " + schema.Type + ", got " + valueType(value))
+	}
+
+	if len(schema.Enum) > 0 {
+		for _, allowed := range schema.Enum {
+			if fmt.Sprint(allowed) == fmt.Sprint(value) {
+				return errs
+			}
+		}
+		options := make([]string, len(schema.Enum))
+		for i, allowed := range schema.Enum {
+			options[i] = fmt.Sprint(allowed)
+		}
+		return fail(fmt.Sprintf("value %v is not one of: %s", value, strings.Join(options, ", ")))
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for _, required := range schema.Required {
+			if _, ok := v[required]; !ok {
+				errs = append(errs, lintError{Field: joinFieldPath(path, required), Message: "required field is missing"})
+			}
+		}
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			child := joinFieldPath(path, key)
+			if property, ok := schema.Properties[key]; ok {
+				errs = validateSchema(d, property, v[key], child, errs)
+				continue
+			}
+			switch {
+			case schema.AdditionalProperties != nil && schema.AdditionalProperties.schema != nil:
+				errs = validateSchema(d, schema.AdditionalProperties.schema, v[key], child, errs)
+			case schema.AdditionalProperties != nil && schema.AdditionalProperties.allowed, schema.PreserveUnknown:
+			default:
+				errs = append(errs, lintError{Field: child, Message: "unknown field"})
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			errs = validateSchema(d, schema.Items, item, path+"["+strconv.Itoa(i)+"]", errs)
+		}
+	}
+	return errs
+}
+
+// joinFieldPath appends a field name to a dotted field path
+func joinFieldPath(path, field string) string {
+	if path == "" {
+		return field
+	}
+	return path + "." + field
+}
+
+// matchesType reports whether a decoded YAML value has an OpenAPI type
+func matchesType(schemaType string, value interface{}) bool {
+	switch schemaType {
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := value.([]interface{})
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "integer":
+		switch v := value.(type) {
+		case int, int64, uint64:
+			return true
+		case float64:
+			return v == float64(int64(v))
+		}
+		return false
+	case "number":
+		switch value.(type) {
+		case int, int64, uint64, float64:
+			return true
+		}
+		return false
+	}
+	return true
+}
+
+// valueType names the OpenAPI type of a decoded YAML value for error messages
+func valueType(value interface{}) string {
+	for _, schemaType := range []string{"object", "array", "string", "boolean", "integer", "number"} {
+		if matchesType(schemaType, value) {
+			return schemaType
+		}
+	}
+	return fmt.Sprintf("%T", value)
+}
@@ -0,0 +1,161 @@
+package kubectl
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// sampleAppsSchema is a trimmed OpenAPI v3 document for apps/v1
+const sampleAppsSchema = `{
+  "openapi": "3.0.0",
+  "components": {"schemas": {
+    "io.k8s.api.apps.v1.Deployment": {
+      "type": "object",
+      "properties": {
+        "apiVersion": {"type": "string"},
+        "kind": {"type": "string"},
+        "metadata": {"allOf": [{"$ref": "#/components/schemas/io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta"}], "default": {}},
+        "spec": {"allOf": [{"$ref": "#/components/schemas/io.k8s.api.apps.v1.DeploymentSpec"}], "default": {}}
+      },
+      "x-kubernetes-group-version-kind": [{"group": "apps", "kind": "Deployment", "version": "v1"}]
+    },
+    "io.k8s.api.apps.v1.DeploymentSpec": {
+      "type": "object",
+      "required": ["selector", "template"],
+      "properties": {
+        "replicas": {"type": "integer"},
+        "selector": {"type": "object", "additionalProperties": true},
+        "template": {"allOf": [{"$ref": "#/components/schemas/io.k8s.api.core.v1.PodTemplateSpec"}], "default": {}}
+      }
+    },
+    "io.k8s.api.core.v1.PodTemplateSpec": {
+      "type": "object",
+      "properties": {
+        "metadata": {"allOf": [{"$ref": "#/components/schemas/io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta"}], "default": {}},
+        "spec": {"type": "object", "properties": {
+          "containers": {"type": "array", "items": {"allOf": [{"$ref": "#/components/schemas/io.k8s.api.core.v1.Container"}], "default": {}}}
+        }}
+      }
+    },
+    "io.k8s.api.core.v1.Container": {
+      "type": "object",
+      "required": ["name"],
+      "properties": {
+        "name": {"type": "string"},
+        "image": {"type": "string"},
+        "imagePullPolicy": {"type": "string", "enum": ["Always", "IfNotPresent", "Never"]},
+        "resources": {"type": "object", "properties": {
+          "limits": {"type": "object", "additionalProperties": {"allOf": [{"$ref": "#/components/schemas/io.k8s.apimachinery.pkg.api.resource.Quantity"}]}}
+        }}
+      }
+    },
+    "io.k8s.apimachinery.pkg.api.resource.Quantity": {"oneOf": [{"type": "string"}, {"type": "number"}]},
+    "io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta": {
+      "type": "object",
+      "properties": {
+        "name": {"type": "string"},
+        "namespace": {"type": "string"},
+        "labels": {"type": "object", "additionalProperties": {"type": "string", "default": ""}}
+      }
+    }
+  }}
+}`
+
+const lintManifestInput = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  labels:
+    app: web
+spec:
+  replicas: "3"
+  selector:
+    matchLabels:
+      app: web
+  template:
+    metadata:
+      labels:
+        app: web
+    spec:
+      containers:
+      - name: web
+        image: nginx
+        imagePullPolcy: Always
+        resources:
+          limits:
+            cpu: 1
+            memory: 128Mi
+      - image: sidecar
+        imagePullPolicy: Sometimes
+`
+
+func TestKubectlToolExecutor_Lint(t *testing.T) {
+	var commands []string
+	worker := newTestWorker(t, func(command string) map[string]interface{} {
+		commands = append(commands, command)
+		if command != "kubectl get --raw /openapi/v3/apis/apps/v1" {
+			return map[string]interface{}{"error": "unexpected command: " + command}
+		}
+		return map[string]interface{}{"stdout": sampleAppsSchema}
+	})
+	executor := NewKubectlToolExecutor(worker)
+
+	lint := func(manifest string) lintReport {
+		t.Helper()
+		result, err := executor.Execute(map[string]interface{}{
+			"_tool_name": "kubectl_config",
+			"operation":  "lint",
+			"resource":   "",
+			"args":       "",
+			"manifest":   manifest,
+		}, newTestConfig("readonly"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v (commands: %v)", err, commands)
+		}
+		var report lintReport
+		if err := json.Unmarshal([]byte(result), &report); err != nil {
+			t.Fatalf("expected JSON, got %q: %v", result, err)
+		}
+		return report
+	}
+
+	report := lint(lintManifestInput)
+	want := []lintError{
+		{Field: "spec.replicas", Message: "expected integer, got string"},
+		{Field: "spec.template.spec.containers[0].imagePullPolcy", Message: "unknown field"},
+		{Field: "spec.template.spec.containers[1].name", Message: "required field is missing"},
+		{Field: "spec.template.spec.containers[1].imagePullPolicy", Message: "value Sometimes is not one of: Always, IfNotPresent, Never"},
+	}
+	if report.Valid || len(report.Objects) != 1 || report.Objects[0].Name != "web" {
+		t.Fatalf("expected one invalid object, got %+v", report)
+	}
+	if !reflect.DeepEqual(report.Objects[0].Errors, want) {
+		t.Errorf("expected errors %+v, got %+v", want, report.Objects[0].Errors)
+	}
+
+	// The schema is cached, so a valid manifest is checked without fetching it again
+	report = lint(`apiVersion: apps/v1
+kind: Deployment
+metadata: {name: api}
+spec:
+  replicas: 2
+  selector: {matchLabels: {app: api}}
+  template: {spec: {containers: [{name: api, image: api}]}}
+`)
+	if !report.Valid || len(report.Objects[0].Errors) != 0 {
+		t.Errorf("expected a valid manifest, got %+v", report)
+	}
+	if len(commands) != 1 {
+		t.Errorf("expected the schema to be fetched once, got %v", commands)
+	}
+
+	// A kind missing from the cached schema fetches it again before it is reported
+	report = lint("apiVersion: apps/v1\nkind: Rollout\nmetadata: {name: web}\n")
+	if report.Valid || len(report.Objects[0].Errors) != 1 || report.Objects[0].Errors[0].Field != "kind" {
+		t.Errorf("expected an unknown kind error, got %+v", report)
+	}
+	if len(commands) != 2 {
+		t.Errorf("expected the schema to be fetched again for an unknown kind, got %v", commands)
+	}
+}
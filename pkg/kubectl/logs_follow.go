@@ -0,0 +1,160 @@
+package kubectl
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Azure/mcp-kubernetes/pkg/config"
+	"github.com/Azure/mcp-kubernetes/pkg/security"
+	"github.com/google/shlex"
+)
+
+const (
+	// defaultFollowDuration is how long logs are followed when no max_duration is given
+	defaultFollowDuration = 60 * time.Second
+	// defaultFollowMaxBytes is how much log output is collected when no max_bytes is given
+	defaultFollowMaxBytes = 256 * 1024
+	// maxFollowMaxBytes bounds max_bytes
+	maxFollowMaxBytes = 4 * 1024 * 1024
+)
+
+// logFollower collects followed log output up to a byte cap
+type logFollower struct {
+	max    int
+	output strings.Builder
+}
+
+// add appends a chunk of log output, cut at the byte cap, and reports the part that was kept
+// and whether the cap has been reached
+func (f *logFollower) add(chunk string) (string, bool) {
+	if remaining := f.max - f.output.Len(); len(chunk) > remaining {
+		chunk = chunk[:remaining]
+	}
+	f.output.WriteString(chunk)
+	return chunk, f.output.Len() >= f.max
+}
+
+// isLogsFollow reports whether a logs call asks to follow, through the follow parameter or a
+// -f/--follow flag in args
+func isLogsFollow(args string, params map[string]interface{}) bool {
+	if follow, _ := params["follow"].(bool); follow {
+		return true
+	}
+	// -f means --follow for logs, so args aren't read with the shared flag table here
+	parts, err := shlex.Split(args)
+	if err != nil {
+		return false
+	}
+	for _, part := range parts {
+		if part == "--" {
+			break
+		}
+		if part == "-f" || part == "--follow" || part == "--follow=true" {
+			return true
+		}
+	}
+	return false
+}
+
+// followLogs streams `logs --follow` through the worker until the log stream ends, max_bytes
+// of output have arrived, max_duration passes or the call's timeout passes, whichever comes
+// first. The stream is closed on return, releasing its slot, and the agent is told to stop
+// the command after the same duration. The output is returned with a note on why it stopped.
+func (e *KubectlToolExecutor) followLogs(toolName, operation, resource, args string, params map[string]interface{}, cfg *config.ConfigData, emit func(chunk string)) (string, error) {
+	duration := defaultFollowDuration
+	if seconds, ok, err := positiveIntParam("max_duration", params["max_duration"]); err != nil {
+		return "", err
+	} else if ok {
+		duration = time.Duration(seconds) * time.Second
+	}
+	if duration > maxWatchDuration {
+		return "", fmt.Errorf("max_duration must be at most %d seconds", int(maxWatchDuration.Seconds()))
+	}
+
+	maxBytes := int64(defaultFollowMaxBytes)
+	if n, ok, err := positiveIntParam("max_bytes", params["max_bytes"]); err != nil {
+		return "", err
+	} else if ok {
+		maxBytes = n
+	}
+	if maxBytes > maxFollowMaxBytes {
+		return "", fmt.Errorf("max_bytes must be at most %d", maxFollowMaxBytes)
+	}
+
+	worker := e.executor.pulsarWorker
+	if worker == nil || worker.cfg == nil {
+		return "", fmt.Errorf("remote worker is not configured")
+	}
+
+	// The call's timeout bounds the follow as well
+	requested, _, err := positiveIntParam("timeout", params["timeout"])
+	if err != nil {
+		return "", err
+	}
+	timeout := cfg.EffectiveTimeout("logs", int(requested))
+	if timeout <= 0 {
+		timeout = cfg.ClampTimeout(worker.cfg.Timeout)
+	}
+	stopReason := fmt.Sprintf("reached max_duration (%ds)", int(duration.Seconds()))
+	if limit := time.Duration(timeout) * time.Second; limit < duration {
+		duration = limit
+		stopReason = fmt.Sprintf("reached the timeout (%ds)", timeout)
+	}
+
+	command, err := e.assembleCommand(toolName, operation, resource, args, params, cfg)
+	if err != nil {
+		return "", err
+	}
+	if !isLogsFollow(command, nil) {
+		command = insertFlags(command, []string{"--follow"})
+	}
+
+	if err := e.checkAccessLevel(command, cfg); err != nil {
+		return "", err
+	}
+	validator := security.NewValidator(cfg.SecurityConfig)
+	if err := validator.ValidateCommand(command, security.CommandTypeKubectl); err != nil {
+		return "", err
+	}
+
+	stream, err := worker.Stream("kubectl "+command, duration)
+	if err != nil {
+		return "", err
+	}
+	defer stream.Close()
+
+	follower := &logFollower{max: int(maxBytes)}
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+collect:
+	for {
+		select {
+		case chunk := <-stream.Chunks():
+			if chunk.Err != nil {
+				return "", chunk.Err
+			}
+			kept, full := follower.add(chunk.Stdout)
+			if kept != "" && emit != nil {
+				emit(kept)
+			}
+			if full {
+				stopReason = fmt.Sprintf("reached max_bytes (%d bytes)", maxBytes)
+				break collect
+			}
+			if chunk.Done {
+				stopReason = "the log stream ended"
+				break collect
+			}
+		case <-timer.C:
+			break collect
+		}
+	}
+
+	output := follower.output.String()
+	if output != "" && !strings.HasSuffix(output, "\n") {
+		output += "\n"
+	}
+	return output + "[follow stopped: " + stopReason + "]\n", nil
+}
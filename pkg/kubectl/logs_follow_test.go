@@ -0,0 +1,111 @@
+package kubectl
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLogFollower_MaxBytes(t *testing.T) {
+	follower := &logFollower{max: 10}
+	if kept, full := follower.add("line 1\n"); kept != "line 1\n" || full {
+		t.Fatalf("add() = %q, %v; want the whole chunk below the cap", kept, full)
+	}
+	if kept, full := follower.add("line 2\n"); kept != "lin" || !full {
+		t.Fatalf("add() = %q, %v; want the chunk cut at the cap", kept, full)
+	}
+	if got := follower.output.String(); got != "line 1\nlin" {
+		t.Errorf("output = %q", got)
+	}
+}
+
+func TestKubectlToolExecutor_LogsFollow(t *testing.T) {
+	logLines := []map[string]interface{}{
+		{"stdout": "line 1\n", "done": false},
+		{"stdout": "line 2\n", "done": false},
+	}
+	tests := []struct {
+		name   string
+		params map[string]interface{}
+		done   bool
+		output string
+	}{
+		{
+			name:   "stream end",
+			params: map[string]interface{}{"follow": true},
+			done:   true,
+			output: "line 1\nline 2\n[follow stopped: the log stream ended]\n",
+		},
+		{
+			name:   "max bytes",
+			params: map[string]interface{}{"follow": true, "max_bytes": float64(10)},
+			output: "line 1\nlin\n[follow stopped: reached max_bytes (10 bytes)]\n",
+		},
+		{
+			name:   "max duration",
+			params: map[string]interface{}{"follow": true, "max_duration": float64(1)},
+			output: "line 1\nline 2\n[follow stopped: reached max_duration (1s)]\n",
+		},
+		{
+			name:   "timeout",
+			params: map[string]interface{}{"follow": true, "max_duration": float64(60), "timeout": float64(1)},
+			output: "line 1\nline 2\n[follow stopped: reached the timeout (1s)]\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var command string
+			worker := newStreamTestWorker(t, func(cmd string) []map[string]interface{} {
+				command = cmd
+				chunks := append([]map[string]interface{}{}, logLines...)
+				if tt.done {
+					chunks = append(chunks, map[string]interface{}{"stdout": "", "done": true})
+				}
+				return chunks
+			})
+			executor := NewKubectlToolExecutor(worker)
+
+			params := map[string]interface{}{
+				"_tool_name": "kubectl_diagnostics",
+				"operation":  "logs",
+				"resource":   "",
+				"args":       "web-0 -n shop",
+			}
+			for key, value := range tt.params {
+				params[key] = value
+			}
+			var streamed strings.Builder
+			output, err := executor.ExecuteStreaming(params, newTestConfig("readonly"), func(chunk string) {
+				streamed.WriteString(chunk)
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if output != tt.output {
+				t.Errorf("output = %q, want %q", output, tt.output)
+			}
+			if !strings.HasPrefix(tt.output, streamed.String()) {
+				t.Errorf("streamed %q, which isn't the start of the output", streamed.String())
+			}
+			worker.pending.Range(func(id, _ any) bool {
+				t.Errorf("request %v is still pending after the follow stopped", id)
+				return true
+			})
+			if !strings.Contains(command, "--follow") {
+				t.Errorf("command %q should follow the logs", command)
+			}
+		})
+	}
+}
+
+func TestIsLogsFollow(t *testing.T) {
+	for args, want := range map[string]bool{
+		"web-0 -f":                  true,
+		"web-0 --follow --tail=10":  true,
+		"web-0 --tail=10":           false,
+		"web-0 -- tail -f /var/log": false,
+	} {
+		if got := isLogsFollow(args, nil); got != want {
+			t.Errorf("isLogsFollow(%q) = %v, want %v", args, got, want)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package kubectl
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// managedFieldsOutputs are the get output formats that print whole objects, managedFields included
+var managedFieldsOutputs = map[string]bool{
+	"json": true,
+	"yaml": true,
+}
+
+// applyManagedFields sets --show-managed-fields on get commands printing JSON or YAML, to false
+// unless the caller asked for the field with show_managed_fields. Server-side apply fills
+// managedFields with bookkeeping that is rarely wanted and costs many tokens. A
+// --show-managed-fields already in args is left alone.
+func applyManagedFields(command string, params map[string]interface{}) (string, error) {
+	cmdline, err := parseCommandLine(command)
+	if err != nil {
+		return "", err
+	}
+	show, requested := params["show_managed_fields"].(bool)
+	if cmdline.verb() != "get" {
+		if requested {
+			return "", fmt.Errorf("show_managed_fields is only supported for get, not %s", cmdline.verb())
+		}
+		return command, nil
+	}
+
+	output, _ := cmdline.flag("--output")
+	if !managedFieldsOutputs[output] {
+		if requested && show {
+			return "", fmt.Errorf("show_managed_fields requires -o json or -o yaml")
+		}
+		return command, nil
+	}
+	if cmdline.hasFlag("--show-managed-fields") {
+		return command, nil
+	}
+	return insertFlags(command, []string{"--show-managed-fields=" + strconv.FormatBool(show)}), nil
+}
@@ -0,0 +1,88 @@
+package kubectl
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestKubectlToolExecutor_ManagedFields(t *testing.T) {
+	const withoutManagedFields = `{"metadata": {"name": "web"}}`
+	const withManagedFields = `{"metadata": {"name": "web", "managedFields": [{"manager": "kubectl"}]}}`
+	worker := newTestWorker(t, func(command string) map[string]interface{} {
+		switch command {
+		case "kubectl get pod web -n default -o json --show-managed-fields=false":
+			return map[string]interface{}{"stdout": withoutManagedFields}
+		case "kubectl get pod web -n default -o json --show-managed-fields=true",
+			"kubectl get pod web -n default -o json --show-managed-fields":
+			return map[string]interface{}{"stdout": withManagedFields}
+		}
+		return map[string]interface{}{"error": "unexpected command: " + command}
+	})
+	executor := NewKubectlToolExecutor(worker)
+	cfg := newTestConfig("readonly")
+
+	tests := []struct {
+		name     string
+		args     string
+		show     interface{}
+		expected string
+	}{
+		{name: "excluded by default", args: "web -n default -o json", expected: withoutManagedFields},
+		{name: "requested", args: "web -n default -o json", show: true, expected: withManagedFields},
+		{name: "flag in args", args: "web -n default -o json --show-managed-fields", expected: withManagedFields},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := map[string]interface{}{
+				"_tool_name": "kubectl_resources",
+				"operation":  "get",
+				"resource":   "pod",
+				"args":       tt.args,
+			}
+			if tt.show != nil {
+				params["show_managed_fields"] = tt.show
+			}
+			output, err := executor.Execute(params, cfg)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if output != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, output)
+			}
+		})
+	}
+}
+
+func TestApplyManagedFields(t *testing.T) {
+	tests := []struct {
+		name     string
+		command  string
+		params   map[string]interface{}
+		expected string
+		err      string
+	}{
+		{name: "yaml", command: "get deploy web -o yaml", expected: "get deploy web -o yaml --show-managed-fields=false"},
+		{name: "table output", command: "get pods", expected: "get pods"},
+		{name: "other verbs", command: "describe pod web", expected: "describe pod web"},
+		{name: "requested for table", command: "get pods", params: map[string]interface{}{"show_managed_fields": true}, err: "requires -o json or -o yaml"},
+		{name: "requested for describe", command: "describe pod web", params: map[string]interface{}{"show_managed_fields": true}, err: "only supported for get"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := tt.params
+			if params == nil {
+				params = map[string]interface{}{}
+			}
+			got, err := applyManagedFields(tt.command, params)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Errorf("Expected error containing %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil || got != tt.expected {
+				t.Errorf("Expected %q, got %q (%v)", tt.expected, got, err)
+			}
+		})
+	}
+}
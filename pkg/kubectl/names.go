@@ -0,0 +1,79 @@
+package kubectl
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// applyNamesOnly makes a get command list names with -o name, unless args already ask for
+// -o name or -o json, whose item names are extracted after the command runs
+func applyNamesOnly(command string) (string, error) {
+	cmdline, err := parseCommandLine(command)
+	if err != nil {
+		return "", err
+	}
+	if cmdline.verb() != "get" {
+		return "", fmt.Errorf("names_only is only supported for get, not %s", cmdline.verb())
+	}
+	format, ok := cmdline.flag("--output")
+	switch {
+	case !ok:
+		return insertFlags(command, []string{"--output=name"}), nil
+	case format == "name" || format == "json":
+		return command, nil
+	default:
+		return "", fmt.Errorf("names_only requires -o name or -o json, not -o %s", format)
+	}
+}
+
+// extractNames reduces the output of a names_only get to one name per line. JSON output
+// contributes the metadata.name of each item; -o name output loses its type prefix when a
+// single resource type was listed, and keeps it to tell types apart otherwise.
+func extractNames(command, output string) (string, error) {
+	cmdline, err := parseCommandLine(command)
+	if err != nil {
+		return "", err
+	}
+
+	if format, _ := cmdline.flag("--output"); format == "json" {
+		var obj map[string]interface{}
+		if err := json.Unmarshal([]byte(output), &obj); err != nil {
+			return "", fmt.Errorf("failed to extract names: %w", err)
+		}
+		items, isList := obj["items"].([]interface{})
+		if !isList {
+			items = []interface{}{obj}
+		}
+		var names []string
+		for _, item := range items {
+			itemObj, _ := item.(map[string]interface{})
+			if _, name := objectNamespacedName(itemObj); name != "" {
+				names = append(names, name)
+			}
+		}
+		return joinNames(names), nil
+	}
+
+	keepType := len(cmdline.args()) == 0 || strings.Contains(cmdline.args()[0], ",")
+	var names []string
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if _, name, ok := strings.Cut(line, "/"); ok && !keepType {
+			line = name
+		}
+		names = append(names, line)
+	}
+	return joinNames(names), nil
+}
+
+// joinNames returns names one per line, or "No resources found" when there are none
+func joinNames(names []string) string {
+	if len(names) == 0 {
+		return "No resources found\n"
+	}
+	return strings.Join(names, "\n") + "\n"
+}
@@ -0,0 +1,62 @@
+package kubectl
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestKubectlToolExecutor_NamesOnly(t *testing.T) {
+	var commands []string
+	worker := newTestWorker(t, func(command string) map[string]interface{} {
+		commands = append(commands, command)
+		if strings.Contains(command, "--output=name") {
+			return map[string]interface{}{"stdout": "pod/web-1\npod/web-2\n"}
+		}
+		return map[string]interface{}{"stdout": `{"kind": "List", "items": [
+			{"kind": "Pod", "metadata": {"name": "web-1", "namespace": "shop"}},
+			{"kind": "Pod", "metadata": {"name": "web-2", "namespace": "shop"}}
+		]}`}
+	})
+	executor := NewKubectlToolExecutor(worker)
+	cfg := newTestConfig("readonly")
+
+	for _, args := range []string{"-n shop -l app=web", "-n shop -l app=web -o json"} {
+		output, err := executor.Execute(map[string]interface{}{
+			"_tool_name": "kubectl_resources",
+			"operation":  "get",
+			"resource":   "pods",
+			"args":       args,
+			"names_only": true,
+		}, cfg)
+		if err != nil {
+			t.Fatalf("args %q: unexpected error: %v", args, err)
+		}
+		if output != "web-1\nweb-2\n" {
+			t.Errorf("args %q: output = %q, want only the names", args, output)
+		}
+	}
+
+	// The namespace and selector are passed through
+	for _, command := range commands {
+		if !strings.Contains(command, "-n shop") || !strings.Contains(command, "-l app=web") {
+			t.Errorf("command %q lost the namespace or selector", command)
+		}
+	}
+	if !strings.Contains(commands[0], "--output=name") {
+		t.Errorf("command %q should list names with -o name", commands[0])
+	}
+
+	// Listing several types keeps the type prefix
+	names, err := extractNames("get pods,svc --output=name", "pod/web-1\nservice/web\n")
+	if err != nil || names != "pod/web-1\nservice/web\n" {
+		t.Errorf("extractNames() = %q, %v", names, err)
+	}
+
+	// Other verbs and output formats are refused
+	if _, err := applyNamesOnly("describe pods"); err == nil {
+		t.Error("expected names_only to be refused for describe")
+	}
+	if _, err := applyNamesOnly("get pods -o yaml"); err == nil {
+		t.Error("expected names_only to be refused with -o yaml")
+	}
+}
@@ -0,0 +1,240 @@
+package kubectl
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Azure/mcp-kubernetes/pkg/config"
+)
+
+// Bounds on a namespace-diff result
+const (
+	namespaceDiffMaxObjects = 50
+	namespaceDiffMaxChanges = 20
+)
+
+// redactedValue stands in for secret values in a namespace-diff
+const redactedValue = "<redacted>"
+
+// namespaceDiff compares the objects of one resource type in two namespaces
+type namespaceDiff struct {
+	Resource   string   `json:"resource"`
+	Namespaces []string `json:"namespaces"`
+	// OnlyIn maps each namespace to the names found only there
+	OnlyIn map[string][]string `json:"only_in"`
+	// Different are the objects in both namespaces whose compared fields differ; Old is the
+	// value in the first namespace and New the value in the second
+	Different []objectDiff   `json:"different"`
+	Same      []string       `json:"same"`
+	Truncated map[string]int `json:"truncated,omitempty"` // entries omitted per list
+}
+
+// objectDiff is the field changes of one object between the two namespaces
+type objectDiff struct {
+	Name    string        `json:"name"`
+	Changes []fieldChange `json:"changes"`
+}
+
+// namespaceDiff lists a resource type in the two namespaces given in args, optionally narrowed
+// with -l, and reports the names found in only one of them and the spec differences of the
+// objects in both, apart from cluster-assigned service IPs. Objects without a spec, such as ConfigMaps, are compared by data and
+// binaryData; secret values are compared but never returned. Both lists go through the same
+// access and namespace checks as a direct get.
+func (e *KubectlToolExecutor) namespaceDiff(resource, args string, cfg *config.ConfigData) (string, error) {
+	cmdline, err := parseCommandLine(args)
+	if err != nil {
+		return "", err
+	}
+	if resource == "" || len(cmdline.positionals) != 2 {
+		return "", fmt.Errorf("namespace-diff requires a resource type and two namespaces, e.g. resource='deployments', args='staging prod'")
+	}
+	if !orphanResourceType.MatchString(resource) {
+		return "", fmt.Errorf("namespace-diff requires a single resource type, got '%s'", resource)
+	}
+	if cmdline.hasFlag("--namespace") || cmdline.hasFlag("--all-namespaces") || cmdline.hasFlag("--output") {
+		return "", fmt.Errorf("namespace-diff takes the namespaces as arguments and does not accept -n, -A or -o")
+	}
+	first, second := cmdline.positionals[0], cmdline.positionals[1]
+	for _, namespace := range []string{first, second} {
+		if !namespaceName.MatchString(namespace) {
+			return "", fmt.Errorf("invalid namespace '%s'", namespace)
+		}
+	}
+	if first == second {
+		return "", fmt.Errorf("namespace-diff requires two different namespaces")
+	}
+	described, known := cfg.SecurityConfig.Resources().Lookup(resource)
+	if known && !described.Namespaced {
+		return "", fmt.Errorf("namespace-diff requires a namespaced resource type, not '%s'", resource)
+	}
+	secrets := known && described.Name == "secrets" && described.Group == ""
+
+	selector, _ := cmdline.flag("--selector")
+	list := func(namespace string) (map[string]map[string]interface{}, error) {
+		command := "get " + resource + " -n " + namespace + " -o json"
+		if selector != "" {
+			command += " -l " + shellQuote(selector)
+		}
+		items, err := e.listObjects(command, cfg)
+		if err != nil {
+			return nil, err
+		}
+		objects := make(map[string]map[string]interface{}, len(items))
+		for _, item := range items {
+			_, name := objectNamespacedName(item)
+			objects[name] = item
+		}
+		return objects, nil
+	}
+	firstObjects, err := list(first)
+	if err != nil {
+		return "", err
+	}
+	secondObjects, err := list(second)
+	if err != nil {
+		return "", err
+	}
+
+	result := &namespaceDiff{
+		Resource:   resource,
+		Namespaces: []string{first, second},
+		OnlyIn:     map[string][]string{first: {}, second: {}},
+		Different:  []objectDiff{},
+		Same:       []string{},
+		Truncated:  map[string]int{},
+	}
+	for _, name := range objectNames(firstObjects) {
+		other, ok := secondObjects[name]
+		if !ok {
+			result.OnlyIn[first] = append(result.OnlyIn[first], name)
+			continue
+		}
+		changes := compareFields(comparedFields(firstObjects[name]), comparedFields(other), secrets)
+		if len(changes) == 0 {
+			result.Same = append(result.Same, name)
+			continue
+		}
+		if len(result.Different) == namespaceDiffMaxObjects {
+			result.Truncated["different"]++
+			continue
+		}
+		if len(changes) > namespaceDiffMaxChanges {
+			result.Truncated["changes of "+name] = len(changes) - namespaceDiffMaxChanges
+			changes = changes[:namespaceDiffMaxChanges]
+		}
+		result.Different = append(result.Different, objectDiff{Name: name, Changes: changes})
+	}
+	for _, name := range objectNames(secondObjects) {
+		if _, ok := firstObjects[name]; !ok {
+			result.OnlyIn[second] = append(result.OnlyIn[second], name)
+		}
+	}
+	for _, key := range []string{first, second} {
+		if omitted := len(result.OnlyIn[key]) - namespaceDiffMaxObjects; omitted > 0 {
+			result.OnlyIn[key] = result.OnlyIn[key][:namespaceDiffMaxObjects]
+			result.Truncated["only_in "+key] = omitted
+		}
+	}
+	if omitted := len(result.Same) - namespaceDiffMaxObjects; omitted > 0 {
+		result.Same = result.Same[:namespaceDiffMaxObjects]
+		result.Truncated["same"] = omitted
+	}
+	if len(result.Truncated) == 0 {
+		result.Truncated = nil
+	}
+
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode namespace diff: %w", err)
+	}
+	return string(data), nil
+}
+
+// assignedField reports whether a compared field is set by the cluster rather than the
+// manifest, and so always differs between namespaces
+func assignedField(path string) bool {
+	return path == "spec.clusterIP" || strings.HasPrefix(path, "spec.clusterIPs[")
+}
+
+// objectNames returns the names of a set of objects in order
+func objectNames(objects map[string]map[string]interface{}) []string {
+	names := make([]string, 0, len(objects))
+	for name := range objects {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// comparedFields flattens the fields a namespace-diff compares into leaf paths: spec, or for
+// objects without one, data and binaryData
+func comparedFields(obj map[string]interface{}) map[string]string {
+	fields := map[string]string{}
+	if spec, ok := obj["spec"]; ok {
+		flattenFields("spec", spec, fields)
+		return fields
+	}
+	for _, key := range []string{"data", "binaryData"} {
+		if value, ok := obj[key]; ok {
+			flattenFields(key, value, fields)
+		}
+	}
+	return fields
+}
+
+// flattenFields adds the leaf values below path to fields, keyed by dotted path with list
+// indexes, e.g. spec.template.spec.containers[0].image
+func flattenFields(path string, value interface{}, fields map[string]string) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			flattenFields(path+"."+key, child, fields)
+		}
+	case []interface{}:
+		for i, child := range v {
+			flattenFields(path+"["+strconv.Itoa(i)+"]", child, fields)
+		}
+	case string:
+		fields[path] = v
+	default:
+		data, _ := json.Marshal(v)
+		fields[path] = string(data)
+	}
+}
+
+// compareFields returns the leaf fields that differ between two objects, in path order, leaving
+// out fields the cluster assigns. With redact, values are replaced by a placeholder.
+func compareFields(first, second map[string]string, redact bool) []fieldChange {
+	paths := map[string]bool{}
+	for path := range first {
+		paths[path] = true
+	}
+	for path := range second {
+		paths[path] = true
+	}
+
+	var changes []fieldChange
+	for path := range paths {
+		oldValue, inOld := first[path]
+		newValue, inNew := second[path]
+		if inOld && inNew && oldValue == newValue || assignedField(path) {
+			continue
+		}
+		if redact {
+			oldValue, newValue = redactedValue, redactedValue
+		}
+		change := fieldChange{Path: path}
+		if inOld {
+			change.Old = &oldValue
+		}
+		if inNew {
+			change.New = &newValue
+		}
+		changes = append(changes, change)
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes
+}
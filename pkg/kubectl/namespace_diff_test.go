@@ -0,0 +1,121 @@
+package kubectl
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestKubectlToolExecutor_NamespaceDiff(t *testing.T) {
+	listings := map[string]string{
+		"kubectl get deployments -n staging -o json": `{"items": [
+  {"metadata": {"name": "web", "namespace": "staging"}, "spec": {"replicas": 1, "template": {"spec": {"containers": [{"name": "web", "image": "web:1.5"}]}}}},
+  {"metadata": {"name": "api", "namespace": "staging"}, "spec": {"replicas": 2}},
+  {"metadata": {"name": "canary", "namespace": "staging"}, "spec": {"replicas": 1}}
+]}`,
+		"kubectl get deployments -n prod -o json": `{"items": [
+  {"metadata": {"name": "web", "namespace": "prod"}, "spec": {"replicas": 3, "template": {"spec": {"containers": [{"name": "web", "image": "web:1.4"}]}}}},
+  {"metadata": {"name": "api", "namespace": "prod"}, "spec": {"replicas": 2}},
+  {"metadata": {"name": "worker", "namespace": "prod"}, "spec": {"replicas": 1, "paused": true}}
+]}`,
+	}
+	worker := newTestWorker(t, func(command string) map[string]interface{} {
+		output, ok := listings[command]
+		if !ok {
+			return map[string]interface{}{"error": "unexpected command: " + command}
+		}
+		return map[string]interface{}{"stdout": output}
+	})
+	executor := NewKubectlToolExecutor(worker)
+
+	result, err := executor.Execute(map[string]interface{}{
+		"_tool_name": "kubectl_config",
+		"operation":  "namespace-diff",
+		"resource":   "deployments",
+		"args":       "staging prod",
+	}, newTestConfig("readonly"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var got namespaceDiff
+	if err := json.Unmarshal([]byte(result), &got); err != nil {
+		t.Fatalf("expected JSON, got %q: %v", result, err)
+	}
+	if strings.Join(got.OnlyIn["staging"], ",") != "canary" || strings.Join(got.OnlyIn["prod"], ",") != "worker" {
+		t.Errorf("expected canary only in staging and worker only in prod, got %v", got.OnlyIn)
+	}
+	if strings.Join(got.Same, ",") != "api" {
+		t.Errorf("expected api to be the same, got %v", got.Same)
+	}
+	if len(got.Different) != 1 || got.Different[0].Name != "web" {
+		t.Fatalf("expected web to differ, got %+v", got.Different)
+	}
+	changes := map[string]string{}
+	for _, change := range got.Different[0].Changes {
+		changes[change.Path] = *change.Old + " -> " + *change.New
+	}
+	want := map[string]string{
+		"spec.replicas":                          "1 -> 3",
+		"spec.template.spec.containers[0].image": "web:1.5 -> web:1.4",
+	}
+	if len(changes) != len(want) {
+		t.Errorf("expected changes %v, got %v", want, changes)
+	}
+	for path, change := range want {
+		if changes[path] != change {
+			t.Errorf("expected %s to change %s, got %q", path, change, changes[path])
+		}
+	}
+}
+
+func TestKubectlToolExecutor_NamespaceDiffPolicy(t *testing.T) {
+	worker := newTestWorker(t, func(command string) map[string]interface{} {
+		if command == "kubectl get configmaps -n staging -o json" {
+			return map[string]interface{}{"stdout": `{"items": []}`}
+		}
+		t.Errorf("unexpected command: %s", command)
+		return map[string]interface{}{"error": "unexpected command"}
+	})
+	executor := NewKubectlToolExecutor(worker)
+	cfg := newTestConfig("readonly")
+	cfg.SecurityConfig.SetAllowedNamespaces("staging")
+
+	_, err := executor.Execute(map[string]interface{}{
+		"_tool_name": "kubectl_config",
+		"operation":  "namespace-diff",
+		"resource":   "configmaps",
+		"args":       "staging prod",
+	}, cfg)
+	if err == nil || !strings.Contains(err.Error(), "prod") {
+		t.Errorf("expected the disallowed namespace to be refused, got %v", err)
+	}
+}
+
+func TestKubectlToolExecutor_NamespaceDiffResourceType(t *testing.T) {
+	worker := newTestWorker(t, func(command string) map[string]interface{} {
+		t.Errorf("unexpected command: %s", command)
+		return map[string]interface{}{"error": "unexpected command"}
+	})
+	executor := NewKubectlToolExecutor(worker)
+
+	// The resource type goes into the command as is, so one carrying flags is refused
+	for _, resource := range []string{"configmaps --as=admin", "configmaps,secrets"} {
+		_, err := executor.Execute(map[string]interface{}{
+			"_tool_name": "kubectl_config",
+			"operation":  "namespace-diff",
+			"resource":   resource,
+			"args":       "staging prod",
+		}, newTestConfig("readonly"))
+		if err == nil || !strings.Contains(err.Error(), "single resource type") {
+			t.Errorf("expected resource %q to be refused, got %v", resource, err)
+		}
+	}
+}
+
+func TestCompareFieldsRedactsSecrets(t *testing.T) {
+	changes := compareFields(map[string]string{"data.password": "aHVudGVyMg=="}, map[string]string{"data.password": "czNjcmV0"}, true)
+	if len(changes) != 1 || *changes[0].Old != redactedValue || *changes[0].New != redactedValue {
+		t.Errorf("expected one redacted change, got %+v", changes)
+	}
+}
@@ -0,0 +1,179 @@
+package kubectl
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Azure/mcp-kubernetes/pkg/config"
+	"gopkg.in/yaml.v3"
+)
+
+// listsNamespaces reports whether a get command lists namespaces that --filter-namespace-list
+// must filter: the option is on, namespaces are restricted and the resource type is namespaces
+func listsNamespaces(command string, cfg *config.ConfigData) bool {
+	if !cfg.FilterNamespaceList || !cfg.SecurityConfig.HasNamespaceRestrictions() {
+		return false
+	}
+	cmdline, err := parseCommandLine(command)
+	if err != nil || cmdline.verb() != "get" || len(cmdline.args()) == 0 {
+		return false
+	}
+	reference, _, _ := strings.Cut(cmdline.args()[0], "/")
+	for _, resourceType := range strings.Split(reference, ",") {
+		if resource, ok := cfg.SecurityConfig.Resources().Lookup(resourceType); ok && resource.Name == "namespaces" {
+			return true
+		}
+	}
+	return false
+}
+
+// filterNamespaceList removes namespaces outside --allow-namespaces from the output of a get
+// namespaces command. JSON and YAML lists lose the disallowed items, a single disallowed
+// namespace becomes "No resources found", and table and name output lose the disallowed
+// rows. Output that can't be filtered reliably is refused rather than returned unfiltered.
+func filterNamespaceList(command, output string, cfg *config.ConfigData) (string, error) {
+	if !listsNamespaces(command, cfg) {
+		return output, nil
+	}
+	cmdline, _ := parseCommandLine(command)
+	if reference := cmdline.args()[0]; strings.Contains(reference, ",") {
+		return "", fmt.Errorf("namespaces must be listed on their own while the namespace list is filtered, got %q", reference)
+	}
+
+	allowed := func(name string) bool { return cfg.SecurityConfig.IsNamespaceAllowed(name) }
+	format, _ := cmdline.flag("--output")
+	switch {
+	case format == "json":
+		return filterNamespaceJSON(output, allowed)
+	case format == "yaml":
+		return filterNamespaceYAML(output, allowed)
+	case format == "" || format == "wide":
+		return filterNamespaceLines(output, !cmdline.boolFlag("--no-headers"), allowed), nil
+	case format == "name":
+		return filterNamespaceLines(output, false, allowed), nil
+	default:
+		return "", fmt.Errorf("output format '%s' can't be filtered by namespace; use json, yaml, name or the default table", format)
+	}
+}
+
+// filterNamespaceJSON filters the items of a JSON namespace list, or a single namespace
+func filterNamespaceJSON(output string, allowed func(string) bool) (string, error) {
+	var obj map[string]interface{}
+	if err := json.Unmarshal([]byte(output), &obj); err != nil {
+		return "", fmt.Errorf("failed to filter namespace list: %w", err)
+	}
+
+	items, isList := obj["items"].([]interface{})
+	if !isList {
+		if _, name := objectNamespacedName(obj); !allowed(name) {
+			return "No resources found\n", nil
+		}
+		return output, nil
+	}
+
+	kept := []interface{}{}
+	for _, item := range items {
+		itemObj, _ := item.(map[string]interface{})
+		if _, name := objectNamespacedName(itemObj); allowed(name) {
+			kept = append(kept, item)
+		}
+	}
+	if len(kept) == len(items) {
+		return output, nil
+	}
+	obj["items"] = kept
+	filtered, ok := marshalKubectlJSON(obj)
+	if !ok {
+		return "", fmt.Errorf("failed to encode filtered namespace list")
+	}
+	return filtered, nil
+}
+
+// filterNamespaceYAML filters the items of a YAML namespace list, or a single namespace,
+// preserving key order
+func filterNamespaceYAML(output string, allowed func(string) bool) (string, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(output), &doc); err != nil || len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return "", fmt.Errorf("failed to filter namespace list: unexpected YAML output")
+	}
+	root := doc.Content[0]
+
+	items := yamlMapValue(root, "items")
+	if items == nil || items.Kind != yaml.SequenceNode {
+		if !allowed(yamlMetadataName(root)) {
+			return "No resources found\n", nil
+		}
+		return output, nil
+	}
+
+	kept := items.Content[:0:0]
+	for _, item := range items.Content {
+		if allowed(yamlMetadataName(item)) {
+			kept = append(kept, item)
+		}
+	}
+	if len(kept) == len(items.Content) {
+		return output, nil
+	}
+	items.Content = kept
+	if len(kept) == 0 {
+		items.Style = yaml.FlowStyle
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return "", fmt.Errorf("failed to encode filtered namespace list: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// yamlMapValue returns the value of key in a YAML mapping, or nil
+func yamlMapValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// yamlMetadataName returns metadata.name of a YAML object
+func yamlMetadataName(node *yaml.Node) string {
+	if name := yamlMapValue(yamlMapValue(node, "metadata"), "name"); name != nil {
+		return name.Value
+	}
+	return ""
+}
+
+// filterNamespaceLines keeps the rows of table or name output whose namespace is allowed,
+// plus the header if there is one. The name is the first column, with any "namespace/"
+// prefix from -o name removed.
+func filterNamespaceLines(output string, header bool, allowed func(string) bool) string {
+	lines := strings.Split(strings.TrimRight(output, "\n"), "\n")
+	var kept []string
+	for i, line := range lines {
+		fields := strings.Fields(line)
+		if (header && i == 0) || len(fields) == 0 {
+			kept = append(kept, line)
+			continue
+		}
+		name := fields[0]
+		if _, after, ok := strings.Cut(name, "/"); ok {
+			name = after
+		}
+		if allowed(name) {
+			kept = append(kept, line)
+		}
+	}
+	if len(kept) == 0 || (header && len(kept) == 1) {
+		return "No resources found\n"
+	}
+	return strings.Join(kept, "\n") + "\n"
+}
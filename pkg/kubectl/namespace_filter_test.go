@@ -0,0 +1,106 @@
+package kubectl
+
+import (
+	"strings"
+	"testing"
+)
+
+const namespaceTable = `NAME          STATUS   AGE
+default       Active   10d
+kube-system   Active   10d
+shop          Active   3d
+`
+
+const namespaceJSON = `{
+    "apiVersion": "v1",
+    "items": [
+        {"kind": "Namespace", "metadata": {"name": "kube-system"}},
+        {"kind": "Namespace", "metadata": {"name": "shop"}}
+    ],
+    "kind": "List"
+}
+`
+
+const namespaceYAML = `apiVersion: v1
+items:
+- kind: Namespace
+  metadata:
+    name: kube-system
+- kind: Namespace
+  metadata:
+    name: shop
+kind: List
+`
+
+func TestFilterNamespaceList(t *testing.T) {
+	cfg := newTestConfig("readonly")
+	cfg.FilterNamespaceList = true
+	cfg.SecurityConfig.SetAllowedNamespaces("shop,default")
+
+	tests := []struct {
+		command string
+		output  string
+	}{
+		{"get namespaces", namespaceTable},
+		{"get ns -o wide", namespaceTable},
+		{"get ns -o name", "namespace/default\nnamespace/kube-system\nnamespace/shop\n"},
+		{"get namespaces -o json", namespaceJSON},
+		{"get namespace -o yaml", namespaceYAML},
+		{"get ns/kube-system -o json", `{"kind": "Namespace", "metadata": {"name": "kube-system"}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.command, func(t *testing.T) {
+			filtered, err := filterNamespaceList(tt.command, tt.output, cfg)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if strings.Contains(filtered, "kube-system") {
+				t.Errorf("disallowed namespace listed:\n%s", filtered)
+			}
+			if !strings.Contains(tt.command, "kube-system") && !strings.Contains(filtered, "shop") {
+				t.Errorf("allowed namespace missing:\n%s", filtered)
+			}
+		})
+	}
+
+	// Formats without item structure are refused rather than leaked
+	if _, err := filterNamespaceList("get ns -o jsonpath={.items[*].metadata.name}", "kube-system shop", cfg); err == nil {
+		t.Error("expected jsonpath output to be refused")
+	}
+	if _, err := filterNamespaceList("get ns,pods", namespaceTable, cfg); err == nil {
+		t.Error("expected a combined listing to be refused")
+	}
+
+	// Other resources, and configurations without the option, are left alone
+	if got, _ := filterNamespaceList("get pods -n shop", namespaceTable, cfg); got != namespaceTable {
+		t.Errorf("non-namespace output was modified")
+	}
+	cfg.FilterNamespaceList = false
+	if got, _ := filterNamespaceList("get namespaces", namespaceTable, cfg); got != namespaceTable {
+		t.Errorf("output was filtered without --filter-namespace-list")
+	}
+}
+
+func TestKubectlToolExecutor_FilterNamespaceList(t *testing.T) {
+	worker := newTestWorker(t, func(command string) map[string]interface{} {
+		return map[string]interface{}{"stdout": namespaceTable}
+	})
+	executor := NewKubectlToolExecutor(worker)
+
+	cfg := newTestConfig("readonly")
+	cfg.FilterNamespaceList = true
+	cfg.SecurityConfig.SetAllowedNamespaces("shop")
+
+	output, err := executor.Execute(map[string]interface{}{
+		"_tool_name": "kubectl_resources",
+		"operation":  "get",
+		"resource":   "namespaces",
+		"args":       "",
+	}, cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "NAME          STATUS   AGE\nshop          Active   3d\n"; output != want {
+		t.Errorf("output = %q, want %q", output, want)
+	}
+}
@@ -0,0 +1,79 @@
+package kubectl
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// outputNDJSON is the output parameter value that returns a get as one JSON object per line
+const outputNDJSON = "ndjson"
+
+// wantsNDJSON reports whether a call asks for newline-delimited JSON
+func wantsNDJSON(params map[string]interface{}) bool {
+	output, _ := params["output"].(string)
+	return strings.TrimSpace(output) == outputNDJSON
+}
+
+// applyNDJSON runs a get that asked for newline-delimited JSON with -o json, whose items are
+// split into lines after the command runs
+func applyNDJSON(command string, params map[string]interface{}) (string, error) {
+	cmdline, err := parseCommandLine(command)
+	if err != nil {
+		return "", err
+	}
+	if cmdline.verb() != "get" {
+		return "", fmt.Errorf("output=ndjson is only supported for get, not %s", cmdline.verb())
+	}
+	if namesOnly, _ := params["names_only"].(bool); namesOnly {
+		return "", fmt.Errorf("output=ndjson can't be combined with names_only")
+	}
+	if structured, _ := params["structured"].(bool); structured {
+		return "", fmt.Errorf("output=ndjson can't be combined with structured")
+	}
+	if expression, _ := params["jq"].(string); strings.TrimSpace(expression) != "" {
+		return "", fmt.Errorf("output=ndjson can't be combined with jq")
+	}
+	return insertFlags(command, []string{"--output=json"}), nil
+}
+
+// toNDJSON writes each item of a JSON list, or a single object, as a compact JSON object on
+// its own line. A list cut short by --max-list-items ends with a {"truncation": ...} line.
+func toNDJSON(output string) (string, error) {
+	var obj interface{}
+	decoder := json.NewDecoder(strings.NewReader(output))
+	decoder.UseNumber()
+	if err := decoder.Decode(&obj); err != nil {
+		return "", fmt.Errorf("failed to convert output to ndjson: %w", err)
+	}
+
+	var lines []interface{}
+	switch value := obj.(type) {
+	case map[string]interface{}:
+		items, isList := value["items"].([]interface{})
+		if !isList {
+			lines = []interface{}{value}
+			break
+		}
+		lines = items
+		if truncation, ok := value["truncation"]; ok {
+			lines = append(lines, map[string]interface{}{"truncation": truncation})
+		}
+	case []interface{}:
+		lines = value
+	default:
+		return "", fmt.Errorf("failed to convert output to ndjson: expected a JSON object or list")
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	for _, line := range lines {
+		// Encode ends each object with a newline
+		if err := encoder.Encode(line); err != nil {
+			return "", fmt.Errorf("failed to convert output to ndjson: %w", err)
+		}
+	}
+	return buf.String(), nil
+}
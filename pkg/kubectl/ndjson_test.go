@@ -0,0 +1,94 @@
+package kubectl
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const ndjsonPodsJSON = `{
+  "apiVersion": "v1",
+  "kind": "List",
+  "items": [
+    {"kind": "Pod", "metadata": {"name": "web-1", "namespace": "shop"}, "spec": {"nodeName": "node-a"}},
+    {"kind": "Pod", "metadata": {"name": "web-2", "namespace": "shop"}, "spec": {"nodeName": "node-b"}},
+    {"kind": "Pod", "metadata": {"name": "web-3", "namespace": "shop", "annotations": {"note": "<a&b>"}}}
+  ]
+}`
+
+func TestKubectlToolExecutor_NDJSON(t *testing.T) {
+	var commands []string
+	worker := newTestWorker(t, func(command string) map[string]interface{} {
+		commands = append(commands, command)
+		return map[string]interface{}{"stdout": ndjsonPodsJSON}
+	})
+	executor := NewKubectlToolExecutor(worker)
+	cfg := newTestConfig("readonly")
+
+	get := func(extra map[string]interface{}) (string, error) {
+		params := map[string]interface{}{
+			"_tool_name": "kubectl_resources",
+			"operation":  "get",
+			"resource":   "pods",
+			"args":       "-n shop",
+			"output":     "ndjson",
+		}
+		for key, value := range extra {
+			params[key] = value
+		}
+		return executor.Execute(params, cfg)
+	}
+
+	output, err := get(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(commands) != 1 || !strings.Contains(commands[0], "--output=json") || strings.Contains(commands[0], "ndjson") {
+		t.Errorf("expected the get to run with -o json, got %v", commands)
+	}
+	if !strings.HasSuffix(output, "\n") {
+		t.Errorf("expected the output to end with a newline, got %q", output)
+	}
+	lines := strings.Split(strings.TrimSuffix(output, "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected one line per pod, got %q", output)
+	}
+	for i, line := range lines {
+		var obj map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &obj); err != nil {
+			t.Fatalf("line %d is not a JSON object: %q: %v", i, line, err)
+		}
+		if _, name := objectNamespacedName(obj); name != []string{"web-1", "web-2", "web-3"}[i] {
+			t.Errorf("line %d holds %q, want web-%d", i, name, i+1)
+		}
+	}
+	if !strings.Contains(lines[2], "<a&b>") {
+		t.Errorf("expected values to be written unescaped, got %q", lines[2])
+	}
+
+	// A list cut by --max-list-items ends with its truncation
+	cfg.MaxListItems = 2
+	output, err = get(nil)
+	cfg.MaxListItems = 0
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lines = strings.Split(strings.TrimSuffix(output, "\n"), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[2], `{"truncation":`) {
+		t.Errorf("expected 2 pods and a truncation line, got %q", output)
+	}
+
+	commands = nil
+	for _, extra := range []map[string]interface{}{
+		{"structured": true},
+		{"names_only": true},
+		{"operation": "describe"},
+	} {
+		if _, err := get(extra); err == nil {
+			t.Errorf("expected output=ndjson with %v to be refused", extra)
+		}
+	}
+	if len(commands) != 0 {
+		t.Errorf("expected nothing to run, got %v", commands)
+	}
+}
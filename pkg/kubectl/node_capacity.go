@@ -0,0 +1,204 @@
+package kubectl
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Azure/mcp-kubernetes/pkg/config"
+	"github.com/Azure/mcp-kubernetes/pkg/security"
+)
+
+// nodeCapacityPodSelector leaves out finished pods, which no longer hold their requests
+const nodeCapacityPodSelector = "status.phase!=Succeeded,status.phase!=Failed"
+
+// nodeCapacityReport is the scheduling headroom of each node
+type nodeCapacityReport struct {
+	Nodes []nodeCapacity `json:"nodes"`
+}
+
+// nodeCapacity compares what a node can hold with what the pods on it request
+type nodeCapacity struct {
+	Name          string        `json:"name"`
+	Unschedulable bool          `json:"unschedulable,omitempty"`
+	Capacity      nodeResources `json:"capacity"`
+	Allocatable   nodeResources `json:"allocatable"`
+	Requested     nodeResources `json:"requested"`
+	// Headroom is allocatable minus requested, what new pods can still request
+	Headroom nodeResources `json:"headroom"`
+}
+
+// nodeResources are amounts of cpu in millicores, memory in MiB and pods
+type nodeResources struct {
+	CPU    int64   `json:"cpu_millicores"`
+	Memory float64 `json:"memory_mib"`
+	Pods   int64   `json:"pods"`
+}
+
+// nodeCapacity reports each node's capacity, allocatable resources, the sum of the requests
+// of the pods running on it, and the headroom left. Args name one node, or select nodes with
+// -l. Nodes are cluster-scoped, and only summed requests are reported, so the pods are listed
+// across all namespaces whatever the namespace allow-list; both lists are otherwise subject
+// to the usual checks.
+func (e *KubectlToolExecutor) nodeCapacity(args string, cfg *config.ConfigData) (string, error) {
+	cmdline, err := parseCommandLine(args)
+	if err != nil {
+		return "", err
+	}
+	if len(cmdline.positionals) > 1 {
+		return "", fmt.Errorf("node-capacity takes at most one node name")
+	}
+
+	var nodes []map[string]interface{}
+	podsCommand := "get pods --all-namespaces -o json --field-selector=" + nodeCapacityPodSelector
+	if len(cmdline.positionals) == 1 {
+		name := cmdline.positionals[0]
+		if kind, node, ok := strings.Cut(name, "/"); ok {
+			if kind != "node" && kind != "nodes" && kind != "no" {
+				return "", fmt.Errorf("node-capacity requires a node, got '%s'", name)
+			}
+			name = node
+		}
+		if !nodeName.MatchString(name) {
+			return "", fmt.Errorf("invalid node name '%s'", name)
+		}
+		node, err := e.getObject("get nodes "+name+" -o json", cfg)
+		if err != nil {
+			return "", err
+		}
+		nodes = []map[string]interface{}{node}
+		podsCommand += ",spec.nodeName=" + name
+	} else {
+		command := "get nodes -o json"
+		if selector, ok := cmdline.flag("--selector"); ok && selector != "" {
+			command += " -l " + shellQuote(selector)
+		}
+		if nodes, err = e.listObjects(command, cfg); err != nil {
+			return "", err
+		}
+	}
+
+	pods, err := e.listPodsAcrossNamespaces(podsCommand, cfg)
+	if err != nil {
+		return "", err
+	}
+	requested := map[string]nodeResources{}
+	for _, pod := range pods {
+		node := nestedString(pod, "spec", "nodeName")
+		if node == "" {
+			continue
+		}
+		sum := requested[node]
+		request := podRequests(pod)
+		sum.CPU += request.CPU
+		sum.Memory += request.Memory
+		sum.Pods++
+		requested[node] = sum
+	}
+
+	report := nodeCapacityReport{Nodes: []nodeCapacity{}}
+	for _, node := range nodes {
+		_, name := objectNamespacedName(node)
+		entry := nodeCapacity{
+			Name:          name,
+			Unschedulable: nestedValue(node, "spec", "unschedulable") == true,
+			Capacity:      resourceAmounts(nestedStringMap(node, "status", "capacity")),
+			Allocatable:   resourceAmounts(nestedStringMap(node, "status", "allocatable")),
+			Requested:     requested[name],
+		}
+		entry.Headroom = nodeResources{
+			CPU:    entry.Allocatable.CPU - entry.Requested.CPU,
+			Memory: entry.Allocatable.Memory - entry.Requested.Memory,
+			Pods:   entry.Allocatable.Pods - entry.Requested.Pods,
+		}
+		report.Nodes = append(report.Nodes, entry)
+	}
+	sort.Slice(report.Nodes, func(i, j int) bool { return report.Nodes[i].Name < report.Nodes[j].Name })
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode node capacity: %w", err)
+	}
+	return string(data), nil
+}
+
+// listPodsAcrossNamespaces lists pods in every namespace for a cluster-scoped summary. The
+// command is checked like a get of pods in no particular namespace, so the access level and
+// every policy but the namespace allow-list still apply.
+func (e *KubectlToolExecutor) listPodsAcrossNamespaces(command string, cfg *config.ConfigData) ([]map[string]interface{}, error) {
+	if err := e.checkAccessLevel(command, cfg); err != nil {
+		return nil, err
+	}
+	validator := security.NewValidator(cfg.SecurityConfig)
+	if err := validator.ValidateCommand("get pods", security.CommandTypeKubectl); err != nil {
+		return nil, err
+	}
+
+	output, err := e.runCommand(command, cfg)
+	if err != nil {
+		return nil, err
+	}
+	var list map[string]interface{}
+	if err := json.Unmarshal([]byte(output), &list); err != nil {
+		return nil, fmt.Errorf("failed to parse output of '%s': %w", command, err)
+	}
+	return listItems(list), nil
+}
+
+// resourceAmounts reads the cpu, memory and pods of a node's capacity or allocatable map;
+// amounts that can't be parsed count as zero
+func resourceAmounts(amounts map[string]string) nodeResources {
+	var resources nodeResources
+	if cpu, err := parseMillicores(amounts["cpu"]); err == nil {
+		resources.CPU = cpu
+	}
+	if memory, err := parseMebibytes(amounts["memory"]); err == nil {
+		resources.Memory = memory
+	}
+	if pods, err := strconv.ParseInt(amounts["pods"], 10, 64); err == nil {
+		resources.Pods = pods
+	}
+	return resources
+}
+
+// podRequests is what the scheduler reserves for a pod: the requests of its containers and
+// sidecars, or of its largest init container if that is more, plus the pod overhead
+func podRequests(pod map[string]interface{}) nodeResources {
+	var running nodeResources
+	containers, _ := nestedValue(pod, "spec", "containers").([]interface{})
+	for _, raw := range containers {
+		container, _ := raw.(map[string]interface{})
+		addRequests(&running, nestedStringMap(container, "resources", "requests"))
+	}
+
+	var largestInit nodeResources
+	initContainers, _ := nestedValue(pod, "spec", "initContainers").([]interface{})
+	for _, raw := range initContainers {
+		container, _ := raw.(map[string]interface{})
+		// Sidecars, init containers that restart always, keep running beside the containers
+		if nestedString(container, "restartPolicy") == "Always" {
+			addRequests(&running, nestedStringMap(container, "resources", "requests"))
+			continue
+		}
+		var request nodeResources
+		addRequests(&request, nestedStringMap(container, "resources", "requests"))
+		largestInit.CPU = max(largestInit.CPU, request.CPU)
+		largestInit.Memory = max(largestInit.Memory, request.Memory)
+	}
+
+	total := nodeResources{CPU: max(running.CPU, largestInit.CPU), Memory: max(running.Memory, largestInit.Memory)}
+	addRequests(&total, nestedStringMap(pod, "spec", "overhead"))
+	return total
+}
+
+// addRequests adds the cpu and memory of a requests map to a running total
+func addRequests(total *nodeResources, requests map[string]string) {
+	if cpu, err := parseMillicores(requests["cpu"]); err == nil {
+		total.CPU += cpu
+	}
+	if memory, err := parseMebibytes(requests["memory"]); err == nil {
+		total.Memory += memory
+	}
+}
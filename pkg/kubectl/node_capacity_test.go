@@ -0,0 +1,112 @@
+package kubectl
+
+import (
+	"testing"
+)
+
+const capacityNodesJSON = `{
+  "items": [
+    {
+      "metadata": {"name": "node-1"},
+      "spec": {},
+      "status": {
+        "capacity": {"cpu": "4", "memory": "16Gi", "pods": "110"},
+        "allocatable": {"cpu": "3860m", "memory": "15Gi", "pods": "110"}
+      }
+    },
+    {
+      "metadata": {"name": "node-2"},
+      "spec": {"unschedulable": true},
+      "status": {
+        "capacity": {"cpu": "2", "memory": "8Gi", "pods": "30"},
+        "allocatable": {"cpu": "1900m", "memory": "7Gi", "pods": "30"}
+      }
+    }
+  ]
+}`
+
+const capacityPodsJSON = `{
+  "items": [
+    {
+      "metadata": {"name": "web-0", "namespace": "shop"},
+      "spec": {
+        "nodeName": "node-1",
+        "containers": [
+          {"name": "web", "resources": {"requests": {"cpu": "500m", "memory": "512Mi"}}},
+          {"name": "proxy", "resources": {"requests": {"cpu": "100m", "memory": "128Mi"}}}
+        ],
+        "initContainers": [
+          {"name": "migrate", "resources": {"requests": {"cpu": "1", "memory": "256Mi"}}},
+          {"name": "log-shipper", "restartPolicy": "Always", "resources": {"requests": {"cpu": "50m", "memory": "64Mi"}}}
+        ]
+      }
+    },
+    {
+      "metadata": {"name": "coredns-1", "namespace": "kube-system"},
+      "spec": {
+        "nodeName": "node-1",
+        "overhead": {"cpu": "10m", "memory": "16Mi"},
+        "containers": [{"name": "coredns", "resources": {"requests": {"cpu": "100m", "memory": "70Mi"}}}]
+      }
+    },
+    {
+      "metadata": {"name": "batch-1", "namespace": "jobs"},
+      "spec": {"nodeName": "node-2", "containers": [{"name": "batch"}]}
+    }
+  ]
+}`
+
+func TestKubectlToolExecutor_NodeCapacity(t *testing.T) {
+	// Pods are summed across namespaces even when the allow-list wouldn't allow listing them
+	cfg := newTestConfig("readonly")
+	cfg.SecurityConfig.SetAllowedNamespaces("shop")
+
+	// web-0 reserves the larger of its migrate init container (1000m, 256Mi) and its
+	// containers plus sidecar (650m, 704Mi); coredns-1 adds its overhead (110m, 86Mi)
+	expectedNode1 := nodeCapacity{
+		Name:        "node-1",
+		Capacity:    nodeResources{CPU: 4000, Memory: 16384, Pods: 110},
+		Allocatable: nodeResources{CPU: 3860, Memory: 15360, Pods: 110},
+		Requested:   nodeResources{CPU: 1110, Memory: 790, Pods: 2},
+		Headroom:    nodeResources{CPU: 2750, Memory: 14570, Pods: 108},
+	}
+
+	runDiagnosticCases(t, "node-capacity", cfg, map[string]string{
+		"kubectl get nodes -o json": capacityNodesJSON,
+		"kubectl get pods --all-namespaces -o json --field-selector=status.phase!=Succeeded,status.phase!=Failed": capacityPodsJSON,
+		"kubectl get nodes node-1 -o json": `{"metadata": {"name": "node-1"}, "spec": {},
+			"status": {"capacity": {"cpu": "4", "memory": "16Gi", "pods": "110"}, "allocatable": {"cpu": "3860m", "memory": "15Gi", "pods": "110"}}}`,
+		"kubectl get pods --all-namespaces -o json --field-selector=status.phase!=Succeeded,status.phase!=Failed,spec.nodeName=node-1": capacityPodsJSON,
+	}, []diagnosticCase{
+		{
+			name: "every node",
+			check: func(t *testing.T, output string, _ []string) {
+				report := decodeReport[nodeCapacityReport](t, output)
+				if len(report.Nodes) != 2 {
+					t.Fatalf("expected two nodes, got %+v", report.Nodes)
+				}
+				if report.Nodes[0] != expectedNode1 {
+					t.Errorf("expected %+v, got %+v", expectedNode1, report.Nodes[0])
+				}
+				node2 := report.Nodes[1]
+				if !node2.Unschedulable || node2.Requested != (nodeResources{Pods: 1}) || node2.Headroom != (nodeResources{CPU: 1900, Memory: 7168, Pods: 29}) {
+					t.Errorf("unexpected node-2: %+v", node2)
+				}
+			},
+		},
+		{
+			// One node lists only the pods scheduled on it
+			name: "one node",
+			args: "node/node-1",
+			check: func(t *testing.T, output string, _ []string) {
+				report := decodeReport[nodeCapacityReport](t, output)
+				if len(report.Nodes) != 1 || report.Nodes[0] != expectedNode1 {
+					t.Errorf("expected only node-1, got %+v", report.Nodes)
+				}
+			},
+		},
+		{name: "name carrying flags refused", args: "'node-1 --as=admin'", refused: "invalid node name"},
+		{name: "other kinds refused", args: "pod/web-0", refused: "requires a node"},
+		{name: "several names refused", args: "node-1 node-2", refused: "at most one node name"},
+	})
+}
@@ -0,0 +1,152 @@
+package kubectl
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Azure/mcp-kubernetes/pkg/config"
+)
+
+// orphanResourceType matches the resource type an orphans check lists
+var orphanResourceType = regexp.MustCompile(`^[a-z0-9]([-a-z0-9.]*[a-z0-9])?$`)
+
+// orphanReport lists the objects of one resource type without a live owner
+type orphanReport struct {
+	Resource  string         `json:"resource"`
+	Namespace string         `json:"namespace"`
+	Checked   int            `json:"checked"`
+	Orphans   []orphanObject `json:"orphans"`
+	// UncheckedOwnerKinds are owner kinds that couldn't be resolved to a resource type, so
+	// objects owned by them are assumed to have a live owner
+	UncheckedOwnerKinds []string `json:"unchecked_owner_kinds,omitempty"`
+}
+
+type orphanObject struct {
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+	Owner  string `json:"owner,omitempty"`
+}
+
+// findOrphans lists a resource type in a namespace and reports the objects with no owner
+// references, or whose owners no longer exist. Each owner kind is listed once in the same
+// namespace, and every lookup is a read-only get subject to the usual namespace checks.
+func (e *KubectlToolExecutor) findOrphans(resource, args string, cfg *config.ConfigData) (string, error) {
+	resource = strings.ToLower(strings.TrimSpace(resource))
+	if !orphanResourceType.MatchString(resource) || strings.Contains(resource, ",") {
+		return "", fmt.Errorf("orphans requires a single resource type, got '%s'", resource)
+	}
+
+	cmdline, err := parseCommandLine(args)
+	if err != nil {
+		return "", err
+	}
+	if len(cmdline.positionals) != 0 {
+		return "", fmt.Errorf("orphans lists every %s in a namespace and takes no names", resource)
+	}
+	namespace, err := namespaceFlag(cmdline, "default")
+	if err != nil {
+		return "", err
+	}
+	listCommand := fmt.Sprintf("get %s -n %s -o json", resource, namespace)
+	if selector, ok := cmdline.flag("--selector"); ok && selector != "" {
+		listCommand = fmt.Sprintf("get %s -n %s -l %s -o json", resource, namespace, shellQuote(selector))
+	}
+
+	objects, err := e.listObjects(listCommand, cfg)
+	if err != nil {
+		return "", err
+	}
+
+	report := &orphanReport{Resource: resource, Namespace: namespace, Checked: len(objects), Orphans: []orphanObject{}}
+	owners := make(map[string]map[string]bool) // uids of the live objects of each owner kind
+	unchecked := make(map[string]bool)
+	for _, obj := range objects {
+		_, name := objectNamespacedName(obj)
+		refs := ownerReferences(obj)
+		if len(refs) == 0 {
+			report.Orphans = append(report.Orphans, orphanObject{Name: name, Reason: "no owner references"})
+			continue
+		}
+
+		for _, ref := range refs {
+			kind, _ := ref["kind"].(string)
+			ownerName, _ := ref["name"].(string)
+			uid, _ := ref["uid"].(string)
+
+			live, ok := owners[kind]
+			if !ok {
+				live, err = e.liveOwners(kind, namespace, cfg)
+				if err != nil {
+					return "", err
+				}
+				owners[kind] = live
+			}
+			if live == nil {
+				unchecked[kind] = true
+				continue
+			}
+			if !live[uid] {
+				report.Orphans = append(report.Orphans, orphanObject{
+					Name:   name,
+					Reason: "owner no longer exists",
+					Owner:  strings.ToLower(kind) + "/" + ownerName,
+				})
+				break
+			}
+		}
+	}
+
+	for kind := range unchecked {
+		report.UncheckedOwnerKinds = append(report.UncheckedOwnerKinds, kind)
+	}
+	sort.Strings(report.UncheckedOwnerKinds)
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode orphans result: %w", err)
+	}
+	return string(data), nil
+}
+
+// ownerReferences returns the controller owner reference of an object, or all of its owner
+// references when none is marked as the controller
+func ownerReferences(obj map[string]interface{}) []map[string]interface{} {
+	rawRefs, _ := nestedValue(obj, "metadata", "ownerReferences").([]interface{})
+	var refs []map[string]interface{}
+	for _, raw := range rawRefs {
+		ref, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if controller, _ := ref["controller"].(bool); controller {
+			return []map[string]interface{}{ref}
+		}
+		refs = append(refs, ref)
+	}
+	return refs
+}
+
+// liveOwners returns the uids of the existing objects of an owner kind, listed in namespace
+// for namespaced kinds, or nil when the kind isn't a known resource type
+func (e *KubectlToolExecutor) liveOwners(kind, namespace string, cfg *config.ConfigData) (map[string]bool, error) {
+	resource, ok := cfg.SecurityConfig.Resources().Lookup(kind)
+	if !ok {
+		return nil, nil
+	}
+	command := fmt.Sprintf("get %s -o json", resource.Name)
+	if resource.Namespaced {
+		command = fmt.Sprintf("get %s -n %s -o json", resource.Name, namespace)
+	}
+	objects, err := e.listObjects(command, cfg)
+	if err != nil {
+		return nil, err
+	}
+	live := make(map[string]bool, len(objects))
+	for _, obj := range objects {
+		live[objectUID(obj)] = true
+	}
+	return live, nil
+}
@@ -0,0 +1,88 @@
+package kubectl
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestKubectlToolExecutor_Orphans(t *testing.T) {
+	outputs := map[string]string{
+		"kubectl get replicasets -n shop -o json": `{"items": [
+			{"metadata": {"name": "web-6d4", "uid": "rs-1",
+				"ownerReferences": [{"kind": "Deployment", "name": "web", "uid": "dep-1", "controller": true}]}},
+			{"metadata": {"name": "api-7f9", "uid": "rs-2",
+				"ownerReferences": [{"kind": "Deployment", "name": "api", "uid": "dep-2", "controller": true}]}},
+			{"metadata": {"name": "manual-1a2", "uid": "rs-3"}},
+			{"metadata": {"name": "rollout-5c8", "uid": "rs-4",
+				"ownerReferences": [{"kind": "Rollout", "name": "canary", "uid": "ro-1", "controller": true}]}}]}`,
+		"kubectl get deployments -n shop -o json": `{"items": [
+			{"metadata": {"name": "web", "uid": "dep-1"}}]}`,
+	}
+	worker := newTestWorker(t, func(command string) map[string]interface{} {
+		output, ok := outputs[command]
+		if !ok {
+			return map[string]interface{}{"error": "unexpected command: " + command}
+		}
+		return map[string]interface{}{"stdout": output}
+	})
+	executor := NewKubectlToolExecutor(worker)
+
+	result, err := executor.Execute(map[string]interface{}{
+		"_tool_name": "kubectl_diagnostics",
+		"operation":  "orphans",
+		"resource":   "replicasets",
+		"args":       "-n shop",
+	}, newTestConfig("readonly"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var report orphanReport
+	if err := json.Unmarshal([]byte(result), &report); err != nil {
+		t.Fatalf("result is not JSON: %v\n%s", err, result)
+	}
+	if report.Checked != 4 {
+		t.Errorf("checked = %d, want 4", report.Checked)
+	}
+	want := []orphanObject{
+		{Name: "api-7f9", Reason: "owner no longer exists", Owner: "deployment/api"},
+		{Name: "manual-1a2", Reason: "no owner references"},
+	}
+	if len(report.Orphans) != len(want) {
+		t.Fatalf("orphans = %+v, want %+v", report.Orphans, want)
+	}
+	for i := range want {
+		if report.Orphans[i] != want[i] {
+			t.Errorf("orphan %d = %+v, want %+v", i, report.Orphans[i], want[i])
+		}
+	}
+	if len(report.UncheckedOwnerKinds) != 1 || report.UncheckedOwnerKinds[0] != "Rollout" {
+		t.Errorf("unchecked owner kinds = %v, want [Rollout]", report.UncheckedOwnerKinds)
+	}
+}
+
+func TestKubectlToolExecutor_OrphansNamespacePolicy(t *testing.T) {
+	worker := newTestWorker(t, func(command string) map[string]interface{} {
+		t.Errorf("unexpected command: %s", command)
+		return map[string]interface{}{"stdout": `{"items": []}`}
+	})
+	executor := NewKubectlToolExecutor(worker)
+	cfg := newTestConfig("readonly")
+	cfg.SecurityConfig.SetAllowedNamespaces("shop")
+
+	for args, refused := range map[string]string{
+		"-n kube-system":       "kube-system",
+		"-n 'shop --as=admin'": "invalid namespace",
+	} {
+		_, err := executor.Execute(map[string]interface{}{
+			"_tool_name": "kubectl_diagnostics",
+			"operation":  "orphans",
+			"resource":   "replicasets",
+			"args":       args,
+		}, cfg)
+		if err == nil || !strings.Contains(err.Error(), refused) {
+			t.Errorf("expected %q to be refused with %q, got %v", args, refused, err)
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package kubectl
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/Azure/mcp-kubernetes/pkg/config"
+	"github.com/Azure/mcp-kubernetes/pkg/security"
+)
+
+// serverTimeoutMessage is how the API server reports a request it couldn't answer in time,
+// which for a large list usually succeeds when the list is fetched in pages
+const serverTimeoutMessage = "the server was unable to return a response in time"
+
+// listIncomplete annotates a list fetched in pages that stopped at --timeout-max-pages
+type listIncomplete struct {
+	Pages          int    `json:"pages"`
+	PageSize       int    `json:"pageSize"`
+	RemainingItems *int64 `json:"remainingItems,omitempty"`
+}
+
+// pagedList is a get list that can be fetched again from the API in pages
+type pagedList struct {
+	resource security.APIResource
+	// path is the API path of the list, e.g. /apis/apps/v1/namespaces/shop/deployments
+	path  string
+	query url.Values
+	// output is the requested format, json or name
+	output string
+	// managedFields keeps metadata.managedFields, which kubectl leaves out by default
+	managedFields bool
+	// impersonation holds the --as and --as-group flags, passed on to each page request
+	impersonation string
+}
+
+// serverTimedOut reports whether a command failed because the API server ran out of time
+func serverTimedOut(err error) bool {
+	return err != nil && strings.Contains(err.Error(), serverTimeoutMessage)
+}
+
+// pagedListCommand returns how to fetch a get command's list in pages. Like the informer
+// cache, only -o json and -o name lists of one known resource type with an explicit
+// namespace, or -A, are supported, so the assembled result matches what kubectl prints.
+func pagedListCommand(command string, cfg *config.ConfigData) (*pagedList, bool) {
+	cl, err := parseCommandLine(command)
+	if err != nil || cl.verb() != "get" || len(cl.trailing) > 0 {
+		return nil, false
+	}
+	args := cl.args()
+	if len(args) != 1 || strings.ContainsAny(args[0], "/,") {
+		return nil, false
+	}
+	resource, ok := cfg.SecurityConfig.Resources().Lookup(args[0])
+	if !ok || resource.Version == "" {
+		return nil, false
+	}
+
+	list := &pagedList{resource: resource, query: url.Values{}}
+	for name, values := range cl.flags {
+		switch name {
+		case "--namespace", "--all-namespaces", "--output", "--chunk-size":
+		case "--show-managed-fields":
+			list.managedFields = cl.boolFlag(name)
+		case "--selector":
+			list.query.Set("labelSelector", values[len(values)-1])
+		case "--field-selector":
+			list.query.Set("fieldSelector", values[len(values)-1])
+		case "--as", "--as-group":
+			for _, value := range values {
+				list.impersonation += " " + name + "=" + shellQuote(value)
+			}
+		default:
+			return nil, false
+		}
+	}
+
+	list.output, _ = cl.flag("--output")
+	if list.output != "json" && list.output != "name" {
+		return nil, false
+	}
+
+	list.path = "/api/" + resource.Version
+	if resource.Group != "" {
+		list.path = "/apis/" + resource.Group + "/" + resource.Version
+	}
+	if resource.Namespaced {
+		namespace, hasNamespace := cl.flag("--namespace")
+		allNamespaces := cl.boolFlag("--all-namespaces")
+		if hasNamespace == allNamespaces {
+			// The kubeconfig's default namespace isn't known here
+			return nil, false
+		}
+		if hasNamespace {
+			if !namespaceName.MatchString(namespace) {
+				return nil, false
+			}
+			list.path += "/namespaces/" + namespace
+		}
+	}
+	list.path += "/" + resource.Name
+	return list, true
+}
+
+// getInPages fetches a list the API server timed out on in pages of --timeout-page-size
+// items, up to --timeout-max-pages pages, and assembles them into the output kubectl would
+// have printed. A list cut off at the page bound says so.
+func (e *KubectlToolExecutor) getInPages(command string, list *pagedList, timeout int, cfg *config.ConfigData) (string, error) {
+	slog.Warn("API server timed out on list, fetching it in pages", "command", command, "page_size", cfg.TimeoutPageSize)
+
+	apiVersion := list.resource.Version
+	if list.resource.Group != "" {
+		apiVersion = list.resource.Group + "/" + apiVersion
+	}
+
+	items := []interface{}{}
+	var incomplete *listIncomplete
+	token := ""
+	for page := 1; ; page++ {
+		list.query.Set("limit", strconv.Itoa(cfg.TimeoutPageSize))
+		if token != "" {
+			list.query.Set("continue", token)
+		}
+		pageCommand := "get --raw " + shellQuote(list.path+"?"+list.query.Encode()) + list.impersonation
+		output, err := e.executor.executeKubectlCommandOnHostWithin(pageCommand, "", timeout, cfg)
+		if err != nil {
+			return "", fmt.Errorf("failed to fetch page %d of the list after the API server timed out: %w", page, err)
+		}
+
+		var result struct {
+			Metadata struct {
+				Continue           string `json:"continue"`
+				RemainingItemCount *int64 `json:"remainingItemCount"`
+			} `json:"metadata"`
+			Items []map[string]interface{} `json:"items"`
+		}
+		if err := json.Unmarshal([]byte(output), &result); err != nil {
+			return "", fmt.Errorf("failed to parse page %d of the list: %w", page, err)
+		}
+		for _, item := range result.Items {
+			// The API leaves out the type of each item in a list; kubectl fills it in
+			if _, ok := item["apiVersion"]; !ok {
+				item["apiVersion"] = apiVersion
+			}
+			if _, ok := item["kind"]; !ok {
+				item["kind"] = list.resource.Kind
+			}
+			if metadata, ok := item["metadata"].(map[string]interface{}); ok && !list.managedFields {
+				delete(metadata, "managedFields")
+			}
+			items = append(items, item)
+		}
+
+		token = result.Metadata.Continue
+		if token == "" {
+			break
+		}
+		if page == cfg.TimeoutMaxPages {
+			incomplete = &listIncomplete{Pages: page, PageSize: cfg.TimeoutPageSize, RemainingItems: result.Metadata.RemainingItemCount}
+			break
+		}
+	}
+
+	if list.output == "name" {
+		prefix := strings.ToLower(list.resource.Kind)
+		if list.resource.Group != "" {
+			prefix += "." + list.resource.Group
+		}
+		var b strings.Builder
+		for _, item := range items {
+			_, name := objectNamespacedName(item.(map[string]interface{}))
+			b.WriteString(prefix + "/" + name + "\n")
+		}
+		if incomplete != nil {
+			fmt.Fprintf(&b, "... incomplete: stopped after %d pages of %d items (--timeout-max-pages)\n", incomplete.Pages, incomplete.PageSize)
+		}
+		return b.String(), nil
+	}
+
+	assembled := map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "List",
+		"items":      items,
+		"metadata":   map[string]interface{}{"resourceVersion": ""},
+	}
+	if incomplete != nil {
+		assembled["incomplete"] = incomplete
+	}
+	output, ok := marshalKubectlJSON(assembled)
+	if !ok {
+		return "", fmt.Errorf("failed to encode the list fetched in pages")
+	}
+	return output, nil
+}
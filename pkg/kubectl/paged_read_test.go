@@ -0,0 +1,115 @@
+package kubectl
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const serverTimeoutStderr = "Error from server (Timeout): the server was unable to return a response in time (get pods)"
+
+func TestKubectlToolExecutor_GetInPagesAfterTimeout(t *testing.T) {
+	pages := map[string]string{
+		"kubectl get --raw '/api/v1/namespaces/shop/pods?labelSelector=app%3Dweb&limit=2'": `{
+  "kind": "PodList", "apiVersion": "v1",
+  "metadata": {"continue": "token-1", "remainingItemCount": 1},
+  "items": [{"metadata": {"name": "web-1", "namespace": "shop"}}, {"metadata": {"name": "web-2", "namespace": "shop"}}]
+}`,
+		"kubectl get --raw '/api/v1/namespaces/shop/pods?continue=token-1&labelSelector=app%3Dweb&limit=2'": `{
+  "kind": "PodList", "apiVersion": "v1",
+  "metadata": {},
+  "items": [{"metadata": {"name": "web-3", "namespace": "shop"}}]
+}`,
+	}
+	var commands []string
+	worker := newTestWorker(t, func(command string) map[string]interface{} {
+		commands = append(commands, command)
+		if strings.HasPrefix(command, "kubectl get pods") {
+			return map[string]interface{}{"stderr": serverTimeoutStderr, "exit_code": 1}
+		}
+		if page, ok := pages[command]; ok {
+			return map[string]interface{}{"stdout": page}
+		}
+		return map[string]interface{}{"error": "unexpected command: " + command}
+	})
+	executor := NewKubectlToolExecutor(worker)
+	cfg := newTestConfig("readonly")
+	cfg.TimeoutPageSize = 2
+
+	get := func(args string) (string, error) {
+		return executor.Execute(map[string]interface{}{
+			"_tool_name": "kubectl_resources",
+			"operation":  "get",
+			"resource":   "pods",
+			"args":       args,
+		}, cfg)
+	}
+
+	result, err := get("-n shop -l app=web -o json")
+	if err != nil {
+		t.Fatalf("unexpected error: %v (commands: %v)", err, commands)
+	}
+	var list struct {
+		Kind       string                   `json:"kind"`
+		Items      []map[string]interface{} `json:"items"`
+		Incomplete *listIncomplete          `json:"incomplete"`
+	}
+	if err := json.Unmarshal([]byte(result), &list); err != nil {
+		t.Fatalf("expected a JSON list, got %q: %v", result, err)
+	}
+	if list.Kind != "List" || len(list.Items) != 3 || list.Incomplete != nil {
+		t.Fatalf("expected the 3 pods from both pages, got %s", result)
+	}
+	if list.Items[2]["kind"] != "Pod" || list.Items[2]["apiVersion"] != "v1" {
+		t.Errorf("expected each item to carry its type, got %v", list.Items[2])
+	}
+	if len(commands) != 3 {
+		t.Errorf("expected the timed-out get and two pages, got %v", commands)
+	}
+
+	// The pages fetched are bounded
+	cfg.TimeoutMaxPages = 1
+	result, err = get("-n shop -l app=web -o name")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "pod/web-1\npod/web-2\n... incomplete: stopped after 1 pages of 2 items (--timeout-max-pages)\n"
+	if result != want {
+		t.Errorf("expected %q, got %q", want, result)
+	}
+
+	// Table output can't be assembled from pages, so the timeout is returned
+	if _, err := get("-n shop"); err == nil || !strings.Contains(err.Error(), serverTimeoutMessage) {
+		t.Errorf("expected the timeout error for table output, got %v", err)
+	}
+}
+
+func TestPagedListCommand(t *testing.T) {
+	cfg := newTestConfig("readonly")
+	tests := []struct {
+		command string
+		path    string
+	}{
+		{command: "get deployments -A -o json", path: "/apis/apps/v1/deployments"},
+		{command: "get hpa -n shop -o name", path: "/apis/autoscaling/v2/namespaces/shop/horizontalpodautoscalers"},
+		{command: "get nodes -o json", path: "/api/v1/nodes"},
+		{command: "get pods -o json"},
+		{command: "get pods -n shop"},
+		{command: "get pods web -n shop -o json"},
+		{command: "get pods -n shop -o json --sort-by=.metadata.name"},
+		{command: "get widgets -n shop -o json"},
+	}
+
+	for _, tt := range tests {
+		list, ok := pagedListCommand(tt.command, cfg)
+		if tt.path == "" {
+			if ok {
+				t.Errorf("pagedListCommand(%q) should not page, got path %s", tt.command, list.path)
+			}
+			continue
+		}
+		if !ok || list.path != tt.path {
+			t.Errorf("pagedListCommand(%q) expected path %s, got %+v", tt.command, tt.path, list)
+		}
+	}
+}
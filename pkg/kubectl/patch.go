@@ -0,0 +1,105 @@
+package kubectl
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// patchTypes are the patch_type values and whether their body is a JSON patch operation list
+var patchTypes = map[string]bool{
+	"strategic": false,
+	"merge":     false,
+	"json":      true,
+}
+
+// jsonPatchOps are the operations allowed in a JSON patch (RFC 6902)
+var jsonPatchOps = map[string]bool{
+	"add":     true,
+	"remove":  true,
+	"replace": true,
+	"move":    true,
+	"copy":    true,
+	"test":    true,
+}
+
+// applyPatchBody serializes the patch_body parameter into -p with the --type for patch_type,
+// quoting it so the JSON survives command line splitting intact. The body may be given as an
+// object or array, or as a string holding JSON.
+func applyPatchBody(command, patchType string, body interface{}) (string, error) {
+	if patchType == "" {
+		patchType = "strategic"
+	}
+	isJSONPatch, ok := patchTypes[patchType]
+	if !ok {
+		return "", fmt.Errorf("invalid patch_type '%s': must be strategic, merge or json", patchType)
+	}
+
+	cmdline, err := parseCommandLine(command)
+	if err != nil {
+		return "", err
+	}
+	if cmdline.hasFlag("--patch") || cmdline.hasFlag("--type") {
+		return "", fmt.Errorf("patch_body cannot be combined with -p or --type in args")
+	}
+
+	if text, ok := body.(string); ok {
+		if err := json.Unmarshal([]byte(text), &body); err != nil {
+			return "", fmt.Errorf("patch_body is not valid JSON: %w", err)
+		}
+	}
+	if err := validatePatchBody(body, isJSONPatch); err != nil {
+		return "", err
+	}
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode patch_body: %w", err)
+	}
+	return insertFlags(command, []string{"--type=" + patchType, "-p", shellQuote(string(data))}), nil
+}
+
+// validatePatchBody checks the body has the shape its patch type needs: an object for
+// strategic and merge patches, a list of operations for JSON patches
+func validatePatchBody(body interface{}, isJSONPatch bool) error {
+	if !isJSONPatch {
+		if _, ok := body.(map[string]interface{}); !ok {
+			return fmt.Errorf("patch_body must be an object for strategic and merge patches")
+		}
+		return nil
+	}
+
+	ops, ok := body.([]interface{})
+	if !ok || len(ops) == 0 {
+		return fmt.Errorf("patch_body must be a non-empty array of operations for json patches")
+	}
+	for i, item := range ops {
+		op, ok := item.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("patch_body[%d] must be an object", i)
+		}
+		name, _ := op["op"].(string)
+		if !jsonPatchOps[name] {
+			return fmt.Errorf("patch_body[%d] has invalid op '%v': must be add, remove, replace, move, copy or test", i, op["op"])
+		}
+		if path, _ := op["path"].(string); !strings.HasPrefix(path, "/") {
+			return fmt.Errorf("patch_body[%d] needs a path starting with /", i)
+		}
+		switch name {
+		case "add", "replace", "test":
+			if _, ok := op["value"]; !ok {
+				return fmt.Errorf("patch_body[%d] %s needs a value", i, name)
+			}
+		case "move", "copy":
+			if from, _ := op["from"].(string); !strings.HasPrefix(from, "/") {
+				return fmt.Errorf("patch_body[%d] %s needs a from path starting with /", i, name)
+			}
+		}
+	}
+	return nil
+}
+
+// shellQuote wraps a value in single quotes, escaping any single quotes it contains
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'"'"'`) + "'"
+}
@@ -0,0 +1,184 @@
+package kubectl
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestKubectlToolExecutor_PatchBody(t *testing.T) {
+	tests := []struct {
+		name      string
+		params    map[string]interface{}
+		wantType  string
+		wantPatch string
+		errMsg    string
+	}{
+		{
+			name: "strategic by default",
+			params: map[string]interface{}{
+				"patch_body": map[string]interface{}{"spec": map[string]interface{}{"replicas": float64(3)}},
+			},
+			wantType:  "strategic",
+			wantPatch: `{"spec":{"replicas":3}}`,
+		},
+		{
+			name: "merge",
+			params: map[string]interface{}{
+				"patch_type": "merge",
+				"patch_body": map[string]interface{}{"metadata": map[string]interface{}{"annotations": map[string]interface{}{"note": "it's fine"}}},
+			},
+			wantType:  "merge",
+			wantPatch: `{"metadata":{"annotations":{"note":"it's fine"}}}`,
+		},
+		{
+			name: "json patch array",
+			params: map[string]interface{}{
+				"patch_type": "json",
+				"patch_body": []interface{}{
+					map[string]interface{}{"op": "replace", "path": "/spec/template/spec/containers/0/image", "value": "nginx:1.27"},
+					map[string]interface{}{"op": "remove", "path": "/metadata/labels/canary"},
+				},
+			},
+			wantType:  "json",
+			wantPatch: `[{"op":"replace","path":"/spec/template/spec/containers/0/image","value":"nginx:1.27"},{"op":"remove","path":"/metadata/labels/canary"}]`,
+		},
+		{
+			name:      "json given as a string",
+			params:    map[string]interface{}{"patch_type": "merge", "patch_body": `{"spec":{"paused":true}}`},
+			wantType:  "merge",
+			wantPatch: `{"spec":{"paused":true}}`,
+		},
+		{
+			name:   "invalid json string",
+			params: map[string]interface{}{"patch_body": `{"spec":`},
+			errMsg: "patch_body is not valid JSON",
+		},
+		{
+			name:   "array for a merge patch",
+			params: map[string]interface{}{"patch_type": "merge", "patch_body": []interface{}{}},
+			errMsg: "must be an object",
+		},
+		{
+			name:   "object for a json patch",
+			params: map[string]interface{}{"patch_type": "json", "patch_body": map[string]interface{}{"op": "add"}},
+			errMsg: "must be a non-empty array",
+		},
+		{
+			name: "json patch with unknown op",
+			params: map[string]interface{}{
+				"patch_type": "json",
+				"patch_body": []interface{}{map[string]interface{}{"op": "update", "path": "/spec"}},
+			},
+			errMsg: "invalid op 'update'",
+		},
+		{
+			name: "json patch replace without value",
+			params: map[string]interface{}{
+				"patch_type": "json",
+				"patch_body": []interface{}{map[string]interface{}{"op": "replace", "path": "/spec/replicas"}},
+			},
+			errMsg: "replace needs a value",
+		},
+		{
+			name:   "unknown patch type",
+			params: map[string]interface{}{"patch_type": "apply", "patch_body": map[string]interface{}{}},
+			errMsg: "invalid patch_type 'apply'",
+		},
+		{
+			name:   "patch type without body",
+			params: map[string]interface{}{"patch_type": "merge"},
+			errMsg: "patch_type requires patch_body",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var commands []string
+			worker := newTestWorker(t, func(command string) map[string]interface{} {
+				commands = append(commands, command)
+				return map[string]interface{}{"stdout": "deployment.apps/web patched"}
+			})
+			executor := NewKubectlToolExecutor(worker)
+
+			params := map[string]interface{}{
+				"_tool_name": "kubectl_resources",
+				"operation":  "patch",
+				"resource":   "deployment",
+				"args":       "web -n shop",
+			}
+			for k, v := range tt.params {
+				params[k] = v
+			}
+
+			_, err := executor.Execute(params, newTestConfig("readwrite"))
+			if tt.errMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+					t.Fatalf("Execute() error = %v, want error containing %q", err, tt.errMsg)
+				}
+				if len(commands) != 0 {
+					t.Errorf("rejected patch should not run, got %v", commands)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(commands) != 1 {
+				t.Fatalf("expected one command, got %v", commands)
+			}
+
+			// The patch must come back out of the command line exactly as serialized
+			cmdline, err := parseCommandLine(strings.TrimPrefix(commands[0], "kubectl "))
+			if err != nil {
+				t.Fatalf("command %q does not parse: %v", commands[0], err)
+			}
+			if patchType, _ := cmdline.flag("--type"); patchType != tt.wantType {
+				t.Errorf("--type = %q, want %q in %q", patchType, tt.wantType, commands[0])
+			}
+			patch, _ := cmdline.flag("--patch")
+			var got, want interface{}
+			if err := json.Unmarshal([]byte(patch), &got); err != nil {
+				t.Fatalf("-p %q is not JSON: %v", patch, err)
+			}
+			_ = json.Unmarshal([]byte(tt.wantPatch), &want)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("-p = %s, want %s", patch, tt.wantPatch)
+			}
+			if cmdline.verb() != "patch" || strings.Join(cmdline.args(), " ") != "deployment web" {
+				t.Errorf("unexpected command %q", commands[0])
+			}
+		})
+	}
+
+	t.Run("conflicts with -p in args", func(t *testing.T) {
+		executor := NewKubectlToolExecutor(nil)
+		_, err := executor.Execute(map[string]interface{}{
+			"_tool_name": "kubectl_resources",
+			"operation":  "patch",
+			"resource":   "deployment",
+			"args":       `web -p '{"spec":{}}'`,
+			"patch_body": map[string]interface{}{"spec": map[string]interface{}{}},
+			"echo":       true,
+		}, newTestConfig("readwrite"))
+		if err == nil || !strings.Contains(err.Error(), "cannot be combined") {
+			t.Fatalf("expected conflict error, got %v", err)
+		}
+	})
+
+	t.Run("rejected for apply", func(t *testing.T) {
+		executor := NewKubectlToolExecutor(nil)
+		_, err := executor.Execute(map[string]interface{}{
+			"_tool_name": "kubectl_resources",
+			"operation":  "apply",
+			"resource":   "",
+			"args":       "-f web.yaml",
+			"patch_body": map[string]interface{}{},
+			"echo":       true,
+		}, newTestConfig("readwrite"))
+		if err == nil || !strings.Contains(err.Error(), "patch_body is only supported for patch") {
+			t.Fatalf("expected patch_body rejection, got %v", err)
+		}
+	})
+}
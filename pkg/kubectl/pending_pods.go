@@ -0,0 +1,161 @@
+package kubectl
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Azure/mcp-kubernetes/pkg/config"
+)
+
+// pendingPodsMaxNamespaces bounds the namespaces listed one at a time under a namespace allow-list
+const pendingPodsMaxNamespaces = 50
+
+// pendingPodsReport lists the Pending pods in scope and why each hasn't started
+type pendingPodsReport struct {
+	Scope    string         `json:"scope"`
+	Pending  int            `json:"pending"`
+	ByReason map[string]int `json:"by_reason"`
+	Pods     []pendingPod   `json:"pods"`
+	// Truncated notes allowed namespaces that weren't listed
+	Truncated string `json:"truncated,omitempty"`
+}
+
+// pendingPod is a Pending pod with the scheduler's reason, or the waiting reasons of its
+// containers once it has been scheduled
+type pendingPod struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Reason    string `json:"reason"`
+	Message   string `json:"message,omitempty"`
+	Since     string `json:"since,omitempty"`
+}
+
+// pendingPods lists Pending pods in one namespace (-n) or every namespace the server may
+// read, with their scheduling failures, e.g. "0/5 nodes are available: 5 Insufficient cpu."
+// Under a namespace allow-list each allowed namespace is listed on its own, since
+// --all-namespaces is refused there. Every list is subject to the usual checks.
+func (e *KubectlToolExecutor) pendingPods(args string, cfg *config.ConfigData) (string, error) {
+	cmdline, err := parseCommandLine(args)
+	if err != nil {
+		return "", err
+	}
+	if len(cmdline.positionals) != 0 {
+		return "", fmt.Errorf("pending-pods takes no names; use -n to pick a namespace or -l to select pods")
+	}
+	selector := ""
+	if value, ok := cmdline.flag("--selector"); ok && value != "" {
+		selector = " -l " + shellQuote(value)
+	}
+
+	report := &pendingPodsReport{ByReason: map[string]int{}, Pods: []pendingPod{}}
+	var commands []string
+	namespace, err := namespaceFlag(cmdline, "")
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case namespace != "":
+		report.Scope = "namespace " + namespace
+		commands = append(commands, "get pods -n "+namespace)
+	case cfg.SecurityConfig.HasNamespaceRestrictions():
+		report.Scope = "allowed namespaces"
+		namespaces, err := e.allowedNamespaceNames(cfg)
+		if err != nil {
+			return "", err
+		}
+		if len(namespaces) > pendingPodsMaxNamespaces {
+			report.Truncated = fmt.Sprintf("listed the first %d of %d allowed namespaces; use -n for the others",
+				pendingPodsMaxNamespaces, len(namespaces))
+			namespaces = namespaces[:pendingPodsMaxNamespaces]
+		}
+		for _, name := range namespaces {
+			commands = append(commands, "get pods -n "+name)
+		}
+	default:
+		report.Scope = "all namespaces"
+		commands = append(commands, "get pods --all-namespaces")
+	}
+
+	for _, command := range commands {
+		pods, err := e.listObjects(command+selector+" --field-selector=status.phase=Pending -o json", cfg)
+		if err != nil {
+			return "", err
+		}
+		for _, pod := range pods {
+			entry := describePendingPod(pod)
+			report.Pods = append(report.Pods, entry)
+			report.ByReason[entry.Reason]++
+		}
+	}
+	sort.Slice(report.Pods, func(i, j int) bool {
+		if report.Pods[i].Namespace != report.Pods[j].Namespace {
+			return report.Pods[i].Namespace < report.Pods[j].Namespace
+		}
+		return report.Pods[i].Name < report.Pods[j].Name
+	})
+	report.Pending = len(report.Pods)
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode pending pods: %w", err)
+	}
+	return string(data), nil
+}
+
+// allowedNamespaceNames lists the cluster's namespaces that the allow-list admits, in name order
+func (e *KubectlToolExecutor) allowedNamespaceNames(cfg *config.ConfigData) ([]string, error) {
+	namespaces, err := e.listObjects("get namespaces -o json", cfg)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, namespace := range namespaces {
+		if _, name := objectNamespacedName(namespace); name != "" && cfg.SecurityConfig.IsNamespaceAllowed(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// describePendingPod explains a Pending pod from its PodScheduled condition, or, once it is
+// scheduled, from the waiting reasons of its containers
+func describePendingPod(pod map[string]interface{}) pendingPod {
+	namespace, name := objectNamespacedName(pod)
+	entry := pendingPod{Namespace: namespace, Name: name}
+
+	conditions, _ := nestedValue(pod, "status", "conditions").([]interface{})
+	for _, raw := range conditions {
+		condition, _ := raw.(map[string]interface{})
+		if nestedString(condition, "type") != "PodScheduled" || nestedString(condition, "status") != "False" {
+			continue
+		}
+		entry.Reason = nestedString(condition, "reason")
+		entry.Message = nestedString(condition, "message")
+		entry.Since = nestedString(condition, "lastTransitionTime")
+		if entry.Reason == "" {
+			entry.Reason = "Unschedulable"
+		}
+		return entry
+	}
+
+	if nestedString(pod, "spec", "nodeName") == "" {
+		entry.Reason = "NotScheduled"
+		return entry
+	}
+	entry.Reason = "Scheduled"
+	var waiting []string
+	for _, field := range []string{"initContainerStatuses", "containerStatuses"} {
+		statuses, _ := nestedValue(pod, "status", field).([]interface{})
+		for _, raw := range statuses {
+			status, _ := raw.(map[string]interface{})
+			if reason := nestedString(status, "state", "waiting", "reason"); reason != "" {
+				waiting = append(waiting, nestedString(status, "name")+": "+reason)
+			}
+		}
+	}
+	entry.Message = strings.Join(waiting, "; ")
+	return entry
+}
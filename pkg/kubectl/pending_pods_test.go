@@ -0,0 +1,84 @@
+package kubectl
+
+import (
+	"testing"
+)
+
+const unschedulablePodsJSON = `{
+  "items": [
+    {
+      "metadata": {"name": "web-2", "namespace": "shop"},
+      "spec": {},
+      "status": {
+        "phase": "Pending",
+        "conditions": [
+          {
+            "type": "PodScheduled",
+            "status": "False",
+            "reason": "Unschedulable",
+            "message": "0/5 nodes are available: 5 Insufficient cpu.",
+            "lastTransitionTime": "2025-10-03T10:00:00Z"
+          }
+        ]
+      }
+    },
+    {
+      "metadata": {"name": "api-0", "namespace": "shop"},
+      "spec": {"nodeName": "node-1"},
+      "status": {
+        "phase": "Pending",
+        "conditions": [{"type": "PodScheduled", "status": "True"}],
+        "containerStatuses": [{"name": "api", "state": {"waiting": {"reason": "ContainerCreating"}}}]
+      }
+    }
+  ]
+}`
+
+func TestKubectlToolExecutor_PendingPods(t *testing.T) {
+	restricted := newTestConfig("readonly")
+	restricted.SecurityConfig.SetAllowedNamespaces("shop")
+
+	// Each scope reports the same two pods
+	checkPods := func(scope string) func(t *testing.T, output string, _ []string) {
+		return func(t *testing.T, output string, _ []string) {
+			report := decodeReport[pendingPodsReport](t, output)
+			if report.Scope != scope || report.Pending != 2 || len(report.Pods) != 2 {
+				t.Fatalf("unexpected report: %+v", report)
+			}
+			api, web := report.Pods[0], report.Pods[1]
+			if web.Name != "web-2" || web.Reason != "Unschedulable" ||
+				web.Message != "0/5 nodes are available: 5 Insufficient cpu." || web.Since != "2025-10-03T10:00:00Z" {
+				t.Errorf("unexpected unschedulable pod: %+v", web)
+			}
+			if api.Name != "api-0" || api.Reason != "Scheduled" || api.Message != "api: ContainerCreating" {
+				t.Errorf("unexpected scheduled pod: %+v", api)
+			}
+			if report.ByReason["Unschedulable"] != 1 || report.ByReason["Scheduled"] != 1 {
+				t.Errorf("unexpected reason counts: %v", report.ByReason)
+			}
+		}
+	}
+
+	runDiagnosticCases(t, "pending-pods", newTestConfig("readonly"), map[string]string{
+		"kubectl get pods -n shop --field-selector=status.phase=Pending -o json":              unschedulablePodsJSON,
+		"kubectl get pods --all-namespaces --field-selector=status.phase=Pending -o json":     unschedulablePodsJSON,
+		"kubectl get pods -n shop -l 'app=web' --field-selector=status.phase=Pending -o json": `{"items": []}`,
+		"kubectl get namespaces -o json":                                                      `{"items": [{"metadata": {"name": "shop"}}, {"metadata": {"name": "kube-system"}}]}`,
+	}, []diagnosticCase{
+		{name: "one namespace", args: "-n shop", check: checkPods("namespace shop")},
+		{name: "all namespaces", args: "", check: checkPods("all namespaces")},
+		{name: "allowed namespaces", args: "", cfg: restricted, check: checkPods("allowed namespaces")},
+		{
+			name: "selector quoted, nothing pending",
+			args: "-n shop -l app=web",
+			check: func(t *testing.T, output string, _ []string) {
+				if report := decodeReport[pendingPodsReport](t, output); report.Pending != 0 || report.Pods == nil {
+					t.Errorf("expected an empty report, got %+v", report)
+				}
+			},
+		},
+		{name: "namespace carrying flags refused", args: "-n 'shop --as=admin'", refused: "invalid namespace"},
+		{name: "names refused", args: "web-2 -n shop", refused: "takes no names"},
+		{name: "namespace outside the allow-list", args: "-n kube-system", cfg: restricted, refused: "kube-system"},
+	})
+}
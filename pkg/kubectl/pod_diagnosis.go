@@ -0,0 +1,226 @@
+package kubectl
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Azure/mcp-kubernetes/pkg/config"
+)
+
+// podDiagnosis is a concise account of why a pod is failing
+type podDiagnosis struct {
+	Name       string               `json:"name"`
+	Namespace  string               `json:"namespace"`
+	Phase      string               `json:"phase"`
+	Node       string               `json:"node,omitempty"`
+	Healthy    bool                 `json:"healthy"`
+	Findings   []string             `json:"findings"`
+	Containers []containerDiagnosis `json:"containers"`
+}
+
+// containerDiagnosis is the state of one container and its last termination
+type containerDiagnosis struct {
+	Name            string             `json:"name"`
+	Init            bool               `json:"init,omitempty"`
+	Ready           bool               `json:"ready"`
+	Restarts        int64              `json:"restarts"`
+	State           string             `json:"state"`
+	Reason          string             `json:"reason,omitempty"`
+	Message         string             `json:"message,omitempty"`
+	ExitCode        *int64             `json:"exit_code,omitempty"`
+	LastTermination *terminationRecord `json:"last_termination,omitempty"`
+	MemoryLimit     string             `json:"memory_limit,omitempty"`
+}
+
+// terminationRecord is how a container last exited
+type terminationRecord struct {
+	Reason     string `json:"reason,omitempty"`
+	ExitCode   int64  `json:"exit_code"`
+	FinishedAt string `json:"finished_at,omitempty"`
+}
+
+// failingWaitReasons are waiting reasons that mean the container can't start on its own
+var failingWaitReasons = map[string]bool{
+	"CrashLoopBackOff":           true,
+	"ImagePullBackOff":           true,
+	"ErrImagePull":               true,
+	"InvalidImageName":           true,
+	"CreateContainerConfigError": true,
+	"CreateContainerError":       true,
+	"RunContainerError":          true,
+}
+
+// podDiagnosis fetches a pod and reports failing containers: OOM kills, crash loops, image
+// pull failures, exit codes and restart counts. The lookup goes through the same access and
+// namespace checks as a direct get.
+func (e *KubectlToolExecutor) podDiagnosis(args string, cfg *config.ConfigData) (string, error) {
+	cmdline, err := parseCommandLine(args)
+	if err != nil {
+		return "", err
+	}
+	if len(cmdline.positionals) != 1 {
+		return "", fmt.Errorf("pod-diagnosis requires exactly one pod name")
+	}
+	name := strings.TrimPrefix(cmdline.positionals[0], "pod/")
+	if !objectName.MatchString(name) {
+		return "", fmt.Errorf("invalid pod name '%s'", name)
+	}
+	namespace, err := namespaceFlag(cmdline, "default")
+	if err != nil {
+		return "", err
+	}
+
+	pod, err := e.getObject(fmt.Sprintf("get pods %s -n %s -o json", name, namespace), cfg)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := json.MarshalIndent(diagnosePod(pod), "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode pod diagnosis: %w", err)
+	}
+	return string(data), nil
+}
+
+// diagnosePod inspects the container statuses of a decoded pod
+func diagnosePod(pod map[string]interface{}) podDiagnosis {
+	namespace, name := objectNamespacedName(pod)
+	diagnosis := podDiagnosis{
+		Name:       name,
+		Namespace:  namespace,
+		Phase:      nestedString(pod, "status", "phase"),
+		Node:       nestedString(pod, "spec", "nodeName"),
+		Findings:   []string{},
+		Containers: []containerDiagnosis{},
+	}
+
+	limits := containerMemoryLimits(pod)
+	for _, section := range []struct {
+		statuses string
+		init     bool
+	}{{"initContainerStatuses", true}, {"containerStatuses", false}} {
+		statuses, _ := nestedValue(pod, "status", section.statuses).([]interface{})
+		for _, raw := range statuses {
+			status, _ := raw.(map[string]interface{})
+			container := diagnoseContainer(status, section.init)
+			container.MemoryLimit = limits[container.Name]
+			diagnosis.Containers = append(diagnosis.Containers, container)
+			diagnosis.Findings = append(diagnosis.Findings, containerFindings(container)...)
+		}
+	}
+
+	// Pods that never got a node have no container statuses to explain them
+	if len(diagnosis.Containers) == 0 {
+		diagnosis.Findings = append(diagnosis.Findings, unscheduledFindings(pod)...)
+	}
+	if diagnosis.Phase == "Failed" {
+		if reason := nestedString(pod, "status", "reason"); reason != "" {
+			diagnosis.Findings = append(diagnosis.Findings, fmt.Sprintf("pod failed: %s %s", reason, nestedString(pod, "status", "message")))
+		}
+	}
+
+	diagnosis.Healthy = len(diagnosis.Findings) == 0
+	return diagnosis
+}
+
+// diagnoseContainer reads the current state and last termination of a container status
+func diagnoseContainer(status map[string]interface{}, init bool) containerDiagnosis {
+	container := containerDiagnosis{
+		Name:     nestedString(status, "name"),
+		Init:     init,
+		Restarts: nestedInt(status, "restartCount"),
+	}
+	container.Ready, _ = status["ready"].(bool)
+
+	for _, state := range []string{"waiting", "running", "terminated"} {
+		detail, ok := nestedValue(status, "state", state).(map[string]interface{})
+		if !ok {
+			continue
+		}
+		container.State = state
+		container.Reason = nestedString(detail, "reason")
+		container.Message = nestedString(detail, "message")
+		if state == "terminated" {
+			code := nestedInt(detail, "exitCode")
+			container.ExitCode = &code
+		}
+		break
+	}
+
+	if last, ok := nestedValue(status, "lastState", "terminated").(map[string]interface{}); ok {
+		container.LastTermination = &terminationRecord{
+			Reason:     nestedString(last, "reason"),
+			ExitCode:   nestedInt(last, "exitCode"),
+			FinishedAt: nestedString(last, "finishedAt"),
+		}
+	}
+	return container
+}
+
+// containerFindings describes what is wrong with a container, if anything
+func containerFindings(c containerDiagnosis) []string {
+	var findings []string
+	label := "container " + c.Name
+	if c.Init {
+		label = "init container " + c.Name
+	}
+
+	if c.State == "waiting" && failingWaitReasons[c.Reason] {
+		finding := fmt.Sprintf("%s is in %s", label, c.Reason)
+		if c.Message != "" {
+			finding += ": " + c.Message
+		}
+		findings = append(findings, finding)
+	}
+
+	terminated := c.LastTermination
+	if c.State == "terminated" && c.ExitCode != nil && (*c.ExitCode != 0 || c.Reason == "OOMKilled") {
+		terminated = &terminationRecord{Reason: c.Reason, ExitCode: *c.ExitCode}
+	}
+	if terminated != nil && (terminated.Reason == "OOMKilled" || terminated.ExitCode != 0) {
+		finding := fmt.Sprintf("%s last exited with code %d", label, terminated.ExitCode)
+		if terminated.Reason == "OOMKilled" {
+			finding = fmt.Sprintf("%s was OOMKilled (exit code %d)", label, terminated.ExitCode)
+			if c.MemoryLimit != "" {
+				finding += " with memory limit " + c.MemoryLimit
+			}
+		} else if terminated.Reason != "" {
+			finding += " (" + terminated.Reason + ")"
+		}
+		findings = append(findings, finding)
+	}
+
+	if c.Restarts > 0 && len(findings) > 0 {
+		findings[len(findings)-1] += fmt.Sprintf("; restarted %d times", c.Restarts)
+	}
+	return findings
+}
+
+// containerMemoryLimits maps container names to their memory limit
+func containerMemoryLimits(pod map[string]interface{}) map[string]string {
+	limits := make(map[string]string)
+	for _, field := range []string{"initContainers", "containers"} {
+		containers, _ := nestedValue(pod, "spec", field).([]interface{})
+		for _, raw := range containers {
+			container, _ := raw.(map[string]interface{})
+			if limit := nestedString(container, "resources", "limits", "memory"); limit != "" {
+				limits[nestedString(container, "name")] = limit
+			}
+		}
+	}
+	return limits
+}
+
+// unscheduledFindings reports a PodScheduled condition that is false
+func unscheduledFindings(pod map[string]interface{}) []string {
+	conditions, _ := nestedValue(pod, "status", "conditions").([]interface{})
+	for _, raw := range conditions {
+		condition, _ := raw.(map[string]interface{})
+		if nestedString(condition, "type") == "PodScheduled" && nestedString(condition, "status") == "False" {
+			return []string{fmt.Sprintf("pod is not scheduled (%s): %s",
+				nestedString(condition, "reason"), nestedString(condition, "message"))}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package kubectl
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestKubectlToolExecutor_PodDiagnosisOOMKilled(t *testing.T) {
+	pod := `{
+		"metadata": {"name": "web-0", "namespace": "shop"},
+		"spec": {"nodeName": "node-1", "containers": [
+			{"name": "app", "resources": {"limits": {"memory": "256Mi"}}},
+			{"name": "sidecar"}]},
+		"status": {"phase": "Running", "containerStatuses": [
+			{"name": "app", "ready": false, "restartCount": 4,
+				"state": {"waiting": {"reason": "CrashLoopBackOff", "message": "back-off 1m20s restarting failed container"}},
+				"lastState": {"terminated": {"reason": "OOMKilled", "exitCode": 137, "finishedAt": "2025-01-02T00:05:00Z"}}},
+			{"name": "sidecar", "ready": true, "restartCount": 0, "state": {"running": {"startedAt": "2025-01-02T00:00:00Z"}}}]}}`
+
+	worker := newTestWorker(t, func(command string) map[string]interface{} {
+		if command != "kubectl get pods web-0 -n shop -o json" {
+			return map[string]interface{}{"error": "unexpected command: " + command}
+		}
+		return map[string]interface{}{"stdout": pod}
+	})
+	executor := NewKubectlToolExecutor(worker)
+
+	result, err := executor.Execute(map[string]interface{}{
+		"_tool_name": "kubectl_diagnostics",
+		"operation":  "pod-diagnosis",
+		"resource":   "",
+		"args":       "pod/web-0 -n shop",
+	}, newTestConfig("readonly"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var diagnosis podDiagnosis
+	if err := json.Unmarshal([]byte(result), &diagnosis); err != nil {
+		t.Fatalf("result is not a diagnosis: %v\n%s", err, result)
+	}
+	if diagnosis.Healthy || diagnosis.Name != "web-0" || diagnosis.Node != "node-1" {
+		t.Errorf("unexpected pod summary: %+v", diagnosis)
+	}
+	if len(diagnosis.Containers) != 2 {
+		t.Fatalf("expected 2 containers, got %+v", diagnosis.Containers)
+	}
+
+	app := diagnosis.Containers[0]
+	if app.State != "waiting" || app.Reason != "CrashLoopBackOff" || app.Restarts != 4 || app.MemoryLimit != "256Mi" {
+		t.Errorf("unexpected app container: %+v", app)
+	}
+	if app.LastTermination == nil || app.LastTermination.Reason != "OOMKilled" || app.LastTermination.ExitCode != 137 {
+		t.Errorf("expected OOMKilled last termination, got %+v", app.LastTermination)
+	}
+
+	expected := []string{
+		"container app is in CrashLoopBackOff: back-off 1m20s restarting failed container",
+		"container app was OOMKilled (exit code 137) with memory limit 256Mi; restarted 4 times",
+	}
+	if strings.Join(diagnosis.Findings, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("unexpected findings:\n%s", strings.Join(diagnosis.Findings, "\n"))
+	}
+}
+
+func TestKubectlToolExecutor_PodDiagnosisNamespacePolicy(t *testing.T) {
+	worker := newTestWorker(t, func(command string) map[string]interface{} {
+		t.Errorf("no command should reach the agent, got %q", command)
+		return map[string]interface{}{"stdout": "{}"}
+	})
+	executor := NewKubectlToolExecutor(worker)
+
+	cfg := newTestConfig("readonly")
+	cfg.SecurityConfig.SetAllowedNamespaces("shop")
+
+	for args, refused := range map[string]string{
+		"etcd-0 -n kube-system":      "kube-system",
+		"web-0 -n 'shop --as=admin'": "invalid namespace",
+		"'web-0 --as=admin' -n shop": "invalid pod name",
+	} {
+		_, err := executor.Execute(map[string]interface{}{
+			"_tool_name": "kubectl_diagnostics",
+			"operation":  "pod-diagnosis",
+			"resource":   "",
+			"args":       args,
+		}, cfg)
+		if err == nil || !strings.Contains(err.Error(), refused) {
+			t.Errorf("expected %q to be refused with %q, got %v", args, refused, err)
+		}
+	}
+}
@@ -0,0 +1,217 @@
+package kubectl
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Azure/mcp-kubernetes/pkg/config"
+)
+
+// probeKinds maps accepted resource names to the kind that is fetched
+var probeKinds = map[string]string{
+	"pod":          "pods",
+	"pods":         "pods",
+	"po":           "pods",
+	"deployment":   "deployments",
+	"deployments":  "deployments",
+	"deploy":       "deployments",
+	"statefulset":  "statefulsets",
+	"statefulsets": "statefulsets",
+	"sts":          "statefulsets",
+	"daemonset":    "daemonsets",
+	"daemonsets":   "daemonsets",
+	"ds":           "daemonsets",
+	"replicaset":   "replicasets",
+	"replicasets":  "replicasets",
+	"rs":           "replicasets",
+	"job":          "jobs",
+	"jobs":         "jobs",
+	"cronjob":      "cronjobs",
+	"cronjobs":     "cronjobs",
+	"cj":           "cronjobs",
+}
+
+// probeTypes are the probe fields of a container spec and the names they are reported under
+var probeTypes = []struct {
+	field string
+	name  string
+}{
+	{field: "livenessProbe", name: "liveness"},
+	{field: "readinessProbe", name: "readiness"},
+	{field: "startupProbe", name: "startup"},
+}
+
+// probesReport lists the probes of each container of a pod or workload template
+type probesReport struct {
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	// Controller is the kind and name of the object that owns a pod, if any
+	Controller string            `json:"controller,omitempty"`
+	Containers []containerProbes `json:"containers"`
+}
+
+// containerProbes holds the probes of one container; init containers are listed only with probes
+type containerProbes struct {
+	Name      string       `json:"name"`
+	Init      bool         `json:"init,omitempty"`
+	Liveness  *probeConfig `json:"liveness,omitempty"`
+	Readiness *probeConfig `json:"readiness,omitempty"`
+	Startup   *probeConfig `json:"startup,omitempty"`
+}
+
+// probeConfig is a probe's check and timings, with Kubernetes' defaults filled in for unset
+// fields, e.g. "HTTP GET :8080/healthz" every 10s with a 1s timeout
+type probeConfig struct {
+	Handler             string `json:"handler"`
+	InitialDelaySeconds int64  `json:"initial_delay_seconds"`
+	PeriodSeconds       int64  `json:"period_seconds"`
+	TimeoutSeconds      int64  `json:"timeout_seconds"`
+	SuccessThreshold    int64  `json:"success_threshold"`
+	FailureThreshold    int64  `json:"failure_threshold"`
+}
+
+// probes fetches a pod, or a workload and its pod template, with a read-only get and returns
+// the liveness, readiness and startup probes of its containers. Args name a pod, or a
+// workload as TYPE/NAME or TYPE NAME. The lookup goes through the same access and namespace
+// checks as a direct get.
+func (e *KubectlToolExecutor) probes(args string, cfg *config.ConfigData) (string, error) {
+	cmdline, err := parseCommandLine(args)
+	if err != nil {
+		return "", err
+	}
+	resource, name := "pod", ""
+	switch len(cmdline.positionals) {
+	case 1:
+		name = cmdline.positionals[0]
+		if kind, objName, ok := strings.Cut(name, "/"); ok {
+			resource, name = kind, objName
+		}
+	case 2:
+		resource, name = cmdline.positionals[0], cmdline.positionals[1]
+	default:
+		return "", fmt.Errorf("probes requires one pod name, or a workload as TYPE/NAME")
+	}
+	kind, ok := probeKinds[strings.ToLower(resource)]
+	if !ok || name == "" {
+		return "", fmt.Errorf("probes supports pods, deployments, statefulsets, daemonsets, replicasets, jobs and cronjobs, got '%s'", resource)
+	}
+	if !objectName.MatchString(name) {
+		return "", fmt.Errorf("invalid %s name '%s'", strings.TrimSuffix(kind, "s"), name)
+	}
+	namespace, err := namespaceFlag(cmdline, "default")
+	if err != nil {
+		return "", err
+	}
+
+	obj, err := e.getObject(fmt.Sprintf("get %s %s -n %s -o json", kind, name, namespace), cfg)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := json.MarshalIndent(extractProbes(kind, obj), "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode probes: %w", err)
+	}
+	return string(data), nil
+}
+
+// extractProbes builds the probes report from a fetched object of the given kind
+func extractProbes(kind string, obj map[string]interface{}) *probesReport {
+	objNamespace, objName := objectNamespacedName(obj)
+	report := &probesReport{
+		Kind:       strings.TrimSuffix(kind, "s"),
+		Name:       objName,
+		Namespace:  objNamespace,
+		Containers: []containerProbes{},
+	}
+
+	var podSpec map[string]interface{}
+	switch kind {
+	case "pods":
+		podSpec, _ = obj["spec"].(map[string]interface{})
+		if refs := ownerReferences(obj); len(refs) > 0 {
+			report.Controller = nestedString(refs[0], "kind") + "/" + nestedString(refs[0], "name")
+		}
+	case "cronjobs":
+		podSpec, _ = nestedValue(obj, "spec", "jobTemplate", "spec", "template", "spec").(map[string]interface{})
+	default:
+		podSpec, _ = nestedValue(obj, "spec", "template", "spec").(map[string]interface{})
+	}
+
+	for _, field := range []string{"initContainers", "containers"} {
+		containers, _ := podSpec[field].([]interface{})
+		for _, raw := range containers {
+			container, _ := raw.(map[string]interface{})
+			entry := containerProbes{Name: nestedString(container, "name"), Init: field == "initContainers"}
+			for _, probeType := range probeTypes {
+				probe, ok := container[probeType.field].(map[string]interface{})
+				if !ok {
+					continue
+				}
+				summary := describeProbe(probe)
+				switch probeType.name {
+				case "liveness":
+					entry.Liveness = summary
+				case "readiness":
+					entry.Readiness = summary
+				case "startup":
+					entry.Startup = summary
+				}
+			}
+			if entry.Init && entry.Liveness == nil && entry.Readiness == nil && entry.Startup == nil {
+				continue
+			}
+			report.Containers = append(report.Containers, entry)
+		}
+	}
+	return report
+}
+
+// describeProbe summarizes a probe's handler and timings, using Kubernetes' defaults for
+// unset timings
+func describeProbe(probe map[string]interface{}) *probeConfig {
+	summary := &probeConfig{
+		InitialDelaySeconds: probeInt(probe, "initialDelaySeconds", 0),
+		PeriodSeconds:       probeInt(probe, "periodSeconds", 10),
+		TimeoutSeconds:      probeInt(probe, "timeoutSeconds", 1),
+		SuccessThreshold:    probeInt(probe, "successThreshold", 1),
+		FailureThreshold:    probeInt(probe, "failureThreshold", 3),
+	}
+
+	switch {
+	case probe["httpGet"] != nil:
+		scheme := strings.ToUpper(nestedString(probe, "httpGet", "scheme"))
+		if scheme == "" {
+			scheme = "HTTP"
+		}
+		summary.Handler = fmt.Sprintf("%s GET %s:%v%s", scheme, nestedString(probe, "httpGet", "host"),
+			nestedValue(probe, "httpGet", "port"), nestedString(probe, "httpGet", "path"))
+	case probe["tcpSocket"] != nil:
+		summary.Handler = fmt.Sprintf("TCP %s:%v", nestedString(probe, "tcpSocket", "host"), nestedValue(probe, "tcpSocket", "port"))
+	case probe["grpc"] != nil:
+		summary.Handler = fmt.Sprintf("gRPC :%v", nestedValue(probe, "grpc", "port"))
+		if service := nestedString(probe, "grpc", "service"); service != "" {
+			summary.Handler += " service=" + service
+		}
+	case probe["exec"] != nil:
+		command, _ := nestedValue(probe, "exec", "command").([]interface{})
+		parts := make([]string, 0, len(command))
+		for _, part := range command {
+			parts = append(parts, fmt.Sprint(part))
+		}
+		summary.Handler = "exec " + strings.Join(parts, " ")
+	default:
+		summary.Handler = "unknown"
+	}
+	return summary
+}
+
+// probeInt returns a probe's integer field, or the default when it is unset
+func probeInt(probe map[string]interface{}, field string, defaultValue int64) int64 {
+	if value, ok := probe[field].(float64); ok {
+		return int64(value)
+	}
+	return defaultValue
+}
@@ -0,0 +1,90 @@
+package kubectl
+
+import (
+	"testing"
+)
+
+const probedPodJSON = `{
+  "metadata": {
+    "name": "web-0",
+    "namespace": "shop",
+    "ownerReferences": [{"kind": "StatefulSet", "name": "web", "controller": true}]
+  },
+  "spec": {
+    "initContainers": [{"name": "migrate", "image": "migrate:1"}],
+    "containers": [
+      {
+        "name": "web",
+        "livenessProbe": {
+          "httpGet": {"path": "/healthz", "port": 8080},
+          "initialDelaySeconds": 15,
+          "periodSeconds": 20,
+          "failureThreshold": 5
+        },
+        "readinessProbe": {"tcpSocket": {"port": "http"}, "timeoutSeconds": 3},
+        "startupProbe": {"exec": {"command": ["cat", "/tmp/started"]}}
+      },
+      {"name": "metrics", "readinessProbe": {"grpc": {"port": 9090}}}
+    ]
+  }
+}`
+
+func TestKubectlToolExecutor_Probes(t *testing.T) {
+	cfg := newTestConfig("readonly")
+	cfg.SecurityConfig.SetAllowedNamespaces("shop")
+
+	runDiagnosticCases(t, "probes", cfg, map[string]string{
+		"kubectl get pods web-0 -n shop -o json": probedPodJSON,
+		"kubectl get cronjobs nightly -n shop -o json": `{
+  "metadata": {"name": "nightly", "namespace": "shop"},
+  "spec": {"jobTemplate": {"spec": {"template": {"spec": {"containers": [{"name": "report"}]}}}}}
+}`,
+	}, []diagnosticCase{
+		{
+			name: "pod",
+			args: "web-0 -n shop",
+			check: func(t *testing.T, output string, _ []string) {
+				report := decodeReport[probesReport](t, output)
+				if report.Kind != "pod" || report.Name != "web-0" || report.Namespace != "shop" || report.Controller != "StatefulSet/web" {
+					t.Errorf("unexpected report: %+v", report)
+				}
+				if len(report.Containers) != 2 {
+					t.Fatalf("expected the two app containers, without the init container that has no probes, got %+v", report.Containers)
+				}
+
+				web := report.Containers[0]
+				expectedLiveness := probeConfig{Handler: "HTTP GET :8080/healthz", InitialDelaySeconds: 15, PeriodSeconds: 20, TimeoutSeconds: 1, SuccessThreshold: 1, FailureThreshold: 5}
+				if web.Liveness == nil || *web.Liveness != expectedLiveness {
+					t.Errorf("expected liveness %+v, got %+v", expectedLiveness, web.Liveness)
+				}
+				if web.Readiness == nil || web.Readiness.Handler != "TCP :http" || web.Readiness.TimeoutSeconds != 3 || web.Readiness.PeriodSeconds != 10 {
+					t.Errorf("unexpected readiness: %+v", web.Readiness)
+				}
+				if web.Startup == nil || web.Startup.Handler != "exec cat /tmp/started" {
+					t.Errorf("unexpected startup: %+v", web.Startup)
+				}
+				metrics := report.Containers[1]
+				if metrics.Liveness != nil || metrics.Startup != nil || metrics.Readiness == nil || metrics.Readiness.Handler != "gRPC :9090" {
+					t.Errorf("unexpected metrics probes: %+v", metrics)
+				}
+			},
+		},
+		{
+			// A workload's pod template is read from its own path
+			name: "cronjob template",
+			args: "cronjob/nightly -n shop",
+			check: func(t *testing.T, output string, _ []string) {
+				report := decodeReport[probesReport](t, output)
+				if report.Kind != "cronjob" || len(report.Containers) != 1 || report.Containers[0].Name != "report" || report.Containers[0].Liveness != nil {
+					t.Errorf("unexpected cronjob report: %+v", report)
+				}
+			},
+		},
+		{name: "type and name as two arguments", args: "pods web-0 -n shop"},
+		{name: "namespace carrying flags refused", args: "web-0 -n 'shop --as=admin'", refused: "invalid namespace"},
+		{name: "name carrying flags refused", args: "'web-0 --as=admin' -n shop", refused: "invalid pod name"},
+		{name: "unsupported kind refused", args: "service/web -n shop", refused: "probes supports"},
+		{name: "no name refused", args: "-n shop", refused: "requires one pod name"},
+		{name: "namespace outside the allow-list", args: "deployment/coredns -n kube-system", refused: "kube-system"},
+	})
+}
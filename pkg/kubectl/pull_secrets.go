@@ -0,0 +1,192 @@
+package kubectl
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Azure/mcp-kubernetes/pkg/config"
+)
+
+// pullFailureReasons are the waiting reasons of containers whose image can't be pulled
+var pullFailureReasons = map[string]bool{
+	"ImagePullBackOff": true,
+	"ErrImagePull":     true,
+}
+
+// pullSecretTypes are the secret types the kubelet reads registry credentials from
+var pullSecretTypes = map[string]bool{
+	"kubernetes.io/dockerconfigjson": true,
+	"kubernetes.io/dockercfg":        true,
+}
+
+// pullSecretsReport diagnoses the pods of a namespace that can't pull their images
+type pullSecretsReport struct {
+	Namespace string `json:"namespace"`
+	// SecretsChecked is false when --deny-resources covers secrets, so only the references are
+	// reported
+	SecretsChecked bool            `json:"secrets_checked"`
+	Pods           []pullFailedPod `json:"pods"`
+}
+
+// pullFailedPod is a pod with containers stuck pulling, and the pull secrets it references
+type pullFailedPod struct {
+	Name        string          `json:"name"`
+	Images      []failedPull    `json:"images"`
+	PullSecrets []pullSecretRef `json:"pull_secrets"`
+	Diagnosis   string          `json:"diagnosis"`
+}
+
+// failedPull is a container whose image can't be pulled
+type failedPull struct {
+	Container string `json:"container"`
+	Image     string `json:"image"`
+	Reason    string `json:"reason"`
+	Message   string `json:"message,omitempty"`
+}
+
+// pullSecretRef is an imagePullSecrets entry and what was found for it
+type pullSecretRef struct {
+	Name   string `json:"name"`
+	Exists *bool  `json:"exists,omitempty"`
+	Type   string `json:"type,omitempty"`
+}
+
+// pullSecrets finds the pods of a namespace (-n, optionally narrowed with -l) whose containers
+// are in ImagePullBackOff or ErrImagePull, and checks the secrets in their imagePullSecrets,
+// which include those of the service account, exist and hold registry credentials. Secrets
+// are listed by name and type only; when --deny-resources covers secrets they aren't listed
+// at all.
+func (e *KubectlToolExecutor) pullSecrets(args string, cfg *config.ConfigData) (string, error) {
+	cmdline, err := parseCommandLine(args)
+	if err != nil {
+		return "", err
+	}
+	namespace, err := namespaceFlag(cmdline, "")
+	if err != nil {
+		return "", err
+	}
+	if namespace == "" || len(cmdline.positionals) != 0 {
+		return "", fmt.Errorf("pull-secrets requires a namespace (-n) and takes no names; use -l to select pods")
+	}
+	command := "get pods -n " + namespace + " -o json"
+	if selector, ok := cmdline.flag("--selector"); ok && selector != "" {
+		command += " -l " + shellQuote(selector)
+	}
+
+	pods, err := e.listObjects(command, cfg)
+	if err != nil {
+		return "", err
+	}
+	report := &pullSecretsReport{Namespace: namespace, Pods: []pullFailedPod{}}
+	for _, pod := range pods {
+		if failed := failedPulls(pod); len(failed) > 0 {
+			_, name := objectNamespacedName(pod)
+			report.Pods = append(report.Pods, pullFailedPod{Name: name, Images: failed, PullSecrets: podPullSecrets(pod)})
+		}
+	}
+	sort.Slice(report.Pods, func(i, j int) bool { return report.Pods[i].Name < report.Pods[j].Name })
+
+	var secretTypes map[string]string
+	if len(report.Pods) > 0 && !cfg.SecurityConfig.IsResourceDenied("secrets") {
+		if secretTypes, err = e.secretTypes(namespace, cfg); err != nil {
+			return "", err
+		}
+		report.SecretsChecked = true
+	}
+	for i := range report.Pods {
+		pod := &report.Pods[i]
+		for j := range pod.PullSecrets {
+			if secretTypes == nil {
+				continue
+			}
+			secretType, exists := secretTypes[pod.PullSecrets[j].Name]
+			pod.PullSecrets[j].Exists = &exists
+			pod.PullSecrets[j].Type = secretType
+		}
+		pod.Diagnosis = diagnosePullSecrets(*pod, namespace, report.SecretsChecked)
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode pull secret diagnosis: %w", err)
+	}
+	return string(data), nil
+}
+
+// secretTypes maps the names of a namespace's secrets to their types, without reading any
+// secret data
+func (e *KubectlToolExecutor) secretTypes(namespace string, cfg *config.ConfigData) (map[string]string, error) {
+	output, err := e.runReadCommand("get secrets -n "+namespace+" --no-headers -o "+shellQuote("custom-columns=NAME:.metadata.name,TYPE:.type"), cfg)
+	if err != nil {
+		return nil, err
+	}
+	types := map[string]string{}
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 {
+			types[fields[0]] = fields[1]
+		}
+	}
+	return types, nil
+}
+
+// failedPulls returns the init and app containers of a pod waiting on an image pull
+func failedPulls(pod map[string]interface{}) []failedPull {
+	var failed []failedPull
+	for _, key := range []string{"initContainerStatuses", "containerStatuses"} {
+		statuses, _ := nestedValue(pod, "status", key).([]interface{})
+		for _, raw := range statuses {
+			status, _ := raw.(map[string]interface{})
+			reason := nestedString(status, "state", "waiting", "reason")
+			if !pullFailureReasons[reason] {
+				continue
+			}
+			failed = append(failed, failedPull{
+				Container: nestedString(status, "name"),
+				Image:     nestedString(status, "image"),
+				Reason:    reason,
+				Message:   nestedString(status, "state", "waiting", "message"),
+			})
+		}
+	}
+	return failed
+}
+
+// podPullSecrets returns the imagePullSecrets of a pod
+func podPullSecrets(pod map[string]interface{}) []pullSecretRef {
+	refs := []pullSecretRef{}
+	secrets, _ := nestedValue(pod, "spec", "imagePullSecrets").([]interface{})
+	for _, raw := range secrets {
+		secret, _ := raw.(map[string]interface{})
+		if name := nestedString(secret, "name"); name != "" {
+			refs = append(refs, pullSecretRef{Name: name})
+		}
+	}
+	return refs
+}
+
+// diagnosePullSecrets explains a pod's pull failure in terms of its pull secrets
+func diagnosePullSecrets(pod pullFailedPod, namespace string, checked bool) string {
+	if len(pod.PullSecrets) == 0 {
+		return "no imagePullSecrets: images from a private registry need a pull secret on the pod or its service account"
+	}
+	if !checked {
+		return "imagePullSecrets not checked: secrets are denied by security configuration"
+	}
+
+	var problems []string
+	for _, ref := range pod.PullSecrets {
+		switch {
+		case ref.Exists != nil && !*ref.Exists:
+			problems = append(problems, fmt.Sprintf("imagePullSecret '%s' does not exist in namespace %s", ref.Name, namespace))
+		case !pullSecretTypes[ref.Type]:
+			problems = append(problems, fmt.Sprintf("imagePullSecret '%s' has type %s, not kubernetes.io/dockerconfigjson", ref.Name, ref.Type))
+		}
+	}
+	if len(problems) > 0 {
+		return strings.Join(problems, "; ")
+	}
+	return "imagePullSecrets exist with registry credentials: check they are valid for the image's registry and that the image and tag exist"
+}
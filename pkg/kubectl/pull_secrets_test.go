@@ -0,0 +1,104 @@
+package kubectl
+
+import (
+	"strings"
+	"testing"
+)
+
+const pullFailingPodsJSON = `{
+  "items": [
+    {
+      "metadata": {"name": "web-0", "namespace": "shop"},
+      "spec": {"imagePullSecrets": [{"name": "registry-creds"}, {"name": "old-token"}]},
+      "status": {
+        "containerStatuses": [{
+          "name": "web",
+          "image": "registry.example.com/shop/web:1.4",
+          "state": {"waiting": {"reason": "ImagePullBackOff", "message": "Back-off pulling image \"registry.example.com/shop/web:1.4\""}}
+        }]
+      }
+    },
+    {
+      "metadata": {"name": "api-0", "namespace": "shop"},
+      "status": {
+        "initContainerStatuses": [{
+          "name": "migrate",
+          "image": "registry.example.com/shop/migrate:2",
+          "state": {"waiting": {"reason": "ErrImagePull"}}
+        }]
+      }
+    },
+    {
+      "metadata": {"name": "cache-0", "namespace": "shop"},
+      "spec": {"imagePullSecrets": [{"name": "registry-creds"}]},
+      "status": {"containerStatuses": [{"name": "cache", "image": "redis:7", "state": {"running": {}}}]}
+    }
+  ]
+}`
+
+func TestKubectlToolExecutor_PullSecrets(t *testing.T) {
+	secretsDenied := newTestConfig("readonly")
+	secretsDenied.SecurityConfig.SetDeniedResources("secrets")
+
+	runDiagnosticCases(t, "pull-secrets", newTestConfig("readonly"), map[string]string{
+		"kubectl get pods -n shop -o json": pullFailingPodsJSON,
+		"kubectl get secrets -n shop --no-headers -o 'custom-columns=NAME:.metadata.name,TYPE:.type'": "default-token   kubernetes.io/service-account-token\nold-token       Opaque\n",
+		"kubectl get pods -n shop -o json -l 'app=cache'":                                             `{"items": []}`,
+	}, []diagnosticCase{
+		{
+			name: "missing and mistyped secrets",
+			args: "-n shop",
+			check: func(t *testing.T, output string, _ []string) {
+				report := decodeReport[pullSecretsReport](t, output)
+				if report.Namespace != "shop" || !report.SecretsChecked || len(report.Pods) != 2 {
+					t.Fatalf("expected the two pods failing to pull, got %+v", report)
+				}
+				api, web := report.Pods[0], report.Pods[1]
+				if api.Name != "api-0" || len(api.Images) != 1 || api.Images[0].Container != "migrate" || !strings.HasPrefix(api.Diagnosis, "no imagePullSecrets") {
+					t.Errorf("unexpected report for api-0: %+v", api)
+				}
+				if web.Name != "web-0" || len(web.Images) != 1 || web.Images[0].Reason != "ImagePullBackOff" || web.Images[0].Image != "registry.example.com/shop/web:1.4" {
+					t.Errorf("unexpected images for web-0: %+v", web.Images)
+				}
+				if len(web.PullSecrets) != 2 || web.PullSecrets[0].Exists == nil || *web.PullSecrets[0].Exists || !*web.PullSecrets[1].Exists || web.PullSecrets[1].Type != "Opaque" {
+					t.Errorf("unexpected pull secrets for web-0: %+v", web.PullSecrets)
+				}
+				for _, want := range []string{"imagePullSecret 'registry-creds' does not exist in namespace shop", "imagePullSecret 'old-token' has type Opaque"} {
+					if !strings.Contains(web.Diagnosis, want) {
+						t.Errorf("diagnosis %q missing %q", web.Diagnosis, want)
+					}
+				}
+			},
+		},
+		{
+			// With secrets denied, only the references are reported
+			name: "secrets denied",
+			args: "-n shop",
+			cfg:  secretsDenied,
+			check: func(t *testing.T, output string, commands []string) {
+				report := decodeReport[pullSecretsReport](t, output)
+				if report.SecretsChecked || report.Pods[1].PullSecrets[0].Exists != nil || !strings.Contains(report.Pods[1].Diagnosis, "not checked") {
+					t.Errorf("expected secrets to be left unchecked, got %+v", report)
+				}
+				for _, command := range commands {
+					if strings.Contains(command, "secrets") {
+						t.Errorf("secrets should not be listed when denied, ran %q", command)
+					}
+				}
+			},
+		},
+		{
+			// Secrets are only listed when a pod fails to pull
+			name: "nothing failing",
+			args: "-n shop -l app=cache",
+			check: func(t *testing.T, output string, commands []string) {
+				if report := decodeReport[pullSecretsReport](t, output); len(report.Pods) != 0 || len(commands) != 1 {
+					t.Errorf("expected no pods and only the pod list, got %+v after %q", report, commands)
+				}
+			},
+		},
+		{name: "namespace carrying flags refused", args: "-n 'shop --as=admin'", refused: "invalid namespace"},
+		{name: "namespace required", args: "", refused: "requires a namespace"},
+		{name: "names refused", args: "web-0 -n shop", refused: "takes no names"},
+	})
+}
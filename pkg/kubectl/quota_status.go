@@ -0,0 +1,132 @@
+package kubectl
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/Azure/mcp-kubernetes/pkg/config"
+)
+
+// quotaStatus is the compute and pod quota headroom of a namespace
+type quotaStatus struct {
+	Namespace string        `json:"namespace"`
+	Status    string        `json:"status,omitempty"`
+	Quotas    []quotaReport `json:"quotas"`
+}
+
+// quotaReport is the used and hard amounts of one ResourceQuota
+type quotaReport struct {
+	Name      string       `json:"name"`
+	Resources []quotaUsage `json:"resources"`
+}
+
+// quotaUsage is one quota'd resource. Remaining is left out when the amounts can't be parsed.
+type quotaUsage struct {
+	Resource  string `json:"resource"`
+	Used      string `json:"used"`
+	Hard      string `json:"hard"`
+	Remaining string `json:"remaining,omitempty"`
+}
+
+// quotaResources are the quota'd resources reported, in order, with the kind of quantity each is
+var quotaResources = []struct {
+	name, kind string
+}{
+	{"requests.cpu", "cpu"},
+	{"limits.cpu", "cpu"},
+	{"cpu", "cpu"},
+	{"requests.memory", "memory"},
+	{"limits.memory", "memory"},
+	{"memory", "memory"},
+	{"pods", "count"},
+	{"count/pods", "count"},
+}
+
+// quotaStatus fetches the ResourceQuotas in a namespace and reports used against hard for cpu,
+// memory and pods. A namespace without quotas gets the status "no quota set". The lookup
+// goes through the same access and namespace checks as a direct get.
+func (e *KubectlToolExecutor) quotaStatus(args string, cfg *config.ConfigData) (string, error) {
+	cmdline, err := parseCommandLine(args)
+	if err != nil {
+		return "", err
+	}
+	if len(cmdline.positionals) != 0 {
+		return "", fmt.Errorf("quota-status takes only a namespace, e.g. '-n default'")
+	}
+	namespace, err := namespaceFlag(cmdline, "default")
+	if err != nil {
+		return "", err
+	}
+
+	quotas, err := e.listObjects(fmt.Sprintf("get resourcequotas -n %s -o json", namespace), cfg)
+	if err != nil {
+		return "", err
+	}
+
+	status := quotaStatus{Namespace: namespace, Quotas: []quotaReport{}}
+	for _, quota := range quotas {
+		_, name := objectNamespacedName(quota)
+		status.Quotas = append(status.Quotas, quotaReport{
+			Name:      name,
+			Resources: quotaUsages(nestedStringMap(quota, "status", "hard"), nestedStringMap(quota, "status", "used")),
+		})
+	}
+	sort.Slice(status.Quotas, func(i, j int) bool { return status.Quotas[i].Name < status.Quotas[j].Name })
+	if len(status.Quotas) == 0 {
+		status.Status = "no quota set"
+	}
+
+	data, err := json.MarshalIndent(status, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode quota status: %w", err)
+	}
+	return string(data), nil
+}
+
+// quotaUsages lists the cpu, memory and pod amounts of a quota that has a hard limit for them
+func quotaUsages(hard, used map[string]string) []quotaUsage {
+	usages := []quotaUsage{}
+	for _, resource := range quotaResources {
+		limit, ok := hard[resource.name]
+		if !ok {
+			continue
+		}
+		usage := quotaUsage{Resource: resource.name, Used: used[resource.name], Hard: limit}
+		if usage.Used == "" {
+			usage.Used = "0"
+		}
+		usage.Remaining = quotaRemaining(resource.kind, usage.Used, usage.Hard)
+		usages = append(usages, usage)
+	}
+	return usages
+}
+
+// quotaRemaining formats hard minus used as millicores, MiB or a count, or returns "" when
+// either amount can't be parsed
+func quotaRemaining(kind, used, hard string) string {
+	switch kind {
+	case "cpu":
+		usedCPU, err1 := parseMillicores(used)
+		hardCPU, err2 := parseMillicores(hard)
+		if err1 != nil || err2 != nil {
+			return ""
+		}
+		return fmt.Sprintf("%dm", hardCPU-usedCPU)
+	case "memory":
+		usedMemory, err1 := parseMebibytes(used)
+		hardMemory, err2 := parseMebibytes(hard)
+		if err1 != nil || err2 != nil {
+			return ""
+		}
+		return strconv.FormatFloat(hardMemory-usedMemory, 'f', -1, 64) + "Mi"
+	default:
+		usedCount, err1 := strconv.ParseInt(used, 10, 64)
+		hardCount, err2 := strconv.ParseInt(hard, 10, 64)
+		if err1 != nil || err2 != nil {
+			return ""
+		}
+		return strconv.FormatInt(hardCount-usedCount, 10)
+	}
+}
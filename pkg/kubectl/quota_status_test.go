@@ -0,0 +1,75 @@
+package kubectl
+
+import (
+	"strings"
+	"testing"
+)
+
+const quotaStatusJSON = `{"items": [
+	{"metadata": {"name": "objects", "namespace": "shop"},
+		"status": {"hard": {"configmaps": "20"}, "used": {"configmaps": "3"}}},
+	{"metadata": {"name": "compute", "namespace": "shop"},
+		"status": {
+			"hard": {"requests.cpu": "2", "requests.memory": "4Gi", "limits.memory": "8Gi", "pods": "10"},
+			"used": {"requests.cpu": "500m", "requests.memory": "1536Mi", "pods": "4"}}}]}`
+
+func TestKubectlToolExecutor_QuotaStatus(t *testing.T) {
+	restricted := newTestConfig("readonly")
+	restricted.SecurityConfig.SetAllowedNamespaces("default")
+
+	runDiagnosticCases(t, "quota-status", newTestConfig("readonly"), map[string]string{
+		"kubectl get resourcequotas -n shop -o json":    quotaStatusJSON,
+		"kubectl get resourcequotas -n empty -o json":   `{"items": []}`,
+		"kubectl get resourcequotas -n default -o json": `{"items": []}`,
+	}, []diagnosticCase{
+		{
+			name: "used against hard",
+			args: "-n shop",
+			check: func(t *testing.T, output string, _ []string) {
+				status := decodeReport[quotaStatus](t, output)
+				if status.Namespace != "shop" || status.Status != "" || len(status.Quotas) != 2 {
+					t.Fatalf("unexpected quota status: %+v", status)
+				}
+				compute := status.Quotas[0]
+				expected := []quotaUsage{
+					{Resource: "requests.cpu", Used: "500m", Hard: "2", Remaining: "1500m"},
+					{Resource: "requests.memory", Used: "1536Mi", Hard: "4Gi", Remaining: "2560Mi"},
+					{Resource: "limits.memory", Used: "0", Hard: "8Gi", Remaining: "8192Mi"},
+					{Resource: "pods", Used: "4", Hard: "10", Remaining: "6"},
+				}
+				if compute.Name != "compute" || len(compute.Resources) != len(expected) {
+					t.Fatalf("unexpected compute quota: %+v", compute)
+				}
+				for i := range expected {
+					if compute.Resources[i] != expected[i] {
+						t.Errorf("resources[%d] = %+v, want %+v", i, compute.Resources[i], expected[i])
+					}
+				}
+				if objects := status.Quotas[1]; objects.Name != "objects" || len(objects.Resources) != 0 {
+					t.Errorf("expected the object count quota to have no compute resources, got %+v", objects)
+				}
+			},
+		},
+		{
+			name: "no quota set",
+			args: "-n empty",
+			check: func(t *testing.T, output string, _ []string) {
+				if !strings.Contains(output, `"status": "no quota set"`) {
+					t.Errorf("expected no quota set, got %q", output)
+				}
+			},
+		},
+		{
+			name: "default namespace",
+			args: "",
+			check: func(t *testing.T, output string, _ []string) {
+				if status := decodeReport[quotaStatus](t, output); status.Namespace != "default" {
+					t.Errorf("expected the default namespace, got %+v", status)
+				}
+			},
+		},
+		{name: "namespace carrying flags refused", args: "-n 'shop --as=admin'", refused: "invalid namespace"},
+		{name: "names refused", args: "compute -n shop", refused: "takes only a namespace"},
+		{name: "namespace outside the allow-list", args: "-n shop", cfg: restricted, refused: "shop"},
+	})
+}
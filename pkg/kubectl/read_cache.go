@@ -0,0 +1,163 @@
+package kubectl
+
+import (
+	"container/list"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/Azure/mcp-kubernetes/pkg/config"
+)
+
+// resourceVersionOutput prints only an object's resourceVersion
+const resourceVersionOutput = "jsonpath={.metadata.resourceVersion}"
+
+// readCacheOutputs are the get output formats whose results are cached
+var readCacheOutputs = map[string]bool{
+	"json": true,
+	"yaml": true,
+}
+
+// readCacheCheckFlags are the flags of a cached get that also apply to its resourceVersion check
+var readCacheCheckFlags = []string{"--namespace", "--context", "--server", "--as", "--as-group"}
+
+// readCacheEntry is the output of a get and the resourceVersion of the object it printed
+type readCacheEntry struct {
+	command         string
+	resourceVersion string
+	output          string
+}
+
+// readCache keeps the output of recent single-object gets, least recently used first out.
+// An entry is only served after a check that the object's resourceVersion hasn't changed.
+type readCache struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+func newReadCache(size int) *readCache {
+	if size <= 0 {
+		return nil
+	}
+	return &readCache{size: size, order: list.New(), entries: make(map[string]*list.Element)}
+}
+
+// get returns the cached entry for a command
+func (c *readCache) get(command string) (readCacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	element, ok := c.entries[command]
+	if !ok {
+		return readCacheEntry{}, false
+	}
+	c.order.MoveToFront(element)
+	return element.Value.(readCacheEntry), true
+}
+
+// put stores a command's output, evicting the least recently used entry when full
+func (c *readCache) put(entry readCacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if element, ok := c.entries[entry.command]; ok {
+		element.Value = entry
+		c.order.MoveToFront(element)
+		return
+	}
+	c.entries[entry.command] = c.order.PushFront(entry)
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(readCacheEntry).command)
+	}
+}
+
+// remove drops a command's entry
+func (c *readCache) remove(command string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if element, ok := c.entries[command]; ok {
+		c.order.Remove(element)
+		delete(c.entries, command)
+	}
+}
+
+// CacheReads keeps the output of the last size gets of a single object as JSON or YAML, for
+// this executor and each tenant's. A cached result is served only after a get of just the
+// object's resourceVersion shows it is unchanged; otherwise the object is fetched again. 0
+// turns the cache off.
+func (e *KubectlToolExecutor) CacheReads(size int) {
+	e.reads = newReadCache(size)
+	for _, tenant := range e.tenants {
+		tenant.reads = newReadCache(size)
+	}
+}
+
+// resourceVersionCommand returns the get that prints only the resourceVersion of the object a
+// command reads, when the command is a cacheable get of one named object as JSON or YAML
+func resourceVersionCommand(command string) (string, bool) {
+	cmdline, err := parseCommandLine(command)
+	if err != nil || cmdline.verb() != "get" || len(cmdline.trailing) != 0 {
+		return "", false
+	}
+	args := cmdline.args()
+	switch {
+	case len(args) == 1 && strings.Count(args[0], "/") == 1:
+	case len(args) == 2 && !strings.Contains(args[0], "/"):
+	default:
+		return "", false
+	}
+	if strings.Contains(args[0], ",") {
+		return "", false
+	}
+	if output, _ := cmdline.flag("--output"); !readCacheOutputs[output] {
+		return "", false
+	}
+	for _, name := range []string{"--selector", "--field-selector", "--all-namespaces", "--watch", "--watch-only", "--raw", "--filename", "--kustomize"} {
+		if cmdline.hasFlag(name) {
+			return "", false
+		}
+	}
+
+	parts := append([]string{"get"}, args...)
+	for _, name := range readCacheCheckFlags {
+		if value, ok := cmdline.flag(name); ok && value != "" {
+			parts = append(parts, name+"="+shellQuote(value))
+		}
+	}
+	return strings.Join(append(parts, "-o", shellQuote(resourceVersionOutput)), " "), true
+}
+
+// outputResourceVersion returns the resourceVersion of the object printed as JSON or YAML
+func outputResourceVersion(output string) string {
+	var obj map[string]interface{}
+	if err := yaml.Unmarshal([]byte(output), &obj); err != nil {
+		return ""
+	}
+	return nestedString(obj, "metadata", "resourceVersion")
+}
+
+// cachedRead serves a get from the read cache when the object's resourceVersion still matches
+// the cached one, and otherwise runs it and caches the result
+func (e *KubectlToolExecutor) cachedRead(command, versionCheck string, requested int, cfg *config.ConfigData) (string, error) {
+	if entry, ok := e.reads.get(command); ok {
+		version, err := e.runOnHost(versionCheck, requested, cfg)
+		if err == nil && strings.TrimSpace(version) == entry.resourceVersion {
+			return entry.output, nil
+		}
+	}
+
+	output, err := e.runOnHost(command, requested, cfg)
+	if err != nil {
+		e.reads.remove(command)
+		return output, err
+	}
+	if version := outputResourceVersion(output); version != "" {
+		e.reads.put(readCacheEntry{command: command, resourceVersion: version, output: output})
+	} else {
+		e.reads.remove(command)
+	}
+	return output, nil
+}
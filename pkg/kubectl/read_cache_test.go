@@ -0,0 +1,82 @@
+package kubectl
+
+import (
+	"testing"
+)
+
+func TestKubectlToolExecutor_ReadCache(t *testing.T) {
+	const get = "kubectl get pod web -n shop -o json --show-managed-fields=false"
+	const versionCheck = "kubectl get pod web --namespace='shop' -o 'jsonpath={.metadata.resourceVersion}'"
+	version := "100"
+	var commands []string
+	worker := newTestWorker(t, func(command string) map[string]interface{} {
+		commands = append(commands, command)
+		switch command {
+		case get:
+			return map[string]interface{}{"stdout": `{"metadata": {"name": "web", "resourceVersion": "` + version + `"}}`}
+		case versionCheck:
+			return map[string]interface{}{"stdout": version}
+		}
+		return map[string]interface{}{"error": "unexpected command: " + command}
+	})
+	executor := NewKubectlToolExecutor(worker)
+	executor.CacheReads(10)
+	cfg := newTestConfig("readonly")
+
+	read := func() string {
+		t.Helper()
+		commands = nil
+		output, err := executor.Execute(map[string]interface{}{
+			"_tool_name": "kubectl_resources",
+			"operation":  "get",
+			"resource":   "pod",
+			"args":       "web -n shop -o json",
+		}, cfg)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return output
+	}
+
+	first := read()
+	if len(commands) != 1 || commands[0] != get {
+		t.Fatalf("Expected the first read to fetch the object, got %q", commands)
+	}
+
+	if second := read(); second != first {
+		t.Errorf("Expected the cached output, got %q", second)
+	}
+	if len(commands) != 1 || commands[0] != versionCheck {
+		t.Errorf("Expected only a resourceVersion check for an unchanged object, got %q", commands)
+	}
+
+	version = "101"
+	third := read()
+	if len(commands) != 2 || commands[0] != versionCheck || commands[1] != get {
+		t.Errorf("Expected a changed resourceVersion to refetch the object, got %q", commands)
+	}
+	if third == first {
+		t.Errorf("Expected the refetched object, got the stale one: %q", third)
+	}
+}
+
+func TestResourceVersionCommand(t *testing.T) {
+	tests := []struct {
+		command  string
+		expected string
+	}{
+		{"get deploy/web -o yaml --as=alice", "get deploy/web --as='alice' -o 'jsonpath={.metadata.resourceVersion}'"},
+		{"get pod web -o json", "get pod web -o 'jsonpath={.metadata.resourceVersion}'"},
+		{"get pods -n shop -o json", ""},
+		{"get pods -l app=web -o json", ""},
+		{"get pod web", ""},
+		{"get pod,svc web -o json", ""},
+		{"describe pod web -o json", ""},
+	}
+	for _, tt := range tests {
+		got, ok := resourceVersionCommand(tt.command)
+		if got != tt.expected || ok != (tt.expected != "") {
+			t.Errorf("resourceVersionCommand(%q) = %q, %v; expected %q", tt.command, got, ok, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package kubectl
+
+import (
+	"errors"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/mcp-kubernetes/pkg/config"
+	"github.com/Azure/mcp-kubernetes/pkg/security"
+	"github.com/Azure/mcp-kubernetes/pkg/tools"
+)
+
+// recentOutcomeMaxLen caps the error text kept for a recent command
+const recentOutcomeMaxLen = 200
+
+// Decisions recorded for recent commands
+const (
+	decisionAllowed = "allowed"
+	decisionDenied  = "denied"
+)
+
+// CommandRecord is one tool call kept for debugging. User is the caller's identity, "anonymous"
+// for calls that carry none. Decision is "denied" when the access level or security policy
+// refused the call and "allowed" otherwise; Outcome is "ok" or the error.
+type CommandRecord struct {
+	Time     time.Time `json:"time"`
+	User     string    `json:"user"`
+	Tool     string    `json:"tool"`
+	Command  string    `json:"command"`
+	Routing  Routing   `json:"routing"`
+	Decision string    `json:"decision"`
+	Outcome  string    `json:"outcome"`
+}
+
+// Routing is where and as whom a tool call ran: the tenant whose cluster agent ran it (empty
+// for the default agent), the API server and kubeconfig context (empty for the agent's
+// current ones), the namespace after an impersonated user's mapped namespace is applied
+// (empty for the context's default), and the impersonated identity.
+type Routing struct {
+	Tenant        string   `json:"tenant,omitempty"`
+	Server        string   `json:"server,omitempty"`
+	Context       string   `json:"context,omitempty"`
+	Namespace     string   `json:"namespace,omitempty"`
+	AllNamespaces bool     `json:"all_namespaces,omitempty"`
+	As            string   `json:"as,omitempty"`
+	AsGroups      []string `json:"as_groups,omitempty"`
+}
+
+// resolveRouting works out the routing of a tool call from its parameters the way the command
+// is assembled: the namespace, server and identity parameters win over the flags in args, and an
+// impersonated user without a namespace gets the one --impersonation-namespaces maps it to
+func resolveRouting(params map[string]interface{}, cfg *config.ConfigData) Routing {
+	tenant, _ := params["tenant"].(string)
+	routing := Routing{Tenant: strings.TrimSpace(tenant)}
+
+	args, _ := params["args"].(string)
+	if scoped, err := applyScopeParams(args, params); err == nil {
+		args = scoped
+	}
+	cmdline, err := parseCommandLine(args)
+	if err != nil {
+		cmdline = &commandLine{flags: map[string][]string{}}
+	}
+	routing.Context, _ = cmdline.flag("--context")
+	routing.Namespace, _ = cmdline.flag("--namespace")
+	routing.AllNamespaces = cmdline.boolFlag("--all-namespaces")
+	routing.Server, _ = cmdline.flag("--server")
+	if server, _ := params["server"].(string); strings.TrimSpace(server) != "" {
+		routing.Server = strings.TrimSpace(server)
+	}
+
+	imp := impersonationFromCommand(cmdline)
+	if fromParams, err := impersonationFromParams(params); err == nil && fromParams.user != "" {
+		imp = fromParams
+	}
+	routing.As, routing.AsGroups = imp.user, imp.groups
+	if routing.Namespace == "" && !routing.AllNamespaces && imp.user != "" {
+		routing.Namespace = cfg.ImpersonationNamespaces[imp.user]
+	}
+	return routing
+}
+
+// recentCommands is a fixed-size ring of the latest tool calls. It keeps much less than an
+// audit log, and nothing once full but the newest entries.
+type recentCommands struct {
+	mu      sync.Mutex
+	records []CommandRecord
+	next    int
+	full    bool
+}
+
+func newRecentCommands(size int) *recentCommands {
+	if size <= 0 {
+		return nil
+	}
+	return &recentCommands{records: make([]CommandRecord, size)}
+}
+
+// add stores a record, overwriting the oldest one when the ring is full
+func (r *recentCommands) add(record CommandRecord) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.records[r.next] = record
+	r.next = (r.next + 1) % len(r.records)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// snapshot returns the stored records, oldest first
+func (r *recentCommands) snapshot() []CommandRecord {
+	records := []CommandRecord{}
+	if r == nil {
+		return records
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.full {
+		records = append(records, r.records[r.next:]...)
+	}
+	return append(records, r.records[:r.next]...)
+}
+
+// KeepRecentCommands records the last size tool calls for RecentCommands; 0 turns it off
+func (e *KubectlToolExecutor) KeepRecentCommands(size int) {
+	e.recent = newRecentCommands(size)
+}
+
+// RecentCommands returns the recorded tool calls, oldest first
+func (e *KubectlToolExecutor) RecentCommands() []CommandRecord {
+	return e.recent.snapshot()
+}
+
+// recordCommand logs a finished tool call with its routing as an audit record and adds it to
+// the recent commands, redacted like command output
+func (e *KubectlToolExecutor) recordCommand(params map[string]interface{}, cfg *config.ConfigData, err error) {
+	toolName, _ := params["_tool_name"].(string)
+	user, _ := params[tools.UserParam].(string)
+	var parts []string
+	for _, key := range []string{"operation", "resource", "args"} {
+		if value, _ := params[key].(string); strings.TrimSpace(value) != "" {
+			parts = append(parts, strings.TrimSpace(value))
+		}
+	}
+
+	record := CommandRecord{
+		Time:     time.Now().UTC(),
+		User:     user,
+		Tool:     toolName,
+		Command:  strings.Join(parts, " "),
+		Routing:  resolveRouting(params, cfg),
+		Decision: decisionAllowed,
+		Outcome:  "ok",
+	}
+	if err != nil {
+		var validationErr *security.ValidationError
+		if errors.As(err, &validationErr) {
+			record.Decision = decisionDenied
+		}
+		record.Outcome = err.Error()
+		if len(record.Outcome) > recentOutcomeMaxLen {
+			record.Outcome = record.Outcome[:recentOutcomeMaxLen] + "..."
+		}
+	}
+	if cfg.SecurityConfig != nil {
+		record.Command = cfg.SecurityConfig.Redact(record.Command)
+		record.Outcome = cfg.SecurityConfig.Redact(record.Outcome)
+	}
+	slog.Info("audit",
+		"user", record.User,
+		"tool", record.Tool,
+		"command", record.Command,
+		"tenant", record.Routing.Tenant,
+		"server", record.Routing.Server,
+		"context", record.Routing.Context,
+		"namespace", record.Routing.Namespace,
+		"all_namespaces", record.Routing.AllNamespaces,
+		"as", record.Routing.As,
+		"as_groups", strings.Join(record.Routing.AsGroups, ","),
+		"decision", record.Decision,
+		"outcome", record.Outcome,
+	)
+	e.recent.add(record)
+}
+
+// denied marks an error from a policy check so the call is recorded as denied
+func denied(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &security.ValidationError{Message: err.Error()}
+}
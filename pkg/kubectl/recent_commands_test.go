@@ -0,0 +1,207 @@
+package kubectl
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"testing"
+
+	"github.com/Azure/mcp-kubernetes/pkg/tools"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestRecentCommandsKeepsLatest(t *testing.T) {
+	recent := newRecentCommands(3)
+	if got := recent.snapshot(); len(got) != 0 {
+		t.Fatalf("Expected an empty buffer, got %v", got)
+	}
+
+	for i := 1; i <= 5; i++ {
+		recent.add(CommandRecord{Command: fmt.Sprintf("get pod-%d", i)})
+	}
+
+	got := recent.snapshot()
+	want := []string{"get pod-3", "get pod-4", "get pod-5"}
+	if len(got) != len(want) {
+		t.Fatalf("Expected %d records, got %d: %v", len(want), len(got), got)
+	}
+	for i, record := range got {
+		if record.Command != want[i] {
+			t.Errorf("Record %d: expected %q, got %q", i, want[i], record.Command)
+		}
+	}
+
+	if newRecentCommands(0).snapshot() == nil {
+		t.Error("Expected a disabled buffer to return an empty list")
+	}
+}
+
+func TestKubectlToolExecutor_RecentCommands(t *testing.T) {
+	worker := newTestWorker(t, func(command string) map[string]interface{} {
+		if command == "kubectl get pods -n default" {
+			return map[string]interface{}{"stdout": "NAME   READY\nweb    1/1\n"}
+		}
+		return map[string]interface{}{"error": "unexpected command: " + command}
+	})
+	executor := NewKubectlToolExecutor(worker)
+	executor.KeepRecentCommands(10)
+	cfg := newTestConfig("readonly")
+
+	if _, err := executor.Execute(map[string]interface{}{
+		"_tool_name": "kubectl_resources",
+		"operation":  "get",
+		"resource":   "pods",
+		"args":       "-n default",
+	}, cfg); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := executor.Execute(map[string]interface{}{
+		"_tool_name": "kubectl_resources",
+		"operation":  "delete",
+		"resource":   "pod",
+		"args":       "web -n default",
+	}, cfg); err == nil {
+		t.Fatal("Expected delete to be refused in readonly mode")
+	}
+
+	records := executor.RecentCommands()
+	if len(records) != 2 {
+		t.Fatalf("Expected 2 records, got %d: %v", len(records), records)
+	}
+	if records[0].Command != "get pods -n default" || records[0].Decision != decisionAllowed || records[0].Outcome != "ok" {
+		t.Errorf("Unexpected record for get: %+v", records[0])
+	}
+	if records[1].Command != "delete pod web -n default" || records[1].Decision != decisionDenied || records[1].Outcome == "ok" {
+		t.Errorf("Unexpected record for delete: %+v", records[1])
+	}
+	if records[1].Tool != "kubectl_resources" || records[1].Time.IsZero() {
+		t.Errorf("Expected tool and time to be recorded: %+v", records[1])
+	}
+}
+
+func TestKubectlToolExecutor_AuditRouting(t *testing.T) {
+	var logs bytes.Buffer
+	defaultLogger := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&logs, nil)))
+	defer slog.SetDefault(defaultLogger)
+
+	worker := newTestWorker(t, func(command string) map[string]interface{} {
+		if command == "kubectl get pods --context=staging --as=ci-bot --as-group=deployers -n ci" {
+			return map[string]interface{}{"stdout": "NAME   READY\nweb    1/1\n"}
+		}
+		return map[string]interface{}{"error": "unexpected command: " + command}
+	})
+	executor := NewKubectlToolExecutor(worker)
+	executor.KeepRecentCommands(10)
+	cfg := newTestConfig("readwrite")
+	cfg.ImpersonationNamespaces["ci-bot"] = "ci"
+
+	if _, err := executor.Execute(map[string]interface{}{
+		"_tool_name": "kubectl_resources",
+		"operation":  "get",
+		"resource":   "pods",
+		"args":       "--context=staging",
+		"as":         "ci-bot",
+		"as_group":   "deployers",
+	}, cfg); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := Routing{Context: "staging", Namespace: "ci", As: "ci-bot", AsGroups: []string{"deployers"}}
+	records := executor.RecentCommands()
+	if len(records) != 1 {
+		t.Fatalf("Expected 1 record, got %d: %v", len(records), records)
+	}
+	got := records[0].Routing
+	if got.Context != want.Context || got.Namespace != want.Namespace || got.As != want.As ||
+		len(got.AsGroups) != 1 || got.AsGroups[0] != "deployers" || got.Tenant != "" || got.Server != "" {
+		t.Errorf("Expected routing %+v, got %+v", want, got)
+	}
+
+	var entry map[string]interface{}
+	for _, line := range bytes.Split(logs.Bytes(), []byte("\n")) {
+		var logged map[string]interface{}
+		if json.Unmarshal(line, &logged) == nil && logged["msg"] == "audit" {
+			entry = logged
+		}
+	}
+	if entry == nil {
+		t.Fatalf("Expected an audit entry in the log:\n%s", logs.String())
+	}
+	expected := map[string]interface{}{
+		"msg":            "audit",
+		"tool":           "kubectl_resources",
+		"command":        "get pods --context=staging",
+		"tenant":         "",
+		"server":         "",
+		"context":        "staging",
+		"namespace":      "ci",
+		"all_namespaces": false,
+		"as":             "ci-bot",
+		"as_groups":      "deployers",
+		"decision":       decisionAllowed,
+		"outcome":        "ok",
+	}
+	for key, value := range expected {
+		if entry[key] != value {
+			t.Errorf("Audit entry %s = %v, want %v", key, entry[key], value)
+		}
+	}
+}
+
+func TestKubectlToolExecutor_AuditUser(t *testing.T) {
+	var logs bytes.Buffer
+	defaultLogger := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&logs, nil)))
+	defer slog.SetDefault(defaultLogger)
+
+	worker := newTestWorker(t, func(command string) map[string]interface{} {
+		return map[string]interface{}{"stdout": "NAME   READY\nweb    1/1\n"}
+	})
+	executor := NewKubectlToolExecutor(worker)
+	executor.KeepRecentCommands(10)
+	handler := tools.CreateToolHandlerWithName(executor, newTestConfig("readonly"), "kubectl_resources")
+
+	// The identity comes from the call's context, whatever the client sends in the arguments
+	calls := []struct {
+		ctx  context.Context
+		args string
+	}{
+		{tools.WithUser(context.Background(), "alice"), "-n shop"},
+		{tools.WithUser(context.Background(), "bob"), "-n billing"},
+		{context.Background(), "-n default"},
+	}
+	for _, call := range calls {
+		req := mcp.CallToolRequest{}
+		req.Params.Name = "kubectl_resources"
+		req.Params.Arguments = map[string]interface{}{"operation": "get", "resource": "pods", "args": call.args, tools.UserParam: "mallory"}
+		if result, err := handler(call.ctx, req); err != nil || result.IsError {
+			t.Fatalf("unexpected failure: %v %+v", err, result)
+		}
+	}
+
+	want := []string{"alice", "bob", tools.AnonymousUser}
+	records := executor.RecentCommands()
+	if len(records) != len(want) {
+		t.Fatalf("expected %d records, got %+v", len(want), records)
+	}
+	for i, record := range records {
+		if record.User != want[i] {
+			t.Errorf("record %d (%s): user = %q, want %q", i, record.Command, record.User, want[i])
+		}
+	}
+
+	var users []string
+	for _, line := range bytes.Split(logs.Bytes(), []byte("\n")) {
+		var logged map[string]interface{}
+		if json.Unmarshal(line, &logged) == nil && logged["msg"] == "audit" {
+			user, _ := logged["user"].(string)
+			users = append(users, user)
+		}
+	}
+	if fmt.Sprint(users) != fmt.Sprint(want) {
+		t.Errorf("expected audit entries for %v, got %v", want, users)
+	}
+}
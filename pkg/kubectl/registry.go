@@ -48,8 +48,11 @@ func RegisterKubectlTools(accessLevel string) []mcp.Tool {
 		{creator: toolCreatorSimple(createClusterTool), minAccess: AccessLevelReadOnly},
 		{creator: toolCreator(createConfigTool), minAccess: AccessLevelReadOnly, readOnlyMode: true},
 		{creator: toolCreatorSimple(createCheckPermissionsTool), minAccess: AccessLevelReadOnly},
+		{creator: toolCreatorSimple(createEffectivePolicyTool), minAccess: AccessLevelReadOnly},
 		{creator: toolCreatorSimple(createWorkloadsTool), minAccess: AccessLevelReadWrite},
 		{creator: toolCreatorSimple(createMetadataTool), minAccess: AccessLevelReadWrite},
+		{creator: toolCreatorSimple(createWorkerStatsTool), minAccess: AccessLevelAdmin},
+		{creator: toolCreatorSimple(createRecentCommandsTool), minAccess: AccessLevelAdmin},
 	}
 
 	// Normalize access level
@@ -115,6 +118,8 @@ func createResourcesTool(readOnly bool) mcp.Tool {
 Available operations:
 - get: Display one or many resources
 - describe: Show detailed information about resources
+- describe-yaml: Show the describe output and the cleaned YAML of one resource in a single call
+- configmap-data: Return the data of one ConfigMap as JSON, with binaryData as decoded lengths
 
 Common resources: pods, deployments, services, configmaps, secrets, namespaces, etc.
 
@@ -123,21 +128,29 @@ Examples:
 - Get specific pod: operation='get', resource='pods', args='nginx-pod -n default'
 - Get with selector: operation='get', resource='pods', args='-l app=nginx'
 - Get all namespaces: operation='get', resource='pods', args='--all-namespaces'
+- Get pod names only: operation='get', resource='pods', args='-n default -l app=nginx', names_only=true
+- Get pods as a table: operation='get', resource='pods', args='-n default', structured=true
 - Describe deployment: operation='describe', resource='deployment', args='myapp -n production'
 - Describe all pods: operation='describe', resource='pods', args=''
-- Describe with selector: operation='describe', resource='pods', args='-l name=myLabel'`
-		operationDesc = "The operation to perform: get, describe"
+- Describe with selector: operation='describe', resource='pods', args='-l name=myLabel'
+- Describe and YAML: operation='describe-yaml', resource='pod', args='nginx-pod -n default'
+- Describe with YAML: operation='describe', resource='configmap', args='app-config -n default', with_yaml=true
+- ConfigMap data: operation='configmap-data', resource='configmap', args='app-config -n default'`
+		operationDesc = "The operation to perform: get, describe, describe-yaml, configmap-data"
 	} else {
 		description = `Manage Kubernetes resources with standard CRUD operations.
 
 Available operations:
 - get: Display one or many resources
 - describe: Show detailed information about resources
+- describe-yaml: Show the describe output and the cleaned YAML of one resource in a single call
+- configmap-data: Return the data of one ConfigMap as JSON, with binaryData as decoded lengths
 - create: Create a resource from a file or stdin
 - delete: Delete resources
 - apply: Apply a configuration to a resource
 - patch: Update fields of a resource
 - replace: Replace a resource
+- restore-last: Put back the latest snapshot of a resource taken before it was changed (only with --snapshots)
 - cordon: Mark node as unschedulable (admin only)
 - uncordon: Mark node as schedulable (admin only)
 - drain: Drain node in preparation for maintenance (admin only)
@@ -150,23 +163,38 @@ Examples:
 - Get specific pod: operation='get', resource='pods', args='nginx-pod -n default'
 - Get with selector: operation='get', resource='pods', args='-l app=nginx'
 - Get all namespaces: operation='get', resource='pods', args='--all-namespaces'
+- Get pod names only: operation='get', resource='pods', args='-n default -l app=nginx', names_only=true
+- Get pods as a table: operation='get', resource='pods', args='-n default', structured=true
 - Describe deployment: operation='describe', resource='deployment', args='myapp -n production'
 - Describe all pods: operation='describe', resource='pods', args=''
 - Describe with selector: operation='describe', resource='pods', args='-l name=myLabel'
+- Describe and YAML: operation='describe-yaml', resource='pod', args='nginx-pod -n default'
+- Describe with YAML: operation='describe', resource='configmap', args='app-config -n default', with_yaml=true
+- ConfigMap data: operation='configmap-data', resource='configmap', args='app-config -n default'
 - Create from file: operation='create', resource='', args='-f deployment.yaml'
 - Create deployment: operation='create', resource='deployment', args='nginx --image=nginx'
 - Create configmap: operation='create', resource='configmap', args='my-config --from-literal=key1=value1'
+- Create configmap from data: operation='create', resource='configmap', args='my-config -n default', literals={"key1":"value1"}, files={"app.properties":"debug=true"}
+- Create secret: operation='create', resource='secret', args='db-creds -n default', literals={"password":"s3cret"}
+- Create job: operation='create', resource='job', args='migrate -n default -- ./migrate.sh', image='registry.example.com/app:1.2'
 - Apply config: operation='apply', resource='', args='-f deployment.yaml'
 - Apply kustomize: operation='apply', resource='', args='-k ./manifests/'
+- Apply inline manifest: operation='apply', resource='', args='-n default', manifest='apiVersion: apps/v1\nkind: Deployment\n...'
+- Apply with a summary of changes: operation='apply', resource='', args='-f app.yaml', structured=true
 - Patch node: operation='patch', resource='node', args='k8s-node-1 -p \'{"spec":{"unschedulable":true}}\''
 - Patch from file: operation='patch', resource='', args='-f node.json -p \'{"spec":{"unschedulable":true}}\''
 - Patch pod image: operation='patch', resource='pod', args='valid-pod -p \'{"spec":{"containers":[{"name":"app","image":"nginx:1.20"}]}}\''
 - Patch with JSON type: operation='patch', resource='pod', args='valid-pod --type=json -p \'[{"op":"replace","path":"/spec/containers/0/image","value":"nginx:1.20"}]\''
+- Patch with structured body: operation='patch', resource='deployment', args='web -n default', patch_type='merge', patch_body={"spec":{"replicas":3}}
 - Replace from file: operation='replace', resource='', args='-f ./updated-pod.json'
 - Force replace: operation='replace', resource='', args='--force -f ./pod.json'
 - Delete service: operation='delete', resource='service', args='myservice -n default'
 - Delete from file: operation='delete', resource='', args='-f pod.yaml'
 - Delete with selector: operation='delete', resource='pods', args='-l name=myLabel'
+- Delete and wait for dependents: operation='delete', resource='deployment', args='web -n default', cascade='foreground'
+- Delete before recreating: operation='delete', resource='pod', args='web -n default', wait_for_deletion=true
+- Undo the last change: operation='restore-last', resource='', args=''
+- Undo the last change to a resource: operation='restore-last', resource='deployment', args='web -n default'
 - Cordon node: operation='cordon', resource='node', args='worker-1'
 - Uncordon node: operation='uncordon', resource='node', args='worker-1'
 - Cordon with selector: operation='cordon', resource='node', args='-l node-type=worker'
@@ -176,10 +204,10 @@ Examples:
 - Add taint: operation='taint', resource='nodes', args='worker-1 dedicated=special-user:NoSchedule'
 - Remove taint: operation='taint', resource='nodes', args='worker-1 dedicated:NoSchedule-'
 - Taint with selector: operation='taint', resource='node', args='-l myLabel=X dedicated=foo:PreferNoSchedule'`
-		operationDesc = "The operation to perform: get, describe, create, delete, apply, patch, replace, cordon, uncordon, drain, taint"
+		operationDesc = "The operation to perform: get, describe, describe-yaml, configmap-data, create, delete, apply, patch, replace, restore-last, cordon, uncordon, drain, taint"
 	}
 
-	return mcp.NewTool("kubectl_resources",
+	options := []mcp.ToolOption{
 		mcp.WithDescription(description),
 		mcp.WithString("operation",
 			mcp.Required(),
@@ -193,7 +221,66 @@ Examples:
 			mcp.Required(),
 			mcp.Description("Additional arguments like resource names, namespaces, and flags"),
 		),
-	)
+		mcp.WithBoolean("structured",
+			mcp.Description("For get without -o (or with -o wide), return the table as JSON {columns, rows}; for apply, return {created, configured, unchanged} lists of objects. Falls back to raw output if parsing fails"),
+		),
+		mcp.WithBoolean("names_only",
+			mcp.Description("For get, return only resource names, one per line (adds -o name; with -o json in args, the item names are extracted)"),
+		),
+		mcp.WithString("output",
+			mcp.Description("Output format, e.g. json, yaml, wide or jsonpath={.items[*].metadata.name} (adds -o; don't also pass -o in args). For get, ndjson returns one JSON object per resource per line"),
+		),
+		mcp.WithBoolean("with_yaml",
+			mcp.Description("For describe of one named resource, also return its cleaned YAML, as describe-yaml does; false turns off the server's --describe-yaml-kinds for this call"),
+		),
+		mcp.WithBoolean("show_managed_fields",
+			mcp.Description("For get with -o json or -o yaml, include metadata.managedFields, which are left out by default (sets --show-managed-fields)"),
+		),
+		mcp.WithString("jq",
+			mcp.Description("jq expression applied to JSON output (use -o json), e.g. '[.items[].metadata.name]'. Supports paths, [], |, [ ... ], length and keys; only available when the server runs with --result-transform=jq"),
+		),
+	}
+	options = append(options, withScopeParams()...)
+	options = append(options, withEchoParam(), withTimeoutParam(), withServerParam(), withTenantParam())
+	if !readOnly {
+		options = append(options, withImpersonationParams()...)
+		options = append(options,
+			mcp.WithString("cascade",
+				mcp.Description("How delete handles dependents: background, foreground or orphan (adds --cascade; kubectl defaults to background)"),
+			),
+			mcp.WithBoolean("wait_for_deletion",
+				mcp.Description("For delete, wait until the deleted resources are gone (adds --wait=true, then checks), within the call's timeout"),
+			),
+			mcp.WithBoolean("confirm",
+				mcp.Description("Accept a delete with cascade=orphan, which leaves dependent resources running without an owner, or a change with --all"),
+			),
+			mcp.WithString("patch_type",
+				mcp.Description("For patch with patch_body, the patch type: strategic (default), merge or json"),
+				mcp.Enum("strategic", "merge", "json"),
+			),
+			withPatchBodyParam(),
+			mcp.WithObject("literals",
+				mcp.Description("For create configmap or secret, keys and string values to store (adds --from-literal; secrets are created as generic)"),
+				mcp.AdditionalProperties(map[string]any{"type": "string"}),
+			),
+			mcp.WithObject("files",
+				mcp.Description("For create configmap or secret, file names and their contents, sent inline and stored under the file name"),
+				mcp.AdditionalProperties(map[string]any{"type": "string"}),
+			),
+			mcp.WithString("image",
+				mcp.Description("For create job, the container image to run (adds --image; must be on the server's image allow-list)"),
+			),
+			mcp.WithString("manifest",
+				mcp.Description("For create and apply, the YAML or JSON objects to create or apply, sent on stdin as -f - (leave resource empty)"),
+			),
+			withPreflightParam(),
+			withReasonParam(),
+			withIdempotencyKeyParam(),
+			withConfirmTokenParam(),
+		)
+	}
+
+	return mcp.NewTool("kubectl_resources", options...)
 }
 
 // createWorkloadsTool creates the workload management tool
@@ -219,10 +306,13 @@ Examples:
 - Autoscale with CPU: operation='autoscale', resource='rc', args='foo --max=5 --cpu-percent=80'
 - Rollout status: operation='rollout', resource='status', args='deployment/myapp'
 - Rollout history: operation='rollout', resource='history', args='deployment/abc'
-- Rollout undo: operation='rollout', resource='undo', args='deployment/abc'
+- Rollout history as JSON: operation='rollout', resource='history', args='deployment/abc', structured=true
+- Template of a revision: operation='rollout', resource='history', args='deployment/abc', revision=3
+- Rollout undo to revision: operation='rollout', resource='undo', args='deployment/abc', to_revision=3
+- Rollout undo to previous: operation='rollout', resource='undo', args='deployment/abc', confirm=true
 - Rollout restart: operation='rollout', resource='restart', args='deployment/abc'`
 
-	return mcp.NewTool("kubectl_workloads",
+	options := []mcp.ToolOption{
 		mcp.WithDescription(description),
 		mcp.WithString("operation",
 			mcp.Required(),
@@ -236,7 +326,25 @@ Examples:
 			mcp.Required(),
 			mcp.Description("Additional arguments specific to the operation"),
 		),
-	)
+		mcp.WithNumber("to_revision",
+			mcp.Description("Revision to roll back to with rollout undo (adds --to-revision)"),
+		),
+		mcp.WithBoolean("confirm",
+			mcp.Description("Accept rolling back to the immediately previous revision when rollout undo has no to_revision, or a change with --all"),
+		),
+		mcp.WithNumber("revision",
+			mcp.Description("For rollout history, show the pod template of this revision (adds --revision)"),
+		),
+		mcp.WithBoolean("structured",
+			mcp.Description("For rollout history, return JSON: [{revision, change_cause}], or {revision, change_cause, template} with revision. Falls back to raw output if parsing fails"),
+		),
+	}
+	options = append(options, withImpersonationParams()...)
+	options = append(options, withScopeParams()...)
+	options = append(options, withEchoParam(), withTimeoutParam(), withServerParam(), withTenantParam())
+	options = append(options, withPreflightParam(), withReasonParam(), withIdempotencyKeyParam())
+
+	return mcp.NewTool("kubectl_workloads", options...)
 }
 
 // createMetadataTool creates the metadata management tool
@@ -257,7 +365,7 @@ Examples:
 - Remove annotation: operation='annotate', resource='pods', args='foo description-'
 - Set image: operation='set', resource='image', args='deployment/nginx busybox=busybox nginx=nginx:1.9.1'`
 
-	return mcp.NewTool("kubectl_metadata",
+	options := []mcp.ToolOption{
 		mcp.WithDescription(description),
 		mcp.WithString("operation",
 			mcp.Required(),
@@ -271,7 +379,16 @@ Examples:
 			mcp.Required(),
 			mcp.Description("Resource names and metadata changes"),
 		),
-	)
+		mcp.WithBoolean("confirm",
+			mcp.Description("Accept a change with --all, which updates every resource of the type in the namespace"),
+		),
+	}
+	options = append(options, withImpersonationParams()...)
+	options = append(options, withScopeParams()...)
+	options = append(options, withEchoParam(), withTimeoutParam(), withServerParam(), withTenantParam())
+	options = append(options, withPreflightParam(), withReasonParam(), withIdempotencyKeyParam())
+
+	return mcp.NewTool("kubectl_metadata", options...)
 }
 
 // createDiagnosticsTool creates the diagnostics and debugging tool
@@ -284,6 +401,23 @@ Available operations:
 - top: Display resource usage (CPU/Memory)
 - exec: Execute a command in a container
 - cp: Copy files to/from containers
+- describe-tree: Show a deployment or statefulset with its replicasets, pods, services and recent events
+- pod-diagnosis: Explain a failing pod (OOMKilled, CrashLoopBackOff, image pull errors, exit codes, restarts)
+- orphans: List resources of a type in a namespace that have no owner or whose owner no longer exists
+- deployment-logs: Recent logs from every pod of a deployment, one labeled section per pod
+- quota-status: Used against hard cpu, memory and pod quota in a namespace
+- pending-pods: Pending pods in a namespace or every allowed namespace, with the scheduler's reason each can't be placed
+- probes: Liveness, readiness and startup probes of a pod's containers, or of a workload's pod template
+- node-capacity: Each node's capacity and allocatable cpu, memory and pods against the requests of its pods, with the headroom left
+- pull-secrets: Pods in a namespace stuck in ImagePullBackOff, whether the imagePullSecrets they reference exist and hold registry credentials
+- cronjobs: CronJobs in a namespace with their schedule, whether they are suspended, when they last ran and their active jobs
+- drain-check: Pods on a node with the PodDisruptionBudgets governing them and their allowed disruptions, to tell whether a drain would block
+- ingress-routes: Host and path routes of the Ingresses in a namespace with their backend service, port and TLS, flagging services that don't exist
+- restarts: Pods in a namespace that restarted most, by total container restarts, with the reason each container's last run ended
+- service-endpoints: Ready and total endpoints of the services in a namespace, flagging services with no ready endpoint
+- rollout-stall: Why a deployment's rollout isn't progressing (quota, image pull, insufficient resources, readiness probe and more), from its conditions, ReplicaSet and pods
+- stuck-terminating: Resources of a type that have been terminating longer than older_than seconds, with the finalizers holding them (reported, never removed)
+- deployment-readiness: Desired, ready, available and updated replicas of every deployment in a namespace, flagging those not fully ready
 
 Examples:
 - Logs for default container: operation='logs', resource='', args='nginx'
@@ -291,29 +425,86 @@ Examples:
 - Logs with selector: operation='logs', resource='', args='-l app=nginx --all-containers=true'
 - Get events: operation='events', resource='', args='--all-namespaces'
 - Get events namespace: operation='events', resource='', args='-n default'
+- Watch warnings for a minute: operation='events', resource='', args='-n default', watch=true, duration=60
 - Top pods: operation='top', resource='pod', args=''
 - Top nodes: operation='top', resource='node', args=''
 - Top with containers: operation='top', resource='pod', args='POD_NAME --containers'
+- Top as JSON: operation='top', resource='node', args='', structured=true
 - Exec command: operation='exec', resource='', args='mypod -n NAMESPACE -- date'
 - Copy to pod: operation='cp', resource='', args='/tmp/foo_dir some-pod:/tmp/bar_dir'
 - Copy from pod: operation='cp', resource='', args='some-namespace/some-pod:/tmp/foo /tmp/bar'
-- Copy with container: operation='cp', resource='', args='/tmp/foo some-pod:/tmp/bar -c specific-container'`
-
-	return mcp.NewTool("kubectl_diagnostics",
+- Copy with container: operation='cp', resource='', args='/tmp/foo some-pod:/tmp/bar', container='specific-container'
+- Container logs: operation='logs', resource='', args='mypod -n NAMESPACE --tail=100', container='sidecar'
+- Follow logs for a minute: operation='logs', resource='', args='mypod -n NAMESPACE --tail=20', follow=true, max_duration=60
+- Workload tree: operation='describe-tree', resource='deployment', args='nginx -n default'
+- Pod diagnosis: operation='pod-diagnosis', resource='', args='web-0 -n default'
+- Orphaned replicasets: operation='orphans', resource='replicasets', args='-n default'
+- Deployment logs: operation='deployment-logs', resource='', args='deployment/web -n shop --tail=50'
+- Quota headroom: operation='quota-status', resource='', args='-n shop'
+- Unschedulable pods: operation='pending-pods', resource='', args=''
+- Pod probes: operation='probes', resource='', args='web-0 -n shop'
+- Deployment probes: operation='probes', resource='', args='deployment/web -n shop'
+- Node headroom: operation='node-capacity', resource='', args=''
+- One node's headroom: operation='node-capacity', resource='', args='aks-nodepool1-0'
+- Pull secret problems: operation='pull-secrets', resource='', args='-n shop'
+- Scheduled jobs: operation='cronjobs', resource='', args='-n shop'
+- Before a drain: operation='drain-check', resource='', args='aks-nodepool1-0'
+- Ingress routes: operation='ingress-routes', resource='', args='-n shop'
+- Flapping pods: operation='restarts', resource='', args='-n shop'
+- Service readiness: operation='service-endpoints', resource='', args='checkout -n shop'
+- Stuck rollout: operation='rollout-stall', resource='', args='web -n shop'
+- Stuck deletions: operation='stuck-terminating', resource='persistentvolumeclaims', args='-n shop', older_than=600
+- Stuck namespaces: operation='stuck-terminating', resource='namespaces', args=''
+- Namespace health: operation='deployment-readiness', resource='', args='-n shop'`
+
+	options := []mcp.ToolOption{
 		mcp.WithDescription(description),
 		mcp.WithString("operation",
 			mcp.Required(),
-			mcp.Description("The operation to perform: logs, events, top, exec, cp"),
+			mcp.Description("The operation to perform: logs, events, top, exec, cp, describe-tree, pod-diagnosis, orphans, deployment-logs, quota-status, pending-pods, probes, node-capacity, pull-secrets, cronjobs, drain-check, ingress-routes, restarts, service-endpoints, rollout-stall, stuck-terminating, deployment-readiness"),
 		),
 		mcp.WithString("resource",
 			mcp.Required(),
-			mcp.Description("The resource type: 'node'/'pod' for top, empty string '' for logs/events/exec/cp"),
+			mcp.Description("The resource type: 'node'/'pod' for top, 'deployment'/'statefulset' for describe-tree, the type to check for orphans or stuck-terminating, empty string '' for logs/events/exec/cp/pod-diagnosis/deployment-logs/quota-status/pending-pods/probes/node-capacity/pull-secrets/cronjobs/drain-check/ingress-routes/restarts/service-endpoints/rollout-stall/deployment-readiness"),
 		),
 		mcp.WithString("args",
 			mcp.Required(),
 			mcp.Description("Resource names and operation-specific flags"),
 		),
-	)
+		mcp.WithBoolean("structured",
+			mcp.Description("Return parsed JSON instead of raw text where supported (top: cpu in millicores, memory in MiB). Falls back to raw output if parsing fails"),
+		),
+		mcp.WithString("container",
+			mcp.Description("Container to target for logs, deployment-logs, exec and cp (adds -c; don't also pass -c in args)"),
+		),
+		mcp.WithBoolean("watch",
+			mcp.Description("For events: watch new events and return them when duration elapses or max_events are collected (also enabled by --watch in args)"),
+		),
+		mcp.WithNumber("duration",
+			mcp.Description("For events watch: seconds to watch, default 30, at most 300"),
+		),
+		mcp.WithNumber("max_events",
+			mcp.Description("For events watch: stop after this many events, default 100"),
+		),
+		mcp.WithBoolean("follow",
+			mcp.Description("For logs: follow new output (also enabled by -f/--follow in args) until the stream ends or max_bytes, max_duration or the timeout is reached"),
+		),
+		mcp.WithNumber("max_bytes",
+			mcp.Description("For logs follow: stop after this many bytes of output, default 262144, at most 4194304"),
+		),
+		mcp.WithNumber("max_duration",
+			mcp.Description("For logs follow: seconds to follow, default 60, at most 300"),
+		),
+		mcp.WithBoolean("include_normal",
+			mcp.Description("For events watch: include Normal events as well as Warning events"),
+		),
+		mcp.WithNumber("older_than",
+			mcp.Description("For stuck-terminating: seconds a resource must have been terminating to be reported, default 300"),
+		),
+	}
+	options = append(options, withScopeParams()...)
+	options = append(options, withEchoParam(), withTimeoutParam(), withServerParam(), withTenantParam())
+	return mcp.NewTool("kubectl_diagnostics", options...)
 }
 
 // createClusterTool creates the cluster information tool
@@ -334,9 +525,11 @@ Examples:
 - Non-namespaced resources: operation='api-resources', resource='', args='--namespaced=false'
 - Resources by group: operation='api-resources', resource='', args='--api-group=rbac.authorization.k8s.io'
 - API versions: operation='api-versions', resource='', args=''
+- API versions of a group: operation='api-versions', resource='', args='', group='apps', structured=true
 - Explain pod: operation='explain', resource='pods', args=''
 - Explain field: operation='explain', resource='pods.spec.containers', args=''
-- Explain with version: operation='explain', resource='deployments', args='--api-version=apps/v1'`
+- Explain with version: operation='explain', resource='deployments', args='--api-version=apps/v1'
+- Explain as JSON: operation='explain', resource='pods.spec.containers', args='', structured=true`
 
 	return mcp.NewTool("kubectl_cluster",
 		mcp.WithDescription(description),
@@ -352,6 +545,16 @@ Examples:
 			mcp.Required(),
 			mcp.Description("Additional flags and options"),
 		),
+		mcp.WithBoolean("structured",
+			mcp.Description("For api-versions, return a JSON list of {group, version} with the core group as \"\"; for explain, return {kind, version, field, type, description, fields} with each field as {field, type, description, required}. Falls back to raw output if parsing fails"),
+		),
+		mcp.WithString("group",
+			mcp.Description("For api-versions, only return versions of this API group ('core' for the core group)"),
+		),
+		withEchoParam(),
+		withTimeoutParam(),
+		withServerParam(),
+		withTenantParam(),
 	)
 }
 
@@ -381,14 +584,103 @@ Returns JSON with:
   "validation_enabled": true|false,
   "validation_error": "error message if any",
   "available_tools": ["kubectl_resources", "kubectl_diagnostics", ...],
+  "admin_confirm_token": "token for the next admin operation (only with --require-admin-confirm)",
   "timestamp": "2025-10-03T10:59:48Z"
-}`
+}
+
+When the server requires admin confirmation, each call issues a new single-use admin_confirm_token
+and invalidates the previous one. Pass it as confirm_token to the admin operation.`
 
 	return mcp.NewTool("kubectl_check_permissions",
 		mcp.WithDescription(description),
 	)
 }
 
+// createEffectivePolicyTool creates the per-call policy tool
+func createEffectivePolicyTool() mcp.Tool {
+	description := `Resolve the policy a tool call would run under for the routing parameters you are about to use,
+without running anything. Pass the same as, as_group, server and tenant you will pass to the call.
+
+It returns JSON with:
+{
+  "access_level": "readonly|readwrite|admin",
+  "identity": {"user": "alice", "groups": ["dev"]},
+  "tenant": "tenant the call is routed to",
+  "server": "API server the call is sent to",
+  "allowed": true|false,
+  "reason": "why the routing would be refused (only when allowed is false)",
+  "namespace_scope": {"restricted": true, "allowed": ["team-a", "team-.*"], "default_namespace": "team-a"},
+  "namespace_overrides": [{"namespace": "kube-system", "rule": "exec and cp may not target pods here"}]
+}
+
+default_namespace is the namespace used when a command names none, which an impersonated user may have
+mapped. namespace_overrides lists rules that apply to single namespaces.
+
+Examples:
+- Policy for the server's own identity: No parameters required, just call the tool
+- Policy when impersonating: as='alice', as_group='dev'`
+
+	options := []mcp.ToolOption{mcp.WithDescription(description)}
+	options = append(options, withImpersonationParams()...)
+	options = append(options, withServerParam(), withTenantParam())
+	return mcp.NewTool("kubectl_effective_policy", options...)
+}
+
+// createWorkerStatsTool creates the remote execution transport stats tool
+func createWorkerStatsTool() mcp.Tool {
+	description := `Report runtime stats of the worker that sends commands to the remote agent over Pulsar.
+
+This is for debugging the remote execution layer, not the cluster. It returns JSON with:
+{
+  "pending": 2,
+  "requests_sent": 118,
+  "acks": 115,
+  "nacks": 1,
+  "reconnects": 0,
+  "last_receive": "2025-10-03T10:59:48Z",
+  "subscription": "active|reconnecting|not started",
+  "breaker": "closed|open"
+}
+
+pending counts requests still waiting for a response. Acks are responses matched to a request here;
+nacks are messages for requests this server isn't waiting on, left for another subscriber.
+The breaker opens when the agent stops answering, and requests are rejected until it answers a ping again.
+
+Examples:
+- Check the transport: No parameters required, just call the tool`
+
+	return mcp.NewTool("kubectl_worker_stats",
+		mcp.WithDescription(description),
+	)
+}
+
+// createRecentCommandsTool creates the recent tool calls tool
+func createRecentCommandsTool() mcp.Tool {
+	description := `List the most recent tool calls this server handled, oldest first, for debugging.
+
+The server keeps the last --recent-commands calls in memory (100 by default); older calls are dropped
+and nothing survives a restart. It returns JSON like:
+[
+  {
+    "time": "2025-10-03T10:59:48Z",
+    "tool": "kubectl_resources",
+    "command": "get pods -n default",
+    "decision": "allowed|denied",
+    "outcome": "ok or the error message"
+  }
+]
+
+decision is "denied" when the access level or security policy refused the call. Commands and
+errors are redacted like command output.
+
+Examples:
+- Review recent calls: No parameters required, just call the tool`
+
+	return mcp.NewTool("kubectl_recent_commands",
+		mcp.WithDescription(description),
+	)
+}
+
 // createConfigTool creates the configuration tool
 func createConfigTool(readOnly bool) mcp.Tool {
 	var description string
@@ -399,38 +691,54 @@ func createConfigTool(readOnly bool) mcp.Tool {
 
 Available operations:
 - diff: Diff the live version against what would be applied
+- drift: Check whether the live cluster matches an inline manifest, classifying drift as missing, extra or changed fields
+- namespace-diff: Compare a resource type across two namespaces: names found in only one, and spec differences of the rest
+- lint: Validate an inline manifest against the cluster's OpenAPI schema, returning errors by field path
 - auth: Inspect authorization (can-i)
 
 Examples:
 - Diff config: operation='diff', resource='', args='-f pod.json'
 - Diff from stdin: operation='diff', resource='', args='-f -'
 - Diff with selector: operation='diff', resource='', args='-f manifest.yaml -l app=nginx'
+- Diff as JSON: operation='diff', resource='', args='-f manifest.yaml', structured=true
+- Drift check: operation='drift', resource='', args='-n default', manifest='apiVersion: v1\nkind: ConfigMap\n...'
+- Compare namespaces: operation='namespace-diff', resource='deployments', args='staging prod'
+- Lint manifest: operation='lint', resource='', args='', manifest='apiVersion: apps/v1\nkind: Deployment\n...'
 - Check auth: operation='auth', resource='can-i', args='create pods --all-namespaces'
 - Check auth resource: operation='auth', resource='can-i', args='list deployments.apps'
 - Check auth as user: operation='auth', resource='can-i', args='list pods --as=system:serviceaccount:dev:foo -n prod'
 - List permissions: operation='auth', resource='can-i', args='--list --namespace=foo'`
-		operationDesc = "The operation to perform: diff, auth"
+		operationDesc = "The operation to perform: diff, drift, namespace-diff, lint, auth"
 	} else {
 		description = `Work with Kubernetes configurations.
 
 Available operations:
 - diff: Diff the live version against what would be applied
-- auth: Inspect authorization (can-i)
+- drift: Check whether the live cluster matches an inline manifest, classifying drift as missing, extra or changed fields
+- namespace-diff: Compare a resource type across two namespaces: names found in only one, and spec differences of the rest
+- lint: Validate an inline manifest against the cluster's OpenAPI schema, returning errors by field path
+- auth: Inspect authorization (can-i) or, in admin mode, reconcile RBAC roles and bindings (reconcile)
 - certificate: Manage certificate resources (approve, deny)
 
 Examples:
 - Diff config: operation='diff', resource='', args='-f pod.json'
 - Diff with selector: operation='diff', resource='', args='-f manifest.yaml -l app=nginx'
+- Diff as JSON: operation='diff', resource='', args='-f manifest.yaml', structured=true
+- Drift check: operation='drift', resource='', args='-n default', manifest='apiVersion: v1\nkind: ConfigMap\n...'
+- Compare namespaces: operation='namespace-diff', resource='deployments', args='staging prod'
+- Lint manifest: operation='lint', resource='', args='', manifest='apiVersion: apps/v1\nkind: Deployment\n...'
 - Check auth: operation='auth', resource='can-i', args='create pods --all-namespaces'
 - Check auth resource: operation='auth', resource='can-i', args='list deployments.apps'
 - Check auth as user: operation='auth', resource='can-i', args='list pods --as=system:serviceaccount:dev:foo -n prod'
 - List permissions: operation='auth', resource='can-i', args='--list --namespace=foo'
+- Reconcile RBAC: operation='auth', resource='reconcile', args='', manifest='apiVersion: rbac.authorization.k8s.io/v1\nkind: Role\n...'
+- Reconcile RBAC, dropping extra grants: operation='auth', resource='reconcile', args='--remove-extra-permissions --remove-extra-subjects', manifest='...'
 - Approve cert: operation='certificate', resource='approve', args='csr-name'
 - Deny cert: operation='certificate', resource='deny', args='csr-name'`
-		operationDesc = "The operation to perform: diff, auth, certificate"
+		operationDesc = "The operation to perform: diff, drift, namespace-diff, lint, auth, certificate"
 	}
 
-	return mcp.NewTool("kubectl_config",
+	options := []mcp.ToolOption{
 		mcp.WithDescription(description),
 		mcp.WithString("operation",
 			mcp.Required(),
@@ -438,12 +746,116 @@ Examples:
 		),
 		mcp.WithString("resource",
 			mcp.Required(),
-			mcp.Description("Subcommand for auth/certificate operations, or empty string '' for diff operation"),
+			mcp.Description("Subcommand for auth/certificate operations, the resource type to compare for namespace-diff, or empty string '' for diff, drift and lint operations"),
 		),
 		mcp.WithString("args",
 			mcp.Required(),
 			mcp.Description("Operation-specific arguments"),
 		),
+		mcp.WithBoolean("structured",
+			mcp.Description("For diff, return a JSON list of resources with change_type (create/update/delete) and field changes {path, old, new}. Falls back to the raw diff if parsing fails"),
+		),
+		mcp.WithString("manifest",
+			mcp.Description("For drift, the YAML or JSON manifest to compare with the live cluster; for lint, the manifest to validate; for auth reconcile, the Roles, RoleBindings, ClusterRoles and ClusterRoleBindings to reconcile"),
+		),
+	}
+	options = append(options, withEchoParam(), withTimeoutParam(), withServerParam(), withTenantParam())
+	if !readOnly {
+		options = append(options, withImpersonationParams()...)
+		options = append(options, withConfirmTokenParam())
+	}
+
+	return mcp.NewTool("kubectl_config", options...)
+}
+
+// withImpersonationParams declares the identity a command runs as
+func withImpersonationParams() []mcp.ToolOption {
+	return []mcp.ToolOption{
+		mcp.WithString("as",
+			mcp.Description("Username or service account to impersonate (adds --as)"),
+		),
+		mcp.WithString("as_group",
+			mcp.Description("Comma-separated groups to impersonate, requires as (adds --as-group)"),
+		),
+	}
+}
+
+// withPreflightParam declares the RBAC preflight check for write operations
+func withPreflightParam() mcp.ToolOption {
+	return mcp.WithBoolean("preflight",
+		mcp.Description("Run 'auth can-i' as the identity the command will run as (including as/as_group) and refuse the change if it is not allowed"),
+	)
+}
+
+// withReasonParam declares the reason recorded with a change
+func withReasonParam() mcp.ToolOption {
+	return mcp.WithString("reason",
+		mcp.Description("Why the change is made; recorded as the kubernetes.io/change-cause annotation when the server runs with --record-change-cause, and required for changes with --require-reason"),
+	)
+}
+
+// withIdempotencyKeyParam declares the key that makes a change safe to retry
+func withIdempotencyKeyParam() mcp.ToolOption {
+	return mcp.WithString("idempotency_key",
+		mcp.Description("Caller-chosen key for a change; a retry with the same key and command returns the first call's result instead of running again"),
+	)
+}
+
+// withScopeParams declares the namespace and label selector, structured forms of -n and -l
+func withScopeParams() []mcp.ToolOption {
+	return []mcp.ToolOption{
+		mcp.WithString("namespace",
+			mcp.Description("Namespace to run in (adds -n; don't also pass -n or -A in args)"),
+		),
+		mcp.WithString("selector",
+			mcp.Description("Label selector, e.g. 'app=web,tier!=cache' (adds -l; don't also pass -l in args)"),
+		),
+	}
+}
+
+// withEchoParam declares the option to return the assembled command instead of running it
+func withEchoParam() mcp.ToolOption {
+	return mcp.WithBoolean("echo",
+		mcp.Description("Return the full kubectl command that would run, including injected flags, without validating or executing it"),
+	)
+}
+
+// withTimeoutParam declares the per-call timeout
+func withTimeoutParam() mcp.ToolOption {
+	return mcp.WithNumber("timeout",
+		mcp.Description("Seconds to wait for the command, overriding the server's timeout for this call (capped by the server's maximum)"),
+	)
+}
+
+// withServerParam declares the API server override
+func withServerParam() mcp.ToolOption {
+	return mcp.WithString("server",
+		mcp.Description("API server URL to send the command to instead of the current context's (adds --server; must be on the server's allow-list)"),
+	)
+}
+
+// withTenantParam declares the tenant whose remote agent runs the command
+func withTenantParam() mcp.ToolOption {
+	return mcp.WithString("tenant",
+		mcp.Description("Tenant whose cluster agent runs the command instead of the default one (must be on the server's allow-list)"),
+	)
+}
+
+// withPatchBodyParam declares the structured patch body, an object or a JSON patch array,
+// which the tool schema helpers can't express
+func withPatchBodyParam() mcp.ToolOption {
+	return func(t *mcp.Tool) {
+		t.InputSchema.Properties["patch_body"] = map[string]any{
+			"type":        []string{"object", "array"},
+			"description": "For patch, the patch as an object (strategic and merge) or an array of operations (json); sent as -p with --type instead of writing quoted JSON in args",
+		}
+	}
+}
+
+// withConfirmTokenParam declares the confirmation token accepted by tools exposing admin operations
+func withConfirmTokenParam() mcp.ToolOption {
+	return mcp.WithString("confirm_token",
+		mcp.Description("Confirmation token from kubectl_check_permissions, required for admin operations when the server runs with --require-admin-confirm"),
 	)
 }
 
@@ -457,6 +869,9 @@ func GetKubectlToolNames() []string {
 		"kubectl_cluster",
 		"kubectl_config",
 		"kubectl_check_permissions",
+		"kubectl_effective_policy",
+		"kubectl_worker_stats",
+		"kubectl_recent_commands",
 	}
 }
 
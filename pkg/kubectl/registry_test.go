@@ -10,7 +10,7 @@ func TestRegisterKubectlTools(t *testing.T) {
 	tools := RegisterKubectlTools("admin")
 
 	// Verify we have the expected number of tools
-	expectedCount := 6
+	expectedCount := 10
 	if len(tools) != expectedCount {
 		t.Errorf("Expected %d consolidated tools, got %d", expectedCount, len(tools))
 	}
@@ -40,7 +40,7 @@ func TestConsolidatedToolDescriptions(t *testing.T) {
 	}{
 		{
 			toolName:           "kubectl_resources",
-			expectedOperations: []string{"get", "describe", "create", "delete", "apply", "patch", "replace"},
+			expectedOperations: []string{"get", "describe", "describe-yaml", "configmap-data", "create", "delete", "apply", "patch", "replace", "restore-last"},
 			expectedInDesc:     []string{"CRUD operations", "Examples:"},
 		},
 		{
@@ -55,7 +55,7 @@ func TestConsolidatedToolDescriptions(t *testing.T) {
 		},
 		{
 			toolName:           "kubectl_diagnostics",
-			expectedOperations: []string{"logs", "events", "top", "exec", "cp"},
+			expectedOperations: []string{"logs", "events", "top", "exec", "cp", "describe-tree", "pod-diagnosis", "orphans", "deployment-logs", "quota-status", "pending-pods", "probes", "node-capacity", "pull-secrets", "cronjobs", "drain-check", "ingress-routes", "restarts", "service-endpoints", "rollout-stall", "stuck-terminating", "deployment-readiness"},
 			expectedInDesc:     []string{"Diagnose", "debug", "Examples:"},
 		},
 		{
@@ -65,7 +65,7 @@ func TestConsolidatedToolDescriptions(t *testing.T) {
 		},
 		{
 			toolName:           "kubectl_config",
-			expectedOperations: []string{"diff", "auth", "certificate"},
+			expectedOperations: []string{"diff", "drift", "namespace-diff", "lint", "auth", "certificate"},
 			expectedInDesc:     []string{"configurations", "Examples:"},
 		},
 	}
@@ -136,6 +136,10 @@ func TestGetKubectlToolNames(t *testing.T) {
 		"kubectl_diagnostics",
 		"kubectl_cluster",
 		"kubectl_config",
+		"kubectl_check_permissions",
+		"kubectl_effective_policy",
+		"kubectl_worker_stats",
+		"kubectl_recent_commands",
 	}
 
 	if len(names) != len(expected) {
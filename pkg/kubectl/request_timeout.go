@@ -0,0 +1,21 @@
+package kubectl
+
+import (
+	"fmt"
+
+	"github.com/Azure/mcp-kubernetes/pkg/config"
+)
+
+// withRequestTimeout adds --request-timeout, set to the seconds the call waits for the
+// command, so kubectl gives up on a slow API server when the call does instead of running on
+// after nobody is waiting. A command that sets its own --request-timeout keeps it.
+func withRequestTimeout(command string, timeout int, cfg *config.ConfigData) string {
+	if cfg == nil || !cfg.InjectRequestTimeout || timeout <= 0 {
+		return command
+	}
+	cmdline, err := parseCommandLine(command)
+	if err != nil || cmdline.hasFlag("--request-timeout") {
+		return command
+	}
+	return insertFlags(command, []string{fmt.Sprintf("--request-timeout=%ds", timeout)})
+}
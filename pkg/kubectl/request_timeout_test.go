@@ -0,0 +1,109 @@
+package kubectl
+
+import (
+	"testing"
+)
+
+func TestKubectlToolExecutor_RequestTimeout(t *testing.T) {
+	var commands []string
+	worker := newTestWorker(t, func(command string) map[string]interface{} {
+		commands = append(commands, command)
+		return map[string]interface{}{"stdout": `{"items": []}`}
+	})
+	executor := NewKubectlToolExecutor(worker)
+	cfg := newTestConfig("admin")
+	cfg.InjectRequestTimeout = true
+
+	tests := []struct {
+		name     string
+		tool     string
+		op       string
+		resource string
+		args     string
+		timeout  interface{}
+		expected string
+	}{
+		{
+			name:     "injected with the time the worker waits",
+			tool:     "kubectl_resources",
+			op:       "get",
+			resource: "pods",
+			args:     "-n shop",
+			expected: "kubectl get pods -n shop --request-timeout=5s",
+		},
+		{
+			name:     "injected with the call's timeout",
+			tool:     "kubectl_resources",
+			op:       "get",
+			resource: "pods",
+			args:     "-n shop",
+			timeout:  float64(15),
+			expected: "kubectl get pods -n shop --request-timeout=15s",
+		},
+		{
+			name:     "caller's request-timeout kept",
+			tool:     "kubectl_resources",
+			op:       "get",
+			resource: "pods",
+			args:     "-n shop --request-timeout=5s",
+			expected: "kubectl get pods -n shop --request-timeout=5s",
+		},
+		{
+			name:     "caller's request-timeout as a separate argument kept",
+			tool:     "kubectl_resources",
+			op:       "get",
+			resource: "pods",
+			args:     "-n shop --request-timeout 0",
+			expected: "kubectl get pods -n shop --request-timeout 0",
+		},
+		{
+			name:     "injected before the exec command",
+			tool:     "kubectl_diagnostics",
+			op:       "exec",
+			args:     "web-0 -n shop -- date",
+			expected: "kubectl exec web-0 -n shop --request-timeout=5s -- date",
+		},
+		{
+			name:     "injected into the reads of composite operations",
+			tool:     "kubectl_diagnostics",
+			op:       "restarts",
+			args:     "-n shop",
+			expected: "kubectl get pods -n shop -o json --request-timeout=5s",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			commands = nil
+			params := map[string]interface{}{
+				"_tool_name": tt.tool,
+				"operation":  tt.op,
+				"resource":   tt.resource,
+				"args":       tt.args,
+			}
+			if tt.timeout != nil {
+				params["timeout"] = tt.timeout
+			}
+			if _, err := executor.Execute(params, cfg); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(commands) != 1 || commands[0] != tt.expected {
+				t.Errorf("commands = %q, want %q", commands, tt.expected)
+			}
+		})
+	}
+
+	// Turned off, commands are sent as given
+	cfg.InjectRequestTimeout = false
+	commands = nil
+	if _, err := executor.Execute(map[string]interface{}{
+		"_tool_name": "kubectl_resources",
+		"operation":  "get",
+		"resource":   "pods",
+		"args":       "-n shop",
+	}, cfg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(commands) != 1 || commands[0] != "kubectl get pods -n shop" {
+		t.Errorf("expected no request-timeout, got %q", commands)
+	}
+}
@@ -0,0 +1,125 @@
+package kubectl
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/Azure/mcp-kubernetes/pkg/config"
+)
+
+// restartsTopPods bounds the pods the restarts report lists
+const restartsTopPods = 10
+
+// restartReport is the pods of a namespace that restart most
+type restartReport struct {
+	Namespace string `json:"namespace"`
+	// PodsChecked counts the pods listed and Restarting those with at least one restart
+	PodsChecked int          `json:"pods_checked"`
+	Restarting  int          `json:"restarting"`
+	Pods        []podRestart `json:"pods"`
+}
+
+// podRestart is a pod's restart count summed over its containers
+type podRestart struct {
+	Name       string             `json:"name"`
+	Restarts   int64              `json:"restarts"`
+	Controller string             `json:"controller,omitempty"`
+	Containers []containerRestart `json:"containers"`
+}
+
+// containerRestart is a restarting container and how its last run ended
+type containerRestart struct {
+	Name     string `json:"name"`
+	Init     bool   `json:"init,omitempty"`
+	Restarts int64  `json:"restarts"`
+	// LastReason is the reason the previous run terminated, e.g. OOMKilled or Error
+	LastReason   string `json:"last_reason,omitempty"`
+	LastExitCode *int64 `json:"last_exit_code,omitempty"`
+	LastFinished string `json:"last_finished_at,omitempty"`
+}
+
+// restarts lists the pods of a namespace (-n, default "default", optionally narrowed with -l)
+// by their total container restarts, most first, and returns the pods that restarted, up to
+// restartsTopPods, with the reason each restarting container's last run ended. The list goes
+// through the same access and namespace checks as a direct get.
+func (e *KubectlToolExecutor) restarts(args string, cfg *config.ConfigData) (string, error) {
+	cmdline, err := parseCommandLine(args)
+	if err != nil {
+		return "", err
+	}
+	if len(cmdline.positionals) != 0 || cmdline.hasFlag("--all-namespaces") {
+		return "", fmt.Errorf("restarts takes a namespace (-n) and optionally -l, e.g. '-n default'")
+	}
+	namespace, err := namespaceFlag(cmdline, "default")
+	if err != nil {
+		return "", err
+	}
+	command := "get pods -n " + namespace + " -o json"
+	if selector, ok := cmdline.flag("--selector"); ok && selector != "" {
+		command += " -l " + shellQuote(selector)
+	}
+
+	pods, err := e.listObjects(command, cfg)
+	if err != nil {
+		return "", err
+	}
+	report := restartReport{Namespace: namespace, PodsChecked: len(pods), Pods: []podRestart{}}
+	for _, pod := range pods {
+		entry := podRestarts(pod)
+		if entry.Restarts > 0 {
+			report.Pods = append(report.Pods, entry)
+		}
+	}
+	report.Restarting = len(report.Pods)
+	sort.Slice(report.Pods, func(i, j int) bool {
+		if report.Pods[i].Restarts != report.Pods[j].Restarts {
+			return report.Pods[i].Restarts > report.Pods[j].Restarts
+		}
+		return report.Pods[i].Name < report.Pods[j].Name
+	})
+	if len(report.Pods) > restartsTopPods {
+		report.Pods = report.Pods[:restartsTopPods]
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode restarts: %w", err)
+	}
+	return string(data), nil
+}
+
+// podRestarts sums a pod's container restarts and reads how each restarting container's last
+// run terminated
+func podRestarts(pod map[string]interface{}) podRestart {
+	_, name := objectNamespacedName(pod)
+	entry := podRestart{Name: name, Containers: []containerRestart{}}
+	if refs := ownerReferences(pod); len(refs) > 0 {
+		entry.Controller = nestedString(refs[0], "kind") + "/" + nestedString(refs[0], "name")
+	}
+	for _, field := range []string{"initContainerStatuses", "containerStatuses"} {
+		statuses, _ := nestedValue(pod, "status", field).([]interface{})
+		for _, raw := range statuses {
+			status, _ := raw.(map[string]interface{})
+			restarts := nestedInt(status, "restartCount")
+			if restarts == 0 {
+				continue
+			}
+			entry.Restarts += restarts
+			container := containerRestart{
+				Name:     nestedString(status, "name"),
+				Init:     field == "initContainerStatuses",
+				Restarts: restarts,
+			}
+			if terminated, ok := nestedValue(status, "lastState", "terminated").(map[string]interface{}); ok {
+				container.LastReason = nestedString(terminated, "reason")
+				exitCode := nestedInt(terminated, "exitCode")
+				container.LastExitCode = &exitCode
+				container.LastFinished = nestedString(terminated, "finishedAt")
+			}
+			entry.Containers = append(entry.Containers, container)
+		}
+	}
+	sort.SliceStable(entry.Containers, func(i, j int) bool { return entry.Containers[i].Restarts > entry.Containers[j].Restarts })
+	return entry
+}
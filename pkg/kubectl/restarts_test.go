@@ -0,0 +1,98 @@
+package kubectl
+
+import (
+	"testing"
+)
+
+const restartPodsJSON = `{
+  "items": [
+    {
+      "metadata": {"name": "web-1", "namespace": "shop",
+        "ownerReferences": [{"kind": "ReplicaSet", "name": "web-7d4b9", "controller": true}]},
+      "status": {"containerStatuses": [
+        {"name": "web", "restartCount": 3, "lastState": {"terminated": {"reason": "Error", "exitCode": 1, "finishedAt": "2024-05-01T10:00:00Z"}}},
+        {"name": "proxy", "restartCount": 0}
+      ]}
+    },
+    {
+      "metadata": {"name": "worker-0", "namespace": "shop"},
+      "status": {
+        "initContainerStatuses": [{"name": "migrate", "restartCount": 2, "lastState": {"terminated": {"reason": "Completed", "exitCode": 0}}}],
+        "containerStatuses": [{"name": "worker", "restartCount": 12, "lastState": {"terminated": {"reason": "OOMKilled", "exitCode": 137, "finishedAt": "2024-05-01T11:00:00Z"}}}]
+      }
+    },
+    {
+      "metadata": {"name": "db-0", "namespace": "shop"},
+      "status": {"containerStatuses": [{"name": "db", "restartCount": 0}]}
+    },
+    {
+      "metadata": {"name": "api-1", "namespace": "shop"},
+      "status": {"containerStatuses": [{"name": "api", "restartCount": 3, "lastState": {}}]}
+    }
+  ]
+}`
+
+func TestKubectlToolExecutor_Restarts(t *testing.T) {
+	cfg := newTestConfig("readonly")
+	cfg.SecurityConfig.SetAllowedNamespaces("shop")
+
+	runDiagnosticCases(t, "restarts", cfg, map[string]string{
+		"kubectl get pods -n shop -o json": restartPodsJSON,
+		"kubectl get pods -n shop -o json -l 'app=db'": `{"items": [
+			{"metadata": {"name": "db-0", "namespace": "shop"}, "status": {"containerStatuses": [{"name": "db", "restartCount": 0}]}}]}`,
+	}, []diagnosticCase{
+		{
+			name: "sorted by restarts",
+			args: "-n shop",
+			check: func(t *testing.T, output string, _ []string) {
+				report := decodeReport[restartReport](t, output)
+				if report.PodsChecked != 4 || report.Restarting != 3 || len(report.Pods) != 3 {
+					t.Fatalf("expected 3 of 4 pods restarting, got %+v", report)
+				}
+
+				// Sorted by total restarts, then name
+				var order []string
+				for _, pod := range report.Pods {
+					order = append(order, pod.Name)
+				}
+				if order[0] != "worker-0" || order[1] != "api-1" || order[2] != "web-1" {
+					t.Errorf("expected worker-0, api-1, web-1, got %v", order)
+				}
+
+				worker0 := report.Pods[0]
+				if worker0.Restarts != 14 || len(worker0.Containers) != 2 {
+					t.Fatalf("expected worker-0 to sum 14 restarts over 2 containers, got %+v", worker0)
+				}
+				oom := worker0.Containers[0]
+				if oom.Name != "worker" || oom.LastReason != "OOMKilled" || oom.LastExitCode == nil || *oom.LastExitCode != 137 ||
+					oom.LastFinished != "2024-05-01T11:00:00Z" {
+					t.Errorf("expected the OOMKilled container first, got %+v", oom)
+				}
+				if init := worker0.Containers[1]; !init.Init || init.LastReason != "Completed" {
+					t.Errorf("expected the init container's last reason, got %+v", init)
+				}
+
+				web := report.Pods[2]
+				if web.Controller != "ReplicaSet/web-7d4b9" || len(web.Containers) != 1 || web.Containers[0].LastReason != "Error" {
+					t.Errorf("expected web-1's restarting container only, got %+v", web)
+				}
+				if api := report.Pods[1]; api.Containers[0].LastReason != "" || api.Containers[0].LastExitCode != nil {
+					t.Errorf("expected no last termination for api-1, got %+v", api.Containers[0])
+				}
+			},
+		},
+		{
+			// Pods that never restarted are checked but not listed
+			name: "nothing restarting",
+			args: "-n shop -l app=db",
+			check: func(t *testing.T, output string, _ []string) {
+				if report := decodeReport[restartReport](t, output); report.PodsChecked != 1 || report.Restarting != 0 || report.Pods == nil || len(report.Pods) != 0 {
+					t.Errorf("expected one pod checked and none listed, got %+v", report)
+				}
+			},
+		},
+		{name: "namespace carrying flags refused", args: "-n 'shop --as=admin'", refused: "invalid namespace"},
+		{name: "names refused", args: "web-1 -n shop", refused: "takes a namespace"},
+		{name: "namespace outside the allow-list", args: "-n kube-system", refused: "kube-system"},
+	})
+}
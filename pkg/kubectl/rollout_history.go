@@ -0,0 +1,83 @@
+package kubectl
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// rolloutRevision is one revision of a rollout history. Template is only filled in for the
+// history of a single revision, where kubectl prints its pod template.
+type rolloutRevision struct {
+	Revision    int    `json:"revision"`
+	ChangeCause string `json:"change_cause"`
+	Template    string `json:"template,omitempty"`
+}
+
+// rolloutRevisionHeader matches the first line of `rollout history --revision=N` output
+var rolloutRevisionHeader = regexp.MustCompile(`^\S+ with revision #([0-9]+)$`)
+
+// rolloutChangeCause matches the change-cause annotation in a revision's pod template
+var rolloutChangeCause = regexp.MustCompile(`kubernetes\.io/change-cause:\s*(.*)$`)
+
+// parseRolloutHistory parses `rollout history` output: the REVISION/CHANGE-CAUSE table into
+// [{revision, change_cause}], or a single revision's output into {revision, change_cause,
+// template}. A missing change cause ("<none>") becomes "".
+func parseRolloutHistory(output string) (interface{}, error) {
+	lines := strings.Split(strings.TrimRight(output, "\n"), "\n")
+	if len(lines) > 0 {
+		if match := rolloutRevisionHeader.FindStringSubmatch(strings.TrimSpace(lines[0])); match != nil {
+			return parseRolloutRevision(match[1], lines[1:])
+		}
+	}
+
+	var revisions []rolloutRevision
+	header := false
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if !header {
+			// The table is preceded by the resource name, e.g. "deployment.apps/web"
+			if len(fields) == 2 && fields[0] == "REVISION" && fields[1] == "CHANGE-CAUSE" {
+				header = true
+			}
+			continue
+		}
+		revision, err := strconv.Atoi(fields[0])
+		if err != nil {
+			return nil, errMalformedTable
+		}
+		cause := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), fields[0]))
+		if cause == "<none>" {
+			cause = ""
+		}
+		revisions = append(revisions, rolloutRevision{Revision: revision, ChangeCause: cause})
+	}
+	if !header {
+		return nil, errMalformedTable
+	}
+	if revisions == nil {
+		revisions = []rolloutRevision{}
+	}
+	return revisions, nil
+}
+
+// parseRolloutRevision parses the pod template printed for a single revision
+func parseRolloutRevision(number string, lines []string) (interface{}, error) {
+	revision, err := strconv.Atoi(number)
+	if err != nil {
+		return nil, fmt.Errorf("invalid revision '%s'", number)
+	}
+	result := rolloutRevision{Revision: revision}
+	for _, line := range lines {
+		if match := rolloutChangeCause.FindStringSubmatch(line); match != nil {
+			result.ChangeCause = strings.TrimSpace(match[1])
+			break
+		}
+	}
+	result.Template = strings.Join(lines, "\n")
+	return result, nil
+}
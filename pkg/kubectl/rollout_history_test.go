@@ -0,0 +1,96 @@
+package kubectl
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const rolloutHistoryOutput = `deployment.apps/web
+REVISION  CHANGE-CAUSE
+1         <none>
+2         kubectl set image deployment/web web=nginx:1.25 --record=true
+3         bump to 1.26
+
+`
+
+const rolloutRevisionOutput = `deployment.apps/web with revision #3
+Pod Template:
+  Labels:	app=web
+	pod-template-hash=5d8f7c9b6
+  Annotations:	kubernetes.io/change-cause: bump to 1.26
+  Containers:
+   web:
+    Image:	nginx:1.26
+    Port:	80/TCP
+`
+
+func TestParseRolloutHistory(t *testing.T) {
+	value, err := parseRolloutHistory(rolloutHistoryOutput)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	revisions := value.([]rolloutRevision)
+	expected := []rolloutRevision{
+		{Revision: 1, ChangeCause: ""},
+		{Revision: 2, ChangeCause: "kubectl set image deployment/web web=nginx:1.25 --record=true"},
+		{Revision: 3, ChangeCause: "bump to 1.26"},
+	}
+	if len(revisions) != len(expected) {
+		t.Fatalf("revisions = %+v, want %+v", revisions, expected)
+	}
+	for i := range expected {
+		if revisions[i] != expected[i] {
+			t.Errorf("revisions[%d] = %+v, want %+v", i, revisions[i], expected[i])
+		}
+	}
+
+	value, err = parseRolloutHistory(rolloutRevisionOutput)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	revision := value.(rolloutRevision)
+	if revision.Revision != 3 || revision.ChangeCause != "bump to 1.26" || !strings.Contains(revision.Template, "Image:\tnginx:1.26") {
+		t.Errorf("unexpected revision: %+v", revision)
+	}
+
+	if _, err := parseRolloutHistory("error: no rollout history found"); err == nil {
+		t.Error("expected output without a history table to fail parsing")
+	}
+}
+
+func TestKubectlToolExecutor_RolloutHistoryRevision(t *testing.T) {
+	worker := newTestWorker(t, func(command string) map[string]interface{} {
+		if command != "kubectl rollout history deployment/web -n shop --revision=3" {
+			return map[string]interface{}{"error": "unexpected command: " + command}
+		}
+		return map[string]interface{}{"stdout": rolloutRevisionOutput}
+	})
+	executor := NewKubectlToolExecutor(worker)
+
+	result, err := executor.Execute(map[string]interface{}{
+		"_tool_name": "kubectl_workloads",
+		"operation":  "rollout",
+		"resource":   "history",
+		"args":       "deployment/web -n shop",
+		"revision":   float64(3),
+		"structured": true,
+	}, newTestConfig("readwrite"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var revision rolloutRevision
+	if err := json.Unmarshal([]byte(result), &revision); err != nil || revision.Revision != 3 {
+		t.Errorf("result is not revision 3: %v\n%s", err, result)
+	}
+
+	if _, err := executor.Execute(map[string]interface{}{
+		"_tool_name": "kubectl_workloads",
+		"operation":  "rollout",
+		"resource":   "status",
+		"args":       "deployment/web -n shop",
+		"revision":   float64(3),
+	}, newTestConfig("readwrite")); err == nil || !strings.Contains(err.Error(), "revision is only supported for rollout history") {
+		t.Errorf("expected revision to be refused for rollout status, got %v", err)
+	}
+}
@@ -0,0 +1,296 @@
+package kubectl
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Azure/mcp-kubernetes/pkg/config"
+)
+
+// Reasons a rollout-stall classifies a deployment under, most fundamental first: a cause
+// earlier in the list keeps the pods of later ones from getting that far
+const (
+	stallQuotaExceeded         = "QuotaExceeded"
+	stallFailedCreate          = "FailedCreate"
+	stallInsufficientResources = "InsufficientResources"
+	stallUnschedulable         = "Unschedulable"
+	stallImagePull             = "ImagePull"
+	stallContainerConfig       = "ContainerConfigError"
+	stallCrashLoop             = "CrashLoop"
+	stallReadinessProbe        = "ReadinessProbeFailing"
+	stallDeadlineExceeded      = "ProgressDeadlineExceeded"
+	stallProgressing           = "Progressing"
+)
+
+// stallReasonOrder ranks the reasons for picking the one that explains the stall
+var stallReasonOrder = []string{
+	stallQuotaExceeded, stallFailedCreate, stallInsufficientResources, stallUnschedulable,
+	stallImagePull, stallContainerConfig, stallCrashLoop, stallReadinessProbe,
+	stallDeadlineExceeded, stallProgressing,
+}
+
+// stallWaitReasons maps container waiting reasons to the stall they cause
+var stallWaitReasons = map[string]string{
+	"ImagePullBackOff":           stallImagePull,
+	"ErrImagePull":               stallImagePull,
+	"InvalidImageName":           stallImagePull,
+	"CreateContainerConfigError": stallContainerConfig,
+	"CreateContainerError":       stallContainerConfig,
+	"CrashLoopBackOff":           stallCrashLoop,
+	"RunContainerError":          stallCrashLoop,
+}
+
+// rolloutStall is why a deployment's rollout isn't completing, with the evidence for it
+type rolloutStall struct {
+	Deployment string `json:"deployment"`
+	Namespace  string `json:"namespace"`
+	// Revision is the deployment's current revision and ReplicaSet the one rolling it out
+	Revision   string `json:"revision,omitempty"`
+	ReplicaSet string `json:"replicaset,omitempty"`
+	Desired    int64  `json:"desired"`
+	Updated    int64  `json:"updated"`
+	Ready      int64  `json:"ready"`
+	Available  int64  `json:"available"`
+	Stalled    bool   `json:"stalled"`
+	// Reason is the cause that explains the stall, or "" when the rollout is complete
+	Reason      string                `json:"reason"`
+	Conditions  []deploymentCondition `json:"conditions"`
+	Causes      []stallCause          `json:"causes"`
+	PodsChecked int                   `json:"pods_checked"`
+}
+
+// deploymentCondition is one of the conditions of a deployment or its ReplicaSet
+type deploymentCondition struct {
+	Type    string `json:"type"`
+	Status  string `json:"status"`
+	Reason  string `json:"reason,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// stallCause is one reason the new pods aren't becoming available, and the pods it affects
+type stallCause struct {
+	Reason  string   `json:"reason"`
+	Message string   `json:"message,omitempty"`
+	Pods    []string `json:"pods,omitempty"`
+}
+
+// rolloutStall classifies why a deployment (named in args, in -n or "default") isn't
+// progressing. It reads the deployment's Progressing, Available and ReplicaFailure
+// conditions, its newest ReplicaSet's ReplicaFailure condition, where quota and admission
+// failures show up, and the pods of that ReplicaSet: scheduling failures, image pulls,
+// container config errors, crash loops and running containers that never turn ready. Every
+// lookup goes through the same access and namespace checks as a direct get.
+func (e *KubectlToolExecutor) rolloutStall(args string, cfg *config.ConfigData) (string, error) {
+	cmdline, err := parseCommandLine(args)
+	if err != nil {
+		return "", err
+	}
+	if len(cmdline.positionals) != 1 {
+		return "", fmt.Errorf("rollout-stall requires exactly one deployment name")
+	}
+	name := cmdline.positionals[0]
+	if kind, deployment, ok := strings.Cut(name, "/"); ok {
+		if describeTreeKinds[strings.ToLower(kind)] != "deployments" {
+			return "", fmt.Errorf("rollout-stall requires a deployment, got '%s'", name)
+		}
+		name = deployment
+	}
+	if !objectName.MatchString(name) {
+		return "", fmt.Errorf("invalid deployment name '%s'", name)
+	}
+	namespace, err := namespaceFlag(cmdline, "default")
+	if err != nil {
+		return "", err
+	}
+
+	deployment, err := e.getObject(fmt.Sprintf("get deployments %s -n %s -o json", name, namespace), cfg)
+	if err != nil {
+		return "", err
+	}
+	desired := int64(1)
+	if _, set := nestedValue(deployment, "spec", "replicas").(float64); set {
+		desired = nestedInt(deployment, "spec", "replicas")
+	}
+	report := rolloutStall{
+		Deployment: name,
+		Namespace:  namespace,
+		Revision:   nestedString(deployment, "metadata", "annotations", "deployment.kubernetes.io/revision"),
+		Desired:    desired,
+		Updated:    nestedInt(deployment, "status", "updatedReplicas"),
+		Ready:      nestedInt(deployment, "status", "readyReplicas"),
+		Available:  nestedInt(deployment, "status", "availableReplicas"),
+		Conditions: []deploymentCondition{},
+		Causes:     []stallCause{},
+	}
+	causes := map[string]*stallCause{}
+	addCause := func(reason, message, pod string) {
+		cause, ok := causes[reason]
+		if !ok {
+			cause = &stallCause{Reason: reason, Message: message}
+			causes[reason] = cause
+		}
+		if pod != "" {
+			cause.Pods = append(cause.Pods, pod)
+		}
+	}
+
+	progressing := ""
+	for _, condition := range objectConditions(deployment) {
+		report.Conditions = append(report.Conditions, condition)
+		switch condition.Type {
+		case "Progressing":
+			progressing = condition.Status
+			if condition.Status == "False" && condition.Reason == stallDeadlineExceeded {
+				addCause(stallDeadlineExceeded, condition.Message, "")
+			}
+		case "ReplicaFailure":
+			if condition.Status == "True" {
+				addCause(replicaFailureReason(condition.Message), condition.Message, "")
+			}
+		}
+	}
+
+	// The newest ReplicaSet is the one the rollout is scaling up
+	replicaSets, err := e.listObjects("get replicasets -n "+namespace+" -o json", cfg)
+	if err != nil {
+		return "", err
+	}
+	replicaSets = ownedBy(replicaSets, objectUID(deployment))
+	var current map[string]interface{}
+	for _, rs := range replicaSets {
+		if current == nil || replicaSetRevision(rs) > replicaSetRevision(current) {
+			current = rs
+		}
+	}
+	if current != nil {
+		_, report.ReplicaSet = objectNamespacedName(current)
+		for _, condition := range objectConditions(current) {
+			if condition.Type == "ReplicaFailure" && condition.Status == "True" {
+				addCause(replicaFailureReason(condition.Message), condition.Message, "")
+			}
+		}
+
+		podsCommand := "get pods -n " + namespace + " -o json"
+		if selector := nestedStringMap(deployment, "spec", "selector", "matchLabels"); len(selector) > 0 {
+			podsCommand = fmt.Sprintf("get pods -n %s -l %s -o json", namespace, formatSelector(selector))
+		}
+		pods, err := e.listObjects(podsCommand, cfg)
+		if err != nil {
+			return "", err
+		}
+		for _, pod := range ownedBy(pods, objectUID(current)) {
+			report.PodsChecked++
+			_, podName := objectNamespacedName(pod)
+			for _, cause := range podStallCauses(pod) {
+				addCause(cause.Reason, cause.Message, podName)
+			}
+		}
+	}
+
+	complete := report.Updated >= report.Desired && report.Available >= report.Desired && report.Ready >= report.Desired
+	if len(causes) == 0 && !complete && progressing != "False" {
+		addCause(stallProgressing, "the rollout is progressing with no failure found yet", "")
+	}
+	for _, reason := range stallReasonOrder {
+		if cause, ok := causes[reason]; ok {
+			sort.Strings(cause.Pods)
+			report.Causes = append(report.Causes, *cause)
+		}
+	}
+	if len(report.Causes) > 0 {
+		report.Reason = report.Causes[0].Reason
+	}
+	report.Stalled = report.Reason != "" && report.Reason != stallProgressing
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode rollout stall: %w", err)
+	}
+	return string(data), nil
+}
+
+// objectConditions reads the status conditions of a deployment or ReplicaSet
+func objectConditions(obj map[string]interface{}) []deploymentCondition {
+	var conditions []deploymentCondition
+	raw, _ := nestedValue(obj, "status", "conditions").([]interface{})
+	for _, item := range raw {
+		condition, _ := item.(map[string]interface{})
+		conditions = append(conditions, deploymentCondition{
+			Type:    nestedString(condition, "type"),
+			Status:  nestedString(condition, "status"),
+			Reason:  nestedString(condition, "reason"),
+			Message: nestedString(condition, "message"),
+		})
+	}
+	return conditions
+}
+
+// replicaFailureReason classifies a ReplicaFailure condition: pods refused for exceeding a
+// ResourceQuota, or failing to be created for any other reason
+func replicaFailureReason(message string) string {
+	if strings.Contains(message, "exceeded quota") {
+		return stallQuotaExceeded
+	}
+	return stallFailedCreate
+}
+
+// replicaSetRevision reads the deployment revision a ReplicaSet was created for
+func replicaSetRevision(rs map[string]interface{}) int {
+	revision, _ := strconv.Atoi(nestedString(rs, "metadata", "annotations", "deployment.kubernetes.io/revision"))
+	return revision
+}
+
+// podStallCauses explains why a pod of the rollout isn't available: a scheduling failure, a
+// container that can't start or keeps crashing, or one that runs but never passes its
+// readiness probe
+func podStallCauses(pod map[string]interface{}) []stallCause {
+	conditions, _ := nestedValue(pod, "status", "conditions").([]interface{})
+	for _, raw := range conditions {
+		condition, _ := raw.(map[string]interface{})
+		if nestedString(condition, "type") != "PodScheduled" || nestedString(condition, "status") != "False" {
+			continue
+		}
+		message := nestedString(condition, "message")
+		if strings.Contains(message, "Insufficient ") {
+			return []stallCause{{Reason: stallInsufficientResources, Message: message}}
+		}
+		return []stallCause{{Reason: stallUnschedulable, Message: message}}
+	}
+
+	var causes []stallCause
+	seen := map[string]bool{}
+	var notReady []string
+	for _, field := range []string{"initContainerStatuses", "containerStatuses"} {
+		statuses, _ := nestedValue(pod, "status", field).([]interface{})
+		for _, raw := range statuses {
+			status, _ := raw.(map[string]interface{})
+			container := nestedString(status, "name")
+			waiting := nestedString(status, "state", "waiting", "reason")
+			if reason, ok := stallWaitReasons[waiting]; ok {
+				if !seen[reason] {
+					seen[reason] = true
+					message := fmt.Sprintf("container '%s' is waiting: %s", container, waiting)
+					if detail := nestedString(status, "state", "waiting", "message"); detail != "" {
+						message += ": " + detail
+					}
+					causes = append(causes, stallCause{Reason: reason, Message: message})
+				}
+				continue
+			}
+			ready, _ := status["ready"].(bool)
+			if field == "containerStatuses" && !ready && nestedValue(status, "state", "running") != nil {
+				notReady = append(notReady, container)
+			}
+		}
+	}
+	if len(causes) == 0 && len(notReady) > 0 {
+		causes = append(causes, stallCause{
+			Reason:  stallReadinessProbe,
+			Message: fmt.Sprintf("container '%s' is running but not ready", strings.Join(notReady, "', '")),
+		})
+	}
+	return causes
+}
@@ -0,0 +1,127 @@
+package kubectl
+
+import (
+	"strings"
+	"testing"
+)
+
+const stalledDeploymentJSON = `{
+  "metadata": {"name": "web", "namespace": "shop", "uid": "dep-web",
+    "annotations": {"deployment.kubernetes.io/revision": "2"}},
+  "spec": {"replicas": 3, "selector": {"matchLabels": {"app": "web"}}},
+  "status": {
+    "updatedReplicas": 3, "readyReplicas": 1, "availableReplicas": 1,
+    "conditions": [
+      {"type": "Available", "status": "False", "reason": "MinimumReplicasUnavailable", "message": "Deployment does not have minimum availability."},
+      {"type": "Progressing", "status": "False", "reason": "ProgressDeadlineExceeded", "message": "ReplicaSet \"web-2\" has timed out progressing."}
+    ]
+  }
+}`
+
+const stalledReplicaSetsJSON = `{
+  "items": [
+    {"metadata": {"name": "web-1", "uid": "rs-web-1", "annotations": {"deployment.kubernetes.io/revision": "1"},
+      "ownerReferences": [{"kind": "Deployment", "name": "web", "uid": "dep-web"}]}},
+    {"metadata": {"name": "web-2", "uid": "rs-web-2", "annotations": {"deployment.kubernetes.io/revision": "2"},
+      "ownerReferences": [{"kind": "Deployment", "name": "web", "uid": "dep-web"}]}},
+    {"metadata": {"name": "api-7", "uid": "rs-api-7", "annotations": {"deployment.kubernetes.io/revision": "7"},
+      "ownerReferences": [{"kind": "Deployment", "name": "api", "uid": "dep-api"}]},
+     "status": {"conditions": [{"type": "ReplicaFailure", "status": "True", "reason": "FailedCreate",
+       "message": "pods \"api-7-x\" is forbidden: exceeded quota: compute, requested: cpu=2, used: cpu=8, limited: cpu=8"}]}}
+  ]
+}`
+
+const stalledPodsJSON = `{
+  "items": [
+    {"metadata": {"name": "web-1-old", "ownerReferences": [{"kind": "ReplicaSet", "name": "web-1", "uid": "rs-web-1"}]},
+     "status": {"phase": "Running", "containerStatuses": [{"name": "web", "ready": false, "state": {"waiting": {"reason": "CrashLoopBackOff"}}}]}},
+    {"metadata": {"name": "web-2-a", "ownerReferences": [{"kind": "ReplicaSet", "name": "web-2", "uid": "rs-web-2"}]},
+     "status": {"phase": "Pending", "containerStatuses": [{"name": "web", "ready": false,
+       "state": {"waiting": {"reason": "ImagePullBackOff", "message": "Back-off pulling image \"nginx:nope\""}}}]}},
+    {"metadata": {"name": "web-2-b", "ownerReferences": [{"kind": "ReplicaSet", "name": "web-2", "uid": "rs-web-2"}]},
+     "status": {"phase": "Pending", "conditions": [{"type": "PodScheduled", "status": "False", "reason": "Unschedulable",
+       "message": "0/3 nodes are available: 3 Insufficient cpu."}]}},
+    {"metadata": {"name": "web-2-c", "ownerReferences": [{"kind": "ReplicaSet", "name": "web-2", "uid": "rs-web-2"}]},
+     "status": {"phase": "Running", "containerStatuses": [{"name": "web", "ready": false, "state": {"running": {"startedAt": "2024-05-01T10:00:00Z"}}}]}},
+    {"metadata": {"name": "web-2-d", "ownerReferences": [{"kind": "ReplicaSet", "name": "web-2", "uid": "rs-web-2"}]},
+     "status": {"phase": "Running", "containerStatuses": [{"name": "web", "ready": false, "state": {"running": {"startedAt": "2024-05-01T10:00:00Z"}}}]}}
+  ]
+}`
+
+func TestKubectlToolExecutor_RolloutStall(t *testing.T) {
+	cfg := newTestConfig("readonly")
+	cfg.SecurityConfig.SetAllowedNamespaces("shop")
+
+	runDiagnosticCases(t, "rollout-stall", cfg, map[string]string{
+		"kubectl get deployments web -n shop -o json": stalledDeploymentJSON,
+		"kubectl get deployments api -n shop -o json": `{"metadata": {"name": "api", "uid": "dep-api"},
+			"spec": {"replicas": 2, "selector": {"matchLabels": {"app": "api"}}},
+			"status": {"updatedReplicas": 0, "conditions": [{"type": "Progressing", "status": "True", "reason": "ReplicaSetUpdated"}]}}`,
+		"kubectl get deployments db -n shop -o json": `{"metadata": {"name": "db", "uid": "dep-db"}, "spec": {"replicas": 1},
+			"status": {"updatedReplicas": 1, "readyReplicas": 1, "availableReplicas": 1,
+				"conditions": [{"type": "Progressing", "status": "True", "reason": "NewReplicaSetAvailable"}]}}`,
+		"kubectl get replicasets -n shop -o json":     stalledReplicaSetsJSON,
+		"kubectl get pods -n shop -l app=web -o json": stalledPodsJSON,
+		"kubectl get pods -n shop -l app=api -o json": `{"items": []}`,
+	}, []diagnosticCase{
+		{
+			name: "insufficient resources",
+			args: "deployment/web -n shop",
+			check: func(t *testing.T, output string, _ []string) {
+				report := decodeReport[rolloutStall](t, output)
+				if !report.Stalled || report.Reason != stallInsufficientResources || report.ReplicaSet != "web-2" || report.PodsChecked != 4 {
+					t.Fatalf("expected web-2 to be stalled on insufficient resources, got %+v", report)
+				}
+				if report.Desired != 3 || report.Ready != 1 || len(report.Conditions) != 2 {
+					t.Errorf("expected the deployment's counts and conditions, got %+v", report)
+				}
+				var reasons []string
+				for _, cause := range report.Causes {
+					reasons = append(reasons, cause.Reason)
+				}
+				want := []string{stallInsufficientResources, stallImagePull, stallReadinessProbe, stallDeadlineExceeded}
+				if strings.Join(reasons, ",") != strings.Join(want, ",") {
+					t.Fatalf("causes = %v, want %v", reasons, want)
+				}
+				if pods := report.Causes[1].Pods; len(pods) != 1 || pods[0] != "web-2-a" || !strings.Contains(report.Causes[1].Message, "nginx:nope") {
+					t.Errorf("expected the image pull to name web-2-a and the image, got %+v", report.Causes[1])
+				}
+				if pods := report.Causes[2].Pods; len(pods) != 2 || pods[0] != "web-2-c" || pods[1] != "web-2-d" {
+					t.Errorf("expected both running pods to fail readiness, got %+v", report.Causes[2])
+				}
+				for _, cause := range report.Causes {
+					if cause.Reason == stallCrashLoop {
+						t.Errorf("expected the old ReplicaSet's pod to be ignored, got %+v", cause)
+					}
+				}
+			},
+		},
+		{
+			// Quota failures show up on the ReplicaSet while the deployment still says it is progressing
+			name: "quota on the replicaset",
+			args: "api -n shop",
+			check: func(t *testing.T, output string, _ []string) {
+				report := decodeReport[rolloutStall](t, output)
+				if !report.Stalled || report.Reason != stallQuotaExceeded || len(report.Causes) != 1 || !strings.Contains(report.Causes[0].Message, "exceeded quota") {
+					t.Errorf("expected api to be stalled on quota, got %+v", report)
+				}
+			},
+		},
+		{
+			// A finished rollout has no ReplicaSet of its own to inspect and is not stalled
+			name: "complete",
+			args: "db -n shop",
+			check: func(t *testing.T, output string, commands []string) {
+				report := decodeReport[rolloutStall](t, output)
+				if report.Stalled || len(report.Causes) != 0 || len(commands) != 2 {
+					t.Errorf("expected db not to be stalled after two reads, got %+v after %q", report, commands)
+				}
+			},
+		},
+		{name: "namespace carrying flags refused", args: "web -n 'shop --as=admin'", refused: "invalid namespace"},
+		{name: "name carrying flags refused", args: "'web --as=admin' -n shop", refused: "invalid deployment name"},
+		{name: "statefulset refused", args: "statefulset/db -n shop", refused: "requires a deployment"},
+		{name: "name required", args: "-n shop", refused: "exactly one deployment name"},
+		{name: "namespace outside the allow-list", args: "web -n kube-system", refused: "kube-system"},
+	})
+}
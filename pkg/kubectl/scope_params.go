@@ -0,0 +1,113 @@
+package kubectl
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// namespaceName matches a namespace name, a DNS label
+var namespaceName = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+// objectName matches the name of a namespaced object, a DNS subdomain
+var objectName = regexp.MustCompile(`^[a-z0-9]([-a-z0-9.]*[a-z0-9])?$`)
+
+// namespaceFlag returns the namespace given with -n, or fallback when there is none, and
+// refuses one that isn't a namespace name so it can't carry extra flags into a command
+func namespaceFlag(cmdline *commandLine, fallback string) (string, error) {
+	namespace, ok := cmdline.flag("--namespace")
+	if !ok || namespace == "" {
+		return fallback, nil
+	}
+	if !namespaceName.MatchString(namespace) {
+		return "", fmt.Errorf("invalid namespace '%s'", namespace)
+	}
+	return namespace, nil
+}
+
+// paramFlags maps each structured parameter that stands for a kubectl flag to the long flags
+// it conflicts with in args
+var paramFlags = []struct {
+	param string
+	flags []string
+}{
+	{"namespace", []string{"--namespace", "--all-namespaces"}},
+	{"selector", []string{"--selector"}},
+	{"output", []string{"--output"}},
+	{"container", []string{"--container"}},
+	{"server", []string{"--server"}},
+	{"as", []string{"--as"}},
+	{"as_group", []string{"--as-group"}},
+	{"cascade", []string{"--cascade"}},
+	{"to_revision", []string{"--to-revision"}},
+	{"revision", []string{"--revision"}},
+}
+
+// checkParamConflicts refuses a call that sets a structured parameter and also passes its flag
+// in args, where one would silently override the other
+func checkParamConflicts(args string, params map[string]interface{}) error {
+	cmdline, err := parseCommandLine(args)
+	if err != nil {
+		return err
+	}
+	for _, entry := range paramFlags {
+		if !paramSet(params[entry.param]) {
+			continue
+		}
+		for _, flag := range entry.flags {
+			if cmdline.hasFlag(flag) {
+				return fmt.Errorf("the %s parameter conflicts with %s in args; set one or the other", entry.param, flagForm(flag))
+			}
+		}
+	}
+	return nil
+}
+
+// paramSet reports whether a structured parameter was given a value
+func paramSet(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case string:
+		return strings.TrimSpace(v) != ""
+	default:
+		return true
+	}
+}
+
+// flagForm names a long flag together with its short form, e.g. "-n/--namespace"
+func flagForm(flag string) string {
+	for short, long := range shortFlagNames {
+		if long == flag {
+			return short + "/" + flag
+		}
+	}
+	return flag
+}
+
+// applyScopeParams adds the namespace, selector and output parameters to args as -n, -l and
+// -o, ahead of any "--" separator, so every operation sees them as if they were in args
+func applyScopeParams(args string, params map[string]interface{}) (string, error) {
+	var flags []string
+	if namespace, _ := params["namespace"].(string); strings.TrimSpace(namespace) != "" {
+		namespace = strings.TrimSpace(namespace)
+		if !namespaceName.MatchString(namespace) {
+			return "", fmt.Errorf("invalid namespace '%s'", namespace)
+		}
+		flags = append(flags, "-n", namespace)
+	}
+	if selector, _ := params["selector"].(string); strings.TrimSpace(selector) != "" {
+		flags = append(flags, "-l", shellQuote(strings.TrimSpace(selector)))
+	}
+	// output=ndjson is turned into -o json when the command is assembled
+	if output, _ := params["output"].(string); strings.TrimSpace(output) != "" && !wantsNDJSON(params) {
+		flags = append(flags, "-o", shellQuote(strings.TrimSpace(output)))
+	}
+	if len(flags) == 0 {
+		return args, nil
+	}
+	if strings.TrimSpace(args) == "" {
+		return strings.Join(flags, " "), nil
+	}
+	return insertFlags(args, flags), nil
+}
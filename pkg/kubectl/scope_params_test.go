@@ -0,0 +1,100 @@
+package kubectl
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestKubectlToolExecutor_ScopeParams(t *testing.T) {
+	tests := []struct {
+		name     string
+		toolName string
+		params   map[string]interface{}
+		expected string
+		errMsg   string
+	}{
+		{
+			name:     "namespace param",
+			toolName: "kubectl_resources",
+			params:   map[string]interface{}{"operation": "get", "resource": "pods", "args": "web-0", "namespace": "shop"},
+			expected: "kubectl get pods web-0 -n shop",
+		},
+		{
+			name:     "selector and output params",
+			toolName: "kubectl_resources",
+			params:   map[string]interface{}{"operation": "get", "resource": "pods", "args": "", "selector": "app=web", "output": "json"},
+			expected: "kubectl get pods -l 'app=web' -o 'json' --show-managed-fields=false",
+		},
+		{
+			name:     "namespace param before exec command",
+			toolName: "kubectl_diagnostics",
+			params:   map[string]interface{}{"operation": "exec", "resource": "", "args": "web-0 -- date", "namespace": "shop"},
+			expected: "kubectl exec web-0 -n shop -- date",
+		},
+		{
+			name:     "namespace param with -n in args",
+			toolName: "kubectl_resources",
+			params:   map[string]interface{}{"operation": "get", "resource": "pods", "args": "-n other", "namespace": "shop"},
+			errMsg:   "the namespace parameter conflicts with -n/--namespace in args",
+		},
+		{
+			name:     "namespace param with --namespace= in args",
+			toolName: "kubectl_workloads",
+			params:   map[string]interface{}{"operation": "scale", "resource": "deployment", "args": "web --replicas=2 --namespace=shop", "namespace": "shop"},
+			errMsg:   "the namespace parameter conflicts with -n/--namespace in args",
+		},
+		{
+			name:     "namespace param with -A in args",
+			toolName: "kubectl_resources",
+			params:   map[string]interface{}{"operation": "get", "resource": "pods", "args": "-A", "namespace": "shop"},
+			errMsg:   "the namespace parameter conflicts with -A/--all-namespaces in args",
+		},
+		{
+			name:     "output param with -o in args",
+			toolName: "kubectl_resources",
+			params:   map[string]interface{}{"operation": "get", "resource": "pods", "args": "-o yaml", "output": "json"},
+			errMsg:   "the output parameter conflicts with -o/--output in args",
+		},
+		{
+			name:     "selector param with -l in args",
+			toolName: "kubectl_diagnostics",
+			params:   map[string]interface{}{"operation": "logs", "resource": "", "args": "-l app=web", "selector": "app=api"},
+			errMsg:   "the selector parameter conflicts with -l/--selector in args",
+		},
+		{
+			name:     "server param with --server in args",
+			toolName: "kubectl_resources",
+			params:   map[string]interface{}{"operation": "get", "resource": "pods", "args": "--server=https://a.example", "server": "https://b.example"},
+			errMsg:   "the server parameter conflicts with -s/--server in args",
+		},
+		{
+			name:     "invalid namespace",
+			toolName: "kubectl_resources",
+			params:   map[string]interface{}{"operation": "get", "resource": "pods", "args": "", "namespace": "shop; rm"},
+			errMsg:   "invalid namespace",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			executor := NewKubectlToolExecutor(nil)
+			params := map[string]interface{}{"_tool_name": tt.toolName, "echo": true}
+			for k, v := range tt.params {
+				params[k] = v
+			}
+			output, err := executor.Execute(params, newTestConfig("admin"))
+			if tt.errMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+					t.Fatalf("Execute() error = %v, want error containing %q", err, tt.errMsg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if output != tt.expected {
+				t.Errorf("Execute() = %q, want %q", output, tt.expected)
+			}
+		})
+	}
+}
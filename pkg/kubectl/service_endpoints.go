@@ -0,0 +1,153 @@
+package kubectl
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Azure/mcp-kubernetes/pkg/config"
+)
+
+// serviceEndpointsLabel is the label linking an EndpointSlice to its service
+const serviceEndpointsLabel = "kubernetes.io/service-name"
+
+// serviceEndpointReport is the endpoint readiness of the services in a namespace
+type serviceEndpointReport struct {
+	Namespace string                 `json:"namespace"`
+	Services  []serviceEndpointState `json:"services"`
+	// NoReadyEndpoints names the services that can't route traffic to any endpoint
+	NoReadyEndpoints []string `json:"no_ready_endpoints"`
+}
+
+// serviceEndpointState is a service's ready and total endpoints across its EndpointSlices
+type serviceEndpointState struct {
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Ready int    `json:"ready"`
+	Total int    `json:"total"`
+	// NotReady names the targets, usually pods, of the endpoints that aren't ready
+	NotReady []string `json:"not_ready,omitempty"`
+	// NoReady is set for a service, other than an ExternalName one, without a ready endpoint
+	NoReady bool `json:"no_ready"`
+}
+
+// serviceEndpoints reports the ready and total endpoints of the services of a namespace (-n,
+// default "default"), or of one service named in args, optionally narrowed with -l, and flags
+// the services with no ready endpoint. Endpoints come from the namespace's EndpointSlices;
+// an endpoint listed in several slices, as on a dual-stack service, is counted once. Both lists
+// go through the same access and namespace checks as a direct get.
+func (e *KubectlToolExecutor) serviceEndpoints(args string, cfg *config.ConfigData) (string, error) {
+	cmdline, err := parseCommandLine(args)
+	if err != nil {
+		return "", err
+	}
+	if len(cmdline.positionals) > 1 || cmdline.hasFlag("--all-namespaces") {
+		return "", fmt.Errorf("service-endpoints takes an optional service name, a namespace (-n) and optionally -l, e.g. '-n default'")
+	}
+	namespace, err := namespaceFlag(cmdline, "default")
+	if err != nil {
+		return "", err
+	}
+
+	var services []map[string]interface{}
+	slicesCommand := "get endpointslices -n " + namespace + " -o json"
+	if len(cmdline.positionals) == 1 {
+		name := strings.TrimPrefix(strings.TrimPrefix(cmdline.positionals[0], "service/"), "svc/")
+		if !objectName.MatchString(name) {
+			return "", fmt.Errorf("invalid service name '%s'", name)
+		}
+		service, err := e.getObject(fmt.Sprintf("get services %s -n %s -o json", name, namespace), cfg)
+		if err != nil {
+			return "", err
+		}
+		services = []map[string]interface{}{service}
+		slicesCommand += " -l " + shellQuote(serviceEndpointsLabel+"="+name)
+	} else {
+		command := "get services -n " + namespace + " -o json"
+		if selector, ok := cmdline.flag("--selector"); ok && selector != "" {
+			command += " -l " + shellQuote(selector)
+		}
+		if services, err = e.listObjects(command, cfg); err != nil {
+			return "", err
+		}
+	}
+
+	report := serviceEndpointReport{Namespace: namespace, Services: []serviceEndpointState{}, NoReadyEndpoints: []string{}}
+	if len(services) == 0 {
+		return marshalServiceEndpoints(report)
+	}
+	slices, err := e.listObjects(slicesCommand, cfg)
+	if err != nil {
+		return "", err
+	}
+	endpoints := map[string]map[string]bool{}
+	for _, slice := range slices {
+		service := nestedStringMap(slice, "metadata", "labels")[serviceEndpointsLabel]
+		if service == "" {
+			continue
+		}
+		if endpoints[service] == nil {
+			endpoints[service] = map[string]bool{}
+		}
+		items, _ := slice["endpoints"].([]interface{})
+		for _, raw := range items {
+			endpoint, _ := raw.(map[string]interface{})
+			key := endpointTarget(endpoint)
+			if key == "" {
+				continue
+			}
+			// Endpoints without a ready condition are taken as ready, as kube-proxy does
+			ready, set := nestedValue(endpoint, "conditions", "ready").(bool)
+			endpoints[service][key] = endpoints[service][key] || ready || !set
+		}
+	}
+
+	for _, service := range services {
+		_, name := objectNamespacedName(service)
+		state := serviceEndpointState{Name: name, Type: nestedString(service, "spec", "type")}
+		if state.Type == "" {
+			state.Type = "ClusterIP"
+		}
+		for target, ready := range endpoints[name] {
+			state.Total++
+			if ready {
+				state.Ready++
+			} else {
+				state.NotReady = append(state.NotReady, target)
+			}
+		}
+		sort.Strings(state.NotReady)
+		state.NoReady = state.Ready == 0 && state.Type != "ExternalName"
+		if state.NoReady {
+			report.NoReadyEndpoints = append(report.NoReadyEndpoints, name)
+		}
+		report.Services = append(report.Services, state)
+	}
+	sort.Slice(report.Services, func(i, j int) bool { return report.Services[i].Name < report.Services[j].Name })
+	sort.Strings(report.NoReadyEndpoints)
+	return marshalServiceEndpoints(report)
+}
+
+// endpointTarget names an EndpointSlice endpoint by the object it points to, or by its first
+// address when it has no target
+func endpointTarget(endpoint map[string]interface{}) string {
+	if name := nestedString(endpoint, "targetRef", "name"); name != "" {
+		return name
+	}
+	addresses, _ := endpoint["addresses"].([]interface{})
+	if len(addresses) == 0 {
+		return ""
+	}
+	address, _ := addresses[0].(string)
+	return address
+}
+
+// marshalServiceEndpoints encodes the endpoint readiness of a namespace's services
+func marshalServiceEndpoints(report serviceEndpointReport) (string, error) {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode service endpoints: %w", err)
+	}
+	return string(data), nil
+}
@@ -0,0 +1,99 @@
+package kubectl
+
+import (
+	"testing"
+)
+
+const endpointServicesJSON = `{
+  "items": [
+    {"metadata": {"name": "web", "namespace": "shop"}, "spec": {"type": "ClusterIP"}},
+    {"metadata": {"name": "checkout", "namespace": "shop"}, "spec": {"type": "LoadBalancer"}},
+    {"metadata": {"name": "legacy", "namespace": "shop"}, "spec": {"type": "ExternalName"}},
+    {"metadata": {"name": "idle", "namespace": "shop"}, "spec": {}}
+  ]
+}`
+
+const endpointSlicesJSON = `{
+  "items": [
+    {
+      "metadata": {"name": "web-ipv4", "namespace": "shop", "labels": {"kubernetes.io/service-name": "web"}},
+      "endpoints": [
+        {"addresses": ["10.0.0.1"], "conditions": {"ready": true}, "targetRef": {"kind": "Pod", "name": "web-1"}},
+        {"addresses": ["10.0.0.2"], "conditions": {"ready": false}, "targetRef": {"kind": "Pod", "name": "web-2"}},
+        {"addresses": ["10.0.0.3"], "conditions": {}}
+      ]
+    },
+    {
+      "metadata": {"name": "web-ipv6", "namespace": "shop", "labels": {"kubernetes.io/service-name": "web"}},
+      "endpoints": [
+        {"addresses": ["fd00::1"], "conditions": {"ready": true}, "targetRef": {"kind": "Pod", "name": "web-1"}}
+      ]
+    },
+    {
+      "metadata": {"name": "checkout-abc", "namespace": "shop", "labels": {"kubernetes.io/service-name": "checkout"}},
+      "endpoints": [
+        {"addresses": ["10.0.1.1"], "conditions": {"ready": false}, "targetRef": {"kind": "Pod", "name": "checkout-1"}},
+        {"addresses": ["10.0.1.2"], "conditions": {"ready": false}, "targetRef": {"kind": "Pod", "name": "checkout-2"}}
+      ]
+    }
+  ]
+}`
+
+func TestKubectlToolExecutor_ServiceEndpoints(t *testing.T) {
+	cfg := newTestConfig("readonly")
+	cfg.SecurityConfig.SetAllowedNamespaces("shop")
+
+	runDiagnosticCases(t, "service-endpoints", cfg, map[string]string{
+		"kubectl get services -n shop -o json":                                                endpointServicesJSON,
+		"kubectl get endpointslices -n shop -o json":                                          endpointSlicesJSON,
+		"kubectl get services checkout -n shop -o json":                                       `{"metadata": {"name": "checkout", "namespace": "shop"}, "spec": {"type": "LoadBalancer"}}`,
+		"kubectl get endpointslices -n shop -o json -l 'kubernetes.io/service-name=checkout'": endpointSlicesJSON,
+	}, []diagnosticCase{
+		{
+			name: "every service",
+			args: "-n shop",
+			check: func(t *testing.T, output string, _ []string) {
+				report := decodeReport[serviceEndpointReport](t, output)
+				if len(report.Services) != 4 {
+					t.Fatalf("expected 4 services, got %+v", report.Services)
+				}
+				states := map[string]serviceEndpointState{}
+				for _, state := range report.Services {
+					states[state.Name] = state
+				}
+
+				// web-1 is in both address families and counts once; an endpoint without a ready condition is ready
+				if web := states["web"]; web.Ready != 2 || web.Total != 3 || web.NoReady || len(web.NotReady) != 1 || web.NotReady[0] != "web-2" {
+					t.Errorf("expected web to have 2 of 3 endpoints ready with web-2 not ready, got %+v", web)
+				}
+				if checkout := states["checkout"]; checkout.Ready != 0 || checkout.Total != 2 || !checkout.NoReady {
+					t.Errorf("expected checkout to be flagged with 0 of 2 endpoints ready, got %+v", checkout)
+				}
+				if legacy := states["legacy"]; legacy.NoReady || legacy.Total != 0 {
+					t.Errorf("expected the ExternalName service not to be flagged, got %+v", legacy)
+				}
+				if idle := states["idle"]; idle.Type != "ClusterIP" || !idle.NoReady {
+					t.Errorf("expected the service without endpoints to be flagged, got %+v", idle)
+				}
+				if len(report.NoReadyEndpoints) != 2 || report.NoReadyEndpoints[0] != "checkout" || report.NoReadyEndpoints[1] != "idle" {
+					t.Errorf("expected checkout and idle to have no ready endpoints, got %v", report.NoReadyEndpoints)
+				}
+			},
+		},
+		{
+			// One service reads only its own slices, selected by the service-name label
+			name: "one service",
+			args: "svc/checkout -n shop",
+			check: func(t *testing.T, output string, _ []string) {
+				report := decodeReport[serviceEndpointReport](t, output)
+				if len(report.Services) != 1 || report.Services[0].Name != "checkout" || len(report.NoReadyEndpoints) != 1 {
+					t.Errorf("expected only checkout, flagged, got %+v", report)
+				}
+			},
+		},
+		{name: "namespace carrying flags refused", args: "-n 'shop --as=admin'", refused: "invalid namespace"},
+		{name: "name carrying flags refused", args: "'checkout --as=admin' -n shop", refused: "invalid service name"},
+		{name: "several names refused", args: "web checkout -n shop", refused: "optional service name"},
+		{name: "namespace outside the allow-list", args: "-n kube-system", refused: "kube-system"},
+	})
+}
@@ -0,0 +1,315 @@
+package kubectl
+
+import (
+	"container/list"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/Azure/mcp-kubernetes/pkg/config"
+	"github.com/Azure/mcp-kubernetes/pkg/security"
+	"github.com/Azure/mcp-kubernetes/pkg/tools"
+)
+
+// snapshotVerbs are the commands whose named resources are snapshotted before they run
+var snapshotVerbs = map[string]bool{
+	"delete":   true,
+	"patch":    true,
+	"scale":    true,
+	"label":    true,
+	"annotate": true,
+	"set":      true,
+}
+
+// snapshotGetFlags are the flags of a change that also select the resource for the get that
+// snapshots it
+var snapshotGetFlags = []string{"--namespace", "--context", "--server", "--as", "--as-group"}
+
+// snapshotOwner is the caller whose change a snapshot was taken for: only the same user, on
+// the same MCP session, can restore it
+type snapshotOwner struct {
+	user    string
+	session string
+}
+
+// snapshotOwnerOf returns the caller of a tool call from the identity the handlers injected
+func snapshotOwnerOf(params map[string]interface{}) snapshotOwner {
+	user, _ := params[tools.UserParam].(string)
+	session, _ := params[tools.SessionParam].(string)
+	return snapshotOwner{user: user, session: session}
+}
+
+// resourceSnapshot is the YAML of a resource taken just before a command changed it
+type resourceSnapshot struct {
+	owner snapshotOwner
+	// target is the resource as the command named it, e.g. deployment/web
+	target    string
+	kind      string
+	namespace string
+	name      string
+	// routing is the --context and --server the resource was read from
+	routing string
+	// identity is the --as and --as-group the change ran as, which a restore runs as too
+	identity impersonation
+	command  string
+	deleted  bool
+	taken    time.Time
+	manifest string
+}
+
+// snapshotStore keeps the latest snapshots, oldest first out once size are held
+type snapshotStore struct {
+	mu    sync.Mutex
+	size  int
+	order *list.List
+}
+
+func newSnapshotStore(size int) *snapshotStore {
+	if size <= 0 {
+		return nil
+	}
+	return &snapshotStore{size: size, order: list.New()}
+}
+
+// put stores a snapshot as the latest, dropping the oldest when full
+func (s *snapshotStore) put(snapshot *resourceSnapshot) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.order.PushBack(snapshot)
+	for s.order.Len() > s.size {
+		s.order.Remove(s.order.Front())
+	}
+}
+
+// latest returns the newest snapshot that match accepts
+func (s *snapshotStore) latest(match func(*resourceSnapshot) bool) (*resourceSnapshot, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for element := s.order.Back(); element != nil; element = element.Prev() {
+		if snapshot := element.Value.(*resourceSnapshot); match(snapshot) {
+			return snapshot, true
+		}
+	}
+	return nil, false
+}
+
+// remove drops a snapshot once it has been restored
+func (s *snapshotStore) remove(snapshot *resourceSnapshot) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for element := s.order.Front(); element != nil; element = element.Next() {
+		if element.Value.(*resourceSnapshot) == snapshot {
+			s.order.Remove(element)
+			return
+		}
+	}
+}
+
+// TakeSnapshots keeps the YAML of the last size resources changed by name, read just before
+// the change, for this executor and each tenant's, so restore-last can put a resource back.
+// 0 turns snapshots off.
+func (e *KubectlToolExecutor) TakeSnapshots(size int) {
+	e.snapshots = newSnapshotStore(size)
+	for _, tenant := range e.tenants {
+		tenant.snapshots = newSnapshotStore(size)
+	}
+}
+
+// snapshotTargets returns the resources a command changes by name when they should be
+// snapshotted first: changes by selector, --all or file, and dry runs, have none
+func snapshotTargets(cmdline *commandLine) []string {
+	if !snapshotVerbs[cmdline.verb()] || isDryRun(cmdline) {
+		return nil
+	}
+	for _, name := range []string{"--selector", "--all", "--filename", "--kustomize", "--all-namespaces", "--field-selector"} {
+		if cmdline.hasFlag(name) {
+			return nil
+		}
+	}
+	args := cmdline.args()
+	if cmdline.verb() == "set" {
+		// set takes a subcommand before its resources, and set serviceaccount ends with the
+		// service account's name
+		if len(args) == 0 {
+			return nil
+		}
+		if args[0] == "serviceaccount" || args[0] == "sa" {
+			args = args[:len(args)-1]
+		}
+		args = args[1:]
+	}
+	return resourceTargets(args)
+}
+
+// snapshotBeforeChange reads the YAML of each resource a command is about to change by name,
+// to be kept once the change succeeds. The get is subject to the usual access and namespace
+// checks; if a resource can't be read, the change is refused rather than made without a
+// snapshot.
+func (e *KubectlToolExecutor) snapshotBeforeChange(command string, owner snapshotOwner, cfg *config.ConfigData) ([]*resourceSnapshot, error) {
+	if e.snapshots == nil {
+		return nil, nil
+	}
+	cmdline, err := parseCommandLine(command)
+	if err != nil {
+		return nil, err
+	}
+	targets := snapshotTargets(cmdline)
+	if len(targets) == 0 {
+		return nil, nil
+	}
+
+	var flags, routing []string
+	for _, name := range snapshotGetFlags {
+		if value, ok := cmdline.flag(name); ok && value != "" {
+			if name == "--namespace" {
+				flags = append(flags, name+"="+value)
+			} else {
+				flags = append(flags, name+"="+shellQuote(value))
+			}
+			if name == "--context" || name == "--server" {
+				routing = append(routing, name+"="+shellQuote(value))
+			}
+		}
+	}
+	var snapshots []*resourceSnapshot
+	for _, target := range targets {
+		get := strings.Join(append(append([]string{"get", target}, flags...), "-o", "yaml"), " ")
+		output, err := e.runReadCommand(get, cfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to snapshot %s before %s: %w", target, cmdline.verb(), err)
+		}
+		snapshot, err := newResourceSnapshot(target, output)
+		if err != nil {
+			return nil, fmt.Errorf("failed to snapshot %s before %s: %w", target, cmdline.verb(), err)
+		}
+		snapshot.owner = owner
+		snapshot.routing = strings.Join(routing, " ")
+		snapshot.identity = impersonationFromCommand(cmdline)
+		snapshot.command = command
+		snapshot.deleted = cmdline.verb() == "delete"
+		snapshots = append(snapshots, snapshot)
+	}
+	return snapshots, nil
+}
+
+// keepSnapshots stores the snapshots of a change that succeeded
+func (e *KubectlToolExecutor) keepSnapshots(snapshots []*resourceSnapshot) {
+	for _, snapshot := range snapshots {
+		e.snapshots.put(snapshot)
+	}
+}
+
+// newResourceSnapshot cleans an object's YAML so it can be applied again, without the status
+// and the server-set metadata, and reads the object's identity from it
+func newResourceSnapshot(target, output string) (*resourceSnapshot, error) {
+	manifest := cleanManifest(output, stripPaths(config.DefaultEditStripPaths))
+	var object map[string]interface{}
+	if err := yaml.Unmarshal([]byte(manifest), &object); err != nil {
+		return nil, fmt.Errorf("invalid YAML: %w", err)
+	}
+	kind := nestedString(object, "kind")
+	if kind == "" || kind == "List" {
+		return nil, fmt.Errorf("expected a single object, got %q", kind)
+	}
+	namespace, name := objectNamespacedName(object)
+	return &resourceSnapshot{target: target, kind: kind, namespace: namespace, name: name, taken: time.Now(), manifest: manifest}, nil
+}
+
+// matches reports whether a snapshot is of the resource TYPE/NAME in namespace, taking TYPE as
+// the type the change named or the object's kind, singular or plural
+func (s *resourceSnapshot) matches(target, namespace string) bool {
+	resourceType, name, _ := strings.Cut(target, "/")
+	if name != s.name || (namespace != "" && namespace != s.namespace) {
+		return false
+	}
+	writtenType, _, _ := strings.Cut(s.target, "/")
+	resourceType = strings.ToLower(strings.SplitN(resourceType, ".", 2)[0])
+	kind := strings.ToLower(s.kind)
+	return resourceType == strings.ToLower(writtenType) || resourceType == kind || resourceType == kind+"s"
+}
+
+// restoreLast puts back the caller's latest snapshot, of the resource named in resource and
+// args when one is given, or the latest taken otherwise; snapshots of other users' and
+// sessions' changes are never restored. A deleted resource is created again and any other is
+// replaced with its snapshotted YAML, as the identity the change ran as. The manifest goes
+// through the same checks as an inline manifest and the command through the same access,
+// reason, confirmation and approval checks as any other change, and the snapshot is dropped
+// once it has been restored, so another restore-last goes back to the one before.
+func (e *KubectlToolExecutor) restoreLast(resource, args string, params map[string]interface{}, cfg *config.ConfigData) (string, error) {
+	if e.snapshots == nil {
+		return "", fmt.Errorf("restore-last is not available: snapshots are turned off on this server")
+	}
+	cmdline, err := parseCommandLine(strings.TrimSpace(resource + " " + args))
+	if err != nil {
+		return "", err
+	}
+	targets := resourceTargets(cmdline.positionals)
+	if len(cmdline.positionals) > 0 && len(targets) != 1 {
+		return "", fmt.Errorf("restore-last takes at most one resource, as TYPE NAME or TYPE/NAME")
+	}
+	namespace, _ := cmdline.flag("--namespace")
+	owner := snapshotOwnerOf(params)
+	snapshot, ok := e.snapshots.latest(func(s *resourceSnapshot) bool {
+		return s.owner == owner && (len(targets) == 0 || s.matches(targets[0], namespace))
+	})
+	if !ok {
+		if len(targets) == 0 {
+			return "", fmt.Errorf("no snapshot to restore")
+		}
+		return "", fmt.Errorf("no snapshot of %s to restore", targets[0])
+	}
+
+	objects, err := manifestObjects(snapshot.manifest)
+	if err != nil {
+		return "", err
+	}
+	if err := checkManifestObjects(objects, cfg); err != nil {
+		return "", err
+	}
+	imp, err := impersonationFromParams(params)
+	if err != nil {
+		return "", err
+	}
+	if len(imp.flags()) > 0 && strings.Join(imp.flags(), " ") != strings.Join(snapshot.identity.flags(), " ") {
+		return "", fmt.Errorf("restore-last runs as the identity the change ran as; as and as_group must match it or be left out")
+	}
+	command := "replace"
+	if snapshot.deleted {
+		command = "create"
+	}
+	if snapshot.namespace != "" {
+		command += " -n " + snapshot.namespace
+	}
+	command += " -f -"
+	if snapshot.routing != "" {
+		command += " " + snapshot.routing
+	}
+	command = snapshot.identity.apply(command)
+
+	if err := e.checkAccessLevel(command, cfg); err != nil {
+		return "", denied(err)
+	}
+	if err := e.checkReason(command, params, cfg); err != nil {
+		return "", denied(err)
+	}
+	if err := e.checkAdminConfirmation(command, params, cfg); err != nil {
+		return "", denied(err)
+	}
+	if err := security.NewValidator(cfg.SecurityConfig).ValidateCommand(command, security.CommandTypeKubectl); err != nil {
+		return "", err
+	}
+	if err := e.checkApproval(command, params, cfg); err != nil {
+		return "", denied(err)
+	}
+
+	output, err := e.executor.executeKubectlCommandWithInput(command, snapshot.manifest, cfg)
+	if err != nil {
+		return "", summarizeFailure(err)
+	}
+	e.snapshots.remove(snapshot)
+	return fmt.Sprintf("%s\nRestored %s/%s from the snapshot taken at %s, before: kubectl %s\n",
+		strings.TrimRight(output, "\n"), snapshot.kind, snapshot.name, snapshot.taken.UTC().Format(time.RFC3339), snapshot.command), nil
+}
@@ -0,0 +1,226 @@
+package kubectl
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/Azure/mcp-kubernetes/pkg/config"
+	"github.com/Azure/mcp-kubernetes/pkg/tools"
+)
+
+func TestKubectlToolExecutor_SnapshotRestore(t *testing.T) {
+	// A fake cluster holding one deployment, changed by patch and delete and restored from stdin
+	var mu sync.Mutex
+	original := map[string]interface{}{
+		"replicas": 2,
+		"selector": map[string]interface{}{"matchLabels": map[string]interface{}{"app": "web"}},
+		"template": map[string]interface{}{"spec": map[string]interface{}{
+			"containers": []interface{}{map[string]interface{}{"name": "web", "image": "nginx:1.25"}},
+		}},
+	}
+	var web map[string]interface{}
+	reset := func() {
+		data, _ := yaml.Marshal(original)
+		web = map[string]interface{}{}
+		_ = yaml.Unmarshal(data, &web)
+	}
+	reset()
+	var commands, restored []string
+
+	worker := newTestWorkerWithInput(t, func(command, stdin string) map[string]interface{} {
+		mu.Lock()
+		defer mu.Unlock()
+		commands = append(commands, command)
+		switch {
+		case command == "kubectl get deployment/web --namespace=shop -o yaml":
+			if web == nil {
+				return map[string]interface{}{"error": `Error from server (NotFound): deployments.apps "web" not found`}
+			}
+			data, _ := yaml.Marshal(map[string]interface{}{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"metadata":   map[string]interface{}{"name": "web", "namespace": "shop", "resourceVersion": "41", "uid": "abc"},
+				"spec":       web,
+				"status":     map[string]interface{}{"readyReplicas": web["replicas"]},
+			})
+			return map[string]interface{}{"stdout": string(data)}
+		case strings.HasPrefix(command, "kubectl patch deployment web "):
+			web["replicas"] = 5
+			return map[string]interface{}{"stdout": "deployment.apps/web patched\n"}
+		case strings.HasPrefix(command, "kubectl delete deployment web "):
+			web = nil
+			return map[string]interface{}{"stdout": `deployment.apps "web" deleted` + "\n"}
+		case command == "kubectl replace -n shop -f -" || command == "kubectl create -n shop -f -":
+			restored = append(restored, stdin)
+			var object map[string]interface{}
+			if err := yaml.Unmarshal([]byte(stdin), &object); err != nil {
+				return map[string]interface{}{"error": err.Error()}
+			}
+			web, _ = object["spec"].(map[string]interface{})
+			return map[string]interface{}{"stdout": "deployment.apps/web replaced\n"}
+		}
+		return map[string]interface{}{"error": "unexpected command: " + command}
+	})
+	executor := NewKubectlToolExecutor(worker)
+	cfg := newTestConfig("readwrite")
+	call := func(operation, resource, args string, extra map[string]interface{}) (string, error) {
+		params := map[string]interface{}{
+			"_tool_name": "kubectl_resources",
+			"operation":  operation,
+			"resource":   resource,
+			"args":       args,
+		}
+		for key, value := range extra {
+			params[key] = value
+		}
+		return executor.Execute(params, cfg)
+	}
+	patch := map[string]interface{}{"patch_type": "merge", "patch_body": map[string]interface{}{"spec": map[string]interface{}{"replicas": 5}}}
+
+	// Without --snapshots nothing is read before the change and there is nothing to restore
+	if _, err := call("patch", "deployment", "web -n shop", patch); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(commands) != 1 {
+		t.Errorf("expected only the patch to run, got %v", commands)
+	}
+	if _, err := call("restore-last", "", "", nil); err == nil || !strings.Contains(err.Error(), "turned off") {
+		t.Errorf("expected restore-last to need snapshots, got %v", err)
+	}
+
+	executor.TakeSnapshots(2)
+	reset()
+	if _, err := call("patch", "deployment", "web -n shop", patch); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if web["replicas"] != 5 {
+		t.Fatalf("expected the patch to change replicas, got %v", web)
+	}
+	output, err := call("restore-last", "deployment", "web -n shop", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(output, "Restored Deployment/web") || !strings.Contains(output, "kubectl patch deployment web") {
+		t.Errorf("expected the output to name the restored resource and the change, got %q", output)
+	}
+	if !reflect.DeepEqual(web, original) {
+		t.Errorf("expected the spec to round-trip, got %v want %v", web, original)
+	}
+	if len(restored) != 1 || strings.Contains(restored[0], "resourceVersion") || strings.Contains(restored[0], "status") ||
+		strings.Contains(restored[0], "uid") {
+		t.Errorf("expected the snapshot without status and server-set metadata, got %q", restored)
+	}
+
+	// The restored snapshot is dropped
+	if _, err := call("restore-last", "", "", nil); err == nil || !strings.Contains(err.Error(), "no snapshot") {
+		t.Errorf("expected no snapshot to be left, got %v", err)
+	}
+
+	// A deleted resource is created again
+	if _, err := call("delete", "deployment", "web -n shop", nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	commands = nil
+	if _, err := call("restore-last", "deployments", "web -n shop", nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(commands) != 1 || commands[0] != "kubectl create -n shop -f -" || !reflect.DeepEqual(web, original) {
+		t.Errorf("expected the deleted deployment to be created from its snapshot, got %v and %v", commands, web)
+	}
+
+	// A change whose resource can't be read is refused
+	web = nil
+	commands = nil
+	if _, err := call("patch", "deployment", "web -n shop", patch); err == nil || !strings.Contains(err.Error(), "failed to snapshot deployment/web") {
+		t.Errorf("expected the change to be refused without a snapshot, got %v", err)
+	}
+	if len(commands) != 1 {
+		t.Errorf("expected only the snapshot get to run, got %v", commands)
+	}
+	if _, err := call("restore-last", "deployment", "api -n shop", nil); err == nil {
+		t.Error("expected restoring a resource without a snapshot to fail")
+	}
+}
+
+func TestKubectlToolExecutor_SnapshotOwnerAndPolicy(t *testing.T) {
+	// A fake cluster whose deployment runs an image without resource limits
+	var commands, restores []string
+	worker := newTestWorkerWithInput(t, func(command, stdin string) map[string]interface{} {
+		commands = append(commands, command)
+		switch {
+		case strings.HasPrefix(command, "kubectl get deployment/web --namespace=shop "):
+			return map[string]interface{}{"stdout": `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: shop
+spec:
+  template:
+    spec:
+      containers:
+      - name: web
+        image: nginx:1.25
+`}
+		case strings.HasPrefix(command, "kubectl patch deployment web "):
+			return map[string]interface{}{"stdout": "deployment.apps/web patched\n"}
+		case strings.HasPrefix(command, "kubectl replace "):
+			restores = append(restores, command)
+			return map[string]interface{}{"stdout": "deployment.apps/web replaced\n"}
+		}
+		return map[string]interface{}{"error": "unexpected command: " + command}
+	})
+	executor := NewKubectlToolExecutor(worker)
+	executor.TakeSnapshots(5)
+	cfg := newTestConfig("readwrite")
+	call := func(operation, user, session string, extra map[string]interface{}, cfg *config.ConfigData) (string, error) {
+		params := map[string]interface{}{
+			"_tool_name":       "kubectl_resources",
+			"operation":        operation,
+			"resource":         "deployment",
+			"args":             "web -n shop",
+			tools.UserParam:    user,
+			tools.SessionParam: session,
+		}
+		for key, value := range extra {
+			params[key] = value
+		}
+		return executor.Execute(params, cfg)
+	}
+	patch := map[string]interface{}{"patch_type": "merge", "patch_body": map[string]interface{}{"spec": map[string]interface{}{"replicas": 5}}, "as": "ci-bot"}
+
+	if _, err := call("patch", "alice", "s1", patch, cfg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Another user, or the same user on another session, can't restore alice's change
+	for _, caller := range [][2]string{{"bob", "s1"}, {"alice", "s2"}} {
+		if _, err := call("restore-last", caller[0], caller[1], nil, cfg); err == nil || !strings.Contains(err.Error(), "no snapshot") {
+			t.Errorf("expected %s on %s to find no snapshot, got %v", caller[0], caller[1], err)
+		}
+	}
+
+	// The manifest checks apply to the snapshot: it sets no resource limits
+	limited := newTestConfig("readwrite")
+	limited.RequireResourceLimits = true
+	if _, err := call("restore-last", "alice", "s1", nil, limited); err == nil || !strings.Contains(err.Error(), "limits") {
+		t.Errorf("expected the restore to need resource limits, got %v", err)
+	}
+
+	// The restore runs as the identity the change ran as, and refuses another
+	if _, err := call("restore-last", "alice", "s1", map[string]interface{}{"as": "cluster-admin"}, cfg); err == nil || !strings.Contains(err.Error(), "identity the change ran as") {
+		t.Errorf("expected a different as to be refused, got %v", err)
+	}
+	if len(restores) != 0 {
+		t.Fatalf("expected no refused restore to run, got %v", restores)
+	}
+	if _, err := call("restore-last", "alice", "s1", nil, cfg); err != nil {
+		t.Fatalf("unexpected error: %v (commands: %q)", err, commands)
+	}
+	if len(restores) != 1 || restores[0] != "kubectl replace -n shop -f - --as=ci-bot" {
+		t.Errorf("expected the restore to run as ci-bot, got %v", restores)
+	}
+}
@@ -0,0 +1,163 @@
+package kubectl
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/mcp-kubernetes/pkg/config"
+)
+
+// streamBuffer is the number of chunks buffered ahead of a slow reader
+const streamBuffer = 64
+
+// streamChunk is one piece of output from a streaming remote command
+type streamChunk struct {
+	Stdout string
+	Err    error
+	// Done marks the last chunk; the agent sends it when the command exits or its duration elapses
+	Done bool
+}
+
+// parseStreamChunk reads a chunk of a streaming response. Streaming agents mark every chunk
+// with "done"; a reply without it comes from an agent that ran the command without streaming,
+// so it holds the whole output and ends the stream. Errors end the stream too.
+func parseStreamChunk(result map[string]interface{}) streamChunk {
+	res := parseCommandResult(result)
+	done, streamed := result["done"].(bool)
+	return streamChunk{Stdout: res.Stdout, Err: res.Err, Done: done || !streamed || res.Err != nil}
+}
+
+// commandStream receives the output of a long-running remote command as it arrives
+type commandStream struct {
+	worker *Worker
+	id     int
+	chunks chan streamChunk
+	closed chan struct{}
+	once   sync.Once
+}
+
+// Chunks returns the channel output chunks are delivered on
+func (s *commandStream) Chunks() <-chan streamChunk {
+	return s.chunks
+}
+
+// Close stops delivery. Chunks that arrive afterwards are dropped.
+func (s *commandStream) Close() {
+	s.once.Do(func() {
+		close(s.closed)
+		s.worker.pending.CompareAndDelete(s.id, s)
+	})
+}
+
+// send delivers a chunk, waiting for the reader unless the stream was closed
+func (s *commandStream) send(chunk streamChunk) {
+	select {
+	case s.chunks <- chunk:
+	case <-s.closed:
+	}
+}
+
+// Stream starts a command that the remote agent runs for at most duration, delivering its
+// output in chunks as it is produced. The caller must Close the stream when done reading.
+func (w *Worker) Stream(command string, duration time.Duration) (*commandStream, error) {
+	if w == nil || w.cfg == nil {
+		return nil, fmt.Errorf("remote worker is not configured")
+	}
+
+	s := &commandStream{
+		worker: w,
+		id:     w.nextRequestID(),
+		chunks: make(chan streamChunk, streamBuffer),
+		closed: make(chan struct{}),
+	}
+	w.pending.Store(s.id, s)
+
+	topic := fmt.Sprintf("mcp-%s-%x",
+		strings.ToLower(w.cfg.Token),
+		sha1.Sum([]byte(strings.ToLower(w.cfg.Location))))
+
+	if err := w.sendRequest(w.cfg.AccountUID, s.id, topic, map[string]interface{}{
+		"command":          command,
+		"stream":           true,
+		"duration_seconds": int(duration.Seconds()),
+		"timeout_seconds":  int(duration.Seconds()),
+	}); err != nil {
+		s.Close()
+		return nil, fmt.Errorf("failed to send request: %s", err.Error())
+	}
+	return s, nil
+}
+
+// isLargeOutput reports whether a command usually returns enough output to be worth
+// streaming: logs, and gets across all namespaces. Gets aren't streamed while maxListItems
+// caps them, since the cap can only be applied to the complete list.
+func isLargeOutput(command string, maxListItems int) bool {
+	cmdline, err := parseCommandLine(command)
+	if err != nil {
+		return false
+	}
+	switch cmdline.verb() {
+	case "logs":
+		return true
+	case "get":
+		return maxListItems == 0 && cmdline.boolFlag("--all-namespaces")
+	}
+	return false
+}
+
+// streamCommand runs a validated command through the worker's streaming path, passing each
+// chunk of output to emit as it arrives and returning the whole output once the command ends.
+// If the timeout passes first, the output received so far is returned with a note saying so.
+func (e *KubectlToolExecutor) streamCommand(command string, requested int, cfg *config.ConfigData, emit func(chunk string)) (string, error) {
+	// Reads the informer cache can answer are small enough to return at once
+	if e.store != nil {
+		if output, ok := serveFromStore(e.store, command); ok {
+			return output, nil
+		}
+	}
+
+	worker := e.executor.pulsarWorker
+	if worker == nil || worker.cfg == nil {
+		return "", fmt.Errorf("remote worker is not configured")
+	}
+	verb, _, _ := strings.Cut(strings.TrimSpace(command), " ")
+	timeout := cfg.EffectiveTimeout(verb, requested)
+	if timeout <= 0 {
+		timeout = cfg.ClampTimeout(worker.cfg.Timeout)
+	}
+	duration := time.Duration(timeout) * time.Second
+
+	stream, err := worker.Stream(withRequestTimeout("kubectl "+command, timeout, cfg), duration)
+	if err != nil {
+		return "", err
+	}
+	defer stream.Close()
+
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	var output strings.Builder
+	for {
+		select {
+		case chunk := <-stream.Chunks():
+			if chunk.Err != nil {
+				return "", chunk.Err
+			}
+			if chunk.Stdout != "" {
+				output.WriteString(chunk.Stdout)
+				emit(chunk.Stdout)
+			}
+			if chunk.Done {
+				return output.String(), nil
+			}
+		case <-timer.C:
+			if output.Len() == 0 {
+				return "", fmt.Errorf("timeout waiting for response")
+			}
+			return output.String() + fmt.Sprintf("\n[output incomplete: the command was still running after %ds]\n", timeout), nil
+		}
+	}
+}
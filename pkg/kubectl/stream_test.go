@@ -0,0 +1,133 @@
+package kubectl
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestKubectlToolExecutor_StreamLargeOutput(t *testing.T) {
+	lines := []string{"line 1\nline 2\n", "line 3\nline 4\n", "line 5\n"}
+
+	var commands []string
+	worker := newStreamTestWorker(t, func(command string) []map[string]interface{} {
+		commands = append(commands, command)
+		return []map[string]interface{}{
+			{"stdout": lines[0], "done": false},
+			{"stdout": lines[1], "done": false},
+			{"stdout": lines[2], "done": true},
+		}
+	})
+	executor := NewKubectlToolExecutor(worker)
+
+	var chunks []string
+	output, err := executor.ExecuteStreaming(map[string]interface{}{
+		"_tool_name": "kubectl_diagnostics",
+		"operation":  "logs",
+		"resource":   "",
+		"args":       "web-0 -n shop",
+	}, newTestConfig("readonly"), func(chunk string) {
+		chunks = append(chunks, chunk)
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(commands) != 1 || commands[0] != "kubectl logs web-0 -n shop" {
+		t.Errorf("commands = %v", commands)
+	}
+	if len(chunks) != 3 || strings.Join(chunks, "|") != strings.Join(lines, "|") {
+		t.Errorf("expected 3 chunks in order, got %q", chunks)
+	}
+	if output != strings.Join(lines, "") {
+		t.Errorf("final output = %q, want all chunks", output)
+	}
+}
+
+func TestKubectlToolExecutor_StreamSmallOutput(t *testing.T) {
+	worker := newTestWorker(t, func(command string) map[string]interface{} {
+		return map[string]interface{}{"stdout": "NAME    READY\nweb-0   1/1\n"}
+	})
+	executor := NewKubectlToolExecutor(worker)
+
+	output, err := executor.ExecuteStreaming(map[string]interface{}{
+		"_tool_name": "kubectl_resources",
+		"operation":  "get",
+		"resource":   "pods",
+		"args":       "-n shop",
+	}, newTestConfig("readonly"), func(chunk string) {
+		t.Errorf("small result should not be streamed, got chunk %q", chunk)
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(output, "web-0") {
+		t.Errorf("unexpected output: %q", output)
+	}
+}
+
+func TestIsLargeOutput(t *testing.T) {
+	tests := map[string]bool{
+		"logs web-0 -n shop":        true,
+		"get pods -A":               true,
+		"get pods --all-namespaces": true,
+		"get pods -n shop":          false,
+		"describe pods -A":          false,
+	}
+	for command, expected := range tests {
+		if got := isLargeOutput(command, 0); got != expected {
+			t.Errorf("isLargeOutput(%q) = %v, want %v", command, got, expected)
+		}
+	}
+
+	// Capped lists are returned whole so the cap can be applied
+	if isLargeOutput("get pods -A", 100) {
+		t.Error("get -A should not stream while --max-list-items is set")
+	}
+}
+
+func TestKubectlToolExecutor_StreamWithoutStreamingAgent(t *testing.T) {
+	// An agent without streaming support answers with a single reply and no "done" marker
+	worker := newTestWorker(t, func(command string) map[string]interface{} {
+		return map[string]interface{}{"stdout": "line 1\nline 2\n"}
+	})
+	executor := NewKubectlToolExecutor(worker)
+
+	start := time.Now()
+	output, err := executor.ExecuteStreaming(map[string]interface{}{
+		"_tool_name": "kubectl_diagnostics",
+		"operation":  "logs",
+		"resource":   "",
+		"args":       "web-0 -n shop",
+	}, newTestConfig("readonly"), func(string) {})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output != "line 1\nline 2\n" {
+		t.Errorf("output = %q", output)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("a single reply should end the stream, waited %s", elapsed)
+	}
+}
+
+func TestKubectlToolExecutor_StreamTimeoutKeepsOutput(t *testing.T) {
+	// The agent streams one chunk and then never finishes
+	worker := newStreamTestWorker(t, func(command string) []map[string]interface{} {
+		return []map[string]interface{}{{"stdout": "line 1\n", "done": false}}
+	})
+	executor := NewKubectlToolExecutor(worker)
+
+	output, err := executor.ExecuteStreaming(map[string]interface{}{
+		"_tool_name": "kubectl_diagnostics",
+		"operation":  "logs",
+		"resource":   "",
+		"args":       "web-0 -n shop",
+		"timeout":    float64(1),
+	}, newTestConfig("readonly"), func(string) {})
+	if err != nil {
+		t.Fatalf("expected the partial output, got error %v", err)
+	}
+	if !strings.HasPrefix(output, "line 1\n") || !strings.Contains(output, "output incomplete") {
+		t.Errorf("output = %q", output)
+	}
+}
@@ -0,0 +1,117 @@
+package kubectl
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+var errMalformedTable = errors.New("malformed table output")
+
+// structuredParser converts raw kubectl output into a JSON-serializable value
+type structuredParser func(output string) (interface{}, error)
+
+// structuredParsers maps kubectl commands ("verb" or "verb resource") to the parser used
+// when the caller asks for structured output
+var structuredParsers = map[string]structuredParser{
+	"top pods":        parseTopPods,
+	"top nodes":       parseTopNodes,
+	"diff":            parseDiff,
+	"api-versions":    parseAPIVersions,
+	"rollout history": parseRolloutHistory,
+	"apply":           parseApply,
+	"explain":         parseExplain,
+}
+
+// resourceAliases maps short and singular resource names to the canonical plural form
+var resourceAliases = map[string]string{
+	"po":         "pods",
+	"pod":        "pods",
+	"no":         "nodes",
+	"node":       "nodes",
+	"deploy":     "deployments",
+	"deployment": "deployments",
+	"svc":        "services",
+	"service":    "services",
+}
+
+// canonicalResource returns the canonical plural name for a resource alias
+func canonicalResource(resource string) string {
+	if canonical, ok := resourceAliases[strings.ToLower(resource)]; ok {
+		return canonical
+	}
+	return strings.ToLower(resource)
+}
+
+// lookupStructuredParser finds the parser registered for a kubectl command, if any
+func lookupStructuredParser(command string) structuredParser {
+	fields := strings.Fields(command)
+	if len(fields) == 0 {
+		return nil
+	}
+	if fields[0] == "get" {
+		if isTableGet(command) {
+			return parseGetTable
+		}
+		return nil
+	}
+
+	if len(fields) > 1 {
+		if parser, ok := structuredParsers[fields[0]+" "+canonicalResource(fields[1])]; ok {
+			return parser
+		}
+	}
+
+	return structuredParsers[fields[0]]
+}
+
+// formatStructured parses command output into indented JSON, returning the raw output
+// unchanged when no parser is registered or parsing fails
+func formatStructured(command, output string) string {
+	parser := lookupStructuredParser(command)
+	if parser == nil {
+		return output
+	}
+
+	value, err := parser(output)
+	if err != nil {
+		return output
+	}
+
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return output
+	}
+
+	return string(data)
+}
+
+// parseTable splits kubectl's whitespace-aligned table output into a header and rows.
+// Rows whose field count doesn't match the header are rejected.
+func parseTable(output string) ([]string, [][]string, error) {
+	var header []string
+	var rows [][]string
+
+	for _, line := range strings.Split(output, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if header == nil {
+			header = fields
+			continue
+		}
+
+		if len(fields) != len(header) {
+			return nil, nil, errMalformedTable
+		}
+		rows = append(rows, fields)
+	}
+
+	if header == nil {
+		return nil, nil, errMalformedTable
+	}
+
+	return header, rows, nil
+}
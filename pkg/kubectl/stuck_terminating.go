@@ -0,0 +1,172 @@
+package kubectl
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Azure/mcp-kubernetes/pkg/config"
+)
+
+// stuckTerminatingDefaultAge is how long in seconds a resource must have been terminating
+// before it is reported, when the call doesn't give older_than
+const stuckTerminatingDefaultAge = 300
+
+// stuckTerminatingMaxNamespaces bounds the namespaces listed one at a time under a namespace
+// allow-list
+const stuckTerminatingMaxNamespaces = 50
+
+// stuckTerminatingReport lists the resources of one type that have been terminating too long
+type stuckTerminatingReport struct {
+	Resource string `json:"resource"`
+	Scope    string `json:"scope"`
+	// OlderThan is the threshold in seconds; Terminating counts every object being deleted
+	// and Stuck those terminating for longer than the threshold
+	OlderThan   int64             `json:"older_than_seconds"`
+	Checked     int               `json:"checked"`
+	Terminating int               `json:"terminating"`
+	Stuck       []terminatingItem `json:"stuck"`
+	// Truncated notes allowed namespaces that weren't listed
+	Truncated string `json:"truncated,omitempty"`
+}
+
+// terminatingItem is an object with a deletionTimestamp and what is holding up its deletion
+type terminatingItem struct {
+	Namespace         string   `json:"namespace,omitempty"`
+	Name              string   `json:"name"`
+	DeletionTimestamp string   `json:"deletion_timestamp"`
+	TerminatingFor    string   `json:"terminating_for"`
+	Finalizers        []string `json:"finalizers"`
+	// SpecFinalizers are a namespace's spec.finalizers, which the namespace controller removes
+	// once everything in the namespace is gone
+	SpecFinalizers []string `json:"spec_finalizers,omitempty"`
+	// Conditions are the true status conditions that explain the wait, e.g. a namespace's
+	// NamespaceFinalizersRemaining
+	Conditions []string `json:"conditions,omitempty"`
+}
+
+// stuckTerminating lists a resource type in one namespace (-n, default "default") or, with
+// --all-namespaces, every namespace the server may read, and reports the objects whose
+// deletionTimestamp is more than older_than seconds old, with the finalizers still holding
+// them. It only reads: finalizers are reported, never removed, since removing one skips the
+// cleanup its controller exists to do. Every list is subject to the usual checks.
+func (e *KubectlToolExecutor) stuckTerminating(resource, args string, params map[string]interface{}, cfg *config.ConfigData) (string, error) {
+	resource = strings.ToLower(strings.TrimSpace(resource))
+	if !orphanResourceType.MatchString(resource) || strings.Contains(resource, ",") {
+		return "", fmt.Errorf("stuck-terminating requires a single resource type, got '%s'", resource)
+	}
+	olderThan, ok, err := positiveIntParam("older_than", params["older_than"])
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		olderThan = stuckTerminatingDefaultAge
+	}
+
+	cmdline, err := parseCommandLine(args)
+	if err != nil {
+		return "", err
+	}
+	if len(cmdline.positionals) != 0 {
+		return "", fmt.Errorf("stuck-terminating lists every %s in a namespace and takes no names", resource)
+	}
+	selector := ""
+	if value, ok := cmdline.flag("--selector"); ok && value != "" {
+		selector = " -l " + shellQuote(value)
+	}
+
+	report := &stuckTerminatingReport{Resource: resource, OlderThan: olderThan, Stuck: []terminatingItem{}}
+	var commands []string
+	namespace, err := namespaceFlag(cmdline, "default")
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case !cmdline.boolFlag("--all-namespaces"):
+		report.Scope = "namespace " + namespace
+		commands = append(commands, fmt.Sprintf("get %s -n %s", resource, namespace))
+	case cfg.SecurityConfig.HasNamespaceRestrictions():
+		report.Scope = "allowed namespaces"
+		namespaces, err := e.allowedNamespaceNames(cfg)
+		if err != nil {
+			return "", err
+		}
+		if len(namespaces) > stuckTerminatingMaxNamespaces {
+			report.Truncated = fmt.Sprintf("listed the first %d of %d allowed namespaces; use -n for the others",
+				stuckTerminatingMaxNamespaces, len(namespaces))
+			namespaces = namespaces[:stuckTerminatingMaxNamespaces]
+		}
+		for _, name := range namespaces {
+			commands = append(commands, fmt.Sprintf("get %s -n %s", resource, name))
+		}
+	default:
+		report.Scope = "all namespaces"
+		commands = append(commands, fmt.Sprintf("get %s --all-namespaces", resource))
+	}
+
+	now := time.Now()
+	threshold := time.Duration(olderThan) * time.Second
+	for _, command := range commands {
+		objects, err := e.listObjects(command+selector+" -o json", cfg)
+		if err != nil {
+			return "", err
+		}
+		report.Checked += len(objects)
+		for _, obj := range objects {
+			deletion := nestedString(obj, "metadata", "deletionTimestamp")
+			if deletion == "" {
+				continue
+			}
+			report.Terminating++
+			deleted, err := time.Parse(time.RFC3339, deletion)
+			if err != nil || now.Sub(deleted) < threshold {
+				continue
+			}
+			report.Stuck = append(report.Stuck, describeTerminating(obj, deletion, now.Sub(deleted)))
+		}
+	}
+	// Longest stuck first
+	sort.SliceStable(report.Stuck, func(i, j int) bool {
+		return report.Stuck[i].DeletionTimestamp < report.Stuck[j].DeletionTimestamp
+	})
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode stuck terminating resources: %w", err)
+	}
+	return string(data), nil
+}
+
+// describeTerminating reports a terminating object's finalizers and, for namespaces, the
+// spec finalizers and conditions that say what content is still being removed
+func describeTerminating(obj map[string]interface{}, deletion string, age time.Duration) terminatingItem {
+	namespace, name := objectNamespacedName(obj)
+	item := terminatingItem{
+		Namespace:         namespace,
+		Name:              name,
+		DeletionTimestamp: deletion,
+		TerminatingFor:    age.Truncate(time.Second).String(),
+		Finalizers:        stringList(nestedValue(obj, "metadata", "finalizers")),
+		SpecFinalizers:    stringList(nestedValue(obj, "spec", "finalizers")),
+	}
+	for _, condition := range objectConditions(obj) {
+		if condition.Status == "True" && condition.Message != "" {
+			item.Conditions = append(item.Conditions, condition.Type+": "+condition.Message)
+		}
+	}
+	return item
+}
+
+// stringList reads a JSON array of strings, never returning nil
+func stringList(value interface{}) []string {
+	list := []string{}
+	raw, _ := value.([]interface{})
+	for _, item := range raw {
+		if s, ok := item.(string); ok {
+			list = append(list, s)
+		}
+	}
+	return list
+}
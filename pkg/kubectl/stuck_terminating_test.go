@@ -0,0 +1,114 @@
+package kubectl
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+const stuckNamespaceJSON = `{
+  "items": [
+    {"metadata": {"name": "old-team", "deletionTimestamp": "2024-03-01T08:00:00Z"},
+     "spec": {"finalizers": ["kubernetes"]},
+     "status": {"phase": "Terminating", "conditions": [
+       {"type": "NamespaceDeletionDiscoveryFailure", "status": "False", "reason": "ResourcesDiscovered", "message": "All resources successfully discovered"},
+       {"type": "NamespaceFinalizersRemaining", "status": "True", "reason": "SomeFinalizersRemain", "message": "Some content in the namespace has finalizers remaining: example.com/cleanup in 1 resource instances"}
+     ]}},
+    {"metadata": {"name": "shop"}, "spec": {"finalizers": ["kubernetes"]}, "status": {"phase": "Active"}}
+  ]
+}`
+
+func TestKubectlToolExecutor_StuckTerminating(t *testing.T) {
+	// One claim stuck for months on its protection finalizer, one deleted a moment ago and one live
+	recent := time.Now().Add(-30 * time.Second).UTC().Format(time.RFC3339)
+	claims := fmt.Sprintf(`{
+  "items": [
+    {"metadata": {"name": "data-db-0", "namespace": "shop", "deletionTimestamp": "2024-05-01T10:00:00Z",
+      "finalizers": ["kubernetes.io/pvc-protection"]}, "status": {"phase": "Bound"}},
+    {"metadata": {"name": "scratch", "namespace": "shop", "deletionTimestamp": %q,
+      "finalizers": ["kubernetes.io/pvc-protection"]}, "status": {"phase": "Bound"}},
+    {"metadata": {"name": "data-db-1", "namespace": "shop"}, "status": {"phase": "Bound"}}
+  ]
+}`, recent)
+	restricted := newTestConfig("readonly")
+	restricted.SecurityConfig.SetAllowedNamespaces("shop")
+
+	// Only reads are run; the finalizers are never removed
+	onlyGets := func(t *testing.T, commands []string) {
+		for _, command := range commands {
+			if !strings.HasPrefix(command, "kubectl get ") {
+				t.Errorf("expected only gets, got %q", command)
+			}
+		}
+	}
+
+	runDiagnosticCases(t, "stuck-terminating", newTestConfig("readonly"), map[string]string{
+		"kubectl get persistentvolumeclaims -n shop -o json": claims,
+		"kubectl get namespaces -n default -o json":          stuckNamespaceJSON,
+		"kubectl get namespaces -o json":                     stuckNamespaceJSON,
+	}, []diagnosticCase{
+		{
+			name:     "past the default threshold",
+			resource: "persistentvolumeclaims",
+			args:     "-n shop",
+			check: func(t *testing.T, output string, commands []string) {
+				report := decodeReport[stuckTerminatingReport](t, output)
+				if report.Checked != 3 || report.Terminating != 2 || report.OlderThan != 300 || len(report.Stuck) != 1 {
+					t.Fatalf("expected one claim past the default threshold, got %+v", report)
+				}
+				claim := report.Stuck[0]
+				if claim.Name != "data-db-0" || claim.Namespace != "shop" || claim.DeletionTimestamp != "2024-05-01T10:00:00Z" ||
+					len(claim.Finalizers) != 1 || claim.Finalizers[0] != "kubernetes.io/pvc-protection" || claim.TerminatingFor == "" {
+					t.Errorf("unexpected stuck claim: %+v", claim)
+				}
+				onlyGets(t, commands)
+			},
+		},
+		{
+			// A lower threshold also reports the claim deleted a moment ago, after the older one
+			name:     "lower threshold",
+			resource: "persistentvolumeclaims",
+			args:     "-n shop",
+			params:   map[string]interface{}{"older_than": float64(10)},
+			check: func(t *testing.T, output string, _ []string) {
+				report := decodeReport[stuckTerminatingReport](t, output)
+				if len(report.Stuck) != 2 || report.Stuck[0].Name != "data-db-0" || report.Stuck[1].Name != "scratch" {
+					t.Errorf("expected both claims, oldest first, got %+v", report.Stuck)
+				}
+			},
+		},
+		{
+			// A namespace reports its spec finalizers and the condition naming what is left
+			name:     "namespace",
+			resource: "namespaces",
+			check: func(t *testing.T, output string, commands []string) {
+				report := decodeReport[stuckTerminatingReport](t, output)
+				if len(report.Stuck) != 1 || report.Stuck[0].Name != "old-team" || len(report.Stuck[0].Finalizers) != 0 ||
+					len(report.Stuck[0].SpecFinalizers) != 1 || len(report.Stuck[0].Conditions) != 1 ||
+					!strings.Contains(report.Stuck[0].Conditions[0], "example.com/cleanup") {
+					t.Errorf("unexpected stuck namespace: %+v", report.Stuck)
+				}
+				onlyGets(t, commands)
+			},
+		},
+		{
+			// Under an allow-list, -A lists the allowed namespaces one at a time
+			name:     "all namespaces under an allow-list",
+			resource: "persistentvolumeclaims",
+			args:     "-A",
+			cfg:      restricted,
+			check: func(t *testing.T, output string, _ []string) {
+				report := decodeReport[stuckTerminatingReport](t, output)
+				if report.Scope != "allowed namespaces" || report.Checked != 3 || len(report.Stuck) != 1 {
+					t.Errorf("expected the claims of shop, got %+v", report)
+				}
+			},
+		},
+		{name: "namespace carrying flags refused", resource: "pods", args: "-n 'shop --as=admin'", refused: "invalid namespace"},
+		{name: "names refused", resource: "pods", args: "web-0 -n shop", refused: "takes no names"},
+		{name: "several resource types refused", resource: "pods,services", args: "-n shop", refused: "single resource type"},
+		{name: "negative older_than refused", resource: "pods", args: "-n shop", params: map[string]interface{}{"older_than": float64(-1)}, refused: "older_than"},
+		{name: "namespace outside the allow-list", resource: "persistentvolumeclaims", args: "-n kube-system", cfg: restricted, refused: "kube-system"},
+	})
+}
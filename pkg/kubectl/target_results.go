@@ -0,0 +1,145 @@
+package kubectl
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// targetResultVerbs are the mutating verbs that act on each named resource in turn, so one can
+// fail while the others succeed
+var targetResultVerbs = map[string]bool{
+	"delete":   true,
+	"label":    true,
+	"annotate": true,
+	"scale":    true,
+	"cordon":   true,
+	"uncordon": true,
+}
+
+// targetResult is what happened to one of the resources a command named
+type targetResult struct {
+	Resource string `json:"resource"`
+	// Status is kubectl's word for the change, e.g. "deleted" or "labeled", or "failed"
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// targetsFailure is a failed command on several resources, broken down per resource
+type targetsFailure struct {
+	Summary string         `json:"summary"`
+	Results []targetResult `json:"results"`
+}
+
+func (f *targetsFailure) Error() string {
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return f.Summary
+	}
+	return string(data)
+}
+
+// commandTargets returns the resources a command names as TYPE NAME... or TYPE/NAME..., as
+// the caller wrote them. Label and annotate changes (key=value, key-) are not targets.
+func commandTargets(cmdline *commandLine) []string {
+	return resourceTargets(cmdline.args())
+}
+
+// resourceTargets returns the TYPE/NAME of each resource named in args
+func resourceTargets(args []string) []string {
+	var targets []string
+	for i, arg := range args {
+		if strings.Contains(arg, "=") || strings.HasSuffix(arg, "-") {
+			continue
+		}
+		switch {
+		case strings.Contains(arg, "/"):
+			targets = append(targets, arg)
+		case i > 0 && !strings.Contains(args[0], "/"):
+			targets = append(targets, args[0]+"/"+arg)
+		}
+	}
+	return targets
+}
+
+// summarizeTargets breaks the output of a failed command on several named resources down
+// into one result per resource. Each output line must name exactly one of the resources and
+// each resource must be named by exactly one line; otherwise ok is false and the caller falls
+// back to the plain failure summary.
+func summarizeTargets(command string, err error) (*targetsFailure, bool) {
+	var exitErr *exitError
+	if !errors.As(err, &exitErr) {
+		return nil, false
+	}
+	cmdline, parseErr := parseCommandLine(command)
+	if parseErr != nil || !targetResultVerbs[cmdline.verb()] {
+		return nil, false
+	}
+	targets := commandTargets(cmdline)
+	if len(targets) < 2 {
+		return nil, false
+	}
+
+	results := make([]*targetResult, len(targets))
+	for _, line := range strings.Split(exitErr.Stdout+"\n"+exitErr.Stderr, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || isFailureNoise(line) {
+			continue
+		}
+		match := -1
+		for i, target := range targets {
+			if !lineNamesTarget(line, target) {
+				continue
+			}
+			if match >= 0 {
+				return nil, false
+			}
+			match = i
+		}
+		if match < 0 || results[match] != nil {
+			return nil, false
+		}
+
+		result := &targetResult{Resource: targets[match]}
+		if isErrorLine(line) {
+			result.Status, result.Error = "failed", line
+		} else {
+			line = strings.TrimSuffix(strings.TrimSuffix(line, " (dry run)"), " (server dry run)")
+			result.Status = line[strings.LastIndex(line, " ")+1:]
+		}
+		results[match] = result
+	}
+
+	failure := &targetsFailure{Results: []targetResult{}}
+	failed := 0
+	for _, result := range results {
+		if result == nil {
+			return nil, false
+		}
+		if result.Status == "failed" {
+			failed++
+		}
+		failure.Results = append(failure.Results, *result)
+	}
+	if failed == 0 {
+		return nil, false
+	}
+	failure.Summary = fmt.Sprintf("%s failed for %d of %d resources", cmdline.verb(), failed, len(targets))
+	return failure, true
+}
+
+// lineNamesTarget reports whether an output line is about a target, which kubectl writes as
+// TYPE "NAME" (delete and most errors) or TYPE/NAME (label, annotate, scale)
+func lineNamesTarget(line, target string) bool {
+	_, name, _ := strings.Cut(target, "/")
+	if strings.Contains(line, `"`+name+`"`) {
+		return true
+	}
+	for _, field := range strings.Fields(line) {
+		if strings.HasSuffix(field, "/"+name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,109 @@
+package kubectl
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestKubectlToolExecutor_PartialDelete(t *testing.T) {
+	worker := newTestWorker(t, func(command string) map[string]interface{} {
+		if command != "kubectl delete pod web-0 web-1 web-2 -n shop" {
+			return map[string]interface{}{"error": "unexpected command: " + command}
+		}
+		return map[string]interface{}{
+			"stdout":    "pod \"web-0\" deleted\npod \"web-2\" deleted\n",
+			"stderr":    "Error from server (NotFound): pods \"web-1\" not found\n",
+			"exit_code": float64(1),
+		}
+	})
+	executor := NewKubectlToolExecutor(worker)
+
+	_, err := executor.Execute(map[string]interface{}{
+		"_tool_name": "kubectl_resources",
+		"operation":  "delete",
+		"resource":   "pod",
+		"args":       "web-0 web-1 web-2 -n shop",
+	}, newTestConfig("readwrite"))
+	if err == nil {
+		t.Fatal("expected the delete to fail")
+	}
+
+	var failure targetsFailure
+	if jsonErr := json.Unmarshal([]byte(err.Error()), &failure); jsonErr != nil {
+		t.Fatalf("error is not per-resource JSON: %v\n%s", jsonErr, err)
+	}
+	if failure.Summary != "delete failed for 1 of 3 resources" {
+		t.Errorf("summary = %q", failure.Summary)
+	}
+	want := []targetResult{
+		{Resource: "pod/web-0", Status: "deleted"},
+		{Resource: "pod/web-1", Status: "failed", Error: `Error from server (NotFound): pods "web-1" not found`},
+		{Resource: "pod/web-2", Status: "deleted"},
+	}
+	if len(failure.Results) != len(want) {
+		t.Fatalf("results = %+v, want %+v", failure.Results, want)
+	}
+	for i := range want {
+		if failure.Results[i] != want[i] {
+			t.Errorf("result %d = %+v, want %+v", i, failure.Results[i], want[i])
+		}
+	}
+}
+
+func TestSummarizeTargets(t *testing.T) {
+	tests := []struct {
+		name    string
+		command string
+		stdout  string
+		stderr  string
+		ok      bool
+	}{
+		{
+			name:    "label by type/name",
+			command: "label pod/web-0 pod/web-1 tier=web -n shop",
+			stdout:  "pod/web-0 labeled\n",
+			stderr:  "Error from server (NotFound): pods \"web-1\" not found\n",
+			ok:      true,
+		},
+		{
+			name:    "single target",
+			command: "delete pod web-0 -n shop",
+			stderr:  "Error from server (NotFound): pods \"web-0\" not found\n",
+		},
+		{
+			name:    "line for no target",
+			command: "delete pod web-0 web-1 -n shop",
+			stdout:  "pod \"web-0\" deleted\n",
+			stderr:  "error: the server doesn't have a resource type \"pod\"\n",
+		},
+		{
+			name:    "target without a line",
+			command: "delete pod web-0 web-1 web-2 -n shop",
+			stdout:  "pod \"web-0\" deleted\n",
+			stderr:  "Error from server (NotFound): pods \"web-1\" not found\n",
+		},
+		{
+			name:    "not a per-resource verb",
+			command: "apply -f a.yaml -f b.yaml",
+			stderr:  "error: error validating \"b.yaml\"\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := &exitError{Code: 1, Stdout: tt.stdout, Stderr: tt.stderr}
+			failure, ok := summarizeTargets(tt.command, err)
+			if ok != tt.ok {
+				t.Fatalf("summarizeTargets() ok = %v, want %v (%+v)", ok, tt.ok, failure)
+			}
+			if ok && (failure.Results[0].Status != "labeled" || failure.Results[1].Status != "failed") {
+				t.Errorf("unexpected results: %+v", failure.Results)
+			}
+		})
+	}
+
+	if _, ok := summarizeTargets("delete pod a b", errors.New("timed out")); ok {
+		t.Error("summarizeTargets() should not parse an error without output")
+	}
+}
@@ -0,0 +1,38 @@
+package kubectl
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Azure/mcp-kubernetes/pkg/config"
+	"github.com/Azure/mcp-kubernetes/pkg/security"
+)
+
+// AddTenant routes tool calls whose tenant parameter names tenant to the remote agent reached
+// through worker, which is configured with that tenant's token and so uses its topic. Tenants
+// are added at startup, before calls are served.
+func (e *KubectlToolExecutor) AddTenant(tenant string, worker *Worker) {
+	if e.tenants == nil {
+		e.tenants = make(map[string]*KubectlToolExecutor)
+	}
+	e.tenants[tenant] = NewKubectlToolExecutor(worker)
+}
+
+// forTenant returns the executor for a call's tenant parameter: e itself when there is none,
+// otherwise the tenant's executor once the tenant has passed the --allow-tenants allow-list
+func (e *KubectlToolExecutor) forTenant(params map[string]interface{}, cfg *config.ConfigData) (*KubectlToolExecutor, error) {
+	tenant, _ := params["tenant"].(string)
+	if tenant = strings.TrimSpace(tenant); tenant == "" {
+		return e, nil
+	}
+	if !cfg.SecurityConfig.IsTenantAllowed(tenant) {
+		return nil, &security.ValidationError{
+			Message: "Error: Tenant '" + tenant + "' is not allowed by security configuration",
+		}
+	}
+	target, ok := e.tenants[tenant]
+	if !ok {
+		return nil, fmt.Errorf("no remote agent is configured for tenant '%s'", tenant)
+	}
+	return target, nil
+}
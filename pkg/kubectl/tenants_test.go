@@ -0,0 +1,93 @@
+package kubectl
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// newTenantTestWorker returns a worker with token that records the topic of each request
+// and answers with the tenant's name
+func newTenantTestWorker(t *testing.T, token string, mu *sync.Mutex, topics *[]string) *Worker {
+	t.Helper()
+
+	var w *Worker
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		var msg struct {
+			Payload struct {
+				Id    int    `json:"Id"`
+				Topic string `json:"topic"`
+			}
+		}
+		if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		*topics = append(*topics, msg.Payload.Topic)
+		mu.Unlock()
+		w.deliver(msg.Payload.Id, map[string]interface{}{"stdout": "answered with " + token})
+	}))
+	t.Cleanup(srv.Close)
+
+	w, err := New(&Config{Mode: ModeAgent, Location: "test-host", Token: token, UnsubscribeEndpoint: srv.URL, Timeout: 5})
+	if err != nil {
+		t.Fatalf("failed to create worker: %v", err)
+	}
+	return w
+}
+
+func TestKubectlToolExecutor_Tenants(t *testing.T) {
+	var mu sync.Mutex
+	var topics []string
+	executor := NewKubectlToolExecutor(newTenantTestWorker(t, "default-token", &mu, &topics))
+	executor.AddTenant("acme", newTenantTestWorker(t, "acme-token", &mu, &topics))
+	executor.AddTenant("globex", newTenantTestWorker(t, "globex-token", &mu, &topics))
+
+	cfg := newTestConfig("readonly")
+	cfg.SecurityConfig.SetAllowedTenants("acme,globex")
+
+	call := func(tenant string) (string, error) {
+		params := map[string]interface{}{
+			"_tool_name": "kubectl_resources",
+			"operation":  "get",
+			"resource":   "pods",
+			"args":       "-n shop",
+		}
+		if tenant != "" {
+			params["tenant"] = tenant
+		}
+		return executor.Execute(params, cfg)
+	}
+
+	for _, tenant := range []string{"acme", "globex"} {
+		output, err := call(tenant)
+		if err != nil {
+			t.Fatalf("tenant %s: unexpected error: %v", tenant, err)
+		}
+		if output != "answered with "+tenant+"-token" {
+			t.Errorf("tenant %s: output = %q", tenant, output)
+		}
+	}
+	if len(topics) != 2 || topics[0] == topics[1] ||
+		!strings.HasPrefix(topics[0], "mcp-acme-token-") || !strings.HasPrefix(topics[1], "mcp-globex-token-") {
+		t.Errorf("expected each tenant on its own topic, got %v", topics)
+	}
+
+	// Calls without a tenant use the default agent
+	if output, err := call(""); err != nil || output != "answered with default-token" {
+		t.Errorf("default call = %q, %v", output, err)
+	}
+
+	// Tenants must be on the allow-list and have an agent
+	if _, err := call("initech"); err == nil || !strings.Contains(err.Error(), "not allowed") {
+		t.Errorf("expected an unlisted tenant to be rejected, got %v", err)
+	}
+	cfg.SecurityConfig.SetAllowedTenants("acme,globex,initech")
+	if _, err := call("initech"); err == nil || !strings.Contains(err.Error(), "no remote agent") {
+		t.Errorf("expected a tenant without an agent to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,226 @@
+package kubectl
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// topUsage is a single row of `kubectl top` output with normalized units
+type topUsage struct {
+	Namespace     string     `json:"namespace,omitempty"`
+	Name          string     `json:"name"`
+	CPU           int64      `json:"cpu"`    // millicores
+	Memory        float64    `json:"memory"` // mebibytes
+	CPUPercent    *int       `json:"cpu_percent,omitempty"`
+	MemoryPercent *int       `json:"memory_percent,omitempty"`
+	Containers    []topUsage `json:"containers,omitempty"`
+}
+
+// topColumns records the column index of each known `kubectl top` header
+type topColumns struct {
+	namespace, pod, name, cpu, memory, cpuPercent, memoryPercent int
+}
+
+// indexTopColumns locates known columns in a `kubectl top` header
+func indexTopColumns(header []string) (topColumns, error) {
+	cols := topColumns{namespace: -1, pod: -1, name: -1, cpu: -1, memory: -1, cpuPercent: -1, memoryPercent: -1}
+	for i, column := range header {
+		switch strings.ToUpper(column) {
+		case "NAMESPACE":
+			cols.namespace = i
+		case "POD":
+			cols.pod = i
+		case "NAME":
+			cols.name = i
+		case "CPU(CORES)":
+			cols.cpu = i
+		case "MEMORY(BYTES)":
+			cols.memory = i
+		case "CPU%", "CPU(%)":
+			cols.cpuPercent = i
+		case "MEMORY%", "MEMORY(%)":
+			cols.memoryPercent = i
+		}
+	}
+
+	if cols.name < 0 || cols.cpu < 0 || cols.memory < 0 {
+		return cols, errMalformedTable
+	}
+	return cols, nil
+}
+
+// parseTopRow converts a table row into a topUsage using the located columns
+func parseTopRow(row []string, cols topColumns) (topUsage, error) {
+	cpu, err := parseMillicores(row[cols.cpu])
+	if err != nil {
+		return topUsage{}, err
+	}
+	memory, err := parseMebibytes(row[cols.memory])
+	if err != nil {
+		return topUsage{}, err
+	}
+
+	usage := topUsage{Name: row[cols.name], CPU: cpu, Memory: memory}
+	if cols.namespace >= 0 {
+		usage.Namespace = row[cols.namespace]
+	}
+	if cols.cpuPercent >= 0 {
+		percent, err := parsePercent(row[cols.cpuPercent])
+		if err != nil {
+			return topUsage{}, err
+		}
+		usage.CPUPercent = &percent
+	}
+	if cols.memoryPercent >= 0 {
+		percent, err := parsePercent(row[cols.memoryPercent])
+		if err != nil {
+			return topUsage{}, err
+		}
+		usage.MemoryPercent = &percent
+	}
+
+	return usage, nil
+}
+
+// parseTopNodes parses `kubectl top node` output
+func parseTopNodes(output string) (interface{}, error) {
+	header, rows, err := parseTable(output)
+	if err != nil {
+		return nil, err
+	}
+	cols, err := indexTopColumns(header)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make([]topUsage, 0, len(rows))
+	for _, row := range rows {
+		usage, err := parseTopRow(row, cols)
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, usage)
+	}
+	return nodes, nil
+}
+
+// parseTopPods parses `kubectl top pod` output. With --containers, container rows are
+// nested under their pod and the pod totals are the sum of its containers.
+func parseTopPods(output string) (interface{}, error) {
+	header, rows, err := parseTable(output)
+	if err != nil {
+		return nil, err
+	}
+	cols, err := indexTopColumns(header)
+	if err != nil {
+		return nil, err
+	}
+
+	pods := make([]topUsage, 0, len(rows))
+	if cols.pod < 0 {
+		for _, row := range rows {
+			usage, err := parseTopRow(row, cols)
+			if err != nil {
+				return nil, err
+			}
+			pods = append(pods, usage)
+		}
+		return pods, nil
+	}
+
+	// --containers output: one row per container, grouped by namespace/pod
+	index := make(map[string]int)
+	for _, row := range rows {
+		container, err := parseTopRow(row, cols)
+		if err != nil {
+			return nil, err
+		}
+
+		podName := row[cols.pod]
+		key := container.Namespace + "/" + podName
+		i, ok := index[key]
+		if !ok {
+			i = len(pods)
+			index[key] = i
+			pods = append(pods, topUsage{Namespace: container.Namespace, Name: podName})
+		}
+
+		container.Namespace = ""
+		pods[i].CPU += container.CPU
+		pods[i].Memory += container.Memory
+		pods[i].Containers = append(pods[i].Containers, container)
+	}
+	return pods, nil
+}
+
+// parseMillicores converts a CPU quantity (e.g. "250m", "2", "1500000n") to millicores
+func parseMillicores(value string) (int64, error) {
+	multipliers := []struct {
+		suffix string
+		factor float64
+	}{
+		{"n", 1e-6},
+		{"u", 1e-3},
+		{"m", 1},
+	}
+
+	for _, m := range multipliers {
+		if strings.HasSuffix(value, m.suffix) {
+			n, err := strconv.ParseFloat(strings.TrimSuffix(value, m.suffix), 64)
+			if err != nil {
+				return 0, fmt.Errorf("invalid cpu quantity %q", value)
+			}
+			return int64(n*m.factor + 0.5), nil
+		}
+	}
+
+	cores, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid cpu quantity %q", value)
+	}
+	return int64(cores*1000 + 0.5), nil
+}
+
+// parseMebibytes converts a memory quantity (e.g. "128Mi", "1Gi", "500M", "1024") to MiB
+func parseMebibytes(value string) (float64, error) {
+	multipliers := []struct {
+		suffix string
+		bytes  float64
+	}{
+		{"Ki", 1 << 10},
+		{"Mi", 1 << 20},
+		{"Gi", 1 << 30},
+		{"Ti", 1 << 40},
+		{"k", 1e3},
+		{"K", 1e3},
+		{"M", 1e6},
+		{"G", 1e9},
+		{"T", 1e12},
+	}
+
+	amount := value
+	factor := 1.0
+	for _, m := range multipliers {
+		if strings.HasSuffix(value, m.suffix) {
+			amount = strings.TrimSuffix(value, m.suffix)
+			factor = m.bytes
+			break
+		}
+	}
+
+	n, err := strconv.ParseFloat(amount, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid memory quantity %q", value)
+	}
+	return n * factor / (1 << 20), nil
+}
+
+// parsePercent converts a percentage column such as "12%" to an integer
+func parsePercent(value string) (int, error) {
+	n, err := strconv.Atoi(strings.TrimSuffix(value, "%"))
+	if err != nil {
+		return 0, fmt.Errorf("invalid percentage %q", value)
+	}
+	return n, nil
+}
@@ -0,0 +1,127 @@
+package kubectl
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseTopPods(t *testing.T) {
+	output := `NAME                     CPU(cores)   MEMORY(bytes)
+nginx-7c5ddbdf54-2xj8k   250m         128Mi
+redis-0                  1            1Gi
+`
+	value, err := parseTopPods(output)
+	if err != nil {
+		t.Fatalf("parseTopPods() unexpected error = %v", err)
+	}
+
+	pods := value.([]topUsage)
+	if len(pods) != 2 {
+		t.Fatalf("expected 2 pods, got %d", len(pods))
+	}
+	if pods[0].Name != "nginx-7c5ddbdf54-2xj8k" || pods[0].CPU != 250 || pods[0].Memory != 128 {
+		t.Errorf("unexpected first pod: %+v", pods[0])
+	}
+	if pods[1].CPU != 1000 || pods[1].Memory != 1024 {
+		t.Errorf("expected 1 core = 1000m and 1Gi = 1024Mi, got %+v", pods[1])
+	}
+}
+
+func TestParseTopPodsWithContainers(t *testing.T) {
+	output := `NAMESPACE   POD       NAME      CPU(cores)   MEMORY(bytes)
+default     web-1     app       100m         64Mi
+default     web-1     sidecar   5m           2048Ki
+kube-system dns-1     coredns   3m           12Mi
+`
+	value, err := parseTopPods(output)
+	if err != nil {
+		t.Fatalf("parseTopPods() unexpected error = %v", err)
+	}
+
+	pods := value.([]topUsage)
+	if len(pods) != 2 {
+		t.Fatalf("expected 2 pods, got %d", len(pods))
+	}
+
+	web := pods[0]
+	if web.Namespace != "default" || web.Name != "web-1" || len(web.Containers) != 2 {
+		t.Fatalf("unexpected pod grouping: %+v", web)
+	}
+	if web.CPU != 105 || web.Memory != 66 {
+		t.Errorf("expected pod totals 105m/66Mi, got %dm/%vMi", web.CPU, web.Memory)
+	}
+	if web.Containers[1].Name != "sidecar" || web.Containers[1].Memory != 2 {
+		t.Errorf("expected sidecar with 2Mi, got %+v", web.Containers[1])
+	}
+}
+
+func TestParseTopNodes(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+	}{
+		{
+			name: "legacy header",
+			output: `NAME       CPU(cores)   CPU%   MEMORY(bytes)   MEMORY%
+worker-1   1500m        37%    3000M           40%
+`,
+		},
+		{
+			name: "parenthesized percent header",
+			output: `NAME       CPU(cores)   CPU(%)   MEMORY(bytes)   MEMORY(%)
+worker-1   1500m        37%      3000M           40%
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := parseTopNodes(tt.output)
+			if err != nil {
+				t.Fatalf("parseTopNodes() unexpected error = %v", err)
+			}
+
+			nodes := value.([]topUsage)
+			if len(nodes) != 1 {
+				t.Fatalf("expected 1 node, got %d", len(nodes))
+			}
+			node := nodes[0]
+			if node.CPU != 1500 {
+				t.Errorf("expected 1500 millicores, got %d", node.CPU)
+			}
+			// 3000M = 3e9 bytes = 2861.02 MiB
+			if node.Memory < 2861 || node.Memory > 2862 {
+				t.Errorf("expected ~2861 MiB, got %v", node.Memory)
+			}
+			if node.CPUPercent == nil || *node.CPUPercent != 37 || node.MemoryPercent == nil || *node.MemoryPercent != 40 {
+				t.Errorf("unexpected percentages: %+v", node)
+			}
+		})
+	}
+}
+
+func TestFormatStructuredTop(t *testing.T) {
+	output := `NAME       CPU(cores)   CPU%   MEMORY(bytes)   MEMORY%
+worker-1   250m         12%    512Mi           13%
+`
+	formatted := formatStructured("top node", output)
+
+	var nodes []map[string]interface{}
+	if err := json.Unmarshal([]byte(formatted), &nodes); err != nil {
+		t.Fatalf("expected JSON output, got %q: %v", formatted, err)
+	}
+	if nodes[0]["name"] != "worker-1" || nodes[0]["cpu"] != float64(250) || nodes[0]["memory"] != float64(512) {
+		t.Errorf("unexpected structured node: %v", nodes[0])
+	}
+
+	// Unparseable output falls back to raw text
+	raw := "error: Metrics API not available\n"
+	if got := formatStructured("top pod", raw); got != raw {
+		t.Errorf("expected raw fallback, got %q", got)
+	}
+
+	// Commands without a parser are returned unchanged
+	if got := formatStructured("logs nginx", output); got != output {
+		t.Errorf("expected unchanged output for command without parser, got %q", got)
+	}
+}
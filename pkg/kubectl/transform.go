@@ -0,0 +1,126 @@
+package kubectl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Azure/mcp-kubernetes/pkg/config"
+)
+
+// jqTransformTimeout bounds the time a jq expression may spend on one result
+const jqTransformTimeout = 2 * time.Second
+
+// resultTransformer rewrites the output of a command before it is returned. expression is
+// the caller's, or "" to use the transformer's configured default.
+type resultTransformer interface {
+	Transform(output, expression string) (string, error)
+}
+
+// newResultTransformer returns the transformer selected by --result-transform, or nil when
+// none is configured
+func newResultTransformer(cfg *config.ConfigData) resultTransformer {
+	switch cfg.ResultTransform {
+	case config.ResultTransformJQ:
+		return &jqTransformer{defaultExpression: cfg.JQExpression, timeout: jqTransformTimeout}
+	default:
+		return nil
+	}
+}
+
+// checkResultTransform rejects a jq parameter the server can't apply, before the command runs
+func checkResultTransform(params map[string]interface{}, cfg *config.ConfigData) error {
+	expression, _ := params["jq"].(string)
+	if strings.TrimSpace(expression) == "" {
+		return nil
+	}
+	if cfg.ResultTransform != config.ResultTransformJQ {
+		return fmt.Errorf("jq is not enabled on this server; it requires --result-transform=jq")
+	}
+	if namesOnly, _ := params["names_only"].(bool); namesOnly {
+		return fmt.Errorf("jq can't be combined with names_only")
+	}
+	if structured, _ := params["structured"].(bool); structured {
+		return fmt.Errorf("jq can't be combined with structured")
+	}
+	_, err := compileJQ(expression)
+	return err
+}
+
+// jqTransformer applies a jq expression to JSON output. Output that isn't JSON is returned
+// unchanged under the configured default expression and refused for a caller's expression,
+// which asked for a transformation that can't be made.
+type jqTransformer struct {
+	defaultExpression string
+	timeout           time.Duration
+}
+
+// Transform implements resultTransformer. A single result is returned as indented JSON and
+// several as one compact JSON value per line, like jq -c.
+func (t *jqTransformer) Transform(output, expression string) (string, error) {
+	expression = strings.TrimSpace(expression)
+	requested := expression != ""
+	if !requested {
+		expression = strings.TrimSpace(t.defaultExpression)
+	}
+	if expression == "" {
+		return output, nil
+	}
+
+	var input interface{}
+	if err := json.Unmarshal([]byte(output), &input); err != nil {
+		if requested {
+			return "", fmt.Errorf("jq requires JSON output; add -o json to args")
+		}
+		return output, nil
+	}
+	filter, err := compileJQ(expression)
+	if err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), t.timeout)
+	defer cancel()
+	type evaluation struct {
+		values []interface{}
+		err    error
+	}
+	done := make(chan evaluation, 1)
+	go func() {
+		values, err := filter(ctx, input)
+		done <- evaluation{values, err}
+	}()
+
+	var result evaluation
+	select {
+	case result = <-done:
+	case <-ctx.Done():
+		return "", fmt.Errorf("jq expression took longer than %s", t.timeout)
+	}
+	if result.err != nil {
+		if ctx.Err() != nil {
+			return "", fmt.Errorf("jq expression took longer than %s", t.timeout)
+		}
+		return "", fmt.Errorf("jq: %w", result.err)
+	}
+
+	if len(result.values) == 1 {
+		data, err := json.MarshalIndent(result.values[0], "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("failed to encode jq result: %w", err)
+		}
+		return string(data) + "\n", nil
+	}
+	var lines strings.Builder
+	for _, value := range result.values {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return "", fmt.Errorf("failed to encode jq result: %w", err)
+		}
+		lines.Write(data)
+		lines.WriteString("\n")
+	}
+	return lines.String(), nil
+}
@@ -0,0 +1,101 @@
+package kubectl
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Azure/mcp-kubernetes/pkg/config"
+)
+
+const podListJSON = `{
+    "apiVersion": "v1",
+    "items": [
+        {"kind": "Pod", "metadata": {"name": "web-0", "labels": {"app.kubernetes.io/name": "web"}}},
+        {"kind": "Pod", "metadata": {"name": "web-1", "labels": {"app.kubernetes.io/name": "web"}}}
+    ],
+    "kind": "List"
+}
+`
+
+func TestKubectlToolExecutor_JQTransform(t *testing.T) {
+	worker := newTestWorker(t, func(command string) map[string]interface{} {
+		if command != "kubectl get pods -n shop -o json --show-managed-fields=false" {
+			return map[string]interface{}{"error": "unexpected command: " + command}
+		}
+		return map[string]interface{}{"stdout": podListJSON}
+	})
+	executor := NewKubectlToolExecutor(worker)
+	cfg := newTestConfig("readonly")
+	cfg.ResultTransform = config.ResultTransformJQ
+
+	params := map[string]interface{}{
+		"_tool_name": "kubectl_resources",
+		"operation":  "get",
+		"resource":   "pods",
+		"args":       "-n shop -o json",
+		"jq":         "[.items[].metadata.name]",
+	}
+	result, err := executor.Execute(params, cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "[\n  \"web-0\",\n  \"web-1\"\n]\n"; result != want {
+		t.Errorf("result = %q, want %q", result, want)
+	}
+
+	// The configured expression applies when the call doesn't pass one
+	delete(params, "jq")
+	cfg.JQExpression = `.items | length`
+	if result, err := executor.Execute(params, cfg); err != nil || result != "2\n" {
+		t.Errorf("result = %q, %v; want 2", result, err)
+	}
+
+	// Without --result-transform the parameter is refused
+	params["jq"] = ".items"
+	if _, err := executor.Execute(params, newTestConfig("readonly")); err == nil || !strings.Contains(err.Error(), "--result-transform=jq") {
+		t.Errorf("expected jq to be refused without the transform, got %v", err)
+	}
+}
+
+func TestJQTransformer(t *testing.T) {
+	transformer := &jqTransformer{timeout: time.Second}
+	tests := []struct {
+		expression string
+		expected   string
+	}{
+		{".", "{\n  \"a\": 1\n}\n"},
+		{".items[0].metadata.name", "\"web-0\"\n"},
+		{".items[-1].metadata.name", "\"web-1\"\n"},
+		{`.items[].metadata.labels."app.kubernetes.io/name"`, "\"web\"\n\"web\"\n"},
+		{".items[0].metadata | keys", "[\n  \"labels\",\n  \"name\"\n]\n"},
+		{".missing.field", "null\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			input := podListJSON
+			if tt.expression == "." {
+				input = `{"a": 1}`
+			}
+			got, err := transformer.Transform(input, tt.expression)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("Transform(%q) = %q, want %q", tt.expression, got, tt.expected)
+			}
+		})
+	}
+
+	for _, expression := range []string{".items[", "select(.a)", ".items..name", ".a | "} {
+		if _, err := compileJQ(expression); err == nil {
+			t.Errorf("expected %q to be rejected", expression)
+		}
+	}
+	if _, err := transformer.Transform("NAME   READY\nweb-0  1/1\n", ".items"); err == nil {
+		t.Error("expected a caller's expression on table output to be refused")
+	}
+	if _, err := transformer.Transform(`"text"`, ".a"); err == nil {
+		t.Error("expected indexing a string to fail")
+	}
+}
@@ -0,0 +1,64 @@
+package kubectl
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/Azure/mcp-kubernetes/pkg/tools"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestHTTPMaxAccessLevel(t *testing.T) {
+	var commands []string
+	worker := newTestWorker(t, func(command string) map[string]interface{} {
+		commands = append(commands, command)
+		return map[string]interface{}{"stdout": "deployment.apps/web scaled\n"}
+	})
+	executor := NewKubectlToolExecutor(worker)
+
+	call := func(transport, tool, operation, args string) *mcp.CallToolResult {
+		cfg := newTestConfig("readwrite")
+		cfg.Transport = transport
+		cfg.HTTPMaxAccessLevel = "readonly"
+		handler := tools.CreateToolHandlerWithName(executor, cfg, tool)
+		req := mcp.CallToolRequest{}
+		req.Params.Name = tool
+		req.Params.Arguments = map[string]interface{}{"operation": operation, "resource": "deployment", "args": args}
+		result, err := handler(context.Background(), req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return result
+	}
+	text := func(result *mcp.CallToolResult) string {
+		if len(result.Content) == 0 {
+			return ""
+		}
+		content, _ := result.Content[0].(mcp.TextContent)
+		return content.Text
+	}
+
+	// A write over the capped http transports is denied before it runs, naming the flag
+	for _, transport := range []string{"streamable-http", "sse"} {
+		result := call(transport, "kubectl_workloads", "scale", "web -n shop --replicas=3")
+		if !result.IsError || !strings.Contains(text(result), "--http-max-access-level=readonly") {
+			t.Errorf("expected the scale over %s to be denied by the ceiling, got %q", transport, text(result))
+		}
+	}
+	if len(commands) != 0 {
+		t.Fatalf("expected nothing to run over http, got %v", commands)
+	}
+
+	// The same write over stdio runs at the global access level
+	result := call("stdio", "kubectl_workloads", "scale", "web -n shop --replicas=3")
+	if result.IsError || len(commands) != 1 || commands[0] != "kubectl scale deployment web -n shop --replicas=3" {
+		t.Errorf("expected the scale over stdio to run, got %q and %v", text(result), commands)
+	}
+
+	// Reads over http are unaffected
+	commands = nil
+	if result := call("streamable-http", "kubectl_resources", "get", "web -n shop"); result.IsError || len(commands) != 1 {
+		t.Errorf("expected the read over http to run, got %q and %v", text(result), commands)
+	}
+}
@@ -8,9 +8,12 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"log/slog"
@@ -61,6 +64,9 @@ type Config struct {
 	Timeout             int
 	CaptureEndpoint     string
 	Fingerprint         string
+	// UserAgent is sent with every request as user_agent, for the agent to set on kubectl's API
+	// requests. kubectl has no flag for it, so it only takes effect with agents that honor the field.
+	UserAgent string
 }
 
 // Worker is the main worker struct
@@ -69,9 +75,10 @@ type Worker struct {
 	pulsarClient *ws.Client
 	topic        string
 	consumer     ws.Consumer
-	messages     map[string]*ws.Msg
-	messagesLock sync.Mutex
 	pending      sync.Map
+	lastID       atomic.Int64
+	stats        workerCounters
+	breaker      agentBreaker
 }
 
 // New creates a new worker
@@ -95,25 +102,8 @@ func New(cfg *Config) (*Worker, error) {
 		cfg:          cfg,
 		pulsarClient: ws.New(cfg.PulsarHost),
 		topic:        topic,
-		messages:     make(map[string]*ws.Msg),
 	}, nil
 }
-func (w *Worker) GetMessage(key string) (*ws.Msg, bool) {
-	w.messagesLock.Lock()
-	defer w.messagesLock.Unlock()
-	msg, ok := w.messages[key]
-	return msg, ok
-}
-func (w *Worker) DeleteMessage(key string) {
-	w.messagesLock.Lock()
-	defer w.messagesLock.Unlock()
-	delete(w.messages, key)
-}
-func (w *Worker) SetMessage(key string, msg *ws.Msg) {
-	w.messagesLock.Lock()
-	defer w.messagesLock.Unlock()
-	w.messages[key] = msg
-}
 
 func (w *Worker) StartSubscriber(topic string) error {
 	return w.startSubscriberWithRetry(topic, 0)
@@ -140,6 +130,7 @@ func (w *Worker) startSubscriberWithRetry(topic string, attempt int) error {
 	}
 
 	w.consumer = consumer
+	w.stats.subscribed.Store(true)
 	slog.Info("started subscriber", "topic", topic)
 
 	go func() {
@@ -149,43 +140,132 @@ func (w *Worker) startSubscriberWithRetry(topic string, attempt int) error {
 			if err != nil {
 				slog.Error("consumer receive error", "err", err)
 				consumer.Close()
+				w.stats.subscribed.Store(false)
+				w.stats.reconnects.Add(1)
 
 				// reconnect (restart fresh)
 				_ = w.startSubscriberWithRetry(topic, 0)
 				return
 			}
 
-			var payload struct {
-				Id     int                    `json:"Id"`
-				Result map[string]interface{} `json:"result"`
-			}
-			if err := json.Unmarshal(msg.Payload, &payload); err != nil {
-				w.retryAck(ctx, consumer, msg)
-				continue
-			}
-
-			if chAny, ok := w.pending.Load(payload.Id); ok {
-				if ch, ok := chAny.(chan string); ok {
-					if stdout, ok := payload.Result["stdout"].(string); ok {
-						slog.Info("received response", slog.Int("id", payload.Id))
-						ch <- stdout
-					} else {
-						ch <- ""
-					}
-					close(ch)
-				}
-				w.pending.Delete(payload.Id)
-				w.retryAck(ctx, consumer, msg)
-				continue
-			}
-
-			w.retryNack(ctx, consumer, msg)
+			w.handleMessage(ctx, consumer, msg)
 		}
 	}()
 
 	return nil
 }
 
+// handleMessage delivers a response to the request waiting for it and acks the message.
+// Messages nobody here is waiting for are nacked so another subscriber can pick them up.
+func (w *Worker) handleMessage(ctx context.Context, consumer ws.Consumer, msg *ws.Msg) {
+	w.stats.lastReceive.Store(time.Now().UnixNano())
+
+	var payload struct {
+		Id     int                    `json:"Id"`
+		Result map[string]interface{} `json:"result"`
+	}
+	if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+		w.retryAck(ctx, consumer, msg)
+		return
+	}
+
+	if w.deliver(payload.Id, payload.Result) {
+		slog.Info("received response", slog.Int("id", payload.Id))
+		w.retryAck(ctx, consumer, msg)
+		return
+	}
+
+	w.retryNack(ctx, consumer, msg)
+}
+
+// deliver hands a remote result to the request waiting on id, reporting whether one was waiting.
+// Streaming requests stay pending until their final chunk arrives.
+func (w *Worker) deliver(id int, result map[string]interface{}) bool {
+	sink, ok := w.pending.Load(id)
+	if !ok {
+		return false
+	}
+	result = decodeResult(result)
+
+	switch sink := sink.(type) {
+	case *commandStream:
+		chunk := parseStreamChunk(result)
+		if chunk.Done {
+			w.pending.CompareAndDelete(id, sink)
+		}
+		// Chunks arriving after the reader stopped are still ours, so they are dropped and acked
+		sink.send(chunk)
+	case chan *commandResult:
+		if !w.pending.CompareAndDelete(id, sink) {
+			return false
+		}
+		sink <- parseCommandResult(result)
+		close(sink)
+	default:
+		w.pending.Delete(id)
+	}
+	return true
+}
+
+// commandResult is the outcome of a command executed by the remote agent
+type commandResult struct {
+	Stdout string
+	Err    error
+}
+
+// parseCommandResult extracts the output of a remote command. An error field, a nonzero
+// exit_code, or stderr without any stdout is reported as an error rather than masked as
+// an empty success. Stderr alongside stdout is treated as warnings.
+func parseCommandResult(result map[string]interface{}) *commandResult {
+	stdout, hasStdout := result["stdout"].(string)
+	stderr, _ := result["stderr"].(string)
+	errMsg, _ := result["error"].(string)
+
+	if errMsg != "" {
+		return &commandResult{Stdout: stdout, Err: errors.New(errMsg)}
+	}
+
+	if code, ok := exitCode(result["exit_code"]); ok && code != 0 {
+		return &commandResult{Stdout: stdout, Err: &exitError{Code: code, Stdout: stdout, Stderr: stderr}}
+	}
+
+	if !hasStdout && stderr != "" {
+		return &commandResult{Err: errors.New(strings.TrimSpace(stderr))}
+	}
+
+	return &commandResult{Stdout: stdout}
+}
+
+// exitError reports a remote command that exited with a nonzero code
+type exitError struct {
+	Code   int
+	Stdout string
+	Stderr string
+}
+
+func (e *exitError) Error() string {
+	detail := strings.TrimSpace(e.Stderr)
+	if detail == "" {
+		detail = strings.TrimSpace(e.Stdout)
+	}
+	return fmt.Sprintf("command exited with code %d: %s", e.Code, detail)
+}
+
+// exitCode reads a numeric exit code, which may arrive as a JSON number or string
+func exitCode(value interface{}) (int, bool) {
+	switch v := value.(type) {
+	case float64:
+		return int(v), true
+	case int:
+		return v, true
+	case string:
+		code, err := strconv.Atoi(v)
+		return code, err == nil
+	default:
+		return 0, false
+	}
+}
+
 func (w *Worker) retryAck(ctx context.Context, consumer ws.Consumer, msg *ws.Msg) {
 	for {
 		if err := consumer.Ack(ctx, msg); err != nil {
@@ -193,6 +273,7 @@ func (w *Worker) retryAck(ctx context.Context, consumer ws.Consumer, msg *ws.Msg
 			time.Sleep(1 * time.Second)
 			continue
 		}
+		w.stats.acks.Add(1)
 		return
 	}
 }
@@ -204,6 +285,7 @@ func (w *Worker) retryNack(ctx context.Context, consumer ws.Consumer, msg *ws.Ms
 			time.Sleep(1 * time.Second)
 			continue
 		}
+		w.stats.nacks.Add(1)
 		return
 	}
 }
@@ -252,6 +334,12 @@ func (w *Worker) produceMessage(accountUid string,
 }
 
 func (w *Worker) sendRequest(accountUid string, id int, topic string, payload map[string]interface{}) error {
+	// Let the agent compress large output; decodeResult expands it
+	payload["accept_encoding"] = payloadEncodingGzip
+	if w.cfg.UserAgent != "" {
+		payload["user_agent"] = w.cfg.UserAgent
+	}
+
 	idString := fmt.Sprintf("%d", id)
 	payloadMap := map[string]interface{}{
 		"Not":         "",
@@ -263,15 +351,73 @@ func (w *Worker) sendRequest(accountUid string, id int, topic string, payload ma
 		"topic":       topic,
 		"result":      payload,
 	}
-	return w.produceMessage(accountUid, topic, idString, payloadMap)
+	if err := w.produceMessage(accountUid, topic, idString, payloadMap); err != nil {
+		return err
+	}
+	w.stats.requestsSent.Add(1)
+	return nil
+}
+
+// nextRequestID returns a millisecond timestamp id, bumped when needed so concurrent
+// requests never share an id
+func (w *Worker) nextRequestID() int {
+	for {
+		last := w.lastID.Load()
+		id := time.Now().UnixMilli()
+		if id <= last {
+			id = last + 1
+		}
+		if w.lastID.CompareAndSwap(last, id) {
+			return int(id)
+		}
+	}
+}
+
+// pingCommand is answered by the remote agent without contacting the API server
+const pingCommand = "kubectl version --client"
+
+// Ping checks that the remote agent is alive with a request it can answer without touching
+// the cluster, so a slow API server isn't mistaken for a dead agent
+func (w *Worker) Ping(timeout time.Duration) error {
+	if w.cfg == nil {
+		return fmt.Errorf("remote worker is not configured")
+	}
+
+	id := w.nextRequestID()
+	respCh := make(chan *commandResult, 1)
+	w.pending.Store(id, respCh)
+
+	topic := fmt.Sprintf("mcp-%s-%x",
+		strings.ToLower(w.cfg.Token),
+		sha1.Sum([]byte(strings.ToLower(w.cfg.Location))))
+
+	if err := w.sendRequest(w.cfg.AccountUID, id, topic, map[string]interface{}{
+		"command":         pingCommand,
+		"ping":            true,
+		"timeout_seconds": int(math.Ceil(timeout.Seconds())),
+	}); err != nil {
+		w.pending.Delete(id)
+		return fmt.Errorf("failed to send ping: %s", err.Error())
+	}
+
+	select {
+	case res := <-respCh:
+		if res.Err != nil {
+			return fmt.Errorf("remote agent answered ping with an error: %w", res.Err)
+		}
+		return nil
+	case <-time.After(timeout):
+		w.pending.Delete(id)
+		return fmt.Errorf("timeout waiting for ping response after %s", timeout)
+	}
 }
 
 // CheckClusterRolePermission validates if mw-opsai-cluster-role exists
 func (w *Worker) CheckClusterRolePermission(timeout int) *ClusterRoleCheckResult {
 	cmd := "kubectl get clusterroles"
 
-	id := int(time.Now().UnixMilli())
-	respCh := make(chan string, 1)
+	id := w.nextRequestID()
+	respCh := make(chan *commandResult, 1)
 	w.pending.Store(id, respCh)
 
 	topic := fmt.Sprintf("mcp-%s-%x",
@@ -279,7 +425,8 @@ func (w *Worker) CheckClusterRolePermission(timeout int) *ClusterRoleCheckResult
 		sha1.Sum([]byte(strings.ToLower(w.cfg.Location))))
 
 	err := w.sendRequest(w.cfg.AccountUID, id, topic, map[string]interface{}{
-		"command": cmd,
+		"command":         cmd,
+		"timeout_seconds": timeout,
 	})
 	if err != nil {
 		slog.Error("failed to send cluster role check request", "error", err, "id", id, "topic", topic)
@@ -295,11 +442,21 @@ func (w *Worker) CheckClusterRolePermission(timeout int) *ClusterRoleCheckResult
 
 	slog.Info("checking for mw-opsai-cluster-role", "id", id, "topic", topic)
 
-	var res string
 	select {
-	case res = <-respCh:
+	case res := <-respCh:
 		slog.Info("received cluster roles response", "id", id)
-		if strings.Contains(res, "mw-opsai-cluster-role") {
+		if res.Err != nil {
+			slog.Error("cluster role check failed on remote agent", "id", id, "error", res.Err)
+			return &ClusterRoleCheckResult{
+				Success:          false,
+				HasAdminRole:     false,
+				ErrorType:        "other",
+				ErrorMessage:     fmt.Sprintf("failed to list cluster roles: %s", res.Err.Error()),
+				ClusterRoleFound: false,
+				ResponseReceived: true,
+			}
+		}
+		if strings.Contains(res.Stdout, "mw-opsai-cluster-role") {
 			slog.Info("mw-opsai-cluster-role found - admin/write permission available")
 			return &ClusterRoleCheckResult{
 				Success:          true,
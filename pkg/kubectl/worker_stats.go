@@ -0,0 +1,61 @@
+package kubectl
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// workerCounters track the worker's traffic with the remote agent and the Pulsar subscription
+type workerCounters struct {
+	requestsSent atomic.Int64
+	acks         atomic.Int64
+	nacks        atomic.Int64
+	reconnects   atomic.Int64
+	lastReceive  atomic.Int64 // unix nanoseconds of the last message received, 0 if none
+	subscribed   atomic.Bool
+}
+
+// WorkerStats is a snapshot of the remote execution transport, for debugging the worker
+// rather than the cluster
+type WorkerStats struct {
+	Pending      int        `json:"pending"`
+	RequestsSent int64      `json:"requests_sent"`
+	Acks         int64      `json:"acks"`
+	Nacks        int64      `json:"nacks"`
+	Reconnects   int64      `json:"reconnects"`
+	LastReceive  *time.Time `json:"last_receive,omitempty"`
+	Subscription string     `json:"subscription"` // "active", "reconnecting" or "not started"
+	Breaker      string     `json:"breaker"`      // "closed", or "open" while requests to the agent are rejected
+}
+
+// Stats returns the worker's current transport counters
+func (w *Worker) Stats() WorkerStats {
+	stats := WorkerStats{Subscription: "not started", Breaker: "closed"}
+	if w == nil {
+		return stats
+	}
+
+	w.pending.Range(func(any, any) bool {
+		stats.Pending++
+		return true
+	})
+	stats.RequestsSent = w.stats.requestsSent.Load()
+	stats.Acks = w.stats.acks.Load()
+	stats.Nacks = w.stats.nacks.Load()
+	stats.Reconnects = w.stats.reconnects.Load()
+	if last := w.stats.lastReceive.Load(); last != 0 {
+		received := time.Unix(0, last).UTC()
+		stats.LastReceive = &received
+	}
+
+	if w.breaker.isOpen() {
+		stats.Breaker = "open"
+	}
+	switch {
+	case w.stats.subscribed.Load():
+		stats.Subscription = "active"
+	case stats.Reconnects > 0:
+		stats.Subscription = "reconnecting"
+	}
+	return stats
+}
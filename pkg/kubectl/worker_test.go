@@ -0,0 +1,340 @@
+package kubectl
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Azure/mcp-kubernetes/pkg/config"
+	"github.com/Azure/mcp-kubernetes/pkg/kubectl/ws"
+	"github.com/Azure/mcp-kubernetes/pkg/security"
+)
+
+// newTestWorker returns a worker whose requests are answered by reply, standing in for the
+// remote agent. reply receives the full kubectl command and returns the agent's result map.
+func newTestWorker(t *testing.T, reply func(command string) map[string]interface{}) *Worker {
+	t.Helper()
+	return newTestWorkerWithInput(t, func(command, _ string) map[string]interface{} { return reply(command) })
+}
+
+// newTestWorkerWithInput is newTestWorker for replies that depend on the command's stdin
+func newTestWorkerWithInput(t *testing.T, reply func(command, stdin string) map[string]interface{}) *Worker {
+	t.Helper()
+
+	var w *Worker
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		var msg struct {
+			Payload struct {
+				Id     int                    `json:"Id"`
+				Result map[string]interface{} `json:"result"`
+			}
+		}
+		if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		command, _ := msg.Payload.Result["command"].(string)
+		stdin, _ := msg.Payload.Result["stdin"].(string)
+		w.deliver(msg.Payload.Id, reply(command, stdin))
+	}))
+	t.Cleanup(srv.Close)
+
+	w, err := New(&Config{
+		Mode:                ModeAgent,
+		Location:            "test-host",
+		Token:               "test-token",
+		UnsubscribeEndpoint: srv.URL,
+		Timeout:             5,
+	})
+	if err != nil {
+		t.Fatalf("failed to create worker: %v", err)
+	}
+	return w
+}
+
+// newTestConfig returns a configuration at the given access level with no namespace restrictions
+func newTestConfig(accessLevel string) *config.ConfigData {
+	cfg := config.NewConfig()
+	cfg.AccessLevel = accessLevel
+	cfg.SecurityConfig.AccessLevel = security.AccessLevel(accessLevel)
+	// Tests match the commands sent to the worker exactly; request_timeout_test.go covers the injected flag
+	cfg.InjectRequestTimeout = false
+	return cfg
+}
+
+// fakeConsumer records acks and nacks from the subscriber
+type fakeConsumer struct {
+	acked  []*ws.Msg
+	nacked []*ws.Msg
+}
+
+func (f *fakeConsumer) Receive(context.Context) (*ws.Msg, error) { return nil, context.Canceled }
+func (f *fakeConsumer) Ack(_ context.Context, m *ws.Msg) error {
+	f.acked = append(f.acked, m)
+	return nil
+}
+func (f *fakeConsumer) Nack(_ context.Context, m *ws.Msg) error {
+	f.nacked = append(f.nacked, m)
+	return nil
+}
+func (f *fakeConsumer) Close() error { return nil }
+
+func TestParseCommandResult(t *testing.T) {
+	tests := []struct {
+		name       string
+		result     map[string]interface{}
+		wantStdout string
+		wantErr    string
+	}{
+		{"stdout only", map[string]interface{}{"stdout": "pod/nginx\n"}, "pod/nginx\n", ""},
+		{"stdout with warnings on stderr", map[string]interface{}{"stdout": "ok", "stderr": "Warning: deprecated"}, "ok", ""},
+		{"error field", map[string]interface{}{"error": "kubectl not found"}, "", "kubectl not found"},
+		{"nonzero exit code", map[string]interface{}{"stdout": "", "stderr": "Error from server (NotFound)", "exit_code": float64(1)}, "", "exited with code 1: Error from server (NotFound)"},
+		{"string exit code", map[string]interface{}{"stderr": "boom", "exit_code": "2"}, "", "exited with code 2: boom"},
+		{"stderr without stdout", map[string]interface{}{"stderr": "connection refused"}, "", "connection refused"},
+		{"empty result", map[string]interface{}{}, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := parseCommandResult(tt.result)
+			if tt.wantErr == "" {
+				if res.Err != nil {
+					t.Fatalf("unexpected error = %v", res.Err)
+				}
+				if res.Stdout != tt.wantStdout {
+					t.Errorf("stdout = %q, want %q", res.Stdout, tt.wantStdout)
+				}
+				return
+			}
+			if res.Err == nil || !strings.Contains(res.Err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want error containing %q", res.Err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestKubectlToolExecutor_RemoteErrorPropagates(t *testing.T) {
+	worker := newTestWorker(t, func(command string) map[string]interface{} {
+		return map[string]interface{}{
+			"error":     "error: the server doesn't have a resource type \"podz\"",
+			"exit_code": float64(1),
+		}
+	})
+	executor := NewKubectlToolExecutor(worker)
+
+	_, err := executor.Execute(map[string]interface{}{
+		"_tool_name": "kubectl_resources",
+		"operation":  "get",
+		"resource":   "podz",
+		"args":       "",
+	}, newTestConfig("readonly"))
+	if err == nil || !strings.Contains(err.Error(), "doesn't have a resource type") {
+		t.Fatalf("expected remote error to propagate, got %v", err)
+	}
+}
+
+func TestWorkerHandleMessage(t *testing.T) {
+	worker := &Worker{}
+	consumer := &fakeConsumer{}
+
+	respCh := make(chan *commandResult, 1)
+	worker.pending.Store(42, respCh)
+
+	// A response for a pending request is delivered and acked
+	msg := &ws.Msg{Payload: []byte(`{"Id":42,"result":{"stdout":"hello"}}`)}
+	worker.handleMessage(context.Background(), consumer, msg)
+	res := <-respCh
+	if res.Err != nil || res.Stdout != "hello" {
+		t.Errorf("unexpected result: %+v", res)
+	}
+	if len(consumer.acked) != 1 {
+		t.Errorf("expected message to be acked")
+	}
+
+	// A response nobody is waiting for is nacked
+	worker.handleMessage(context.Background(), consumer, &ws.Msg{Payload: []byte(`{"Id":7,"result":{}}`)})
+	if len(consumer.nacked) != 1 {
+		t.Errorf("expected unknown message to be nacked")
+	}
+}
+
+// queueConsumer hands queued messages to the subscriber, standing in for the Pulsar consumer
+type queueConsumer struct {
+	fakeConsumer
+	msgs chan *ws.Msg
+}
+
+func (q *queueConsumer) Receive(ctx context.Context) (*ws.Msg, error) {
+	select {
+	case msg := <-q.msgs:
+		return msg, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func TestWorkerPing(t *testing.T) {
+	consumer := &queueConsumer{msgs: make(chan *ws.Msg, 1)}
+	var requests []map[string]interface{}
+
+	// The fake agent answers pings by publishing a response for the consumer to receive
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		var msg struct {
+			Payload struct {
+				Id     int                    `json:"Id"`
+				Result map[string]interface{} `json:"result"`
+			}
+		}
+		if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+		requests = append(requests, msg.Payload.Result)
+
+		if ping, _ := msg.Payload.Result["ping"].(bool); ping {
+			payload, _ := json.Marshal(map[string]interface{}{
+				"Id":     msg.Payload.Id,
+				"result": map[string]interface{}{"stdout": "Client Version: v1.30.0"},
+			})
+			consumer.msgs <- &ws.Msg{Payload: payload}
+		}
+	}))
+	defer srv.Close()
+
+	worker, err := New(&Config{Mode: ModeAgent, Token: "test-token", Location: "test-host", UnsubscribeEndpoint: srv.URL})
+	if err != nil {
+		t.Fatalf("failed to create worker: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		for {
+			msg, err := consumer.Receive(ctx)
+			if err != nil {
+				return
+			}
+			worker.handleMessage(ctx, consumer, msg)
+		}
+	}()
+
+	if err := worker.Ping(2 * time.Second); err != nil {
+		t.Fatalf("Ping() error = %v", err)
+	}
+	if len(requests) != 1 || requests[0]["command"] != pingCommand {
+		t.Errorf("unexpected ping request: %v", requests)
+	}
+
+	// An agent that never answers times out
+	silent := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	defer silent.Close()
+	worker.cfg.UnsubscribeEndpoint = silent.URL
+	if err := worker.Ping(50 * time.Millisecond); err == nil || !strings.Contains(err.Error(), "timeout") {
+		t.Errorf("expected timeout, got %v", err)
+	}
+}
+
+func TestWorkerUserAgent(t *testing.T) {
+	// run sends one command through a worker with the given user agent and returns the request
+	// the agent received
+	run := func(userAgent string) map[string]interface{} {
+		var request map[string]interface{}
+		var w *Worker
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			var msg struct {
+				Payload struct {
+					Id     int                    `json:"Id"`
+					Result map[string]interface{} `json:"result"`
+				}
+			}
+			if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+				http.Error(rw, err.Error(), http.StatusBadRequest)
+				return
+			}
+			request = msg.Payload.Result
+			w.deliver(msg.Payload.Id, map[string]interface{}{"stdout": "No resources found\n"})
+		}))
+		defer srv.Close()
+
+		w, err := New(&Config{Mode: ModeAgent, Token: "test-token", Location: "test-host", UnsubscribeEndpoint: srv.URL,
+			Timeout: 5, UserAgent: userAgent})
+		if err != nil {
+			t.Fatalf("failed to create worker: %v", err)
+		}
+		params := map[string]interface{}{"_tool_name": "kubectl_resources", "operation": "get", "resource": "pods", "args": "-n shop"}
+		if _, err := NewKubectlToolExecutor(w).Execute(params, newTestConfig("readonly")); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return request
+	}
+
+	// The user agent travels as its own field, for the agent to set; the command line is unchanged
+	request := run("mcp-kubernetes/test")
+	if request["user_agent"] != "mcp-kubernetes/test" {
+		t.Errorf("expected the request to carry user_agent, got %v", request)
+	}
+	if request["command"] != "kubectl get pods -n shop" {
+		t.Errorf("expected the command without the user agent, got %v", request["command"])
+	}
+
+	// An empty user agent sends no field, leaving kubectl's own
+	if request := run(""); request["user_agent"] != nil {
+		t.Errorf("expected no user_agent, got %v", request["user_agent"])
+	}
+}
+
+func TestWorkerStats(t *testing.T) {
+	consumer := &fakeConsumer{}
+	var worker *Worker
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		var msg struct {
+			Payload struct {
+				Id int `json:"Id"`
+			}
+		}
+		if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// While the request is in flight it counts as pending
+		if stats := worker.Stats(); stats.Pending != 1 || stats.RequestsSent != 0 {
+			t.Errorf("stats during request = %+v, want one pending and none sent yet", stats)
+		}
+		payload, _ := json.Marshal(map[string]interface{}{
+			"Id":     msg.Payload.Id,
+			"result": map[string]interface{}{"stdout": "Client Version: v1.30.0"},
+		})
+		worker.handleMessage(context.Background(), consumer, &ws.Msg{Payload: payload})
+	}))
+	defer srv.Close()
+
+	worker, err := New(&Config{Mode: ModeAgent, Token: "test-token", Location: "test-host", UnsubscribeEndpoint: srv.URL})
+	if err != nil {
+		t.Fatalf("failed to create worker: %v", err)
+	}
+	if stats := worker.Stats(); stats.RequestsSent != 0 || stats.LastReceive != nil || stats.Subscription != "not started" {
+		t.Errorf("unexpected initial stats: %+v", stats)
+	}
+
+	before := time.Now()
+	if err := worker.Ping(2 * time.Second); err != nil {
+		t.Fatalf("Ping() error = %v", err)
+	}
+	// A response nobody is waiting for is nacked
+	worker.handleMessage(context.Background(), consumer, &ws.Msg{Payload: []byte(`{"Id":7,"result":{}}`)})
+
+	stats := worker.Stats()
+	if stats.Pending != 0 || stats.RequestsSent != 1 || stats.Acks != 1 || stats.Nacks != 1 {
+		t.Errorf("unexpected stats after request/response: %+v", stats)
+	}
+	if stats.LastReceive == nil || stats.LastReceive.Before(before) {
+		t.Errorf("last_receive = %v, want after %v", stats.LastReceive, before)
+	}
+}
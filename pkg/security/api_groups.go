@@ -0,0 +1,77 @@
+package security
+
+import "strings"
+
+// SetDeniedAPIGroups sets the comma-separated API groups that commands may not touch, e.g.
+// "rbac.authorization.k8s.io,policy". "core" names the core group of pods, services and
+// the like. Without a list no group is denied.
+func (s *SecurityConfig) SetDeniedAPIGroups(groups string) {
+	s.deniedAPIGroups = []string{}
+	for _, group := range strings.Split(groups, ",") {
+		group = strings.ToLower(strings.TrimSpace(group))
+		if group == "core" {
+			group = ""
+		} else if group == "" {
+			continue
+		}
+		s.deniedAPIGroups = append(s.deniedAPIGroups, group)
+	}
+}
+
+// IsAPIGroupDenied reports whether a resource type reference belongs to a denied API group.
+// A group-qualified reference such as "roles.rbac.authorization.k8s.io" names its group;
+// others are resolved through the resource catalog, which discovery fills with the cluster's
+// api-resources. References the catalog doesn't know are not denied.
+func (s *SecurityConfig) IsAPIGroupDenied(reference string) bool {
+	if len(s.deniedAPIGroups) == 0 {
+		return false
+	}
+	group, ok := resourceGroup(s.resources, reference)
+	if !ok {
+		return false
+	}
+	for _, denied := range s.deniedAPIGroups {
+		if denied == group {
+			return true
+		}
+	}
+	return false
+}
+
+// resourceGroup returns the API group of a resource type reference, and whether it is known
+func resourceGroup(catalog *ResourceCatalog, reference string) (string, bool) {
+	name, _, _ := strings.Cut(strings.ToLower(reference), "/")
+	if _, group, ok := strings.Cut(name, "."); ok {
+		return group, true
+	}
+	resource, ok := catalog.Lookup(name)
+	if !ok {
+		return "", false
+	}
+	return resource.Group, true
+}
+
+// validateDeniedAPIGroups rejects commands, reads included, whose resource type belongs to a
+// denied API group. Resources named only in files (-f) are not checked.
+func (v *Validator) validateDeniedAPIGroups(command string) error {
+	if len(v.secConfig.deniedAPIGroups) == 0 {
+		return nil
+	}
+	_, target := commandTarget(command)
+	reference, _, _ := strings.Cut(target, "/")
+	if reference == "" {
+		return nil
+	}
+	for _, resourceType := range strings.Split(reference, ",") {
+		if v.secConfig.IsAPIGroupDenied(resourceType) {
+			group, _ := resourceGroup(v.secConfig.resources, resourceType)
+			if group == "" {
+				group = "core"
+			}
+			return &ValidationError{
+				Message: "Error: Cannot access resource type '" + resourceType + "' in API group '" + group + "', which is denied by security configuration",
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package security
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// AdminConfirmTokenTTL is how long an issued admin confirmation token stays valid
+const AdminConfirmTokenTTL = 5 * time.Minute
+
+// confirmTokenBytes is the amount of randomness in a confirmation token, enough that it can't
+// be guessed within its TTL
+const confirmTokenBytes = 16
+
+// confirmationToken is a single-use nonce that rotates on every issue and successful use
+type confirmationToken struct {
+	mu     sync.Mutex
+	value  string
+	issued time.Time
+}
+
+// issue generates a new token, invalidating any previously issued one
+func (c *confirmationToken) issue() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	buf := make([]byte, confirmTokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		// Leave no valid token rather than issuing a predictable one
+		c.value = ""
+		return ""
+	}
+
+	c.value = hex.EncodeToString(buf)
+	c.issued = time.Now()
+	return c.value
+}
+
+// consume reports whether token matches the current unexpired token, rotating it out on success
+func (c *confirmationToken) consume(token string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.value == "" || token == "" || time.Since(c.issued) > AdminConfirmTokenTTL {
+		return false
+	}
+	if subtle.ConstantTimeCompare([]byte(c.value), []byte(token)) != 1 {
+		return false
+	}
+
+	// Tokens are single use
+	c.value = ""
+	return true
+}
+
+// IssueAdminConfirmToken generates a new admin confirmation token, replacing the previous one.
+// Settings not made by NewSecurityConfig have no token to issue.
+func (s *SecurityConfig) IssueAdminConfirmToken() string {
+	if s.adminConfirm == nil {
+		return ""
+	}
+	return s.adminConfirm.issue()
+}
+
+// ConsumeAdminConfirmToken checks token against the current admin confirmation token.
+// A matching token is invalidated so each admin operation needs a freshly issued one.
+func (s *SecurityConfig) ConsumeAdminConfirmToken(token string) bool {
+	if s.adminConfirm == nil {
+		return false
+	}
+	return s.adminConfirm.consume(token)
+}
@@ -0,0 +1,29 @@
+package security
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestAdminConfirmToken(t *testing.T) {
+	s := NewSecurityConfig()
+
+	token := s.IssueAdminConfirmToken()
+	if raw, err := hex.DecodeString(token); err != nil || len(raw) != confirmTokenBytes {
+		t.Fatalf("token %q should be %d random bytes in hex", token, confirmTokenBytes)
+	}
+
+	// Issuing again invalidates the previous token
+	next := s.IssueAdminConfirmToken()
+	if next == token || s.ConsumeAdminConfirmToken(token) {
+		t.Errorf("previous token should be invalidated on reissue")
+	}
+
+	// Tokens are single use
+	if !s.ConsumeAdminConfirmToken(next) {
+		t.Fatalf("current token should be accepted")
+	}
+	if s.ConsumeAdminConfirmToken(next) {
+		t.Errorf("token should not be accepted twice")
+	}
+}
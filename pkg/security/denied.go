@@ -0,0 +1,35 @@
+package security
+
+import "strings"
+
+// SetDeniedResources sets the comma-separated resource types that commands may not create or
+// change, e.g. "secrets,clusterrolebindings". Without a list no type is denied.
+func (s *SecurityConfig) SetDeniedResources(resources string) {
+	s.deniedResources = []string{}
+	for _, resource := range strings.Split(resources, ",") {
+		if resource = strings.TrimSpace(resource); resource != "" {
+			s.deniedResources = append(s.deniedResources, resource)
+		}
+	}
+}
+
+// IsResourceDenied reports whether a resource type reference names a denied type. Both are
+// resolved through the resource catalog, so plural names, short names and kinds all match.
+func (s *SecurityConfig) IsResourceDenied(reference string) bool {
+	name := resourceName(s.resources, reference)
+	for _, denied := range s.deniedResources {
+		if resourceName(s.resources, denied) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// resourceName returns the plural name of a resource type reference, or the lowercased
+// reference when the catalog doesn't know it
+func resourceName(catalog *ResourceCatalog, reference string) string {
+	if resource, ok := catalog.Lookup(reference); ok {
+		return resource.Name
+	}
+	return strings.ToLower(reference)
+}
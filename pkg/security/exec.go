@@ -0,0 +1,83 @@
+package security
+
+import (
+	"strings"
+
+	"github.com/google/shlex"
+)
+
+// DefaultNoExecNamespaces are the namespaces exec and cp are refused in unless
+// --no-exec-namespaces says otherwise
+const DefaultNoExecNamespaces = "kube-system"
+
+// SetNoExecNamespaces sets the comma-separated namespaces whose pods exec and cp may not
+// target at any access level, e.g. system namespaces running node agents
+func (s *SecurityConfig) SetNoExecNamespaces(namespaces string) {
+	s.noExecNamespaces = []string{}
+	for _, namespace := range strings.Split(namespaces, ",") {
+		if namespace = strings.TrimSpace(namespace); namespace != "" {
+			s.noExecNamespaces = append(s.noExecNamespaces, namespace)
+		}
+	}
+}
+
+// IsExecDenied reports whether exec and cp are refused for pods in namespace
+func (s *SecurityConfig) IsExecDenied(namespace string) bool {
+	for _, denied := range s.noExecNamespaces {
+		if namespace == denied {
+			return true
+		}
+	}
+	return false
+}
+
+// NoExecNamespaces returns the namespaces whose pods exec and cp may not target
+func (s *SecurityConfig) NoExecNamespaces() []string {
+	return append([]string{}, s.noExecNamespaces...)
+}
+
+// execNamespaces returns the namespaces of the pods an exec or cp command reaches: the
+// --namespace flag, or "default" without one, and for cp any namespace given in a
+// namespace/pod:path argument. Other commands reach none.
+func execNamespaces(command string) []string {
+	parts, err := shlex.Split(command)
+	if err != nil {
+		return nil
+	}
+	if len(parts) > 0 && parts[0] == CommandTypeKubectl {
+		parts = parts[1:]
+	}
+	if len(parts) == 0 || (parts[0] != "exec" && parts[0] != "cp") {
+		return nil
+	}
+
+	namespace := "default"
+	var podNamespaces []string
+	for i := 1; i < len(parts); i++ {
+		part := parts[i]
+		if part == "--" {
+			break
+		}
+		switch {
+		case part == "-n" || part == "--namespace":
+			if i+1 < len(parts) {
+				namespace = parts[i+1]
+				i++
+			}
+		case strings.HasPrefix(part, "--namespace="):
+			namespace = strings.TrimPrefix(part, "--namespace=")
+		case strings.HasPrefix(part, "-n="):
+			namespace = strings.TrimPrefix(part, "-n=")
+		case strings.HasPrefix(part, "-n") && len(part) > 2:
+			namespace = part[2:]
+		case parts[0] == "cp" && !strings.HasPrefix(part, "-"):
+			// A remote path is [namespace/]pod:path
+			if remote, _, ok := strings.Cut(part, ":"); ok {
+				if podNamespace, _, ok := strings.Cut(remote, "/"); ok {
+					podNamespaces = append(podNamespaces, podNamespace)
+				}
+			}
+		}
+	}
+	return append(podNamespaces, namespace)
+}
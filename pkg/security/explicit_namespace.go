@@ -0,0 +1,64 @@
+package security
+
+import (
+	"strings"
+
+	"github.com/google/shlex"
+)
+
+// validateExplicitNamespace rejects commands that change namespaced resources without naming
+// their namespace when RequireExplicitNamespace is set, so a missing -n can't fall back to the
+// context's default namespace. Cluster-scoped types are not affected; resource types the
+// catalog doesn't know are treated as namespaced. Resources named only in files (-f) are not
+// checked, since manifests carry their own namespace.
+func (v *Validator) validateExplicitNamespace(command string) error {
+	if !v.secConfig.RequireExplicitNamespace || hasExplicitNamespace(command) {
+		return nil
+	}
+
+	verb, reference := commandTarget(command)
+	if verb == "run" {
+		// run creates a pod named by its first argument
+		reference = "pods"
+	} else if _, ok := scopedVerbs[verb]; !ok {
+		return nil
+	}
+	reference, _, _ = strings.Cut(reference, "/")
+	if reference == "" {
+		return nil
+	}
+
+	for _, resourceType := range strings.Split(reference, ",") {
+		if !namespacedPseudoResources[strings.ToLower(resourceType)] {
+			if resource, ok := v.secConfig.resources.Lookup(resourceType); ok && !resource.Namespaced {
+				continue
+			}
+		}
+		return &ValidationError{
+			Message: "Error: Cannot " + verb + " namespaced resource type '" + resourceType + "' without an explicit namespace (-n), which is required by security configuration",
+		}
+	}
+	return nil
+}
+
+// hasExplicitNamespace reports whether a command names its namespace with -n/--namespace, or
+// all of them with -A/--all-namespaces
+func hasExplicitNamespace(command string) bool {
+	parts, err := shlex.Split(command)
+	if err != nil {
+		return false
+	}
+	for _, part := range parts {
+		switch {
+		case part == "--":
+			return false
+		case part == "-n" || part == "--namespace" || part == "-A" || part == "--all-namespaces":
+			return true
+		case strings.HasPrefix(part, "--namespace=") || strings.HasPrefix(part, "--all-namespaces="):
+			return true
+		case strings.HasPrefix(part, "-n") && len(part) > 2:
+			return true
+		}
+	}
+	return false
+}
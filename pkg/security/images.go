@@ -0,0 +1,113 @@
+package security
+
+import (
+	"strings"
+
+	"github.com/google/shlex"
+)
+
+// SetAllowedImages sets the comma-separated image allow-list. Entries are registries
+// ("registry.example.com"), repository prefixes ("ghcr.io/acme/") or full image names; an
+// empty list allows any image.
+func (s *SecurityConfig) SetAllowedImages(images string) {
+	s.allowedImages = []string{}
+	for _, image := range strings.Split(images, ",") {
+		if image = strings.TrimSpace(image); image != "" {
+			s.allowedImages = append(s.allowedImages, image)
+		}
+	}
+}
+
+// IsImageAllowed checks an image reference against the allow-list. Short Docker Hub names
+// like "nginx" are also matched in their full form, "docker.io/library/nginx".
+func (s *SecurityConfig) IsImageAllowed(image string) bool {
+	if len(s.allowedImages) == 0 {
+		return true
+	}
+
+	candidates := []string{image}
+	if normalized := normalizeImage(image); normalized != image {
+		candidates = append(candidates, normalized)
+	}
+	for _, allowed := range s.allowedImages {
+		for _, candidate := range candidates {
+			if imageMatches(candidate, allowed) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// imageMatches reports whether image is the allowed entry or lies under it. Entries ending in
+// "/" are plain prefixes; other entries only match at a path, tag or digest boundary so that
+// "registry.example.com" doesn't match "registry.example.com.evil.io/app".
+func imageMatches(image, allowed string) bool {
+	if strings.HasSuffix(allowed, "/") {
+		return strings.HasPrefix(image, allowed)
+	}
+	if !strings.HasPrefix(image, allowed) {
+		return false
+	}
+	rest := image[len(allowed):]
+	return rest == "" || strings.ContainsAny(rest[:1], "/:@")
+}
+
+// normalizeImage expands a Docker Hub short name to its fully qualified form
+func normalizeImage(image string) string {
+	first, _, hasPath := strings.Cut(image, "/")
+	if hasPath && (strings.ContainsAny(first, ".:") || first == "localhost") {
+		return image
+	}
+	if !hasPath {
+		return "docker.io/library/" + image
+	}
+	return "docker.io/" + image
+}
+
+// commandImages returns the container images a kubectl command sets inline, from --image
+// flags (run, create deployment/job/cronjob) and the CONTAINER=IMAGE arguments of set image
+func commandImages(command string) []string {
+	parts, err := shlex.Split(command)
+	if err != nil {
+		return nil
+	}
+	if len(parts) > 0 && parts[0] == CommandTypeKubectl {
+		parts = parts[1:]
+	}
+
+	var images []string
+	var positionals []string
+	for i := 0; i < len(parts); i++ {
+		part := parts[i]
+		if part == "--" {
+			break
+		}
+		if value, ok := strings.CutPrefix(part, "--image="); ok {
+			images = append(images, value)
+			continue
+		}
+		if part == "--image" && i+1 < len(parts) {
+			i++
+			images = append(images, parts[i])
+			continue
+		}
+		if strings.HasPrefix(part, "-") {
+			if !strings.Contains(part, "=") && positionalValueFlags[part] {
+				i++
+			}
+			continue
+		}
+		positionals = append(positionals, part)
+	}
+
+	// set image RESOURCE CONTAINER=IMAGE ...
+	if len(positionals) > 2 && positionals[0] == "set" && positionals[1] == "image" {
+		for _, arg := range positionals[2:] {
+			if _, image, ok := strings.Cut(arg, "="); ok {
+				images = append(images, image)
+			}
+		}
+	}
+	return images
+}
@@ -0,0 +1,59 @@
+package security
+
+import "testing"
+
+func TestIsImageAllowed(t *testing.T) {
+	secConfig := NewSecurityConfig()
+	if !secConfig.IsImageAllowed("evil.io/miner") {
+		t.Error("any image should be allowed without an allow-list")
+	}
+
+	secConfig.SetAllowedImages("registry.example.com, ghcr.io/acme/, docker.io/library/nginx")
+	tests := []struct {
+		image   string
+		allowed bool
+	}{
+		{"registry.example.com/team/app:1.2", true},
+		{"registry.example.com:5000/app", true},
+		{"ghcr.io/acme/api@sha256:abc", true},
+		{"nginx:1.25", true},
+		{"docker.io/library/nginx", true},
+		{"registry.example.com.evil.io/app", false},
+		{"ghcr.io/other/api", false},
+		{"nginx-unprivileged", false},
+		{"busybox", false},
+	}
+	for _, tt := range tests {
+		if got := secConfig.IsImageAllowed(tt.image); got != tt.allowed {
+			t.Errorf("IsImageAllowed(%q) = %v, want %v", tt.image, got, tt.allowed)
+		}
+	}
+}
+
+func TestValidatorImageAllowList(t *testing.T) {
+	secConfig := NewSecurityConfig()
+	secConfig.AccessLevel = AccessLevelReadWrite
+	secConfig.SetAllowedImages("registry.example.com")
+	validator := NewValidator(secConfig)
+
+	tests := []struct {
+		command string
+		allowed bool
+	}{
+		{"run web --image=registry.example.com/web:1.0 -n shop", true},
+		{"create deployment web --image registry.example.com/web:1.0", true},
+		{"set image deployment/web web=registry.example.com/web:1.1", true},
+		{"run miner --image=docker.io/evil/miner", false},
+		{"create deployment web --image=quay.io/web:1.0", false},
+		{"set image deployment/web web=registry.example.com/web:1.1 sidecar=busybox", false},
+		{"apply -f deployment.yaml", true},
+	}
+	for _, tt := range tests {
+		err := validator.ValidateCommand(tt.command, CommandTypeKubectl)
+		if tt.allowed && err != nil {
+			t.Errorf("ValidateCommand(%q) should have succeeded, got: %v", tt.command, err)
+		} else if !tt.allowed && err == nil {
+			t.Errorf("ValidateCommand(%q) should have failed", tt.command)
+		}
+	}
+}
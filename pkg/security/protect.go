@@ -0,0 +1,146 @@
+package security
+
+import (
+	"strings"
+
+	"github.com/google/shlex"
+)
+
+// SetProtectedNamespace sets the namespace the server runs in, which delete may not remove.
+// pod, when known, is the server's own pod in podNamespace, which delete may not remove either.
+func (s *SecurityConfig) SetProtectedNamespace(namespace, podNamespace, pod string) {
+	s.protectedNamespace = strings.TrimSpace(namespace)
+	s.protectedPodNamespace = strings.TrimSpace(podNamespace)
+	s.protectedPod = strings.TrimSpace(pod)
+}
+
+// ProtectedNamespace returns the namespace delete may not remove, or "" when there is none
+func (s *SecurityConfig) ProtectedNamespace() string {
+	return s.protectedNamespace
+}
+
+// deleteTargets is the parsed form of a delete command: the resource types and names it
+// names, its namespace, and whether it selects by --all or a label selector
+type deleteTargets struct {
+	references []string
+	namespace  string
+	bulk       bool
+}
+
+// parseDeleteTargets parses a delete command, returning false for any other command
+func parseDeleteTargets(command string) (deleteTargets, bool) {
+	parts, err := shlex.Split(command)
+	if err != nil {
+		return deleteTargets{}, false
+	}
+	if len(parts) > 0 && parts[0] == CommandTypeKubectl {
+		parts = parts[1:]
+	}
+	if len(parts) == 0 || parts[0] != "delete" {
+		return deleteTargets{}, false
+	}
+
+	targets := deleteTargets{namespace: "default"}
+	for i := 1; i < len(parts); i++ {
+		part := parts[i]
+		if part == "--" {
+			break
+		}
+		flag, value, hasValue := strings.Cut(part, "=")
+		switch {
+		case flag == "-n" || flag == "--namespace":
+			if !hasValue && i+1 < len(parts) {
+				value = parts[i+1]
+				i++
+			}
+			targets.namespace = value
+		case strings.HasPrefix(part, "-n") && !strings.HasPrefix(part, "--") && len(part) > 2:
+			targets.namespace = part[2:]
+		case flag == "--all" && value != "false", flag == "-l", flag == "--selector", flag == "--field-selector":
+			targets.bulk = true
+			if !hasValue && flag != "--all" && positionalValueFlags[flag] {
+				i++
+			}
+		case strings.HasPrefix(part, "-"):
+			if !hasValue && positionalValueFlags[part] {
+				i++
+			}
+		default:
+			targets.references = append(targets.references, part)
+		}
+	}
+	return targets, true
+}
+
+// protectedDeletion returns what a delete command would remove of the server itself, or ""
+// when it leaves the server alone: its namespace, named directly or caught by --all or a
+// selector, or its own pod
+func (s *SecurityConfig) protectedDeletion(command string) string {
+	if s.protectedNamespace == "" && s.protectedPod == "" {
+		return ""
+	}
+	targets, ok := parseDeleteTargets(command)
+	if !ok || len(targets.references) == 0 {
+		return ""
+	}
+
+	// The first reference is a type ("pods", "ns,pods") followed by names, or every
+	// reference is type/name
+	var resourceTypes, names []string
+	if !strings.Contains(targets.references[0], "/") {
+		resourceTypes = strings.Split(targets.references[0], ",")
+		names = targets.references[1:]
+	}
+	for _, reference := range targets.references {
+		if resourceType, name, ok := strings.Cut(reference, "/"); ok {
+			if s.deletionHits(resourceType, []string{name}, targets) {
+				return reference
+			}
+		}
+	}
+	for _, resourceType := range resourceTypes {
+		if s.deletionHits(resourceType, names, targets) {
+			if len(names) == 0 {
+				return resourceType + " selected by --all or a selector"
+			}
+			return resourceType + " " + strings.Join(names, " ")
+		}
+	}
+	return ""
+}
+
+// deletionHits reports whether deleting names of resourceType (or, with none, a bulk
+// selection) removes the protected namespace or pod
+func (s *SecurityConfig) deletionHits(resourceType string, names []string, targets deleteTargets) bool {
+	resource, ok := s.resources.Lookup(resourceType)
+	if !ok {
+		return false
+	}
+	switch resource.Name {
+	case "namespaces":
+		if s.protectedNamespace == "" {
+			return false
+		}
+		if len(names) == 0 {
+			return targets.bulk
+		}
+		for _, name := range names {
+			if name == s.protectedNamespace {
+				return true
+			}
+		}
+	case "pods":
+		if s.protectedPod == "" || targets.namespace != s.protectedPodNamespace {
+			return false
+		}
+		if len(names) == 0 {
+			return targets.bulk
+		}
+		for _, name := range names {
+			if name == s.protectedPod {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,57 @@
+package security
+
+import (
+	"sync"
+	"time"
+)
+
+// rateLimiter gives each user a token bucket refilled at perMinute calls a minute and holding
+// at most perMinute tokens, so a user can burst up to a minute's allowance
+type rateLimiter struct {
+	mu        sync.Mutex
+	perMinute int
+	buckets   map[string]*rateBucket
+	now       func() time.Time
+}
+
+// rateBucket is one user's remaining calls as of last
+type rateBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// SetRateLimit limits each user to perMinute tool calls a minute; zero removes the limit
+func (s *SecurityConfig) SetRateLimit(perMinute int) {
+	if perMinute <= 0 {
+		s.rateLimit = nil
+		return
+	}
+	s.rateLimit = &rateLimiter{perMinute: perMinute, buckets: make(map[string]*rateBucket), now: time.Now}
+}
+
+// AllowCall takes one call from user's rate limit bucket, reporting false when it is empty
+func (s *SecurityConfig) AllowCall(user string) bool {
+	if s == nil || s.rateLimit == nil {
+		return true
+	}
+	return s.rateLimit.allow(user)
+}
+
+func (l *rateLimiter) allow(user string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	bucket, ok := l.buckets[user]
+	if !ok {
+		bucket = &rateBucket{tokens: float64(l.perMinute), last: now}
+		l.buckets[user] = bucket
+	}
+	bucket.tokens = min(float64(l.perMinute), bucket.tokens+now.Sub(bucket.last).Minutes()*float64(l.perMinute))
+	bucket.last = now
+	if bucket.tokens < 1 {
+		return false
+	}
+	bucket.tokens--
+	return true
+}
@@ -0,0 +1,37 @@
+package security
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRateLimitPerUser(t *testing.T) {
+	secConfig := NewSecurityConfig()
+	if !secConfig.AllowCall("alice") {
+		t.Fatal("calls should be allowed without a rate limit")
+	}
+
+	secConfig.SetRateLimit(2)
+	now := time.Now()
+	secConfig.rateLimit.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		if !secConfig.AllowCall("alice") {
+			t.Fatalf("call %d for alice should be within the limit", i+1)
+		}
+	}
+	if secConfig.AllowCall("alice") {
+		t.Error("third call for alice should be rate limited")
+	}
+
+	// Each user has their own bucket
+	if !secConfig.AllowCall("bob") || !secConfig.AllowCall("bob") {
+		t.Error("bob should not be limited by alice's calls")
+	}
+
+	// The bucket refills over time
+	now = now.Add(30 * time.Second)
+	if !secConfig.AllowCall("alice") {
+		t.Error("alice should get a call back after half a minute")
+	}
+}
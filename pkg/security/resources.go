@@ -0,0 +1,283 @@
+package security
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/google/shlex"
+)
+
+// APIResource is a resource type served by the cluster, as listed by `kubectl api-resources`
+type APIResource struct {
+	Name       string
+	ShortNames []string
+	Group      string
+	Version    string
+	Namespaced bool
+	Kind       string
+}
+
+// builtinResources are the core resource types known before discovery has run
+var builtinResources = []APIResource{
+	{Name: "pods", ShortNames: []string{"po"}, Version: "v1", Namespaced: true, Kind: "Pod"},
+	{Name: "services", ShortNames: []string{"svc"}, Version: "v1", Namespaced: true, Kind: "Service"},
+	{Name: "configmaps", ShortNames: []string{"cm"}, Version: "v1", Namespaced: true, Kind: "ConfigMap"},
+	{Name: "secrets", Version: "v1", Namespaced: true, Kind: "Secret"},
+	{Name: "serviceaccounts", ShortNames: []string{"sa"}, Version: "v1", Namespaced: true, Kind: "ServiceAccount"},
+	{Name: "persistentvolumeclaims", ShortNames: []string{"pvc"}, Version: "v1", Namespaced: true, Kind: "PersistentVolumeClaim"},
+	{Name: "endpoints", ShortNames: []string{"ep"}, Version: "v1", Namespaced: true, Kind: "Endpoints"},
+	{Name: "events", ShortNames: []string{"ev"}, Version: "v1", Namespaced: true, Kind: "Event"},
+	{Name: "resourcequotas", ShortNames: []string{"quota"}, Version: "v1", Namespaced: true, Kind: "ResourceQuota"},
+	{Name: "limitranges", ShortNames: []string{"limits"}, Version: "v1", Namespaced: true, Kind: "LimitRange"},
+	{Name: "deployments", ShortNames: []string{"deploy"}, Group: "apps", Version: "v1", Namespaced: true, Kind: "Deployment"},
+	{Name: "replicasets", ShortNames: []string{"rs"}, Group: "apps", Version: "v1", Namespaced: true, Kind: "ReplicaSet"},
+	{Name: "statefulsets", ShortNames: []string{"sts"}, Group: "apps", Version: "v1", Namespaced: true, Kind: "StatefulSet"},
+	{Name: "daemonsets", ShortNames: []string{"ds"}, Group: "apps", Version: "v1", Namespaced: true, Kind: "DaemonSet"},
+	{Name: "jobs", Group: "batch", Version: "v1", Namespaced: true, Kind: "Job"},
+	{Name: "cronjobs", ShortNames: []string{"cj"}, Group: "batch", Version: "v1", Namespaced: true, Kind: "CronJob"},
+	{Name: "horizontalpodautoscalers", ShortNames: []string{"hpa"}, Group: "autoscaling", Version: "v2", Namespaced: true, Kind: "HorizontalPodAutoscaler"},
+	{Name: "ingresses", ShortNames: []string{"ing"}, Group: "networking.k8s.io", Version: "v1", Namespaced: true, Kind: "Ingress"},
+	{Name: "networkpolicies", ShortNames: []string{"netpol"}, Group: "networking.k8s.io", Version: "v1", Namespaced: true, Kind: "NetworkPolicy"},
+	{Name: "poddisruptionbudgets", ShortNames: []string{"pdb"}, Group: "policy", Version: "v1", Namespaced: true, Kind: "PodDisruptionBudget"},
+	{Name: "roles", Group: "rbac.authorization.k8s.io", Version: "v1", Namespaced: true, Kind: "Role"},
+	{Name: "rolebindings", Group: "rbac.authorization.k8s.io", Version: "v1", Namespaced: true, Kind: "RoleBinding"},
+	{Name: "namespaces", ShortNames: []string{"ns"}, Version: "v1", Kind: "Namespace"},
+	{Name: "nodes", ShortNames: []string{"no"}, Version: "v1", Kind: "Node"},
+	{Name: "persistentvolumes", ShortNames: []string{"pv"}, Version: "v1", Kind: "PersistentVolume"},
+	{Name: "clusterroles", Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRole"},
+	{Name: "clusterrolebindings", Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRoleBinding"},
+	{Name: "storageclasses", ShortNames: []string{"sc"}, Group: "storage.k8s.io", Version: "v1", Kind: "StorageClass"},
+	{Name: "customresourcedefinitions", ShortNames: []string{"crd", "crds"}, Group: "apiextensions.k8s.io", Version: "v1", Kind: "CustomResourceDefinition"},
+	{Name: "priorityclasses", ShortNames: []string{"pc"}, Group: "scheduling.k8s.io", Version: "v1", Kind: "PriorityClass"},
+	{Name: "validatingwebhookconfigurations", Group: "admissionregistration.k8s.io", Version: "v1", Kind: "ValidatingWebhookConfiguration"},
+	{Name: "mutatingwebhookconfigurations", Group: "admissionregistration.k8s.io", Version: "v1", Kind: "MutatingWebhookConfiguration"},
+}
+
+// builtinIndex indexes builtinResources for catalogs that haven't been set up
+var builtinIndex = indexResources(builtinResources)
+
+// ResourceCatalog resolves resource names, short names, kinds and group-qualified names to
+// the resource types the cluster serves. It starts with the built-in types and is replaced
+// by the results of discovery, which also covers custom resources.
+type ResourceCatalog struct {
+	mu         sync.RWMutex
+	byName     map[string]APIResource
+	discovered bool
+}
+
+// NewResourceCatalog creates a catalog holding the built-in resource types
+func NewResourceCatalog() *ResourceCatalog {
+	return &ResourceCatalog{byName: indexResources(builtinResources)}
+}
+
+// Replace swaps the catalog contents for the resource types found by discovery. Built-in
+// types missing from the list are kept so a partial discovery can't make them unknown.
+func (c *ResourceCatalog) Replace(resources []APIResource) {
+	index := indexResources(builtinResources)
+	for key, resource := range indexResources(resources) {
+		index[key] = resource
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.byName = index
+	c.discovered = true
+}
+
+// Discovered reports whether the catalog has been populated from the cluster
+func (c *ResourceCatalog) Discovered() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.discovered
+}
+
+// Lookup finds a resource type from a command-line reference such as "deploy",
+// "deployments.apps", "Deployment" or "pod/web". A nil catalog knows the built-in types.
+func (c *ResourceCatalog) Lookup(reference string) (APIResource, bool) {
+	name := strings.ToLower(reference)
+	if before, _, ok := strings.Cut(name, "/"); ok {
+		name = before
+	}
+
+	index := builtinIndex
+	if c != nil {
+		c.mu.RLock()
+		defer c.mu.RUnlock()
+		index = c.byName
+	}
+
+	if resource, ok := index[name]; ok {
+		return resource, true
+	}
+	// A group-qualified name whose group wasn't indexed, e.g. "deployments.extensions"
+	if before, _, ok := strings.Cut(name, "."); ok {
+		resource, ok := index[before]
+		return resource, ok
+	}
+	return APIResource{}, false
+}
+
+// indexResources keys resources by lowercased name, singular kind, short names and
+// name.group; the first resource listed wins for names served by several groups
+func indexResources(resources []APIResource) map[string]APIResource {
+	index := make(map[string]APIResource)
+	add := func(key string, resource APIResource) {
+		if _, ok := index[key]; !ok && key != "" {
+			index[key] = resource
+		}
+	}
+
+	for _, resource := range resources {
+		name := strings.ToLower(resource.Name)
+		if resource.Group != "" {
+			index[name+"."+resource.Group] = resource
+		}
+		add(name, resource)
+		add(strings.ToLower(resource.Kind), resource)
+		for _, short := range resource.ShortNames {
+			add(strings.ToLower(short), resource)
+		}
+	}
+	return index
+}
+
+// ParseAPIResources parses the table printed by `kubectl api-resources`. Columns are located
+// by the header because the SHORTNAMES column is blank for many resources.
+func ParseAPIResources(output string) ([]APIResource, error) {
+	lines := strings.Split(strings.TrimRight(output, "\n"), "\n")
+	if len(lines) == 0 {
+		return nil, fmt.Errorf("empty api-resources output")
+	}
+
+	header := lines[0]
+	columns := []string{"NAME", "SHORTNAMES", "APIVERSION", "NAMESPACED", "KIND"}
+	starts := make([]int, len(columns))
+	for i, column := range columns {
+		starts[i] = strings.Index(header, column)
+		if starts[i] < 0 {
+			return nil, fmt.Errorf("unexpected api-resources header: %q", header)
+		}
+	}
+
+	field := func(line string, i int) string {
+		if starts[i] >= len(line) {
+			return ""
+		}
+		end := len(line)
+		if i+1 < len(starts) && starts[i+1] < end {
+			end = starts[i+1]
+		}
+		return strings.TrimSpace(line[starts[i]:end])
+	}
+
+	var resources []APIResource
+	for _, line := range lines[1:] {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		resource := APIResource{
+			Name:       field(line, 0),
+			Namespaced: field(line, 3) == "true",
+			Kind:       field(line, 4),
+		}
+		if shortNames := field(line, 1); shortNames != "" {
+			resource.ShortNames = strings.Split(shortNames, ",")
+		}
+		if group, version, ok := strings.Cut(field(line, 2), "/"); ok {
+			resource.Group, resource.Version = group, version
+		} else {
+			resource.Version = group
+		}
+		if resource.Name == "" || resource.Kind == "" {
+			return nil, fmt.Errorf("malformed api-resources line: %q", line)
+		}
+		resources = append(resources, resource)
+	}
+	return resources, nil
+}
+
+// scopedVerbs are the mutating kubectl verbs whose first argument is a resource type. Verbs
+// with a subcommand, like "rollout restart deployment/web", name the type after it.
+var scopedVerbs = map[string]int{
+	"create":    0,
+	"delete":    0,
+	"patch":     0,
+	"replace":   0,
+	"label":     0,
+	"annotate":  0,
+	"edit":      0,
+	"scale":     0,
+	"autoscale": 0,
+	"expose":    0,
+	"taint":     0,
+	"rollout":   1,
+	"set":       1,
+}
+
+// positionalValueFlags are the flags whose value is a separate argument, so it isn't
+// mistaken for a resource type
+var positionalValueFlags = map[string]bool{
+	"-n": true, "--namespace": true, "-l": true, "--selector": true, "-o": true, "--output": true,
+	"-f": true, "--filename": true, "-k": true, "--kustomize": true, "-p": true, "--patch": true,
+	"-c": true, "--container": true, "--field-selector": true, "--type": true, "--context": true,
+	"--as": true, "--as-group": true, "--image": true, "--replicas": true,
+}
+
+// readVerbs are the read-only kubectl verbs whose first argument is a resource type
+var readVerbs = map[string]int{
+	"get":      0,
+	"describe": 0,
+	"explain":  0,
+	"top":      0,
+}
+
+// commandResourceType returns the resource type a mutating command acts on, or "" when it has
+// none on the command line (e.g. file-based operations)
+func commandResourceType(command string) string {
+	verb, resourceType := commandTarget(command)
+	if _, ok := scopedVerbs[verb]; !ok {
+		return ""
+	}
+	return resourceType
+}
+
+// commandTarget returns the verb of a kubectl command and the resource type it names, which is
+// "" for verbs that take no resource type and for file-based operations
+func commandTarget(command string) (string, string) {
+	parts, err := shlex.Split(command)
+	if err != nil {
+		return "", ""
+	}
+
+	var positionals []string
+	for i := 0; i < len(parts); i++ {
+		part := parts[i]
+		if part == "--" {
+			break
+		}
+		if strings.HasPrefix(part, "-") {
+			if !strings.Contains(part, "=") && positionalValueFlags[part] {
+				i++
+			}
+			continue
+		}
+		positionals = append(positionals, part)
+	}
+	if len(positionals) > 0 && positionals[0] == CommandTypeKubectl {
+		positionals = positionals[1:]
+	}
+	if len(positionals) == 0 {
+		return "", ""
+	}
+
+	verb := positionals[0]
+	skip, ok := scopedVerbs[verb]
+	if !ok {
+		skip, ok = readVerbs[verb]
+	}
+	if !ok || len(positionals) < 2+skip {
+		return verb, ""
+	}
+	return verb, positionals[1+skip]
+}
@@ -0,0 +1,112 @@
+package security
+
+import (
+	"strings"
+	"testing"
+)
+
+const sampleAPIResources = `NAME                     SHORTNAMES   APIVERSION                NAMESPACED   KIND
+pods                     po           v1                        true         Pod
+namespaces               ns           v1                        false        Namespace
+deployments              deploy       apps/v1                   true         Deployment
+certificates             cert,certs   cert-manager.io/v1        true         Certificate
+clusterissuers                        cert-manager.io/v1        false        ClusterIssuer
+`
+
+func TestParseAPIResources(t *testing.T) {
+	resources, err := ParseAPIResources(sampleAPIResources)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(resources) != 5 {
+		t.Fatalf("expected 5 resources, got %d", len(resources))
+	}
+
+	cert := resources[3]
+	if cert.Name != "certificates" || cert.Group != "cert-manager.io" || cert.Version != "v1" || !cert.Namespaced ||
+		cert.Kind != "Certificate" || strings.Join(cert.ShortNames, ",") != "cert,certs" {
+		t.Errorf("unexpected certificate resource: %+v", cert)
+	}
+	if pods := resources[0]; pods.Group != "" || pods.Version != "v1" {
+		t.Errorf("unexpected pod resource: %+v", pods)
+	}
+	issuer := resources[4]
+	if issuer.Name != "clusterissuers" || issuer.Namespaced || len(issuer.ShortNames) != 0 {
+		t.Errorf("unexpected clusterissuer resource: %+v", issuer)
+	}
+
+	if _, err := ParseAPIResources("error: the server could not find the requested resource"); err == nil {
+		t.Error("expected error for output without a header")
+	}
+}
+
+func TestValidatorResourceScope(t *testing.T) {
+	secConfig := NewSecurityConfig()
+	secConfig.AccessLevel = AccessLevelAdmin
+	secConfig.SetAllowedNamespaces("shop")
+	validator := NewValidator(secConfig)
+
+	check := func(command string, allowed bool) {
+		t.Helper()
+		err := validator.ValidateCommand(command, CommandTypeKubectl)
+		if allowed && err != nil {
+			t.Errorf("ValidateCommand(%q) should have succeeded, got: %v", command, err)
+		} else if !allowed && err == nil {
+			t.Errorf("ValidateCommand(%q) should have failed", command)
+		}
+	}
+
+	// Built-in types are classified before discovery; unknown types are treated as cluster-scoped
+	check("delete pods web -n shop", true)
+	check("rollout restart deployment/web -n shop", true)
+	check("delete namespace shop", false)
+	check("get namespaces", true)
+	check("delete certificates web -n shop", false)
+
+	secConfig.Resources().Replace(mustParseAPIResources(t, sampleAPIResources))
+	check("delete certificates web -n shop", true)
+	check("delete cert/web -n shop", true)
+	check("delete certificates.cert-manager.io web -n shop", true)
+	check("delete clusterissuers letsencrypt", false)
+	check("get clusterissuers", true)
+
+	// Without namespace restrictions the scope doesn't matter
+	secConfig.SetAllowedNamespaces("")
+	if secConfig.HasNamespaceRestrictions() {
+		t.Error("expected no namespace restrictions after clearing the allow-list")
+	}
+	check("delete clusterissuers letsencrypt", true)
+}
+
+func mustParseAPIResources(t *testing.T, output string) []APIResource {
+	t.Helper()
+	resources, err := ParseAPIResources(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resources
+}
+
+func TestValidatorDeniedResources(t *testing.T) {
+	secConfig := NewSecurityConfig()
+	secConfig.AccessLevel = AccessLevelAdmin
+	secConfig.SetDeniedResources("secrets, ClusterRoleBinding")
+	validator := NewValidator(secConfig)
+
+	tests := map[string]bool{
+		"create secret generic db --from-literal=a=b": false,
+		"delete secrets db -n shop":                   false,
+		"delete clusterrolebinding admins":            false,
+		"get secrets -n shop":                         true,
+		"create configmap app --from-literal=a=b":     true,
+		"delete pods,secrets -l app=web":              false,
+	}
+	for command, allowed := range tests {
+		err := validator.ValidateCommand(command, CommandTypeKubectl)
+		if allowed && err != nil {
+			t.Errorf("ValidateCommand(%q) should have succeeded, got: %v", command, err)
+		} else if !allowed && err == nil {
+			t.Errorf("ValidateCommand(%q) should have failed", command)
+		}
+	}
+}
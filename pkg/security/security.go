@@ -1,6 +1,7 @@
 package security
 
 import (
+	"fmt"
 	"regexp"
 	"strings"
 )
@@ -22,6 +23,44 @@ type SecurityConfig struct {
 	allowedNamespaces []string
 	// allowedNamespacesRe is a list of compiled regex patterns for namespace matching
 	allowedNamespacesRe []*regexp.Regexp
+	// denyUnlistedNamespaces makes an empty namespace allow-list deny every namespace
+	denyUnlistedNamespaces bool
+	// RequireAdminConfirm requires admin operations to present the current confirmation token
+	RequireAdminConfirm bool
+	// RequireExplicitNamespace refuses changes to namespaced resources that don't name a namespace
+	RequireExplicitNamespace bool
+	// DisallowInsecureTLS refuses commands that pass --insecure-skip-tls-verify
+	DisallowInsecureTLS bool
+	// adminConfirm holds the rotating confirmation token for admin operations, shared by the
+	// copies WithAccessLevel makes
+	adminConfirm *confirmationToken
+	// redactPatterns are applied to all command output before it is returned
+	redactPatterns []*regexp.Regexp
+	// resources classifies resource types as namespaced or cluster-scoped
+	resources *ResourceCatalog
+	// allowedImages restricts the container images commands may set (empty allows all)
+	allowedImages []string
+	// allowedServers are the API server URLs commands may target with --server (empty allows none)
+	allowedServers []string
+	// allowedTenants are the tenants tool calls may be routed to (empty allows none)
+	allowedTenants []string
+	// deniedResources are resource types commands may not create or change (empty denies none)
+	deniedResources []string
+	// deniedAPIGroups are API groups commands may not touch, "" being the core group (empty denies none)
+	deniedAPIGroups []string
+	// noExecNamespaces are the namespaces whose pods exec and cp may not target
+	noExecNamespaces []string
+	// protectedNamespace is the namespace the server runs in, which delete may not remove
+	protectedNamespace string
+	// protectedPodNamespace and protectedPod identify the server's own pod, when known
+	protectedPodNamespace string
+	protectedPod          string
+	// resourceVerbs maps resource types to the kubectl verbs allowed on them
+	resourceVerbs map[string][]string
+	// rateLimit limits tool calls per user (nil means unlimited)
+	rateLimit *rateLimiter
+	// sessionLimit limits the tool calls running at once per session (nil means unlimited)
+	sessionLimit *sessionLimiter
 }
 
 // NewSecurityConfig creates a new SecurityConfig instance
@@ -30,9 +69,26 @@ func NewSecurityConfig() *SecurityConfig {
 		AccessLevel:         AccessLevelReadOnly,
 		allowedNamespaces:   []string{},
 		allowedNamespacesRe: []*regexp.Regexp{},
+		redactPatterns:      mustCompileRedactPatterns(),
+		resources:           NewResourceCatalog(),
+		DisallowInsecureTLS: true,
+		adminConfirm:        &confirmationToken{},
 	}
 }
 
+// WithAccessLevel returns a copy of the security settings at another access level, sharing
+// everything else, including the rate and session limits and the admin confirmation token
+func (s *SecurityConfig) WithAccessLevel(level AccessLevel) *SecurityConfig {
+	copied := *s
+	copied.AccessLevel = level
+	return &copied
+}
+
+// Resources returns the catalog of resource types used to classify commands
+func (s *SecurityConfig) Resources() *ResourceCatalog {
+	return s.resources
+}
+
 // SetAllowedNamespaces sets the list of allowed namespaces
 func (s *SecurityConfig) SetAllowedNamespaces(namespaces string) {
 	s.allowedNamespaces = []string{}
@@ -42,24 +98,13 @@ func (s *SecurityConfig) SetAllowedNamespaces(namespaces string) {
 		return
 	}
 
-	regexSpecialChars := ".*+?[](){}|^$\\"
-
 	for _, ns := range strings.Split(namespaces, ",") {
 		ns = strings.TrimSpace(ns)
 		if ns == "" {
 			continue
 		}
 
-		// Check if the namespace pattern contains regex special characters
-		isRegex := false
-		for _, char := range ns {
-			if strings.ContainsRune(regexSpecialChars, char) {
-				isRegex = true
-				break
-			}
-		}
-
-		if isRegex {
+		if isNamespacePattern(ns) {
 			// Try to compile the regex pattern
 			re, err := regexp.Compile("^" + ns + "$")
 			if err == nil {
@@ -74,10 +119,55 @@ func (s *SecurityConfig) SetAllowedNamespaces(namespaces string) {
 	}
 }
 
+// ValidateNamespacePatterns reports namespace entries that look like regex patterns but don't
+// compile. SetAllowedNamespaces treats such entries as literal names.
+func ValidateNamespacePatterns(namespaces string) error {
+	var invalid []string
+	for _, ns := range strings.Split(namespaces, ",") {
+		ns = strings.TrimSpace(ns)
+		if ns == "" || !isNamespacePattern(ns) {
+			continue
+		}
+		if _, err := regexp.Compile("^" + ns + "$"); err != nil {
+			invalid = append(invalid, fmt.Sprintf("'%s': %v", ns, err))
+		}
+	}
+
+	if len(invalid) > 0 {
+		return fmt.Errorf("invalid namespace pattern %s", strings.Join(invalid, "; "))
+	}
+	return nil
+}
+
+// isNamespacePattern reports whether a namespace entry contains regex special characters
+func isNamespacePattern(ns string) bool {
+	return strings.ContainsAny(ns, ".*+?[](){}|^$\\")
+}
+
+// AllowedNamespaces returns the namespace allow-list as configured, names and patterns alike
+func (s *SecurityConfig) AllowedNamespaces() []string {
+	namespaces := append([]string{}, s.allowedNamespaces...)
+	for _, pattern := range s.allowedNamespacesRe {
+		namespaces = append(namespaces, strings.TrimSuffix(strings.TrimPrefix(pattern.String(), "^"), "$"))
+	}
+	return namespaces
+}
+
+// SetDenyUnlistedNamespaces sets whether an empty namespace allow-list denies every namespace
+// rather than allowing them all
+func (s *SecurityConfig) SetDenyUnlistedNamespaces(deny bool) {
+	s.denyUnlistedNamespaces = deny
+}
+
+// HasNamespaceRestrictions reports whether --allow-namespaces limits the namespaces that can be accessed
+func (s *SecurityConfig) HasNamespaceRestrictions() bool {
+	return len(s.allowedNamespaces) > 0 || len(s.allowedNamespacesRe) > 0 || s.denyUnlistedNamespaces
+}
+
 // IsNamespaceAllowed checks if a namespace is allowed to be accessed
 func (s *SecurityConfig) IsNamespaceAllowed(namespace string) bool {
 	// If no restrictions are defined, allow all namespaces
-	if len(s.allowedNamespaces) == 0 && len(s.allowedNamespacesRe) == 0 {
+	if !s.HasNamespaceRestrictions() {
 		return true
 	}
 
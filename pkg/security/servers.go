@@ -0,0 +1,65 @@
+package security
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/google/shlex"
+)
+
+// SetAllowedServers sets the comma-separated list of API server URLs that commands may target
+// with --server. Without a list no server override is allowed.
+func (s *SecurityConfig) SetAllowedServers(servers string) {
+	s.allowedServers = []string{}
+	for _, server := range strings.Split(servers, ",") {
+		if server = strings.TrimSpace(server); server != "" {
+			s.allowedServers = append(s.allowedServers, normalizeServer(server))
+		}
+	}
+}
+
+// IsServerAllowed checks an API server URL against the allow-list. URLs are compared by
+// scheme, host, port and path, ignoring case in the scheme and host and a trailing slash.
+func (s *SecurityConfig) IsServerAllowed(server string) bool {
+	server = normalizeServer(server)
+	for _, allowed := range s.allowedServers {
+		if server == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeServer puts an API server URL in the form used for comparison
+func normalizeServer(server string) string {
+	u, err := url.Parse(strings.TrimSpace(server))
+	if err != nil || u.Host == "" {
+		return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(server)), "/")
+	}
+	return strings.ToLower(u.Scheme) + "://" + strings.ToLower(u.Host) + strings.TrimSuffix(u.Path, "/")
+}
+
+// commandServers returns the API server URLs a kubectl command overrides with --server or -s
+func commandServers(command string) []string {
+	parts, err := shlex.Split(command)
+	if err != nil {
+		return nil
+	}
+
+	var servers []string
+	for i := 0; i < len(parts); i++ {
+		part := parts[i]
+		if part == "--" {
+			break
+		}
+		for _, name := range []string{"--server", "-s"} {
+			if value, ok := strings.CutPrefix(part, name+"="); ok {
+				servers = append(servers, value)
+			} else if part == name && i+1 < len(parts) {
+				i++
+				servers = append(servers, parts[i])
+			}
+		}
+	}
+	return servers
+}
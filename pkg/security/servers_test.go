@@ -0,0 +1,54 @@
+package security
+
+import "testing"
+
+func TestIsServerAllowed(t *testing.T) {
+	secConfig := NewSecurityConfig()
+	if secConfig.IsServerAllowed("https://api.example.com:6443") {
+		t.Error("no server should be allowed without an allow-list")
+	}
+
+	secConfig.SetAllowedServers("https://api.example.com:6443, https://Gateway.example.com/k8s/prod/")
+	tests := []struct {
+		server  string
+		allowed bool
+	}{
+		{"https://api.example.com:6443", true},
+		{"https://API.example.com:6443/", true},
+		{"https://gateway.example.com/k8s/prod", true},
+		{"https://api.example.com", false},
+		{"http://api.example.com:6443", false},
+		{"https://api.example.com:6443.evil.io", false},
+		{"https://gateway.example.com/k8s/staging", false},
+	}
+	for _, tt := range tests {
+		if got := secConfig.IsServerAllowed(tt.server); got != tt.allowed {
+			t.Errorf("IsServerAllowed(%q) = %v, want %v", tt.server, got, tt.allowed)
+		}
+	}
+}
+
+func TestValidatorServerAllowList(t *testing.T) {
+	secConfig := NewSecurityConfig()
+	secConfig.SetAllowedServers("https://api.example.com:6443")
+	validator := NewValidator(secConfig)
+
+	tests := []struct {
+		command string
+		allowed bool
+	}{
+		{"get pods -n shop --server=https://api.example.com:6443", true},
+		{"get pods -s https://api.example.com:6443", true},
+		{"get pods --server https://other.example.com", false},
+		{"get pods -s=https://other.example.com", false},
+		{"get pods -n shop", true},
+	}
+	for _, tt := range tests {
+		err := validator.ValidateCommand(tt.command, CommandTypeKubectl)
+		if tt.allowed && err != nil {
+			t.Errorf("ValidateCommand(%q) should have succeeded, got: %v", tt.command, err)
+		} else if !tt.allowed && err == nil {
+			t.Errorf("ValidateCommand(%q) should have failed", tt.command)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package security
+
+import "sync"
+
+// sessionLimiter caps the tool calls each MCP session may have running at once
+type sessionLimiter struct {
+	mu      sync.Mutex
+	max     int
+	running map[string]int
+}
+
+// SetSessionConcurrency limits each session to max tool calls running at once; zero removes
+// the limit
+func (s *SecurityConfig) SetSessionConcurrency(max int) {
+	if max <= 0 {
+		s.sessionLimit = nil
+		return
+	}
+	s.sessionLimit = &sessionLimiter{max: max, running: make(map[string]int)}
+}
+
+// StartSessionCall counts a call against session's concurrency limit, reporting false when the
+// session already has the maximum running. A started call must be ended with release.
+func (s *SecurityConfig) StartSessionCall(session string) (release func(), ok bool) {
+	if s == nil || s.sessionLimit == nil {
+		return func() {}, true
+	}
+	return s.sessionLimit.start(session)
+}
+
+// SessionConcurrency returns the limit on calls running at once in a session (0 means none)
+func (s *SecurityConfig) SessionConcurrency() int {
+	if s == nil || s.sessionLimit == nil {
+		return 0
+	}
+	return s.sessionLimit.max
+}
+
+func (l *sessionLimiter) start(session string) (func(), bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.running[session] >= l.max {
+		return nil, false
+	}
+	l.running[session]++
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			if l.running[session]--; l.running[session] <= 0 {
+				delete(l.running, session)
+			}
+		})
+	}, true
+}
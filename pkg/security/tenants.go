@@ -0,0 +1,24 @@
+package security
+
+import "strings"
+
+// SetAllowedTenants sets the comma-separated list of tenants whose remote agents tool calls
+// may be routed to with the tenant parameter. Without a list no tenant is allowed.
+func (s *SecurityConfig) SetAllowedTenants(tenants string) {
+	s.allowedTenants = []string{}
+	for _, tenant := range strings.Split(tenants, ",") {
+		if tenant = strings.TrimSpace(tenant); tenant != "" {
+			s.allowedTenants = append(s.allowedTenants, tenant)
+		}
+	}
+}
+
+// IsTenantAllowed checks a tenant name against the allow-list. Names are matched exactly.
+func (s *SecurityConfig) IsTenantAllowed(tenant string) bool {
+	for _, allowed := range s.allowedTenants {
+		if tenant == allowed {
+			return true
+		}
+	}
+	return false
+}
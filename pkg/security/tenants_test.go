@@ -0,0 +1,24 @@
+package security
+
+import "testing"
+
+func TestIsTenantAllowed(t *testing.T) {
+	secConfig := NewSecurityConfig()
+	if secConfig.IsTenantAllowed("acme") {
+		t.Error("no tenant should be allowed without an allow-list")
+	}
+
+	secConfig.SetAllowedTenants("acme, globex")
+	tests := map[string]bool{
+		"acme":   true,
+		"globex": true,
+		"ACME":   false,
+		"acme2":  false,
+		"":       false,
+	}
+	for tenant, allowed := range tests {
+		if got := secConfig.IsTenantAllowed(tenant); got != allowed {
+			t.Errorf("IsTenantAllowed(%q) = %v, want %v", tenant, got, allowed)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package security
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/google/shlex"
+)
+
+// insecureTLSFlag turns off verification of the API server's certificate
+const insecureTLSFlag = "--insecure-skip-tls-verify"
+
+// validateInsecureTLS rejects commands that turn off TLS verification of the API server when
+// DisallowInsecureTLS is set. --insecure-skip-tls-verify=false is allowed, since it only
+// restates the default.
+func (v *Validator) validateInsecureTLS(command string) error {
+	if !v.secConfig.DisallowInsecureTLS || !skipsTLSVerify(command) {
+		return nil
+	}
+	return &ValidationError{
+		Message: "Error: Cannot use " + insecureTLSFlag + ", which turns off verification of the API server's certificate and is disallowed by security configuration",
+	}
+}
+
+// skipsTLSVerify reports whether a command sets --insecure-skip-tls-verify to anything but false
+func skipsTLSVerify(command string) bool {
+	parts, err := shlex.Split(command)
+	if err != nil {
+		return strings.Contains(command, insecureTLSFlag)
+	}
+	for _, part := range parts {
+		if part == "--" {
+			return false
+		}
+		if part == insecureTLSFlag {
+			return true
+		}
+		if value, ok := strings.CutPrefix(part, insecureTLSFlag+"="); ok {
+			if skip, err := strconv.ParseBool(value); err != nil || skip {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -21,6 +21,11 @@ var (
 		"completion", "help", "kustomize", "options", "plugin", "proxy", "wait", "events",
 	}
 
+	// KubectlReadSubcommands defines subcommands of read-write operations that don't modify state
+	KubectlReadSubcommands = map[string][]string{
+		"rollout": {"status", "history"},
+	}
+
 	// KubectlReadWriteOperations defines kubectl operations that modify state but are not admin operations
 	KubectlReadWriteOperations = []string{
 		"create", "delete", "apply", "expose", "run", "set", "rollout", "scale",
@@ -32,10 +37,15 @@ var (
 		"cordon", "uncordon", "drain", "taint", "certificate",
 	}
 
+	// KubectlAdminSubcommands defines subcommands of read operations that require admin privileges
+	KubectlAdminSubcommands = map[string][]string{
+		"auth": {"reconcile"},
+	}
+
 	// HelmReadOperations defines helm operations that don't modify state
 	HelmReadOperations = []string{
 		"get", "history", "list", "show", "status", "search", "repo",
-		"env", "version", "verify", "completion", "help",
+		"env", "version", "verify", "completion", "help", "template",
 	}
 
 	// CiliumReadOperations defines cilium operations that don't modify state
@@ -144,6 +154,58 @@ func (v *Validator) ValidateCommand(command, commandType string) error {
 		return err
 	}
 
+	if commandType == CommandTypeKubectl {
+		// Only use the verbs the policy allows on each resource type
+		if err := v.validateResourceVerbs(command); err != nil {
+			return err
+		}
+
+		// Keep the server from deleting its own namespace or pod
+		if err := v.validateProtectedDeletion(command); err != nil {
+			return err
+		}
+
+		// Keep exec and cp out of protected namespaces
+		if err := v.validateExecNamespaces(command); err != nil {
+			return err
+		}
+
+		// Make changes to namespaced resources name their namespace
+		if err := v.validateExplicitNamespace(command); err != nil {
+			return err
+		}
+
+		// Keep changes inside the allowed namespaces
+		if err := v.validateResourceScope(command, commandType); err != nil {
+			return err
+		}
+
+		// Leave denied resource types alone
+		if err := v.validateDeniedResources(command); err != nil {
+			return err
+		}
+
+		// Stay out of denied API groups
+		if err := v.validateDeniedAPIGroups(command); err != nil {
+			return err
+		}
+
+		// Only launch images from allowed registries
+		if err := v.validateImages(command); err != nil {
+			return err
+		}
+
+		// Only talk to allowed API servers
+		if err := v.validateServers(command); err != nil {
+			return err
+		}
+
+		// Keep verifying the API server's certificate
+		if err := v.validateInsecureTLS(command); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -154,6 +216,15 @@ func (v *Validator) validateAccessLevel(command, commandType string) error {
 	adminOperations := v.getAdminOperationsList(commandType)
 
 	operation := v.extractOperationFromCommand(command, commandType)
+	if commandType == CommandTypeKubectl && v.isSubcommand(command, operation, KubectlReadSubcommands) {
+		return nil
+	}
+	if commandType == CommandTypeKubectl && v.isSubcommand(command, operation, KubectlAdminSubcommands) {
+		// Check the subcommand as the admin operation it is rather than as its read-only verb
+		readOperations = nil
+		readWriteOperations = nil
+		adminOperations = []string{operation}
+	}
 
 	switch v.secConfig.AccessLevel {
 	case AccessLevelReadOnly:
@@ -188,7 +259,7 @@ func (v *Validator) validateNamespaceScope(command string) error {
 	namespace := v.extractNamespaceFromCommand(command)
 
 	// If command applies to all namespaces, and there are namespace restrictions
-	if namespace == "*" && (len(v.secConfig.allowedNamespaces) > 0 || len(v.secConfig.allowedNamespacesRe) > 0) {
+	if namespace == "*" && v.secConfig.HasNamespaceRestrictions() {
 		return &ValidationError{Message: "Error: Access to all namespaces is restricted by security configuration"}
 	}
 
@@ -204,6 +275,157 @@ func (v *Validator) validateNamespaceScope(command string) error {
 	return nil
 }
 
+// namespacedPseudoResources are command arguments in the resource position that aren't
+// resource types but only act within a namespace
+var namespacedPseudoResources = map[string]bool{
+	"all":   true, // category of namespaced workload types
+	"token": true, // create token SERVICEACCOUNT
+}
+
+// validateResourceScope rejects changes to cluster-scoped resources when namespaces are
+// restricted, since the namespace allow-list can't contain them. Resource types the catalog
+// doesn't know, such as custom resources before discovery has run, are treated as
+// cluster-scoped.
+func (v *Validator) validateResourceScope(command, commandType string) error {
+	if !v.secConfig.HasNamespaceRestrictions() {
+		return nil
+	}
+	if v.isOperationInList(v.extractOperationFromCommand(command, commandType), v.getReadOperationsList(commandType)) {
+		return nil
+	}
+
+	reference := commandResourceType(command)
+	if reference == "" {
+		return nil
+	}
+	if before, _, ok := strings.Cut(reference, "/"); ok {
+		reference = before
+	}
+
+	return v.checkNamespaced(strings.Split(reference, ","))
+}
+
+// ValidateResourceKinds applies the cluster-scope check to resource types named outside the
+// command line, such as the kinds in a manifest passed on stdin: while namespaces are
+// restricted, every type must be namespaced.
+func (v *Validator) ValidateResourceKinds(kinds []string) error {
+	if !v.secConfig.HasNamespaceRestrictions() {
+		return nil
+	}
+	return v.checkNamespaced(kinds)
+}
+
+// checkNamespaced rejects unknown and cluster-scoped resource types
+func (v *Validator) checkNamespaced(resourceTypes []string) error {
+	for _, resourceType := range resourceTypes {
+		if namespacedPseudoResources[strings.ToLower(resourceType)] {
+			continue
+		}
+		resource, ok := v.secConfig.resources.Lookup(resourceType)
+		if !ok {
+			return &ValidationError{
+				Message: "Error: Cannot modify unknown resource type '" + resourceType + "' while namespaces are restricted by security configuration",
+			}
+		}
+		if !resource.Namespaced {
+			return &ValidationError{
+				Message: "Error: Cannot modify cluster-scoped resource type '" + resource.Name + "' while namespaces are restricted by security configuration",
+			}
+		}
+	}
+	return nil
+}
+
+// validateDeniedResources rejects commands that create or change a denied resource type
+func (v *Validator) validateDeniedResources(command string) error {
+	if len(v.secConfig.deniedResources) == 0 {
+		return nil
+	}
+	reference, _, _ := strings.Cut(commandResourceType(command), "/")
+	if reference == "" {
+		return nil
+	}
+	for _, resourceType := range strings.Split(reference, ",") {
+		if v.secConfig.IsResourceDenied(resourceType) {
+			return &ValidationError{
+				Message: "Error: Cannot modify resource type '" + resourceType + "', which is denied by security configuration",
+			}
+		}
+	}
+	return nil
+}
+
+// validateResourceVerbs rejects verbs that the resource verbs policy doesn't allow on the
+// resource types a command names
+func (v *Validator) validateResourceVerbs(command string) error {
+	if len(v.secConfig.resourceVerbs) == 0 {
+		return nil
+	}
+	verb, target := commandTarget(command)
+	reference, _, _ := strings.Cut(target, "/")
+	for _, resourceType := range strings.Split(reference, ",") {
+		if !v.secConfig.IsVerbAllowed(verb, resourceType) {
+			if resourceType == "" {
+				return &ValidationError{
+					Message: "Error: Verb '" + verb + "' is not allowed by security configuration without a resource type",
+				}
+			}
+			return &ValidationError{
+				Message: "Error: Verb '" + verb + "' is not allowed on resource type '" + resourceType + "' by security configuration",
+			}
+		}
+	}
+	return nil
+}
+
+// validateExecNamespaces rejects exec and cp into pods in the namespaces listed in
+// --no-exec-namespaces, whatever the access level
+func (v *Validator) validateExecNamespaces(command string) error {
+	for _, namespace := range execNamespaces(command) {
+		if v.secConfig.IsExecDenied(namespace) {
+			return &ValidationError{
+				Message: "Error: exec and cp into pods in namespace '" + namespace + "' are not allowed by security configuration",
+			}
+		}
+	}
+	return nil
+}
+
+// validateProtectedDeletion rejects deletes that would remove the server's own namespace or
+// pod, whatever the access level
+func (v *Validator) validateProtectedDeletion(command string) error {
+	if target := v.secConfig.protectedDeletion(command); target != "" {
+		return &ValidationError{
+			Message: "Error: Deleting " + target + " would remove this server and is not allowed by security configuration",
+		}
+	}
+	return nil
+}
+
+// validateImages rejects inline container images that aren't on the image allow-list
+func (v *Validator) validateImages(command string) error {
+	for _, image := range commandImages(command) {
+		if !v.secConfig.IsImageAllowed(image) {
+			return &ValidationError{
+				Message: "Error: Image '" + image + "' is not allowed by security configuration",
+			}
+		}
+	}
+	return nil
+}
+
+// validateServers rejects API server overrides that aren't on the server allow-list
+func (v *Validator) validateServers(command string) error {
+	for _, server := range commandServers(command) {
+		if !v.secConfig.IsServerAllowed(server) {
+			return &ValidationError{
+				Message: "Error: API server '" + server + "' is not allowed by security configuration",
+			}
+		}
+	}
+	return nil
+}
+
 // isOperationInList checks if an operation is in the given list
 func (v *Validator) isOperationInList(operation string, allowedOperations []string) bool {
 	for _, allowed := range allowedOperations {
@@ -232,6 +454,22 @@ func (v *Validator) extractOperationFromCommand(command, commandType string) str
 	return operation
 }
 
+// isSubcommand reports whether a kubectl command runs one of the subcommands listed for its
+// operation, such as those in KubectlReadSubcommands or KubectlAdminSubcommands
+func (v *Validator) isSubcommand(command, operation string, listed map[string][]string) bool {
+	subcommands, ok := listed[operation]
+	if !ok {
+		return false
+	}
+	fields := strings.Fields(command)
+	for i, field := range fields {
+		if field == operation {
+			return i+1 < len(fields) && v.isOperationInList(fields[i+1], subcommands)
+		}
+	}
+	return false
+}
+
 // extractNamespaceFromCommand extracts the namespace from a command
 func (v *Validator) extractNamespaceFromCommand(command string) string {
 	// Check for explicit namespace parameter
@@ -19,6 +19,10 @@ func TestValidatorAccessLevels(t *testing.T) {
 		{"ReadOnly - delete pod", AccessLevelReadOnly, "kubectl delete pod mypod", true, "read-only mode"},
 		{"ReadOnly - create deployment", AccessLevelReadOnly, "kubectl create deployment nginx --image=nginx", true, "read-only mode"},
 		{"ReadOnly - cordon node", AccessLevelReadOnly, "kubectl cordon node1", true, "read-only mode"},
+		{"ReadOnly - rollout history", AccessLevelReadOnly, "kubectl rollout history deployment/web", false, ""},
+		{"ReadOnly - rollout undo", AccessLevelReadOnly, "kubectl rollout undo deployment/web", true, "read-only mode"},
+		{"ReadOnly - auth can-i", AccessLevelReadOnly, "kubectl auth can-i create pods", false, ""},
+		{"ReadOnly - auth reconcile", AccessLevelReadOnly, "kubectl auth reconcile -f rbac.yaml", true, "read-only mode"},
 
 		// ReadWrite access level tests
 		{"ReadWrite - get pods", AccessLevelReadWrite, "kubectl get pods", false, ""},
@@ -26,6 +30,7 @@ func TestValidatorAccessLevels(t *testing.T) {
 		{"ReadWrite - create deployment", AccessLevelReadWrite, "kubectl create deployment nginx --image=nginx", false, ""},
 		{"ReadWrite - cordon node", AccessLevelReadWrite, "kubectl cordon node1", true, "admin operations"},
 		{"ReadWrite - drain node", AccessLevelReadWrite, "kubectl drain node1", true, "admin operations"},
+		{"ReadWrite - auth reconcile", AccessLevelReadWrite, "kubectl auth reconcile -f rbac.yaml", true, "admin operations"},
 
 		// Admin access level tests
 		{"Admin - get pods", AccessLevelAdmin, "kubectl get pods", false, ""},
@@ -33,6 +38,7 @@ func TestValidatorAccessLevels(t *testing.T) {
 		{"Admin - create deployment", AccessLevelAdmin, "kubectl create deployment nginx --image=nginx", false, ""},
 		{"Admin - cordon node", AccessLevelAdmin, "kubectl cordon node1", false, ""},
 		{"Admin - drain node", AccessLevelAdmin, "kubectl drain node1", false, ""},
+		{"Admin - auth reconcile", AccessLevelAdmin, "kubectl auth reconcile -f rbac.yaml", false, ""},
 	}
 
 	for _, tc := range tests {
@@ -131,6 +137,7 @@ func TestReadOperationsValidation(t *testing.T) {
 		{"kubectl create namespace test", CommandTypeKubectl, true},
 		{"helm list", CommandTypeHelm, false},
 		{"helm status release", CommandTypeHelm, false},
+		{"helm template web ./chart", CommandTypeHelm, false},
 		{"helm install chart", CommandTypeHelm, true},
 		{"helm uninstall release", CommandTypeHelm, true},
 		{"cilium status", CommandTypeCilium, false},
@@ -197,3 +204,223 @@ func TestValidateCommand(t *testing.T) {
 		})
 	}
 }
+
+func TestValidatorResourceVerbs(t *testing.T) {
+	secConfig := NewSecurityConfig()
+	secConfig.AccessLevel = AccessLevelAdmin
+	if err := secConfig.SetResourceVerbs(map[string][]string{
+		"*":    {"get", "describe"},
+		"pods": {"get", "describe", "delete"},
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	validator := NewValidator(secConfig)
+
+	tests := []struct {
+		command   string
+		shouldErr bool
+	}{
+		{"kubectl get deployments -n shop", false},
+		{"kubectl delete pod web-0 -n shop", false},
+		{"kubectl delete po/web-0 -n shop", false},
+		{"kubectl delete deployment web -n shop", true},
+		{"kubectl delete services,pods -l app=web", true},
+		{"kubectl scale deployment web --replicas=3", true},
+		{"kubectl apply -f manifest.yaml", true},
+	}
+	for _, tc := range tests {
+		err := validator.ValidateCommand(tc.command, CommandTypeKubectl)
+		if tc.shouldErr && err == nil {
+			t.Errorf("ValidateCommand(%q) should have failed", tc.command)
+		}
+		if !tc.shouldErr && err != nil {
+			t.Errorf("ValidateCommand(%q) failed: %v", tc.command, err)
+		}
+	}
+
+	if err := secConfig.SetResourceVerbs(map[string][]string{"pods": {"get", ""}}); err == nil {
+		t.Error("expected an empty verb to be rejected")
+	}
+}
+
+func TestValidatorNoExecNamespaces(t *testing.T) {
+	secConfig := NewSecurityConfig()
+	secConfig.AccessLevel = AccessLevelAdmin
+	secConfig.SetNoExecNamespaces(DefaultNoExecNamespaces)
+	validator := NewValidator(secConfig)
+
+	tests := []struct {
+		command   string
+		shouldErr bool
+	}{
+		{"kubectl exec kube-proxy-x2 -n kube-system -- sh", true},
+		{"kubectl exec kube-proxy-x2 --namespace=kube-system -- sh", true},
+		{"kubectl cp kube-system/kube-proxy-x2:/etc/config /tmp/config", true},
+		{"kubectl cp /tmp/config kube-proxy-x2:/tmp -n kube-system", true},
+		{"kubectl exec web-0 -n team-a -- ls /app", false},
+		{"kubectl cp team-a/web-0:/app/log /tmp/log", false},
+		{"kubectl get pods -n kube-system", false},
+	}
+	for _, tc := range tests {
+		err := validator.ValidateCommand(tc.command, CommandTypeKubectl)
+		if tc.shouldErr && err == nil {
+			t.Errorf("ValidateCommand(%q) should have failed", tc.command)
+		}
+		if !tc.shouldErr && err != nil {
+			t.Errorf("ValidateCommand(%q) failed: %v", tc.command, err)
+		}
+	}
+}
+
+func TestValidatorProtectedNamespace(t *testing.T) {
+	secConfig := NewSecurityConfig()
+	secConfig.AccessLevel = AccessLevelAdmin
+	secConfig.SetProtectedNamespace("mcp", "mcp", "mcp-server-7d9f-abcde")
+	validator := NewValidator(secConfig)
+
+	tests := []struct {
+		command   string
+		shouldErr bool
+	}{
+		{"kubectl delete namespace mcp", true},
+		{"kubectl delete ns team-a mcp", true},
+		{"kubectl delete namespaces/mcp", true},
+		{"kubectl delete ns --all", true},
+		{"kubectl delete ns -l purpose=tools", true},
+		{"kubectl delete pod mcp-server-7d9f-abcde -n mcp", true},
+		{"kubectl delete pods --all --namespace=mcp", true},
+		{"kubectl delete ns team-a", false},
+		{"kubectl delete pod mcp-server-7d9f-abcde -n team-a", false},
+		{"kubectl delete pods --all -n team-a", false},
+		{"kubectl delete configmap mcp -n mcp", false},
+		{"kubectl get namespace mcp", false},
+	}
+	for _, tc := range tests {
+		err := validator.ValidateCommand(tc.command, CommandTypeKubectl)
+		if tc.shouldErr && (err == nil || !strings.Contains(err.Error(), "would remove this server")) {
+			t.Errorf("ValidateCommand(%q) should have been refused, got %v", tc.command, err)
+		}
+		if !tc.shouldErr && err != nil {
+			t.Errorf("ValidateCommand(%q) failed: %v", tc.command, err)
+		}
+	}
+}
+
+func TestValidatorDeniedAPIGroups(t *testing.T) {
+	secConfig := NewSecurityConfig()
+	secConfig.AccessLevel = AccessLevelAdmin
+	secConfig.SetDeniedAPIGroups("rbac.authorization.k8s.io, policy")
+	secConfig.Resources().Replace([]APIResource{
+		{Name: "widgets", ShortNames: []string{"wd"}, Group: "policy", Namespaced: true, Kind: "Widget"},
+	})
+	validator := NewValidator(secConfig)
+
+	tests := []struct {
+		command   string
+		shouldErr bool
+	}{
+		{"kubectl get roles.rbac.authorization.k8s.io -n shop", true},
+		{"kubectl get roles -n shop", true},
+		{"kubectl describe clusterrole/admin", true},
+		{"kubectl delete pdb web -n shop", true},
+		{"kubectl get wd -n shop", true},
+		{"kubectl get pods,rolebindings -n shop", true},
+		{"kubectl get pods -n shop", false},
+		{"kubectl get deployments.apps -n shop", false},
+		{"kubectl scale deploy web --replicas=2 -n shop", false},
+		{"kubectl get gadgets -n shop", false},
+	}
+	for _, tc := range tests {
+		err := validator.ValidateCommand(tc.command, CommandTypeKubectl)
+		if tc.shouldErr && (err == nil || !strings.Contains(err.Error(), "denied by security configuration")) {
+			t.Errorf("ValidateCommand(%q) should have been refused, got %v", tc.command, err)
+		}
+		if !tc.shouldErr && err != nil {
+			t.Errorf("ValidateCommand(%q) failed: %v", tc.command, err)
+		}
+	}
+
+	secConfig.SetDeniedAPIGroups("core")
+	if err := validator.ValidateCommand("kubectl get secrets -n shop", CommandTypeKubectl); err == nil || !strings.Contains(err.Error(), "API group 'core'") {
+		t.Errorf("Expected the core group to be denied, got %v", err)
+	}
+	if err := validator.ValidateCommand("kubectl get deployments -n shop", CommandTypeKubectl); err != nil {
+		t.Errorf("Expected apps resources to be allowed, got %v", err)
+	}
+}
+
+func TestValidatorExplicitNamespace(t *testing.T) {
+	secConfig := NewSecurityConfig()
+	secConfig.AccessLevel = AccessLevelAdmin
+	secConfig.RequireExplicitNamespace = true
+	validator := NewValidator(secConfig)
+
+	tests := []struct {
+		command   string
+		shouldErr bool
+	}{
+		{"kubectl create deployment web --image=nginx", true},
+		{"kubectl create secret generic creds --from-literal=a=b", true},
+		{"kubectl delete pod/web-0", true},
+		{"kubectl rollout restart deployment/web", true},
+		{"kubectl run debug --image=busybox", true},
+		{"kubectl scale deploy web --replicas=2 --context=prod", true},
+		{"kubectl create widget thing", true},
+		{"kubectl create deployment web --image=nginx -n shop", false},
+		{"kubectl create deployment web --image=nginx --namespace=shop", false},
+		{"kubectl label pods -l app=web tier=front -nshop", false},
+		{"kubectl delete pods --all -A", false},
+		{"kubectl create namespace shop", false},
+		{"kubectl taint nodes node-1 dedicated=ci:NoSchedule", false},
+		{"kubectl delete clusterrole admin-extra", false},
+		{"kubectl apply -f web.yaml", false},
+		{"kubectl get pods", false},
+		{"kubectl exec web-0 -- ls", false},
+	}
+	for _, tc := range tests {
+		err := validator.ValidateCommand(tc.command, CommandTypeKubectl)
+		if tc.shouldErr && (err == nil || !strings.Contains(err.Error(), "without an explicit namespace")) {
+			t.Errorf("ValidateCommand(%q) should have been refused, got %v", tc.command, err)
+		}
+		if !tc.shouldErr && err != nil {
+			t.Errorf("ValidateCommand(%q) failed: %v", tc.command, err)
+		}
+	}
+
+	secConfig.RequireExplicitNamespace = false
+	if err := validator.ValidateCommand("kubectl create deployment web --image=nginx", CommandTypeKubectl); err != nil {
+		t.Errorf("Expected a create without namespace to pass without the requirement, got %v", err)
+	}
+}
+
+func TestValidatorInsecureTLS(t *testing.T) {
+	secConfig := NewSecurityConfig()
+	secConfig.AccessLevel = AccessLevelAdmin
+	validator := NewValidator(secConfig)
+
+	tests := []struct {
+		command   string
+		shouldErr bool
+	}{
+		{"kubectl get pods --insecure-skip-tls-verify", true},
+		{"kubectl get pods --insecure-skip-tls-verify=true", true},
+		{"kubectl delete pod web-0 -n shop --insecure-skip-tls-verify=1", true},
+		{"kubectl get pods --insecure-skip-tls-verify=false", false},
+		{"kubectl get pods -n shop", false},
+		{"kubectl exec web-0 -n shop -- curl --insecure-skip-tls-verify", false},
+	}
+	for _, tc := range tests {
+		err := validator.ValidateCommand(tc.command, CommandTypeKubectl)
+		if tc.shouldErr && (err == nil || !strings.Contains(err.Error(), "--insecure-skip-tls-verify")) {
+			t.Errorf("ValidateCommand(%q) should have been refused, got %v", tc.command, err)
+		}
+		if !tc.shouldErr && err != nil {
+			t.Errorf("ValidateCommand(%q) failed: %v", tc.command, err)
+		}
+	}
+
+	secConfig.DisallowInsecureTLS = false
+	if err := validator.ValidateCommand("kubectl get pods --insecure-skip-tls-verify", CommandTypeKubectl); err != nil {
+		t.Errorf("Expected --insecure-skip-tls-verify to pass with the rule off, got %v", err)
+	}
+}
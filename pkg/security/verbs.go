@@ -0,0 +1,60 @@
+package security
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AnyResource is the resource verbs policy entry for resource types without their own entry
+const AnyResource = "*"
+
+// SetResourceVerbs sets the kubectl verbs allowed on each resource type, e.g. get and delete
+// on "pods" and only get on "*" for every other type. Types are resolved through the resource
+// catalog like denied resources. An empty policy allows every verb.
+func (s *SecurityConfig) SetResourceVerbs(policy map[string][]string) error {
+	resourceVerbs := make(map[string][]string, len(policy))
+	for resource, verbs := range policy {
+		resource = strings.TrimSpace(resource)
+		if resource == "" {
+			return fmt.Errorf("resource verbs policy has an entry without a resource type")
+		}
+		allowed := make([]string, 0, len(verbs))
+		for _, verb := range verbs {
+			if verb = strings.TrimSpace(verb); verb == "" || strings.ContainsAny(verb, " \t") {
+				return fmt.Errorf("invalid verb '%s' for resource type '%s'", verb, resource)
+			}
+			allowed = append(allowed, verb)
+		}
+		resourceVerbs[resource] = allowed
+	}
+	s.resourceVerbs = resourceVerbs
+	return nil
+}
+
+// IsVerbAllowed reports whether the resource verbs policy allows verb on a resource type.
+// Types without an entry follow the "*" entry, and are unrestricted without one; commands
+// with no resource type on the command line, such as apply -f or logs, follow the "*" entry.
+func (s *SecurityConfig) IsVerbAllowed(verb, resourceType string) bool {
+	if len(s.resourceVerbs) == 0 {
+		return true
+	}
+	allowed, ok := s.resourceVerbs[AnyResource]
+	if resourceType != "" {
+		name := resourceName(s.resources, resourceType)
+		for resource, verbs := range s.resourceVerbs {
+			if resource != AnyResource && resourceName(s.resources, resource) == name {
+				allowed, ok = verbs, true
+				break
+			}
+		}
+	}
+	if !ok {
+		return true
+	}
+	for _, candidate := range allowed {
+		if candidate == verb {
+			return true
+		}
+	}
+	return false
+}
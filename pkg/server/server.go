@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
 	"strconv"
 	"strings"
@@ -16,7 +17,6 @@ import (
 	"github.com/Azure/mcp-kubernetes/pkg/helm"
 	"github.com/Azure/mcp-kubernetes/pkg/hubble"
 	"github.com/Azure/mcp-kubernetes/pkg/kubectl"
-	"github.com/Azure/mcp-kubernetes/pkg/security"
 	"github.com/Azure/mcp-kubernetes/pkg/tools"
 	"github.com/Azure/mcp-kubernetes/pkg/version"
 	"github.com/mark3labs/mcp-go/mcp"
@@ -32,6 +32,7 @@ type PermissionMetadata struct {
 	ValidationEnabled    bool     `json:"validation_enabled"`
 	ValidationError      string   `json:"validation_error,omitempty"`
 	AvailableTools       []string `json:"available_tools"`
+	AdminConfirmToken    string   `json:"admin_confirm_token,omitempty"`
 	Timestamp            string   `json:"timestamp"`
 }
 
@@ -42,6 +43,10 @@ type Service struct {
 	pulsarWorker       *kubectl.Worker
 	Hostname           string // Hostname of the user
 	permissionMetadata *PermissionMetadata
+	// stopBackground holds the stop channels of background loops started for the tools
+	stopBackground []chan<- struct{}
+	// tenantWorkers reach the remote agents of the tenants in --allow-tenants
+	tenantWorkers map[string]*kubectl.Worker
 }
 
 // NewService creates a new MCP Kubernetes service
@@ -79,23 +84,20 @@ func (s *Service) Initialize() error {
 	}
 
 	// Register individual kubectl commands based on permission level
-	pulsar, _ := kubectl.New(&kubectl.Config{
-		Mode:                1,
-		Location:            os.Getenv("HOSTNAME"),
-		AccountUID:          os.Getenv("ACCOUNT_UID"),
-		Hostname:            os.Getenv("HOSTNAME"),
-		PulsarHost:          os.Getenv("PULSAR_HOST"),
-		Timeout:             timeout,
-		NCAPassword:         os.Getenv("NCA_PASSWORD"),
-		UnsubscribeEndpoint: os.Getenv("UNSUBSCRIBE_ENDPOINT"),
-		Token:               os.Getenv("TOKEN"),
-		Fingerprint:         fingerprint,
-	})
-	s.pulsarWorker = pulsar
+	s.pulsarWorker = startRemoteWorker(os.Getenv("TOKEN"), timeout, fingerprint, s.cfg.KubectlUserAgent)
 
-	topic := fmt.Sprintf("mcp-%s-%x", strings.ToLower(os.Getenv("TOKEN")), sha1.Sum([]byte(strings.ToLower(os.Getenv("HOSTNAME")))))
-	if err := s.pulsarWorker.StartSubscriber(topic + "-unsubscribe"); err != nil {
-		log.Fatalf("failed to start subscriber: %v", err)
+	// Each allowed tenant reaches its own remote agent with its own token
+	tenantTokens, err := parseTenantTokens(os.Getenv("TENANT_TOKENS"))
+	if err != nil {
+		return err
+	}
+	s.tenantWorkers = make(map[string]*kubectl.Worker)
+	for tenant, token := range tenantTokens {
+		if !s.cfg.SecurityConfig.IsTenantAllowed(tenant) {
+			log.Printf("Warning: ignoring token for tenant %s, which is not in --allow-tenants", tenant)
+			continue
+		}
+		s.tenantWorkers[tenant] = startRemoteWorker(token, timeout, fingerprint, s.cfg.KubectlUserAgent)
 	}
 
 	// Initialize permission metadata
@@ -117,56 +119,9 @@ func (s *Service) Initialize() error {
 
 		time.Sleep(2 * time.Second)
 
-		result := s.pulsarWorker.CheckClusterRolePermission(timeout)
-
-		s.permissionMetadata.ClusterRoleFound = result.ClusterRoleFound
-
-		if !result.Success {
-			// Connection or timeout issues - set validation error but keep current access level
-			switch result.ErrorType {
-			case "connection":
-				log.Printf("Connection issue during cluster validation: %s", result.ErrorMessage)
-				s.permissionMetadata.ValidationError = fmt.Sprintf("connection_issue: %s", result.ErrorMessage)
-				// Don't change access level, just mark as downgraded for error reporting
-				s.permissionMetadata.WasDowngraded = true
-
-			case "timeout":
-				log.Printf("Timeout during cluster validation: %s", result.ErrorMessage)
-				s.permissionMetadata.ValidationError = fmt.Sprintf("timeout_issue: %s", result.ErrorMessage)
-				// Don't change access level, just mark as downgraded for error reporting
-				s.permissionMetadata.WasDowngraded = true
-
-			default:
-				log.Printf("Warning: Failed to validate cluster: %s", result.ErrorMessage)
-				s.permissionMetadata.ValidationError = result.ErrorMessage
-				// For other errors, downgrade to readonly for safety
-				if s.cfg.AccessLevel != "readonly" {
-					log.Printf("Downgrading from '%s' to 'readonly' for safety", s.cfg.AccessLevel)
-					s.cfg.AccessLevel = "readonly"
-					s.cfg.SecurityConfig.AccessLevel = security.AccessLevelReadOnly
-					s.permissionMetadata.CurrentAccessLevel = "readonly"
-					s.permissionMetadata.WasDowngraded = true
-				}
-			}
-		} else {
-			// Connection successful, now check permissions based on access level
-			if s.cfg.AccessLevel == "admin" || s.cfg.AccessLevel == "readwrite" {
-				if !result.HasAdminRole {
-					log.Printf("mw-opsai-cluster-role not found, downgrading from '%s' to 'readonly'", s.cfg.AccessLevel)
-					s.cfg.AccessLevel = "readonly"
-					s.cfg.SecurityConfig.AccessLevel = security.AccessLevelReadOnly
-					s.permissionMetadata.CurrentAccessLevel = "readonly"
-					s.permissionMetadata.WasDowngraded = true
-				} else {
-					log.Printf("mw-opsai-cluster-role found, using requested access level: %s", s.cfg.AccessLevel)
-					s.permissionMetadata.CurrentAccessLevel = s.cfg.AccessLevel
-				}
-			} else {
-				// Already readonly, just confirm connection is working
-				log.Printf("Cluster connection validated successfully, using readonly access level")
-				s.permissionMetadata.CurrentAccessLevel = s.cfg.AccessLevel
-			}
-		}
+		result := checkClusterRoleWithRetries(s.pulsarWorker.CheckClusterRolePermission,
+			s.cfg.ClampTimeout(timeout), s.cfg.ValidationRetries, validationBackoff)
+		s.applyClusterRoleResult(result)
 	}
 
 	s.registerKubectlCommands()
@@ -189,9 +144,120 @@ func (s *Service) Initialize() error {
 	return nil
 }
 
+// validationBackoff is the wait before the first retry of a failed cluster role check,
+// doubled before each further retry
+var validationBackoff = 2 * time.Second
+
+// checkClusterRoleWithRetries runs the cluster role check, repeating a check that fails up to
+// retries times with exponential backoff so a transient failure doesn't cost access
+func checkClusterRoleWithRetries(check func(timeout int) *kubectl.ClusterRoleCheckResult, timeout, retries int, backoff time.Duration) *kubectl.ClusterRoleCheckResult {
+	result := check(timeout)
+	for attempt := 1; attempt <= retries && !result.Success; attempt++ {
+		log.Printf("Cluster validation failed (%s), retrying in %s (%d/%d)", result.ErrorMessage, backoff, attempt, retries)
+		time.Sleep(backoff)
+		backoff *= 2
+		result = check(timeout)
+	}
+	return result
+}
+
+// applyClusterRoleResult records the outcome of the cluster role check, downgrading to
+// readonly when the role is missing or the check failed with an error other than a
+// connection problem or timeout
+func (s *Service) applyClusterRoleResult(result *kubectl.ClusterRoleCheckResult) {
+	s.permissionMetadata.ClusterRoleFound = result.ClusterRoleFound
+
+	if !result.Success {
+		// Connection or timeout issues - set validation error but keep current access level
+		switch result.ErrorType {
+		case "connection":
+			log.Printf("Connection issue during cluster validation: %s", result.ErrorMessage)
+			s.permissionMetadata.ValidationError = fmt.Sprintf("connection_issue: %s", result.ErrorMessage)
+			// Don't change access level, just mark as downgraded for error reporting
+			s.permissionMetadata.WasDowngraded = true
+
+		case "timeout":
+			log.Printf("Timeout during cluster validation: %s", result.ErrorMessage)
+			s.permissionMetadata.ValidationError = fmt.Sprintf("timeout_issue: %s", result.ErrorMessage)
+			// Don't change access level, just mark as downgraded for error reporting
+			s.permissionMetadata.WasDowngraded = true
+
+		default:
+			log.Printf("Warning: Failed to validate cluster: %s", result.ErrorMessage)
+			s.permissionMetadata.ValidationError = result.ErrorMessage
+			// For other errors, downgrade to readonly for safety
+			if s.cfg.AccessLevel != "readonly" {
+				log.Printf("Downgrading from '%s' to 'readonly' for safety", s.cfg.AccessLevel)
+				s.cfg.DowngradeToReadOnly("cluster validation failed: " + result.ErrorMessage)
+				s.permissionMetadata.CurrentAccessLevel = "readonly"
+				s.permissionMetadata.WasDowngraded = true
+			}
+		}
+	} else {
+		// Connection successful, now check permissions based on access level
+		if s.cfg.AccessLevel == "admin" || s.cfg.AccessLevel == "readwrite" {
+			if !result.HasAdminRole {
+				log.Printf("mw-opsai-cluster-role not found, downgrading from '%s' to 'readonly'", s.cfg.AccessLevel)
+				s.cfg.DowngradeToReadOnly("mw-opsai-cluster-role was not found")
+				s.permissionMetadata.CurrentAccessLevel = "readonly"
+				s.permissionMetadata.WasDowngraded = true
+			} else {
+				log.Printf("mw-opsai-cluster-role found, using requested access level: %s", s.cfg.AccessLevel)
+				s.permissionMetadata.CurrentAccessLevel = s.cfg.AccessLevel
+			}
+		} else {
+			// Already readonly, just confirm connection is working
+			log.Printf("Cluster connection validated successfully, using readonly access level")
+			s.permissionMetadata.CurrentAccessLevel = s.cfg.AccessLevel
+		}
+	}
+}
+
+// startRemoteWorker creates a worker for the remote agent reached with token and subscribes
+// to its responses; userAgent is sent with every request
+func startRemoteWorker(token string, timeout int, fingerprint, userAgent string) *kubectl.Worker {
+	worker, _ := kubectl.New(&kubectl.Config{
+		Mode:                1,
+		Location:            os.Getenv("HOSTNAME"),
+		AccountUID:          os.Getenv("ACCOUNT_UID"),
+		Hostname:            os.Getenv("HOSTNAME"),
+		PulsarHost:          os.Getenv("PULSAR_HOST"),
+		Timeout:             timeout,
+		NCAPassword:         os.Getenv("NCA_PASSWORD"),
+		UnsubscribeEndpoint: os.Getenv("UNSUBSCRIBE_ENDPOINT"),
+		Token:               token,
+		Fingerprint:         fingerprint,
+		UserAgent:           userAgent,
+	})
+
+	topic := fmt.Sprintf("mcp-%s-%x", strings.ToLower(token), sha1.Sum([]byte(strings.ToLower(os.Getenv("HOSTNAME")))))
+	if err := worker.StartSubscriber(topic + "-unsubscribe"); err != nil {
+		log.Fatalf("failed to start subscriber: %v", err)
+	}
+	return worker
+}
+
+// parseTenantTokens parses the comma-separated tenant=token pairs of TENANT_TOKENS
+func parseTenantTokens(value string) (map[string]string, error) {
+	tokens := make(map[string]string)
+	for _, pair := range strings.Split(value, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		tenant, token, ok := strings.Cut(pair, "=")
+		tenant, token = strings.TrimSpace(tenant), strings.TrimSpace(token)
+		if !ok || tenant == "" || token == "" {
+			return nil, fmt.Errorf("invalid TENANT_TOKENS entry for tenant '%s', expected tenant=token", tenant)
+		}
+		tokens[tenant] = token
+	}
+	return tokens, nil
+}
+
 // Run starts the service with the specified transport
 func (s *Service) Run() error {
 	log.Println("MCP Kubernetes version:", version.GetVersion())
+	defer s.stopBackgroundLoops()
 
 	// Start the server
 	switch s.cfg.Transport {
@@ -199,13 +265,23 @@ func (s *Service) Run() error {
 		log.Println("Listening for requests on STDIO...")
 		return server.ServeStdio(s.mcpServer)
 	case "sse":
-		sse := server.NewSSEServer(s.mcpServer)
 		addr := fmt.Sprintf("%s:%d", s.cfg.Host, s.cfg.Port)
+		mux := http.NewServeMux()
+		sse := server.NewSSEServer(s.mcpServer,
+			server.WithHTTPServer(&http.Server{Addr: addr, Handler: mux}),
+			server.WithSSEContextFunc(tools.HTTPContextFunc))
+		mux.Handle("/", sse)
+		mux.Handle("/readyz", kubectl.ReadinessHandler(s.pulsarWorker))
 		log.Printf("SSE server listening on %s", addr)
 		return sse.Start(addr)
 	case "streamable-http":
-		streamableServer := server.NewStreamableHTTPServer(s.mcpServer)
 		addr := fmt.Sprintf("%s:%d", s.cfg.Host, s.cfg.Port)
+		mux := http.NewServeMux()
+		streamableServer := server.NewStreamableHTTPServer(s.mcpServer,
+			server.WithStreamableHTTPServer(&http.Server{Addr: addr, Handler: mux}),
+			server.WithHTTPContextFunc(tools.HTTPContextFunc))
+		mux.Handle("/mcp", streamableServer)
+		mux.Handle("/readyz", kubectl.ReadinessHandler(s.pulsarWorker))
 		log.Printf("Streamable HTTP server listening on %s", addr)
 		return streamableServer.Start(addr)
 	default:
@@ -213,23 +289,58 @@ func (s *Service) Run() error {
 	}
 }
 
+// stopBackgroundLoops stops the background loops started for the tools
+func (s *Service) stopBackgroundLoops() {
+	for _, stop := range s.stopBackground {
+		close(stop)
+	}
+	s.stopBackground = nil
+}
+
 // registerKubectlCommands registers kubectl tools based on access level
 func (s *Service) registerKubectlCommands() {
-	// Get kubectl tools filtered by access level
-	kubectlTools := kubectl.RegisterKubectlTools(s.cfg.AccessLevel)
+	// Get kubectl tools filtered by access level, as capped for the transport
+	kubectlTools := kubectl.RegisterKubectlTools(s.cfg.ForTransport().AccessLevel)
 
 	// Create a kubectl executor
 	kubectlExecutor := kubectl.NewKubectlToolExecutor(s.pulsarWorker)
+	kubectlExecutor.KeepRecentCommands(s.cfg.RecentCommands)
+	for tenant, worker := range s.tenantWorkers {
+		kubectlExecutor.AddTenant(tenant, worker)
+	}
+	kubectlExecutor.CacheReads(s.cfg.ReadCache)
+	kubectlExecutor.TrackIdempotencyKeys(s.cfg.IdempotencyKeys, time.Duration(s.cfg.IdempotencyTTL)*time.Second)
+	kubectlExecutor.TakeSnapshots(s.cfg.Snapshots)
+	if s.cfg.ReadSource == kubectl.ReadSourceInformer {
+		log.Printf("Serving get operations from informer cache (resync every %ds)", s.cfg.InformerResync)
+		stop := kubectlExecutor.EnableInformerReads(s.cfg, time.Duration(s.cfg.InformerResync)*time.Second)
+		s.stopBackground = append(s.stopBackground, stop)
+	}
+
+	// Classify custom resources for namespace policy, which only applies while namespaces are restricted
+	if s.cfg.SecurityConfig.HasNamespaceRestrictions() {
+		stop := kubectlExecutor.EnableAPIDiscovery(s.cfg, kubectl.APIDiscoveryInterval)
+		s.stopBackground = append(s.stopBackground, stop)
+	}
 
 	// Register each kubectl tool
 	for _, tool := range kubectlTools {
 		// Collect tool names for metadata
 		s.permissionMetadata.AvailableTools = append(s.permissionMetadata.AvailableTools, tool.Name)
 
-		// Special handler for check_permissions tool
+		// Special handlers for the tools that report on the server itself, admitted like any other call
 		if tool.Name == "kubectl_check_permissions" {
 			handler := s.createCheckPermissionsHandler()
-			s.mcpServer.AddTool(tool, handler)
+			s.mcpServer.AddTool(tool, tools.WithAdmission(handler, s.cfg))
+		} else if tool.Name == "kubectl_worker_stats" {
+			handler := s.createWorkerStatsHandler()
+			s.mcpServer.AddTool(tool, tools.WithAdmission(handler, s.cfg))
+		} else if tool.Name == "kubectl_effective_policy" {
+			handler := s.createEffectivePolicyHandler(kubectlExecutor)
+			s.mcpServer.AddTool(tool, tools.WithAdmission(handler, s.cfg))
+		} else if tool.Name == "kubectl_recent_commands" {
+			handler := s.createRecentCommandsHandler(kubectlExecutor)
+			s.mcpServer.AddTool(tool, tools.WithAdmission(handler, s.cfg))
 		} else {
 			// Create a handler that injects the tool name into params
 			handler := tools.CreateToolHandlerWithName(kubectlExecutor, s.cfg, tool.Name)
@@ -241,8 +352,22 @@ func (s *Service) registerKubectlCommands() {
 // createCheckPermissionsHandler creates a custom handler for the check_permissions tool
 func (s *Service) createCheckPermissionsHandler() func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		metadata := *s.permissionMetadata
+
+		// Report the level calls over this transport run at
+		cfg := s.cfg.ForTransport()
+		if cfg != s.cfg {
+			metadata.CurrentAccessLevel = cfg.AccessLevel
+			metadata.WasDowngraded = true
+		}
+
+		// Issue a fresh confirmation token for the next admin operation in safe mode
+		if cfg.SecurityConfig.RequireAdminConfirm && cfg.AccessLevel == "admin" {
+			metadata.AdminConfirmToken = cfg.SecurityConfig.IssueAdminConfirmToken()
+		}
+
 		// Return the current permission metadata as JSON
-		jsonData, err := json.MarshalIndent(s.permissionMetadata, "", "  ")
+		jsonData, err := json.MarshalIndent(metadata, "", "  ")
 		if err != nil {
 			return mcp.NewToolResultError(fmt.Sprintf("Failed to retrieve permission metadata: %v", err)), nil
 		}
@@ -250,3 +375,44 @@ func (s *Service) createCheckPermissionsHandler() func(ctx context.Context, req
 		return mcp.NewToolResultText(string(jsonData)), nil
 	}
 }
+
+// createEffectivePolicyHandler creates a custom handler for the effective_policy tool
+func (s *Service) createEffectivePolicyHandler(executor *kubectl.KubectlToolExecutor) func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		policy, err := executor.EffectivePolicy(req.GetArguments(), s.cfg.ForTransport())
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		jsonData, err := json.MarshalIndent(policy, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to resolve effective policy: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(string(jsonData)), nil
+	}
+}
+
+// createWorkerStatsHandler creates a custom handler for the worker_stats tool
+func (s *Service) createWorkerStatsHandler() func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		jsonData, err := json.MarshalIndent(s.pulsarWorker.Stats(), "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to retrieve worker stats: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(string(jsonData)), nil
+	}
+}
+
+// createRecentCommandsHandler creates a custom handler for the recent_commands tool
+func (s *Service) createRecentCommandsHandler(executor *kubectl.KubectlToolExecutor) func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		jsonData, err := json.MarshalIndent(executor.RecentCommands(), "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to retrieve recent commands: %v", err)), nil
+		}
+
+		return mcp.NewToolResultText(string(jsonData)), nil
+	}
+}
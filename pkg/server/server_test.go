@@ -0,0 +1,109 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/Azure/mcp-kubernetes/pkg/config"
+	"github.com/Azure/mcp-kubernetes/pkg/kubectl"
+	"github.com/Azure/mcp-kubernetes/pkg/tools"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// newValidationService returns a service requesting accessLevel, ready to record a cluster role check
+func newValidationService(accessLevel string) *Service {
+	cfg := config.NewConfig()
+	cfg.AccessLevel = accessLevel
+	return &Service{
+		cfg: cfg,
+		permissionMetadata: &PermissionMetadata{
+			CurrentAccessLevel:   accessLevel,
+			RequestedAccessLevel: accessLevel,
+		},
+	}
+}
+
+func TestClusterRoleValidationRetries(t *testing.T) {
+	t.Run("transient timeout", func(t *testing.T) {
+		calls := 0
+		check := func(timeout int) *kubectl.ClusterRoleCheckResult {
+			calls++
+			if calls == 1 {
+				return &kubectl.ClusterRoleCheckResult{ErrorType: "timeout", ErrorMessage: "timeout waiting for response"}
+			}
+			return &kubectl.ClusterRoleCheckResult{Success: true, HasAdminRole: true, ClusterRoleFound: true, ResponseReceived: true}
+		}
+
+		s := newValidationService("readwrite")
+		s.applyClusterRoleResult(checkClusterRoleWithRetries(check, 10, 2, 0))
+
+		if calls != 2 {
+			t.Errorf("check ran %d times, want 2", calls)
+		}
+		if s.cfg.AccessLevel != "readwrite" || s.permissionMetadata.WasDowngraded || s.permissionMetadata.ValidationError != "" {
+			t.Errorf("access level %q, downgraded %v, error %q; want readwrite kept after a successful retry",
+				s.cfg.AccessLevel, s.permissionMetadata.WasDowngraded, s.permissionMetadata.ValidationError)
+		}
+	})
+
+	t.Run("all retries fail", func(t *testing.T) {
+		calls := 0
+		check := func(timeout int) *kubectl.ClusterRoleCheckResult {
+			calls++
+			return &kubectl.ClusterRoleCheckResult{ErrorType: "other", ErrorMessage: "forbidden", ResponseReceived: true}
+		}
+
+		s := newValidationService("admin")
+		s.applyClusterRoleResult(checkClusterRoleWithRetries(check, 10, 2, 0))
+
+		if calls != 3 {
+			t.Errorf("check ran %d times, want 3", calls)
+		}
+		if s.cfg.AccessLevel != "readonly" || !s.permissionMetadata.WasDowngraded {
+			t.Errorf("access level %q, downgraded %v; want a downgrade to readonly", s.cfg.AccessLevel, s.permissionMetadata.WasDowngraded)
+		}
+	})
+}
+
+func TestServerToolsAdmitted(t *testing.T) {
+	s := newValidationService("admin")
+	s.cfg.SecurityConfig.SetRateLimit(1)
+	s.mcpServer = server.NewMCPServer("test", "0")
+	s.registerKubectlCommands()
+
+	call := func(user, tool string) mcp.CallToolResult {
+		message, _ := json.Marshal(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      1,
+			"method":  "tools/call",
+			"params":  map[string]interface{}{"name": tool, "arguments": map[string]interface{}{}},
+		})
+		response, ok := s.mcpServer.HandleMessage(tools.WithUser(context.Background(), user), message).(mcp.JSONRPCResponse)
+		if !ok {
+			t.Fatalf("expected a response to %s", tool)
+		}
+		result, ok := response.Result.(mcp.CallToolResult)
+		if !ok {
+			t.Fatalf("expected a tool result from %s, got %T", tool, response.Result)
+		}
+		return result
+	}
+
+	if result := call("alice", "kubectl_check_permissions"); result.IsError {
+		t.Fatalf("expected alice's first call to run, got %v", result.Content)
+	}
+	// The server's own tools count against the same limit: alice has used her one call a minute
+	for _, tool := range []string{"kubectl_check_permissions", "kubectl_effective_policy", "kubectl_worker_stats", "kubectl_recent_commands"} {
+		result := call("alice", tool)
+		text, _ := result.Content[0].(mcp.TextContent)
+		if !result.IsError || !strings.Contains(text.Text, "rate limit exceeded for user 'alice'") {
+			t.Errorf("expected %s to refuse alice over her rate limit, got %q", tool, text.Text)
+		}
+	}
+	if result := call("bob", "kubectl_check_permissions"); result.IsError {
+		t.Errorf("expected bob's call to run, got %v", result.Content)
+	}
+}
@@ -9,3 +9,10 @@ import (
 type CommandExecutor interface {
 	Execute(params map[string]interface{}, cfg *config.ConfigData) (string, error)
 }
+
+// StreamingExecutor is implemented by executors that can pass partial output to emit while a
+// command runs. The complete output is still returned when the command finishes.
+type StreamingExecutor interface {
+	CommandExecutor
+	ExecuteStreaming(params map[string]interface{}, cfg *config.ConfigData, emit func(chunk string)) (string, error)
+}
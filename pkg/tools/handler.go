@@ -3,9 +3,12 @@ package tools
 import (
 	"context"
 	"fmt"
+	"log"
+	"strings"
 
 	"github.com/Azure/mcp-kubernetes/pkg/config"
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
 )
 
 // CreateToolHandler creates an adapter that converts CommandExecutor to the format expected by MCP server
@@ -15,12 +18,24 @@ func CreateToolHandler(executor CommandExecutor, cfg *config.ConfigData) func(ct
 		if !ok {
 			return mcp.NewToolResultError("arguments must be a map[string]interface{}, got " + fmt.Sprintf("%T", req.Params.Arguments)), nil
 		}
-		result, err := executor.Execute(args, cfg)
+		release, result := admitCall(ctx, req, cfg)
+		if result != nil {
+			return result, nil
+		}
+		defer release()
+
+		// Calls over a transport with an access ceiling run at that level at most
+		cfg := cfg.ForTransport()
+
+		// Pass the caller's identity and session on for the audit record and snapshots
+		args[UserParam] = UserFromContext(ctx)
+		args[SessionParam] = sessionFromContext(ctx)
+		output, err := executor.Execute(args, cfg)
 		if err != nil {
-			return mcp.NewToolResultError(err.Error()), nil
+			return mcp.NewToolResultError(cfg.SecurityConfig.Redact(err.Error())), nil
 		}
 
-		return mcp.NewToolResultText(result), nil
+		return mcp.NewToolResultText(cfg.SecurityConfig.Redact(output)), nil
 	}
 }
 
@@ -31,15 +46,144 @@ func CreateToolHandlerWithName(executor CommandExecutor, cfg *config.ConfigData,
 		if !ok {
 			return mcp.NewToolResultError("arguments must be a map[string]interface{}, got " + fmt.Sprintf("%T", req.Params.Arguments)), nil
 		}
+		release, result := admitCall(ctx, req, cfg)
+		if result != nil {
+			return result, nil
+		}
+		defer release()
+
+		// Calls over a transport with an access ceiling run at that level at most
+		cfg := cfg.ForTransport()
 
-		// Inject the tool name into the arguments
+		// Inject the tool name and the caller's identity and session into the arguments
 		args["_tool_name"] = toolName
+		args[UserParam] = UserFromContext(ctx)
+		args[SessionParam] = sessionFromContext(ctx)
+
+		// Stream large output to clients that asked for progress
+		var output string
+		var err error
+		streaming, canStream := executor.(StreamingExecutor)
+		if emit, flush := progressEmitter(ctx, req, cfg); emit != nil && canStream {
+			output, err = streaming.ExecuteStreaming(args, cfg, emit)
+			flush()
+		} else {
+			output, err = executor.Execute(args, cfg)
+		}
+		if err != nil {
+			return mcp.NewToolResultError(cfg.SecurityConfig.Redact(err.Error())), nil
+		}
+
+		return mcp.NewToolResultText(cfg.SecurityConfig.Redact(output)), nil
+	}
+}
+
+// WithAdmission wraps a handler that doesn't go through CreateToolHandler, such as those
+// reporting on the server itself, in the same audit log entry, rate limit and session
+// concurrency limit
+func WithAdmission(handler server.ToolHandlerFunc, cfg *config.ConfigData) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		release, result := admitCall(ctx, req, cfg)
+		if result != nil {
+			return result, nil
+		}
+		defer release()
+		return handler(ctx, req)
+	}
+}
+
+// admitCall records the caller and tool of a call in the audit log and applies the caller's
+// rate limit and the session's concurrency limit. It returns the error result for a call over
+// either limit, or else a release to call once the call has finished.
+func admitCall(ctx context.Context, req mcp.CallToolRequest, cfg *config.ConfigData) (func(), *mcp.CallToolResult) {
+	user := UserFromContext(ctx)
+	operation := ""
+	if args, ok := req.Params.Arguments.(map[string]interface{}); ok {
+		operation, _ = args["operation"].(string)
+	}
+	allowed := cfg.SecurityConfig.AllowCall(user)
+	log.Printf("audit: user=%q tool=%q operation=%q allowed=%t", user, req.Params.Name, operation, allowed)
+	if !allowed {
+		return nil, mcp.NewToolResultError(fmt.Sprintf("Error: rate limit exceeded for user '%s'", user))
+	}
+
+	release, ok := cfg.SecurityConfig.StartSessionCall(sessionFromContext(ctx))
+	if !ok {
+		log.Printf("audit: user=%q tool=%q rejected: session has %d calls running", user, req.Params.Name, cfg.SecurityConfig.SessionConcurrency())
+		return nil, mcp.NewToolResultError(fmt.Sprintf("Error: this session already has %d tool calls running, the most allowed at once; wait for one to finish and retry", cfg.SecurityConfig.SessionConcurrency()))
+	}
+	return release, nil
+}
+
+// sessionFromContext returns the ID of the MCP session a call arrived on, or "" outside one
+func sessionFromContext(ctx context.Context) string {
+	if client := server.ClientSessionFromContext(ctx); client != nil {
+		return client.SessionID()
+	}
+	return ""
+}
+
+// maxPartialLine bounds how much of an unterminated line is held back before it is sent anyway
+const maxPartialLine = 64 * 1024
 
-		result, err := executor.Execute(args, cfg)
+// progressEmitter returns a function sending output chunks to the client as progress
+// notifications, and a flush to call once the command ends, or nils when the call can't
+// receive them. Chunks are only streamed over stdio, and only when the client asked for
+// progress by sending a progress token.
+func progressEmitter(ctx context.Context, req mcp.CallToolRequest, cfg *config.ConfigData) (emit func(chunk string), flush func()) {
+	if cfg.Transport != "stdio" || req.Params.Meta == nil || req.Params.Meta.ProgressToken == nil {
+		return nil, nil
+	}
+	srv := server.ServerFromContext(ctx)
+	if srv == nil {
+		return nil, nil
+	}
+
+	token := req.Params.Meta.ProgressToken
+	sent := 0
+	send := func(message string) {
+		if message == "" {
+			return
+		}
+		sent++
+		err := srv.SendNotificationToClient(ctx, "notifications/progress", map[string]any{
+			"progressToken": token,
+			"progress":      sent,
+			"message":       message,
+		})
 		if err != nil {
-			return mcp.NewToolResultError(err.Error()), nil
+			log.Printf("Warning: failed to send progress notification: %v", err)
 		}
+	}
+
+	lines := &redactedLines{redact: cfg.SecurityConfig.Redact}
+	emit = func(chunk string) { send(lines.add(chunk)) }
+	flush = func() { send(lines.flush()) }
+	return emit, flush
+}
 
-		return mcp.NewToolResultText(result), nil
+// redactedLines regroups streamed output into complete lines before redacting it, so a
+// secret split across two chunks is still matched
+type redactedLines struct {
+	redact func(string) string
+	tail   string
+}
+
+// add returns the redacted complete lines available after chunk, holding back a trailing
+// partial line until it ends or grows past maxPartialLine
+func (r *redactedLines) add(chunk string) string {
+	text := r.tail + chunk
+	end := strings.LastIndexByte(text, '\n') + 1
+	if end == 0 && len(text) > maxPartialLine {
+		end = len(text)
 	}
+	r.tail = text[end:]
+	return r.redact(text[:end])
+}
+
+// flush returns the redacted partial line held back, if any
+func (r *redactedLines) flush() string {
+	text := r.tail
+	r.tail = ""
+	return r.redact(text)
 }
@@ -0,0 +1,117 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Azure/mcp-kubernetes/pkg/config"
+	"github.com/Azure/mcp-kubernetes/pkg/security"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestRedactedLines(t *testing.T) {
+	lines := &redactedLines{redact: security.NewSecurityConfig().Redact}
+
+	// The token is split across chunks, so it can only be matched once the line is complete
+	var sent []string
+	for _, chunk := range []string{"curl -H 'Authorization: Bearer abc", "def123'\nnext ", "line\nunterminated"} {
+		sent = append(sent, lines.add(chunk))
+	}
+	sent = append(sent, lines.flush())
+
+	output := strings.Join(sent, "")
+	if strings.Contains(output, "abc") || strings.Contains(output, "def123") {
+		t.Errorf("token split across chunks leaked: %q", sent)
+	}
+	if want := "curl -H 'Authorization: Bearer " + security.RedactedPlaceholder + "'\nnext line\nunterminated"; output != want {
+		t.Errorf("output = %q, want %q", output, want)
+	}
+	if sent[0] != "" {
+		t.Errorf("a partial line should be held back, got %q", sent[0])
+	}
+
+	// A line that never ends is sent once it outgrows the buffer
+	long := strings.Repeat("x", maxPartialLine+1)
+	if got := lines.add(long); got != long {
+		t.Errorf("expected an oversized partial line to be sent, got %d bytes", len(got))
+	}
+}
+
+// testSession is an MCP client session identified only by its id
+type testSession string
+
+func (s testSession) Initialize()                                         {}
+func (s testSession) Initialized() bool                                   { return true }
+func (s testSession) NotificationChannel() chan<- mcp.JSONRPCNotification { return nil }
+func (s testSession) SessionID() string                                   { return string(s) }
+
+// blockingExecutor holds each call until release is closed
+type blockingExecutor struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (e *blockingExecutor) Execute(params map[string]interface{}, cfg *config.ConfigData) (string, error) {
+	e.started <- struct{}{}
+	<-e.release
+	return "done", nil
+}
+
+func TestSessionConcurrencyLimit(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.SecurityConfig.SetSessionConcurrency(2)
+	executor := &blockingExecutor{started: make(chan struct{}), release: make(chan struct{})}
+	handler := CreateToolHandlerWithName(executor, cfg, "kubectl_resources")
+
+	srv := server.NewMCPServer("test", "1.0")
+	call := func(session string) (*mcp.CallToolResult, error) {
+		req := mcp.CallToolRequest{}
+		req.Params.Name = "kubectl_resources"
+		req.Params.Arguments = map[string]interface{}{"operation": "get"}
+		return handler(srv.WithContext(context.Background(), testSession(session)), req)
+	}
+
+	// Fill the session's two slots with calls that stay running
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if result, err := call("session-a"); err != nil || result.IsError {
+				t.Errorf("expected a running call to succeed, got %v %+v", err, result)
+			}
+		}()
+		<-executor.started
+	}
+
+	result, err := call("session-a")
+	if err != nil || !result.IsError {
+		t.Fatalf("expected the third concurrent call to be rejected, got %v %+v", err, result)
+	}
+	if text := result.Content[0].(mcp.TextContent).Text; !strings.Contains(text, "already has 2 tool calls running") {
+		t.Errorf("unexpected rejection message: %q", text)
+	}
+
+	// Other sessions have their own slots
+	go func() { <-executor.started }()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if result, err := call("session-b"); err != nil || result.IsError {
+			t.Errorf("expected a call on another session to succeed, got %v %+v", err, result)
+		}
+	}()
+
+	close(executor.release)
+	wg.Wait()
+	<-done
+
+	// Finished calls free their slots
+	go func() { <-executor.started }()
+	if result, err := call("session-a"); err != nil || result.IsError {
+		t.Errorf("expected a call after the others finished to succeed, got %v %+v", err, result)
+	}
+}
@@ -0,0 +1,68 @@
+package tools
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// AnonymousUser is the identity of calls that carry none, such as those over stdio
+const AnonymousUser = "anonymous"
+
+// UserParam and SessionParam are the tool call parameters the handlers set to the caller's
+// identity and MCP session ID, overriding any value the client sent
+const (
+	UserParam    = "_user"
+	SessionParam = "_session"
+)
+
+// userKey is the context key holding the caller's identity
+type userKey struct{}
+
+// WithUser returns a context carrying the caller's identity
+func WithUser(ctx context.Context, user string) context.Context {
+	return context.WithValue(ctx, userKey{}, user)
+}
+
+// UserFromContext returns the caller's identity, or AnonymousUser when there is none
+func UserFromContext(ctx context.Context) string {
+	if user, ok := ctx.Value(userKey{}).(string); ok && user != "" {
+		return user
+	}
+	return AnonymousUser
+}
+
+// UserFromRequest identifies the caller of an HTTP request by its X-User header, or else the
+// subject of its bearer token. The token is not verified, so the identity is only as
+// trustworthy as the proxy in front of the server that sets it.
+func UserFromRequest(r *http.Request) string {
+	if user := strings.TrimSpace(r.Header.Get("X-User")); user != "" {
+		return user
+	}
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return AnonymousUser
+	}
+	parts := strings.Split(strings.TrimSpace(token), ".")
+	if len(parts) != 3 {
+		return AnonymousUser
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return AnonymousUser
+	}
+	var claims struct {
+		Subject string `json:"sub"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Subject == "" {
+		return AnonymousUser
+	}
+	return claims.Subject
+}
+
+// HTTPContextFunc tags the context of each HTTP request with the caller's identity
+func HTTPContextFunc(ctx context.Context, r *http.Request) context.Context {
+	return WithUser(ctx, UserFromRequest(r))
+}
@@ -0,0 +1,41 @@
+package tools
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestUserFromRequest(t *testing.T) {
+	claims := base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"alice@example.com"}`))
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    string
+	}{
+		{"no identity", nil, AnonymousUser},
+		{"header", map[string]string{"X-User": "bob"}, "bob"},
+		{"bearer token subject", map[string]string{"Authorization": "Bearer e30." + claims + ".sig"}, "alice@example.com"},
+		{"header wins over token", map[string]string{"X-User": "bob", "Authorization": "Bearer e30." + claims + ".sig"}, "bob"},
+		{"opaque token", map[string]string{"Authorization": "Bearer abc123"}, AnonymousUser},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("POST", "/mcp", nil)
+			for key, value := range tt.headers {
+				r.Header.Set(key, value)
+			}
+			if got := UserFromRequest(r); got != tt.want {
+				t.Errorf("UserFromRequest() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if got := UserFromContext(context.Background()); got != AnonymousUser {
+		t.Errorf("UserFromContext() without a user = %q, want %q", got, AnonymousUser)
+	}
+	if got := UserFromContext(HTTPContextFunc(context.Background(), httptest.NewRequest("GET", "/", nil))); got != AnonymousUser {
+		t.Errorf("UserFromContext() for an anonymous request = %q", got)
+	}
+}